@@ -23,6 +23,7 @@ import (
 	"github.com/go-openapi/jsonpointer"
 	"github.com/go-openapi/spec"
 	"github.com/go-openapi/swag"
+	"gopkg.in/yaml.v3"
 )
 
 type referenceAnalysis struct {
@@ -141,11 +142,20 @@ func (p *enumAnalysis) addSchemaEnum(key string, enum []interface{}) {
 // reason about semantics of a swagger specification for use in code generation
 // or validation etc.
 func New(doc *spec.Swagger) *Spec {
+	a := newSpec(doc)
+	a.runEagerPlugins()
+
+	return a
+}
+
+// newSpec analyzes a spec like New, without running the eager analysis plugins, for internal analyses
+func newSpec(doc *spec.Swagger) *Spec {
 	a := &Spec{
 		spec:       doc,
 		references: referenceAnalysis{},
 		patterns:   patternAnalysis{},
 		enums:      enumAnalysis{},
+		defaults:   defaultAnalysis{},
 	}
 	a.reset()
 	a.initialize()
@@ -164,8 +174,14 @@ type Spec struct {
 	references  referenceAnalysis
 	patterns    patternAnalysis
 	enums       enumAnalysis
+	defaults    defaultAnalysis
 	allSchemas  map[string]SchemaRef
 	allOfs      map[string]SchemaRef
+	plugins     *pluginCache
+	provenance  map[string]Provenance // not reset when the spec is reloaded
+	frozen      bool                  // a read-only view (see Freeze)
+	shared      bool                  // shares its document with a view or a thawed copy until modified (see Freeze, Thaw)
+	sources     map[string]*yaml.Node // the sources of the documents, by location (see NewWithSource)
 }
 
 func (s *Spec) reset() {
@@ -175,6 +191,7 @@ func (s *Spec) reset() {
 	s.operations = make(map[string]map[string]*spec.Operation, 150)
 	s.allSchemas = make(map[string]SchemaRef, 150)
 	s.allOfs = make(map[string]SchemaRef, 150)
+	s.plugins = newPluginCache()
 	s.references.schemas = make(map[string]spec.Ref, 150)
 	s.references.pathItems = make(map[string]spec.Ref, 150)
 	s.references.responses = make(map[string]spec.Ref, 150)
@@ -193,6 +210,7 @@ func (s *Spec) reset() {
 	s.enums.items = make(map[string][]interface{}, 150)
 	s.enums.schemas = make(map[string][]interface{}, 150)
 	s.enums.allEnums = make(map[string][]interface{}, 150)
+	s.defaults.allDefaults = make(map[string]DefaultValue, 150)
 }
 
 func (s *Spec) reload() {
@@ -201,6 +219,18 @@ func (s *Spec) reload() {
 }
 
 func (s *Spec) initialize() {
+	s.initializeGlobals()
+	s.initializePaths()
+	s.initializeParameters()
+	s.initializeResponses()
+	s.initializeDefinitions()
+	// TODO: after analyzing all things and flattening schemas etc
+	// resolve all the collected references to their final representations
+	// best put in a separate method because this could get expensive
+}
+
+// initializeGlobals indexes the media types and security requirements declared at the top level of the document
+func (s *Spec) initializeGlobals() {
 	for _, c := range s.spec.Consumes {
 		s.consumes[c] = struct{}{}
 	}
@@ -212,10 +242,15 @@ func (s *Spec) initialize() {
 			s.authSchemes[k] = struct{}{}
 		}
 	}
+}
+
+func (s *Spec) initializePaths() {
 	for path, pathItem := range s.AllPaths() {
 		s.analyzeOperations(path, &pathItem) //#nosec
 	}
+}
 
+func (s *Spec) initializeParameters() {
 	for name, parameter := range s.spec.Parameters {
 		refPref := slashpath.Join("/parameters", jsonpointer.Escape(name))
 		if parameter.Items != nil {
@@ -230,8 +265,13 @@ func (s *Spec) initialize() {
 		if len(parameter.Enum) > 0 {
 			s.enums.addParameterEnum(refPref, parameter.Enum)
 		}
+		if parameter.Default != nil {
+			s.defaults.addSimpleDefault(refPref, parameter.SimpleSchema, parameter.CommonValidations)
+		}
 	}
+}
 
+func (s *Spec) initializeResponses() {
 	for name, response := range s.spec.Responses {
 		refPref := slashpath.Join("/responses", jsonpointer.Escape(name))
 		for k, v := range response.Headers {
@@ -245,19 +285,21 @@ func (s *Spec) initialize() {
 			if len(v.Enum) > 0 {
 				s.enums.addHeaderEnum(hRefPref, v.Enum)
 			}
+			if v.Default != nil {
+				s.defaults.addSimpleDefault(hRefPref, v.SimpleSchema, v.CommonValidations)
+			}
 		}
 		if response.Schema != nil {
 			s.analyzeSchema("schema", response.Schema, refPref)
 		}
 	}
+}
 
+func (s *Spec) initializeDefinitions() {
 	for name := range s.spec.Definitions {
 		schema := s.spec.Definitions[name]
 		s.analyzeSchema(name, &schema, "/definitions")
 	}
-	// TODO: after analyzing all things and flattening schemas etc
-	// resolve all the collected references to their final representations
-	// best put in a separate method because this could get expensive
 }
 
 func (s *Spec) analyzeOperations(path string, pi *spec.PathItem) {
@@ -286,6 +328,9 @@ func (s *Spec) analyzeOperations(path string, pi *spec.PathItem) {
 		if len(param.Enum) > 0 {
 			s.enums.addParameterEnum(refPref, param.Enum)
 		}
+		if param.Default != nil {
+			s.defaults.addSimpleDefault(refPref, param.SimpleSchema, param.CommonValidations)
+		}
 		if param.Items != nil {
 			s.analyzeItems("items", param.Items, refPref, "parameter")
 		}
@@ -310,6 +355,9 @@ func (s *Spec) analyzeItems(name string, items *spec.Items, prefix, location str
 	if len(items.Enum) > 0 {
 		s.enums.addItemsEnum(refPref, items.Enum)
 	}
+	if items.Default != nil {
+		s.defaults.addSimpleDefault(refPref, items.SimpleSchema, items.CommonValidations)
+	}
 }
 
 func (s *Spec) analyzeParameter(prefix string, i int, param spec.Parameter) {
@@ -326,6 +374,10 @@ func (s *Spec) analyzeParameter(prefix string, i int, param spec.Parameter) {
 		s.enums.addParameterEnum(refPref, param.Enum)
 	}
 
+	if param.Default != nil {
+		s.defaults.addSimpleDefault(refPref, param.SimpleSchema, param.CommonValidations)
+	}
+
 	s.analyzeItems("items", param.Items, refPref, "parameter")
 	if param.In == "body" && param.Schema != nil {
 		s.analyzeSchema("schema", param.Schema, refPref)
@@ -337,19 +389,7 @@ func (s *Spec) analyzeOperation(method, path string, op *spec.Operation) {
 		return
 	}
 
-	for _, c := range op.Consumes {
-		s.consumes[c] = struct{}{}
-	}
-
-	for _, c := range op.Produces {
-		s.produces[c] = struct{}{}
-	}
-
-	for _, ss := range op.Security {
-		for k := range ss {
-			s.authSchemes[k] = struct{}{}
-		}
-	}
+	s.aggregateOperation(op)
 
 	if _, ok := s.operations[method]; !ok {
 		s.operations[method] = make(map[string]*spec.Operation)
@@ -374,6 +414,23 @@ func (s *Spec) analyzeOperation(method, path string, op *spec.Operation) {
 	}
 }
 
+// aggregateOperation collects the media types and security schemes used by an operation
+func (s *Spec) aggregateOperation(op *spec.Operation) {
+	for _, c := range op.Consumes {
+		s.consumes[c] = struct{}{}
+	}
+
+	for _, c := range op.Produces {
+		s.produces[c] = struct{}{}
+	}
+
+	for _, ss := range op.Security {
+		for k := range ss {
+			s.authSchemes[k] = struct{}{}
+		}
+	}
+}
+
 func (s *Spec) analyzeDefaultResponse(prefix string, res *spec.Response) {
 	refPref := slashpath.Join(prefix, "responses", "default")
 	if res.Ref.String() != "" {
@@ -386,6 +443,10 @@ func (s *Spec) analyzeDefaultResponse(prefix string, res *spec.Response) {
 		if v.Pattern != "" {
 			s.patterns.addHeaderPattern(hRefPref, v.Pattern)
 		}
+
+		if v.Default != nil {
+			s.defaults.addSimpleDefault(hRefPref, v.SimpleSchema, v.CommonValidations)
+		}
 	}
 
 	if res.Schema != nil {
@@ -409,6 +470,10 @@ func (s *Spec) analyzeResponse(prefix string, k int, res spec.Response) {
 		if len(v.Enum) > 0 {
 			s.enums.addHeaderEnum(hRefPref, v.Enum)
 		}
+
+		if v.Default != nil {
+			s.defaults.addSimpleDefault(hRefPref, v.SimpleSchema, v.CommonValidations)
+		}
 	}
 
 	if res.Schema != nil {
@@ -439,6 +504,10 @@ func (s *Spec) analyzeSchema(name string, schema *spec.Schema, prefix string) {
 		s.enums.addSchemaEnum(refURI, schema.Enum)
 	}
 
+	if schema.Default != nil {
+		s.defaults.addDefault(refURI, schema.Default, schema)
+	}
+
 	for k, v := range schema.Definitions {
 		v := v
 		s.analyzeSchema(k, &v, slashpath.Join(refURI, "definitions"))
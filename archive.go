@@ -0,0 +1,362 @@
+package analysis
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	slashpath "path"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/swag"
+)
+
+// ArchiveFormat is the format of an archive holding a multi-file spec
+type ArchiveFormat uint8
+
+// Supported archive formats
+const (
+	ZipArchive ArchiveFormat = iota
+	TarArchive
+	TarGzArchive
+)
+
+// maxArchiveSize is the maximum size of an archive, and of its decompressed content
+const maxArchiveSize = 256 << 20
+
+// OpenArchive reads a zip, tar or gzipped tar archive as a file system.
+//
+// The format is detected from the content. The resulting file system may be used with LoadFS and
+// FlattenOpts.FS, so relative $ref's between the documents of the archive are resolved within the archive.
+// It also supports fs.ReadDir, fs.WalkDir and fs.Glob, with the directories implied by the files of the archive.
+//
+// The archive is decompressed in memory: archives larger than 256MB, or holding more than 256MB of
+// decompressed content, are rejected with ErrArchiveTooLarge.
+func OpenArchive(r io.Reader) (fs.FS, error) {
+	data, err := readAtMost(r, maxArchiveSize)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")), bytes.HasPrefix(data, []byte("PK\x05\x06")):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+
+		return readZip(zr, maxArchiveSize)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+
+		return readTar(gz, maxArchiveSize)
+	default:
+		return readTar(bytes.NewReader(data), maxArchiveSize)
+	}
+}
+
+// readAtMost reads all the content of a reader, unless it holds more than limit bytes
+func readAtMost(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > limit {
+		return nil, ErrArchiveTooLarge
+	}
+
+	return data, nil
+}
+
+// WriteArchive writes documents, such as the output of Flatten, as an archive.
+//
+// Documents are keyed by their path in the archive: documents with a ".yaml" or ".yml" extension are
+// written as YAML, all others as JSON. Entries are written in lexicographic order.
+func WriteArchive(w io.Writer, format ArchiveFormat, docs map[string]interface{}) error {
+	switch format {
+	case ZipArchive:
+		zw := zip.NewWriter(w)
+		for _, name := range sortedKeys(docs) {
+			data, err := marshalDocument(name, docs[name])
+			if err != nil {
+				return err
+			}
+
+			fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+			if err != nil {
+				return err
+			}
+
+			if _, err := fw.Write(data); err != nil {
+				return err
+			}
+		}
+
+		return zw.Close()
+	case TarArchive:
+		return writeTar(w, docs)
+	case TarGzArchive:
+		gz := gzip.NewWriter(w)
+		if err := writeTar(gz, docs); err != nil {
+			return err
+		}
+
+		return gz.Close()
+	default:
+		return fmt.Errorf("unsupported archive format: %d", format)
+	}
+}
+
+func writeTar(w io.Writer, docs map[string]interface{}) error {
+	tw := tar.NewWriter(w)
+	for _, name := range sortedKeys(docs) {
+		data, err := marshalDocument(name, docs[name])
+		if err != nil {
+			return err
+		}
+
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+		}); err != nil {
+			return err
+		}
+
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+// marshalDocument marshals a document as YAML or JSON, depending on the extension of its name
+func marshalDocument(name string, doc interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if !swag.YAMLMatcher(name) {
+		return data, nil
+	}
+
+	// preserve the order of keys
+	var ordered swag.JSONMapSlice
+	if err := json.Unmarshal(data, &ordered); err != nil {
+		return nil, err
+	}
+
+	out, err := ordered.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+
+	yamlDoc, ok := out.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected YAML document of type %T", out)
+	}
+
+	return yamlDoc, nil
+}
+
+// readZip reads the files of a zip archive, holding at most limit bytes of decompressed content
+func readZip(zr *zip.Reader, limit int64) (fs.FS, error) {
+	files := make(memFS)
+
+	for _, file := range zr.File {
+		if file.Mode().IsDir() {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := readAtMost(rc, limit)
+		_ = rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		limit -= int64(len(data))
+
+		files[strings.TrimPrefix(slashpath.Clean("/"+file.Name), "/")] = data
+	}
+
+	return files, nil
+}
+
+// readTar reads the regular files of a tar archive, holding at most limit bytes of decompressed content
+func readTar(r io.Reader, limit int64) (fs.FS, error) {
+	files := make(memFS)
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := readAtMost(tr, limit)
+		if err != nil {
+			return nil, err
+		}
+		limit -= int64(len(data))
+
+		files[strings.TrimPrefix(slashpath.Clean("/"+header.Name), "/")] = data
+	}
+}
+
+// memFS is a read-only, in-memory file system of regular files, by path.
+//
+// Directories are implied by the paths of the files.
+type memFS map[string][]byte
+
+func (m memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if data, ok := m[name]; ok {
+		return &memFile{Reader: bytes.NewReader(data), info: memFileInfo{name: slashpath.Base(name), size: int64(len(data))}}, nil
+	}
+
+	entries := m.dirEntries(name)
+	if entries == nil && name != "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &memDir{name: name, info: memFileInfo{name: slashpath.Base(name), dir: true}, entries: entries}, nil
+}
+
+// dirEntries yields the sorted entries of a directory, or nil if there is no file under this directory
+func (m memFS) dirEntries(dir string) []fs.DirEntry {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+
+	children := make(map[string]bool) // by name, telling whether the child is a directory
+	for name := range m {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		child, _, nested := strings.Cut(strings.TrimPrefix(name, prefix), "/")
+		children[child] = children[child] || nested
+	}
+
+	if len(children) == 0 {
+		return nil
+	}
+
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, child := range sortedKeys(children) {
+		info := memFileInfo{name: child, dir: children[child]}
+		if !info.dir {
+			info.size = int64(len(m[prefix+child]))
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+
+	return entries
+}
+
+func (m memFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	data, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return append([]byte(nil), data...), nil // the caller may modify the content
+}
+
+type memFile struct {
+	*bytes.Reader
+	info memFileInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+func (f *memFile) Close() error { return nil }
+
+// memDir is a directory of a memFS, which may be listed with ReadDir
+type memDir struct {
+	name    string
+	info    memFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *memDir) Close() error { return nil }
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+
+		return remaining, nil
+	}
+
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+
+	return remaining[:n], nil
+}
+
+type memFileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i memFileInfo) Name() string { return i.name }
+func (i memFileInfo) Size() int64  { return i.size }
+func (i memFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+
+	return 0o444
+}
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.dir }
+func (i memFileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,127 @@
+package analysis
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	docs := map[string]interface{}{
+		"archived/api/root.yaml": map[string]interface{}{
+			"swagger": "2.0",
+			"info":    map[string]interface{}{"title": "archived spec", "version": "1.0"},
+			"paths": map[string]interface{}{
+				"/pets": map[string]interface{}{
+					"get": map[string]interface{}{
+						"responses": map[string]interface{}{
+							"200": map[string]interface{}{
+								"description": "ok",
+								"schema":      map[string]interface{}{"$ref": "models/pet.json"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"archived/api/models/pet.json": spec.Schema{SchemaProps: spec.SchemaProps{
+			Type:       spec.StringOrArray{"object"},
+			Properties: spec.SchemaProperties{"name": *spec.StringProperty()},
+		}},
+	}
+
+	for _, toPin := range []struct {
+		Name   string
+		Format ArchiveFormat
+	}{
+		{Name: "zip", Format: ZipArchive},
+		{Name: "tar", Format: TarArchive},
+		{Name: "tar.gz", Format: TarGzArchive},
+	} {
+		fixture := toPin
+		t.Run(fixture.Name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			require.NoError(t, WriteArchive(&buf, fixture.Format, docs))
+
+			fsys, err := OpenArchive(&buf)
+			require.NoError(t, err)
+			require.NoError(t, fstest.TestFS(fsys, "archived/api/root.yaml", "archived/api/models/pet.json"))
+
+			matches, err := fs.Glob(fsys, "archived/api/*.yaml")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"archived/api/root.yaml"}, matches)
+
+			sp, err := LoadFS(fsys, "archived/api/root.yaml")
+			require.NoError(t, err)
+			assert.Equal(t, "archived spec", sp.Info.Title)
+
+			require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: "archived/api/root.yaml", FS: fsys, Minimal: true}))
+			require.Contains(t, sp.Definitions, "pet")
+			assert.Contains(t, sp.Definitions["pet"].Properties, "name")
+
+			// write the bundled spec back
+			var out bytes.Buffer
+			require.NoError(t, WriteArchive(&out, fixture.Format, map[string]interface{}{"bundle.json": sp}))
+
+			bundle, err := OpenArchive(&out)
+			require.NoError(t, err)
+
+			bundled, err := LoadFS(bundle, "bundle.json")
+			require.NoError(t, err)
+			assert.Contains(t, bundled.Definitions, "pet")
+		})
+	}
+
+	t.Run("should not write an unknown format", func(t *testing.T) {
+		t.Parallel()
+
+		require.Error(t, WriteArchive(&bytes.Buffer{}, ArchiveFormat(99), docs))
+	})
+}
+
+func TestOpenArchive_TooLarge(t *testing.T) {
+	t.Parallel()
+
+	docs := map[string]interface{}{
+		"a.json": map[string]interface{}{"description": "first document"},
+		"b.json": map[string]interface{}{"description": "second document"},
+	}
+
+	t.Run("with tar archive", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteArchive(&buf, TarArchive, docs))
+
+		_, err := readTar(bytes.NewReader(buf.Bytes()), 50)
+		require.ErrorIs(t, err, ErrArchiveTooLarge)
+
+		_, err = readTar(bytes.NewReader(buf.Bytes()), 100)
+		require.NoError(t, err)
+	})
+
+	t.Run("with zip archive", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteArchive(&buf, ZipArchive, docs))
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+
+		_, err = readZip(zr, 50)
+		require.ErrorIs(t, err, ErrArchiveTooLarge)
+
+		_, err = readZip(zr, 100)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,172 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// Extensions carrying OpenAPI 3.x servers (e.g. when converted from a 3.x document)
+const (
+	xServers = "x-servers"
+	xServer  = "x-server"
+)
+
+// serverObject is an OpenAPI 3.x server, as carried by the x-servers and x-server extensions
+type serverObject struct {
+	URL       string                    `json:"url"`
+	Variables map[string]serverVariable `json:"variables,omitempty"`
+}
+
+type serverVariable struct {
+	Default string   `json:"default"`
+	Enum    []string `json:"enum,omitempty"`
+}
+
+// BaseURLs yields the base URLs of the API, combining schemes, host and basePath.
+//
+// Whenever no scheme is declared, scheme-relative URLs are returned (e.g. "//api.example.com/v1").
+// Whenever no host is declared, the result is just the base path.
+//
+// Documents converted from OpenAPI 3.x may carry their servers as a "x-servers" extension at the top level:
+// whenever present, these URLs are returned instead, with server variables expanded to all their enumerated values
+// (or their default value).
+func (s *Spec) BaseURLs() []string {
+	return s.baseURLs(s.spec.Schemes, s.spec.Extensions)
+}
+
+// BaseURLsFor yields the base URLs for an operation.
+//
+// Operation-level schemes take precedence over the top-level schemes. Likewise, "x-server" or "x-servers" extensions
+// on the operation override any server declared at the top level.
+func (s *Spec) BaseURLsFor(operation *spec.Operation) []string {
+	schemes := s.spec.Schemes
+	if len(operation.Schemes) > 0 {
+		schemes = operation.Schemes
+	}
+
+	if servers := serversFromExtensions(operation.Extensions); len(servers) > 0 {
+		return expandServers(servers)
+	}
+
+	return s.baseURLs(schemes, s.spec.Extensions)
+}
+
+func (s *Spec) baseURLs(schemes []string, extensions spec.Extensions) []string {
+	if servers := serversFromExtensions(extensions); len(servers) > 0 {
+		return expandServers(servers)
+	}
+
+	basePath := normalizeBasePath(s.spec.BasePath)
+	if s.spec.Host == "" {
+		return []string{basePath}
+	}
+
+	if len(schemes) == 0 {
+		return []string{"//" + s.spec.Host + basePath}
+	}
+
+	result := make([]string, 0, len(schemes))
+	seen := make(map[string]bool, len(schemes))
+	for _, scheme := range schemes {
+		u := strings.ToLower(scheme) + "://" + s.spec.Host + basePath
+		if seen[u] {
+			continue
+		}
+
+		seen[u] = true
+		result = append(result, u)
+	}
+
+	return result
+}
+
+func normalizeBasePath(basePath string) string {
+	if basePath == "" || basePath == "/" {
+		return "/"
+	}
+
+	if !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
+	}
+
+	return strings.TrimSuffix(basePath, "/")
+}
+
+// serversFromExtensions reads servers from the x-servers (list) or x-server (single) extensions
+func serversFromExtensions(extensions spec.Extensions) []serverObject {
+	if raw, ok := lookupExtension(extensions, xServers); ok {
+		var servers []serverObject
+		if err := swag.FromDynamicJSON(raw, &servers); err == nil {
+			return servers
+		}
+	}
+
+	if raw, ok := lookupExtension(extensions, xServer); ok {
+		var server serverObject
+		if err := swag.FromDynamicJSON(raw, &server); err == nil && server.URL != "" {
+			return []serverObject{server}
+		}
+
+		// a plain URL string is also accepted
+		if u, isString := raw.(string); isString && u != "" {
+			return []serverObject{{URL: u}}
+		}
+	}
+
+	return nil
+}
+
+// expandServers expands server variables in server URLs, preserving the order of servers
+func expandServers(servers []serverObject) []string {
+	var result []string
+	seen := make(map[string]bool)
+
+	for _, server := range servers {
+		for _, u := range expandServerURL(server) {
+			if seen[u] {
+				continue
+			}
+
+			seen[u] = true
+			result = append(result, u)
+		}
+	}
+
+	return result
+}
+
+func expandServerURL(server serverObject) []string {
+	urls := []string{server.URL}
+
+	for _, name := range sortedKeys(server.Variables) {
+		variable := server.Variables[name]
+		values := variable.Enum
+		if len(values) == 0 {
+			values = []string{variable.Default}
+		} else {
+			values = append([]string(nil), values...)
+			sort.Strings(values)
+		}
+
+		placeholder := "{" + name + "}"
+		expanded := make([]string, 0, len(urls)*len(values))
+		for _, u := range urls {
+			if !strings.Contains(u, placeholder) {
+				expanded = append(expanded, u)
+
+				continue
+			}
+
+			for _, value := range values {
+				expanded = append(expanded, strings.ReplaceAll(u, placeholder, value))
+			}
+		}
+
+		urls = expanded
+	}
+
+	return urls
+}
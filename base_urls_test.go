@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseURLs(t *testing.T) {
+	t.Parallel()
+
+	for _, toPin := range []struct {
+		Name     string
+		Doc      spec.SwaggerProps
+		Expected []string
+	}{
+		{
+			Name:     "no host",
+			Doc:      spec.SwaggerProps{BasePath: "api/"},
+			Expected: []string{"/api"},
+		},
+		{
+			Name:     "no scheme",
+			Doc:      spec.SwaggerProps{Host: "api.example.com", BasePath: "/v1"},
+			Expected: []string{"//api.example.com/v1"},
+		},
+		{
+			Name:     "with schemes",
+			Doc:      spec.SwaggerProps{Host: "api.example.com", Schemes: []string{"https", "HTTP", "http"}},
+			Expected: []string{"https://api.example.com/", "http://api.example.com/"},
+		},
+	} {
+		fixture := toPin
+		t.Run(fixture.Name, func(t *testing.T) {
+			t.Parallel()
+
+			an := New(&spec.Swagger{SwaggerProps: fixture.Doc})
+			assert.Equal(t, fixture.Expected, an.BaseURLs())
+		})
+	}
+}
+
+func TestBaseURLs_Servers(t *testing.T) {
+	t.Parallel()
+
+	doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Host:    "api.example.com",
+		Schemes: []string{"https"},
+	}}
+	doc.AddExtension(xServers, []interface{}{
+		map[string]interface{}{
+			"url": "https://{region}.example.com/{version}",
+			"variables": map[string]interface{}{
+				"region":  map[string]interface{}{"default": "eu", "enum": []interface{}{"us", "eu"}},
+				"version": map[string]interface{}{"default": "v2"},
+			},
+		},
+		map[string]interface{}{"url": "https://backup.example.com"},
+	})
+
+	op := spec.NewOperation("getPets")
+	op.Schemes = []string{"http"}
+	other := spec.NewOperation("getOthers")
+	other.AddExtension(xServer, "https://other.example.com/api")
+
+	an := New(doc)
+	expected := []string{"https://eu.example.com/v2", "https://us.example.com/v2", "https://backup.example.com"}
+	assert.Equal(t, expected, an.BaseURLs())
+	assert.Equal(t, expected, an.BaseURLsFor(op))
+	assert.Equal(t, []string{"https://other.example.com/api"}, an.BaseURLsFor(other))
+
+	// extensions are case insensitive
+	mixed := spec.NewOperation("getMixed")
+	mixed.Extensions = spec.Extensions{"X-Server": "https://mixed.example.com"}
+	assert.Equal(t, []string{"https://mixed.example.com"}, an.BaseURLsFor(mixed))
+
+	doc.Extensions = nil
+	an = New(doc)
+	assert.Equal(t, []string{"http://api.example.com/"}, an.BaseURLsFor(op))
+	assert.Equal(t, []string{"https://api.example.com/"}, an.BaseURLsFor(spec.NewOperation("")))
+}
@@ -0,0 +1,152 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/go-openapi/spec"
+)
+
+// BodySchema describes the payload accepted by an operation
+type BodySchema struct {
+	// In is "body" for a body parameter, or "formData" for a set of form parameters
+	In string
+
+	// Name is the name of the body parameter. It is empty for form parameters.
+	Name string
+
+	// Required tells if the payload is required: the body parameter is required, or some form parameter is
+	Required bool
+
+	// Schema is the schema of the payload, with its $ref resolved. Form parameters are represented as
+	// the properties of an object schema, with the type "file" for file uploads.
+	Schema *spec.Schema
+
+	// Analysis is the analysis of the schema of the payload, before its $ref is resolved
+	// (so the name of the definition it refers to is known from its GoTypeHint)
+	Analysis *AnalyzedSchema
+}
+
+// BodySchemaFor determines the payload accepted by an operation, from its body parameter or its form parameters
+// (see SynthesizeFormSchema), including those of its path item.
+//
+// This returns nil when the operation accepts no payload, or does not belong to the spec.
+func (s *Spec) BodySchemaFor(op *spec.Operation) (*BodySchema, error) {
+	params, err := s.operationParams(op)
+	if err != nil {
+		return nil, err
+	}
+
+	var form []spec.Parameter
+	for _, param := range params {
+		switch param.In {
+		case "body":
+			if param.Schema == nil {
+				continue
+			}
+
+			return s.bodySchemaOf(param)
+		case "formData":
+			form = append(form, param)
+		}
+	}
+
+	if len(form) == 0 {
+		return nil, nil
+	}
+
+	schema := formSchemaOf(form)
+	analysis, err := Schema(SchemaOpts{Schema: schema, Root: s.spec})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BodySchema{In: "formData", Required: len(schema.Required) > 0, Schema: schema, Analysis: analysis}, nil
+}
+
+// SynthesizeFormSchema represents the form parameters of an operation, including those of its path item,
+// as a single object schema: each parameter is a property, with the type "file" for file uploads,
+// and required parameters are required properties.
+//
+// This returns nil when the operation has no form parameters, or does not belong to the spec.
+func (s *Spec) SynthesizeFormSchema(op *spec.Operation) (*spec.Schema, error) {
+	params, err := s.operationParams(op)
+	if err != nil {
+		return nil, err
+	}
+
+	var form []spec.Parameter
+	for _, param := range params {
+		if param.In == "formData" {
+			form = append(form, param)
+		}
+	}
+
+	if len(form) == 0 {
+		return nil, nil
+	}
+
+	return formSchemaOf(form), nil
+}
+
+// operationParams yields the parameters of an operation and its path item, with their $ref resolved,
+// sorted by location then by name
+func (s *Spec) operationParams(op *spec.Operation) ([]spec.Parameter, error) {
+	key, found := s.operationKeyOf(op)
+	if !found {
+		return nil, nil
+	}
+
+	var paramErr error
+	byKey := s.SafeParamsFor(key.Method, key.Path, func(param spec.Parameter, err error) bool {
+		paramErr = &RefError{Ref: param.Ref.String(), Cause: err}
+
+		return false
+	})
+	if paramErr != nil {
+		return nil, paramErr
+	}
+
+	params := make([]spec.Parameter, 0, len(byKey))
+	for _, mapKey := range sortedKeys(byKey) {
+		params = append(params, byKey[mapKey])
+	}
+
+	return params, nil
+}
+
+func (s *Spec) bodySchemaOf(param spec.Parameter) (*BodySchema, error) {
+	analysis, err := Schema(SchemaOpts{Schema: param.Schema, Root: s.spec})
+	if err != nil {
+		return nil, err
+	}
+
+	schema := param.Schema
+	if ref := schema.Ref; ref.String() != "" {
+		if schema, err = spec.ResolveRef(s.spec, &ref); err != nil {
+			return nil, &RefError{Ref: ref.String(), Cause: err}
+		}
+	}
+
+	return &BodySchema{In: "body", Name: param.Name, Required: param.Required, Schema: schema, Analysis: analysis}, nil
+}
+
+// formSchemaOf represents form parameters as the properties of an object schema
+func formSchemaOf(form []spec.Parameter) *spec.Schema {
+	schema := new(spec.Schema).Typed("object", "")
+	for _, param := range form {
+		property := simpleSchemaOf(param.SimpleSchema, param.CommonValidations)
+		property.Description = param.Description
+		property.Default = param.Default
+		for name, value := range param.Extensions {
+			property.AddExtension(name, value)
+		}
+		schema.SetProperty(param.Name, *property)
+
+		if param.Required {
+			schema.Required = append(schema.Required, param.Name)
+		}
+	}
+	sort.Strings(schema.Required)
+
+	return schema
+}
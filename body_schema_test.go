@@ -0,0 +1,116 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_BodySchemaFor(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "body_schema.yml")))
+	bodyFor := func(method, path string) (*BodySchema, error) {
+		op, found := an.OperationFor(method, path)
+		require.True(t, found)
+
+		return an.BodySchemaFor(op)
+	}
+
+	t.Run("should resolve the schema of a shared body parameter", func(t *testing.T) {
+		body, err := bodyFor("POST", "/pets")
+		require.NoError(t, err)
+		require.NotNil(t, body)
+
+		assert.Equal(t, "body", body.In)
+		assert.Equal(t, "pet", body.Name)
+		assert.True(t, body.Required)
+		assert.Contains(t, body.Schema.Properties, "name")
+		assert.Equal(t, "pet", body.Analysis.GoTypeHint.Definition)
+	})
+
+	t.Run("should analyze an inline body schema", func(t *testing.T) {
+		body, err := bodyFor("PUT", "/pets/{id}")
+		require.NoError(t, err)
+		require.NotNil(t, body)
+
+		assert.False(t, body.Required)
+		assert.True(t, body.Analysis.IsSimpleArray)
+	})
+
+	t.Run("should represent form parameters as an object", func(t *testing.T) {
+		body, err := bodyFor("POST", "/pets/{id}/photo")
+		require.NoError(t, err)
+		require.NotNil(t, body)
+
+		assert.Equal(t, "formData", body.In)
+		assert.Empty(t, body.Name)
+		assert.True(t, body.Required)
+		assert.Equal(t, []string{"photo"}, body.Schema.Required)
+		assert.True(t, body.Schema.Properties["photo"].Type.Contains("file"))
+		require.NotNil(t, body.Schema.Properties["caption"].MaxLength)
+		assert.EqualValues(t, 140, *body.Schema.Properties["caption"].MaxLength)
+		assert.NotContains(t, body.Schema.Properties, "id")
+	})
+
+	t.Run("should find no payload", func(t *testing.T) {
+		body, err := bodyFor("GET", "/pets")
+		require.NoError(t, err)
+		assert.Nil(t, body)
+
+		body, err = an.BodySchemaFor(&spec.Operation{})
+		require.NoError(t, err)
+		assert.Nil(t, body)
+	})
+
+	t.Run("should fail on an unresolved parameter", func(t *testing.T) {
+		_, err := bodyFor("POST", "/broken")
+		require.Error(t, err)
+
+		var refErr *RefError
+		require.ErrorAs(t, err, &refErr)
+		assert.Equal(t, "#/parameters/missing", refErr.Ref)
+	})
+}
+
+func TestAnalyzer_SynthesizeFormSchema(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "body_schema.yml")))
+
+	op, found := an.OperationFor("POST", "/pets/{id}/photo")
+	require.True(t, found)
+
+	schema, err := an.SynthesizeFormSchema(op)
+	require.NoError(t, err)
+	require.NotNil(t, schema)
+
+	assert.True(t, schema.Type.Contains("object"))
+	assert.Equal(t, []string{"caption", "photo", "tags"}, sortedKeys(schema.Properties))
+	assert.Equal(t, []string{"photo"}, schema.Required)
+	assert.True(t, schema.Properties["photo"].Type.Contains("file"))
+	assert.Equal(t, "Legend", schema.Properties["caption"].Extensions["x-go-name"])
+
+	tags := schema.Properties["tags"]
+	assert.True(t, tags.Type.Contains("array"))
+	require.NotNil(t, tags.Items)
+	assert.True(t, tags.Items.Schema.Type.Contains("string"))
+
+	for _, method := range []string{"GET", "POST"} {
+		op, found := an.OperationFor(method, "/pets")
+		require.True(t, found)
+
+		schema, err := an.SynthesizeFormSchema(op)
+		require.NoError(t, err)
+		assert.Nil(t, schema)
+	}
+
+	op, found = an.OperationFor("POST", "/broken")
+	require.True(t, found)
+	_, err = an.SynthesizeFormSchema(op)
+	require.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+const definitionsPrefix = definitionsPath + "/"
+
+// BulkOperation describes an operation accepting an array of some resource as its body (e.g. bulk create or update),
+// paired with the single-item operations on the same resource with the same method.
+type BulkOperation struct {
+	OperationKey
+
+	// Resource is the definition name of the items of the body (e.g. "pet"),
+	// or the $ref of the items when these are not local definitions
+	Resource string
+
+	// Singles lists the operations with the same method, accepting a single Resource as their body, sorted by path
+	Singles []OperationKey
+}
+
+type methodResource struct {
+	method   string
+	resource string
+}
+
+// BulkOperations detects operations accepting arrays of a resource in their body, and pairs them
+// with the single-item operations on the same resource.
+//
+// Only bodies referring to a named resource are considered: the body may either be an array with $ref items,
+// or a $ref to a definition which is such an array.
+//
+// Bulk operations are sorted by path, then by method.
+func (s *Spec) BulkOperations() []BulkOperation {
+	var (
+		bulks   []BulkOperation
+		singles = make(map[methodResource][]OperationKey)
+	)
+
+	for _, key := range s.sortedOperationKeys() {
+		resource, isBulk := s.bodyResource(key.Method, key.Path)
+		if resource == "" {
+			continue
+		}
+
+		if isBulk {
+			bulks = append(bulks, BulkOperation{OperationKey: key, Resource: resource})
+
+			continue
+		}
+
+		byResource := methodResource{method: key.Method, resource: resource}
+		singles[byResource] = append(singles[byResource], key)
+	}
+
+	for i := range bulks {
+		bulks[i].Singles = singles[methodResource{method: bulks[i].Method, resource: bulks[i].Resource}]
+	}
+
+	return bulks
+}
+
+// sortedOperationKeys lists all operations, sorted by path then by method
+func (s *Spec) sortedOperationKeys() []OperationKey {
+	var keys []OperationKey
+	s.EachOperation(func(method, path string, _ *spec.Operation) bool {
+		keys = append(keys, OperationKey{Method: method, Path: path})
+
+		return true
+	})
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Path == keys[j].Path {
+			return keys[i].Method < keys[j].Method
+		}
+
+		return keys[i].Path < keys[j].Path
+	})
+
+	return keys
+}
+
+// bodyResource determines the resource accepted in the body of an operation, and whether this is an array of it
+func (s *Spec) bodyResource(method, path string) (string, bool) {
+	params := s.SafeParamsFor(method, path, func(spec.Parameter, error) bool { return true })
+
+	for _, param := range params {
+		if param.In != "body" || param.Schema == nil {
+			continue
+		}
+
+		schema := param.Schema
+		if ref := schema.Ref.String(); ref != "" {
+			def, ok := s.definitionFor(ref)
+			if !ok || !def.Type.Contains("array") {
+				return resourceName(ref), false
+			}
+
+			schema = def
+		}
+
+		if schema.Type.Contains("array") && schema.Items != nil && schema.Items.Schema != nil {
+			if ref := schema.Items.Schema.Ref.String(); ref != "" {
+				return resourceName(ref), true
+			}
+		}
+
+		return "", false
+	}
+
+	return "", false
+}
+
+// definitionFor resolves a $ref to a local definition
+func (s *Spec) definitionFor(ref string) (*spec.Schema, bool) {
+	if !strings.HasPrefix(ref, definitionsPrefix) {
+		return nil, false
+	}
+
+	def, ok := s.spec.Definitions[jsonpointer.Unescape(strings.TrimPrefix(ref, definitionsPrefix))]
+	if !ok {
+		return nil, false
+	}
+
+	return &def, true
+}
+
+// resourceName yields the name of a local definition, or the $ref itself for other references
+func resourceName(ref string) string {
+	if strings.HasPrefix(ref, definitionsPrefix) {
+		return jsonpointer.Unescape(strings.TrimPrefix(ref, definitionsPrefix))
+	}
+
+	return ref
+}
@@ -0,0 +1,35 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkOperations(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "operations", "fixture-bulk.yaml")))
+
+	assert.Equal(t, []BulkOperation{
+		{
+			OperationKey: OperationKey{Method: "POST", Path: "/orders/bulk"},
+			Resource:     "order",
+		},
+		{
+			OperationKey: OperationKey{Method: "POST", Path: "/pets/bulk"},
+			Resource:     "pet",
+			Singles:      []OperationKey{{Method: "POST", Path: "/pets"}},
+		},
+		{
+			OperationKey: OperationKey{Method: "PUT", Path: "/pets/bulk"},
+			Resource:     "pet",
+			Singles:      []OperationKey{{Method: "PUT", Path: "/pets"}, {Method: "PUT", Path: "/pets/{id}"}},
+		},
+	}, an.BulkOperations())
+
+	assert.Empty(t, New(&spec.Swagger{}).BulkOperations())
+}
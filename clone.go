@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"reflect"
+
+	"github.com/go-openapi/spec"
+)
+
+// CloneSpec performs a deep copy of a spec, e.g. to protect a document from the transforms which modify
+// their input in place (Flatten, Mixin, ...).
+//
+// Unlike a round trip through JSON, the copy is exact: the values of vendor extensions, examples, defaults
+// and enums keep their Go types (e.g. an int64 remains an int64), and nil and empty collections are preserved.
+func CloneSpec(sp *spec.Swagger) *spec.Swagger {
+	if sp == nil {
+		return nil
+	}
+
+	return cloneValue(reflect.ValueOf(sp)).Interface().(*spec.Swagger)
+}
+
+// CloneSchema performs a deep copy of a schema (see CloneSpec)
+func CloneSchema(schema *spec.Schema) *spec.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	return cloneValue(reflect.ValueOf(schema)).Interface().(*spec.Schema)
+}
+
+// cloneValue performs a deep copy of a value.
+//
+// Unexported struct fields are copied as they are: in the spec package, these only hold parsed $ref's,
+// which are replaced rather than modified.
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+
+		clone := reflect.New(v.Type().Elem())
+		clone.Elem().Set(cloneValue(v.Elem()))
+
+		return clone
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(cloneValue(v.Elem()))
+
+		return clone
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		clone := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			clone.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+
+		return clone
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		clone := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			clone.Index(i).Set(cloneValue(v.Index(i)))
+		}
+
+		return clone
+	case reflect.Array:
+		clone := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			clone.Index(i).Set(cloneValue(v.Index(i)))
+		}
+
+		return clone
+	case reflect.Struct:
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if field := clone.Field(i); field.CanSet() {
+				field.Set(cloneValue(v.Field(i)))
+			}
+		}
+
+		return clone
+	default:
+		return v
+	}
+}
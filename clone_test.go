@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneSpec(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "flatten.yml"))
+	sp.AddExtension("x-count", int64(3))
+	sp.AddExtension("x-empty", []interface{}{})
+
+	clone := CloneSpec(sp)
+	require.Equal(t, sp, clone)
+	assert.IsType(t, int64(0), clone.Extensions["x-count"])
+	assert.NotNil(t, clone.Extensions["x-empty"])
+
+	// the copy is independent from the original spec
+	original := CloneSpec(sp)
+	require.NoError(t, Flatten(FlattenOpts{Spec: New(clone), BasePath: filepath.Join("fixtures", "flatten.yml")}))
+	clone.Info.Title = "changed"
+	clone.Extensions["x-count"] = int64(4)
+
+	assert.Equal(t, original, sp)
+	assert.NotEqual(t, sp, clone)
+
+	assert.Nil(t, CloneSpec(nil))
+}
+
+func TestCloneSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := spec.MapProperty(spec.StringProperty().WithEnum("a", "b")).WithDefault(map[string]interface{}{"k": "a"})
+	schema.Properties = spec.SchemaProperties{"pet": *spec.RefSchema("#/definitions/pet")}
+	schema.AddExtension("x-go-name", "Labels")
+
+	clone := CloneSchema(schema)
+	require.Equal(t, schema, clone)
+	pet := clone.Properties["pet"]
+	assert.Equal(t, "#/definitions/pet", pet.Ref.String())
+
+	clone.AdditionalProperties.Schema.Enum[0] = "c"
+	clone.Default.(map[string]interface{})["k"] = "c"
+	clone.Properties["pet"] = *spec.StringProperty()
+
+	assert.Equal(t, "a", schema.AdditionalProperties.Schema.Enum[0])
+	assert.Equal(t, "a", schema.Default.(map[string]interface{})["k"])
+	pet = schema.Properties["pet"]
+	assert.Equal(t, "#/definitions/pet", pet.Ref.String())
+
+	assert.Nil(t, CloneSchema(nil))
+}
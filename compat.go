@@ -0,0 +1,388 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// Classes of changes reported by CheckCompatibility, besides the changes of schemas (see DiffSchemas)
+const (
+	ChangeOperationAdded   SchemaChangeKind = "operation-added"
+	ChangeOperationRemoved SchemaChangeKind = "operation-removed"
+	ChangeParameterAdded   SchemaChangeKind = "parameter-added"
+	ChangeParameterRemoved SchemaChangeKind = "parameter-removed"
+	ChangeResponseRemoved  SchemaChangeKind = "response-removed"
+)
+
+// CompatDirection tells whether a change affects what clients send (requests) or what they receive (responses)
+type CompatDirection string
+
+// Directions of changes
+const (
+	CompatRequest  CompatDirection = "request"
+	CompatResponse CompatDirection = "response"
+)
+
+// CompatRule sets the severity of a class of changes, e.g. to report new enum values in responses as errors,
+// but not in requests
+type CompatRule struct {
+	Kind      SchemaChangeKind
+	Direction CompatDirection // the direction of the changes to match, empty to match both directions
+	Severity  Severity        // the severity of the changes, empty to ignore them
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// CompatOpts configures CheckCompatibility
+type CompatOpts struct {
+	// Rules set the severity of classes of changes, over DefaultCompatRules: the last rule matching a change applies
+	Rules []CompatRule
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// CompatFinding is a change between two versions of a spec, with its severity for clients.
+//
+// The Code of the finding is its Kind. The Pointer locates the change in the new version of the spec,
+// or in the former version for removals.
+type CompatFinding struct {
+	Finding
+
+	Kind      SchemaChangeKind
+	Direction CompatDirection
+	Severity  Severity
+}
+
+// DefaultCompatRules returns the default severities of changes: SeverityError for the changes which break
+// existing clients, SeverityWarning for the changes which may break some clients and SeverityInfo for the others.
+//
+// Changes are breaking when they reject requests which were valid (e.g. a new required property, a tightened
+// constraint or a removed enum value in requests), or when they send responses clients may not expect
+// (e.g. a removed property, or a new enum value in responses).
+func DefaultCompatRules() []CompatRule {
+	return []CompatRule{
+		{Kind: ChangeOperationAdded, Severity: SeverityInfo},
+		{Kind: ChangeOperationRemoved, Severity: SeverityError},
+		{Kind: ChangeParameterAdded, Severity: SeverityInfo},
+		{Kind: ChangeParameterRemoved, Severity: SeverityWarning},
+		{Kind: ChangeResponseRemoved, Severity: SeverityWarning},
+		{Kind: ChangePropertyAdded, Severity: SeverityInfo},
+		{Kind: ChangePropertyRemoved, Direction: CompatRequest, Severity: SeverityWarning},
+		{Kind: ChangePropertyRemoved, Direction: CompatResponse, Severity: SeverityError},
+		{Kind: ChangeTypeChanged, Severity: SeverityError},
+		{Kind: ChangeFormatChanged, Severity: SeverityError},
+		{Kind: ChangeRefChanged, Severity: SeverityWarning},
+		{Kind: ChangeRequiredAdded, Direction: CompatRequest, Severity: SeverityError},
+		{Kind: ChangeRequiredAdded, Direction: CompatResponse, Severity: SeverityInfo},
+		{Kind: ChangeRequiredRemoved, Direction: CompatRequest, Severity: SeverityInfo},
+		{Kind: ChangeRequiredRemoved, Direction: CompatResponse, Severity: SeverityError},
+		{Kind: ChangeEnumValueAdded, Direction: CompatRequest, Severity: SeverityInfo},
+		{Kind: ChangeEnumValueAdded, Direction: CompatResponse, Severity: SeverityError},
+		{Kind: ChangeEnumValueRemoved, Direction: CompatRequest, Severity: SeverityError},
+		{Kind: ChangeEnumValueRemoved, Direction: CompatResponse, Severity: SeverityInfo},
+		{Kind: ChangeConstraintTightened, Direction: CompatRequest, Severity: SeverityError},
+		{Kind: ChangeConstraintTightened, Direction: CompatResponse, Severity: SeverityInfo},
+		{Kind: ChangeConstraintRelaxed, Direction: CompatRequest, Severity: SeverityInfo},
+		{Kind: ChangeConstraintRelaxed, Direction: CompatResponse, Severity: SeverityWarning},
+		{Kind: ChangeCompositionMemberAdded, Severity: SeverityWarning},
+		{Kind: ChangeCompositionMemberRemoved, Severity: SeverityWarning},
+		{Kind: ChangeAnnotationChanged, Severity: SeverityInfo},
+	}
+}
+
+// CheckCompatibility reports the changes between two versions of a spec which affect clients, with their severity:
+// operations added or removed, parameters, request bodies, responses and definitions changed (see DiffSchemas).
+//
+// A change to a definition is reported once for each direction this definition is used in, by the $ref's
+// of parameters and responses. Changes to unused definitions are not reported.
+// Findings are sorted by pointer, then by code.
+func CheckCompatibility(before, after *spec.Swagger, opts CompatOpts) []CompatFinding {
+	c := &compatCheck{
+		before: newSpec(before),
+		after:  newSpec(after),
+		rules:  append(DefaultCompatRules(), opts.Rules...),
+	}
+
+	c.checkOperations()
+	c.checkDefinitions()
+
+	sort.SliceStable(c.findings, func(i, j int) bool {
+		if c.findings[i].Pointer == c.findings[j].Pointer {
+			return c.findings[i].Code < c.findings[j].Code
+		}
+
+		return c.findings[i].Pointer < c.findings[j].Pointer
+	})
+
+	return c.findings
+}
+
+type compatCheck struct {
+	before, after *Spec
+	rules         []CompatRule
+	findings      []CompatFinding
+}
+
+// severityOf yields the severity of a class of changes, from the last matching rule
+func (c *compatCheck) severityOf(kind SchemaChangeKind, direction CompatDirection) Severity {
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		rule := c.rules[i]
+		if rule.Kind == kind && (rule.Direction == "" || rule.Direction == direction) {
+			return rule.Severity
+		}
+	}
+
+	return SeverityWarning
+}
+
+func (c *compatCheck) report(pointer string, kind SchemaChangeKind, direction CompatDirection, message string) {
+	severity := c.severityOf(kind, direction)
+	if severity == "" {
+		return
+	}
+
+	c.findings = append(c.findings, CompatFinding{
+		Finding:   Finding{Pointer: pointer, Code: string(kind), Message: message},
+		Kind:      kind,
+		Direction: direction,
+		Severity:  severity,
+	})
+}
+
+// reportSchemaChanges reports the changes of a schema located at some pointer
+func (c *compatCheck) reportSchemaChanges(pointer string, direction CompatDirection, before, after *spec.Schema) {
+	for _, change := range DiffSchemas(before, after, SchemaDiffOpts{}) {
+		c.report(pointer+strings.TrimPrefix(change.Pointer, "#"), change.Kind, direction, change.Message)
+	}
+}
+
+func (c *compatCheck) checkOperations() {
+	afterKeys := make(map[OperationKey]bool)
+	for _, key := range c.after.sortedOperationKeys() {
+		afterKeys[key] = true
+	}
+
+	for _, key := range c.before.sortedOperationKeys() {
+		if !afterKeys[key] {
+			c.report(key.pointer(), ChangeOperationRemoved, "", fmt.Sprintf("operation %s removed", key))
+
+			continue
+		}
+		delete(afterKeys, key)
+
+		opBefore, _ := c.before.OperationFor(key.Method, key.Path)
+		opAfter, _ := c.after.OperationFor(key.Method, key.Path)
+		c.checkParameters(key, opBefore, opAfter)
+		c.checkResponses(key, opBefore, opAfter)
+	}
+
+	for _, key := range c.after.sortedOperationKeys() {
+		if afterKeys[key] {
+			c.report(key.pointer(), ChangeOperationAdded, "", fmt.Sprintf("operation %s added", key))
+		}
+	}
+}
+
+func (c *compatCheck) checkParameters(key OperationKey, opBefore, opAfter *spec.Operation) {
+	paramsBefore, _ := c.before.operationParams(opBefore)
+	paramsAfter, _ := c.after.operationParams(opAfter)
+
+	byKey := make(map[string]spec.Parameter, len(paramsBefore))
+	for _, param := range paramsBefore {
+		byKey[mapKeyFromParam(&param)] = param
+	}
+
+	for _, param := range paramsAfter {
+		pointer := parameterPointer(key, opAfter, param)
+		former, existed := byKey[mapKeyFromParam(&param)]
+		delete(byKey, mapKeyFromParam(&param))
+
+		switch {
+		case !existed && param.Required:
+			c.report(pointer, ChangeRequiredAdded, CompatRequest, fmt.Sprintf("required %s parameter %q added", param.In, param.Name))
+		case !existed:
+			c.report(pointer, ChangeParameterAdded, CompatRequest, fmt.Sprintf("%s parameter %q added", param.In, param.Name))
+		case param.Required && !former.Required:
+			c.report(pointer, ChangeRequiredAdded, CompatRequest, fmt.Sprintf("%s parameter %q now required", param.In, param.Name))
+		case !param.Required && former.Required:
+			c.report(pointer, ChangeRequiredRemoved, CompatRequest, fmt.Sprintf("%s parameter %q no longer required", param.In, param.Name))
+		}
+
+		if !existed {
+			continue
+		}
+
+		if param.In == "body" {
+			c.reportSchemaChanges(pointer+"/schema", CompatRequest, former.Schema, param.Schema)
+		} else {
+			c.reportSchemaChanges(pointer, CompatRequest, parameterSchema(former), parameterSchema(param))
+		}
+	}
+
+	for _, mapKey := range sortedKeys(byKey) {
+		param := byKey[mapKey]
+		pointer := parameterPointer(key, opBefore, param)
+		c.report(pointer, ChangeParameterRemoved, CompatRequest, fmt.Sprintf("%s parameter %q removed", param.In, param.Name))
+	}
+}
+
+// parameterSchema represents a non-body parameter as a schema, with the schema of its items
+func parameterSchema(param spec.Parameter) *spec.Schema {
+	schema := simpleSchemaOf(param.SimpleSchema, param.CommonValidations)
+	if param.Items != nil {
+		schema.Items = &spec.SchemaOrArray{Schema: simpleSchemaOf(param.Items.SimpleSchema, param.Items.CommonValidations)}
+	}
+
+	return schema
+}
+
+// parameterPointer locates a parameter declared by an operation, or the operation itself for parameters
+// declared by its path item or by a $ref
+func parameterPointer(key OperationKey, op *spec.Operation, param spec.Parameter) string {
+	for i, candidate := range op.Parameters {
+		if candidate.In == param.In && candidate.Name == param.Name {
+			return key.pointer() + "/parameters/" + strconv.Itoa(i)
+		}
+	}
+
+	return key.pointer()
+}
+
+func (c *compatCheck) checkResponses(key OperationKey, opBefore, opAfter *spec.Operation) {
+	responsesBefore := c.responsesOf(c.before, opBefore)
+	responsesAfter := c.responsesOf(c.after, opAfter)
+
+	for _, code := range sortedKeys(responsesBefore) {
+		pointer := key.pointer() + "/responses/" + jsonpointer.Escape(code)
+		former := responsesBefore[code]
+		response, exists := responsesAfter[code]
+		if !exists {
+			c.report(pointer, ChangeResponseRemoved, CompatResponse, fmt.Sprintf("response %s removed", code))
+
+			continue
+		}
+
+		if former.Schema != nil || response.Schema != nil {
+			c.reportSchemaChanges(pointer+"/schema", CompatResponse, former.Schema, response.Schema)
+		}
+	}
+}
+
+// responsesOf indexes the responses of an operation by status code (or "default"), with $ref's to shared
+// responses resolved
+func (c *compatCheck) responsesOf(s *Spec, op *spec.Operation) map[string]spec.Response {
+	responses := make(map[string]spec.Response)
+	if op.Responses == nil {
+		return responses
+	}
+
+	resolve := func(response spec.Response) spec.Response {
+		if response.Ref.String() == "" {
+			return response
+		}
+
+		if resolved, err := spec.ResolveResponse(s.spec, response.Ref); err == nil && resolved != nil {
+			return *resolved
+		}
+
+		return response
+	}
+
+	for code, response := range op.Responses.StatusCodeResponses {
+		responses[strconv.Itoa(code)] = resolve(response)
+	}
+
+	if op.Responses.Default != nil {
+		responses["default"] = resolve(*op.Responses.Default)
+	}
+
+	return responses
+}
+
+func (c *compatCheck) checkDefinitions() {
+	directions := definitionDirections(c.before)
+	for name, used := range definitionDirections(c.after) {
+		for direction := range used {
+			if directions[name] == nil {
+				directions[name] = make(map[CompatDirection]bool)
+			}
+			directions[name][direction] = true
+		}
+	}
+
+	for _, name := range sortedKeys(c.before.spec.Definitions) {
+		after, exists := c.after.spec.Definitions[name]
+		if !exists {
+			continue
+		}
+
+		before := c.before.spec.Definitions[name]
+		for _, direction := range []CompatDirection{CompatRequest, CompatResponse} {
+			if directions[name][direction] {
+				c.reportSchemaChanges(definitionsPrefix+jsonpointer.Escape(name), direction, &before, &after)
+			}
+		}
+	}
+}
+
+// definitionDirections tells, for each definition, whether it is used by requests or responses,
+// directly or transitively
+func definitionDirections(s *Spec) map[string]map[CompatDirection]bool {
+	g := s.refGraph()
+	directions := make(map[string]map[CompatDirection]bool)
+
+	for pointer, ref := range s.references.allRefs {
+		direction := directionOfPointer(pointer)
+		node := s.refGraphNode(g, ref.String())
+		if direction == "" || node == "" {
+			continue
+		}
+
+		reached := g.reachable(node)
+		reached[node] = true
+		for target := range reached {
+			name, isDefinition := definitionOfPointer(target)
+			if !isDefinition {
+				continue
+			}
+
+			if directions[name] == nil {
+				directions[name] = make(map[CompatDirection]bool)
+			}
+			directions[name][direction] = true
+		}
+	}
+
+	return directions
+}
+
+// directionOfPointer tells if a location of a spec is part of a request (a parameter) or of a response
+func directionOfPointer(pointer string) CompatDirection {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "#/"), "/")
+
+	var section string
+	switch {
+	case tokens[0] == "parameters" || tokens[0] == "responses":
+		section = tokens[0]
+	case tokens[0] == "paths" && len(tokens) > 2 && tokens[2] == "parameters":
+		section = tokens[2]
+	case tokens[0] == "paths" && len(tokens) > 3:
+		section = tokens[3]
+	}
+
+	switch section {
+	case "parameters":
+		return CompatRequest
+	case "responses":
+		return CompatResponse
+	default:
+		return ""
+	}
+}
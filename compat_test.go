@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	t.Parallel()
+
+	type finding struct {
+		Pointer   string
+		Kind      SchemaChangeKind
+		Direction CompatDirection
+		Severity  Severity
+	}
+
+	before := antest.LoadOrFail(t, filepath.Join("fixtures", "compat", "v1.yml"))
+	after := antest.LoadOrFail(t, filepath.Join("fixtures", "compat", "v2.yml"))
+
+	for _, toPin := range []struct {
+		Title    string
+		Rules    []CompatRule
+		Expected []finding
+	}{
+		{
+			Title: "default rules",
+			Expected: []finding{
+				{"#/definitions/kind", ChangeEnumValueAdded, CompatRequest, SeverityInfo},
+				{"#/definitions/kind", ChangeEnumValueAdded, CompatResponse, SeverityError},
+				{"#/definitions/newPet", ChangeRequiredAdded, CompatRequest, SeverityError},
+				{"#/definitions/pet/properties/nickname", ChangePropertyRemoved, CompatResponse, SeverityError},
+				{"#/paths/~1pets/get/parameters/0", ChangeConstraintTightened, CompatRequest, SeverityError},
+				{"#/paths/~1pets/get/parameters/1", ChangeParameterAdded, CompatRequest, SeverityInfo},
+				{"#/paths/~1pets/post/parameters/1", ChangeRequiredAdded, CompatRequest, SeverityError},
+				{"#/paths/~1pets~1{id}/delete", ChangeOperationRemoved, "", SeverityError},
+				{"#/paths/~1pets~1{id}/get", ChangeOperationAdded, "", SeverityInfo},
+			},
+		},
+		{
+			Title: "custom rules",
+			Rules: []CompatRule{
+				{Kind: ChangeEnumValueAdded, Direction: CompatResponse, Severity: SeverityInfo},
+				{Kind: ChangeEnumValueAdded, Direction: CompatRequest, Severity: SeverityError},
+				{Kind: ChangeOperationAdded},
+				{Kind: ChangeParameterAdded},
+				{Kind: ChangeConstraintTightened, Severity: SeverityWarning},
+			},
+			Expected: []finding{
+				{"#/definitions/kind", ChangeEnumValueAdded, CompatRequest, SeverityError},
+				{"#/definitions/kind", ChangeEnumValueAdded, CompatResponse, SeverityInfo},
+				{"#/definitions/newPet", ChangeRequiredAdded, CompatRequest, SeverityError},
+				{"#/definitions/pet/properties/nickname", ChangePropertyRemoved, CompatResponse, SeverityError},
+				{"#/paths/~1pets/get/parameters/0", ChangeConstraintTightened, CompatRequest, SeverityWarning},
+				{"#/paths/~1pets/post/parameters/1", ChangeRequiredAdded, CompatRequest, SeverityError},
+				{"#/paths/~1pets~1{id}/delete", ChangeOperationRemoved, "", SeverityError},
+			},
+		},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.Title, func(t *testing.T) {
+			t.Parallel()
+
+			findings := CheckCompatibility(before, after, CompatOpts{Rules: fixture.Rules})
+
+			actual := make([]finding, 0, len(findings))
+			for _, f := range findings {
+				assert.NotEmpty(t, f.Message)
+				assert.Equal(t, string(f.Kind), f.Code)
+				actual = append(actual, finding{f.Pointer, f.Kind, f.Direction, f.Severity})
+			}
+
+			assert.Equal(t, fixture.Expected, actual)
+		})
+	}
+}
+
+func TestCheckCompatibility_Same(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "compat", "v1.yml"))
+
+	assert.Empty(t, CheckCompatibility(sp, CloneSpec(sp), CompatOpts{}))
+}
@@ -0,0 +1,174 @@
+package analysis
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// ContentTypes classifies the media types consumed or produced by an operation
+type ContentTypes struct {
+	// MediaTypes are the media types of the operation, with the defaults of the spec applied
+	MediaTypes []string
+
+	JSON       bool // e.g. "application/json", "application/problem+json"
+	XML        bool // e.g. "application/xml", "application/atom+xml"
+	Multipart  bool // "multipart/form-data", or another multipart type
+	URLEncoded bool // "application/x-www-form-urlencoded"
+	Binary     bool // a binary stream, e.g. "application/octet-stream", "image/png"
+	Text       bool // e.g. "text/plain", "text/csv"
+}
+
+// IsJSONMediaType tells if a media type denotes JSON content, e.g. "application/json" or "application/vnd.api+json"
+func IsJSONMediaType(mediaType string) bool {
+	mt := baseMediaType(mediaType)
+
+	return mt == "application/json" || mt == "text/json" || strings.HasSuffix(mt, "+json")
+}
+
+// IsXMLMediaType tells if a media type denotes XML content, e.g. "application/xml" or "application/atom+xml"
+func IsXMLMediaType(mediaType string) bool {
+	mt := baseMediaType(mediaType)
+
+	return mt == "application/xml" || mt == "text/xml" || strings.HasSuffix(mt, "+xml")
+}
+
+// IsMultipartMediaType tells if a media type denotes multipart content, e.g. "multipart/form-data"
+func IsMultipartMediaType(mediaType string) bool {
+	return strings.HasPrefix(baseMediaType(mediaType), "multipart/")
+}
+
+// IsURLEncodedMediaType tells if a media type denotes a url-encoded form
+func IsURLEncodedMediaType(mediaType string) bool {
+	return baseMediaType(mediaType) == "application/x-www-form-urlencoded"
+}
+
+// IsBinaryMediaType tells if a media type denotes a binary stream, e.g. "application/octet-stream", an image,
+// an audio or a video stream, or an archive
+func IsBinaryMediaType(mediaType string) bool {
+	mt := baseMediaType(mediaType)
+	switch mt {
+	case "application/octet-stream", "application/pdf", "application/zip", "application/gzip", "application/x-tar":
+		return true
+	}
+
+	for _, prefix := range []string{"image/", "audio/", "video/", "font/"} {
+		if strings.HasPrefix(mt, prefix) {
+			return !strings.HasSuffix(mt, "+xml")
+		}
+	}
+
+	return false
+}
+
+// IsTextMediaType tells if a media type denotes plain text content, e.g. "text/plain" or "text/csv"
+func IsTextMediaType(mediaType string) bool {
+	mt := baseMediaType(mediaType)
+
+	return strings.HasPrefix(mt, "text/") && !IsJSONMediaType(mt) && !IsXMLMediaType(mt)
+}
+
+// baseMediaType yields a lower-cased media type, without parameters (e.g. "text/plain; charset=utf-8" yields "text/plain")
+func baseMediaType(mediaType string) string {
+	if mt, _, err := mime.ParseMediaType(mediaType); err == nil {
+		return mt
+	}
+
+	mt, _, _ := strings.Cut(mediaType, ";")
+
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
+// classifyContentTypes classifies some media types
+func classifyContentTypes(mediaTypes []string) ContentTypes {
+	result := ContentTypes{MediaTypes: mediaTypes}
+	for _, mt := range mediaTypes {
+		result.JSON = result.JSON || IsJSONMediaType(mt)
+		result.XML = result.XML || IsXMLMediaType(mt)
+		result.Multipart = result.Multipart || IsMultipartMediaType(mt)
+		result.URLEncoded = result.URLEncoded || IsURLEncodedMediaType(mt)
+		result.Binary = result.Binary || IsBinaryMediaType(mt)
+		result.Text = result.Text || IsTextMediaType(mt)
+	}
+
+	return result
+}
+
+// ConsumedContentTypes classifies the media types consumed by an operation
+func (s *Spec) ConsumedContentTypes(operation *spec.Operation) ContentTypes {
+	return classifyContentTypes(s.ConsumesFor(operation))
+}
+
+// ProducedContentTypes classifies the media types produced by an operation
+func (s *Spec) ProducedContentTypes(operation *spec.Operation) ContentTypes {
+	return classifyContentTypes(s.ProducesFor(operation))
+}
+
+// ProducesFile tells if an operation responds with a file: a response schema with "type: file",
+// or a string with format "binary"
+func (s *Spec) ProducesFile(operation *spec.Operation) bool {
+	if operation == nil || operation.Responses == nil {
+		return false
+	}
+
+	if operation.Responses.Default != nil && s.isFileResponse(*operation.Responses.Default) {
+		return true
+	}
+
+	for _, resp := range operation.Responses.StatusCodeResponses {
+		if s.isFileResponse(resp) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OperationsProducingFiles lists the operations responding with a file (see ProducesFile),
+// as "METHOD /path" (e.g. "GET /pets/{id}/photo"), sorted by path and method
+func (s *Spec) OperationsProducingFiles() []string {
+	var result []string
+	walkOperations(s.spec, func(pointer string, op *spec.Operation) {
+		if s.ProducesFile(op) {
+			parent, method := splitPointer(pointer)
+			_, path := splitPointer(parent)
+			result = append(result, fmt.Sprintf("%s %s", strings.ToUpper(method), path))
+		}
+	})
+
+	return result
+}
+
+// isFileResponse tells if a response, or the shared response it refers to, has a file schema
+func (s *Spec) isFileResponse(resp spec.Response) bool {
+	return isFileSchema(s.resolveSchema(s.resolveResponse(resp).Schema))
+}
+
+// resolveSchema follows the $ref's of a schema to local definitions
+func (s *Spec) resolveSchema(schema *spec.Schema) *spec.Schema {
+	visited := make(map[string]bool)
+	for schema != nil && schema.Ref.String() != "" {
+		ref := schema.Ref.String()
+		name, isDefinition := definitionOfPointer(ref)
+		if !isDefinition || ref != definitionsPrefix+jsonpointer.Escape(name) || visited[ref] {
+			return schema
+		}
+		visited[ref] = true
+
+		definition, ok := s.spec.Definitions[name]
+		if !ok {
+			return schema
+		}
+		schema = &definition
+	}
+
+	return schema
+}
+
+// isFileSchema tells if a schema denotes a file
+func isFileSchema(schema *spec.Schema) bool {
+	return schema != nil && (schema.Type.Contains("file") || schema.Type.Contains("string") && schema.Format == "binary")
+}
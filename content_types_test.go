@@ -0,0 +1,92 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaTypes(t *testing.T) {
+	t.Parallel()
+
+	for _, toPin := range []struct {
+		MediaType                                      string
+		JSON, XML, Multipart, URLEncoded, Binary, Text bool
+	}{
+		{MediaType: "application/json", JSON: true},
+		{MediaType: "application/JSON; charset=utf-8", JSON: true},
+		{MediaType: "application/vnd.api+json", JSON: true},
+		{MediaType: "application/xml", XML: true},
+		{MediaType: "application/atom+xml", XML: true},
+		{MediaType: "text/xml", XML: true},
+		{MediaType: "multipart/form-data", Multipart: true},
+		{MediaType: "multipart/mixed; boundary=frontier", Multipart: true},
+		{MediaType: "application/x-www-form-urlencoded", URLEncoded: true},
+		{MediaType: "application/octet-stream", Binary: true},
+		{MediaType: "image/png", Binary: true},
+		{MediaType: "image/svg+xml", XML: true},
+		{MediaType: "video/mp4", Binary: true},
+		{MediaType: "application/pdf", Binary: true},
+		{MediaType: "text/plain", Text: true},
+		{MediaType: "text/csv; charset=utf-8", Text: true},
+		{MediaType: "application/yaml"},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.MediaType, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, fixture.JSON, IsJSONMediaType(fixture.MediaType))
+			assert.Equal(t, fixture.XML, IsXMLMediaType(fixture.MediaType))
+			assert.Equal(t, fixture.Multipart, IsMultipartMediaType(fixture.MediaType))
+			assert.Equal(t, fixture.URLEncoded, IsURLEncodedMediaType(fixture.MediaType))
+			assert.Equal(t, fixture.Binary, IsBinaryMediaType(fixture.MediaType))
+			assert.Equal(t, fixture.Text, IsTextMediaType(fixture.MediaType))
+		})
+	}
+}
+
+func TestContentTypes(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "content_types.yml"))
+	an := New(sp)
+
+	listPets, ok := an.OperationFor("GET", "/pets")
+	require.True(t, ok)
+	consumed := an.ConsumedContentTypes(listPets)
+	assert.Equal(t, ContentTypes{MediaTypes: []string{"application/json"}, JSON: true}, consumed)
+	assert.False(t, an.ProducesFile(listPets))
+
+	createPet, ok := an.OperationFor("POST", "/pets")
+	require.True(t, ok)
+	consumed = an.ConsumedContentTypes(createPet)
+	assert.True(t, consumed.Multipart)
+	assert.True(t, consumed.URLEncoded)
+	assert.False(t, consumed.JSON)
+
+	getPhoto, ok := an.OperationFor("GET", "/pets/{id}/photo")
+	require.True(t, ok)
+	produced := an.ProducedContentTypes(getPhoto)
+	assert.True(t, produced.Binary)
+	assert.False(t, produced.JSON)
+	assert.True(t, an.ProducesFile(getPhoto))
+
+	putPhoto, ok := an.OperationFor("PUT", "/pets/{id}/photo")
+	require.True(t, ok)
+	assert.True(t, an.ConsumedContentTypes(putPhoto).Binary)
+	assert.False(t, an.ProducesFile(putPhoto))
+
+	getReport, ok := an.OperationFor("GET", "/pets/{id}/report")
+	require.True(t, ok)
+	produced = an.ProducedContentTypes(getReport)
+	assert.True(t, produced.Binary)
+	assert.True(t, produced.Text)
+	assert.True(t, produced.JSON)
+	assert.False(t, produced.XML)
+
+	assert.Equal(t, []string{"GET /pets/{id}/photo", "GET /pets/{id}/report"}, an.OperationsProducingFiles())
+}
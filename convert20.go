@@ -0,0 +1,879 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// Codes for the findings reporting what Convert30To20 cannot translate
+const (
+	CodeUnconvertibleCallbacks      = "unconvertible-callbacks"
+	CodeUnconvertibleLinks          = "unconvertible-links"
+	CodeUnconvertibleComposition    = "unconvertible-composition"
+	CodeUnconvertibleDiscriminator  = "unconvertible-discriminator"
+	CodeUnconvertibleContentTypes   = "unconvertible-content-types"
+	CodeUnconvertibleServers        = "unconvertible-servers"
+	CodeUnconvertibleParameter      = "unconvertible-parameter"
+	CodeUnconvertibleSecurityScheme = "unconvertible-security-scheme"
+	CodeUnconvertibleOperation      = "unconvertible-operation"
+	CodeUnconvertibleComponent      = "unconvertible-component"
+)
+
+const (
+	swagger20Version       = "2.0"
+	openAPI30VersionPrefix = "3.0."
+
+	componentsSchemasPrefix       = "#/components/schemas/"
+	componentsRequestBodiesPrefix = "#/components/requestBodies/"
+	componentsParametersPrefix    = "#/components/parameters/"
+	componentsResponsesPrefix     = "#/components/responses/"
+	componentsHeadersPrefix       = "#/components/headers/"
+
+	// name of body parameters translated from request bodies
+	defaultRequestBodyName = "body"
+
+	unconvertibleRefMessage = "$ref %s is retained, but the document it refers to is not converted"
+)
+
+// the methods of OpenAPI 3.0 operations, which swagger 2.0 supports
+var swagger20Methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// oauth2 flows of swagger 2.0, for the flows of OpenAPI 3.0, in order of preference
+var oauth2Flows20 = []struct{ from, to string }{
+	{"authorizationCode", "accessCode"},
+	{"implicit", "implicit"},
+	{"password", "password"},
+	{"clientCredentials", "application"},
+}
+
+// Convert30To20 translates an OpenAPI 3.0 document, as generic JSON, down to a swagger 2.0 spec:
+//   - servers become host, basePath and schemes (server variables take their default value);
+//   - request bodies become body or formData parameters, and their media types become consumes;
+//   - the media types of response contents become produces;
+//   - components move to definitions, parameters, responses and security definitions
+//     (shared request bodies become shared body parameters, shared headers are inlined);
+//   - parameter schemas and styles become simple parameters with collection formats;
+//   - schema keywords specific to OpenAPI 3.0 (nullable, discriminator) are translated.
+//
+// Findings report the features of OpenAPI 3.0 which swagger 2.0 cannot represent, sorted by pointer:
+// e.g. oneOf and anyOf, callbacks, links, cookie parameters, or different schemas for several media types.
+// When a content has several media types, the schema for a JSON media type is preferred.
+//
+// The document is not modified.
+func Convert30To20(doc map[string]interface{}) (*spec.Swagger, []Finding, error) {
+	if version, _ := doc["openapi"].(string); !strings.HasPrefix(version, openAPI30VersionPrefix) {
+		return nil, nil, fmt.Errorf("%w: openapi version %v", ErrUnsupportedDocument, doc["openapi"])
+	}
+
+	raw, err := genericJSON(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := &converter20{doc: raw.(map[string]interface{})}
+	c.components, _ = c.doc["components"].(map[string]interface{})
+
+	converted := c.convert()
+
+	buf, err := json.Marshal(converted)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sp := new(spec.Swagger)
+	if err := json.Unmarshal(buf, sp); err != nil {
+		return nil, nil, err
+	}
+
+	sortFindings(c.findings)
+
+	return sp, c.findings, nil
+}
+
+type converter20 struct {
+	doc        map[string]interface{}
+	components map[string]interface{}
+	findings   []Finding
+
+	// names of the shared body parameters translated from shared request bodies
+	requestBodyParams map[string]string
+}
+
+func (c *converter20) report(pointer, code, format string, args ...interface{}) {
+	c.findings = append(c.findings, Finding{Pointer: pointer, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *converter20) convert() map[string]interface{} {
+	converted := map[string]interface{}{"swagger": swagger20Version}
+
+	for key, value := range c.doc {
+		if strings.HasPrefix(key, "x-") {
+			converted[key] = value
+		}
+	}
+
+	for _, key := range []string{"info", "tags", "externalDocs", "security"} {
+		if value, ok := c.doc[key]; ok {
+			converted[key] = value
+		}
+	}
+
+	c.servers(converted)
+	c.componentsTo(converted)
+	converted["paths"] = c.paths()
+
+	return converted
+}
+
+// servers translates the servers of the document into host, basePath and schemes
+func (c *converter20) servers(converted map[string]interface{}) {
+	servers, _ := c.doc["servers"].([]interface{})
+
+	var host, basePath string
+	var schemes []interface{}
+	for i, item := range servers {
+		server, _ := item.(map[string]interface{})
+		u, err := url.Parse(serverURL(server))
+		if err != nil {
+			c.report(fmt.Sprintf("#/servers/%d", i), CodeUnconvertibleServers, "invalid server url: %v", err)
+
+			continue
+		}
+
+		if i == 0 {
+			host, basePath = u.Host, u.Path
+		} else if u.Host != host || u.Path != basePath {
+			c.report(fmt.Sprintf("#/servers/%d", i), CodeUnconvertibleServers,
+				"server %s is dropped: only servers with the same host and base path as the first one may be represented", u)
+
+			continue
+		}
+
+		if u.Scheme != "" && !containsValue(schemes, u.Scheme) {
+			schemes = append(schemes, u.Scheme)
+		}
+	}
+
+	if host != "" {
+		converted["host"] = host
+	}
+
+	if basePath != "" {
+		converted["basePath"] = basePath
+	}
+
+	if len(schemes) > 0 {
+		converted["schemes"] = schemes
+	}
+}
+
+// serverURL yields the url of a server, with its variables substituted by their default value
+func serverURL(server map[string]interface{}) string {
+	u, _ := server["url"].(string)
+	variables, _ := server["variables"].(map[string]interface{})
+	for _, name := range sortedKeys(variables) {
+		variable, _ := variables[name].(map[string]interface{})
+		value, _ := variable["default"].(string)
+		u = strings.ReplaceAll(u, "{"+name+"}", value)
+	}
+
+	return u
+}
+
+func (c *converter20) componentsTo(converted map[string]interface{}) {
+	for _, section := range sortedKeys(c.components) {
+		switch section {
+		case "schemas", "parameters", "requestBodies", "responses", "securitySchemes", "headers":
+		default:
+			if !strings.HasPrefix(section, "x-") {
+				c.report("#/components/"+jsonpointer.Escape(section), CodeUnconvertibleComponent,
+					"components %s have no equivalent: they are inlined where referred to, or dropped", section)
+			}
+		}
+	}
+
+	schemas, _ := c.components["schemas"].(map[string]interface{})
+	if len(schemas) > 0 {
+		definitions := make(map[string]interface{}, len(schemas))
+		for _, name := range sortedKeys(schemas) {
+			definitions[name] = c.schema(componentsSchemasPrefix+jsonpointer.Escape(name), schemas[name])
+		}
+		converted["definitions"] = definitions
+	}
+
+	parameters := make(map[string]interface{})
+	shared, _ := c.components["parameters"].(map[string]interface{})
+	for _, name := range sortedKeys(shared) {
+		param, _ := shared[name].(map[string]interface{})
+		if p := c.parameter(componentsParametersPrefix+jsonpointer.Escape(name), param); p != nil {
+			parameters[name] = p
+		}
+	}
+
+	// shared request bodies become shared body parameters, unless they hold forms
+	requestBodies, _ := c.components["requestBodies"].(map[string]interface{})
+	c.requestBodyParams = make(map[string]string, len(requestBodies))
+	for _, name := range sortedKeys(requestBodies) {
+		body, _ := requestBodies[name].(map[string]interface{})
+		pointer := componentsRequestBodiesPrefix + jsonpointer.Escape(name)
+		params, _ := c.requestBody(pointer, body)
+		if len(params) != 1 || params[0]["in"] != "body" {
+			continue
+		}
+
+		paramName := name
+		for _, exists := shared[paramName]; exists; _, exists = shared[paramName] {
+			paramName += "Body"
+		}
+
+		parameters[paramName] = params[0]
+		c.requestBodyParams[name] = paramName
+	}
+
+	if len(parameters) > 0 {
+		converted["parameters"] = parameters
+	}
+
+	responses, _ := c.components["responses"].(map[string]interface{})
+	if len(responses) > 0 {
+		convertedResponses := make(map[string]interface{}, len(responses))
+		for _, name := range sortedKeys(responses) {
+			resp, _ := responses[name].(map[string]interface{})
+			convertedResponses[name], _ = c.response(componentsResponsesPrefix+jsonpointer.Escape(name), resp)
+		}
+		converted["responses"] = convertedResponses
+	}
+
+	securitySchemes, _ := c.components["securitySchemes"].(map[string]interface{})
+	securityDefinitions := make(map[string]interface{}, len(securitySchemes))
+	for _, name := range sortedKeys(securitySchemes) {
+		scheme, _ := securitySchemes[name].(map[string]interface{})
+		if definition := c.securityScheme("#/components/securitySchemes/"+jsonpointer.Escape(name), scheme); definition != nil {
+			securityDefinitions[name] = definition
+		}
+	}
+
+	if len(securityDefinitions) > 0 {
+		converted["securityDefinitions"] = securityDefinitions
+	}
+}
+
+func (c *converter20) securityScheme(pointer string, scheme map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{})
+	for key, value := range scheme {
+		if key == "description" || strings.HasPrefix(key, "x-") {
+			converted[key] = value
+		}
+	}
+
+	switch scheme["type"] {
+	case "http":
+		if s, _ := scheme["scheme"].(string); !strings.EqualFold(s, "basic") {
+			c.report(pointer, CodeUnconvertibleSecurityScheme, "http authentication scheme %q has no equivalent", s)
+
+			return nil
+		}
+		converted["type"] = "basic"
+	case "apiKey":
+		if scheme["in"] == "cookie" {
+			c.report(pointer, CodeUnconvertibleSecurityScheme, "api keys in cookies have no equivalent")
+
+			return nil
+		}
+		converted["type"] = "apiKey"
+		converted["name"] = scheme["name"]
+		converted["in"] = scheme["in"]
+	case "oauth2":
+		flows, _ := scheme["flows"].(map[string]interface{})
+		var selected string
+		for _, flow := range oauth2Flows20 {
+			definition, ok := flows[flow.from].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if selected != "" {
+				c.report(pointer+"/flows/"+flow.from, CodeUnconvertibleSecurityScheme,
+					"oauth2 flow %s is dropped: only flow %s is retained", flow.from, selected)
+
+				continue
+			}
+
+			selected = flow.from
+			converted["type"] = "oauth2"
+			converted["flow"] = flow.to
+			for _, key := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+				if value, ok := definition[key]; ok {
+					converted[key] = value
+				}
+			}
+		}
+
+		if selected == "" {
+			c.report(pointer, CodeUnconvertibleSecurityScheme, "oauth2 security scheme without any known flow")
+
+			return nil
+		}
+	default:
+		c.report(pointer, CodeUnconvertibleSecurityScheme, "security scheme type %v has no equivalent", scheme["type"])
+
+		return nil
+	}
+
+	return converted
+}
+
+func (c *converter20) paths() map[string]interface{} {
+	converted := make(map[string]interface{})
+	paths, _ := c.doc["paths"].(map[string]interface{})
+
+	for _, pth := range sortedKeys(paths) {
+		pathItem, _ := paths[pth].(map[string]interface{})
+		pointer := "#/paths/" + jsonpointer.Escape(pth)
+		convertedItem := make(map[string]interface{})
+
+		for key, value := range pathItem {
+			switch {
+			case strings.HasPrefix(key, "x-"):
+				convertedItem[key] = value
+			case key == "$ref":
+				c.report(pointer, CodeUnconvertedRemoteRef, "path item "+unconvertibleRefMessage, value)
+				convertedItem[key] = value
+			case key == "servers":
+				c.report(pointer+"/servers", CodeUnconvertibleServers, "servers of a path item have no equivalent")
+			case key == "trace":
+				c.report(pointer+"/trace", CodeUnconvertibleOperation, "trace operations have no equivalent")
+			}
+		}
+
+		// parameters of the path item: request bodies only exist on operations in OpenAPI 3.0
+		if params := c.parameters(pointer+"/parameters", pathItem["parameters"]); len(params) > 0 {
+			convertedItem["parameters"] = params
+		}
+
+		for _, method := range swagger20Methods {
+			op, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			convertedItem[method] = c.operation(pointer+"/"+method, op)
+		}
+
+		converted[pth] = convertedItem
+	}
+
+	return converted
+}
+
+func (c *converter20) operation(pointer string, op map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{})
+	for key, value := range op {
+		switch key {
+		case "parameters", "requestBody", "responses":
+		case "callbacks":
+			c.report(pointer+"/callbacks", CodeUnconvertibleCallbacks, "callbacks have no equivalent")
+		case "servers":
+			c.report(pointer+"/servers", CodeUnconvertibleServers, "servers of an operation have no equivalent")
+		default:
+			converted[key] = value
+		}
+	}
+
+	params := c.parameters(pointer+"/parameters", op["parameters"])
+
+	if body, ok := op["requestBody"].(map[string]interface{}); ok {
+		bodyParams, consumes := c.requestBodyOrRef(pointer+"/requestBody", body)
+		for _, param := range bodyParams {
+			params = append(params, param)
+		}
+
+		if len(consumes) > 0 {
+			converted["consumes"] = consumes
+		}
+	}
+
+	if len(params) > 0 {
+		converted["parameters"] = params
+	}
+
+	responses, _ := op["responses"].(map[string]interface{})
+	convertedResponses := make(map[string]interface{}, len(responses))
+	produced := make(map[string]struct{})
+	for _, status := range sortedKeys(responses) {
+		resp, _ := responses[status].(map[string]interface{})
+		if strings.HasPrefix(status, "x-") {
+			convertedResponses[status] = responses[status]
+
+			continue
+		}
+
+		var mediaTypes []string
+		convertedResponses[status], mediaTypes = c.responseOrRef(pointer+"/responses/"+jsonpointer.Escape(status), resp)
+		for _, mt := range mediaTypes {
+			produced[mt] = struct{}{}
+		}
+	}
+	converted["responses"] = convertedResponses
+
+	if len(produced) > 0 {
+		converted["produces"] = sortedKeys(produced)
+	}
+
+	return converted
+}
+
+// parameters translates a list of parameters which are not request bodies
+func (c *converter20) parameters(pointer string, node interface{}) []interface{} {
+	params, _ := node.([]interface{})
+	converted := make([]interface{}, 0, len(params))
+	for i, item := range params {
+		param, _ := item.(map[string]interface{})
+		paramPointer := fmt.Sprintf("%s/%d", pointer, i)
+
+		if ref, isRef := param["$ref"].(string); isRef {
+			if !strings.HasPrefix(ref, componentsParametersPrefix) {
+				c.report(paramPointer, CodeUnconvertedRemoteRef, "parameter "+unconvertibleRefMessage, ref)
+				converted = append(converted, map[string]interface{}{"$ref": ref})
+
+				continue
+			}
+
+			// parameters dropped from the components are dropped from operations as well
+			shared, _ := c.components["parameters"].(map[string]interface{})
+			target, _ := shared[jsonpointer.Unescape(strings.TrimPrefix(ref, componentsParametersPrefix))].(map[string]interface{})
+			if target["in"] == "cookie" || target["content"] != nil {
+				continue
+			}
+
+			converted = append(converted, map[string]interface{}{"$ref": "#/parameters/" + strings.TrimPrefix(ref, componentsParametersPrefix)})
+
+			continue
+		}
+
+		if p := c.parameter(paramPointer, param); p != nil {
+			converted = append(converted, p)
+		}
+	}
+
+	return converted
+}
+
+// parameter translates a parameter which is not a request body. It yields nil for parameters which
+// cannot be translated.
+func (c *converter20) parameter(pointer string, param map[string]interface{}) map[string]interface{} {
+	if param["in"] == "cookie" {
+		c.report(pointer, CodeUnconvertibleParameter, "cookie parameter %v has no equivalent", param["name"])
+
+		return nil
+	}
+
+	if _, hasContent := param["content"]; hasContent {
+		c.report(pointer, CodeUnconvertibleParameter, "parameter %v described by a content has no equivalent", param["name"])
+
+		return nil
+	}
+
+	converted := make(map[string]interface{})
+	for key, value := range param {
+		switch key {
+		case "schema", "style", "explode", "example", "examples", "deprecated", "allowReserved":
+		default:
+			converted[key] = value
+		}
+	}
+
+	c.simpleSchema(pointer+"/schema", param["schema"], converted)
+
+	if converted["type"] == "array" {
+		if format := collectionFormatOf(param); format != "" {
+			converted["collectionFormat"] = format
+		} else if style, ok := param["style"].(string); ok {
+			c.report(pointer, CodeUnconvertibleParameter, "style %s has no equivalent collection format", style)
+		}
+	}
+
+	return converted
+}
+
+// collectionFormatOf yields the collection format of an array parameter, from its style
+func collectionFormatOf(param map[string]interface{}) string {
+	style, _ := param["style"].(string)
+	if style == "" {
+		switch param["in"] {
+		case "query", "cookie":
+			style = "form"
+		default:
+			style = "simple"
+		}
+	}
+
+	explode, isBool := param["explode"].(bool)
+	if !isBool {
+		explode = style == "form"
+	}
+
+	switch style {
+	case "form":
+		if explode {
+			return "multi"
+		}
+
+		return "csv"
+	case "simple":
+		return "csv"
+	case "spaceDelimited":
+		return "ssv"
+	case "pipeDelimited":
+		return "pipes"
+	default:
+		return ""
+	}
+}
+
+// simpleSchema moves the keywords of the schema of a parameter or a header to the parameter or header.
+// Schemas defined by a $ref are resolved.
+func (c *converter20) simpleSchema(pointer string, node interface{}, converted map[string]interface{}) {
+	schema, _ := node.(map[string]interface{})
+	if ref, isRef := schema["$ref"].(string); isRef {
+		schema = c.resolveComponent(ref, componentsSchemasPrefix, "schemas")
+		if schema == nil {
+			c.report(pointer, CodeUnconvertedRemoteRef, "the schema of a parameter or a header may not be a $ref: %s cannot be inlined", ref)
+
+			return
+		}
+	}
+
+	for _, key := range simpleSchemaKeys {
+		value, ok := schema[key]
+		if !ok {
+			continue
+		}
+
+		if key == "items" {
+			items := make(map[string]interface{})
+			c.simpleSchema(pointer+"/items", value, items)
+			value = items
+		}
+
+		converted[key] = value
+	}
+
+	if nullable, isBool := schema["nullable"].(bool); isBool && nullable {
+		converted["x-nullable"] = true
+	}
+}
+
+// resolveComponent resolves a local $ref to a component
+func (c *converter20) resolveComponent(ref, prefix, section string) map[string]interface{} {
+	if !strings.HasPrefix(ref, prefix) {
+		return nil
+	}
+
+	entries, _ := c.components[section].(map[string]interface{})
+	resolved, _ := entries[jsonpointer.Unescape(strings.TrimPrefix(ref, prefix))].(map[string]interface{})
+
+	return resolved
+}
+
+// requestBodyOrRef translates a request body, or a $ref to a shared request body. It yields parameters
+// and the consumed media types.
+func (c *converter20) requestBodyOrRef(pointer string, body map[string]interface{}) ([]map[string]interface{}, []string) {
+	ref, isRef := body["$ref"].(string)
+	if !isRef {
+		return c.requestBody(pointer, body)
+	}
+
+	resolved := c.resolveComponent(ref, componentsRequestBodiesPrefix, "requestBodies")
+	if resolved == nil {
+		c.report(pointer, CodeUnconvertedRemoteRef, "request body "+unconvertibleRefMessage, ref)
+
+		return []map[string]interface{}{{"name": defaultRequestBodyName, "in": "body", "$ref": ref}}, nil
+	}
+
+	name := jsonpointer.Unescape(strings.TrimPrefix(ref, componentsRequestBodiesPrefix))
+	if paramName, isShared := c.requestBodyParams[name]; isShared {
+		content, _ := resolved["content"].(map[string]interface{})
+
+		return []map[string]interface{}{{"$ref": "#/parameters/" + jsonpointer.Escape(paramName)}}, sortedKeys(content)
+	}
+
+	// forms are inlined, as formData parameters
+	return c.requestBody(pointer, resolved)
+}
+
+// requestBody translates a request body into a body parameter, or formData parameters.
+// It yields parameters and the consumed media types.
+func (c *converter20) requestBody(pointer string, body map[string]interface{}) ([]map[string]interface{}, []string) {
+	content, _ := body["content"].(map[string]interface{})
+	mediaTypes := sortedKeys(content)
+	required, _ := body["required"].(bool)
+
+	var formTypes, otherTypes []string
+	for _, mt := range mediaTypes {
+		if IsMultipartMediaType(mt) || IsURLEncodedMediaType(mt) {
+			formTypes = append(formTypes, mt)
+		} else {
+			otherTypes = append(otherTypes, mt)
+		}
+	}
+
+	if len(formTypes) > 0 && len(otherTypes) > 0 {
+		c.report(pointer+"/content", CodeUnconvertibleContentTypes,
+			"forms (%s) and other media types (%s) may not be mixed: only forms are retained",
+			strings.Join(formTypes, ", "), strings.Join(otherTypes, ", "))
+	}
+
+	if len(formTypes) > 0 {
+		return c.formParameters(pointer+"/content", content, formTypes), formTypes
+	}
+
+	param := map[string]interface{}{"name": defaultRequestBodyName, "in": "body"}
+	for key, value := range body {
+		if key == "description" || strings.HasPrefix(key, "x-") {
+			param[key] = value
+		}
+	}
+
+	if name, ok := body["x-codegen-request-body-name"]; ok {
+		param["name"] = name
+	}
+
+	if required {
+		param["required"] = true
+	}
+
+	param["schema"] = c.contentSchema(pointer+"/content", content, mediaTypes)
+
+	return []map[string]interface{}{param}, mediaTypes
+}
+
+// formParameters translates the schema of a form into formData parameters
+func (c *converter20) formParameters(pointer string, content map[string]interface{}, formTypes []string) []map[string]interface{} {
+	preferred := c.preferredMediaType(pointer, content, formTypes)
+	media, _ := content[preferred].(map[string]interface{})
+	schema, _ := media["schema"].(map[string]interface{})
+	schemaPointer := pointer + "/" + jsonpointer.Escape(preferred) + "/schema"
+
+	if ref, isRef := schema["$ref"].(string); isRef {
+		schema = c.resolveComponent(ref, componentsSchemasPrefix, "schemas")
+		if schema == nil {
+			c.report(schemaPointer, CodeUnconvertedRemoteRef, "the schema of a form may not be a $ref: %s cannot be inlined", ref)
+
+			return nil
+		}
+	}
+
+	required := make(map[string]bool)
+	requiredList, _ := schema["required"].([]interface{})
+	for _, name := range requiredList {
+		if n, ok := name.(string); ok {
+			required[n] = true
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	params := make([]map[string]interface{}, 0, len(properties))
+	for _, name := range sortedKeys(properties) {
+		property, _ := properties[name].(map[string]interface{})
+		param := map[string]interface{}{"name": name, "in": "formData"}
+
+		if description, ok := property["description"]; ok {
+			param["description"] = description
+		}
+
+		if required[name] {
+			param["required"] = true
+		}
+
+		if property["type"] == "string" && (property["format"] == "binary" || property["format"] == "base64") {
+			param["type"] = "file"
+		} else {
+			c.simpleSchema(schemaPointer+"/properties/"+jsonpointer.Escape(name), property, param)
+			if param["type"] == "array" {
+				param["collectionFormat"] = "multi"
+			}
+		}
+
+		params = append(params, param)
+	}
+
+	return params
+}
+
+// contentSchema yields the translated schema of a content, for the preferred media type
+func (c *converter20) contentSchema(pointer string, content map[string]interface{}, mediaTypes []string) interface{} {
+	if len(mediaTypes) == 0 {
+		return map[string]interface{}{}
+	}
+
+	preferred := c.preferredMediaType(pointer, content, mediaTypes)
+	media, _ := content[preferred].(map[string]interface{})
+	schema, hasSchema := media["schema"]
+	if !hasSchema {
+		return map[string]interface{}{}
+	}
+
+	return c.schema(pointer+"/"+jsonpointer.Escape(preferred)+"/schema", schema)
+}
+
+// preferredMediaType picks the media type of a content, which schema is retained: a JSON media type if any.
+// It reports when the other media types have a different schema.
+func (c *converter20) preferredMediaType(pointer string, content map[string]interface{}, mediaTypes []string) string {
+	preferred := mediaTypes[0]
+	for _, mt := range mediaTypes {
+		if IsJSONMediaType(mt) {
+			preferred = mt
+
+			break
+		}
+	}
+
+	schemaOf := func(mt string) string {
+		media, _ := content[mt].(map[string]interface{})
+		buf, _ := json.Marshal(media["schema"])
+
+		return string(buf)
+	}
+
+	var different []string
+	for _, mt := range mediaTypes {
+		if mt != preferred && schemaOf(mt) != schemaOf(preferred) {
+			different = append(different, mt)
+		}
+	}
+
+	if len(different) > 0 {
+		c.report(pointer, CodeUnconvertibleContentTypes, "media types %s have different schemas: only the schema for %s is retained",
+			strings.Join(append([]string{preferred}, different...), ", "), preferred)
+	}
+
+	return preferred
+}
+
+// responseOrRef translates a response, or a $ref to a shared response. It yields the response and its media types.
+func (c *converter20) responseOrRef(pointer string, resp map[string]interface{}) (map[string]interface{}, []string) {
+	ref, isRef := resp["$ref"].(string)
+	if !isRef {
+		return c.response(pointer, resp)
+	}
+
+	resolved := c.resolveComponent(ref, componentsResponsesPrefix, "responses")
+	if resolved == nil {
+		c.report(pointer, CodeUnconvertedRemoteRef, "response "+unconvertibleRefMessage, ref)
+
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+
+	content, _ := resolved["content"].(map[string]interface{})
+
+	return map[string]interface{}{"$ref": "#/responses/" + strings.TrimPrefix(ref, componentsResponsesPrefix)}, sortedKeys(content)
+}
+
+func (c *converter20) response(pointer string, resp map[string]interface{}) (map[string]interface{}, []string) {
+	converted := map[string]interface{}{"description": resp["description"]}
+	for key, value := range resp {
+		if strings.HasPrefix(key, "x-") {
+			converted[key] = value
+		}
+	}
+
+	if _, hasLinks := resp["links"]; hasLinks {
+		c.report(pointer+"/links", CodeUnconvertibleLinks, "links have no equivalent")
+	}
+
+	headers, _ := resp["headers"].(map[string]interface{})
+	if len(headers) > 0 {
+		convertedHeaders := make(map[string]interface{}, len(headers))
+		for _, name := range sortedKeys(headers) {
+			headerPointer := pointer + "/headers/" + jsonpointer.Escape(name)
+			header, _ := headers[name].(map[string]interface{})
+			if ref, isRef := header["$ref"].(string); isRef {
+				header = c.resolveComponent(ref, componentsHeadersPrefix, "headers")
+				if header == nil {
+					c.report(headerPointer, CodeUnconvertedRemoteRef, "headers may not be $ref's: %s cannot be inlined", ref)
+
+					continue
+				}
+			}
+
+			convertedHeader := make(map[string]interface{})
+			for key, value := range header {
+				if key == "description" || strings.HasPrefix(key, "x-") {
+					convertedHeader[key] = value
+				}
+			}
+			c.simpleSchema(headerPointer+"/schema", header["schema"], convertedHeader)
+			convertedHeaders[name] = convertedHeader
+		}
+		converted["headers"] = convertedHeaders
+	}
+
+	content, _ := resp["content"].(map[string]interface{})
+	mediaTypes := sortedKeys(content)
+	if len(mediaTypes) == 0 {
+		return converted, nil
+	}
+
+	if schema := c.contentSchema(pointer+"/content", content, mediaTypes); len(schema.(map[string]interface{})) > 0 {
+		converted["schema"] = schema
+	}
+
+	examples := make(map[string]interface{})
+	for _, mt := range mediaTypes {
+		media, _ := content[mt].(map[string]interface{})
+		if example, ok := media["example"]; ok {
+			examples[mt] = example
+		}
+	}
+
+	if len(examples) > 0 {
+		converted["examples"] = examples
+	}
+
+	return converted, mediaTypes
+}
+
+// schema translates a schema decoded as generic JSON, in place
+func (c *converter20) schema(pointer string, raw interface{}) interface{} {
+	_ = walkJSONSchema(raw, pointer, func(schema map[string]interface{}, pointer string) error {
+		if ref, hasRef := schema["$ref"].(string); hasRef {
+			if strings.HasPrefix(ref, componentsSchemasPrefix) {
+				schema["$ref"] = definitionsPrefix + strings.TrimPrefix(ref, componentsSchemasPrefix)
+			} else {
+				c.report(pointer, CodeUnconvertedRemoteRef, "schema "+unconvertibleRefMessage, ref)
+			}
+		}
+
+		if nullable, isBool := schema["nullable"].(bool); isBool {
+			delete(schema, "nullable")
+			if nullable {
+				schema["x-nullable"] = true
+			}
+		}
+
+		if discriminator, isMap := schema["discriminator"].(map[string]interface{}); isMap {
+			schema["discriminator"] = discriminator["propertyName"]
+			if _, hasMapping := discriminator["mapping"]; hasMapping {
+				c.report(pointer+"/discriminator", CodeUnconvertibleDiscriminator,
+					"the mapping of a discriminator has no equivalent: discriminator values must be definition names")
+			}
+		}
+
+		for _, keyword := range []string{"oneOf", "anyOf"} {
+			if _, isComposed := schema[keyword]; isComposed {
+				c.report(pointer+"/"+keyword, CodeUnconvertibleComposition, "%s has no equivalent: the alternatives are dropped", keyword)
+				delete(schema, keyword)
+			}
+		}
+
+		return nil
+	})
+
+	return raw
+}
@@ -0,0 +1,138 @@
+package analysis
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert30To20(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should convert back a converted swagger 2.0 spec", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, filepath.Join("fixtures", "convert30.yml"))
+		doc, _, err := Convert20To30(sp)
+		require.NoError(t, err)
+
+		converted, findings, err := Convert30To20(doc)
+		require.NoError(t, err)
+		assert.Empty(t, findings)
+
+		assert.Equal(t, sp.Host, converted.Host)
+		assert.Equal(t, sp.BasePath, converted.BasePath)
+		assert.Equal(t, sp.Schemes, converted.Schemes)
+		assert.JSONEq(t, antest.AsJSON(t, sp.Definitions["error"]), antest.AsJSON(t, converted.Definitions["error"]))
+		assert.JSONEq(t, antest.AsJSON(t, sp.Responses), antest.AsJSON(t, converted.Responses))
+		assert.JSONEq(t, antest.AsJSON(t, sp.SecurityDefinitions), antest.AsJSON(t, converted.SecurityDefinitions))
+		assert.JSONEq(t, antest.AsJSON(t, sp.Paths.Paths["/pets"].Get.Parameters[0]),
+			antest.AsJSON(t, converted.Paths.Paths["/pets"].Get.Parameters[0]))
+		assert.JSONEq(t, antest.AsJSON(t, sp.Paths.Paths["/pets"].Get.Responses),
+			antest.AsJSON(t, converted.Paths.Paths["/pets"].Get.Responses))
+
+		post := converted.Paths.Paths["/pets"].Post
+		assert.ElementsMatch(t, []string{"application/json", "application/xml"}, post.Consumes)
+		require.Len(t, post.Parameters, 1)
+		assert.Equal(t, "body", post.Parameters[0].In)
+
+		upload := converted.Paths.Paths["/pets/{id}/photo"].Post
+		assert.Equal(t, []string{"multipart/form-data"}, upload.Consumes)
+		require.Len(t, upload.Parameters, 3)
+		assert.Equal(t, "file", upload.Parameters[2].Name)
+		assert.Equal(t, "file", upload.Parameters[2].Type)
+		assert.True(t, upload.Parameters[2].Required)
+	})
+
+	t.Run("should report what cannot be translated", func(t *testing.T) {
+		t.Parallel()
+
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(`{
+  "openapi": "3.0.3",
+  "info": {"title": "api", "version": "1.0"},
+  "servers": [
+    {"url": "https://{region}.example.com/api", "variables": {"region": {"default": "eu"}}},
+    {"url": "https://other.example.com/api"}
+  ],
+  "paths": {
+    "/items": {
+      "get": {
+        "parameters": [
+          {"name": "session", "in": "cookie", "schema": {"type": "string"}},
+          {"name": "filter", "in": "query", "style": "deepObject", "schema": {"type": "array", "items": {"type": "string"}}}
+        ],
+        "responses": {
+          "200": {
+            "description": "items",
+            "links": {"next": {"operationId": "getItems"}},
+            "content": {
+              "application/json": {"schema": {"oneOf": [{"$ref": "#/components/schemas/item"}, {"type": "string"}]}},
+              "text/plain": {"schema": {"type": "string"}}
+            }
+          }
+        },
+        "callbacks": {"onEvent": {}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "item": {
+        "type": "object",
+        "discriminator": {"propertyName": "kind", "mapping": {"a": "#/components/schemas/item"}},
+        "properties": {"kind": {"type": "string", "nullable": true}}
+      }
+    },
+    "securitySchemes": {
+      "bearer": {"type": "http", "scheme": "bearer"}
+    }
+  }
+}`), &doc))
+
+		converted, findings, err := Convert30To20(doc)
+		require.NoError(t, err)
+
+		assert.Equal(t, "eu.example.com", converted.Host)
+		assert.Equal(t, "/api", converted.BasePath)
+		assert.Empty(t, converted.SecurityDefinitions)
+		assert.JSONEq(t, `{
+  "type": "object",
+  "discriminator": "kind",
+  "properties": {"kind": {"type": "string", "x-nullable": true}}
+}`, antest.AsJSON(t, converted.Definitions["item"]))
+
+		get := converted.Paths.Paths["/items"].Get
+		require.Len(t, get.Parameters, 1)
+		assert.Equal(t, "filter", get.Parameters[0].Name)
+		assert.ElementsMatch(t, []string{"application/json", "text/plain"}, get.Produces)
+
+		codes := make(map[string]string, len(findings))
+		for _, finding := range findings {
+			codes[finding.Pointer] = finding.Code
+		}
+
+		assert.Equal(t, map[string]string{
+			"#/servers/1": CodeUnconvertibleServers,
+			"#/components/schemas/item/discriminator":                                  CodeUnconvertibleDiscriminator,
+			"#/components/securitySchemes/bearer":                                      CodeUnconvertibleSecurityScheme,
+			"#/paths/~1items/get/callbacks":                                            CodeUnconvertibleCallbacks,
+			"#/paths/~1items/get/parameters/0":                                         CodeUnconvertibleParameter,
+			"#/paths/~1items/get/parameters/1":                                         CodeUnconvertibleParameter,
+			"#/paths/~1items/get/responses/200/links":                                  CodeUnconvertibleLinks,
+			"#/paths/~1items/get/responses/200/content":                                CodeUnconvertibleContentTypes,
+			"#/paths/~1items/get/responses/200/content/application~1json/schema/oneOf": CodeUnconvertibleComposition,
+		}, codes)
+	})
+
+	t.Run("should reject documents which are not OpenAPI 3.0", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := Convert30To20(map[string]interface{}{"swagger": "2.0"})
+		require.ErrorIs(t, err, ErrUnsupportedDocument)
+	})
+}
@@ -0,0 +1,783 @@
+package analysis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// Codes for the findings reporting what Convert20To30 cannot translate
+const (
+	CodeUnconvertibleTuple            = "unconvertible-tuple"
+	CodeUnconvertibleCollectionFormat = "unconvertible-collection-format"
+	CodeUnconvertibleSchemes          = "unconvertible-schemes"
+	CodeUnconvertedRemoteRef          = "unconverted-remote-ref"
+)
+
+const openAPI30Version = "3.0.3"
+
+// default media type of request and response bodies, when a spec declares none
+const defaultMediaType = "application/json"
+
+// keys of a swagger 2.0 parameter or header which describe its schema in OpenAPI 3.0
+var simpleSchemaKeys = []string{
+	"type", "format", "items", "default", "enum", "multipleOf",
+	"maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems",
+}
+
+// oauth2 flows of OpenAPI 3.0, for the flows of swagger 2.0
+var oauth2Flows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+// Convert20To30 translates a swagger 2.0 spec into an OpenAPI 3.0 document, as generic JSON:
+//   - host, basePath and schemes become servers;
+//   - body and formData parameters become request bodies, with a content entry for each consumed media type;
+//   - response schemas and examples become content entries, for each produced media type;
+//   - definitions, shared parameters, shared responses and security definitions move to components
+//     (shared body parameters become components/requestBodies, shared formData parameters are inlined);
+//   - parameters and headers get a schema, and collection formats become styles;
+//   - schema keywords specific to swagger 2.0 (x-nullable, discriminator, type: file) are translated.
+//
+// Findings report what cannot be translated (e.g. tuples, or tsv collection formats), sorted by pointer.
+// The spec is not modified.
+func Convert20To30(sp *spec.Swagger) (map[string]interface{}, []Finding, error) {
+	c := &converter30{an: newSpec(sp), sp: sp}
+
+	doc, err := c.convert()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sortFindings(c.findings)
+
+	return doc, c.findings, nil
+}
+
+type converter30 struct {
+	an       *Spec
+	sp       *spec.Swagger
+	findings []Finding
+}
+
+func (c *converter30) report(pointer, code, format string, args ...interface{}) {
+	c.findings = append(c.findings, Finding{Pointer: pointer, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *converter30) convert() (map[string]interface{}, error) {
+	doc := map[string]interface{}{"openapi": openAPI30Version}
+
+	for key, value := range c.sp.Extensions {
+		doc[key] = value
+	}
+
+	for key, value := range map[string]interface{}{
+		"info":         c.sp.Info,
+		"tags":         c.sp.Tags,
+		"externalDocs": c.sp.ExternalDocs,
+		"security":     c.sp.Security,
+	} {
+		converted, err := genericJSON(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if converted != nil {
+			doc[key] = converted
+		}
+	}
+
+	if servers := c.servers(c.sp.Schemes); len(servers) > 0 {
+		doc["servers"] = servers
+	}
+
+	paths, err := c.paths()
+	if err != nil {
+		return nil, err
+	}
+	doc["paths"] = paths
+
+	components, err := c.components()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(components) > 0 {
+		doc["components"] = components
+	}
+
+	return doc, nil
+}
+
+// servers translates the host, base path and schemes of the spec
+func (c *converter30) servers(schemes []string) []interface{} {
+	if c.sp.Host == "" && c.sp.BasePath == "" {
+		return nil
+	}
+
+	if c.sp.Host == "" {
+		return []interface{}{map[string]interface{}{"url": c.sp.BasePath}}
+	}
+
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+
+	servers := make([]interface{}, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]interface{}{"url": scheme + "://" + c.sp.Host + c.sp.BasePath})
+	}
+
+	return servers
+}
+
+func (c *converter30) components() (map[string]interface{}, error) {
+	components := make(map[string]interface{})
+
+	schemas := make(map[string]interface{}, len(c.sp.Definitions))
+	for _, name := range sortedKeys(c.sp.Definitions) {
+		schema := c.sp.Definitions[name]
+		converted, err := c.schema(definitionsPrefix+jsonpointer.Escape(name), &schema)
+		if err != nil {
+			return nil, err
+		}
+		schemas[name] = converted
+	}
+
+	parameters := make(map[string]interface{})
+	requestBodies := make(map[string]interface{})
+	for _, name := range sortedKeys(c.sp.Parameters) {
+		param := c.sp.Parameters[name]
+		pointer := "#/parameters/" + jsonpointer.Escape(name)
+
+		switch param.In {
+		case "body":
+			body, err := c.requestBody(pointer, []spec.Parameter{param}, c.sp.Consumes)
+			if err != nil {
+				return nil, err
+			}
+			requestBodies[name] = body
+		case "formData":
+			// inlined in the request bodies of operations
+		default:
+			converted, err := c.parameter(pointer, param)
+			if err != nil {
+				return nil, err
+			}
+			parameters[name] = converted
+		}
+	}
+
+	responses := make(map[string]interface{}, len(c.sp.Responses))
+	for _, name := range sortedKeys(c.sp.Responses) {
+		converted, err := c.response("#/responses/"+jsonpointer.Escape(name), c.sp.Responses[name], c.sp.Produces)
+		if err != nil {
+			return nil, err
+		}
+		responses[name] = converted
+	}
+
+	securitySchemes := make(map[string]interface{}, len(c.sp.SecurityDefinitions))
+	for _, name := range sortedKeys(c.sp.SecurityDefinitions) {
+		if scheme := c.sp.SecurityDefinitions[name]; scheme != nil {
+			securitySchemes[name] = c.securityScheme(scheme)
+		}
+	}
+
+	for section, entries := range map[string]map[string]interface{}{
+		"schemas":         schemas,
+		"parameters":      parameters,
+		"requestBodies":   requestBodies,
+		"responses":       responses,
+		"securitySchemes": securitySchemes,
+	} {
+		if len(entries) > 0 {
+			components[section] = entries
+		}
+	}
+
+	return components, nil
+}
+
+func (c *converter30) securityScheme(scheme *spec.SecurityScheme) map[string]interface{} {
+	converted := make(map[string]interface{})
+	for key, value := range scheme.Extensions {
+		converted[key] = value
+	}
+
+	if scheme.Description != "" {
+		converted["description"] = scheme.Description
+	}
+
+	switch scheme.Type {
+	case "basic":
+		converted["type"] = "http"
+		converted["scheme"] = "basic"
+	case "apiKey":
+		converted["type"] = "apiKey"
+		converted["name"] = scheme.Name
+		converted["in"] = scheme.In
+	case "oauth2":
+		scopes := make(map[string]interface{}, len(scheme.Scopes))
+		for scope, description := range scheme.Scopes {
+			scopes[scope] = description
+		}
+
+		flow := map[string]interface{}{"scopes": scopes}
+		if scheme.AuthorizationURL != "" {
+			flow["authorizationUrl"] = scheme.AuthorizationURL
+		}
+		if scheme.TokenURL != "" {
+			flow["tokenUrl"] = scheme.TokenURL
+		}
+
+		converted["type"] = "oauth2"
+		converted["flows"] = map[string]interface{}{oauth2Flows[scheme.Flow]: flow}
+	default:
+		converted["type"] = scheme.Type
+	}
+
+	return converted
+}
+
+func (c *converter30) paths() (map[string]interface{}, error) {
+	paths := make(map[string]interface{})
+	if c.sp.Paths == nil {
+		return paths, nil
+	}
+
+	for key, value := range c.sp.Paths.Extensions {
+		paths[key] = value
+	}
+
+	for _, pth := range sortedKeys(c.sp.Paths.Paths) {
+		pathItem := c.sp.Paths.Paths[pth]
+		pointer := "#/paths/" + jsonpointer.Escape(pth)
+		converted := make(map[string]interface{})
+
+		for key, value := range pathItem.Extensions {
+			converted[key] = value
+		}
+
+		if ref := pathItem.Ref.String(); ref != "" {
+			c.report(pointer, CodeUnconvertedRemoteRef, "path item $ref %s is retained, but the document it refers to is not converted", ref)
+			converted["$ref"] = ref
+		}
+
+		// parameters of the path item which are not request bodies remain on the path item
+		var shared []interface{}
+		for i, param := range pathItem.Parameters {
+			if in := c.parameterLocation(param); in == "body" || in == "formData" {
+				continue
+			}
+
+			p, err := c.parameterOrRef(fmt.Sprintf("%s/parameters/%d", pointer, i), param)
+			if err != nil {
+				return nil, err
+			}
+			shared = append(shared, p)
+		}
+
+		if len(shared) > 0 {
+			converted["parameters"] = shared
+		}
+
+		for _, method := range sortedOperationMethods(&pathItem) {
+			op := operationOf(&pathItem, method)
+			convertedOp, err := c.operation(pointer+"/"+strings.ToLower(method), &pathItem, op)
+			if err != nil {
+				return nil, err
+			}
+
+			converted[strings.ToLower(method)] = convertedOp
+		}
+
+		paths[pth] = converted
+	}
+
+	return paths, nil
+}
+
+func (c *converter30) operation(pointer string, pathItem *spec.PathItem, op *spec.Operation) (map[string]interface{}, error) {
+	raw, err := genericJSON(op)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, _ := raw.(map[string]interface{})
+	if converted == nil {
+		converted = make(map[string]interface{})
+	}
+
+	for _, key := range []string{"consumes", "produces", "schemes", "parameters", "responses"} {
+		delete(converted, key)
+	}
+
+	if len(op.Schemes) > 0 {
+		c.report(pointer, CodeUnconvertibleSchemes, "operation schemes %s cannot be translated: servers are declared for the whole document", strings.Join(op.Schemes, ", "))
+	}
+
+	// body and formData parameters from the path item, overridden by those of the operation
+	var bodyParams []spec.Parameter
+	bodyIndex := make(map[string]int)
+	addBodyParam := func(param spec.Parameter) {
+		key := param.In + "#" + param.Name
+		if i, exists := bodyIndex[key]; exists {
+			bodyParams[i] = param
+
+			return
+		}
+
+		bodyIndex[key] = len(bodyParams)
+		bodyParams = append(bodyParams, param)
+	}
+
+	var bodyRef string
+	consumes := c.an.ConsumesFor(op)
+	for _, params := range [][]spec.Parameter{pathItem.Parameters, op.Parameters} {
+		for _, param := range params {
+			switch c.parameterLocation(param) {
+			case "body":
+				// a shared body parameter is referred to as a shared request body, unless the operation
+				// consumes other media types than the shared request body
+				if shared, isShared := c.sharedParameterName(param); isShared && sameMediaTypes(consumes, c.an.ConsumesFor(&spec.Operation{})) {
+					bodyRef = "#/components/requestBodies/" + jsonpointer.Escape(shared)
+					bodyParams = nil
+					bodyIndex = make(map[string]int)
+
+					continue
+				}
+
+				bodyRef = ""
+				addBodyParam(c.resolveParameter(param))
+			case "formData":
+				addBodyParam(c.resolveParameter(param))
+			}
+		}
+	}
+
+	var parameters []interface{}
+	for i, param := range op.Parameters {
+		if in := c.parameterLocation(param); in == "body" || in == "formData" {
+			continue
+		}
+
+		p, err := c.parameterOrRef(fmt.Sprintf("%s/parameters/%d", pointer, i), param)
+		if err != nil {
+			return nil, err
+		}
+		parameters = append(parameters, p)
+	}
+
+	if len(parameters) > 0 {
+		converted["parameters"] = parameters
+	}
+
+	switch {
+	case bodyRef != "":
+		converted["requestBody"] = map[string]interface{}{"$ref": bodyRef}
+	case len(bodyParams) > 0:
+		body, err := c.requestBody(pointer, bodyParams, consumes)
+		if err != nil {
+			return nil, err
+		}
+		converted["requestBody"] = body
+	}
+
+	responses := make(map[string]interface{})
+	if op.Responses != nil {
+		produces := c.an.ProducesFor(op)
+		if op.Responses.Default != nil {
+			resp, err := c.responseOrRef(pointer+"/responses/default", *op.Responses.Default, produces)
+			if err != nil {
+				return nil, err
+			}
+			responses["default"] = resp
+		}
+
+		for _, code := range sortedStatusCodes(op.Responses.StatusCodeResponses) {
+			status := strconv.Itoa(code)
+			resp, err := c.responseOrRef(pointer+"/responses/"+status, op.Responses.StatusCodeResponses[code], produces)
+			if err != nil {
+				return nil, err
+			}
+			responses[status] = resp
+		}
+
+		for key, value := range op.Responses.Extensions {
+			responses[key] = value
+		}
+	}
+	converted["responses"] = responses
+
+	return converted, nil
+}
+
+// parameterLocation tells where a parameter, or the shared parameter it refers to, is located
+func (c *converter30) parameterLocation(param spec.Parameter) string {
+	return c.resolveParameter(param).In
+}
+
+// resolveParameter follows a $ref to a shared parameter
+func (c *converter30) resolveParameter(param spec.Parameter) spec.Parameter {
+	if name, isShared := c.sharedParameterName(param); isShared {
+		if shared, ok := c.sp.Parameters[name]; ok {
+			return shared
+		}
+	}
+
+	return param
+}
+
+// sharedParameterName yields the name of the shared parameter a parameter refers to
+func (c *converter30) sharedParameterName(param spec.Parameter) (string, bool) {
+	ref := param.Ref.String()
+	if !strings.HasPrefix(ref, "#/parameters/") {
+		return "", false
+	}
+
+	return jsonpointer.Unescape(strings.TrimPrefix(ref, "#/parameters/")), true
+}
+
+func (c *converter30) parameterOrRef(pointer string, param spec.Parameter) (interface{}, error) {
+	if name, isShared := c.sharedParameterName(param); isShared {
+		return map[string]interface{}{"$ref": "#/components/parameters/" + jsonpointer.Escape(name)}, nil
+	}
+
+	if ref := param.Ref.String(); ref != "" {
+		c.report(pointer, CodeUnconvertedRemoteRef, "parameter $ref %s is retained, but the document it refers to is not converted", ref)
+
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+
+	return c.parameter(pointer, param)
+}
+
+// parameter translates a parameter which is neither in body nor in formData
+func (c *converter30) parameter(pointer string, param spec.Parameter) (map[string]interface{}, error) {
+	converted, err := c.simpleSchemaHolder(pointer, param)
+	if err != nil {
+		return nil, err
+	}
+
+	if param.CollectionFormat != "" && param.Type == "array" {
+		c.collectionFormat(pointer, param.In, param.CollectionFormat, converted)
+	}
+	delete(converted, "collectionFormat")
+
+	return converted, nil
+}
+
+// collectionFormat translates the collection format of an array parameter into a style
+func (c *converter30) collectionFormat(pointer, in, format string, converted map[string]interface{}) {
+	switch format {
+	case "csv":
+		if in == "query" || in == "formData" {
+			converted["style"] = "form"
+			converted["explode"] = false
+		}
+	case "multi":
+		converted["style"] = "form"
+		converted["explode"] = true
+	case "ssv":
+		converted["style"] = "spaceDelimited"
+	case "pipes":
+		converted["style"] = "pipeDelimited"
+	default:
+		c.report(pointer, CodeUnconvertibleCollectionFormat, "collection format %q has no equivalent style", format)
+	}
+}
+
+// simpleSchemaHolder translates a parameter or a header, by moving the keywords describing its value to a schema
+func (c *converter30) simpleSchemaHolder(pointer string, holder interface{}) (map[string]interface{}, error) {
+	raw, err := genericJSON(holder)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, _ := raw.(map[string]interface{})
+	if converted == nil {
+		converted = make(map[string]interface{})
+	}
+
+	schema := make(map[string]interface{})
+	for _, key := range simpleSchemaKeys {
+		if value, ok := converted[key]; ok {
+			schema[key] = value
+			delete(converted, key)
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		c.simpleItems(pointer+"/items", items)
+	}
+
+	if len(schema) > 0 {
+		translated, err := c.schemaJSON(pointer, schema)
+		if err != nil {
+			return nil, err
+		}
+		converted["schema"] = translated
+	}
+
+	return converted, nil
+}
+
+// simpleItems drops the collection formats of nested items, which have no equivalent in OpenAPI 3.0
+func (c *converter30) simpleItems(pointer string, items map[string]interface{}) {
+	if format, ok := items["collectionFormat"].(string); ok {
+		delete(items, "collectionFormat")
+		if format != "csv" {
+			c.report(pointer, CodeUnconvertibleCollectionFormat, "collection format %q of nested items has no equivalent", format)
+		}
+	}
+
+	if nested, ok := items["items"].(map[string]interface{}); ok {
+		c.simpleItems(pointer+"/items", nested)
+	}
+}
+
+// requestBody translates a body parameter, or formData parameters, into a request body
+func (c *converter30) requestBody(pointer string, params []spec.Parameter, consumes []string) (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+
+	if len(params) == 1 && params[0].In == "body" {
+		param := params[0]
+		for key, value := range param.Extensions {
+			body[key] = value
+		}
+
+		if param.Description != "" {
+			body["description"] = param.Description
+		}
+
+		if param.Required {
+			body["required"] = true
+		}
+
+		var schema interface{} = map[string]interface{}{}
+		if param.Schema != nil {
+			var err error
+			if schema, err = c.schema(pointer+"/schema", param.Schema); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(consumes) == 0 {
+			consumes = []string{defaultMediaType}
+		}
+
+		body["content"] = contentFor(consumes, schema)
+
+		return body, nil
+	}
+
+	// formData parameters become the properties of an object
+	properties := make(map[string]interface{}, len(params))
+	var required []interface{}
+	hasFile := false
+	for _, param := range params {
+		converted, err := c.simpleSchemaHolder(pointer, param)
+		if err != nil {
+			return nil, err
+		}
+
+		property, _ := converted["schema"].(map[string]interface{})
+		if property == nil {
+			property = make(map[string]interface{})
+		}
+
+		if param.Description != "" {
+			property["description"] = param.Description
+		}
+
+		hasFile = hasFile || param.Type == "file"
+		properties[param.Name] = property
+
+		if param.Required {
+			required = append(required, param.Name)
+			body["required"] = true
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	var formTypes []string
+	for _, mt := range consumes {
+		if IsMultipartMediaType(mt) || IsURLEncodedMediaType(mt) {
+			formTypes = append(formTypes, mt)
+		}
+	}
+
+	if len(formTypes) == 0 {
+		if hasFile {
+			formTypes = []string{"multipart/form-data"}
+		} else {
+			formTypes = []string{"application/x-www-form-urlencoded"}
+		}
+	}
+
+	body["content"] = contentFor(formTypes, schema)
+
+	return body, nil
+}
+
+// sameMediaTypes tells if two lists of media types hold the same media types, in any order
+func sameMediaTypes(left, right []string) bool {
+	if len(left) != len(right) {
+		return false
+	}
+
+	index := make(map[string]struct{}, len(left))
+	for _, mt := range left {
+		index[mt] = struct{}{}
+	}
+
+	for _, mt := range right {
+		if _, ok := index[mt]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// contentFor builds a content map, with the same schema for all media types
+func contentFor(mediaTypes []string, schema interface{}) map[string]interface{} {
+	content := make(map[string]interface{}, len(mediaTypes))
+	for _, mt := range mediaTypes {
+		content[mt] = map[string]interface{}{"schema": schema}
+	}
+
+	return content
+}
+
+func (c *converter30) responseOrRef(pointer string, resp spec.Response, produces []string) (interface{}, error) {
+	ref := resp.Ref.String()
+	if strings.HasPrefix(ref, "#/responses/") {
+		return map[string]interface{}{"$ref": "#/components/responses/" + strings.TrimPrefix(ref, "#/responses/")}, nil
+	}
+
+	if ref != "" {
+		c.report(pointer, CodeUnconvertedRemoteRef, "response $ref %s is retained, but the document it refers to is not converted", ref)
+
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+
+	return c.response(pointer, resp, produces)
+}
+
+func (c *converter30) response(pointer string, resp spec.Response, produces []string) (map[string]interface{}, error) {
+	converted := map[string]interface{}{"description": resp.Description}
+	for key, value := range resp.Extensions {
+		converted[key] = value
+	}
+
+	if len(resp.Headers) > 0 {
+		headers := make(map[string]interface{}, len(resp.Headers))
+		for _, name := range sortedKeys(resp.Headers) {
+			headerPointer := pointer + "/headers/" + jsonpointer.Escape(name)
+			header, err := c.simpleSchemaHolder(headerPointer, resp.Headers[name])
+			if err != nil {
+				return nil, err
+			}
+
+			if format, ok := header["collectionFormat"].(string); ok {
+				delete(header, "collectionFormat")
+				if format != "csv" {
+					c.report(headerPointer, CodeUnconvertibleCollectionFormat, "collection format %q of a header has no equivalent style", format)
+				}
+			}
+			headers[name] = header
+		}
+		converted["headers"] = headers
+	}
+
+	if resp.Schema == nil && len(resp.Examples) == 0 {
+		return converted, nil
+	}
+
+	if len(produces) == 0 {
+		produces = []string{defaultMediaType}
+	}
+
+	content := make(map[string]interface{}, len(produces))
+	if resp.Schema != nil {
+		schema, err := c.schema(pointer+"/schema", resp.Schema)
+		if err != nil {
+			return nil, err
+		}
+		content = contentFor(produces, schema)
+	}
+
+	for _, mt := range sortedKeys(resp.Examples) {
+		media, _ := content[mt].(map[string]interface{})
+		if media == nil {
+			media = make(map[string]interface{})
+			content[mt] = media
+		}
+		media["example"] = resp.Examples[mt]
+	}
+	converted["content"] = content
+
+	return converted, nil
+}
+
+// schema translates a schema
+func (c *converter30) schema(pointer string, schema *spec.Schema) (interface{}, error) {
+	raw, err := genericJSON(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.schemaJSON(pointer, raw)
+}
+
+// schemaJSON translates a schema decoded as generic JSON, in place
+func (c *converter30) schemaJSON(pointer string, raw interface{}) (interface{}, error) {
+	err := walkJSONSchema(raw, pointer, func(schema map[string]interface{}, pointer string) error {
+		if ref, hasRef := schema["$ref"].(string); hasRef {
+			if strings.HasPrefix(ref, definitionsPrefix) {
+				schema["$ref"] = "#/components/schemas/" + strings.TrimPrefix(ref, definitionsPrefix)
+			} else {
+				c.report(pointer, CodeUnconvertedRemoteRef, "schema $ref %s is retained, but the document it refers to is not converted", ref)
+			}
+		}
+
+		if nullable, isBool := schema["x-nullable"].(bool); isBool {
+			delete(schema, "x-nullable")
+			if nullable {
+				schema["nullable"] = true
+			}
+		}
+
+		if discriminator, isString := schema["discriminator"].(string); isString {
+			schema["discriminator"] = map[string]interface{}{"propertyName": discriminator}
+		}
+
+		if tpe, isString := schema["type"].(string); isString && tpe == "file" {
+			schema["type"] = "string"
+			schema["format"] = "binary"
+		}
+
+		if _, isTuple := schema["items"].([]interface{}); isTuple {
+			c.report(pointer, CodeUnconvertibleTuple, "OpenAPI 3.0 does not support tuples: items are not constrained")
+			schema["items"] = map[string]interface{}{}
+			delete(schema, "additionalItems")
+		}
+
+		return nil
+	})
+
+	return raw, err
+}
@@ -0,0 +1,161 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert20To30(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "convert30.yml"))
+	original := antest.AsJSON(t, sp)
+
+	doc, findings, err := Convert20To30(sp)
+	require.NoError(t, err)
+
+	assert.JSONEqf(t, original, antest.AsJSON(t, sp), "expected the spec to remain unchanged")
+
+	assert.Equal(t, "3.0.3", doc["openapi"])
+
+	t.Run("should translate host, basePath and schemes to servers", func(t *testing.T) {
+		assert.JSONEq(t, `[
+  {"url": "https://petstore.example.com/v1"},
+  {"url": "http://petstore.example.com/v1"}
+]`, antest.AsJSON(t, doc["servers"]))
+	})
+
+	t.Run("should move definitions and shared objects to components", func(t *testing.T) {
+		assert.JSONEq(t, `{
+  "schemas": {
+    "error": {"type": "object", "properties": {"message": {"type": "string"}}},
+    "pet": {
+      "type": "object",
+      "discriminator": {"propertyName": "kind"},
+      "required": ["kind"],
+      "properties": {
+        "kind": {"type": "string"},
+        "name": {"type": "string", "nullable": true},
+        "position": {"type": "array", "items": {}}
+      }
+    }
+  },
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}}
+  },
+  "requestBodies": {
+    "petBody": {
+      "required": true,
+      "content": {"application/json": {"schema": {"$ref": "#/components/schemas/pet"}}}
+    }
+  },
+  "responses": {
+    "notFound": {
+      "description": "not found",
+      "content": {"application/json": {"schema": {"$ref": "#/components/schemas/error"}}}
+    }
+  },
+  "securitySchemes": {
+    "basic": {"type": "http", "scheme": "basic"},
+    "key": {"type": "apiKey", "name": "X-API-Key", "in": "header"},
+    "oauth": {
+      "type": "oauth2",
+      "flows": {
+        "authorizationCode": {
+          "authorizationUrl": "https://example.com/authorize",
+          "tokenUrl": "https://example.com/token",
+          "scopes": {"read": "read access"}
+        }
+      }
+    }
+  }
+}`, antest.AsJSON(t, doc["components"]))
+	})
+
+	t.Run("should translate parameters and responses", func(t *testing.T) {
+		assert.JSONEq(t, `{
+  "parameters": [{"$ref": "#/components/parameters/limit"}],
+  "get": {
+    "operationId": "listPets",
+    "parameters": [
+      {"name": "tags", "in": "query", "style": "pipeDelimited", "schema": {"type": "array", "items": {"type": "string"}}},
+      {"name": "ids", "in": "query", "schema": {"type": "array", "items": {"type": "integer"}}}
+    ],
+    "responses": {
+      "200": {
+        "description": "pets",
+        "headers": {"X-Rate-Limit": {"schema": {"type": "integer", "format": "int32"}}},
+        "content": {
+          "application/json": {
+            "schema": {"type": "array", "items": {"$ref": "#/components/schemas/pet"}},
+            "example": [{"name": "rex"}]
+          }
+        }
+      }
+    }
+  },
+  "post": {
+    "operationId": "createPet",
+    "requestBody": {
+      "required": true,
+      "content": {
+        "application/json": {"schema": {"$ref": "#/components/schemas/pet"}},
+        "application/xml": {"schema": {"$ref": "#/components/schemas/pet"}}
+      }
+    },
+    "responses": {
+      "201": {"description": "created"},
+      "default": {"$ref": "#/components/responses/notFound"}
+    }
+  }
+}`, antest.AsJSON(t, doc["paths"].(map[string]interface{})["/pets"]))
+	})
+
+	t.Run("should translate formData parameters to a request body", func(t *testing.T) {
+		assert.JSONEq(t, `{
+  "required": true,
+  "content": {
+    "multipart/form-data": {
+      "schema": {
+        "type": "object",
+        "required": ["file"],
+        "properties": {
+          "caption": {"type": "string"},
+          "file": {"type": "string", "format": "binary"}
+        }
+      }
+    }
+  }
+}`, antest.AsJSON(t, doc["paths"].(map[string]interface{})["/pets/{id}/photo"].(map[string]interface{})["post"].(map[string]interface{})["requestBody"]))
+	})
+
+	t.Run("should report what cannot be translated", func(t *testing.T) {
+		codes := make(map[string]string, len(findings))
+		for _, finding := range findings {
+			codes[finding.Pointer] = finding.Code
+		}
+
+		assert.Equal(t, map[string]string{
+			"#/definitions/pet/properties/position": CodeUnconvertibleTuple,
+			"#/paths/~1pets/get/parameters/1":       CodeUnconvertibleCollectionFormat,
+			"#/paths/~1pets~1{id}~1photo/post":      CodeUnconvertibleSchemes,
+		}, codes)
+	})
+}
+
+func TestConvert20To30_Defaults(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "content_types.yml"))
+	sp.Host = ""
+	sp.BasePath = "/api"
+
+	doc, _, err := Convert20To30(sp)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[{"url": "/api"}]`, antest.AsJSON(t, doc["servers"]))
+}
@@ -0,0 +1,87 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/go-openapi/spec"
+)
+
+// CodeInconsistentDefault is the code for findings about default values which their schema rejects
+const CodeInconsistentDefault = "inconsistent-default"
+
+// DefaultValue is a default value declared in a specification
+type DefaultValue struct {
+	Value interface{}
+
+	// Schema is the schema owning the default value.
+	//
+	// Parameters, headers and items are represented by an equivalent schema, with their type, format and validations.
+	Schema *spec.Schema
+}
+
+type defaultAnalysis struct {
+	allDefaults map[string]DefaultValue
+}
+
+func (d *defaultAnalysis) addDefault(key string, value interface{}, schema *spec.Schema) {
+	d.allDefaults["#"+key] = DefaultValue{Value: value, Schema: schema}
+}
+
+func (d *defaultAnalysis) addSimpleDefault(key string, simple spec.SimpleSchema, validations spec.CommonValidations) {
+	d.addDefault(key, simple.Default, simpleSchemaOf(simple, validations))
+}
+
+// AllDefaults returns all the default values found in the spec, keyed by the pointer to their owner
+// (e.g. "#/definitions/pet/properties/status").
+//
+// The map is cloned to avoid accidental changes
+func (s *Spec) AllDefaults() map[string]DefaultValue {
+	res := make(map[string]DefaultValue, len(s.defaults.allDefaults))
+	for k, v := range s.defaults.allDefaults {
+		res[k] = v
+	}
+
+	return res
+}
+
+// InconsistentDefaults reports the default values which do not validate against their own schema:
+// values of the wrong type, not listed in the enum, or violating some other validation.
+//
+// Defaults declared alongside a $ref are not checked. Findings are sorted by pointer.
+func (s *Spec) InconsistentDefaults() []Finding {
+	var findings []Finding
+	for pointer, def := range s.defaults.allDefaults {
+		keyword, decided := rejectingKeyword(schemaAsJSON(def.Schema), def.Value)
+		if !decided || keyword == "" {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Pointer: pointer,
+			Code:    CodeInconsistentDefault,
+			Message: fmt.Sprintf("default value %v is rejected by %s", def.Value, keyword),
+		})
+	}
+	sortFindings(findings)
+
+	return findings
+}
+
+// simpleSchemaOf represents the simple schema of a parameter, header or items as a schema
+func simpleSchemaOf(simple spec.SimpleSchema, validations spec.CommonValidations) *spec.Schema {
+	schema := new(spec.Schema)
+	if simple.Type != "" {
+		schema.Typed(simple.Type, simple.Format)
+	}
+	schema.SetValidations(validations.Validations())
+
+	if simple.Nullable {
+		schema.AddExtension("x-nullable", true)
+	}
+
+	if items := simple.Items; items != nil {
+		schema.Items = &spec.SchemaOrArray{Schema: simpleSchemaOf(items.SimpleSchema, items.CommonValidations)}
+	}
+
+	return schema
+}
@@ -0,0 +1,62 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpec_AllDefaults(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "defaults", "spec.yml")))
+	defaults := an.AllDefaults()
+
+	assert.Len(t, defaults, 10)
+
+	limit, ok := defaults["#/paths/~1pets/parameters/0"]
+	require.True(t, ok)
+	assert.Equal(t, float64(0), limit.Value)
+	assert.True(t, limit.Schema.Type.Contains("integer"))
+	require.NotNil(t, limit.Schema.Minimum)
+
+	items, ok := defaults["#/paths/~1pets/get/parameters/0/items"]
+	require.True(t, ok)
+	assert.Equal(t, "sleepy", items.Value)
+	assert.Len(t, items.Schema.Enum, 2)
+
+	status, ok := defaults["#/definitions/pet/properties/status"]
+	require.True(t, ok)
+	assert.Equal(t, "pending", status.Value)
+	assert.Equal(t, []interface{}{"available", "sold"}, status.Schema.Enum)
+
+	assert.Contains(t, defaults, "#/parameters/sort")
+	assert.Contains(t, defaults, "#/paths/~1pets/get/responses/200/headers/X-Rate-Limit")
+	assert.Contains(t, defaults, "#/definitions/owner")
+}
+
+func TestSpec_InconsistentDefaults(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "defaults", "spec.yml")))
+	findings := an.InconsistentDefaults()
+
+	pointers := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		assert.Equal(t, CodeInconsistentDefault, finding.Code)
+		assert.NotEmpty(t, finding.Message)
+		pointers = append(pointers, finding.Pointer)
+	}
+
+	assert.Equal(t, []string{
+		"#/definitions/pet/properties/status",
+		"#/definitions/pet/properties/tags",
+		"#/paths/~1pets/get/parameters/0/items",
+		"#/paths/~1pets/get/responses/200/headers/X-Rate-Limit",
+		"#/paths/~1pets/parameters/0",
+	}, pointers)
+	assert.Contains(t, findings[0].Message, "enum")
+}
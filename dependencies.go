@@ -0,0 +1,164 @@
+package analysis
+
+import (
+	"encoding/json"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/analysis/internal/flatten/normalize"
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// DependencyOpts configures the discovery of the external documents a spec depends on
+type DependencyOpts struct {
+	// BasePath is the location of the root document, used to resolve relative remote $ref's
+	BasePath string
+
+	// FS is the file system to read local documents from. Defaults to the OS file system
+	FS fs.FS
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// ExternalDependency is an external document a spec depends on, directly or transitively
+type ExternalDependency struct {
+	// Document is the location of the document, as an absolute file path or a URL
+	Document string
+
+	// ReferencedFrom lists the locations of the $ref's to this document: JSON pointers in the root document
+	// (e.g. "#/paths/~1pets/get/responses/200/schema"), or in another external document
+	// (e.g. "/specs/models.yml#/definitions/pet/properties/owner")
+	ReferencedFrom []string
+
+	// Direct is true when the document is referenced from the root document
+	Direct bool
+
+	// Reachable is true when the document could be loaded. Otherwise, Err tells why.
+	Reachable bool
+	Err       error
+}
+
+// ExternalDependencies lists all the external documents referenced by the spec, following the $ref's
+// found in these documents. Documents are loaded to discover their own $ref's.
+//
+// Dependencies are sorted by document.
+func (s *Spec) ExternalDependencies(opts DependencyOpts) []ExternalDependency {
+	base := absoluteBasePath(opts.BasePath)
+	loader := spec.PathLoader
+	if opts.FS != nil {
+		loader = fsPathLoader(opts.FS)
+	}
+
+	dependencies := make(map[string]*ExternalDependency)
+	var pending []string
+
+	addRef := func(from, document, ref string) {
+		target, _ := refDocument(document, ref)
+		if target == "" || target == document {
+			return
+		}
+
+		dependency, known := dependencies[target]
+		if !known {
+			dependency = &ExternalDependency{Document: target}
+			dependencies[target] = dependency
+			pending = append(pending, target)
+		}
+
+		dependency.ReferencedFrom = append(dependency.ReferencedFrom, from)
+		dependency.Direct = dependency.Direct || document == base
+	}
+
+	for _, key := range sortedKeys(s.references.allRefs) {
+		ref := s.references.allRefs[key]
+		if !ref.HasFragmentOnly {
+			addRef(key, base, ref.String())
+		}
+	}
+
+	for len(pending) > 0 {
+		document := pending[0]
+		pending = pending[1:]
+		dependency := dependencies[document]
+
+		raw, err := loader(document)
+		if err != nil {
+			dependency.Err = err
+
+			continue
+		}
+
+		var doc interface{}
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			dependency.Err = err
+
+			continue
+		}
+
+		dependency.Reachable = true
+		walkJSONRefs(doc, "", func(pointer, ref string) {
+			addRef(document+"#"+pointer, document, ref)
+		})
+	}
+
+	result := make([]ExternalDependency, 0, len(dependencies))
+	for _, document := range sortedKeys(dependencies) {
+		dependency := dependencies[document]
+		sort.Strings(dependency.ReferencedFrom)
+		result = append(result, *dependency)
+	}
+
+	return result
+}
+
+// refDocument resolves a $ref found in some document, and yields the absolute location of the document
+// it refers to (empty for a local $ref), and its fragment
+func refDocument(document, ref string) (string, string) {
+	target, fragment, _ := strings.Cut(ref, "#")
+	if target == "" {
+		return "", fragment
+	}
+
+	// NOTE: the fragment is rebased separately, since normalize.RebaseRef drops it from remote URLs
+	return absoluteBasePath(normalize.RebaseRef(document, target)), fragment
+}
+
+// walkJSONRefs visits the $ref's found in a generic JSON document, in a stable order
+func walkJSONRefs(doc interface{}, pointer string, visit func(pointer, ref string)) {
+	switch value := doc.(type) {
+	case map[string]interface{}:
+		if ref, isRef := value["$ref"].(string); isRef {
+			visit(pointer, ref)
+		}
+
+		for _, key := range sortedKeys(value) {
+			walkJSONRefs(value[key], pointer+"/"+jsonpointer.Escape(key), visit)
+		}
+	case []interface{}:
+		for i, item := range value {
+			walkJSONRefs(item, pointer+"/"+strconv.Itoa(i), visit)
+		}
+	}
+}
+
+// rewriteJSONRefs replaces the $ref's found in a generic JSON document
+func rewriteJSONRefs(doc interface{}, rewrite func(ref string) string) {
+	switch value := doc.(type) {
+	case map[string]interface{}:
+		if ref, isRef := value["$ref"].(string); isRef {
+			value["$ref"] = rewrite(ref)
+		}
+
+		for _, child := range value {
+			rewriteJSONRefs(child, rewrite)
+		}
+	case []interface{}:
+		for _, item := range value {
+			rewriteJSONRefs(item, rewrite)
+		}
+	}
+}
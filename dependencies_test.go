@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalDependencies(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "expand", "spec.yml")
+	sp := antest.LoadOrFail(t, bp)
+	sp.Definitions["missing"] = *spec.RefSchema("missing.yml#/definitions/nowhere")
+
+	dependencies := New(sp).ExternalDependencies(DependencyOpts{BasePath: bp})
+	require.Len(t, dependencies, 3)
+
+	missing := dependencies[0]
+	assert.Equal(t, absoluteBasePath(filepath.Join("fixtures", "expand", "missing.yml")), missing.Document)
+	assert.Equal(t, []string{"#/definitions/missing"}, missing.ReferencedFrom)
+	assert.True(t, missing.Direct)
+	assert.False(t, missing.Reachable)
+	assert.Error(t, missing.Err)
+
+	models := dependencies[1]
+	paths := absoluteBasePath(filepath.Join("fixtures", "expand", "paths.yml"))
+	assert.Equal(t, absoluteBasePath(filepath.Join("fixtures", "expand", "models.yml")), models.Document)
+	assert.Equal(t, []string{
+		"#/paths/~1pets/post/responses/201/schema",
+		paths + "#/owners/get/responses/200/schema/items",
+	}, models.ReferencedFrom)
+	assert.True(t, models.Direct)
+	assert.True(t, models.Reachable)
+	assert.NoError(t, models.Err)
+
+	assert.Equal(t, paths, dependencies[2].Document)
+	assert.Equal(t, []string{"#/paths/~1owners"}, dependencies[2].ReferencedFrom)
+	assert.True(t, dependencies[2].Reachable)
+}
+
+func TestExternalDependencies_Transitive(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"spec.yml":          {Data: []byte("swagger: '2.0'\ninfo: {title: t, version: '1'}\npaths: {}\ndefinitions:\n  pet: {$ref: 'models/pet.yml'}\n")},
+		"models/pet.yml":    {Data: []byte("type: object\nproperties:\n  owner: {$ref: 'owner.yml#/owner'}\n")},
+		"models/owner.yml":  {Data: []byte("owner: {type: object, properties: {pet: {$ref: 'pet.yml'}}}\n")},
+		"models/unused.yml": {Data: []byte("type: string\n")},
+	}
+
+	sp, err := LoadFS(fsys, "spec.yml")
+	require.NoError(t, err)
+
+	dependencies := New(sp).ExternalDependencies(DependencyOpts{BasePath: "spec.yml", FS: fsys})
+	require.Len(t, dependencies, 2)
+
+	owner, pet := dependencies[0], dependencies[1]
+	assert.Equal(t, absoluteBasePath(filepath.Join("models", "owner.yml")), owner.Document)
+	assert.False(t, owner.Direct)
+	assert.True(t, owner.Reachable)
+	assert.Equal(t, []string{pet.Document + "#/properties/owner"}, owner.ReferencedFrom)
+
+	assert.True(t, pet.Direct)
+	assert.True(t, pet.Reachable)
+	assert.Equal(t, []string{"#/definitions/pet", owner.Document + "#/owner/properties/pet"}, pet.ReferencedFrom)
+}
@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// DeprecationKind is the kind of a deprecated element of a spec
+type DeprecationKind string
+
+// Kinds of deprecated elements
+const (
+	DeprecatedOperation  DeprecationKind = "operation"
+	DeprecatedParameter  DeprecationKind = "parameter"
+	DeprecatedDefinition DeprecationKind = "definition"
+	DeprecatedProperty   DeprecationKind = "property"
+	DeprecatedSchema     DeprecationKind = "schema"
+)
+
+// extDeprecated is the vendor extension marking parameters and schemas as deprecated
+const extDeprecated = "x-deprecated"
+
+// Deprecation is a deprecated element of a spec
+type Deprecation struct {
+	// Pointer locates the deprecated element (e.g. "#/definitions/pet/properties/nickname")
+	Pointer string
+	Kind    DeprecationKind
+
+	// Name is the operationId of an operation, the name of a parameter, definition or property,
+	// or empty for other schemas
+	Name string
+}
+
+// Deprecated lists the deprecated elements of a spec: operations marked as deprecated, and parameters
+// and schemas marked with the x-deprecated extension (or the deprecated keyword of later versions of
+// JSON schema, for schemas).
+//
+// Deprecated elements are sorted by pointer.
+func (s *Spec) Deprecated() []Deprecation {
+	var deprecated []Deprecation
+
+	walkOperations(s.spec, func(pointer string, op *spec.Operation) {
+		if op.Deprecated {
+			deprecated = append(deprecated, Deprecation{Pointer: "#" + pointer, Kind: DeprecatedOperation, Name: op.ID})
+		}
+	})
+
+	walkParameters(s.spec, func(pointer string, param *spec.Parameter) {
+		if isDeprecatedExtension(param.Extensions) {
+			deprecated = append(deprecated, Deprecation{Pointer: pointer, Kind: DeprecatedParameter, Name: param.Name})
+		}
+	})
+
+	walkSchemas(s.spec, func(pointer string, schema *spec.Schema) {
+		deprecatedKeyword, _ := schema.ExtraProps["deprecated"].(bool)
+		if !deprecatedKeyword && !isDeprecatedExtension(schema.Extensions) {
+			return
+		}
+
+		deprecation := Deprecation{Pointer: pointer, Kind: DeprecatedSchema}
+		parent, name := splitPointer(pointer)
+		switch {
+		case parent == definitionsPath:
+			deprecation.Kind, deprecation.Name = DeprecatedDefinition, name
+		case strings.HasSuffix(parent, "/properties"):
+			deprecation.Kind, deprecation.Name = DeprecatedProperty, name
+		}
+
+		deprecated = append(deprecated, deprecation)
+	})
+
+	sort.SliceStable(deprecated, func(i, j int) bool {
+		return deprecated[i].Pointer < deprecated[j].Pointer
+	})
+
+	return deprecated
+}
+
+func isDeprecatedExtension(extensions spec.Extensions) bool {
+	value, ok := lookupExtension(extensions, extDeprecated)
+	if !ok {
+		return false
+	}
+
+	isDeprecated, _ := value.(bool)
+
+	return isDeprecated
+}
+
+// splitPointer splits a JSON pointer into the pointer to its parent and its last (unescaped) token
+func splitPointer(pointer string) (string, string) {
+	i := strings.LastIndexByte(pointer, '/')
+	if i < 0 {
+		return pointer, ""
+	}
+
+	return pointer[:i], jsonpointer.Unescape(pointer[i+1:])
+}
@@ -0,0 +1,24 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpec_Deprecated(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "deprecated", "spec.yml")))
+
+	assert.Equal(t, []Deprecation{
+		{Pointer: "#/definitions/oldPet", Kind: DeprecatedDefinition, Name: "oldPet"},
+		{Pointer: "#/definitions/pet/properties/nickname", Kind: DeprecatedProperty, Name: "nickname"},
+		{Pointer: "#/parameters/legacy", Kind: DeprecatedParameter, Name: "legacy"},
+		{Pointer: "#/paths/~1pets/get", Kind: DeprecatedOperation, Name: "listPets"},
+		{Pointer: "#/paths/~1pets/get/parameters/0", Kind: DeprecatedParameter, Name: "sort"},
+		{Pointer: "#/paths/~1pets/post/parameters/0/schema", Kind: DeprecatedSchema},
+	}, an.Deprecated())
+}
@@ -31,6 +31,8 @@ Depending on flattening options, additional preprocessing may take place:
 
 Mixin several specifications merges all Swagger constructs, and warns about found conflicts.
 
+Alternatively, an OpenAPI Overlay document may be applied to a specification.
+
 ## Fixing a specification
 
 Unmarshalling a specification with golang json unmarshalling may lead to
@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// DocumentOpts configures the end-to-end analysis of a document with AnalyzeDocument
+type DocumentOpts struct {
+	// BasePath is the location of a document analyzed with AnalyzeDocumentBytes, used to resolve relative $ref's.
+	//
+	// It is ignored by AnalyzeDocument, which uses the location of the document.
+	BasePath string
+
+	// Fix applies the automatic fixes (FixEmptyResponseDescriptions, FixNoOpConstraints) before analysis
+	Fix bool
+
+	// Flatten flattens the spec with these options before analysis, when not nil.
+	//
+	// The Spec and BasePath options are set by AnalyzeDocument.
+	Flatten *FlattenOpts
+
+	// Checks run on the analyzed spec. Defaults to DefaultHealthChecks().
+	//
+	// Use an empty, non-nil slice to skip all checks.
+	Checks []HealthCheck
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// DocumentAnalysis is the result of AnalyzeDocument
+type DocumentAnalysis struct {
+	// Swagger is the loaded specification, after fixing and flattening
+	Swagger *spec.Swagger
+
+	// Spec is the analyzer for this specification
+	Spec *Spec
+
+	// Findings reported by checks, sorted by pointer
+	Findings []Finding
+}
+
+// AnalyzeDocument loads a swagger specification document (JSON or YAML) from a local file or a URL,
+// then optionally fixes and flattens it, builds the analyzer and runs checks.
+func AnalyzeDocument(path string, opts DocumentOpts) (*DocumentAnalysis, error) {
+	data, err := swag.LoadFromFileOrHTTP(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not load %s: %w", path, err)
+	}
+
+	opts.BasePath = path
+
+	return AnalyzeDocumentBytes(data, opts)
+}
+
+// AnalyzeDocumentBytes is like AnalyzeDocument, for a document (JSON or YAML) already loaded in memory
+func AnalyzeDocumentBytes(data []byte, opts DocumentOpts) (*DocumentAnalysis, error) {
+	doc, err := documentAsJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse document: %w", err)
+	}
+
+	var sp spec.Swagger
+	if err := json.Unmarshal(doc, &sp); err != nil {
+		return nil, fmt.Errorf("could not parse document: %w", err)
+	}
+
+	if opts.Fix {
+		FixEmptyResponseDescriptions(&sp)
+		FixNoOpConstraints(&sp)
+	}
+
+	analyzed := newSpec(&sp)
+
+	if opts.Flatten != nil {
+		flattenOpts := *opts.Flatten
+		flattenOpts.Spec = analyzed
+		flattenOpts.BasePath = opts.BasePath
+
+		if err := Flatten(flattenOpts); err != nil {
+			return nil, fmt.Errorf("could not flatten document: %w", err)
+		}
+	}
+
+	analyzed.runEagerPlugins()
+
+	checks := opts.Checks
+	if checks == nil {
+		checks = DefaultHealthChecks()
+	}
+
+	var findings []Finding
+	for _, check := range checks {
+		findings = append(findings, check(analyzed)...)
+	}
+	sortFindings(findings)
+
+	return &DocumentAnalysis{Swagger: &sp, Spec: analyzed, Findings: findings}, nil
+}
+
+// documentAsJSON yields a JSON or YAML document as JSON
+func documentAsJSON(data []byte) (json.RawMessage, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return json.RawMessage(data), nil
+	}
+
+	doc, err := swag.BytesToYAMLDoc(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return swag.YAMLToJSON(doc)
+}
@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeDocument(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with defaults", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := AnalyzeDocument(filepath.Join("fixtures", "fixer", "fixer.yaml"), DocumentOpts{})
+		require.NoError(t, err)
+
+		require.NotNil(t, result.Spec)
+		assert.NotEmpty(t, result.Spec.AllPaths())
+		assert.Contains(t, findingCodes(result.Findings), CodeEmptyResponseDescription)
+	})
+
+	t.Run("with fixes", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := AnalyzeDocument(filepath.Join("fixtures", "fixer", "fixer.yaml"), DocumentOpts{Fix: true})
+		require.NoError(t, err)
+
+		assert.NotContains(t, findingCodes(result.Findings), CodeEmptyResponseDescription)
+		assert.NotEmpty(t, result.Swagger.Responses["someResponse"].Description)
+	})
+
+	t.Run("with flattening", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := AnalyzeDocument(filepath.Join("fixtures", "expand", "spec.yml"), DocumentOpts{
+			Flatten: &FlattenOpts{Minimal: true},
+			Checks:  []HealthCheck{},
+		})
+		require.NoError(t, err)
+
+		// remote definitions are imported
+		assert.Contains(t, result.Swagger.Definitions, "tag")
+		assert.Contains(t, result.Spec.AllDefinitionReferences(), "#/definitions/tag")
+		assert.Empty(t, result.Findings)
+	})
+
+	t.Run("with JSON bytes", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := AnalyzeDocumentBytes([]byte(`{
+			"swagger": "2.0",
+			"info": {"title": "test", "version": "1.0"},
+			"paths": {"/pets": {"get": {"operationId": "listPets", "responses": {"200": {"description": "ok"}}}}}
+		}`), DocumentOpts{})
+		require.NoError(t, err)
+
+		_, _, _, ok := result.Spec.OperationForName("listPets")
+		assert.True(t, ok)
+	})
+
+	t.Run("with YAML bytes", func(t *testing.T) {
+		t.Parallel()
+
+		bp := filepath.Join("fixtures", "expand", "spec.yml")
+		data, err := os.ReadFile(bp)
+		require.NoError(t, err)
+
+		result, err := AnalyzeDocumentBytes(data, DocumentOpts{BasePath: bp, Flatten: &FlattenOpts{Minimal: true}})
+		require.NoError(t, err)
+		assert.Contains(t, result.Swagger.Definitions, "tag")
+	})
+
+	t.Run("with errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := AnalyzeDocument(filepath.Join("fixtures", "nowhere.yml"), DocumentOpts{})
+		require.Error(t, err)
+
+		_, err = AnalyzeDocumentBytes([]byte("{"), DocumentOpts{})
+		require.Error(t, err)
+
+		_, err = AnalyzeDocumentBytes([]byte("swagger: [2.0"), DocumentOpts{})
+		require.Error(t, err)
+	})
+}
+
+func findingCodes(findings []Finding) []string {
+	codes := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		codes = append(codes, finding.Code)
+	}
+
+	return codes
+}
@@ -0,0 +1,158 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// EnumCluster is a set of enum values declared in several places of a spec, which may be consolidated
+// into a shared definition
+type EnumCluster struct {
+	// Values of the enum, in the order of their first occurrence
+	Values []interface{}
+
+	// Occurrences are the locations declaring this enum (e.g. "#/definitions/order/properties/status",
+	// "#/paths/~1orders/get/parameters/0"), sorted
+	Occurrences []string
+
+	// Definition is an existing definition which declares this enum, to which the other occurrences may refer
+	// (e.g. "#/definitions/status"). It is empty when no definition declares this enum.
+	Definition string
+
+	// SuggestedName is a name for a new definition of this enum, after the names of its occurrences
+	// (e.g. "status"), when no definition declares it already
+	SuggestedName string
+}
+
+// EnumClusters groups the enums with the same set of values declared in several places of the spec
+// (schemas, parameters, headers and items), regardless of the order of these values.
+//
+// Each cluster suggests a definition to share: an existing definition declaring the enum, or a new one named
+// after the most common name of its occurrences (a property, parameter or header name).
+// Clusters are sorted by decreasing number of occurrences, then by first occurrence.
+func (s *Spec) EnumClusters() []EnumCluster {
+	byValues := make(map[string]*EnumCluster)
+	for _, pointer := range sortedKeys(s.enums.allEnums) {
+		enum := s.enums.allEnums[pointer]
+		key := enumSetKey(enum)
+
+		cluster, found := byValues[key]
+		if !found {
+			cluster = &EnumCluster{Values: enum}
+			byValues[key] = cluster
+		}
+		cluster.Occurrences = append(cluster.Occurrences, pointer)
+	}
+
+	clusters := make([]EnumCluster, 0, len(byValues))
+	for _, cluster := range byValues {
+		if len(cluster.Occurrences) < 2 {
+			continue
+		}
+
+		for _, pointer := range cluster.Occurrences {
+			if name, isDefinition := definitionOfPointer(pointer); isDefinition && pointer == definitionsPrefix+jsonpointer.Escape(name) {
+				cluster.Definition = pointer
+
+				break
+			}
+		}
+
+		if cluster.Definition == "" {
+			cluster.SuggestedName = s.enumClusterName(cluster)
+		}
+
+		clusters = append(clusters, *cluster)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Occurrences) != len(clusters[j].Occurrences) {
+			return len(clusters[i].Occurrences) > len(clusters[j].Occurrences)
+		}
+
+		return clusters[i].Occurrences[0] < clusters[j].Occurrences[0]
+	})
+
+	return clusters
+}
+
+// enumSetKey yields a key identifying the set of values of an enum, regardless of their order
+func enumSetKey(enum []interface{}) string {
+	values := make([]string, 0, len(enum))
+	seen := make(map[string]bool, len(enum))
+	for _, value := range enum {
+		buf, err := json.Marshal(value)
+		if err != nil {
+			buf = []byte(fmt.Sprintf("%#v", value))
+		}
+
+		if !seen[string(buf)] {
+			seen[string(buf)] = true
+			values = append(values, string(buf))
+		}
+	}
+	sort.Strings(values)
+
+	return strings.Join(values, ",")
+}
+
+// enumClusterName suggests a name for the definition of the enum of a cluster, after the most common name
+// of its occurrences
+func (s *Spec) enumClusterName(cluster *EnumCluster) string {
+	counts := make(map[string]int)
+	for _, pointer := range cluster.Occurrences {
+		if name := s.enumOccurrenceName(pointer); name != "" {
+			counts[name]++
+		}
+	}
+
+	var best string
+	for _, name := range sortedKeys(counts) {
+		if best == "" || counts[name] > counts[best] {
+			best = name
+		}
+	}
+
+	if best == "" {
+		best = "enum"
+	}
+
+	return liftedEnumName(s.spec, best, &spec.Schema{SchemaProps: spec.SchemaProps{Enum: cluster.Values}})
+}
+
+// enumOccurrenceName yields the name of the element declaring an enum: a property or header name, or the name
+// of a parameter
+func (s *Spec) enumOccurrenceName(pointer string) string {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "#/"), "/")
+	for len(tokens) > 1 && tokens[len(tokens)-1] == "items" {
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	last := jsonpointer.Unescape(tokens[len(tokens)-1])
+	if len(tokens) > 1 && tokens[len(tokens)-2] == "parameters" {
+		// parameters are named after their name, rather than their index or key
+		if ptr, err := jsonpointer.New("/" + strings.Join(tokens, "/")); err == nil {
+			if element, _, err := ptr.Get(s.spec); err == nil {
+				switch param := element.(type) {
+				case spec.Parameter:
+					last = param.Name
+				case *spec.Parameter:
+					last = param.Name
+				}
+			}
+		}
+	}
+
+	if _, err := strconv.Atoi(last); err == nil {
+		return ""
+	}
+
+	return swag.ToJSONName(last)
+}
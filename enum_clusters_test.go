@@ -0,0 +1,48 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumClusters(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "enum_clusters.yml"))
+	clusters := New(sp).EnumClusters()
+
+	require.Len(t, clusters, 2)
+
+	assert.Equal(t, []string{
+		"#/definitions/order/properties/status",
+		"#/definitions/shipment/properties/status",
+		"#/paths/~1orders/get/parameters/0",
+		"#/paths/~1orders/get/responses/200/headers/X-Order-Status",
+	}, clusters[0].Occurrences)
+	assert.Equal(t, []interface{}{"pending", "shipped", "cancelled"}, clusters[0].Values)
+	assert.Empty(t, clusters[0].Definition)
+	assert.Equal(t, "status", clusters[0].SuggestedName)
+
+	assert.Equal(t, []string{
+		"#/definitions/color",
+		"#/definitions/order/properties/color",
+		"#/paths/~1orders/get/parameters/1/items",
+	}, clusters[1].Occurrences)
+	assert.Equal(t, "#/definitions/color", clusters[1].Definition)
+	assert.Empty(t, clusters[1].SuggestedName)
+}
+
+func TestEnumClusters_NameCollision(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "enum_clusters.yml"))
+	sp.Definitions["status"] = sp.Definitions["order"]
+
+	clusters := New(sp).EnumClusters()
+	require.NotEmpty(t, clusters)
+	assert.Equal(t, "status2", clusters[0].SuggestedName)
+}
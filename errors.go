@@ -0,0 +1,121 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoSchema is returned when analyzing a nil schema
+var ErrNoSchema = errors.New("no schema to analyze")
+
+// ErrUnsupportedDocument is returned when analyzing a raw document of an unsupported type
+var ErrUnsupportedDocument = errors.New("unsupported document type")
+
+// ErrNoResponse is returned when an operation declares no response for a status code, nor any default response
+var ErrNoResponse = errors.New("no response declared for status code")
+
+// ErrFrozenSpec is returned when modifying a spec frozen with Freeze
+var ErrFrozenSpec = errors.New("spec is frozen")
+
+// ErrArchiveTooLarge is returned when opening an archive larger than 256MB, or which decompresses to more than 256MB
+var ErrArchiveTooLarge = errors.New("archive too large")
+
+// RefError is an error about a $ref which cannot be resolved, e.g. a remote document which cannot be loaded,
+// or a JSON pointer which does not locate anything in the target document
+type RefError struct {
+	// Pointer is the location of the $ref in the analyzed document (e.g. "#/definitions/pet/properties/owner").
+	// It is empty when unknown.
+	Pointer string
+
+	// Ref is the $ref which cannot be resolved. It is empty when unknown.
+	Ref string
+
+	Cause error
+}
+
+func (e *RefError) Error() string {
+	msg := "could not resolve $ref"
+	if e.Ref != "" {
+		msg += " " + e.Ref
+	}
+
+	if e.Pointer != "" {
+		msg = "at " + e.Pointer + ", " + msg
+	}
+
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+
+	return msg
+}
+
+func (e *RefError) Unwrap() error {
+	return e.Cause
+}
+
+// RefRedirect is returned by a SchemaOpts.OnResolve hook to resolve another $ref instead of the one submitted
+// to the hook, e.g. "https://mirror.example.com/schemas/pet.json" in place of "https://schemas.example.com/pet.json"
+type RefRedirect struct {
+	Ref string
+}
+
+func (e *RefRedirect) Error() string {
+	return "$ref redirected to " + e.Ref
+}
+
+// PointerError is an error about a JSON pointer which does not locate an element which can be rewritten,
+// e.g. an invalid array index, or a location which does not hold a schema
+type PointerError struct {
+	Pointer string
+	Cause   error
+}
+
+func (e *PointerError) Error() string {
+	return fmt.Sprintf("invalid pointer %s: %v", e.Pointer, e.Cause)
+}
+
+func (e *PointerError) Unwrap() error {
+	return e.Cause
+}
+
+// SchemaError is an error raised while analyzing the schema at some location
+type SchemaError struct {
+	Pointer string
+	Cause   error
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("schema analysis [%s]: %v", e.Pointer, e.Cause)
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Cause
+}
+
+// NameConflictError is an error about an entry colliding with an existing one, e.g. when merging specs with Mixin
+type NameConflictError struct {
+	Section string // the section of the spec, e.g. "paths", "definitions"
+	Name    string // the colliding name
+	Pointer string // the location of the existing entry (e.g. "#/definitions/pet")
+}
+
+func (e *NameConflictError) Error() string {
+	return fmt.Sprintf("%s entry %q conflicts with the existing entry at %s", e.Section, e.Name, e.Pointer)
+}
+
+// ExtensionError is an error about a vendor extension with an unexpected value, e.g. a string where
+// an object is expected
+type ExtensionError struct {
+	Pointer   string // the location of the element holding the extension (e.g. "#/definitions/pet")
+	Extension string // the name of the extension (e.g. "x-go-type")
+	Cause     error
+}
+
+func (e *ExtensionError) Error() string {
+	return fmt.Sprintf("invalid extension %s at %s: %v", e.Extension, e.Pointer, e.Cause)
+}
+
+func (e *ExtensionError) Unwrap() error {
+	return e.Cause
+}
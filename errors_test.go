@@ -0,0 +1,111 @@
+package analysis
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors_RefError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with Flatten", func(t *testing.T) {
+		t.Parallel()
+
+		bp := filepath.Join("fixtures", "expand", "spec.yml")
+		sp := antest.LoadOrFail(t, bp)
+		sp.Definitions["dangling"] = *spec.RefSchema("missing.yml#/definitions/nowhere")
+
+		err := Flatten(FlattenOpts{Spec: New(sp), BasePath: bp})
+		require.Error(t, err)
+
+		var refErr *RefError
+		require.True(t, errors.As(err, &refErr))
+		assert.Equal(t, "#/definitions/dangling", refErr.Pointer)
+		assert.True(t, strings.HasSuffix(refErr.Ref, "missing.yml#/definitions/nowhere"), refErr.Ref)
+		assert.Error(t, refErr.Cause)
+		assert.Contains(t, err.Error(), "#/definitions/dangling")
+
+		var pointerErr *PointerError
+		assert.False(t, errors.As(err, &pointerErr))
+	})
+
+	t.Run("with ExpandRefs", func(t *testing.T) {
+		t.Parallel()
+
+		bp := filepath.Join("fixtures", "expand", "spec.yml")
+		sp := antest.LoadOrFail(t, bp)
+		sp.Definitions["dangling"] = *spec.RefSchema("#/definitions/nowhere")
+
+		err := ExpandRefs(sp, ExpandOpts{BasePath: bp})
+
+		var refErr *RefError
+		require.True(t, errors.As(err, &refErr))
+		assert.Equal(t, "#/definitions/dangling", refErr.Pointer)
+		assert.Equal(t, "#/definitions/nowhere", refErr.Ref)
+	})
+
+	t.Run("with Schema", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Schema(SchemaOpts{Schema: spec.RefSchema("#/definitions/nowhere"), Root: &spec.Swagger{}})
+
+		var refErr *RefError
+		require.True(t, errors.As(err, &refErr))
+		assert.Empty(t, refErr.Pointer)
+		assert.Equal(t, "#/definitions/nowhere", refErr.Ref)
+	})
+}
+
+func TestErrors_PointerError(t *testing.T) {
+	t.Parallel()
+
+	sp := &spec.Swagger{}
+	opts := &FlattenOpts{Spec: New(sp)}
+
+	err := opts.updateRef("#/definitions/nowhere/properties/id", spec.MustCreateRef("#/definitions/id"))
+	require.Error(t, err)
+
+	var pointerErr *PointerError
+	require.True(t, errors.As(err, &pointerErr))
+	assert.Equal(t, "#/definitions/nowhere/properties/id", pointerErr.Pointer)
+	assert.Equal(t, pointerErr.Cause, errors.Unwrap(err))
+}
+
+func TestErrors_Schema(t *testing.T) {
+	t.Parallel()
+
+	_, err := Schema(SchemaOpts{})
+	assert.ErrorIs(t, err, ErrNoSchema)
+
+	schemaErr := &SchemaError{Pointer: "#/definitions/pet", Cause: ErrNoSchema}
+	assert.ErrorIs(t, schemaErr, ErrNoSchema)
+	assert.Contains(t, schemaErr.Error(), "#/definitions/pet")
+}
+
+func TestErrors_NameConflictError(t *testing.T) {
+	t.Parallel()
+
+	primary := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Definitions: spec.Definitions{"pet": *spec.StringProperty()},
+	}}
+	mixin := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Definitions: spec.Definitions{"pet": *spec.Int64Property()},
+	}}
+
+	conflicts := MixinReport(primary, mixin)
+	require.Len(t, conflicts, 1)
+
+	var conflictErr *NameConflictError
+	require.True(t, errors.As(conflicts[0].Err(), &conflictErr))
+	assert.Equal(t, MixinSectionDefinitions, conflictErr.Section)
+	assert.Equal(t, "pet", conflictErr.Name)
+	assert.Equal(t, "#/definitions/pet", conflictErr.Pointer)
+	assert.Contains(t, conflictErr.Error(), `"pet"`)
+}
@@ -0,0 +1,402 @@
+package analysis
+
+import (
+	"math"
+	slashpath "path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// Example is an example value declared in a specification
+type Example struct {
+	// Pointer locates the example value (e.g. "#/paths/~1pets/get/responses/200/examples/application~1json").
+	//
+	// Examples found in shared parameters, responses and definitions are located there.
+	Pointer string
+
+	// MediaType is the media type of a response example
+	MediaType string
+
+	Value interface{}
+}
+
+// ExamplesFor collects the examples declared for an operation: in its parameters (including the parameters
+// of its path), their schemas, its responses, their headers and their schemas.
+//
+// Local $ref's are followed, so the examples declared in shared parameters, responses and definitions are collected.
+//
+// Examples are sorted by pointer.
+func (s *Spec) ExamplesFor(operation *spec.Operation) []Example {
+	if operation == nil {
+		return nil
+	}
+
+	c := &exampleCollector{sp: s.spec, visited: make(map[string]bool)}
+
+	pointer := "#"
+	if s.spec.Paths != nil {
+		for _, path := range sortedKeys(s.spec.Paths.Paths) {
+			pathItem := s.spec.Paths.Paths[path]
+			for _, method := range sortedOperationMethods(&pathItem) {
+				if operationOf(&pathItem, method) == operation {
+					pointer = "#" + slashpath.Join("/paths", jsonpointer.Escape(path), strings.ToLower(method))
+					c.parameters(slashpath.Dir(pointer), pathItem.Parameters)
+				}
+			}
+		}
+	}
+
+	c.parameters(pointer, operation.Parameters)
+
+	if operation.Responses != nil {
+		if operation.Responses.Default != nil {
+			c.response(slashpath.Join(pointer, "responses", "default"), operation.Responses.Default)
+		}
+
+		for _, code := range sortedStatusCodes(operation.Responses.StatusCodeResponses) {
+			resp := operation.Responses.StatusCodeResponses[code]
+			c.response(slashpath.Join(pointer, "responses", strconv.Itoa(code)), &resp)
+		}
+	}
+
+	sort.SliceStable(c.examples, func(i, j int) bool {
+		return c.examples[i].Pointer < c.examples[j].Pointer
+	})
+
+	return c.examples
+}
+
+type exampleCollector struct {
+	sp       *spec.Swagger
+	visited  map[string]bool // the $ref's already followed
+	examples []Example
+}
+
+// follow tells if a $ref should be followed, and yields the location it points to
+func (c *exampleCollector) follow(ref spec.Ref) (string, bool) {
+	target := ref.String()
+	if !ref.HasFragmentOnly || c.visited[target] {
+		return "", false
+	}
+	c.visited[target] = true
+
+	return target, true
+}
+
+func (c *exampleCollector) add(pointer, mediaType string, value interface{}) {
+	c.examples = append(c.examples, Example{Pointer: pointer, MediaType: mediaType, Value: value})
+}
+
+func (c *exampleCollector) parameters(pointer string, params []spec.Parameter) {
+	for i := range params {
+		c.parameter(slashpath.Join(pointer, "parameters", strconv.Itoa(i)), &params[i])
+	}
+}
+
+func (c *exampleCollector) parameter(pointer string, param *spec.Parameter) {
+	if param.Ref.String() != "" {
+		target, ok := c.follow(param.Ref)
+		if !ok {
+			return
+		}
+
+		resolved, err := spec.ResolveParameter(c.sp, param.Ref)
+		if err != nil {
+			return
+		}
+
+		pointer, param = target, resolved
+	}
+
+	if param.Example != nil {
+		c.add(pointer+"/example", "", param.Example)
+	}
+
+	for items, itemsPointer := param.Items, pointer+"/items"; items != nil; items, itemsPointer = items.Items, itemsPointer+"/items" {
+		if items.Example != nil {
+			c.add(itemsPointer+"/example", "", items.Example)
+		}
+	}
+
+	if param.Schema != nil {
+		c.schema(pointer+"/schema", param.Schema)
+	}
+}
+
+func (c *exampleCollector) response(pointer string, resp *spec.Response) {
+	if resp.Ref.String() != "" {
+		target, ok := c.follow(resp.Ref)
+		if !ok {
+			return
+		}
+
+		resolved, err := spec.ResolveResponse(c.sp, resp.Ref)
+		if err != nil {
+			return
+		}
+
+		pointer, resp = target, resolved
+	}
+
+	for _, mediaType := range sortedKeys(resp.Examples) {
+		c.add(slashpath.Join(pointer, "examples", jsonpointer.Escape(mediaType)), mediaType, resp.Examples[mediaType])
+	}
+
+	for _, name := range sortedKeys(resp.Headers) {
+		if header := resp.Headers[name]; header.Example != nil {
+			c.add(slashpath.Join(pointer, "headers", jsonpointer.Escape(name), "example"), "", header.Example)
+		}
+	}
+
+	if resp.Schema != nil {
+		c.schema(pointer+"/schema", resp.Schema)
+	}
+}
+
+func (c *exampleCollector) schema(pointer string, schema *spec.Schema) {
+	walkSchema(strings.TrimPrefix(pointer, "#"), schema, func(ptr string, sch *spec.Schema) {
+		if sch.Example != nil {
+			c.add(ptr+"/example", "", sch.Example)
+		}
+
+		if sch.Ref.String() == "" {
+			return
+		}
+
+		target, ok := c.follow(sch.Ref)
+		if !ok {
+			return
+		}
+
+		if resolved, err := spec.ResolveRef(c.sp, &sch.Ref); err == nil {
+			c.schema(target, resolved)
+		}
+	})
+}
+
+// formatExamples are sample values for the common string formats
+// MaxSynthesizedLength is the maximum number of items of the arrays and characters of the strings
+// produced by SynthesizeExample
+const MaxSynthesizedLength = 1024
+
+var formatExamples = map[string]string{
+	"byte":      "ZXhhbXBsZQ==",
+	"date":      "2021-01-01",
+	"date-time": "2021-01-01T00:00:00Z",
+	"duration":  "1s",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"password":  "secret",
+	"uri":       "https://example.com",
+	"uuid":      "123e4567-e89b-12d3-a456-426614174000",
+}
+
+// SynthesizeExample produces a sample value for a schema, as generic JSON.
+//
+// The declared example, default value or first enum value is used when present. Otherwise, a value of the
+// first type of the schema is built: objects with all their declared and required properties, arrays with
+// as many items as required, strings honoring formats, lengths and (when some simple candidate matches) patterns,
+// numbers honoring bounds and multipleOf. The members of allOf are merged, and the first member of anyOf
+// and oneOf is used.
+//
+// Arrays and strings are no longer than MaxSynthesizedLength, even when the schema requires more.
+//
+// $ref's are not resolved and yield nil: use EffectiveSchema first to inline them.
+func SynthesizeExample(schema *spec.Schema) interface{} {
+	if schema == nil {
+		return nil
+	}
+
+	switch {
+	case schema.Example != nil:
+		return deepCopyJSON(schema.Example)
+	case schema.Default != nil:
+		return deepCopyJSON(schema.Default)
+	case len(schema.Enum) > 0:
+		return deepCopyJSON(schema.Enum[0])
+	case schema.Ref.String() != "":
+		return nil
+	case len(schema.AllOf) > 0:
+		own := *schema
+		own.AllOf = nil
+		merged := schemaAsJSON(&own)
+
+		var ignored []Finding
+		for i := range schema.AllOf {
+			merged, _ = intersectJSON("#", merged, schemaAsJSON(&schema.AllOf[i]), &ignored)
+		}
+		delete(merged, "allOf") // constraints which could not be merged are not honored
+
+		return SynthesizeExample(jsonAsSchema(merged))
+	case len(schema.AnyOf) > 0:
+		return SynthesizeExample(&schema.AnyOf[0])
+	case len(schema.OneOf) > 0:
+		return SynthesizeExample(&schema.OneOf[0])
+	}
+
+	tpe := ""
+	switch {
+	case len(schema.Type) > 0:
+		tpe = schema.Type[0]
+	case len(schema.Properties) > 0 || len(schema.Required) > 0:
+		tpe = "object"
+	case schema.Items != nil:
+		tpe = "array"
+	}
+
+	switch tpe {
+	case "object":
+		return synthesizeObject(schema)
+	case "array":
+		return synthesizeArray(schema)
+	case "string":
+		return synthesizeString(schema)
+	case "integer":
+		return synthesizeNumber(schema, 1)
+	case "number":
+		return synthesizeNumber(schema, 0)
+	case "boolean":
+		return true
+	default:
+		return nil
+	}
+}
+
+func synthesizeObject(schema *spec.Schema) map[string]interface{} {
+	result := make(map[string]interface{}, len(schema.Properties))
+	for name := range schema.Properties {
+		property := schema.Properties[name]
+		result[name] = SynthesizeExample(&property)
+	}
+
+	for _, name := range schema.Required {
+		if _, isDeclared := result[name]; isDeclared {
+			continue
+		}
+
+		var value interface{}
+		if additional := schema.AdditionalProperties; additional != nil && additional.Schema != nil {
+			value = SynthesizeExample(additional.Schema)
+		}
+		result[name] = value
+	}
+
+	return result
+}
+
+func synthesizeArray(schema *spec.Schema) []interface{} {
+	count := int64(1)
+	if schema.MinItems != nil && *schema.MinItems > count {
+		count = *schema.MinItems
+	}
+	if schema.MaxItems != nil && *schema.MaxItems < count {
+		count = *schema.MaxItems
+	}
+	count = boundedLength(count)
+
+	result := make([]interface{}, 0, count)
+	if schema.Items == nil {
+		for i := int64(0); i < count; i++ {
+			result = append(result, nil)
+		}
+
+		return result
+	}
+
+	if schema.Items.Schema != nil {
+		for i := int64(0); i < count; i++ {
+			result = append(result, SynthesizeExample(schema.Items.Schema))
+		}
+
+		return result
+	}
+
+	for i := range schema.Items.Schemas {
+		result = append(result, SynthesizeExample(&schema.Items.Schemas[i]))
+	}
+
+	return result
+}
+
+func synthesizeString(schema *spec.Schema) string {
+	if example, isKnown := formatExamples[schema.Format]; isKnown {
+		return example
+	}
+
+	candidates := []string{"example"}
+	if schema.Pattern != "" {
+		candidates = append(candidates, "a", "A", "0", "")
+	}
+
+	for _, candidate := range candidates {
+		value := candidate
+		if schema.MinLength != nil && int64(len(value)) < *schema.MinLength {
+			value += strings.Repeat("x", int(boundedLength(*schema.MinLength))-len(value))
+		}
+		if schema.MaxLength != nil && int64(len(value)) > *schema.MaxLength {
+			value = value[:boundedLength(*schema.MaxLength)]
+		}
+
+		if schema.Pattern == "" {
+			return value
+		}
+
+		if re, err := regexp.Compile(schema.Pattern); err != nil || re.MatchString(value) {
+			return value
+		}
+	}
+
+	return candidates[0]
+}
+
+// boundedLength keeps a length required by a schema between 0 and MaxSynthesizedLength
+func boundedLength(length int64) int64 {
+	switch {
+	case length < 0:
+		return 0
+	case length > MaxSynthesizedLength:
+		return MaxSynthesizedLength
+	default:
+		return length
+	}
+}
+
+// synthesizeNumber yields the value closest to zero within the bounds of a schema.
+//
+// The step is the granularity of the values: 1 for integers, 0 for numbers.
+func synthesizeNumber(schema *spec.Schema, step float64) float64 {
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		step = *schema.MultipleOf
+	}
+
+	value := 0.0
+	switch {
+	case schema.Minimum != nil && (value < *schema.Minimum || value == *schema.Minimum && schema.ExclusiveMinimum):
+		value = *schema.Minimum
+		if step > 0 {
+			value = math.Ceil(value/step) * step
+		}
+
+		if value == *schema.Minimum && schema.ExclusiveMinimum {
+			value += math.Max(step, 1)
+		}
+	case schema.Maximum != nil && (value > *schema.Maximum || value == *schema.Maximum && schema.ExclusiveMaximum):
+		value = *schema.Maximum
+		if step > 0 {
+			value = math.Floor(value/step) * step
+		}
+
+		if value == *schema.Maximum && schema.ExclusiveMaximum {
+			value -= math.Max(step, 1)
+		}
+	}
+
+	return value
+}
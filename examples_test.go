@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExamplesFor(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "examples", "spec.yml")
+	an := New(antest.LoadOrFail(t, bp))
+
+	op, ok := an.OperationFor("PUT", "/pets/{id}")
+	require.True(t, ok)
+
+	assert.Equal(t, []Example{
+		{Pointer: "#/definitions/pet/example", Value: map[string]interface{}{"name": "rex"}},
+		{Pointer: "#/definitions/pet/properties/name/example", Value: "rex"},
+		{Pointer: "#/paths/~1pets~1{id}/parameters/0/example", Value: float64(42)},
+		{Pointer: "#/paths/~1pets~1{id}/put/parameters/1/items/example", Value: "cute"},
+		{Pointer: "#/paths/~1pets~1{id}/put/responses/200/examples/application~1json", MediaType: "application/json", Value: map[string]interface{}{"name": "rex"}},
+		{Pointer: "#/paths/~1pets~1{id}/put/responses/200/headers/X-Rate-Limit/example", Value: float64(100)},
+		{Pointer: "#/responses/error/schema/properties/message/example", Value: "not found"},
+	}, an.ExamplesFor(op))
+
+	assert.Empty(t, an.ExamplesFor(nil))
+}
+
+func TestSynthesizeExample(t *testing.T) {
+	t.Parallel()
+
+	for _, toPin := range []struct {
+		Title    string
+		Schema   string
+		Expected interface{}
+	}{
+		{Title: "declared example", Schema: `{"type": "string", "example": "x", "default": "y"}`, Expected: "x"},
+		{Title: "default", Schema: `{"type": "string", "default": "y", "enum": ["y", "z"]}`, Expected: "y"},
+		{Title: "enum", Schema: `{"type": "string", "enum": ["z"]}`, Expected: "z"},
+		{Title: "format", Schema: `{"type": "string", "format": "date"}`, Expected: "2021-01-01"},
+		{Title: "lengths", Schema: `{"type": "string", "minLength": 10}`, Expected: "examplexxx"},
+		{Title: "short string", Schema: `{"type": "string", "maxLength": 3}`, Expected: "exa"},
+		{Title: "pattern", Schema: `{"type": "string", "pattern": "^[0-9]+$"}`, Expected: "0"},
+		{Title: "integer", Schema: `{"type": "integer"}`, Expected: 0.0},
+		{Title: "bounds", Schema: `{"type": "integer", "minimum": 3, "exclusiveMinimum": true, "multipleOf": 2}`, Expected: 4.0},
+		{Title: "upper bound", Schema: `{"type": "number", "maximum": -1.5}`, Expected: -1.5},
+		{Title: "boolean", Schema: `{"type": "boolean"}`, Expected: true},
+		{Title: "array", Schema: `{"type": "array", "items": {"type": "integer", "minimum": 1}, "minItems": 2}`, Expected: []interface{}{1.0, 1.0}},
+		{Title: "tuple", Schema: `{"type": "array", "items": [{"type": "integer"}, {"type": "boolean"}]}`, Expected: []interface{}{0.0, true}},
+		{
+			Title:    "object",
+			Schema:   `{"required": ["id", "extra"], "properties": {"id": {"type": "integer"}}, "additionalProperties": {"type": "string"}}`,
+			Expected: map[string]interface{}{"id": 0.0, "extra": "example"},
+		},
+		{
+			Title:    "allOf",
+			Schema:   `{"allOf": [{"type": "string"}, {"maxLength": 2}]}`,
+			Expected: "ex",
+		},
+		{Title: "anyOf", Schema: `{"anyOf": [{"type": "boolean"}, {"type": "string"}]}`, Expected: true},
+		{Title: "$ref", Schema: `{"$ref": "#/definitions/pet"}`, Expected: nil},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.Title, func(t *testing.T) {
+			t.Parallel()
+
+			schema := schemaFromJSON(t, fixture.Schema)
+			example := SynthesizeExample(schema)
+			assert.Equal(t, fixture.Expected, example)
+
+			if fixture.Expected != nil {
+				keyword, decided := rejectingKeyword(schemaAsJSON(schema), example)
+				if decided {
+					assert.Empty(t, keyword)
+				}
+			}
+		})
+	}
+
+	assert.Nil(t, SynthesizeExample(nil))
+	assert.Regexp(t, regexp.MustCompile(`^\d{4}-`), SynthesizeExample(schemaFromJSON(t, `{"type": "string", "format": "date-time"}`)))
+
+	t.Run("with huge lengths", func(t *testing.T) {
+		t.Parallel()
+
+		str, isString := SynthesizeExample(schemaFromJSON(t, `{"type": "string", "minLength": 9223372036854775807}`)).(string)
+		require.True(t, isString)
+		assert.Len(t, str, MaxSynthesizedLength)
+
+		array, isArray := SynthesizeExample(schemaFromJSON(t, `{"type": "array", "items": {"type": "integer"}, "minItems": 9223372036854775807}`)).([]interface{})
+		require.True(t, isArray)
+		assert.Len(t, array, MaxSynthesizedLength)
+
+		assert.Empty(t, SynthesizeExample(schemaFromJSON(t, `{"type": "array", "maxItems": -1}`)))
+	})
+}
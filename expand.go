@@ -0,0 +1,368 @@
+package analysis
+
+import (
+	"encoding/json"
+	slashpath "path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/analysis/internal/flatten/normalize"
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// ExpandOpts configures the selective expansion of $ref's with ExpandRefs
+type ExpandOpts struct {
+	// BasePath is the location of the spec, used to resolve relative remote $ref's
+	BasePath string
+
+	// MaxDepth limits the expansion of nested schema $ref's: the $ref's found in a schema which has been
+	// reached through MaxDepth $ref's are retained. Zero means no limit.
+	MaxDepth int
+
+	// Only restricts the expansion to the $ref's located under some JSON pointers (e.g. "#/paths", "#/responses").
+	// All $ref's are expanded when empty.
+	Only []string
+
+	// SkipDefinitions retains all schema $ref's: only $ref's to parameters, responses and path items are expanded
+	SkipDefinitions bool
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// ExpandRefs replaces $ref's with the content they refer to, like spec.ExpandSpec, but selectively:
+// the expansion may be restricted to some locations of the spec, to $ref's to parameters, responses and path items,
+// or to a bounded depth of nested schemas.
+//
+// Circular $ref's are retained. Retained $ref's found in remote documents are rebased, so they remain
+// valid from the expanded spec.
+//
+// The spec is modified in place.
+func ExpandRefs(sp *spec.Swagger, opts ExpandOpts) error {
+	if sp == nil {
+		return nil
+	}
+
+	return expandRefs(sp, opts, nil)
+}
+
+// expandRefs expands $ref's like ExpandRefs, loading remote documents with some loader (the default one when nil)
+func expandRefs(sp *spec.Swagger, opts ExpandOpts, loader func(string) (json.RawMessage, error)) error {
+	e := &refExpander{sp: sp, root: CloneSpec(sp), opts: opts, base: absoluteBasePath(opts.BasePath), loader: loader}
+
+	if err := e.expandPathItems(); err != nil {
+		return err
+	}
+
+	var err error
+	walkParameters(sp, func(pointer string, param *spec.Parameter) {
+		if err == nil {
+			err = e.expandParameter(pointer, param)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := e.expandResponses(); err != nil {
+		return err
+	}
+
+	if opts.SkipDefinitions {
+		return nil
+	}
+
+	var expanded []string // roots of the schemas already expanded, with their children
+	walkSchemas(sp, func(pointer string, schema *spec.Schema) {
+		for _, root := range expanded {
+			if strings.HasPrefix(pointer, root+"/") {
+				return
+			}
+		}
+
+		expanded = append(expanded, pointer)
+
+		var stack []string
+		if _, isDefinition := definitionOfPointer(pointer); isDefinition {
+			stack = append(stack, normalize.RebaseRef(e.base, pointer)) // a definition refers to itself
+		}
+
+		if err == nil {
+			err = e.expandSchema(pointer, schema, e.base, 0, stack)
+		}
+	})
+
+	return err
+}
+
+// absoluteBasePath makes the location of a local document absolute, so the $ref's found in remote documents
+// may be rebased consistently
+func absoluteBasePath(base string) string {
+	if base == "" || strings.Contains(base, "://") || filepath.IsAbs(base) {
+		return base
+	}
+
+	if abs, err := filepath.Abs(base); err == nil {
+		return abs
+	}
+
+	return base
+}
+
+type refExpander struct {
+	sp   *spec.Swagger
+	root *spec.Swagger // the unaltered spec, against which $ref's are resolved
+	opts ExpandOpts
+	base string // the absolute location of the spec
+
+	loader func(string) (json.RawMessage, error)
+}
+
+// inScope tells if the $ref's at some location should be expanded
+func (e *refExpander) inScope(pointer string) bool {
+	return len(e.opts.Only) == 0 || inScopes(pointer, e.opts.Only)
+}
+
+// inScopes tells if a location is found under some JSON pointers (e.g. "#/paths", "#/definitions/pet")
+func inScopes(pointer string, scopes []string) bool {
+	for _, scope := range scopes {
+		if pointer == scope || strings.HasPrefix(pointer, strings.TrimSuffix(scope, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mayContainScope tells if some location of the scope may be found under a pointer
+func (e *refExpander) mayContainScope(pointer string) bool {
+	if len(e.opts.Only) == 0 {
+		return true
+	}
+
+	if e.inScope(pointer) {
+		return true
+	}
+
+	for _, scope := range e.opts.Only {
+		if strings.HasPrefix(scope, pointer+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *refExpander) expandOpts(base string) *spec.ExpandOptions {
+	return &spec.ExpandOptions{RelativeBase: base, PathLoader: e.loader}
+}
+
+// documentOf yields the location of the document a $ref points to, relative to the document it is found in
+func (e *refExpander) documentOf(ref spec.Ref, base string) string {
+	if ref.HasFragmentOnly {
+		return base
+	}
+
+	document, _, _ := strings.Cut(ref.String(), "#")
+
+	return normalize.RebaseRef(base, document)
+}
+
+// absoluteRef rebases a $ref found in a remote document, so it may be resolved from the spec
+func (e *refExpander) absoluteRef(ref spec.Ref, base string) spec.Ref {
+	if base == e.base {
+		return ref
+	}
+
+	return spec.MustCreateRef(normalize.RebaseRef(base, ref.String()))
+}
+
+func (e *refExpander) expandPathItems() error {
+	if e.sp.Paths == nil {
+		return nil
+	}
+
+	for _, path := range sortedKeys(e.sp.Paths.Paths) {
+		pathItem := e.sp.Paths.Paths[path]
+		pointer := "#/paths/" + jsonpointer.Escape(path)
+		if pathItem.Ref.String() == "" || !e.inScope(pointer) {
+			continue
+		}
+
+		resolved, err := spec.ResolvePathItemWithBase(e.root, pathItem.Ref, e.expandOpts(e.base))
+		if err != nil {
+			return &RefError{Pointer: pointer, Ref: pathItem.Ref.String(), Cause: err}
+		}
+
+		e.rebasePathItem(resolved, e.documentOf(pathItem.Ref, e.base))
+		e.sp.Paths.Paths[path] = *resolved
+	}
+
+	return nil
+}
+
+func (e *refExpander) expandParameter(pointer string, param *spec.Parameter) error {
+	if param.Ref.String() == "" || !e.inScope(pointer) {
+		return nil
+	}
+
+	resolved, err := spec.ResolveParameterWithBase(e.root, param.Ref, e.expandOpts(e.base))
+	if err != nil {
+		return &RefError{Pointer: pointer, Ref: param.Ref.String(), Cause: err}
+	}
+
+	if resolved.Schema != nil {
+		e.rebaseSchema(resolved.Schema, e.documentOf(param.Ref, e.base))
+	}
+
+	*param = *resolved
+
+	return nil
+}
+
+func (e *refExpander) expandResponses() error {
+	expand := func(pointer string, resp *spec.Response) error {
+		if resp.Ref.String() == "" || !e.inScope(pointer) {
+			return nil
+		}
+
+		resolved, err := spec.ResolveResponseWithBase(e.root, resp.Ref, e.expandOpts(e.base))
+		if err != nil {
+			return &RefError{Pointer: pointer, Ref: resp.Ref.String(), Cause: err}
+		}
+
+		if resolved.Schema != nil {
+			e.rebaseSchema(resolved.Schema, e.documentOf(resp.Ref, e.base))
+		}
+
+		*resp = *resolved
+
+		return nil
+	}
+
+	for _, name := range sortedKeys(e.sp.Responses) {
+		resp := e.sp.Responses[name]
+		if err := expand("#/responses/"+jsonpointer.Escape(name), &resp); err != nil {
+			return err
+		}
+		e.sp.Responses[name] = resp
+	}
+
+	var err error
+	walkOperations(e.sp, func(pointer string, op *spec.Operation) {
+		if err != nil || op.Responses == nil {
+			return
+		}
+
+		if op.Responses.Default != nil {
+			err = expand("#"+slashpath.Join(pointer, "responses", "default"), op.Responses.Default)
+		}
+
+		for _, code := range sortedStatusCodes(op.Responses.StatusCodeResponses) {
+			resp := op.Responses.StatusCodeResponses[code]
+			if err == nil {
+				err = expand("#"+slashpath.Join(pointer, "responses", strconv.Itoa(code)), &resp)
+			}
+			op.Responses.StatusCodeResponses[code] = resp
+		}
+	})
+
+	return err
+}
+
+// expandSchema expands the $ref's of a schema and its children.
+//
+// The base is the location of the document the schema comes from, and the stack holds the $ref's being expanded.
+func (e *refExpander) expandSchema(pointer string, schema *spec.Schema, base string, depth int, stack []string) error {
+	if !e.mayContainScope(pointer) {
+		return nil
+	}
+
+	if ref := schema.Ref; ref.String() != "" && e.inScope(pointer) {
+		absolute := e.absoluteRef(ref, base)
+		key := normalize.RebaseRef(base, ref.String())
+		if (e.opts.MaxDepth > 0 && depth >= e.opts.MaxDepth) || containsString(stack, key) {
+			schema.Ref = absolute
+
+			return nil
+		}
+
+		resolved, err := spec.ResolveRefWithBase(e.root, &absolute, e.expandOpts(e.base))
+		if err != nil {
+			return &RefError{Pointer: pointer, Ref: ref.String(), Cause: err}
+		}
+
+		*schema = *resolved
+		base = e.documentOf(absolute, base)
+		stack = append(stack, key)
+		depth++
+	}
+
+	var err error
+	forEachSubSchema(schema, func(suffix string, child *spec.Schema) {
+		if err == nil {
+			err = e.expandSchema(slashpath.Join(pointer, suffix), child, base, depth, stack)
+		}
+	})
+
+	return err
+}
+
+// rebaseSchema rebases the $ref's of a schema found in a remote document
+func (e *refExpander) rebaseSchema(schema *spec.Schema, base string) {
+	walkSchema("", schema, func(_ string, sch *spec.Schema) {
+		if sch.Ref.String() != "" {
+			sch.Ref = e.absoluteRef(sch.Ref, base)
+		}
+	})
+}
+
+// rebasePathItem rebases the $ref's of a path item found in a remote document
+func (e *refExpander) rebasePathItem(pathItem *spec.PathItem, base string) {
+	if base == e.base {
+		return
+	}
+
+	rebaseParams := func(params []spec.Parameter) {
+		for i := range params {
+			if params[i].Ref.String() != "" {
+				params[i].Ref = e.absoluteRef(params[i].Ref, base)
+			}
+
+			if params[i].Schema != nil {
+				e.rebaseSchema(params[i].Schema, base)
+			}
+		}
+	}
+
+	rebaseResponse := func(resp *spec.Response) {
+		if resp.Ref.String() != "" {
+			resp.Ref = e.absoluteRef(resp.Ref, base)
+		}
+
+		if resp.Schema != nil {
+			e.rebaseSchema(resp.Schema, base)
+		}
+	}
+
+	rebaseParams(pathItem.Parameters)
+	for _, method := range sortedOperationMethods(pathItem) {
+		op := operationOf(pathItem, method)
+		rebaseParams(op.Parameters)
+
+		if op.Responses == nil {
+			continue
+		}
+
+		if op.Responses.Default != nil {
+			rebaseResponse(op.Responses.Default)
+		}
+
+		for code, resp := range op.Responses.StatusCodeResponses {
+			rebaseResponse(&resp)
+			op.Responses.StatusCodeResponses[code] = resp
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package analysis
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandRefs(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "expand", "spec.yml")
+	sp := antest.LoadOrFail(t, bp)
+	require.NoError(t, ExpandRefs(sp, ExpandOpts{BasePath: bp}))
+
+	// only circular $ref's are retained
+	assert.ElementsMatch(t, []string{
+		"#/definitions/owner/properties/pets/items/properties/owner",
+		"#/definitions/pet/properties/owner/properties/pets/items",
+		"#/parameters/pet/schema/properties/owner/properties/pets/items",
+		"#/paths/~1pets/get/responses/200/schema/items/properties/owner/properties/pets/items",
+		"#/paths/~1pets/post/parameters/0/schema/properties/owner/properties/pets/items",
+	}, schemaRefPointers(sp))
+
+	get := sp.Paths.Paths["/pets"].Get
+	assert.Equal(t, "limit", get.Parameters[0].Name)
+	assert.Equal(t, "error", get.Responses.Default.Description)
+	assert.Contains(t, get.Responses.Default.Schema.Properties, "message")
+
+	receipt := sp.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses[201].Schema
+	assert.True(t, receipt.Properties["tag"].Type.Contains("string"))
+
+	owners := sp.Paths.Paths["/owners"].Get
+	require.NotNil(t, owners)
+	assert.True(t, owners.Responses.StatusCodeResponses[200].Schema.Items.Schema.Type.Contains("string"))
+}
+
+func TestExpandRefs_Selective(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "expand", "spec.yml")
+
+	t.Run("with parameters and responses only", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, ExpandRefs(sp, ExpandOpts{BasePath: bp, Only: []string{"#/paths/~1pets"}, SkipDefinitions: true}))
+
+		pets := sp.Paths.Paths["/pets"]
+		assert.Equal(t, "limit", pets.Get.Parameters[0].Name)
+		assert.Equal(t, "#/definitions/error", pets.Get.Responses.Default.Schema.Ref.String())
+		assert.Equal(t, "#/definitions/pet", pets.Post.Parameters[0].Schema.Ref.String())
+		assert.Equal(t, "#/definitions/pet", pets.Get.Responses.StatusCodeResponses[200].Schema.Items.Schema.Ref.String())
+
+		// out of scope
+		assert.Equal(t, "paths.yml#/owners", pathItemRef(sp.Paths.Paths["/owners"]))
+	})
+
+	t.Run("with bounded depth", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, ExpandRefs(sp, ExpandOpts{BasePath: bp, MaxDepth: 1, Only: []string{"#/paths"}}))
+
+		pets := sp.Paths.Paths["/pets"]
+		pet := pets.Get.Responses.StatusCodeResponses[200].Schema.Items.Schema
+		assert.Empty(t, pet.Ref.String())
+		assert.Equal(t, "#/definitions/owner", schemaRef(pet.Properties["owner"]))
+
+		// retained $ref's from remote documents are rebased
+		receipt := pets.Post.Responses.StatusCodeResponses[201].Schema
+		tag := schemaRef(receipt.Properties["tag"])
+		assert.True(t, strings.HasSuffix(filepath.ToSlash(tag), "fixtures/expand/models.yml#/definitions/tag"), tag)
+
+		// definitions are out of scope
+		assert.Equal(t, "#/definitions/owner", schemaRef(sp.Definitions["pet"].Properties["owner"]))
+	})
+
+	t.Run("with unresolved $ref", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		sp.Definitions["dangling"] = *spec.RefSchema("#/definitions/nowhere")
+
+		err := ExpandRefs(sp, ExpandOpts{BasePath: bp})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "#/definitions/dangling")
+	})
+
+	require.NoError(t, ExpandRefs(nil, ExpandOpts{}))
+}
+
+func schemaRefPointers(sp *spec.Swagger) []string {
+	var pointers []string
+	walkSchemas(sp, func(pointer string, schema *spec.Schema) {
+		if schema.Ref.String() != "" {
+			pointers = append(pointers, pointer)
+		}
+	})
+
+	return pointers
+}
+
+func pathItemRef(pathItem spec.PathItem) string {
+	return pathItem.Ref.String()
+}
@@ -0,0 +1,109 @@
+package analysis
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// Codes for the findings reporting what the exporters of definitions to other schema languages cannot map
+const (
+	CodeUnmappableMap          = "unmappable-map"
+	CodeUnmappableTuple        = "unmappable-tuple"
+	CodeUnmappableName         = "unmappable-name"
+	CodeUnmappablePolymorphism = "unmappable-polymorphism"
+	CodeUnmappableRef          = "unmappable-ref"
+)
+
+var rexIdentifier = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// objectField is a property of an object schema, as exported to another schema language
+type objectField struct {
+	name     string
+	pointer  string
+	schema   *spec.Schema
+	required bool
+
+	// owner is the name of the type which declares the field, after which inline types are named
+	owner string
+}
+
+// exportedTypeName yields the name of an exported type, e.g. "Pet" for a "pet" definition
+func exportedTypeName(name string) string {
+	typeName := swag.ToGoName(name)
+	if !rexIdentifier.MatchString(typeName) {
+		typeName = "_" + typeName
+	}
+
+	return typeName
+}
+
+// collectObjectFields gathers the properties of an object, including those inherited with allOf,
+// and the definitions with a discriminator it extends
+func collectObjectFields(sp *spec.Swagger, pointer, owner string, schema *spec.Schema, fields *[]objectField, bases *[]string, seen map[string]bool) {
+	for i := range schema.AllOf {
+		member := &schema.AllOf[i]
+		memberPointer := pointer + "/allOf/" + strconv.Itoa(i)
+
+		if ref := member.Ref.String(); ref != "" {
+			def, isDefinition := definitionOfPointer(ref)
+			target, found := sp.Definitions[def]
+			if !isDefinition || !found || seen[def] {
+				continue
+			}
+			seen[def] = true
+
+			if a, err := Schema(SchemaOpts{Schema: &target, Root: sp}); err == nil && a.IsBaseType {
+				*bases = append(*bases, def)
+			}
+			collectObjectFields(sp, definitionsPrefix+jsonpointer.Escape(def), exportedTypeName(def), &target, fields, bases, seen)
+
+			continue
+		}
+
+		collectObjectFields(sp, memberPointer, owner, member, fields, bases, seen)
+	}
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	for _, name := range sortedKeys(schema.Properties) {
+		property := schema.Properties[name]
+		field := objectField{
+			name:     name,
+			pointer:  pointer + "/properties/" + jsonpointer.Escape(name),
+			schema:   &property,
+			required: required[name],
+			owner:    owner,
+		}
+
+		replaced := false
+		for i := range *fields {
+			if (*fields)[i].name == name {
+				(*fields)[i] = field
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			*fields = append(*fields, field)
+		}
+	}
+}
+
+// appendFindingOnce appends a finding, unless it is already reported: the schemas of definitions
+// may be visited several times, e.g. when inherited
+func appendFindingOnce(findings []Finding, finding Finding) []Finding {
+	for _, reported := range findings {
+		if reported.Pointer == finding.Pointer && reported.Code == finding.Code && reported.Message == finding.Message {
+			return findings
+		}
+	}
+
+	return append(findings, finding)
+}
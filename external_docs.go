@@ -0,0 +1,175 @@
+package analysis
+
+import (
+	gocontext "context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+// DefaultLinkCheckTimeout is the default timeout of each request made by Spec.CheckLinks
+const DefaultLinkCheckTimeout = 10 * time.Second
+
+// ErrMalformedLink is reported by Spec.CheckLinks for a URL which is not an absolute http or https URL
+var ErrMalformedLink = errors.New("malformed link")
+
+// ExternalDocsRef locates a link to some documentation in the spec
+type ExternalDocsRef struct {
+	Pointer     string `json:"pointer"` // e.g. "#/tags/0/externalDocs", "#/info/termsOfService"
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// AllExternalDocs returns the externalDocs of the spec, its tags, operations and schemas, in document order
+func (s *Spec) AllExternalDocs() []ExternalDocsRef {
+	var result []ExternalDocsRef
+	add := func(pointer string, docs *spec.ExternalDocumentation) {
+		if docs != nil {
+			result = append(result, ExternalDocsRef{Pointer: pointer, URL: docs.URL, Description: docs.Description})
+		}
+	}
+
+	add("#/externalDocs", s.spec.ExternalDocs)
+
+	for i, tag := range s.spec.Tags {
+		add("#/tags/"+strconv.Itoa(i)+"/externalDocs", tag.ExternalDocs)
+	}
+
+	walkOperations(s.spec, func(pointer string, op *spec.Operation) {
+		add("#"+pointer+"/externalDocs", op.ExternalDocs)
+	})
+
+	walkSchemas(s.spec, func(pointer string, schema *spec.Schema) {
+		add(pointer+"/externalDocs", schema.ExternalDocs)
+	})
+
+	return result
+}
+
+// LinkCheckOpts configures the verification of links by Spec.CheckLinks
+type LinkCheckOpts struct {
+	// Client sends the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// Timeout limits the duration of each request. Defaults to DefaultLinkCheckTimeout.
+	Timeout time.Duration
+
+	// Offline only verifies that links are well-formed, without sending any request
+	Offline bool
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// LinkCheckResult is the outcome of the verification of a link
+type LinkCheckResult struct {
+	ExternalDocsRef
+
+	// StatusCode is the status of the response to the request made to the link, or zero when no response was received
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Err is the reason why the link is broken, or nil when the link works
+	Err error `json:"-"`
+}
+
+// OK tells if the link works
+func (r LinkCheckResult) OK() bool {
+	return r.Err == nil
+}
+
+// CheckLinks verifies the externalDocs of the spec (see AllExternalDocs) and the terms of service of its info.
+//
+// Links must be absolute http or https URLs. Unless opts.Offline is set, a HEAD request is made to each link
+// (or a GET request, when HEAD is not supported by the server): links which do not respond with a successful
+// status are broken. Each URL is requested once, even when it is used several times.
+//
+// The context cancels all pending requests.
+func (s *Spec) CheckLinks(ctx gocontext.Context, opts LinkCheckOpts) []LinkCheckResult {
+	links := s.AllExternalDocs()
+	if s.spec.Info != nil && s.spec.Info.TermsOfService != "" {
+		links = append([]ExternalDocsRef{{Pointer: "#/info/termsOfService", URL: s.spec.Info.TermsOfService}}, links...)
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultLinkCheckTimeout
+	}
+
+	type outcome struct {
+		status int
+		err    error
+	}
+	checked := make(map[string]outcome, len(links))
+
+	results := make([]LinkCheckResult, 0, len(links))
+	for _, link := range links {
+		checkedLink, done := checked[link.URL]
+		if !done {
+			if err := wellFormedLink(link.URL); err != nil || opts.Offline {
+				checkedLink = outcome{err: err}
+			} else {
+				checkedLink.status, checkedLink.err = checkLink(ctx, client, timeout, link.URL)
+			}
+			checked[link.URL] = checkedLink
+		}
+
+		results = append(results, LinkCheckResult{ExternalDocsRef: link, StatusCode: checkedLink.status, Err: checkedLink.err})
+	}
+
+	return results
+}
+
+func wellFormedLink(link string) error {
+	u, err := url.Parse(link)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedLink, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%w: %q is not an absolute http or https URL", ErrMalformedLink, link)
+	}
+
+	return nil
+}
+
+// checkLink requests a link, and yields the status of the response
+func checkLink(ctx gocontext.Context, client *http.Client, timeout time.Duration, link string) (int, error) {
+	status, err := requestLink(ctx, client, timeout, http.MethodHead, link)
+	if status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented {
+		status, err = requestLink(ctx, client, timeout, http.MethodGet, link)
+	}
+
+	return status, err
+}
+
+func requestLink(ctx gocontext.Context, client *http.Client, timeout time.Duration, method, link string) (int, error) {
+	ctx, cancel := gocontext.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp.StatusCode, fmt.Errorf("%s %s: %s", method, link, resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,97 @@
+package analysis
+
+import (
+	gocontext "context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_AllExternalDocs(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "external_docs.yml"))
+
+	assert.Equal(t, []ExternalDocsRef{
+		{Pointer: "#/externalDocs", URL: "https://example.com/guide", Description: "the guide"},
+		{Pointer: "#/tags/0/externalDocs", URL: "https://example.com/pets"},
+		{Pointer: "#/paths/~1pets/get/externalDocs", URL: "/relative/docs"},
+		{Pointer: "#/definitions/pet/externalDocs", URL: "https://example.com/pet"},
+		{Pointer: "#/definitions/pet/properties/owner/externalDocs", URL: "https://example.com/owner"},
+	}, New(sp).AllExternalDocs())
+
+	assert.Empty(t, New(&spec.Swagger{}).AllExternalDocs())
+}
+
+func TestAnalyzer_CheckLinks(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		switch r.URL.Path {
+		case "/ok":
+		case "/get-only":
+			if r.Method != http.MethodGet {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Info: &spec.Info{InfoProps: spec.InfoProps{TermsOfService: server.URL + "/ok"}},
+		Tags: []spec.Tag{
+			{TagProps: spec.TagProps{Name: "pets", ExternalDocs: &spec.ExternalDocumentation{URL: server.URL + "/get-only"}}},
+			{TagProps: spec.TagProps{Name: "stores", ExternalDocs: &spec.ExternalDocumentation{URL: server.URL + "/missing"}}},
+			{TagProps: spec.TagProps{Name: "users", ExternalDocs: &spec.ExternalDocumentation{URL: "docs/users"}}},
+			{TagProps: spec.TagProps{Name: "orders", ExternalDocs: &spec.ExternalDocumentation{URL: server.URL + "/slow"}}},
+			{TagProps: spec.TagProps{Name: "legal", ExternalDocs: &spec.ExternalDocumentation{URL: server.URL + "/ok"}}},
+		},
+	}}
+
+	results := New(sp).CheckLinks(gocontext.Background(), LinkCheckOpts{Client: server.Client(), Timeout: 50 * time.Millisecond})
+	require.Len(t, results, 6)
+
+	assert.Equal(t, "#/info/termsOfService", results[0].Pointer)
+	assert.True(t, results[0].OK())
+	assert.Equal(t, http.StatusOK, results[0].StatusCode)
+
+	assert.True(t, results[1].OK(), "should fall back to GET")
+
+	assert.False(t, results[2].OK())
+	assert.Equal(t, http.StatusNotFound, results[2].StatusCode)
+
+	assert.True(t, errors.Is(results[3].Err, ErrMalformedLink))
+	assert.Zero(t, results[3].StatusCode)
+
+	assert.True(t, errors.Is(results[4].Err, gocontext.DeadlineExceeded))
+
+	assert.True(t, results[5].OK())
+	assert.Equal(t, "#/tags/4/externalDocs", results[5].Pointer)
+	assert.Equal(t, int32(5), requests.Load(), "should request each URL once, and retry with GET")
+
+	t.Run("should only verify that links are well-formed when offline", func(t *testing.T) {
+		before := requests.Load()
+
+		results := New(sp).CheckLinks(gocontext.Background(), LinkCheckOpts{Offline: true})
+		require.Len(t, results, 6)
+		assert.True(t, results[2].OK())
+		assert.False(t, results[3].OK())
+		assert.Equal(t, before, requests.Load())
+	})
+}
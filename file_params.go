@@ -0,0 +1,105 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/go-openapi/spec"
+)
+
+// FileParameter is a parameter carrying a file: a formData parameter with "type: file", or a parameter
+// (e.g. a body parameter) with a binary string
+type FileParameter struct {
+	Parameter spec.Parameter
+
+	// Format is the format of a binary string ("binary" or "byte"). It is empty for a parameter with "type: file".
+	Format string
+}
+
+// FileParameters describes the files uploaded with an operation
+type FileParameters struct {
+	// Parameters carrying files, sorted by location and name
+	Parameters []FileParameter
+
+	// Multipart is true when the operation consumes multipart content (e.g. "multipart/form-data")
+	Multipart bool
+}
+
+// FileParametersFor identifies the parameters of an operation carrying files: formData parameters with
+// "type: file", and parameters or body schemas with a string of format "binary" or "byte".
+//
+// Parameters declared on the path item of the operation are included, and $ref's to shared parameters
+// or to definitions are resolved. Parameters which cannot be resolved are skipped.
+func (s *Spec) FileParametersFor(operation *spec.Operation) FileParameters {
+	var result FileParameters
+	if operation == nil {
+		return result
+	}
+
+	skip := func(spec.Parameter, error) bool { return true }
+	params := make(map[string]spec.Parameter)
+	if method, path, found := s.methodPathOf(operation); found {
+		params = s.SafeParamsFor(method, path, skip)
+	} else {
+		s.paramsAsMap(operation.Parameters, params, skip)
+	}
+
+	for _, key := range sortedKeys(params) {
+		param := params[key]
+		if format, isFile := s.fileFormatOf(param); isFile {
+			result.Parameters = append(result.Parameters, FileParameter{Parameter: param, Format: format})
+		}
+	}
+
+	sort.SliceStable(result.Parameters, func(i, j int) bool {
+		pi, pj := result.Parameters[i].Parameter, result.Parameters[j].Parameter
+		if pi.In != pj.In {
+			return pi.In < pj.In
+		}
+
+		return pi.Name < pj.Name
+	})
+
+	result.Multipart = s.ConsumedContentTypes(operation).Multipart
+
+	return result
+}
+
+// fileFormatOf tells if a parameter carries a file, and with which string format
+func (s *Spec) fileFormatOf(param spec.Parameter) (string, bool) {
+	if param.In == "body" {
+		schema := s.resolveSchema(param.Schema)
+		if schema == nil {
+			return "", false
+		}
+
+		if schema.Type.Contains("file") {
+			return "", true
+		}
+
+		return schema.Format, schema.Type.Contains("string") && isBinaryFormat(schema.Format)
+	}
+
+	if param.Type == "file" {
+		return "", true
+	}
+
+	return param.Format, param.Type == "string" && isBinaryFormat(param.Format)
+}
+
+// isBinaryFormat tells if a string format denotes binary content
+func isBinaryFormat(format string) bool {
+	return format == "binary" || format == "byte"
+}
+
+// methodPathOf finds the method and path of an operation of the spec
+func (s *Spec) methodPathOf(operation *spec.Operation) (string, string, bool) {
+	for _, method := range sortedKeys(s.operations) {
+		for _, path := range sortedKeys(s.operations[method]) {
+			if s.operations[method][path] == operation {
+				return method, path, true
+			}
+		}
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileParametersFor(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "content_types.yml"))
+	an := New(sp)
+
+	names := func(params FileParameters) map[string]string {
+		result := make(map[string]string, len(params.Parameters))
+		for _, param := range params.Parameters {
+			result[param.Parameter.In+"#"+param.Parameter.Name] = param.Format
+		}
+
+		return result
+	}
+
+	createPet, ok := an.OperationFor("POST", "/pets")
+	require.True(t, ok)
+	params := an.FileParametersFor(createPet)
+	assert.True(t, params.Multipart)
+	require.Len(t, params.Parameters, 2)
+	assert.Equal(t, "photo", params.Parameters[0].Parameter.Name)
+	assert.Equal(t, map[string]string{"formData#photo": "", "formData#thumbnail": "byte"}, names(params))
+
+	putPhoto, ok := an.OperationFor("PUT", "/pets/{id}/photo")
+	require.True(t, ok)
+	params = an.FileParametersFor(putPhoto)
+	assert.False(t, params.Multipart)
+	assert.Equal(t, map[string]string{"body#photo": "binary"}, names(params))
+
+	listPets, ok := an.OperationFor("GET", "/pets")
+	require.True(t, ok)
+	assert.Empty(t, an.FileParametersFor(listPets).Parameters)
+
+	// an operation which is not part of the spec
+	upload := &spec.Operation{OperationProps: spec.OperationProps{
+		Consumes: []string{"multipart/form-data"},
+		Parameters: []spec.Parameter{
+			*spec.FileParam("attachment"),
+			*spec.QueryParam("name").Typed("string", ""),
+		},
+	}}
+	params = an.FileParametersFor(upload)
+	assert.True(t, params.Multipart)
+	assert.Equal(t, map[string]string{"formData#attachment": ""}, names(params))
+}
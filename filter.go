@@ -0,0 +1,521 @@
+package analysis
+
+import (
+	"fmt"
+	slashpath "path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+const defaultFilterExtension = "x-internal"
+
+// FilterOpts configures the removal of the parts of a spec marked with a vendor extension
+type FilterOpts struct {
+	// Extension marking elements to remove. Defaults to "x-internal".
+	Extension string
+
+	// Audiences to remove. When empty, elements are removed if their extension is true
+	// (e.g. "x-internal: true"). Otherwise, elements are removed when their extension is one of these
+	// values, or a list containing any of these values (e.g. "x-audience: [partner, internal]").
+	Audiences []string
+
+	_ struct{}
+}
+
+// marks tells if some vendor extensions mark an element for removal
+func (o FilterOpts) marks(extensions spec.Extensions) bool {
+	value, ok := lookupExtension(extensions, o.Extension)
+	if !ok {
+		return false
+	}
+
+	if len(o.Audiences) == 0 {
+		switch v := value.(type) {
+		case bool:
+			return v
+		case string:
+			return strings.EqualFold(v, "true")
+		default:
+			return false
+		}
+	}
+
+	var values []interface{}
+	if list, isList := value.([]interface{}); isList {
+		values = list
+	} else {
+		values = []interface{}{value}
+	}
+
+	for _, v := range values {
+		for _, audience := range o.Audiences {
+			if fmt.Sprintf("%v", v) == audience {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Filter removes operations, path items, parameters, properties and definitions marked with a vendor extension
+// (by default, "x-internal: true"), so that several variants of an API (e.g. public and internal) may be
+// published from a single source spec.
+//
+// Elements referring to a removed definition or parameter are removed as well, so the filtered spec still resolves:
+// the closest property, parameter, response or definition holding the $ref (e.g. the property holding an array
+// of removed items), or the operation when none of its responses remains. $ref's which were already broken before
+// filtering are left unchanged. Definitions which are no longer reachable after filtering, and path items left
+// without any operation are removed too.
+//
+// The spec is modified in place. Filter returns the JSON pointers to all removed elements, sorted.
+func Filter(sp *spec.Swagger, opts FilterOpts) []string {
+	if opts.Extension == "" {
+		opts.Extension = defaultFilterExtension
+	}
+
+	reachableBefore := reachableDefinitions(sp)
+	components := componentPointers(sp)
+
+	var removed []string
+	removedParams := make(map[string]bool)
+
+	for _, name := range sortedKeys(sp.Definitions) {
+		if opts.marks(sp.Definitions[name].Extensions) {
+			delete(sp.Definitions, name)
+			removed = append(removed, slashpath.Join(definitionsPath, jsonpointer.Escape(name)))
+		}
+	}
+
+	for _, name := range sortedKeys(sp.Parameters) {
+		if opts.marks(sp.Parameters[name].Extensions) {
+			delete(sp.Parameters, name)
+			ref := "#/parameters/" + jsonpointer.Escape(name)
+			removedParams[ref] = true
+			removed = append(removed, ref)
+		}
+	}
+
+	keepParam := func(_ string, param spec.Parameter) bool {
+		return !opts.marks(param.Extensions) && !removedParams[param.Ref.String()]
+	}
+
+	if sp.Paths != nil {
+		for _, pth := range sortedKeys(sp.Paths.Paths) {
+			pathItem := sp.Paths.Paths[pth]
+			prefix := "#/paths/" + jsonpointer.Escape(pth)
+
+			if opts.marks(pathItem.Extensions) {
+				delete(sp.Paths.Paths, pth)
+				removed = append(removed, prefix)
+
+				continue
+			}
+
+			hadOperations := len(sortedOperationMethods(&pathItem)) > 0
+			pathItem.Parameters, removed = filterParameters(pathItem.Parameters, prefix, keepParam, removed)
+
+			for _, method := range sortedOperationMethods(&pathItem) {
+				op := operationOf(&pathItem, method)
+				opPrefix := slashpath.Join(prefix, strings.ToLower(method))
+				if opts.marks(op.Extensions) {
+					setOperation(&pathItem, method, nil)
+					removed = append(removed, opPrefix)
+
+					continue
+				}
+
+				op.Parameters, removed = filterParameters(op.Parameters, opPrefix, keepParam, removed)
+			}
+
+			if hadOperations && len(sortedOperationMethods(&pathItem)) == 0 {
+				delete(sp.Paths.Paths, pth)
+				removed = append(removed, prefix)
+
+				continue
+			}
+
+			sp.Paths.Paths[pth] = pathItem
+		}
+	}
+
+	walkSchemas(sp, func(pointer string, schema *spec.Schema) {
+		for _, name := range sortedKeys(schema.Properties) {
+			property := schema.Properties[name]
+			if !opts.marks(property.Extensions) {
+				continue
+			}
+
+			delete(schema.Properties, name)
+			schema.Required = removeString(schema.Required, name)
+			removed = append(removed, slashpath.Join(pointer, "properties", jsonpointer.Escape(name)))
+		}
+	})
+
+	removed = pruneDanglingRefs(sp, components, removed)
+
+	reachableAfter := reachableDefinitions(sp)
+	for name := range reachableBefore {
+		if _, exists := sp.Definitions[name]; exists && !reachableAfter[name] {
+			delete(sp.Definitions, name)
+			removed = append(removed, slashpath.Join(definitionsPath, jsonpointer.Escape(name)))
+		}
+	}
+
+	sort.Strings(removed)
+
+	return removed
+}
+
+// pruneDanglingRefs removes the elements holding $ref's to definitions, parameters or responses which have been
+// removed (i.e. which are among the components which existed before filtering, but no longer exist), until no such
+// $ref remains. $ref's which were already dangling before filtering are left unchanged.
+//
+// It appends the pointers to the removed elements.
+func pruneDanglingRefs(sp *spec.Swagger, components map[string]bool, removed []string) []string {
+	for {
+		owners := danglingRefOwners(sp, components)
+		if len(owners) == 0 {
+			return removed
+		}
+
+		count := len(removed)
+		removed = removeOwners(sp, owners, removed)
+		if len(removed) == count {
+			return removed // nothing to remove: the $ref's are left dangling
+		}
+	}
+}
+
+// componentPointers lists the pointers to the shared definitions, parameters and responses of a spec
+func componentPointers(sp *spec.Swagger) map[string]bool {
+	components := make(map[string]bool, len(sp.Definitions)+len(sp.Parameters)+len(sp.Responses))
+	for name := range sp.Definitions {
+		components[definitionsPrefix+jsonpointer.Escape(name)] = true
+	}
+	for name := range sp.Parameters {
+		components["#/parameters/"+jsonpointer.Escape(name)] = true
+	}
+	for name := range sp.Responses {
+		components["#/responses/"+jsonpointer.Escape(name)] = true
+	}
+
+	return components
+}
+
+// danglingRefOwners collects the pointers to the elements holding $ref's to components which have been removed,
+// i.e. which were among the components given, but no longer exist
+func danglingRefOwners(sp *spec.Swagger, components map[string]bool) map[string]bool {
+	owners := make(map[string]bool)
+	isDangling := func(ref spec.Ref) bool {
+		component, isComponent := componentOfPointer(ref.String())
+		if !isComponent || !components[component] {
+			return false
+		}
+
+		section, name, _ := strings.Cut(strings.TrimPrefix(component, "#/"), "/")
+		name = jsonpointer.Unescape(name)
+		switch section {
+		case "definitions":
+			_, exists := sp.Definitions[name]
+
+			return !exists
+		case "parameters":
+			_, exists := sp.Parameters[name]
+
+			return !exists
+		case "responses":
+			_, exists := sp.Responses[name]
+
+			return !exists
+		}
+
+		return false
+	}
+	addOwner := func(pointer string) {
+		if owner := refOwner(pointer); owner != "" {
+			owners[owner] = true
+		}
+	}
+
+	walkSchemas(sp, func(pointer string, schema *spec.Schema) {
+		if isDangling(schema.Ref) {
+			addOwner(pointer)
+		}
+	})
+
+	walkParameters(sp, func(pointer string, param *spec.Parameter) {
+		if isDangling(param.Ref) {
+			addOwner(pointer)
+		}
+	})
+
+	walkOperations(sp, func(pointer string, op *spec.Operation) {
+		if op.Responses == nil {
+			return
+		}
+
+		if op.Responses.Default != nil && isDangling(op.Responses.Default.Ref) {
+			addOwner("#" + slashpath.Join(pointer, "responses", "default"))
+		}
+
+		for code, resp := range op.Responses.StatusCodeResponses {
+			if isDangling(resp.Ref) {
+				addOwner("#" + slashpath.Join(pointer, "responses", strconv.Itoa(code)))
+			}
+		}
+	})
+
+	return owners
+}
+
+// refOwner yields the pointer to the closest element which may be removed with a $ref found at some location:
+// a property, a parameter, a response or a shared component.
+func refOwner(pointer string) string {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "#/"), "/")
+	owner := func(n int) string { return "#/" + strings.Join(tokens[:n], "/") }
+
+	var (
+		found string
+		i     int
+	)
+	switch {
+	case len(tokens) >= 2 && (tokens[0] == "definitions" || tokens[0] == "parameters" || tokens[0] == "responses"):
+		found, i = owner(2), 2
+	case len(tokens) >= 4 && tokens[0] == "paths" && tokens[2] == "parameters":
+		found, i = owner(4), 4
+	case len(tokens) >= 5 && tokens[0] == "paths" && (tokens[3] == "parameters" || tokens[3] == "responses"):
+		found, i = owner(5), 5
+	default:
+		return ""
+	}
+
+	if i < len(tokens) && tokens[i] == "schema" {
+		i++
+	}
+
+	// properties within the schema
+	for i < len(tokens) {
+		switch tokens[i] {
+		case "properties":
+			if i+1 < len(tokens) {
+				found = owner(i + 2)
+			}
+			i += 2
+		case "patternProperties", "definitions", "allOf", "anyOf", "oneOf":
+			i += 2
+		case "items":
+			i++
+			if i < len(tokens) {
+				if _, err := strconv.Atoi(tokens[i]); err == nil {
+					i++
+				}
+			}
+		default:
+			i++
+		}
+	}
+
+	return found
+}
+
+// removeOwners removes the elements at some pointers, as determined by refOwner
+func removeOwners(sp *spec.Swagger, owners map[string]bool, removed []string) []string {
+	for _, section := range []string{"definitions", "parameters", "responses"} {
+		prefix := "#/" + section + "/"
+		for _, owner := range sortedKeys(owners) {
+			name := strings.TrimPrefix(owner, prefix)
+			if name == owner || strings.Contains(name, "/") {
+				continue
+			}
+
+			name = jsonpointer.Unescape(name)
+			switch section {
+			case "definitions":
+				delete(sp.Definitions, name)
+			case "parameters":
+				delete(sp.Parameters, name)
+			case "responses":
+				delete(sp.Responses, name)
+			}
+			removed = append(removed, owner)
+		}
+	}
+
+	walkSchemas(sp, func(pointer string, schema *spec.Schema) {
+		for _, name := range sortedKeys(schema.Properties) {
+			property := slashpath.Join(pointer, "properties", jsonpointer.Escape(name))
+			if !owners[property] {
+				continue
+			}
+
+			delete(schema.Properties, name)
+			schema.Required = removeString(schema.Required, name)
+			removed = append(removed, property)
+		}
+	})
+
+	if sp.Paths == nil {
+		return removed
+	}
+
+	keep := func(pointer string, _ spec.Parameter) bool { return !owners[pointer] }
+	for _, pth := range sortedKeys(sp.Paths.Paths) {
+		pathItem := sp.Paths.Paths[pth]
+		prefix := "#/paths/" + jsonpointer.Escape(pth)
+		pathItem.Parameters, removed = filterParameters(pathItem.Parameters, prefix, keep, removed)
+
+		hadOperations := len(sortedOperationMethods(&pathItem)) > 0
+		for _, method := range sortedOperationMethods(&pathItem) {
+			op := operationOf(&pathItem, method)
+			opPrefix := slashpath.Join(prefix, strings.ToLower(method))
+
+			var opRemoved []string
+			op.Parameters, opRemoved = filterParameters(op.Parameters, opPrefix, keep, opRemoved)
+			if op.Responses != nil && removeResponses(op.Responses, opPrefix, owners, &opRemoved) {
+				// an operation is not left without any response
+				setOperation(&pathItem, method, nil)
+				removed = append(removed, opPrefix)
+
+				continue
+			}
+
+			removed = append(removed, opRemoved...)
+		}
+
+		if hadOperations && len(sortedOperationMethods(&pathItem)) == 0 {
+			delete(sp.Paths.Paths, pth)
+			removed = append(removed, prefix)
+
+			continue
+		}
+
+		sp.Paths.Paths[pth] = pathItem
+	}
+
+	return removed
+}
+
+// removeResponses removes the responses of an operation at some pointers. It tells if all the responses
+// of the operation are removed.
+func removeResponses(responses *spec.Responses, opPrefix string, owners map[string]bool, removed *[]string) bool {
+	hadResponses := responses.Default != nil || len(responses.StatusCodeResponses) > 0
+
+	if response := slashpath.Join(opPrefix, "responses", "default"); owners[response] {
+		responses.Default = nil
+		*removed = append(*removed, response)
+	}
+
+	for _, code := range sortedStatusCodes(responses.StatusCodeResponses) {
+		if response := slashpath.Join(opPrefix, "responses", strconv.Itoa(code)); owners[response] {
+			delete(responses.StatusCodeResponses, code)
+			*removed = append(*removed, response)
+		}
+	}
+
+	return hadResponses && responses.Default == nil && len(responses.StatusCodeResponses) == 0
+}
+
+func filterParameters(params []spec.Parameter, prefix string, keep func(string, spec.Parameter) bool, removed []string) ([]spec.Parameter, []string) {
+	if len(params) == 0 {
+		return params, removed
+	}
+
+	kept := make([]spec.Parameter, 0, len(params))
+	for i, param := range params {
+		pointer := slashpath.Join(prefix, "parameters", strconv.Itoa(i))
+		if keep(pointer, param) {
+			kept = append(kept, param)
+
+			continue
+		}
+
+		removed = append(removed, pointer)
+	}
+
+	return kept, removed
+}
+
+// reachableDefinitions determines the names of all definitions which may be reached from
+// the paths, shared parameters and shared responses of a spec, following $ref's
+func reachableDefinitions(sp *spec.Swagger) map[string]bool {
+	roots := make([]string, 0, len(sp.Parameters)+len(sp.Responses))
+	for name := range sp.Parameters {
+		roots = append(roots, "#/parameters/"+jsonpointer.Escape(name))
+	}
+	for name := range sp.Responses {
+		roots = append(roots, "#/responses/"+jsonpointer.Escape(name))
+	}
+
+	reachable := make(map[string]bool)
+	for pointer := range reachableComponents(sp, roots...) {
+		if name, isDefinition := definitionOfPointer(pointer); isDefinition {
+			reachable[name] = true
+		}
+	}
+
+	return reachable
+}
+
+// definitionOfPointer yields the name of the definition a local JSON pointer belongs to
+func definitionOfPointer(pointer string) (string, bool) {
+	if !strings.HasPrefix(pointer, definitionsPrefix) {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(pointer, definitionsPrefix)
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		name = name[:i]
+	}
+
+	return jsonpointer.Unescape(name), true
+}
+
+// lookupExtension retrieves the value of a vendor extension, matching its name case-insensitively
+func lookupExtension(extensions spec.Extensions, name string) (interface{}, bool) {
+	for key, value := range extensions {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+func setOperation(pathItem *spec.PathItem, method string, op *spec.Operation) {
+	switch method {
+	case "GET":
+		pathItem.Get = op
+	case "PUT":
+		pathItem.Put = op
+	case "POST":
+		pathItem.Post = op
+	case "PATCH":
+		pathItem.Patch = op
+	case "DELETE":
+		pathItem.Delete = op
+	case "HEAD":
+		pathItem.Head = op
+	case "OPTIONS":
+		pathItem.Options = op
+	}
+}
+
+func removeString(values []string, value string) []string {
+	result := values[:0]
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
@@ -0,0 +1,116 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "filter", "fixture-internal.yaml"))
+
+	removed := Filter(sp, FilterOpts{})
+
+	assert.Equal(t, []string{
+		"#/definitions/adminReport",
+		"#/definitions/newPet",
+		"#/definitions/owner",
+		"#/definitions/pet/properties/owner",
+		"#/definitions/pet/properties/secret",
+		"#/parameters/internalTrace",
+		"#/paths/~1admin",
+		"#/paths/~1pets/get/parameters/1",
+		"#/paths/~1pets/get/parameters/2",
+		"#/paths/~1pets/post",
+	}, removed)
+
+	require.Contains(t, sp.Paths.Paths, "/pets")
+	assert.NotContains(t, sp.Paths.Paths, "/admin")
+	pets := sp.Paths.Paths["/pets"]
+	assert.Nil(t, pets.Post)
+	require.NotNil(t, pets.Get)
+	require.Len(t, pets.Get.Parameters, 1)
+	assert.Equal(t, "limit", pets.Get.Parameters[0].Name)
+
+	assert.ElementsMatch(t, []string{"pet", "orphan"}, sortedKeys(sp.Definitions))
+	assert.Equal(t, []string{"name"}, sp.Definitions["pet"].Required)
+	assert.Empty(t, sp.Parameters)
+}
+
+func TestFilter_DanglingRefs(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "filter", "fixture-dangling.yaml"))
+
+	removed := Filter(sp, FilterOpts{})
+
+	assert.Equal(t, []string{
+		"#/definitions/audit",
+		"#/definitions/pet/properties/labels",
+		"#/definitions/pet/properties/tags",
+		"#/definitions/secret",
+		"#/paths/~1pets/get/responses/206",
+		"#/paths/~1pets/get/responses/default",
+		"#/paths/~1pets/post/parameters/0",
+	}, removed)
+
+	assert.Equal(t, []string{"pet"}, sortedKeys(sp.Definitions))
+	assert.Equal(t, []string{"name"}, sortedKeys(sp.Definitions["pet"].Properties))
+
+	pets := sp.Paths.Paths["/pets"]
+	assert.Nil(t, pets.Get.Responses.Default)
+	assert.Equal(t, []int{200}, sortedStatusCodes(pets.Get.Responses.StatusCodeResponses))
+	require.Len(t, pets.Post.Parameters, 1)
+	assert.Equal(t, "name", pets.Post.Parameters[0].Name)
+
+	// the filtered spec resolves
+	assert.Empty(t, danglingRefOwners(sp, componentPointers(antest.LoadOrFail(t, filepath.Join("fixtures", "filter", "fixture-dangling.yaml")))))
+	require.NoError(t, ExpandRefs(sp, ExpandOpts{}))
+}
+
+func TestFilter_BrokenRefs(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "filter", "fixture-broken.yaml"))
+
+	removed := Filter(sp, FilterOpts{})
+
+	// $ref's broken before filtering are left unchanged, and operations are not left without responses
+	assert.Equal(t, []string{
+		"#/definitions/secret",
+		"#/paths/~1b",
+		"#/paths/~1c/get",
+	}, removed)
+
+	require.Contains(t, sp.Paths.Paths, "/a")
+	assert.Contains(t, sp.Paths.Paths["/a"].Get.Responses.StatusCodeResponses, 200)
+	require.Contains(t, sp.Paths.Paths, "/c")
+	assert.Nil(t, sp.Paths.Paths["/c"].Get)
+	assert.NotNil(t, sp.Paths.Paths["/c"].Put)
+}
+
+func TestFilter_Audiences(t *testing.T) {
+	t.Parallel()
+
+	sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+			"/pets": {PathItemProps: spec.PathItemProps{
+				Get:  &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-audience": []interface{}{"partner", "internal"}}}},
+				Post: &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-audience": "public"}}},
+				Put:  &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-audience": true}}},
+			}},
+		}},
+	}}
+
+	removed := Filter(sp, FilterOpts{Extension: "X-Audience", Audiences: []string{"internal"}})
+
+	assert.Equal(t, []string{"#/paths/~1pets/get"}, removed)
+	assert.NotNil(t, sp.Paths.Paths["/pets"].Post)
+	assert.NotNil(t, sp.Paths.Paths["/pets"].Put)
+}
@@ -0,0 +1,58 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+)
+
+// Finding describes an issue detected in a specification.
+//
+// The Pointer locates the offending construct in the analyzed document, as a JSON pointer
+// in the same form as the keys used by the analyzer indexes (e.g. "#/definitions/pet/properties/age").
+type Finding struct {
+	Pointer string
+	Code    string
+	Message string
+
+	// Fix is a machine-applicable remediation for this finding, as a JSON Patch (RFC 6902) targeting the
+	// analyzed document. It is empty when no fix can be determined.
+	Fix []PatchOperation
+}
+
+// PatchOperation is a JSON Patch (RFC 6902) operation
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// patchPath converts a pointer in the form used by findings (e.g. "#/definitions/pet") into a JSON Patch path,
+// appending some escaped tokens
+func patchPath(pointer string, tokens ...string) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimPrefix(pointer, "#"))
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(jsonpointer.Escape(token))
+	}
+
+	return b.String()
+}
+
+func (f Finding) String() string {
+	return f.Pointer + ": " + f.Message
+}
+
+// sortFindings orders findings by pointer, then by code, so reports are stable
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Pointer == findings[j].Pointer {
+			return findings[i].Code < findings[j].Code
+		}
+
+		return findings[i].Pointer < findings[j].Pointer
+	})
+}
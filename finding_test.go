@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinding_Fixes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should fix empty response descriptions", func(t *testing.T) {
+		t.Parallel()
+
+		bp := filepath.Join("fixtures", "fixer", "fixer.yaml")
+		findings := New(antest.LoadOrFail(t, bp)).EmptyResponseDescriptions()
+		require.Len(t, findings, 16)
+		assert.Equal(t, "#/paths/~1noDesc/delete/responses/200", findings[0].Pointer)
+
+		fixed := applyFixes(t, antest.LoadOrFail(t, bp), findings)
+		expected := antest.LoadOrFail(t, bp)
+		FixEmptyResponseDescriptions(expected)
+
+		assert.JSONEq(t, antest.AsJSON(t, expected), antest.AsJSON(t, fixed))
+	})
+
+	t.Run("should fix no-op constraints", func(t *testing.T) {
+		t.Parallel()
+
+		bp := filepath.Join("fixtures", "constraints", "noop.yml")
+		findings := New(antest.LoadOrFail(t, bp)).NoOpConstraints()
+		require.NotEmpty(t, findings)
+
+		fixed := applyFixes(t, antest.LoadOrFail(t, bp), findings)
+		assert.Empty(t, New(fixed).NoOpConstraints())
+	})
+
+	t.Run("should fix missing operation ids", func(t *testing.T) {
+		t.Parallel()
+
+		bp := filepath.Join("fixtures", "operations", "fixture-operation-ids.yaml")
+		findings := New(antest.LoadOrFail(t, bp)).OperationIDIssues()
+		require.NotEmpty(t, findings)
+
+		fixed := applyFixes(t, antest.LoadOrFail(t, bp), findings)
+		for _, finding := range New(fixed).OperationIDIssues() {
+			assert.Equal(t, CodeDuplicateOperationID, finding.Code)
+		}
+	})
+}
+
+// applyFixes applies the add and remove operations of fixes to a spec
+func applyFixes(t testing.TB, sp *spec.Swagger, findings []Finding) *spec.Swagger {
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(antest.AsJSON(t, sp)), &doc))
+
+	for _, finding := range findings {
+		for _, op := range finding.Fix {
+			i := strings.LastIndexByte(op.Path, '/')
+			ptr, err := jsonpointer.New(op.Path[:i])
+			require.NoError(t, err)
+
+			parent, _, err := ptr.Get(doc)
+			require.NoError(t, err)
+
+			container, ok := parent.(map[string]interface{})
+			require.Truef(t, ok, "expected an object at %s", op.Path)
+
+			key := jsonpointer.Unescape(op.Path[i+1:])
+			switch op.Op {
+			case "add":
+				container[key] = op.Value
+			case "remove":
+				delete(container, key)
+			default:
+				require.Failf(t, "unexpected patch operation", "%s", op.Op)
+			}
+		}
+	}
+
+	buf, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	var fixed spec.Swagger
+	require.NoError(t, json.Unmarshal(buf, &fixed))
+
+	return &fixed
+}
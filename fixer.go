@@ -14,7 +14,57 @@
 
 package analysis
 
-import "github.com/go-openapi/spec"
+import (
+	slashpath "path"
+	"strconv"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// CodeEmptyResponseDescription is the code for findings about responses with an empty description
+const CodeEmptyResponseDescription = "empty-response-description"
+
+// EmptyResponseDescriptions reports the responses with an empty description, which FixEmptyResponseDescriptions
+// would fix. Each finding comes with the equivalent fix.
+//
+// Findings are sorted by pointer.
+func (s *Spec) EmptyResponseDescriptions() []Finding {
+	var findings []Finding
+	report := func(pointer string, resp *spec.Response) {
+		if resp == nil || resp.Description != "" || resp.Ref.Ref.GetURL() != nil {
+			return
+		}
+
+		findings = append(findings, Finding{
+			Pointer: "#" + pointer,
+			Code:    CodeEmptyResponseDescription,
+			Message: "response has an empty description",
+			Fix:     []PatchOperation{{Op: "add", Path: patchPath(pointer, "description"), Value: "(empty)"}},
+		})
+	}
+
+	for _, name := range sortedKeys(s.spec.Responses) {
+		resp := s.spec.Responses[name]
+		report(slashpath.Join("/responses", jsonpointer.Escape(name)), &resp)
+	}
+
+	walkOperations(s.spec, func(pointer string, op *spec.Operation) {
+		if op.Responses == nil {
+			return
+		}
+
+		report(slashpath.Join(pointer, "responses", "default"), op.Responses.Default)
+		for _, code := range sortedStatusCodes(op.Responses.StatusCodeResponses) {
+			resp := op.Responses.StatusCodeResponses[code]
+			report(slashpath.Join(pointer, "responses", strconv.Itoa(code)), &resp)
+		}
+	})
+
+	sortFindings(findings)
+
+	return findings
+}
 
 // FixEmptyResponseDescriptions replaces empty ("") response
 // descriptions in the input with "(empty)" to ensure that the
@@ -77,3 +127,23 @@ func FixEmptyDesc(rs *spec.Response) {
 	}
 	rs.Description = "(empty)"
 }
+
+// FixNoOpConstraints strips validation keywords which have no effect given the type
+// of the schema or parameter they are attached to (e.g. maxLength on a number).
+//
+// See Spec.NoOpConstraints() to report these constraints without altering the spec.
+func FixNoOpConstraints(s *spec.Swagger) {
+	walkSchemas(s, func(_ string, sch *spec.Schema) {
+		if sch.Ref.String() != "" {
+			return
+		}
+
+		for _, keyword := range noOpSchemaKeywords(sch) {
+			clearSchemaKeyword(sch, keyword)
+		}
+	})
+
+	walkParameters(s, func(_ string, param *spec.Parameter) {
+		fixNoOpSimpleSchema(param.In, &param.SimpleSchema, &param.CommonValidations)
+	})
+}
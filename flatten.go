@@ -46,15 +46,27 @@ type newRef struct {
 // context stores intermediary results from flatten
 type context struct {
 	newRefs  map[string]*newRef
-	warnings []string
+	warnings []Warning
 	resolved map[string]string
+
+	renamed    map[string]string // names given on collisions to the definitions created from some source
+	collisions map[string]bool   // names given on collisions
+	events     []Event           // events emitted while flattening, reported by FlattenWithReport
+	reused     []string          // inline schemas replaced by a $ref to an existing definition
+	created    map[string]bool   // definitions created while flattening
+	mangled    map[string]string // original names of the definitions renamed by mangling, by new name
 }
 
 func newContext() *context {
 	return &context{
 		newRefs:  make(map[string]*newRef, 150),
-		warnings: make([]string, 0),
+		warnings: make([]Warning, 0),
 		resolved: make(map[string]string, 50),
+
+		renamed:    make(map[string]string),
+		collisions: make(map[string]bool),
+		created:    make(map[string]bool),
+		mangled:    make(map[string]string),
 	}
 }
 
@@ -96,6 +108,9 @@ func newContext() *context {
 //   - Verbose: croaks about name conflicts detected
 //   - RemoveUnused: removes unused parameters, responses and definitions after expansion/flattening
 //
+// Use FlattenWithWarnings to collect the non-fatal issues found while flattening (e.g. name conflicts, keywords
+// ignored next to a $ref).
+//
 // NOTE: expansion removes all $ref save circular $ref, which remain in place
 //
 // TODO: additional options
@@ -107,14 +122,63 @@ func newContext() *context {
 //   - merge allOf with extensions only
 //   - ...
 func Flatten(opts FlattenOpts) error {
-	debugLog("FlattenOpts: %#v", opts)
-
 	opts.flattenContext = newContext()
 
+	return flatten(&opts)
+}
+
+// Flattened flattens a copy of a spec like Flatten, and returns this copy. The spec is left unchanged.
+//
+// The Spec of the options is ignored.
+func Flattened(sp *spec.Swagger, opts FlattenOpts) (*spec.Swagger, error) {
+	opts.Spec = newSpec(CloneSpec(sp))
+	if err := Flatten(opts); err != nil {
+		return nil, err
+	}
+
+	return opts.Swagger(), nil
+}
+
+func flatten(opts *FlattenOpts) error {
+	opts.tracef("FlattenOpts: %#v", *opts)
+	opts.prepareDryRun()
+	if err := opts.Spec.mutable(); err != nil {
+		return err
+	}
+
+	if err := opts.applyAlgorithm(); err != nil {
+		return err
+	}
+
+	if _, recorded := opts.Spec.provenance["#"]; !recorded && opts.BasePath != "" {
+		opts.Spec.setProvenance("#", Provenance{Document: documentLocation(opts.BasePath), Pointer: "#"})
+	}
+
+	opts.Scope = scopePointers(opts.Scope)
+	opts.warnRefSiblings()
+
+	if opts.hasRefHostsPolicy() {
+		if err := opts.checkRefHosts(); err != nil {
+			return err
+		}
+	}
+
+	// 0. Optionally rename the definitions with unsafe names, so $ref's to them may be rewritten safely,
+	// and move the schemas of shared parameters and responses to definitions, before these are expanded
+	if err := mangleDefinitionNames(opts); err != nil {
+		return err
+	}
+
+	if opts.InlineParamsAndResponses && !opts.Expand {
+		if err := liftSharedSchemas(opts); err != nil {
+			return err
+		}
+	}
+
 	// 1. Recursively expand responses, parameters, path items and items in simple schemas.
 	//
 	// This simplifies the spec and leaves only the $ref's in schema objects.
-	if err := expand(&opts); err != nil {
+	if err := expand(opts); err != nil {
 		return err
 	}
 
@@ -122,41 +186,63 @@ func Flatten(opts FlattenOpts) error {
 	// so we can recognize them as proper definitions
 	//
 	// In particular, this works around issue go-openapi/spec#76: leading absolute file in $ref is stripped
-	if err := normalizeRef(&opts); err != nil {
+	if err := normalizeRef(opts); err != nil {
 		return err
 	}
 
 	// 3. Optionally remove shared parameters and responses already expanded (now unused).
 	//
 	// Operation parameters (i.e. under paths) remain.
-	if opts.RemoveUnused {
-		removeUnusedShared(&opts)
+	if opts.RemoveUnused && len(opts.Scope) == 0 {
+		removeUnusedShared(opts)
 	}
 
 	// 4. Import all remote references.
-	if err := importReferences(&opts); err != nil {
+	if err := importReferences(opts); err != nil {
 		return err
 	}
 
+	// When verifying a lockfile, fail if any remote document did not match, even when continuing on errors
+	if opts.Lockfile != nil && opts.Lockfile.Verify {
+		if err := opts.Lockfile.verified(); err != nil {
+			return err
+		}
+	}
+
 	// 5. full flattening: rewrite inline schemas (schemas that aren't simple types or arrays or maps)
 	if !opts.Minimal && !opts.Expand {
-		if err := nameInlinedSchemas(&opts); err != nil {
+		if err := nameInlinedSchemas(opts); err != nil {
 			return err
 		}
 	}
 
 	// 6. Rewrite JSON pointers other than $ref to named definitions
 	// and attempt to resolve conflicting names whenever possible.
-	if err := stripPointersAndOAIGen(&opts); err != nil {
+	if err := stripPointersAndOAIGen(opts); err != nil {
 		return err
 	}
 
-	// 7. Strip the spec from unused definitions
-	if opts.RemoveUnused {
-		removeUnused(&opts)
+	// 7. Optionally re-inline the small definitions created while flattening
+	if opts.InlineThreshold > 0 && !opts.Expand {
+		if err := inlineSmallDefinitions(opts); err != nil {
+			return err
+		}
 	}
 
-	// 8. Issue warning notifications, if any
+	// 8. Optionally rename the definitions created with unsafe names
+	if err := mangleDefinitionNames(opts); err != nil {
+		return err
+	}
+
+	// 9. Strip the spec from unused definitions
+	if opts.RemoveUnused && len(opts.Scope) == 0 {
+		removeUnused(opts)
+	}
+
+	// 10. Filter vendor extensions
+	filterExtensions(opts)
+
+	// 11. Issue warning notifications, if any
 	opts.croak()
 
 	// TODO: simplify known schema patterns to flat objects with properties
@@ -170,6 +256,18 @@ func Flatten(opts FlattenOpts) error {
 }
 
 func expand(opts *FlattenOpts) error {
+	if len(opts.Scope) > 0 {
+		// expand only the $ref's in scope
+		expandOpts := ExpandOpts{BasePath: opts.BasePath, Only: opts.Scope, SkipDefinitions: !opts.Expand}
+		if err := expandRefs(opts.Swagger(), expandOpts, opts.ExpandOpts(false).PathLoader); err != nil {
+			return err
+		}
+
+		opts.Spec.reload() // re-analyze
+
+		return nil
+	}
+
 	if err := spec.ExpandSpec(opts.Swagger(), opts.ExpandOpts(!opts.Expand)); err != nil {
 		return err
 	}
@@ -182,19 +280,19 @@ func expand(opts *FlattenOpts) error {
 // normalizeRef strips the current file from any absolute file $ref. This works around issue go-openapi/spec#76:
 // leading absolute file in $ref is stripped
 func normalizeRef(opts *FlattenOpts) error {
-	debugLog("normalizeRef")
+	opts.tracef("normalizeRef")
 
 	altered := false
 	for k, w := range opts.Spec.references.allRefs {
-		if !strings.HasPrefix(w.String(), opts.BasePath+definitionsPath) { // may be a mix of / and \, depending on OS
+		if !strings.HasPrefix(w.String(), opts.BasePath+definitionsPath) || !opts.inScope(k) { // may be a mix of / and \, depending on OS
 			continue
 		}
 
 		altered = true
-		debugLog("stripping absolute path for: %s", w.String())
+		opts.tracef("stripping absolute path for: %s", w.String())
 
 		// strip the base path from definition
-		if err := replace.UpdateRef(opts.Swagger(), k,
+		if err := opts.updateRef(k,
 			spec.MustCreateRef(path.Join(definitionsPath, path.Base(w.String())))); err != nil {
 			return err
 		}
@@ -233,7 +331,7 @@ func importReferences(opts *FlattenOpts) error {
 
 // nameInlinedSchemas replaces every complex inline construct by a named definition.
 func nameInlinedSchemas(opts *FlattenOpts) error {
-	debugLog("nameInlinedSchemas")
+	opts.tracef("nameInlinedSchemas")
 
 	namer := &InlineSchemaNamer{
 		Spec:           opts.Swagger(),
@@ -245,13 +343,13 @@ func nameInlinedSchemas(opts *FlattenOpts) error {
 	depthFirst := sortref.DepthFirst(opts.Spec.allSchemas)
 	for _, key := range depthFirst {
 		sch := opts.Spec.allSchemas[key]
-		if sch.Schema == nil || sch.Schema.Ref.String() != "" || sch.TopLevel {
+		if sch.Schema == nil || sch.Schema.Ref.String() != "" || sch.TopLevel || !opts.inScope(key) {
 			continue
 		}
 
 		asch, err := Schema(SchemaOpts{Schema: sch.Schema, Root: opts.Swagger(), BasePath: opts.BasePath})
 		if err != nil {
-			return fmt.Errorf("schema analysis [%s]: %w", key, err)
+			return &SchemaError{Pointer: key, Cause: err}
 		}
 
 		if asch.isAnalyzedAsComplex() { // move complex schemas to definitions
@@ -277,11 +375,11 @@ func removeUnused(opts *FlattenOpts) {
 	}
 
 	for k := range expected {
-		debugLog("removing unused definition %s", path.Base(k))
+		opts.tracef("removing unused definition %s", path.Base(k))
 		if opts.Verbose {
 			log.Printf("info: removing unused definition: %s", path.Base(k))
 		}
-		delete(opts.Swagger().Definitions, path.Base(k))
+		opts.removeDefinition(path.Base(k))
 	}
 
 	opts.Spec.reload() // re-analyze
@@ -290,10 +388,10 @@ func removeUnused(opts *FlattenOpts) {
 func importKnownRef(entry sortref.RefRevIdx, refStr, newName string, opts *FlattenOpts) error {
 	// rewrite ref with already resolved external ref (useful for cyclical refs):
 	// rewrite external refs to local ones
-	debugLog("resolving known ref [%s] to %s", refStr, newName)
+	opts.tracef("resolving known ref [%s] to %s", refStr, newName)
 
 	for _, key := range entry.Keys {
-		if err := replace.UpdateRef(opts.Swagger(), key, spec.MustCreateRef(path.Join(definitionsPath, newName))); err != nil {
+		if err := opts.updateRef(key, spec.MustCreateRef(path.Join(definitionsPath, newName))); err != nil {
 			return err
 		}
 	}
@@ -307,11 +405,11 @@ func importNewRef(entry sortref.RefRevIdx, refStr string, opts *FlattenOpts) err
 		newName  string
 	)
 
-	debugLog("resolving schema from remote $ref [%s]", refStr)
+	opts.tracef("resolving schema from remote $ref [%s]", refStr)
 
 	sch, err := spec.ResolveRefWithBase(opts.Swagger(), &entry.Ref, opts.ExpandOpts(false))
 	if err != nil {
-		return fmt.Errorf("could not resolve schema: %w", err)
+		return &RefError{Pointer: entry.Keys[0], Ref: refStr, Cause: err}
 	}
 
 	// at this stage only $ref analysis matters
@@ -325,20 +423,20 @@ func importNewRef(entry sortref.RefRevIdx, refStr string, opts *FlattenOpts) err
 
 	// now rewrite those refs with rebase
 	for key, ref := range partialAnalyzer.references.allRefs {
-		if err := replace.UpdateRef(sch, key, spec.MustCreateRef(normalize.RebaseRef(entry.Ref.String(), ref.String()))); err != nil {
-			return fmt.Errorf("failed to rewrite ref for key %q at %s: %w", key, entry.Ref.String(), err)
+		if err := opts.updateRefIn(sch, key, spec.MustCreateRef(normalize.RebaseRef(entry.Ref.String(), ref.String()))); err != nil {
+			return &PointerError{Pointer: key, Cause: fmt.Errorf("failed to rewrite ref at %s: %w", entry.Ref.String(), err)}
 		}
 	}
 
 	// generate a unique name - isOAIGen means that a naming conflict was resolved by changing the name
-	newName, isOAIGen = uniqifyName(opts.Swagger().Definitions, nameFromRef(entry.Ref))
-	debugLog("new name for [%s]: %s - with name conflict:%t", strings.Join(entry.Keys, ", "), newName, isOAIGen)
+	newName, isOAIGen = opts.uniqueName(entry.Ref.String(), opts.definitionName(rawNameFromRef(entry.Ref)), sch)
+	opts.tracef("new name for [%s]: %s - with name conflict:%t", strings.Join(entry.Keys, ", "), newName, isOAIGen)
 
 	opts.flattenContext.resolved[refStr] = newName
 
 	// rewrite the external refs to local ones
 	for _, key := range entry.Keys {
-		if err := replace.UpdateRef(opts.Swagger(), key,
+		if err := opts.updateRef(key,
 			spec.MustCreateRef(path.Join(definitionsPath, newName))); err != nil {
 			return err
 		}
@@ -349,7 +447,7 @@ func importNewRef(entry sortref.RefRevIdx, refStr string, opts *FlattenOpts) err
 			resolved = opts.flattenContext.newRefs[key].resolved
 		}
 
-		debugLog("keeping track of ref: %s (%s), resolved: %t", key, newName, resolved)
+		opts.tracef("keeping track of ref: %s (%s), resolved: %t", key, newName, resolved)
 		opts.flattenContext.newRefs[key] = &newRef{
 			key:      key,
 			newName:  newName,
@@ -362,6 +460,8 @@ func importNewRef(entry sortref.RefRevIdx, refStr string, opts *FlattenOpts) err
 
 	// add the resolved schema to the definitions
 	schutils.Save(opts.Swagger(), newName, sch)
+	opts.flattenContext.created[newName] = true
+	opts.emit(Event{Kind: EventDefinitionImported, Pointer: path.Join(definitionsPath, newName), Ref: refStr})
 
 	return nil
 }
@@ -372,9 +472,9 @@ func importNewRef(entry sortref.RefRevIdx, refStr string, opts *FlattenOpts) err
 //
 // This returns true when no more remote references can be found.
 func importExternalReferences(opts *FlattenOpts) (bool, error) {
-	debugLog("importExternalReferences")
+	opts.tracef("importExternalReferences")
 
-	groupedRefs := sortref.ReverseIndex(opts.Spec.references.schemas, opts.BasePath)
+	groupedRefs := sortref.ReverseIndex(opts.scopedRefs(opts.Spec.references.schemas), opts.BasePath)
 	sortedRefStr := make([]string, 0, len(groupedRefs))
 	if opts.flattenContext == nil {
 		opts.flattenContext = newContext()
@@ -420,7 +520,7 @@ func importExternalReferences(opts *FlattenOpts) (bool, error) {
 			ref := spec.MustCreateRef(r.path)
 			sch, err := spec.ResolveRefWithBase(opts.Swagger(), &ref, opts.ExpandOpts(false))
 			if err != nil {
-				return false, fmt.Errorf("could not resolve schema: %w", err)
+				return false, &RefError{Pointer: k, Ref: r.path, Cause: err}
 			}
 
 			r.schema = sch
@@ -439,7 +539,7 @@ func importExternalReferences(opts *FlattenOpts) (bool, error) {
 		r.newName = path.Base(k)
 		r.schema = spec.RefSchema(r.path)
 		r.path = k
-		r.isOAIGen = strings.Contains(k, "OAIGen")
+		r.isOAIGen = strings.Contains(k, "OAIGen") || opts.flattenContext.collisions[path.Base(k)]
 	}
 
 	return complete, nil
@@ -492,7 +592,7 @@ func stripPointersAndOAIGen(opts *FlattenOpts) error {
 // This function returns true whenever it re-inlined a complex schema, so the caller may chose to iterate
 // pointer and name resolution again.
 func stripOAIGen(opts *FlattenOpts) (bool, error) {
-	debugLog("stripOAIGen")
+	opts.tracef("stripOAIGen")
 	replacedWithComplex := false
 
 	// figure out referers of OAIGen definitions (doing it before the ref start mutating)
@@ -502,7 +602,7 @@ func stripOAIGen(opts *FlattenOpts) (bool, error) {
 
 	for k := range opts.flattenContext.newRefs {
 		r := opts.flattenContext.newRefs[k]
-		debugLog("newRefs[%s]: isOAIGen: %t, resolved: %t, name: %s, path:%s, #parents: %d, parents: %v,  ref: %s",
+		opts.tracef("newRefs[%s]: isOAIGen: %t, resolved: %t, name: %s, path:%s, #parents: %d, parents: %v,  ref: %s",
 			k, r.isOAIGen, r.resolved, r.newName, r.path, len(r.parents), r.parents, r.schema.Ref.String())
 
 		if !r.isOAIGen || len(r.parents) == 0 {
@@ -517,7 +617,7 @@ func stripOAIGen(opts *FlattenOpts) (bool, error) {
 		replacedWithComplex = replacedWithComplex || hasReplacedWithComplex
 	}
 
-	debugLog("replacedWithComplex: %t", replacedWithComplex)
+	opts.tracef("replacedWithComplex: %t", replacedWithComplex)
 	opts.Spec.reload() // re-analyze
 
 	return replacedWithComplex, nil
@@ -553,14 +653,14 @@ func stripOAIGenForRef(opts *FlattenOpts, k string, r *newRef) (bool, error) {
 	pr := sortref.TopmostFirst(r.parents)
 
 	// rewrite first parent schema in hierarchical then lexicographical order
-	debugLog("rewrite first parent %s with schema", pr[0])
-	if err := replace.UpdateRefWithSchema(opts.Swagger(), pr[0], r.schema); err != nil {
+	opts.tracef("rewrite first parent %s with schema", pr[0])
+	if err := opts.updateRefWithSchema(pr[0], r.schema); err != nil {
 		return false, err
 	}
 
 	if pa, ok := opts.flattenContext.newRefs[pr[0]]; ok && pa.isOAIGen {
 		// update parent in ref index entry
-		debugLog("update parent entry: %s", pr[0])
+		opts.tracef("update parent entry: %s", pr[0])
 		pa.schema = r.schema
 		pa.resolved = false
 		replacedWithComplex = true
@@ -573,17 +673,17 @@ func stripOAIGenForRef(opts *FlattenOpts, k string, r *newRef) (bool, error) {
 
 			// set complex when replacing ref is an anonymous jsonpointer: further processing may be required
 			replacedWithComplex = replacedWithComplex || path.Dir(replacingRef.String()) != definitionsPath
-			debugLog("rewrite parent with ref: %s", replacingRef.String())
+			opts.tracef("rewrite parent with ref: %s", replacingRef.String())
 
 			// NOTE: it is possible at this stage to introduce json pointers (to non-definitions places).
 			// Those are stripped later on.
-			if err := replace.UpdateRef(opts.Swagger(), p, replacingRef); err != nil {
+			if err := opts.updateRef(p, replacingRef); err != nil {
 				return false, err
 			}
 
 			if pa, ok := opts.flattenContext.newRefs[p]; ok && pa.isOAIGen {
 				// update parent in ref index
-				debugLog("update parent entry: %s", p)
+				opts.tracef("update parent entry: %s", p)
 				pa.schema = r.schema
 				pa.resolved = false
 				replacedWithComplex = true
@@ -592,8 +692,8 @@ func stripOAIGenForRef(opts *FlattenOpts, k string, r *newRef) (bool, error) {
 	}
 
 	// remove OAIGen definition
-	debugLog("removing definition %s", path.Base(r.path))
-	delete(opts.Swagger().Definitions, path.Base(r.path))
+	opts.tracef("removing definition %s", path.Base(r.path))
+	opts.removeDefinition(path.Base(r.path))
 
 	// propagate changes in ref index for keys which have this one as a parent
 	for kk, value := range opts.flattenContext.newRefs {
@@ -622,7 +722,7 @@ func stripOAIGenForRef(opts *FlattenOpts, k string, r *newRef) (bool, error) {
 	}
 
 	// mark naming conflict as resolved
-	debugLog("marking naming conflict resolved for key: %s", r.key)
+	opts.tracef("marking naming conflict resolved for key: %s", r.key)
 	opts.flattenContext.newRefs[r.key].isOAIGen = false
 	opts.flattenContext.newRefs[r.key].resolved = true
 
@@ -633,7 +733,7 @@ func stripOAIGenForRef(opts *FlattenOpts, k string, r *newRef) (bool, error) {
 			return false, err
 		}
 
-		debugLog("re-inlined schema: parent: %s, %t", pr[0], asch.isAnalyzedAsComplex())
+		opts.tracef("re-inlined schema: parent: %s, %t", pr[0], asch.isAnalyzedAsComplex())
 		replacedWithComplex = replacedWithComplex || !(path.Dir(pr[0]) == definitionsPath) && asch.isAnalyzedAsComplex()
 	}
 
@@ -645,27 +745,27 @@ func stripOAIGenForRef(opts *FlattenOpts, k string, r *newRef) (bool, error) {
 // This is carried on depth-first. Pointers to $refs which are top level definitions are replaced by the $ref itself.
 // Pointers to simple types are expanded, unless they express commonality (i.e. several such $ref are used).
 func namePointers(opts *FlattenOpts) error {
-	debugLog("name pointers")
+	opts.tracef("name pointers")
 
 	refsToReplace := make(map[string]SchemaRef, len(opts.Spec.references.schemas))
 	for k, ref := range opts.Spec.references.allRefs {
-		if path.Dir(ref.String()) == definitionsPath {
+		if path.Dir(ref.String()) == definitionsPath || !opts.inScope(k) {
 			// this a ref to a top-level definition: ok
 			continue
 		}
 
 		result, err := replace.DeepestRef(opts.Swagger(), opts.ExpandOpts(false), ref)
 		if err != nil {
-			return fmt.Errorf("at %s, %w", k, err)
+			return &RefError{Pointer: k, Ref: ref.String(), Cause: err}
 		}
 
 		replacingRef := result.Ref
 		sch := result.Schema
 		if opts.flattenContext != nil {
-			opts.flattenContext.warnings = append(opts.flattenContext.warnings, result.Warnings...)
+			opts.flattenContext.warn(k, result.Warnings)
 		}
 
-		debugLog("planning pointer to replace at %s: %s, resolved to: %s", k, ref.String(), replacingRef.String())
+		opts.tracef("planning pointer to replace at %s: %s, resolved to: %s", k, ref.String(), replacingRef.String())
 		refsToReplace[k] = SchemaRef{
 			Name:     k,            // caller
 			Ref:      replacingRef, // called
@@ -687,23 +787,23 @@ func namePointers(opts *FlattenOpts) error {
 		// update current replacement, which may have been updated by previous changes of deeper elements
 		result, erd := replace.DeepestRef(opts.Swagger(), opts.ExpandOpts(false), v.Ref)
 		if erd != nil {
-			return fmt.Errorf("at %s, %w", key, erd)
+			return &RefError{Pointer: key, Ref: v.Ref.String(), Cause: erd}
 		}
 
 		if opts.flattenContext != nil {
-			opts.flattenContext.warnings = append(opts.flattenContext.warnings, result.Warnings...)
+			opts.flattenContext.warn(key, result.Warnings)
 		}
 
 		v.Ref = result.Ref
 		v.Schema = result.Schema
 		v.TopLevel = path.Dir(result.Ref.String()) == definitionsPath
-		debugLog("replacing pointer at %s: resolved to: %s", key, v.Ref.String())
+		opts.tracef("replacing pointer at %s: resolved to: %s", key, v.Ref.String())
 
 		if v.TopLevel {
-			debugLog("replace pointer %s by canonical definition: %s", key, v.Ref.String())
+			opts.tracef("replace pointer %s by canonical definition: %s", key, v.Ref.String())
 
 			// if the schema is a $ref to a top level definition, just rewrite the pointer to this $ref
-			if err := replace.UpdateRef(opts.Swagger(), key, v.Ref); err != nil {
+			if err := opts.updateRef(key, v.Ref); err != nil {
 				return err
 			}
 
@@ -728,26 +828,26 @@ func flattenAnonPointer(key string, v SchemaRef, refsToReplace map[string]Schema
 	// otherwise, expand the pointer (single reference to a simple type)
 	//
 	// The named definition for this follows the target's key, not the caller's
-	debugLog("namePointers at %s for %s", key, v.Ref.String())
+	opts.tracef("namePointers at %s for %s", key, v.Ref.String())
 
 	// qualify the expanded schema
 	asch, ers := Schema(SchemaOpts{Schema: v.Schema, Root: opts.Swagger(), BasePath: opts.BasePath})
 	if ers != nil {
-		return fmt.Errorf("schema analysis [%s]: %w", key, ers)
+		return &SchemaError{Pointer: key, Cause: ers}
 	}
 	callers := make([]string, 0, 64)
 
-	debugLog("looking for callers")
+	opts.tracef("looking for callers")
 
-	an := New(opts.Swagger())
+	an := newSpec(opts.Swagger())
 	for k, w := range an.references.allRefs {
 		r, err := replace.DeepestRef(opts.Swagger(), opts.ExpandOpts(false), w)
 		if err != nil {
-			return fmt.Errorf("at %s, %w", key, err)
+			return &RefError{Pointer: k, Ref: w.String(), Cause: err}
 		}
 
 		if opts.flattenContext != nil {
-			opts.flattenContext.warnings = append(opts.flattenContext.warnings, r.Warnings...)
+			opts.flattenContext.warn(k, r.Warnings)
 		}
 
 		if r.Ref.String() == v.Ref.String() {
@@ -755,19 +855,19 @@ func flattenAnonPointer(key string, v SchemaRef, refsToReplace map[string]Schema
 		}
 	}
 
-	debugLog("callers for %s: %d", v.Ref.String(), len(callers))
+	opts.tracef("callers for %s: %d", v.Ref.String(), len(callers))
 	if len(callers) == 0 {
 		// has already been updated and resolved
 		return nil
 	}
 
 	parts := sortref.KeyParts(v.Ref.String())
-	debugLog("number of callers for %s: %d", v.Ref.String(), len(callers))
+	opts.tracef("number of callers for %s: %d", v.Ref.String(), len(callers))
 
 	// identifying edge case when the namer did nothing because we point to a non-schema object
 	// no definition is created and we expand the $ref for all callers
 	if (!asch.IsSimpleSchema || len(callers) > 1) && !parts.IsSharedParam() && !parts.IsSharedResponse() {
-		debugLog("replace JSON pointer at [%s] by definition: %s", key, v.Ref.String())
+		opts.tracef("replace JSON pointer at [%s] by definition: %s", key, v.Ref.String())
 		if err := namer.Name(v.Ref.String(), v.Schema, asch); err != nil {
 			return err
 		}
@@ -779,7 +879,7 @@ func flattenAnonPointer(key string, v SchemaRef, refsToReplace map[string]Schema
 			}
 
 			// move $ref for next to resolve
-			debugLog("identified caller of %s at [%s]", v.Ref.String(), caller)
+			opts.tracef("identified caller of %s at [%s]", v.Ref.String(), caller)
 			c := refsToReplace[caller]
 			c.Ref = v.Ref
 			refsToReplace[caller] = c
@@ -788,12 +888,28 @@ func flattenAnonPointer(key string, v SchemaRef, refsToReplace map[string]Schema
 		return nil
 	}
 
-	debugLog("expand JSON pointer for key=%s", key)
+	opts.tracef("expand JSON pointer for key=%s", key)
 
-	if err := replace.UpdateRefWithSchema(opts.Swagger(), key, v.Schema); err != nil {
+	if err := opts.updateRefWithSchema(key, v.Schema); err != nil {
 		return err
 	}
 	// NOTE: there is no other caller to update
 
 	return nil
 }
+
+// filterExtensions removes vendor extensions according to the StripExtensions and KeepExtensions options
+func filterExtensions(opts *FlattenOpts) {
+	if len(opts.StripExtensions) == 0 && len(opts.KeepExtensions) == 0 {
+		return
+	}
+
+	walkExtensions(opts.Swagger(), func(extensions spec.Extensions) {
+		for name := range extensions {
+			if !opts.keepExtension(name) {
+				opts.tracef("removing vendor extension %s", name)
+				delete(extensions, name)
+			}
+		}
+	})
+}
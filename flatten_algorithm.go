@@ -0,0 +1,79 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Versions of the flattening algorithm, for FlattenOpts.AlgorithmVersion
+const (
+	// FlattenAlgorithmLatest is the most recent version of the flattening algorithm. Bundles flattened with
+	// this version may change when the library is upgraded.
+	FlattenAlgorithmLatest = "latest"
+
+	// FlattenAlgorithmV021 is the flattening algorithm of the v0.21 releases
+	FlattenAlgorithmV021 = "v0.21"
+)
+
+// flattenAlgorithm holds the behaviors of a version of the flattening algorithm which may change between releases
+type flattenAlgorithm struct {
+	// collisionTemplate is the default template of the names given to definitions on name collisions
+	collisionTemplate string
+}
+
+// flattenAlgorithms are the supported versions of the flattening algorithm.
+//
+// Releases which change the output of flattening register a new version here, and keep former versions
+// available, so that pinned bundles remain stable.
+var flattenAlgorithms = map[string]flattenAlgorithm{
+	FlattenAlgorithmV021: {collisionTemplate: DefaultCollisionTemplate},
+}
+
+// latestFlattenAlgorithm is the version FlattenAlgorithmLatest stands for
+const latestFlattenAlgorithm = FlattenAlgorithmV021
+
+// UnknownAlgorithmError reports a version of the flattening algorithm which is not supported by this release
+type UnknownAlgorithmError struct {
+	Version string
+}
+
+func (e *UnknownAlgorithmError) Error() string {
+	return fmt.Sprintf("unknown flattening algorithm version %q: supported versions are %s, or %q",
+		e.Version, strings.Join(FlattenAlgorithmVersions(), ", "), FlattenAlgorithmLatest)
+}
+
+// FlattenAlgorithmVersions lists the versions of the flattening algorithm supported by this release, sorted
+func FlattenAlgorithmVersions() []string {
+	return sortedKeys(flattenAlgorithms)
+}
+
+// algorithmVersion resolves the version of the flattening algorithm to apply
+func (f *FlattenOpts) algorithmVersion() (string, error) {
+	version := f.AlgorithmVersion
+	if version == "" || version == FlattenAlgorithmLatest {
+		return latestFlattenAlgorithm, nil
+	}
+
+	if _, known := flattenAlgorithms[version]; !known {
+		return "", &UnknownAlgorithmError{Version: version}
+	}
+
+	return version, nil
+}
+
+// applyAlgorithm sets the options left unspecified to the defaults of the pinned version of the algorithm
+func (f *FlattenOpts) applyAlgorithm() error {
+	version, err := f.algorithmVersion()
+	if err != nil {
+		return err
+	}
+
+	algorithm := flattenAlgorithms[version]
+	f.tracef("flattening with algorithm %s", version)
+
+	if f.CollisionTemplate == "" {
+		f.CollisionTemplate = algorithm.collisionTemplate
+	}
+
+	return nil
+}
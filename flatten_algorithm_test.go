@@ -0,0 +1,47 @@
+package analysis
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten_AlgorithmVersion(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "flatten.yml")
+
+	t.Run("should pin the latest algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		latest, err := FlattenWithReport(FlattenOpts{Spec: New(antest.LoadOrFail(t, bp)), BasePath: bp})
+		require.NoError(t, err)
+		assert.Equal(t, FlattenAlgorithmV021, latest.AlgorithmVersion)
+
+		sp := antest.LoadOrFail(t, bp)
+		pinned, err := FlattenWithReport(FlattenOpts{Spec: New(sp), BasePath: bp, AlgorithmVersion: FlattenAlgorithmV021})
+		require.NoError(t, err)
+		assert.Equal(t, FlattenAlgorithmV021, pinned.AlgorithmVersion)
+		assert.Equal(t, latest.Renamed, pinned.Renamed)
+		assert.Equal(t, latest.Definitions, pinned.Definitions)
+	})
+
+	t.Run("should reject unknown versions", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		err := Flatten(FlattenOpts{Spec: New(sp), BasePath: bp, AlgorithmVersion: "v9.99"})
+		require.Error(t, err)
+
+		var unknown *UnknownAlgorithmError
+		require.True(t, errors.As(err, &unknown))
+		assert.Equal(t, "v9.99", unknown.Version)
+		assert.Contains(t, err.Error(), FlattenAlgorithmV021)
+	})
+
+	assert.Equal(t, []string{FlattenAlgorithmV021}, FlattenAlgorithmVersions())
+}
@@ -0,0 +1,91 @@
+package analysis
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-openapi/swag"
+)
+
+// KeepCase keeps the case of the words of the names given to definitions (see FlattenOpts.NameCase)
+const KeepCase NamingCase = "keep"
+
+// WordSplitting is a strategy to case the initialisms (e.g. "ID", "URL", "HTTP") found in the names
+// given to definitions
+type WordSplitting string
+
+// Strategies to case initialisms
+const (
+	// SplitInitialisms keeps known initialisms as upper case words, e.g. "petID" or "HTTPServer" in camel case
+	SplitInitialisms WordSplitting = ""
+
+	// SplitPlainWords cases initialisms like other words, e.g. "petId" or "httpServer" in camel case
+	SplitPlainWords WordSplitting = "plain"
+)
+
+// knownInitialisms are the initialisms kept in upper case with SplitInitialisms
+var knownInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "OAI": true, "RPC": true,
+	"SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true,
+	"URI": true, "URL": true, "UTF8": true, "UUID": true, "VM": true, "XML": true, "XSRF": true, "XSS": true,
+}
+
+// definitionName yields the name of a definition from raw words (e.g. "createPet params body"),
+// cased according to the NameCase option
+func (f *FlattenOpts) definitionName(raw string) string {
+	if f.NameCase == "" {
+		return swag.ToJSONName(raw)
+	}
+
+	return f.casedName(raw)
+}
+
+// casedName cases a name according to the NameCase and NameWords options, or leaves it unchanged when
+// no case is specified
+func (f *FlattenOpts) casedName(name string) string {
+	switch f.NameCase {
+	case KeepCase:
+		segments := strings.Fields(name)
+		for i := 1; i < len(segments); i++ {
+			segments[i] = upperFirst(segments[i])
+		}
+
+		return strings.Join(segments, "")
+	case CamelCase, PascalCase:
+		words := splitWords(name)
+		for i, word := range words {
+			switch {
+			case i == 0 && f.NameCase == CamelCase:
+				words[i] = strings.ToLower(word)
+			case f.NameWords == SplitInitialisms && knownInitialisms[strings.ToUpper(word)]:
+				words[i] = strings.ToUpper(word)
+			default:
+				words[i] = upperFirst(strings.ToLower(word))
+			}
+		}
+
+		return strings.Join(words, "")
+	case SnakeCase, LowerCase:
+		separator := "_"
+		if f.NameCase == LowerCase {
+			separator = ""
+		}
+
+		return strings.ToLower(strings.Join(splitWords(name), separator))
+	case KebabCase:
+		return strings.ToLower(strings.Join(splitWords(name), "-"))
+	case UpperSnakeCase:
+		return strings.ToUpper(strings.Join(splitWords(name), "_"))
+	default:
+		return name
+	}
+}
+
+// upperFirst upper cases the first letter of a word
+func upperFirst(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+
+	return string(unicode.ToUpper(r)) + word[size:]
+}
@@ -0,0 +1,92 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenOpts_CasedName(t *testing.T) {
+	t.Parallel()
+
+	for _, toPin := range []struct {
+		Case     NamingCase
+		Words    WordSplitting
+		Name     string
+		Expected string
+	}{
+		{Name: "createPet params body", Expected: "createPet params body"},
+		{Case: CamelCase, Name: "createPet params body", Expected: "createPetParamsBody"},
+		{Case: PascalCase, Name: "createPet params body", Expected: "CreatePetParamsBody"},
+		{Case: SnakeCase, Name: "createPet params body", Expected: "create_pet_params_body"},
+		{Case: KebabCase, Name: "createPet params body", Expected: "create-pet-params-body"},
+		{Case: UpperSnakeCase, Name: "createPet params body", Expected: "CREATE_PET_PARAMS_BODY"},
+		{Case: LowerCase, Name: "createPet params body", Expected: "createpetparamsbody"},
+		{Case: KeepCase, Name: "createPet params body", Expected: "createPetParamsBody"},
+		{Case: KeepCase, Name: "Pet_Model", Expected: "Pet_Model"},
+		{Case: CamelCase, Name: "HTTPServer url", Expected: "httpServerURL"},
+		{Case: PascalCase, Name: "pet id", Expected: "PetID"},
+		{Case: CamelCase, Words: SplitPlainWords, Name: "HTTPServer url", Expected: "httpServerUrl"},
+		{Case: PascalCase, Words: SplitPlainWords, Name: "petID", Expected: "PetId"},
+	} {
+		fixture := toPin
+
+		opts := FlattenOpts{NameCase: fixture.Case, NameWords: fixture.Words}
+		assert.Equalf(t, fixture.Expected, opts.casedName(fixture.Name), "for %q in %s", fixture.Name, fixture.Case)
+	}
+}
+
+func TestFlatten_NameCase(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "naming_strategies.yml")
+
+	for _, toPin := range []struct {
+		Case     NamingCase
+		Naming   FlattenNaming
+		Expected []string
+	}{
+		{
+			Expected: []string{
+				"Order", "createOrderParamsBody", "createOrderParamsBodyNote",
+				"orderLinesItems", "orderLinesItemsProduct", "orderShippingAddress",
+			},
+		},
+		{
+			Case: PascalCase,
+			Expected: []string{
+				"CreateOrderParamsBody", "CreateOrderParamsBodyNote", "Order",
+				"OrderLinesItems", "OrderLinesItemsProduct", "OrderShippingAddress",
+			},
+		},
+		{
+			Case: SnakeCase,
+			Expected: []string{
+				"Order", "create_order_params_body", "create_order_params_body_note",
+				"order_lines_items", "order_lines_items_product", "order_shipping_address",
+			},
+		},
+		{
+			Case:   CamelCase,
+			Naming: FlattenNamingParentProperty,
+			Expected: []string{
+				"Order", "createOrderParamsBody", "createOrderParamsBodyNote",
+				"orderLinesItems", "orderLinesItemsProduct", "orderShippingAddress",
+			},
+		},
+	} {
+		fixture := toPin
+
+		t.Run(string(fixture.Case)+"/"+string(fixture.Naming), func(t *testing.T) {
+			t.Parallel()
+
+			sp := antest.LoadOrFail(t, bp)
+			require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp, NameCase: fixture.Case, Naming: fixture.Naming}))
+
+			assert.Equal(t, fixture.Expected, sortedKeys(sp.Definitions))
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package analysis
+
+// prepareDryRun makes flattening work on a copy of the spec with the DryRun option, so the analyzed spec
+// is left unchanged. Remote documents are not recorded into the Snapshot or the Lockfile either, but
+// they are still replayed or verified.
+func (f *FlattenOpts) prepareDryRun() {
+	if !f.DryRun {
+		return
+	}
+
+	clone := newSpec(CloneSpec(f.Swagger()))
+	for pointer, origin := range f.Spec.provenance {
+		clone.setProvenance(pointer, origin)
+	}
+	f.Spec = clone
+
+	if f.Snapshot != nil && !f.Snapshot.Replay {
+		f.Snapshot = nil
+	}
+
+	if f.Lockfile != nil && !f.Lockfile.Verify {
+		f.Lockfile = nil
+	}
+}
@@ -0,0 +1,67 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten_DryRun(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "reuse", "spec.yml")
+	sp := antest.LoadOrFail(t, bp)
+	before := CloneSpec(sp)
+
+	var lifted []Event
+	opts := FlattenOpts{
+		Spec:     New(sp),
+		BasePath: bp,
+		DryRun:   true,
+		OnEvent: func(event Event) {
+			if event.Kind == EventSchemaLifted {
+				lifted = append(lifted, event)
+			}
+		},
+	}
+	result, err := FlattenWithReport(opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, before, sp)
+	assert.Equal(t, before, opts.Swagger())
+
+	require.NotEmpty(t, result.Definitions)
+	assert.Len(t, result.RemovedInlineSchemas, len(lifted))
+	assert.Contains(t, result.RemovedInlineSchemas, "#/paths/~1pets/post/parameters/0/schema")
+	assert.Contains(t, result.Definitions, CreatedDefinition{
+		Name: "createPetParamsBody", Source: "#/paths/~1pets/post/parameters/0/schema",
+	})
+
+	// the same changes are made without DryRun
+	opts.DryRun = false
+	applied, err := FlattenWithReport(opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, applied.Definitions, result.Definitions)
+	assert.Equal(t, applied.RemovedInlineSchemas, result.RemovedInlineSchemas)
+	assert.Equal(t, applied.RewrittenRefs, result.RewrittenRefs)
+	assert.Contains(t, sp.Definitions, "createPetParamsBody")
+}
+
+func TestFlatten_DryRunLockfile(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "expand", "spec.yml")
+	sp := antest.LoadOrFail(t, bp)
+	lock := NewLockfile()
+
+	result, err := FlattenWithReport(FlattenOpts{Spec: New(sp), BasePath: bp, Lockfile: lock, DryRun: true})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, result.Documents)
+	assert.Empty(t, lock.Documents)
+	assert.NotContains(t, sp.Definitions, "receipt")
+}
@@ -0,0 +1,113 @@
+package analysis
+
+import (
+	"encoding/json"
+	"path"
+
+	"github.com/go-openapi/analysis/internal/flatten/replace"
+	"github.com/go-openapi/spec"
+)
+
+// EventKind is the kind of an event emitted while flattening a spec
+type EventKind string
+
+// Kinds of events emitted by Flatten
+const (
+	// EventFetchStarted is emitted before a remote document is loaded
+	EventFetchStarted EventKind = "fetch-started"
+	// EventFetchFinished is emitted after a remote document is loaded, successfully or not
+	EventFetchFinished EventKind = "fetch-finished"
+	// EventSchemaLifted is emitted when an inline schema is moved to a new definition
+	EventSchemaLifted EventKind = "schema-lifted"
+	// EventDefinitionImported is emitted when a schema from a remote document is imported as a new definition
+	EventDefinitionImported EventKind = "definition-imported"
+	// EventDefinitionRemoved is emitted when a definition is removed
+	EventDefinitionRemoved EventKind = "definition-removed"
+	// EventDefinitionRenamed is emitted when a definition is renamed (see FlattenOpts.NameMangling)
+	EventDefinitionRenamed EventKind = "definition-renamed"
+	// EventRefRewritten is emitted when a $ref is rewritten to point to another location
+	EventRefRewritten EventKind = "ref-rewritten"
+	// EventRefInlined is emitted when a $ref is replaced by the schema it points to
+	EventRefInlined EventKind = "ref-inlined"
+)
+
+// Event reports the progress of Flatten, and the changes it makes to a spec
+type Event struct {
+	Kind EventKind
+
+	// Pointer locates the altered element of the spec: the lifted inline schema, the imported, renamed or removed
+	// definition, or the rewritten $ref (e.g. "#/paths/~1pets/get/responses/200/schema").
+	// It is empty for fetches.
+	Pointer string
+
+	// Ref is the location of the fetched document, the definition an inline schema is lifted to,
+	// the location of the imported remote schema, the new location of a renamed definition, or the new $ref
+	Ref string
+
+	// Err is the error of a failed fetch
+	Err error
+}
+
+// emit reports an event to the OnEvent callback, if any, and records it for the FlattenWithReport report
+func (f *FlattenOpts) emit(event Event) {
+	if f.flattenContext != nil {
+		f.flattenContext.events = append(f.flattenContext.events, event)
+	}
+
+	if f.Spec != nil {
+		f.Spec.recordProvenance(event)
+	}
+
+	if f.OnEvent != nil {
+		f.OnEvent(event)
+	}
+}
+
+// eventsPathLoader wraps a loader of documents, so fetches are reported
+func (f *FlattenOpts) eventsPathLoader(loader func(string) (json.RawMessage, error)) func(string) (json.RawMessage, error) {
+	return func(pth string) (json.RawMessage, error) {
+		f.emit(Event{Kind: EventFetchStarted, Ref: pth})
+		doc, err := loader(pth)
+		f.emit(Event{Kind: EventFetchFinished, Ref: pth, Err: err})
+
+		return doc, err
+	}
+}
+
+// updateRef rewrites the $ref at some location of the spec
+func (f *FlattenOpts) updateRef(key string, ref spec.Ref) error {
+	if err := f.updateRefIn(f.Swagger(), key, ref); err != nil {
+		return &PointerError{Pointer: key, Cause: err}
+	}
+
+	f.emit(Event{Kind: EventRefRewritten, Pointer: key, Ref: ref.String()})
+
+	return nil
+}
+
+// updateRefIn replaces a $ref at some location of a spec or schema, retaining unknown keywords when required
+func (f *FlattenOpts) updateRefIn(root interface{}, key string, ref spec.Ref) error {
+	if f.PreserveUnknownKeywords {
+		return replace.UpdateRefKeepingKeywords(root, key, ref)
+	}
+
+	return replace.UpdateRef(root, key, ref)
+}
+
+// updateRefWithSchema replaces the $ref at some location of the spec by a schema
+func (f *FlattenOpts) updateRefWithSchema(key string, schema *spec.Schema) error {
+	if err := replace.UpdateRefWithSchema(f.Swagger(), key, schema); err != nil {
+		return &PointerError{Pointer: key, Cause: err}
+	}
+
+	f.emit(Event{Kind: EventRefInlined, Pointer: key})
+
+	return nil
+}
+
+// removeDefinition removes a definition from the spec
+func (f *FlattenOpts) removeDefinition(name string) {
+	delete(f.Swagger().Definitions, name)
+
+	f.emit(Event{Kind: EventDefinitionRemoved, Pointer: path.Join(definitionsPath, name)})
+}
@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten_OnEvent(t *testing.T) {
+	t.Parallel()
+
+	// documents are written to a new location, which the spec package has not cached yet
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spec.yml"), []byte(`
+swagger: '2.0'
+info:
+  title: events
+  version: '1.0'
+paths:
+  /pets:
+    get:
+      responses:
+        200:
+          description: pets
+          schema:
+            type: object
+            properties:
+              tag:
+                $ref: 'models.yml#/definitions/tag'
+definitions:
+  unused:
+    type: string
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "models.yml"), []byte(`
+definitions:
+  tag:
+    type: string
+`), 0o600))
+
+	bp := filepath.Join(dir, "spec.yml")
+	sp, err := antest.LoadSpec(bp)
+	require.NoError(t, err)
+
+	var (
+		mx     sync.Mutex
+		events []Event
+	)
+	require.NoError(t, Flatten(FlattenOpts{
+		Spec:         New(sp),
+		BasePath:     bp,
+		RemoveUnused: true,
+		OnEvent: func(event Event) {
+			mx.Lock()
+			defer mx.Unlock()
+
+			events = append(events, event)
+		},
+	}))
+
+	byKind := make(map[EventKind][]Event)
+	for _, event := range events {
+		byKind[event.Kind] = append(byKind[event.Kind], event)
+	}
+
+	require.Len(t, byKind[EventFetchStarted], 1)
+	require.Len(t, byKind[EventFetchFinished], 1)
+	assert.Contains(t, byKind[EventFetchStarted][0].Ref, "models.yml")
+	require.NoError(t, byKind[EventFetchFinished][0].Err)
+
+	require.Len(t, byKind[EventDefinitionImported], 1)
+	assert.Equal(t, "#/definitions/tag", byKind[EventDefinitionImported][0].Pointer)
+	assert.Equal(t, filepath.ToSlash(filepath.Join(dir, "models.yml"))+"#/definitions/tag", byKind[EventDefinitionImported][0].Ref)
+	assert.Contains(t, byKind[EventSchemaLifted], Event{
+		Kind: EventSchemaLifted, Pointer: "#/paths/~1pets/get/responses/200/schema", Ref: "#/definitions/getPetsOKBody",
+	})
+	assert.Contains(t, byKind[EventRefRewritten], Event{
+		Kind: EventRefRewritten, Pointer: "#/paths/~1pets/get/responses/200/schema/properties/tag", Ref: "#/definitions/tag",
+	})
+	assert.Contains(t, byKind[EventDefinitionRemoved], Event{Kind: EventDefinitionRemoved, Pointer: "#/definitions/unused"})
+
+	assert.Contains(t, sp.Definitions, "getPetsOKBody")
+	assert.NotContains(t, sp.Definitions, "unused")
+}
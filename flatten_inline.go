@@ -0,0 +1,60 @@
+package analysis
+
+import (
+	"path"
+
+	"github.com/go-openapi/analysis/internal/flatten/schutils"
+	"github.com/go-openapi/spec"
+)
+
+// inlineSmallDefinitions re-inlines the definitions created while flattening with a complexity below
+// the InlineThreshold option, then removes them.
+//
+// Definitions containing a $ref are retained, so no circular $ref may be inlined.
+func inlineSmallDefinitions(opts *FlattenOpts) error {
+	opts.tracef("inlineSmallDefinitions")
+	opts.Spec.reload() // re-analyze
+
+	definitions := opts.Swagger().Definitions
+	inlined := false
+	for _, name := range sortedKeys(opts.flattenContext.created) {
+		definition, exists := definitions[name]
+		if !exists || hasRef(&definition) || SchemaComplexity(&definition).Total >= opts.InlineThreshold {
+			continue
+		}
+
+		ref := path.Join(definitionsPath, name)
+		opts.tracef("re-inlining definition %s", name)
+
+		for _, key := range sortedKeys(opts.Spec.references.allRefs) {
+			if current := opts.Spec.references.allRefs[key]; current.String() != ref {
+				continue
+			}
+
+			sch := schutils.Clone(&definition)
+			delete(sch.Extensions, "x-go-gen-location")
+			if err := opts.updateRefWithSchema(key, sch); err != nil {
+				return err
+			}
+		}
+
+		opts.removeDefinition(name)
+		inlined = true
+	}
+
+	if inlined {
+		opts.Spec.reload() // re-analyze
+	}
+
+	return nil
+}
+
+// hasRef tells if a schema or any of its children holds a $ref
+func hasRef(schema *spec.Schema) bool {
+	found := false
+	walkSchema("", schema, func(_ string, sch *spec.Schema) {
+		found = found || sch.Ref.String() != ""
+	})
+
+	return found
+}
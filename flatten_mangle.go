@@ -0,0 +1,211 @@
+package analysis
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// NameMangling is a strategy to rename the definitions with names which are unsafe for $ref's and code generators,
+// e.g. with spaces, dots, slashes or non-ASCII characters
+type NameMangling string
+
+// Strategies to mangle the names of definitions
+const (
+	// NameManglingNone keeps the names of definitions
+	NameManglingNone NameMangling = ""
+
+	// NameManglingUnsafe renames all the definitions with unsafe names (see MangleDefinitionName)
+	NameManglingUnsafe NameMangling = "unsafe"
+
+	// NameManglingOnConflict keeps the names of definitions, but renames those which become identical to another one
+	// once mangled, regardless of case (e.g. "pet.v1" and "petV1", or "Pet" and "pet"). Safe names are kept first.
+	NameManglingOnConflict NameMangling = "on-conflict"
+)
+
+// latinFolding folds the accented latin letters to ASCII
+var latinFolding = strings.NewReplacer(
+	"À", "A", "Á", "A", "Â", "A", "Ã", "A", "Ä", "A", "Å", "A", "Æ", "AE", "Ç", "C",
+	"È", "E", "É", "E", "Ê", "E", "Ë", "E", "Ì", "I", "Í", "I", "Î", "I", "Ï", "I",
+	"Ñ", "N", "Ò", "O", "Ó", "O", "Ô", "O", "Õ", "O", "Ö", "O", "Ø", "O", "Œ", "OE",
+	"Ù", "U", "Ú", "U", "Û", "U", "Ü", "U", "Ý", "Y",
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "æ", "ae", "ç", "c",
+	"è", "e", "é", "e", "ê", "e", "ë", "e", "ì", "i", "í", "i", "î", "i", "ï", "i",
+	"ñ", "n", "ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o", "œ", "oe",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ý", "y", "ÿ", "y", "ß", "ss",
+)
+
+// isSafeNameRune tells if a character may be used in the name of a definition without mangling
+func isSafeNameRune(r rune) bool {
+	return r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-')
+}
+
+// IsSafeDefinitionName tells if the name of a definition is only made of ASCII letters, digits, "_" and "-",
+// and starts with a letter or "_"
+func IsSafeDefinitionName(name string) bool {
+	for i, r := range name {
+		if !isSafeNameRune(r) || i == 0 && (unicode.IsDigit(r) || r == '-') {
+			return false
+		}
+	}
+
+	return name != ""
+}
+
+// MangleDefinitionName yields a safe name for a definition (see IsSafeDefinitionName).
+//
+// Accented latin letters are folded to ASCII, other unsafe characters separate words, which are joined
+// in camel case (e.g. "pet.v1" yields "petV1", "my model" yields "myModel" and "café" yields "cafe").
+// Other non-ASCII characters are replaced by their code point (e.g. "名前" yields "u540dU524d").
+func MangleDefinitionName(name string) string {
+	if IsSafeDefinitionName(name) {
+		return name
+	}
+
+	var (
+		mangled  strings.Builder
+		newWord  bool
+		hasWords bool
+	)
+	for _, r := range latinFolding.Replace(name) {
+		var word string
+		switch {
+		case isSafeNameRune(r):
+			word = string(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = fmt.Sprintf("u%04x", r)
+			newWord = true
+		default:
+			newWord = true
+
+			continue
+		}
+
+		if newWord && hasWords {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		newWord = false
+		hasWords = true
+		mangled.WriteString(word)
+	}
+
+	result := mangled.String()
+	if result == "" || !IsSafeDefinitionName(result) {
+		result = "_" + result
+	}
+
+	return result
+}
+
+// mangleDefinitionNames renames the definitions with unsafe or conflicting names, according to the NameMangling
+// option, and rewrites the local $ref's to these definitions
+func mangleDefinitionNames(opts *FlattenOpts) error {
+	if opts.NameMangling == NameManglingNone {
+		return nil
+	}
+
+	definitions := opts.Swagger().Definitions
+	renames := opts.definitionRenames(definitions)
+	if len(renames) == 0 {
+		return nil
+	}
+
+	for key, ref := range opts.Spec.references.allRefs {
+		if !ref.HasFragmentOnly {
+			continue
+		}
+
+		pointer := "#" + ref.GetURL().Fragment // unlike the $ref, the fragment is not URL-encoded
+		name, isDefinition := definitionOfPointer(pointer)
+		newName, isRenamed := renames[name]
+		if !isDefinition || !isRenamed {
+			continue
+		}
+
+		rest := strings.TrimPrefix(pointer, definitionsPrefix+jsonpointer.Escape(name))
+		if err := opts.updateRef(key, spec.MustCreateRef(definitionsPrefix+jsonpointer.Escape(newName)+rest)); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(renames) {
+		newName := renames[name]
+		definitions[newName] = definitions[name]
+		delete(definitions, name)
+
+		if opts.flattenContext != nil {
+			original, isMangled := opts.flattenContext.mangled[name]
+			if !isMangled {
+				original = name
+			}
+			delete(opts.flattenContext.mangled, name)
+			opts.flattenContext.mangled[newName] = original
+		}
+
+		opts.tracef("renamed definition %q to %q", name, newName)
+		opts.emit(Event{
+			Kind:    EventDefinitionRenamed,
+			Pointer: path.Join(definitionsPath, jsonpointer.Escape(name)),
+			Ref:     path.Join(definitionsPath, jsonpointer.Escape(newName)),
+		})
+	}
+
+	opts.Spec.reload()
+
+	return nil
+}
+
+// definitionRenames determines the new names of the definitions to mangle
+func (f *FlattenOpts) definitionRenames(definitions spec.Definitions) map[string]string {
+	// the names which remain, to which new names may not collide
+	kept := make(spec.Definitions, len(definitions))
+	var renamed []string
+
+	if f.NameMangling == NameManglingOnConflict {
+		// safe names are kept first, then names in lexical order
+		names := sortedKeys(definitions)
+		ordered := make([]string, 0, len(names))
+		for _, safe := range []bool{true, false} {
+			for _, name := range names {
+				if IsSafeDefinitionName(name) == safe {
+					ordered = append(ordered, name)
+				}
+			}
+		}
+
+		seen := make(map[string]bool, len(ordered))
+		for _, name := range ordered {
+			mangled := strings.ToLower(MangleDefinitionName(name))
+			if seen[mangled] {
+				renamed = append(renamed, name)
+
+				continue
+			}
+			seen[mangled] = true
+			kept[name] = definitions[name]
+		}
+	} else {
+		for _, name := range sortedKeys(definitions) {
+			if IsSafeDefinitionName(name) {
+				kept[name] = definitions[name]
+
+				continue
+			}
+			renamed = append(renamed, name)
+		}
+	}
+
+	renames := make(map[string]string, len(renamed))
+	for _, name := range renamed {
+		schema := definitions[name]
+		newName, _ := uniqifyNameWithTemplate(kept, MangleDefinitionName(name), f.CollisionTemplate, &schema)
+		kept[newName] = schema
+		renames[name] = newName
+	}
+
+	return renames
+}
@@ -0,0 +1,119 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMangleDefinitionName(t *testing.T) {
+	t.Parallel()
+
+	for _, toPin := range []struct {
+		Name     string
+		Expected string
+	}{
+		{Name: "pet", Expected: "pet"},
+		{Name: "pet_v1-beta", Expected: "pet_v1-beta"},
+		{Name: "pet.v1", Expected: "petV1"},
+		{Name: "my model", Expected: "myModel"},
+		{Name: "models/pet", Expected: "modelsPet"},
+		{Name: "café", Expected: "cafe"},
+		{Name: "Ærø", Expected: "AEro"},
+		{Name: "名前", Expected: "u540dU524d"},
+		{Name: "1pet", Expected: "_1pet"},
+		{Name: "...", Expected: "_"},
+	} {
+		fixture := toPin
+
+		mangled := MangleDefinitionName(fixture.Name)
+		assert.Equalf(t, fixture.Expected, mangled, "for %q", fixture.Name)
+		assert.Truef(t, IsSafeDefinitionName(mangled), "for %q", fixture.Name)
+	}
+}
+
+func TestFlatten_NameMangling(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "mangle.yml")
+
+	t.Run("should keep names by default", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		result, err := FlattenWithReport(FlattenOpts{Spec: New(sp), BasePath: bp, Minimal: true})
+		require.NoError(t, err)
+
+		assert.Contains(t, sp.Definitions, "pet.v1")
+		assert.Empty(t, result.Mangled)
+	})
+
+	t.Run("should mangle unsafe names", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		an := New(sp)
+		result, err := FlattenWithReport(FlattenOpts{Spec: an, BasePath: bp, Minimal: true, NameMangling: NameManglingUnsafe})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Pet", "cafe", "myModel", "pet", "petV1", "petV1OAIGen"}, sortedKeys(sp.Definitions))
+		assert.Equal(t, map[string]string{
+			"cafe":        "café",
+			"myModel":     "my model",
+			"petV1OAIGen": "pet.v1",
+		}, result.Mangled)
+		assert.Empty(t, result.Definitions)
+		assert.Empty(t, result.RemovedDefinitions)
+
+		pets := sp.Paths.Paths["/pets"]
+		assert.Equal(t, "#/definitions/petV1OAIGen", pets.Get.Responses.StatusCodeResponses[200].Schema.Items.Schema.Ref.String())
+		assert.Equal(t, "#/definitions/myModel", pets.Get.Responses.StatusCodeResponses[404].Schema.Ref.String())
+
+		pet := sp.Definitions["petV1OAIGen"]
+		owner, tag := pet.Properties["owner"], pet.Properties["tag"]
+		assert.Equal(t, "#/definitions/cafe", owner.Ref.String())
+		assert.Empty(t, tag.Ref.String()) // the JSON pointer to a simple schema is inlined
+		assert.Equal(t, spec.StringOrArray{"string"}, tag.Type)
+
+		assert.Equal(t, Provenance{Document: absoluteBasePath(bp), Pointer: "#/definitions/café"}, an.ProvenanceOf("#/definitions/cafe"))
+	})
+
+	t.Run("should mangle conflicting names", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		var renamed []Event
+		result, err := FlattenWithReport(FlattenOpts{
+			Spec: New(sp), BasePath: bp, Minimal: true, NameMangling: NameManglingOnConflict,
+			OnEvent: func(event Event) {
+				if event.Kind == EventDefinitionRenamed {
+					renamed = append(renamed, event)
+				}
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Pet", "café", "my model", "petOAIGen", "petV1", "petV1OAIGen"}, sortedKeys(sp.Definitions))
+		assert.Equal(t, map[string]string{"petOAIGen": "pet", "petV1OAIGen": "pet.v1"}, result.Mangled)
+		assert.Equal(t, []Event{
+			{Kind: EventDefinitionRenamed, Pointer: "#/definitions/pet", Ref: "#/definitions/petOAIGen"},
+			{Kind: EventDefinitionRenamed, Pointer: "#/definitions/pet.v1", Ref: "#/definitions/petV1OAIGen"},
+		}, renamed)
+	})
+
+	t.Run("should leave only safe names after a full flattening", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		_, err := FlattenWithReport(FlattenOpts{Spec: New(sp), BasePath: bp, NameMangling: NameManglingUnsafe})
+		require.NoError(t, err)
+
+		for name := range sp.Definitions {
+			assert.Truef(t, IsSafeDefinitionName(name), "unexpected name %q", name)
+		}
+	})
+}
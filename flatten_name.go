@@ -1,9 +1,13 @@
 package analysis
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/go-openapi/analysis/internal/flatten/operations"
@@ -24,16 +28,22 @@ type InlineSchemaNamer struct {
 
 // Name yields a new name for the inline schema
 func (isn *InlineSchemaNamer) Name(key string, schema *spec.Schema, aschema *AnalyzedSchema) error {
-	debugLog("naming inlined schema at %s", key)
+	isn.opts.tracef("naming inlined schema at %s", key)
+
+	if isn.opts.ReuseExistingDefinitions {
+		if name, found := isn.identicalDefinition(schema); found {
+			return isn.reuseDefinition(key, name)
+		}
+	}
 
 	parts := sortref.KeyParts(key)
-	for _, name := range namesFromKey(parts, aschema, isn.Operations) {
+	for _, name := range isn.names(key, parts, schema, aschema) {
 		if name == "" {
 			continue
 		}
 
 		// create unique name
-		newName, isOAIGen := uniqifyName(isn.Spec.Definitions, swag.ToJSONName(name))
+		newName, isOAIGen := isn.opts.uniqueName(key, name, schema)
 
 		// clone schema
 		sch := schutils.Clone(schema)
@@ -41,35 +51,11 @@ func (isn *InlineSchemaNamer) Name(key string, schema *spec.Schema, aschema *Ana
 		// replace values on schema
 		if err := replace.RewriteSchemaToRef(isn.Spec, key,
 			spec.MustCreateRef(path.Join(definitionsPath, newName))); err != nil {
-			return fmt.Errorf("error while creating definition %q from inline schema: %w", newName, err)
+			return &PointerError{Pointer: key, Cause: fmt.Errorf("could not create definition %q from inline schema: %w", newName, err)}
 		}
 
-		// rewrite any dependent $ref pointing to this place,
-		// when not already pointing to a top-level definition.
-		//
-		// NOTE: this is important if such referers use arbitrary JSON pointers.
-		an := New(isn.Spec)
-		for k, v := range an.references.allRefs {
-			r, erd := replace.DeepestRef(isn.opts.Swagger(), isn.opts.ExpandOpts(false), v)
-			if erd != nil {
-				return fmt.Errorf("at %s, %w", k, erd)
-			}
-
-			if isn.opts.flattenContext != nil {
-				isn.opts.flattenContext.warnings = append(isn.opts.flattenContext.warnings, r.Warnings...)
-			}
-
-			if r.Ref.String() != key && (r.Ref.String() != path.Join(definitionsPath, newName) || path.Dir(v.String()) == definitionsPath) {
-				continue
-			}
-
-			debugLog("found a $ref to a rewritten schema: %s points to %s", k, v.String())
-
-			// rewrite $ref to the new target
-			if err := replace.UpdateRef(isn.Spec, k,
-				spec.MustCreateRef(path.Join(definitionsPath, newName))); err != nil {
-				return err
-			}
+		if err := isn.rewriteDependentRefs(key, newName); err != nil {
+			return err
 		}
 
 		// NOTE: this extension is currently not used by go-swagger (provided for information only)
@@ -77,13 +63,17 @@ func (isn *InlineSchemaNamer) Name(key string, schema *spec.Schema, aschema *Ana
 
 		// save cloned schema to definitions
 		schutils.Save(isn.Spec, newName, sch)
+		if isn.flattenContext != nil {
+			isn.flattenContext.created[newName] = true
+		}
+		isn.opts.emit(Event{Kind: EventSchemaLifted, Pointer: key, Ref: path.Join(definitionsPath, newName)})
 
 		// keep track of created refs
 		if isn.flattenContext == nil {
 			continue
 		}
 
-		debugLog("track created ref: key=%s, newName=%s, isOAIGen=%t", key, newName, isOAIGen)
+		isn.opts.tracef("track created ref: key=%s, newName=%s, isOAIGen=%t", key, newName, isOAIGen)
 		resolved := false
 
 		if _, ok := isn.flattenContext.newRefs[key]; ok {
@@ -103,8 +93,166 @@ func (isn *InlineSchemaNamer) Name(key string, schema *spec.Schema, aschema *Ana
 	return nil
 }
 
+// names yields the candidate names of the definition created from an inline schema, according to the Naming option
+func (isn *InlineSchemaNamer) names(key string, parts sortref.SplitKey, schema *spec.Schema, aschema *AnalyzedSchema) []string {
+	switch isn.opts.Naming {
+	case FlattenNamingCustom:
+		if isn.opts.NameFunc != nil {
+			if name := isn.opts.NameFunc(key, schema); name != "" {
+				return []string{name}
+			}
+		}
+	case FlattenNamingParentProperty:
+		if names := parentPropertyNames(parts, aschema, isn.Operations); len(names) > 0 {
+			for i, name := range names {
+				names[i] = isn.opts.casedName(name)
+			}
+
+			return names
+		}
+	}
+
+	names := namesFromKey(parts, aschema, isn.Operations)
+	for i, name := range names {
+		names[i] = isn.opts.definitionName(name)
+	}
+
+	return names
+}
+
+// rewriteDependentRefs rewrites any dependent $ref pointing to a schema moved to a definition,
+// when not already pointing to a top-level definition.
+//
+// NOTE: this is important if such referers use arbitrary JSON pointers.
+func (isn *InlineSchemaNamer) rewriteDependentRefs(key, newName string) error {
+	an := newSpec(isn.Spec)
+	for k, v := range an.references.allRefs {
+		r, erd := replace.DeepestRef(isn.opts.Swagger(), isn.opts.ExpandOpts(false), v)
+		if erd != nil {
+			return &RefError{Pointer: k, Ref: v.String(), Cause: erd}
+		}
+
+		if isn.opts.flattenContext != nil {
+			isn.opts.flattenContext.warn(k, r.Warnings)
+		}
+
+		if r.Ref.String() != key && (r.Ref.String() != path.Join(definitionsPath, newName) || path.Dir(v.String()) == definitionsPath) {
+			continue
+		}
+
+		isn.opts.tracef("found a $ref to a rewritten schema: %s points to %s", k, v.String())
+
+		// rewrite $ref to the new target
+		if err := isn.opts.updateRef(k, spec.MustCreateRef(path.Join(definitionsPath, newName))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// identicalDefinition finds an existing definition identical to an inline schema
+func (isn *InlineSchemaNamer) identicalDefinition(schema *spec.Schema) (string, bool) {
+	fingerprint := schemaFingerprint(schema)
+	if fingerprint == "" {
+		return "", false
+	}
+
+	for _, name := range sortedKeys(isn.Spec.Definitions) {
+		definition := isn.Spec.Definitions[name]
+		if schemaFingerprint(&definition) == fingerprint {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// reuseDefinition replaces an inline schema by a $ref to an existing definition
+func (isn *InlineSchemaNamer) reuseDefinition(key, name string) error {
+	isn.opts.tracef("reusing existing definition %s for inlined schema at %s", name, key)
+
+	ref := path.Join(definitionsPath, name)
+	if err := replace.RewriteSchemaToRef(isn.Spec, key, spec.MustCreateRef(ref)); err != nil {
+		return &PointerError{Pointer: key, Cause: fmt.Errorf("could not reuse definition %q for inline schema: %w", name, err)}
+	}
+
+	if err := isn.rewriteDependentRefs(key, name); err != nil {
+		return err
+	}
+
+	isn.opts.emit(Event{Kind: EventRefRewritten, Pointer: key, Ref: ref})
+	if isn.flattenContext != nil {
+		isn.flattenContext.reused = append(isn.flattenContext.reused, key)
+	}
+
+	return nil
+}
+
+// schemaFingerprint yields a digest of a schema, regardless of the x-go-gen-location extension set by Flatten
+func schemaFingerprint(schema *spec.Schema) string {
+	doc := schemaAsJSON(schema)
+	delete(doc, "x-go-gen-location")
+
+	buf, err := json.Marshal(doc)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(buf)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// DefaultCollisionTemplate is the template of the names given to definitions on name collisions,
+// e.g. "petOAIGen", then "petOAIGen1", "petOAIGen2", ...
+const DefaultCollisionTemplate = "{name}OAIGen{n}"
+
+// FlattenNaming is a strategy to name the definitions created from inline schemas when flattening
+type FlattenNaming string
+
+// Strategies to name the definitions created from inline schemas
+const (
+	// FlattenNamingPointerBased names definitions after all the segments of the location of the inline schema,
+	// e.g. "orderLinesItemsProduct" for "#/definitions/Order/properties/lines/items/properties/product"
+	FlattenNamingPointerBased FlattenNaming = ""
+
+	// FlattenNamingParentProperty names properties after their parent and the property,
+	// e.g. "OrderShippingAddress" for "#/definitions/Order/properties/shippingAddress".
+	// Other inline schemas are named after their location.
+	FlattenNamingParentProperty FlattenNaming = "parent-property"
+
+	// FlattenNamingCustom names definitions with FlattenOpts.NameFunc. Inline schemas it yields no name for
+	// are named after their location.
+	FlattenNamingCustom FlattenNaming = "custom"
+)
+
+// uniqueName yields a unique name for a definition created from the schema at some source location, and keeps track
+// of the renaming when resolving a name collision
+func (f *FlattenOpts) uniqueName(source, name string, schema *spec.Schema) (string, bool) {
+	newName, isOAIGen := uniqifyNameWithTemplate(f.Swagger().Definitions, name, f.CollisionTemplate, schema)
+	if isOAIGen && f.flattenContext != nil {
+		f.flattenContext.renamed[source] = newName
+		f.flattenContext.collisions[newName] = true
+	}
+
+	return newName, isOAIGen
+}
+
 // uniqifyName yields a unique name for a definition
 func uniqifyName(definitions spec.Definitions, name string) (string, bool) {
+	return uniqifyNameWithTemplate(definitions, name, DefaultCollisionTemplate, nil)
+}
+
+// uniqifyNameWithTemplate yields a unique name for a definition, applying a template on collisions.
+//
+// The template supports the following placeholders:
+//   - {name}: the colliding name
+//   - {n}: a counter, empty at the first attempt
+//   - {hash}: a short digest of the schema
+//
+// A counter is appended when the template has no {n} placeholder and the first attempt collides.
+func uniqifyNameWithTemplate(definitions spec.Definitions, name, template string, schema *spec.Schema) (string, bool) {
 	isOAIGen := false
 	if name == "" {
 		name = "oaiGen"
@@ -128,21 +276,47 @@ func uniqifyName(definitions spec.Definitions, name string) (string, bool) {
 		return name, isOAIGen
 	}
 
-	name += "OAIGen"
+	if template == "" {
+		template = DefaultCollisionTemplate
+	}
+
+	var hash string
+	if strings.Contains(template, "{hash}") {
+		hash = schemaFingerprint(schema)
+		if len(hash) > collisionHashLength {
+			hash = hash[:collisionHashLength]
+		}
+	}
+
+	if !strings.Contains(template, "{n}") {
+		template += "{n}"
+	}
+
 	isOAIGen = true
 	var idx int
-	unique := name
+	unique := collisionName(template, name, hash, idx)
 	_, known := definitions[unique]
 
-	for known {
+	for known || unique == name {
 		idx++
-		unique = fmt.Sprintf("%s%d", name, idx)
+		unique = collisionName(template, name, hash, idx)
 		_, known = definitions[unique]
 	}
 
 	return unique, isOAIGen
 }
 
+const collisionHashLength = 8
+
+func collisionName(template, name, hash string, idx int) string {
+	var counter string
+	if idx > 0 {
+		counter = strconv.Itoa(idx)
+	}
+
+	return strings.NewReplacer("{name}", name, "{hash}", hash, "{n}", counter).Replace(template)
+}
+
 func namesFromKey(parts sortref.SplitKey, aschema *AnalyzedSchema, operations map[string]operations.OpRef) []string {
 	var (
 		baseNames  [][]string
@@ -172,6 +346,58 @@ func namesFromKey(parts sortref.SplitKey, aschema *AnalyzedSchema, operations ma
 	return result
 }
 
+// parentPropertyNames names a property (or its items) after its parent and the property, e.g. "OrderShippingAddress"
+// for "#/definitions/Order/properties/shippingAddress".
+//
+// The parent is the definition holding the property, the name the parent would get when it is a property too,
+// or the name it would get from its location otherwise. Schemas which are not properties yield no name.
+func parentPropertyNames(parts sortref.SplitKey, aschema *AnalyzedSchema, operations map[string]operations.OpRef) []string {
+	property := -1
+	for i := len(parts) - 1; i > 0 && property < 0; i-- {
+		// the name of a property follows an odd number of "properties" segments
+		count := 0
+		for j := i - 1; j > 0 && parts[j] == "properties"; j-- {
+			count++
+		}
+
+		if count%2 != 0 {
+			property = i
+		}
+	}
+
+	if property < 0 {
+		return nil
+	}
+
+	suffix := swag.ToGoName(parts[property])
+	for _, part := range parts[property+1:] {
+		for _, segment := range partAdder(aschema)(part) {
+			suffix += swag.ToGoName(segment)
+		}
+	}
+
+	parent := parts[:property-1]
+	if len(parent) == 2 && parent.IsDefinition() {
+		return []string{parent.DefinitionName() + suffix}
+	}
+
+	parents := parentPropertyNames(parent, &AnalyzedSchema{}, operations)
+	if len(parents) == 0 {
+		for _, name := range namesFromKey(parent, &AnalyzedSchema{}, operations) {
+			parents = append(parents, swag.ToJSONName(name))
+		}
+	}
+
+	names := make([]string, 0, len(parents))
+	for _, name := range parents {
+		if name != "" {
+			names = append(names, name+suffix)
+		}
+	}
+
+	return names
+}
+
 func namesForParam(parts sortref.SplitKey, operations map[string]operations.OpRef) ([][]string, int) {
 	var (
 		baseNames  [][]string
@@ -257,24 +483,25 @@ func partAdder(aschema *AnalyzedSchema) sortref.PartAdder {
 }
 
 func nameFromRef(ref spec.Ref) string {
+	return swag.ToJSONName(rawNameFromRef(ref))
+}
+
+// rawNameFromRef yields the words a definition imported from a remote $ref is named after:
+// the last segment of the JSON pointer, the base name of the document or the host
+func rawNameFromRef(ref spec.Ref) string {
 	u := ref.GetURL()
 	if u.Fragment != "" {
-		return swag.ToJSONName(path.Base(u.Fragment))
+		return path.Base(u.Fragment)
 	}
 
 	if u.Path != "" {
 		bn := path.Base(u.Path)
 		if bn != "" && bn != "/" {
-			ext := path.Ext(bn)
-			if ext != "" {
-				return swag.ToJSONName(bn[:len(bn)-len(ext)])
-			}
-
-			return swag.ToJSONName(bn)
+			return strings.TrimSuffix(bn, path.Ext(bn))
 		}
 	}
 
-	return swag.ToJSONName(strings.ReplaceAll(u.Host, ".", " "))
+	return strings.ReplaceAll(u.Host, ".", " ")
 }
 
 // GenLocation indicates from which section of the specification (models or operations) a definition has been created.
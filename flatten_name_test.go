@@ -466,6 +466,68 @@ func TestName_InlinedSchemas(t *testing.T) {
 	}
 }
 
+func TestName_ReuseExistingDefinitions(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "reuse", "spec.yml")
+
+	t.Run("with ReuseExistingDefinitions", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp, ReuseExistingDefinitions: true}))
+
+		pets := sp.Paths.Paths["/pets"]
+		assert.Equal(t, "#/definitions/pet", pets.Get.Responses.StatusCodeResponses[200].Schema.Items.Schema.Ref.String())
+		assert.NotContains(t, sp.Definitions, "listPetsOKBodyItems")
+
+		// not identical to an existing definition
+		assert.Equal(t, "#/definitions/createPetParamsBody", pets.Post.Parameters[0].Schema.Ref.String())
+		assert.Contains(t, sp.Definitions, "createPetParamsBody")
+	})
+
+	t.Run("without ReuseExistingDefinitions", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp}))
+
+		pets := sp.Paths.Paths["/pets"]
+		assert.Equal(t, "#/definitions/listPetsOKBodyItems", pets.Get.Responses.StatusCodeResponses[200].Schema.Items.Schema.Ref.String())
+	})
+}
+
+func TestName_UniqifyNameWithTemplate(t *testing.T) {
+	t.Parallel()
+
+	definitions := spec.Definitions{
+		"pet":       *spec.StringProperty(),
+		"petOAIGen": *spec.StringProperty(),
+		"petV":      *spec.StringProperty(),
+	}
+
+	for _, toPin := range []struct {
+		Template string
+		Name     string
+		Expected string
+		Conflict bool
+	}{
+		{Template: "", Name: "owner", Expected: "owner"},
+		{Template: "", Name: "Pet", Expected: "PetOAIGen", Conflict: true},
+		{Template: "", Name: "pet", Expected: "petOAIGen1", Conflict: true},
+		{Template: "{name}V{n}", Name: "pet", Expected: "petV1", Conflict: true},
+		{Template: "{name}_", Name: "pet", Expected: "pet_", Conflict: true},
+		{Template: "{name}{n}", Name: "pet", Expected: "pet1", Conflict: true},
+		{Template: "{name}_{hash}", Name: "pet", Expected: "pet_" + schemaFingerprint(spec.StringProperty())[:8], Conflict: true},
+	} {
+		fixture := toPin
+
+		name, conflict := uniqifyNameWithTemplate(definitions, fixture.Name, fixture.Template, spec.StringProperty())
+		assert.Equalf(t, fixture.Expected, name, "for template %q", fixture.Template)
+		assert.Equal(t, fixture.Conflict, conflict)
+	}
+}
+
 func TestFlattenSchema_UnitGuards(t *testing.T) {
 	t.Parallel()
 
@@ -481,3 +543,58 @@ func definitionPtr(key string) string {
 
 	return strings.Join(strings.Split(key, "/")[:3], "/")
 }
+
+func TestName_NamingStrategies(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "naming_strategies.yml")
+
+	for _, toPin := range []struct {
+		Title    string
+		Naming   FlattenNaming
+		NameFunc NamingFunc
+		Expected []string
+	}{
+		{
+			Title: "pointer based",
+			Expected: []string{
+				"Order", "createOrderParamsBody", "createOrderParamsBodyNote",
+				"orderLinesItems", "orderLinesItemsProduct", "orderShippingAddress",
+			},
+		},
+		{
+			Title:  "parent and property",
+			Naming: FlattenNamingParentProperty,
+			Expected: []string{
+				"Order", "OrderLinesItems", "OrderLinesItemsProduct", "OrderShippingAddress",
+				"createOrderParamsBody", "createOrderParamsBodyNote",
+			},
+		},
+		{
+			Title:  "custom",
+			Naming: FlattenNamingCustom,
+			NameFunc: func(pointer string, _ *spec.Schema) string {
+				if strings.HasPrefix(pointer, "#/definitions/") {
+					return "Model" + strings.ReplaceAll(strings.TrimPrefix(pointer, "#/definitions/Order"), "/", "_")
+				}
+
+				return "" // named after the location
+			},
+			Expected: []string{
+				"Model_properties_lines_items", "Model_properties_lines_items_properties_product",
+				"Model_properties_shippingAddress", "Order", "createOrderParamsBody", "createOrderParamsBodyNote",
+			},
+		},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.Title, func(t *testing.T) {
+			t.Parallel()
+
+			sp := antest.LoadOrFail(t, bp)
+			require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp, Naming: fixture.Naming, NameFunc: fixture.NameFunc}))
+
+			assert.Equal(t, fixture.Expected, sortedKeys(sp.Definitions))
+		})
+	}
+}
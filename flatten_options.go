@@ -1,7 +1,9 @@
 package analysis
 
 import (
+	"io/fs"
 	"log"
+	"strings"
 
 	"github.com/go-openapi/spec"
 )
@@ -14,11 +16,36 @@ import (
 //
 // If none specified, relative references (e.g. "$ref": "folder/schema.yaml#/definitions/...")
 // found in the spec are searched from the current working directory.
+//
+// When a FS is specified, local files are read from this file system instead of the OS file system
+// (e.g. a spec embedded with go:embed). The BasePath is then a path in FS.
 type FlattenOpts struct {
 	Spec           *Spec    // The analyzed spec to work with
 	flattenContext *context // Internal context to track flattening activity
 
 	BasePath string // The location of the root document for this spec to resolve relative $ref
+	FS       fs.FS  // The file system to read local documents from. Defaults to the OS file system
+
+	// Snapshot records remote documents into a content-addressed store, or replays them from it
+	Snapshot *Snapshot
+
+	// Lockfile records the hashes of remote documents, or verifies them against a lock
+	Lockfile *Lockfile
+
+	// AllowedRefHosts restricts the hosts remote documents may be fetched from (e.g. "schemas.example.com",
+	// "*.example.com", "localhost:8080"). All hosts are allowed when empty.
+	//
+	// DeniedRefHosts lists hosts remote documents may not be fetched from, even when allowed.
+	//
+	// Fetching a document from another host fails with a *RefHostError. Local files are not restricted.
+	AllowedRefHosts []string
+	DeniedRefHosts  []string
+
+	// AlgorithmVersion pins the version of the flattening algorithm (e.g. "v0.21"), so that upgrading this library
+	// does not silently change the names and structure of the flattened spec. Defaults to FlattenAlgorithmLatest.
+	//
+	// Flattening fails with an *UnknownAlgorithmError for versions not listed by FlattenAlgorithmVersions.
+	AlgorithmVersion string
 
 	// Flattening options
 	Expand          bool // When true, skip flattening the spec and expand it instead (if Minimal is false)
@@ -27,17 +54,217 @@ type FlattenOpts struct {
 	RemoveUnused    bool // When true, remove unused parameters, responses and definitions after expansion/flattening
 	ContinueOnError bool // Continue when spec expansion issues are found
 
+	// InlineParamsAndResponses moves the complex schemas of shared parameters and responses to definitions, before
+	// $ref's to these parameters and responses are expanded into operations (e.g. "#/parameters/pet/schema" is moved
+	// to "#/definitions/petBody"). All the operations then refer to the same definitions, instead of lifting a copy
+	// of these schemas for every operation. This applies in Minimal mode too.
+	InlineParamsAndResponses bool
+
+	// InlineThreshold re-inlines the definitions created while flattening with a complexity below this threshold
+	// (e.g. a string enum imported from a remote document), instead of keeping one-line definitions.
+	// Definitions containing a $ref are never re-inlined. Zero disables re-inlining.
+	//
+	// The complexity is the Total of SchemaComplexity: a threshold of 2 re-inlines schemas without children,
+	// such as a primitive with a format.
+	InlineThreshold int
+
+	// Scope restricts flattening to some parts of the spec, as JSON pointers (e.g. "#/paths/~1pets", "#/definitions"),
+	// leaving the rest untouched. The whole spec is flattened when empty.
+	//
+	// Definitions created from the scope are flattened as well. Schemas located out of scope, but targeted by
+	// JSON pointers from the scope, may still be moved to definitions.
+	// RemoveUnused is ignored when a scope is specified.
+	Scope []string
+
+	// Vendor extensions filtering.
+	//
+	// Extension names are matched case-insensitively. A name ending with "*" matches all extensions
+	// with this prefix (e.g. "x-internal-*").
+	StripExtensions []string // Remove these vendor extensions from the flattened spec
+	KeepExtensions  []string // When not empty, retain only these vendor extensions in the flattened spec
+
+	// OnEvent is called with the events reporting the progress of flattening (e.g. remote documents being fetched)
+	// and the changes made to the spec (e.g. inline schemas lifted to definitions, $ref's rewritten)
+	OnEvent func(Event)
+
+	// ReuseExistingDefinitions replaces an inline schema identical to an existing definition by a $ref to
+	// this definition, instead of creating a new definition. This applies to full flattening only.
+	ReuseExistingDefinitions bool
+
+	// CollisionTemplate is the template of the names given to definitions on name collisions.
+	// Defaults to DefaultCollisionTemplate.
+	//
+	// The template supports the placeholders {name} (the colliding name), {n} (a counter, empty at the first attempt)
+	// and {hash} (a short digest of the schema, stable across runs), e.g. "{name}_{hash}" or "{name}V{n}".
+	CollisionTemplate string
+
+	// Naming is the strategy to name the definitions created from inline schemas.
+	// Defaults to FlattenNamingPointerBased.
+	//
+	// NameFunc names these definitions with the FlattenNamingCustom strategy.
+	Naming   FlattenNaming
+	NameFunc NamingFunc
+
+	// NameCase is the case of the names given to the definitions created from inline schemas or imported
+	// from remote documents: CamelCase, PascalCase, SnakeCase, KebabCase, UpperSnakeCase, LowerCase, or KeepCase
+	// to keep the case of the words names are made of (e.g. "Pet_Model" for "models.yml#/definitions/Pet_Model").
+	// Defaults to the historical camel case. Names given by a NameFunc are not re-cased.
+	//
+	// NameWords tells how initialisms (e.g. "ID", "URL", "HTTP") are cased in these names. Defaults to SplitInitialisms.
+	NameCase  NamingCase
+	NameWords WordSplitting
+
+	// NameMangling renames the definitions with names which are unsafe for $ref's and code generators
+	// (e.g. "pet.v1", "my model"), or which only differ by case or punctuation. Names are kept by default.
+	//
+	// Definitions are renamed before and after flattening, so the definitions created are renamed too.
+	// The original names are reported by FlattenWithReport.
+	NameMangling NameMangling
+
+	// DryRun plans flattening without changing the spec: the changes which would be made are reported by
+	// FlattenWithReport (the schemas to lift, the names of the definitions to create, the $ref's to rewrite)
+	// and by OnEvent, but applied to a copy of the spec. Remote documents are still fetched.
+	//
+	// For instance, len(result.RemovedInlineSchemas) counts the inline schemas a spec would require to lift.
+	DryRun bool
+
+	// PreserveUnknownKeywords retains the vendor extensions and the unknown keywords (e.g. JSON schema keywords
+	// not supported by swagger 2.0) found alongside a $ref, when this $ref is rewritten (e.g. to a definition
+	// imported from a remote document). By default, only the $ref is kept.
+	PreserveUnknownKeywords bool
+
+	// Logger traces the decisions made while flattening (e.g. $ref's resolved, definitions renamed or removed).
+	// Defaults to the Logger set with SetLogger.
+	Logger Logger
+
 	/* Extra keys */
 	_ struct{} // require keys
 }
 
 // ExpandOpts creates a spec.ExpandOptions to configure expanding a specification document.
 func (f *FlattenOpts) ExpandOpts(skipSchemas bool) *spec.ExpandOptions {
-	return &spec.ExpandOptions{
+	opts := &spec.ExpandOptions{
 		RelativeBase:    f.BasePath,
 		SkipSchemas:     skipSchemas,
 		ContinueOnError: f.ContinueOnError,
 	}
+
+	if f.FS != nil {
+		opts.PathLoader = fsPathLoader(f.FS)
+	}
+
+	if f.Snapshot != nil {
+		loader := opts.PathLoader
+		if loader == nil {
+			loader = spec.PathLoader
+		}
+
+		opts.PathLoader = f.Snapshot.pathLoader(loader)
+	}
+
+	if f.Lockfile != nil {
+		loader := opts.PathLoader
+		if loader == nil {
+			loader = spec.PathLoader
+		}
+
+		opts.PathLoader = f.Lockfile.pathLoader(loader)
+	}
+
+	if f.OnEvent != nil || f.flattenContext != nil {
+		loader := opts.PathLoader
+		if loader == nil {
+			loader = spec.PathLoader
+		}
+
+		opts.PathLoader = f.eventsPathLoader(loader)
+	}
+
+	if f.hasRefHostsPolicy() {
+		loader := opts.PathLoader
+		if loader == nil {
+			loader = spec.PathLoader
+		}
+
+		opts.PathLoader = f.refHostsPathLoader(loader)
+	}
+
+	return opts
+}
+
+// inScope tells if a location of the spec should be flattened, according to the Scope option
+func (f *FlattenOpts) inScope(pointer string) bool {
+	if len(f.Scope) == 0 || inScopes(pointer, f.Scope) {
+		return true
+	}
+
+	if f.flattenContext == nil {
+		return false
+	}
+
+	name, isDefinition := definitionOfPointer(pointer)
+
+	return isDefinition && f.flattenContext.created[name]
+}
+
+// scopedRefs retains the $ref's in scope
+func (f *FlattenOpts) scopedRefs(refs map[string]spec.Ref) map[string]spec.Ref {
+	if len(f.Scope) == 0 {
+		return refs
+	}
+
+	scoped := make(map[string]spec.Ref, len(refs))
+	for key, ref := range refs {
+		if f.inScope(key) {
+			scoped[key] = ref
+		}
+	}
+
+	return scoped
+}
+
+// scopePointers normalizes scopes as JSON pointers with a leading "#" (e.g. "/paths/~1pets" yields "#/paths/~1pets")
+func scopePointers(scopes []string) []string {
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	pointers := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		pointers = append(pointers, "#"+strings.TrimPrefix(scope, "#"))
+	}
+
+	return pointers
+}
+
+// keepExtension tells if a vendor extension is retained by the StripExtensions and KeepExtensions options
+func (f *FlattenOpts) keepExtension(name string) bool {
+	if len(f.KeepExtensions) > 0 && !matchesExtension(name, f.KeepExtensions) {
+		return false
+	}
+
+	return !matchesExtension(name, f.StripExtensions)
+}
+
+func matchesExtension(name string, patterns []string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if strings.HasSuffix(pattern, "*") && strings.HasPrefix(name, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+
+		if name == pattern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tracef traces a decision made while flattening
+func (f *FlattenOpts) tracef(format string, args ...interface{}) {
+	tracef(f.Logger, format, args...)
 }
 
 // Swagger gets the swagger specification for this flatten operation
@@ -52,27 +279,7 @@ func (f *FlattenOpts) croak() {
 		return
 	}
 
-	reported := make(map[string]bool, len(f.flattenContext.newRefs))
-	for _, v := range f.Spec.references.allRefs {
-		// warns about duplicate handling
-		for _, r := range f.flattenContext.newRefs {
-			if r.isOAIGen && r.path == v.String() {
-				reported[r.newName] = true
-			}
-		}
-	}
-
-	for k := range reported {
-		log.Printf("warning: duplicate flattened definition name resolved as %s", k)
-	}
-
-	// warns about possible type mismatches
-	uniqueMsg := make(map[string]bool)
-	for _, msg := range f.flattenContext.warnings {
-		if _, ok := uniqueMsg[msg]; ok {
-			continue
-		}
-		log.Printf("warning: %s", msg)
-		uniqueMsg[msg] = true
+	for _, w := range f.warnings() {
+		log.Printf("warning: %s", w.Message)
 	}
 }
@@ -0,0 +1,149 @@
+package analysis
+
+import (
+	"path"
+	"strings"
+)
+
+// FlattenResult reports the changes made by flattening a spec
+type FlattenResult struct {
+	// Definitions lists the definitions created by flattening, with their source
+	Definitions []CreatedDefinition
+
+	// RemovedDefinitions lists the names of the definitions of the original spec which have been removed
+	// (e.g. with FlattenOpts.RemoveUnused)
+	RemovedDefinitions []string
+
+	// RemovedInlineSchemas lists the locations of the inline schemas replaced by a $ref to a definition
+	RemovedInlineSchemas []string
+
+	// RewrittenRefs maps the locations of the $ref's rewritten by flattening to their new value
+	// (e.g. "#/paths/~1pets/get/responses/200/schema": "#/definitions/pet")
+	RewrittenRefs map[string]string
+
+	// Documents lists the remote documents loaded or imported while flattening
+	Documents []string
+
+	// Renamed maps the sources of the definitions created with a new name to resolve a name collision
+	// (e.g. "#/paths/~1pets/get/responses/200/schema" or "models.yml#/definitions/pet") to this name
+	// (e.g. "petOAIGen"). See FlattenOpts.CollisionTemplate.
+	Renamed map[string]string
+
+	// Mangled maps the new names of the definitions renamed by FlattenOpts.NameMangling (e.g. "petV1")
+	// to their original names (e.g. "pet.v1"), so names may be mapped back to the original spec
+	Mangled map[string]string
+
+	// Warnings lists the non-fatal issues found while flattening, as reported by FlattenWithWarnings
+	Warnings []Warning
+
+	// AlgorithmVersion is the version of the flattening algorithm applied (see FlattenOpts.AlgorithmVersion)
+	AlgorithmVersion string
+}
+
+// CreatedDefinition is a definition created by flattening a spec
+type CreatedDefinition struct {
+	Name string
+
+	// Source is the location of the inline schema moved to this definition
+	// (e.g. "#/paths/~1pets/get/responses/200/schema"), or the remote $ref imported as this definition
+	// (e.g. "models.yml#/definitions/pet")
+	Source string
+}
+
+// FlattenWithReport flattens a spec like Flatten, and reports the changes made, so they may be audited or cached.
+//
+// A partial report is returned if flattening fails.
+func FlattenWithReport(opts FlattenOpts) (*FlattenResult, error) {
+	original := make(map[string]bool, len(opts.Swagger().Definitions))
+	for name := range opts.Swagger().Definitions {
+		original[name] = true
+	}
+
+	opts.flattenContext = newContext()
+	err := flatten(&opts)
+
+	return opts.result(original), err
+}
+
+// result yields the report of the changes made while flattening, given the names of the original definitions
+func (f *FlattenOpts) result(original map[string]bool) *FlattenResult {
+	definitions := f.Swagger().Definitions
+	result := &FlattenResult{
+		RewrittenRefs: make(map[string]string),
+		Renamed:       make(map[string]string, len(f.flattenContext.renamed)),
+		Mangled:       make(map[string]string, len(f.flattenContext.mangled)),
+		Warnings:      f.warnings(),
+	}
+	result.AlgorithmVersion, _ = f.algorithmVersion()
+
+	// the original definitions which have been renamed by mangling are neither removed nor created
+	renamed := make(map[string]bool, len(f.flattenContext.mangled))
+	for name, originalName := range f.flattenContext.mangled {
+		if _, exists := definitions[name]; exists {
+			result.Mangled[name] = originalName
+			renamed[originalName] = true
+		}
+	}
+
+	for _, name := range sortedKeys(original) {
+		if _, exists := definitions[name]; !exists && !renamed[name] {
+			result.RemovedDefinitions = append(result.RemovedDefinitions, name)
+		}
+	}
+
+	sources := make(map[string]string)
+	inline := make(map[string]bool)
+	documents := make(map[string]bool)
+	for _, event := range f.flattenContext.events {
+		switch event.Kind {
+		case EventSchemaLifted:
+			sources[path.Base(event.Ref)] = event.Pointer
+			inline[event.Pointer] = true
+		case EventDefinitionRenamed:
+			sources[path.Base(event.Ref)] = sources[path.Base(event.Pointer)]
+		case EventDefinitionImported:
+			sources[path.Base(event.Pointer)] = event.Ref
+			if document, _, _ := strings.Cut(event.Ref, "#"); document != "" {
+				documents[documentLocation(document)] = true
+			}
+		case EventFetchFinished:
+			if event.Err == nil {
+				documents[documentLocation(event.Ref)] = true
+			}
+		case EventRefRewritten:
+			result.RewrittenRefs[event.Pointer] = event.Ref
+		}
+	}
+
+	for _, pointer := range f.flattenContext.reused {
+		inline[pointer] = true
+	}
+
+	for _, name := range sortedKeys(definitions) {
+		if original[name] || original[result.Mangled[name]] {
+			continue
+		}
+
+		result.Definitions = append(result.Definitions, CreatedDefinition{Name: name, Source: sources[name]})
+	}
+
+	result.RemovedInlineSchemas = sortedKeys(inline)
+	result.Documents = sortedKeys(documents)
+
+	for source, name := range f.flattenContext.renamed {
+		if _, exists := definitions[name]; exists { // collisions may have been resolved since
+			result.Renamed[source] = name
+		}
+	}
+
+	return result
+}
+
+// documentLocation normalizes the location of a remote document, as an absolute file path or a URL
+func documentLocation(document string) string {
+	if strings.HasPrefix(document, "file://") {
+		return strings.TrimPrefix(document, "file://")
+	}
+
+	return absoluteBasePath(document)
+}
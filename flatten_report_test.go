@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenWithReport(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "expand", "spec.yml")
+	sp := antest.LoadOrFail(t, bp)
+	sp.Definitions["unused"] = sp.Definitions["error"]
+
+	result, err := FlattenWithReport(FlattenOpts{Spec: New(sp), BasePath: bp, RemoveUnused: true})
+	require.NoError(t, err)
+
+	require.Len(t, result.Definitions, 2)
+	assert.Equal(t, "receipt", result.Definitions[0].Name)
+	assert.True(t, strings.HasSuffix(result.Definitions[0].Source, "models.yml#/definitions/receipt"), result.Definitions[0].Source)
+	assert.Equal(t, "tag", result.Definitions[1].Name)
+
+	assert.Equal(t, []string{"unused"}, result.RemovedDefinitions)
+	assert.Empty(t, result.RemovedInlineSchemas)
+	assert.Equal(t, "#/definitions/receipt", result.RewrittenRefs["#/paths/~1pets/post/responses/201/schema"])
+	assert.Equal(t, "#/definitions/tag", result.RewrittenRefs["#/definitions/receipt/properties/tag"])
+
+	require.NotEmpty(t, result.Documents)
+	assert.Contains(t, result.Documents, absoluteBasePath(filepath.Join("fixtures", "expand", "models.yml")))
+	assert.Empty(t, result.Renamed)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestFlattenWithReport_InlineSchemas(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "reuse", "spec.yml")
+	sp := antest.LoadOrFail(t, bp)
+
+	result, err := FlattenWithReport(FlattenOpts{Spec: New(sp), BasePath: bp, ReuseExistingDefinitions: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, []CreatedDefinition{
+		{Name: "createPetParamsBody", Source: "#/paths/~1pets/post/parameters/0/schema"},
+	}, result.Definitions)
+	assert.Equal(t, []string{
+		"#/paths/~1pets/get/responses/200/schema/items",
+		"#/paths/~1pets/post/parameters/0/schema",
+	}, result.RemovedInlineSchemas)
+	assert.Empty(t, result.Documents)
+}
+
+func TestFlattenWithReport_Renamed(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "oaigen", "fixture-oaigen.yaml")
+
+	t.Run("with default collision template", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		result, err := FlattenWithReport(FlattenOpts{Spec: New(sp), BasePath: bp})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"#/definitions/a/properties/a": "aAOAIGen"}, result.Renamed)
+		assert.Contains(t, sp.Definitions, "aAOAIGen")
+	})
+
+	t.Run("with hash-based collision template", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		result, err := FlattenWithReport(FlattenOpts{Spec: New(sp), BasePath: bp, CollisionTemplate: "{name}_{hash}"})
+		require.NoError(t, err)
+
+		require.Len(t, result.Renamed, 1)
+		name := result.Renamed["#/definitions/a/properties/a"]
+		assert.Regexp(t, regexp.MustCompile(`^aA_[0-9a-f]{8}$`), name)
+		assert.Contains(t, sp.Definitions, name)
+
+		// hash-based names are stable
+		again := antest.LoadOrFail(t, bp)
+		result, err = FlattenWithReport(FlattenOpts{Spec: New(again), BasePath: bp, CollisionTemplate: "{name}_{hash}"})
+		require.NoError(t, err)
+		assert.Equal(t, name, result.Renamed["#/definitions/a/properties/a"])
+	})
+}
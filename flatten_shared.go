@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"path"
+
+	"github.com/go-openapi/analysis/internal/flatten/schutils"
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// liftSharedSchemas moves the complex inline schemas of shared parameters and responses to definitions,
+// so the operations these parameters and responses are expanded into refer to the same definitions.
+//
+// The definitions are named after the shared entry, e.g. "#/parameters/pet/schema" is moved to "#/definitions/petBody".
+func liftSharedSchemas(opts *FlattenOpts) error {
+	opts.tracef("liftSharedSchemas")
+	sp := opts.Swagger()
+
+	for _, name := range sortedKeys(sp.Parameters) {
+		param := sp.Parameters[name]
+		key := "#/parameters/" + jsonpointer.Escape(name) + "/schema"
+		if param.Schema == nil || !opts.inScope(key) {
+			continue
+		}
+
+		lifted, err := opts.liftSharedSchema(key, name, param.Schema)
+		if err != nil {
+			return err
+		}
+
+		param.Schema = lifted
+		sp.Parameters[name] = param
+	}
+
+	for _, name := range sortedKeys(sp.Responses) {
+		resp := sp.Responses[name]
+		key := "#/responses/" + jsonpointer.Escape(name) + "/schema"
+		if resp.Schema == nil || !opts.inScope(key) {
+			continue
+		}
+
+		lifted, err := opts.liftSharedSchema(key, name, resp.Schema)
+		if err != nil {
+			return err
+		}
+
+		resp.Schema = lifted
+		sp.Responses[name] = resp
+	}
+
+	opts.Spec.reload() // re-analyze
+
+	return nil
+}
+
+// liftSharedSchema moves the schema of a shared parameter or response to a definition when it is complex,
+// and yields the $ref replacing it
+func (f *FlattenOpts) liftSharedSchema(key, name string, schema *spec.Schema) (*spec.Schema, error) {
+	if schema.Ref.String() != "" {
+		return schema, nil
+	}
+
+	asch, err := Schema(SchemaOpts{Schema: schema, Root: f.Swagger(), BasePath: f.BasePath, Logger: f.Logger})
+	if err != nil {
+		return nil, &SchemaError{Pointer: key, Cause: err}
+	}
+
+	if !asch.isAnalyzedAsComplex() {
+		return schema, nil
+	}
+
+	newName, _ := f.uniqueName(key, f.definitionName(name+" body"), schema)
+	f.tracef("lifting schema of shared entry at %s to %s", key, newName)
+
+	sch := schutils.Clone(schema)
+	sch.AddExtension("x-go-gen-location", "models")
+	schutils.Save(f.Swagger(), newName, sch)
+	f.flattenContext.created[newName] = true
+
+	ref := path.Join(definitionsPath, newName)
+	f.emit(Event{Kind: EventSchemaLifted, Pointer: key, Ref: ref})
+
+	return spec.RefSchema(ref), nil
+}
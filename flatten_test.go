@@ -1390,3 +1390,221 @@ func testFlattenWithDefaults(t *testing.T, bp string) *Spec {
 
 	return an
 }
+
+func TestFlatten_FilterExtensions(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stdout)
+
+	bp := filepath.Join("fixtures", "extensions", "fixture-extensions.yaml")
+
+	t.Run("should strip extensions by name or prefix", func(t *testing.T) {
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, Flatten(FlattenOpts{
+			Spec: New(sp), BasePath: bp, Minimal: true,
+			StripExtensions: []string{"X-Internal-*", "x-logo"},
+		}))
+
+		jazon := antest.AsJSON(t, sp)
+		assert.NotContains(t, jazon, "x-internal")
+		assert.NotContains(t, jazon, "x-logo")
+		assert.Contains(t, jazon, `"x-go-name": "ListPets"`)
+		assert.Contains(t, jazon, `"x-go-name": "Pet"`)
+		assert.Contains(t, jazon, "X-Rate-Limit")
+	})
+
+	t.Run("should keep only whitelisted extensions", func(t *testing.T) {
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, Flatten(FlattenOpts{
+			Spec: New(sp), BasePath: bp, Minimal: true,
+			KeepExtensions: []string{"x-go-name", "x-internal-table"},
+		}))
+
+		assert.Equal(t, spec.Extensions{"x-go-name": "Pet", "x-internal-table": "pets"}, sp.Definitions["pet"].Extensions)
+		assert.Empty(t, sp.Extensions)
+		assert.Empty(t, sp.Info.Extensions)
+		assert.Empty(t, sp.Paths.Extensions)
+		assert.Empty(t, sp.Paths.Paths["/pets"].Extensions)
+		assert.Equal(t, spec.Extensions{"x-go-name": "ListPets"}, sp.Paths.Paths["/pets"].Get.Extensions)
+	})
+}
+
+func TestFlatten_Scope(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "scope", "spec.yml")
+
+	for _, scope := range []string{"#/paths/~1pets", "/paths/~1pets"} {
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp, Scope: []string{scope}}))
+
+		// in scope: parameters are expanded, inline schemas are lifted and remote $ref's imported
+		pets := sp.Paths.Paths["/pets"].Get
+		assert.Equal(t, "limit", pets.Parameters[0].Name)
+		assert.Equal(t, "#/definitions/listPetsOKBody", pets.Responses.StatusCodeResponses[200].Schema.Ref.String())
+		require.Contains(t, sp.Definitions, "listPetsOKBody")
+		assert.Equal(t, "#/definitions/pet", sp.Definitions["listPetsOKBody"].Properties["items"].Items.Schema.Ref.String())
+
+		// definitions imported from the scope are flattened too
+		require.Contains(t, sp.Definitions, "pet")
+		assert.Equal(t, "#/definitions/owner", schemaRef(sp.Definitions["pet"].Properties["owner"]))
+		assert.Contains(t, sp.Definitions, "owner")
+
+		// out of scope: untouched
+		owners := sp.Paths.Paths["/owners"].Get
+		assert.Equal(t, "#/parameters/limit", owners.Parameters[0].Ref.String())
+		ownersBody := owners.Responses.StatusCodeResponses[200].Schema
+		assert.Empty(t, ownersBody.Ref.String())
+		assert.Equal(t, "models.yml#/definitions/owner", ownersBody.Properties["items"].Items.Schema.Ref.String())
+		assert.Empty(t, schemaRef(sp.Definitions["error"].Properties["details"]))
+		assert.Contains(t, sp.Parameters, "limit")
+	}
+}
+
+func TestFlatten_InlineParamsAndResponses(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "inline", "spec.yml")
+
+	for _, minimal := range []bool{false, true} {
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, Flatten(FlattenOpts{
+			Spec: New(sp), BasePath: bp, Minimal: minimal, RemoveUnused: true,
+			InlineParamsAndResponses: true,
+		}))
+
+		pets := sp.Paths.Paths["/pets"]
+		for _, op := range []*spec.Operation{pets.Post, pets.Put} {
+			require.Len(t, op.Parameters, 1)
+			assert.Equal(t, "pet", op.Parameters[0].Name)
+			assert.Equal(t, "#/definitions/petBody", op.Parameters[0].Schema.Ref.String())
+			assert.Equal(t, "error", op.Responses.Default.Description)
+			assert.Equal(t, "#/definitions/errorBody", op.Responses.Default.Schema.Ref.String())
+		}
+
+		assert.Equal(t, []string{"errorBody", "petBody"}, sortedKeys(sp.Definitions))
+		assert.Empty(t, sp.Parameters)
+		assert.Empty(t, sp.Responses)
+	}
+}
+
+func TestFlatten_InlineThreshold(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "inline", "small.yml")
+
+	t.Run("with InlineThreshold", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp, InlineThreshold: 2}))
+
+		// original definitions are retained
+		assert.Equal(t, []string{"code", "pet"}, sortedKeys(sp.Definitions))
+
+		pet := sp.Definitions["pet"]
+		status := pet.Properties["status"]
+		assert.Empty(t, status.Ref.String())
+		assert.Equal(t, []interface{}{"available", "sold"}, status.Enum)
+		assert.Equal(t, "date", pet.Properties["birthday"].Format)
+
+		param := sp.Paths.Paths["/pets"].Get.Parameters[0]
+		assert.Empty(t, param.Schema.Ref.String())
+		assert.Len(t, param.Schema.Enum, 2)
+	})
+
+	t.Run("without InlineThreshold", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp}))
+
+		assert.Equal(t, []string{"code", "date", "pet", "status"}, sortedKeys(sp.Definitions))
+	})
+}
+
+func TestFlatten_PreserveUnknownKeywords(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "keywords", "spec.yml")
+
+	for _, minimal := range []bool{true, false} {
+		minimal := minimal
+
+		t.Run(fmt.Sprintf("with PreserveUnknownKeywords, minimal=%t", minimal), func(t *testing.T) {
+			t.Parallel()
+
+			sp := antest.LoadOrFail(t, bp)
+			original := CloneSpec(sp)
+			require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp, Minimal: minimal, PreserveUnknownKeywords: true}))
+
+			// unrelated parts of the document are untouched
+			for _, part := range []func(*spec.Swagger) interface{}{
+				func(s *spec.Swagger) interface{} { return s.Info },
+				func(s *spec.Swagger) interface{} { return s.Definitions["unrelated"] },
+			} {
+				expected, err := json.Marshal(part(original))
+				require.NoError(t, err)
+				actual, err := json.Marshal(part(sp))
+				require.NoError(t, err)
+				assert.Equal(t, string(expected), string(actual))
+			}
+
+			owner := sp.Definitions["owner"]
+			pet := owner.Properties["pet"]
+			assert.Equal(t, "#/definitions/pet", pet.Ref.String())
+			assert.Equal(t, spec.Extensions{"x-order": float64(1)}, pet.Extensions)
+			assert.Equal(t, map[string]interface{}{"unevaluatedProperties": false}, pet.ExtraProps)
+
+			tag := owner.Properties["tag"]
+			assert.Equal(t, "#/definitions/tagsItems", tag.Ref.String())
+			assert.Equal(t, spec.Extensions{"x-order": float64(2)}, tag.Extensions)
+
+			items := sp.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200].Schema.Items.Schema
+			assert.Equal(t, "#/definitions/pet", items.Ref.String())
+			assert.Equal(t, spec.Extensions{"x-nullable": true}, items.Extensions)
+			assert.Equal(t, map[string]interface{}{"$comment": "pets may be null"}, items.ExtraProps)
+
+			assert.Equal(t, spec.Extensions{"x-model": "pet"}, sp.Definitions["pet"].Extensions)
+		})
+	}
+
+	t.Run("without PreserveUnknownKeywords", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp, Minimal: true}))
+
+		pet := sp.Definitions["owner"].Properties["pet"]
+		assert.Equal(t, "#/definitions/pet", pet.Ref.String())
+		assert.Empty(t, pet.Extensions)
+		assert.Empty(t, pet.ExtraProps)
+	})
+}
+
+func TestFlattened(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "expand", "spec.yml")
+	sp := antest.LoadOrFail(t, bp)
+	original := CloneSpec(sp)
+
+	flattened, err := Flattened(sp, FlattenOpts{BasePath: bp, Minimal: true})
+	require.NoError(t, err)
+
+	expected := CloneSpec(sp)
+	require.NoError(t, Flatten(FlattenOpts{Spec: New(expected), BasePath: bp, Minimal: true}))
+	assert.Equal(t, expected, flattened)
+	assert.Contains(t, flattened.Definitions, "receipt")
+	assert.Equal(t, original, sp)
+
+	t.Run("on error", func(t *testing.T) {
+		t.Parallel()
+
+		broken := antest.LoadOrFail(t, bp)
+		broken.Definitions["broken"] = *spec.RefSchema("missing.yml#/definitions/broken")
+
+		flattened, err := Flattened(broken, FlattenOpts{BasePath: bp, Minimal: true})
+		require.Error(t, err)
+		assert.Nil(t, flattened)
+	})
+}
@@ -0,0 +1,183 @@
+package analysis
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// FlattenDrift reports the structural differences between a freshly flattened spec and a golden one,
+// previously flattened and stored
+type FlattenDrift struct {
+	// Added lists the definitions of the flattened spec absent from the golden spec, sorted
+	Added []string
+
+	// Removed lists the definitions of the golden spec absent from the flattened spec, sorted
+	Removed []string
+
+	// Renamed maps the names of the definitions of the golden spec to their new name, for definitions
+	// found identical under another name
+	Renamed map[string]string
+
+	// Changed lists the definitions found under the same name in both specs, with a different content, sorted
+	Changed []string
+
+	// Sections lists the other top-level sections of the spec (e.g. "paths", "parameters") with a different
+	// content, sorted
+	Sections []string
+}
+
+// IsEmpty tells if the flattened spec matches the golden spec
+func (d *FlattenDrift) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Renamed) == 0 && len(d.Changed) == 0 && len(d.Sections) == 0
+}
+
+// VerifyFlatten flattens a spec and compares the result with a golden spec, e.g. a bundle checked into
+// version control, to detect drift.
+//
+// The comparison is structural, not textual: the formatting and the order of keys do not matter.
+// A definition with a new name but the same content is reported as renamed, and $ref's to renamed definitions
+// are not reported as changes.
+//
+// The spec of the options is flattened in place.
+func VerifyFlatten(opts FlattenOpts, golden *spec.Swagger) (*FlattenDrift, error) {
+	if err := Flatten(opts); err != nil {
+		return nil, err
+	}
+
+	actualDoc, err := genericJSON(opts.Swagger())
+	if err != nil {
+		return nil, err
+	}
+
+	goldenDoc, err := genericJSON(golden)
+	if err != nil {
+		return nil, err
+	}
+
+	return flattenDrift(asObject(actualDoc), asObject(goldenDoc)), nil
+}
+
+// flattenDrift compares two specs as generic JSON documents
+func flattenDrift(actual, golden map[string]interface{}) *FlattenDrift {
+	drift := &FlattenDrift{Renamed: make(map[string]string)}
+	actualDefinitions := asObject(actual["definitions"])
+	goldenDefinitions := asObject(golden["definitions"])
+
+	var added, removed []string
+	for _, name := range sortedKeys(actualDefinitions) {
+		if _, exists := goldenDefinitions[name]; !exists {
+			added = append(added, name)
+		}
+	}
+	for _, name := range sortedKeys(goldenDefinitions) {
+		if _, exists := actualDefinitions[name]; !exists {
+			removed = append(removed, name)
+		}
+	}
+
+	// definitions with the same content under another name are renamed
+	matched := make(map[string]bool)
+	for _, oldName := range removed {
+		for _, newName := range added {
+			if !matched[newName] && reflect.DeepEqual(goldenDefinitions[oldName], actualDefinitions[newName]) {
+				drift.Renamed[oldName] = newName
+				matched[newName] = true
+
+				break
+			}
+		}
+	}
+
+	for _, name := range added {
+		if !matched[name] {
+			drift.Added = append(drift.Added, name)
+		}
+	}
+	for _, name := range removed {
+		if _, isRenamed := drift.Renamed[name]; !isRenamed {
+			drift.Removed = append(drift.Removed, name)
+		}
+	}
+
+	// compare the golden spec with $ref's to renamed definitions rewritten
+	renamedRefs := make(map[string]string, len(drift.Renamed))
+	for oldName, newName := range drift.Renamed {
+		renamedRefs[definitionsPrefix+jsonpointer.Escape(oldName)] = definitionsPrefix + jsonpointer.Escape(newName)
+	}
+	golden, _ = renameRefs(golden, renamedRefs).(map[string]interface{})
+	goldenDefinitions = asObject(golden["definitions"])
+
+	for _, name := range sortedKeys(actualDefinitions) {
+		if expected, exists := goldenDefinitions[name]; exists && !reflect.DeepEqual(expected, actualDefinitions[name]) {
+			drift.Changed = append(drift.Changed, name)
+		}
+	}
+
+	sections := make(map[string]bool)
+	for section := range actual {
+		sections[section] = true
+	}
+	for section := range golden {
+		sections[section] = true
+	}
+	delete(sections, "definitions")
+
+	for section := range sections {
+		if !reflect.DeepEqual(actual[section], golden[section]) {
+			drift.Sections = append(drift.Sections, section)
+		}
+	}
+	sort.Strings(drift.Sections)
+
+	return drift
+}
+
+// renameRefs rewrites the $ref's of a generic JSON document, including $ref's to some location within
+// a renamed target (e.g. "#/definitions/pet/properties/name")
+func renameRefs(doc interface{}, renamed map[string]string) interface{} {
+	switch value := doc.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, child := range value {
+			if ref, isString := child.(string); key == "$ref" && isString {
+				result[key] = renameRef(ref, renamed)
+
+				continue
+			}
+
+			result[key] = renameRefs(child, renamed)
+		}
+
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, child := range value {
+			result[i] = renameRefs(child, renamed)
+		}
+
+		return result
+	default:
+		return doc
+	}
+}
+
+func renameRef(ref string, renamed map[string]string) string {
+	for from, to := range renamed {
+		if ref == from || strings.HasPrefix(ref, from+"/") {
+			return to + strings.TrimPrefix(ref, from)
+		}
+	}
+
+	return ref
+}
+
+// asObject yields a generic JSON object, or nil when the value is not an object
+func asObject(value interface{}) map[string]interface{} {
+	object, _ := value.(map[string]interface{})
+
+	return object
+}
@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyFlatten(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "flatten_verify.yml")
+	flattened := func(t *testing.T) *spec.Swagger {
+		sp := antest.LoadOrFail(t, bp)
+		require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp}))
+
+		return sp
+	}
+	verify := func(t *testing.T, golden *spec.Swagger) *FlattenDrift {
+		drift, err := VerifyFlatten(FlattenOpts{Spec: New(antest.LoadOrFail(t, bp)), BasePath: bp}, golden)
+		require.NoError(t, err)
+
+		return drift
+	}
+
+	t.Run("should not report drift against the same bundle", func(t *testing.T) {
+		t.Parallel()
+
+		drift := verify(t, flattened(t))
+		assert.True(t, drift.IsEmpty())
+	})
+
+	t.Run("should report drifting definitions", func(t *testing.T) {
+		t.Parallel()
+
+		golden := flattened(t)
+		require.Contains(t, golden.Definitions, "getPetsOKBodyItems")
+
+		// the golden bundle was produced with other names, and has drifted since
+		golden.Definitions["petItem"] = golden.Definitions["getPetsOKBodyItems"]
+		delete(golden.Definitions, "getPetsOKBodyItems")
+		golden.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200].Schema.Items.Schema.Ref = spec.MustCreateRef("#/definitions/petItem")
+
+		golden.Definitions["legacy"] = *spec.StringProperty()
+		delete(golden.Definitions, "store")
+
+		owner := golden.Definitions["owner"]
+		owner.Required = []string{"name"}
+		golden.Definitions["owner"] = owner
+
+		golden.Info.Version = "0.9"
+
+		drift := verify(t, golden)
+		assert.False(t, drift.IsEmpty())
+		assert.Equal(t, []string{"store"}, drift.Added)
+		assert.Equal(t, []string{"legacy"}, drift.Removed)
+		assert.Equal(t, map[string]string{"petItem": "getPetsOKBodyItems"}, drift.Renamed)
+		assert.Equal(t, []string{"owner"}, drift.Changed)
+		assert.Equal(t, []string{"info"}, drift.Sections)
+	})
+
+	t.Run("should fail when flattening fails", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := VerifyFlatten(FlattenOpts{Spec: New(antest.LoadOrFail(t, bp)), AlgorithmVersion: "v0.1"}, flattened(t))
+		require.Error(t, err)
+	})
+}
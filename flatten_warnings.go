@@ -0,0 +1,107 @@
+package analysis
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// Codes of the warnings reported by FlattenWithWarnings
+const (
+	CodeRefSiblingsIgnored     = "ref-siblings-ignored"
+	CodeRefInterpretedAsSchema = "ref-interpreted-as-schema"
+	CodeDuplicateDefinition    = "duplicate-definition"
+)
+
+// Warning describes a non-fatal issue found while flattening a spec, such as keywords ignored next to a $ref,
+// or a definition renamed to resolve a name conflict
+type Warning struct {
+	Pointer string // the location of the construct in the flattened spec (e.g. "#/definitions/petOAIGen")
+	Code    string
+	Message string
+}
+
+// FlattenWithWarnings flattens a spec like Flatten, and reports the non-fatal issues found while flattening.
+//
+// Warnings are reported even if flattening fails.
+func FlattenWithWarnings(opts FlattenOpts) ([]Warning, error) {
+	opts.flattenContext = newContext()
+	err := flatten(&opts)
+
+	return opts.warnings(), err
+}
+
+// warn records the warnings reported when resolving the $ref at some location
+func (c *context) warn(pointer string, msgs []string) {
+	for _, msg := range msgs {
+		c.warnings = append(c.warnings, Warning{Pointer: pointer, Code: CodeRefInterpretedAsSchema, Message: msg})
+	}
+}
+
+// warnRefSiblings records the keywords found next to a $ref, which are ignored (and dropped when expanding)
+func (f *FlattenOpts) warnRefSiblings() {
+	walkSchemas(f.Swagger(), func(pointer string, schema *spec.Schema) {
+		if schema.Ref.String() == "" {
+			return
+		}
+
+		var siblings []string
+		for keyword := range schemaAsJSON(schema) {
+			if keyword != "$ref" && !strings.HasPrefix(strings.ToLower(keyword), "x-") {
+				siblings = append(siblings, keyword)
+			}
+		}
+
+		if len(siblings) == 0 {
+			return
+		}
+
+		sort.Strings(siblings)
+		f.flattenContext.warnings = append(f.flattenContext.warnings, Warning{
+			Pointer: pointer,
+			Code:    CodeRefSiblingsIgnored,
+			Message: fmt.Sprintf("keywords next to $ref %q are ignored: %s", schema.Ref.String(), strings.Join(siblings, ", ")),
+		})
+	})
+}
+
+// warnings yields the unique warnings found while flattening, including the duplicate definition names
+// resolved with an auto-generated name
+func (f *FlattenOpts) warnings() []Warning {
+	warnings := make([]Warning, 0, len(f.flattenContext.warnings))
+	unique := make(map[Warning]bool, len(f.flattenContext.warnings))
+	add := func(w Warning) {
+		if unique[w] {
+			return
+		}
+
+		unique[w] = true
+		warnings = append(warnings, w)
+	}
+
+	for _, w := range f.flattenContext.warnings {
+		add(w)
+	}
+
+	for _, k := range sortedKeys(f.flattenContext.newRefs) {
+		r := f.flattenContext.newRefs[k]
+		if !r.isOAIGen {
+			continue
+		}
+
+		if _, exists := f.Swagger().Definitions[r.newName]; !exists {
+			continue
+		}
+
+		add(Warning{
+			Pointer: path.Join(definitionsPath, r.newName),
+			Code:    CodeDuplicateDefinition,
+			Message: fmt.Sprintf("duplicate flattened definition name resolved as %s", r.newName),
+		})
+	}
+
+	return warnings
+}
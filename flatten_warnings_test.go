@@ -0,0 +1,87 @@
+package analysis
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenWithWarnings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with $ref siblings and $ref to a response", func(t *testing.T) {
+		t.Parallel()
+
+		var sp spec.Swagger
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"swagger": "2.0",
+			"info": {"title": "warnings", "version": "1.0"},
+			"paths": {
+				"/pets": {
+					"get": {
+						"responses": {
+							"200": {"description": "ok", "schema": {"$ref": "#/responses/pets"}}
+						}
+					}
+				}
+			},
+			"responses": {
+				"pets": {"description": "pets", "schema": {"type": "array", "items": {"type": "string"}}}
+			},
+			"definitions": {
+				"pet": {
+					"type": "object",
+					"properties": {
+						"owner": {"$ref": "#/definitions/owner", "description": "the owner", "x-nullable": true}
+					}
+				},
+				"owner": {"type": "string"}
+			}
+		}`), &sp))
+
+		warnings, err := FlattenWithWarnings(FlattenOpts{Spec: New(&sp), Minimal: true})
+		require.NoError(t, err)
+
+		codes := make(map[string]string, len(warnings))
+		for _, w := range warnings {
+			assert.NotEmpty(t, w.Message)
+			codes[w.Pointer] = w.Code
+		}
+
+		assert.Equal(t, CodeRefSiblingsIgnored, codes["#/definitions/pet/properties/owner"])
+		assert.Equal(t, CodeRefInterpretedAsSchema, codes["#/paths/~1pets/get/responses/200/schema"])
+	})
+
+	t.Run("with duplicate definition names", func(t *testing.T) {
+		t.Parallel()
+
+		bp := filepath.Join("fixtures", "oaigen", "fixture-oaigen.yaml")
+		sp := antest.LoadOrFail(t, bp)
+
+		warnings, err := FlattenWithWarnings(FlattenOpts{Spec: New(sp), BasePath: bp})
+		require.NoError(t, err)
+
+		assert.Contains(t, warnings, Warning{
+			Pointer: "#/definitions/aAOAIGen",
+			Code:    CodeDuplicateDefinition,
+			Message: "duplicate flattened definition name resolved as aAOAIGen",
+		})
+	})
+
+	t.Run("with error", func(t *testing.T) {
+		t.Parallel()
+
+		dangling := spec.RefSchema("missing.yml#/definitions/nowhere").WithDescription("dropped")
+		sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{"dangling": *dangling}}}
+
+		warnings, err := FlattenWithWarnings(FlattenOpts{Spec: New(sp), BasePath: filepath.Join("fixtures", "spec.yml")})
+		require.Error(t, err)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, CodeRefSiblingsIgnored, warnings[0].Code)
+	})
+}
@@ -0,0 +1,39 @@
+package analysis
+
+import (
+	"sync"
+
+	"github.com/go-openapi/strfmt"
+)
+
+// customFormats holds the formats declared with RegisterFormat
+var customFormats = struct {
+	sync.RWMutex
+	names map[string]bool
+}{names: make(map[string]bool)}
+
+// RegisterFormat declares custom formats (e.g. "decimal", "ulid"), so that schemas with these formats
+// are classified as known types by Schema, like schemas with a format known by strfmt.Default.
+//
+// Formats are registered for the whole process: callers which need a scoped registration
+// should use SchemaOpts.ExtraFormats instead.
+func RegisterFormat(names ...string) {
+	customFormats.Lock()
+	defer customFormats.Unlock()
+
+	for _, name := range names {
+		customFormats.names[name] = true
+	}
+}
+
+// IsKnownFormat tells if a format is known to strfmt.Default, or has been declared with RegisterFormat
+func IsKnownFormat(name string) bool {
+	if strfmt.Default.ContainsName(name) {
+		return true
+	}
+
+	customFormats.RLock()
+	defer customFormats.RUnlock()
+
+	return customFormats.names[name]
+}
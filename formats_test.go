@@ -0,0 +1,51 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_ExtraFormats(t *testing.T) {
+	t.Parallel()
+
+	// a custom format, serialized as an object
+	decimal := (&spec.Schema{}).Typed("object", "x-test-decimal").
+		SetProperty("mantissa", *spec.Int64Property()).
+		SetProperty("exponent", *spec.Int32Property())
+	decimals := spec.ArrayProperty(decimal)
+
+	sch, err := Schema(SchemaOpts{Schema: decimal})
+	require.NoError(t, err)
+	assert.False(t, sch.IsKnownType)
+
+	sch, err = Schema(SchemaOpts{Schema: decimal, ExtraFormats: []string{"x-test-decimal"}})
+	require.NoError(t, err)
+	assert.True(t, sch.IsKnownType)
+	assert.True(t, sch.IsSimpleSchema)
+
+	// extra formats apply to nested schemas
+	sch, err = Schema(SchemaOpts{Schema: decimals})
+	require.NoError(t, err)
+	assert.False(t, sch.IsSimpleArray)
+
+	sch, err = Schema(SchemaOpts{Schema: decimals, ExtraFormats: []string{"x-test-decimal"}})
+	require.NoError(t, err)
+	assert.True(t, sch.IsSimpleArray)
+}
+
+func TestRegisterFormat(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsKnownFormat("date-time"))
+	assert.False(t, IsKnownFormat("x-test-ulid"))
+
+	RegisterFormat("x-test-ulid")
+	assert.True(t, IsKnownFormat("x-test-ulid"))
+
+	sch, err := Schema(SchemaOpts{Schema: (&spec.Schema{}).Typed("file", "x-test-ulid")})
+	require.NoError(t, err)
+	assert.True(t, sch.IsKnownType)
+}
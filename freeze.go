@@ -0,0 +1,69 @@
+package analysis
+
+// Freeze yields a read-only view of an analyzed spec, which shares its document and analysis.
+//
+// The transforms of this package refuse to modify a frozen spec: ApplyPatch, ApplyMergePatch and Flatten
+// (unless FlattenOpts.DryRun is set) and SetProvenance fail with ErrFrozenSpec. All the queries are
+// available. Use Thaw to get a modifiable copy.
+//
+// The spec remains modifiable: its document is cloned when it is first modified, so the view is left unchanged.
+// Freezing does not protect the document from changes made directly to the spec.Swagger it was created from.
+func Freeze(s *Spec) *Spec {
+	view := s.view()
+	view.frozen = true
+
+	return view
+}
+
+// IsFrozen tells if the spec is a read-only view created by Freeze
+func (s *Spec) IsFrozen() bool {
+	return s.frozen
+}
+
+// Thaw yields a modifiable copy of the spec, frozen or not.
+//
+// Thawing is cheap: the copy shares the document and analysis of the spec until either of them is first modified,
+// when the document of the modified one is cloned.
+func (s *Spec) Thaw() *Spec {
+	view := s.view()
+	view.frozen = false
+	view.shared = true
+
+	return view
+}
+
+// view yields a shallow copy of the spec, with its own cache of plugin results.
+//
+// Both specs share their document: whichever is modified first clones it.
+func (s *Spec) view() *Spec {
+	s.shared = true
+
+	view := *s
+	view.plugins = s.plugins.copy()
+
+	return &view
+}
+
+// mutable prepares the spec before it is modified: it fails when the spec is frozen, and clones the document
+// shared with other specs (see view)
+func (s *Spec) mutable() error {
+	if s.frozen {
+		return ErrFrozenSpec
+	}
+
+	if !s.shared {
+		return nil
+	}
+
+	provenance := make(map[string]Provenance, len(s.provenance))
+	for pointer, origin := range s.provenance {
+		provenance[pointer] = origin
+	}
+
+	s.spec = CloneSpec(s.spec)
+	s.provenance = provenance
+	s.shared = false
+	s.reload()
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeze(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "reuse", "spec.yml")
+	sp := antest.LoadOrFail(t, bp)
+	before := CloneSpec(sp)
+	frozen := Freeze(New(sp))
+
+	require.True(t, frozen.IsFrozen())
+	assert.NotEmpty(t, frozen.Operations())
+	assert.ElementsMatch(t, New(sp).AllDefinitionReferences(), frozen.AllDefinitionReferences())
+
+	t.Run("transforms fail", func(t *testing.T) {
+		t.Parallel()
+
+		require.ErrorIs(t, Flatten(FlattenOpts{Spec: frozen, BasePath: bp}), ErrFrozenSpec)
+		require.ErrorIs(t, frozen.ApplyPatch([]PatchOperation{{Op: "remove", Path: "/definitions/pet"}}), ErrFrozenSpec)
+		require.ErrorIs(t, frozen.ApplyMergePatch(map[string]interface{}{"basePath": "/v2"}), ErrFrozenSpec)
+		require.ErrorIs(t, frozen.SetProvenance("#", Provenance{Document: "spec.yml"}), ErrFrozenSpec)
+	})
+
+	t.Run("dry runs succeed", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := FlattenWithReport(FlattenOpts{Spec: frozen, BasePath: bp, DryRun: true})
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.Definitions)
+	})
+
+	t.Run("thawed copies are modified", func(t *testing.T) {
+		t.Parallel()
+
+		thawed := frozen.Thaw()
+		require.False(t, thawed.IsFrozen())
+		require.NoError(t, Flatten(FlattenOpts{Spec: thawed, BasePath: bp}))
+
+		assert.Contains(t, thawed.spec.Definitions, "createPetParamsBody")
+		assert.NotContains(t, frozen.spec.Definitions, "createPetParamsBody")
+	})
+
+	t.Run("thawed copies are patched", func(t *testing.T) {
+		t.Parallel()
+
+		thawed := frozen.Thaw()
+		require.NoError(t, thawed.ApplyMergePatch(map[string]interface{}{"basePath": "/v2"}))
+		require.NoError(t, thawed.SetProvenance("#", Provenance{Document: "spec.yml"}))
+
+		assert.Equal(t, "/v2", thawed.spec.BasePath)
+		assert.Equal(t, "spec.yml", thawed.ProvenanceOf("#").Document)
+		assert.Empty(t, frozen.ProvenanceOf("#").Document)
+	})
+
+	t.Cleanup(func() {
+		assert.Equal(t, before, sp)
+		assert.Equal(t, before.BasePath, frozen.spec.BasePath)
+	})
+}
+
+func TestFreeze_OriginalModified(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "reuse", "spec.yml")))
+	paths := len(an.AllPaths())
+	frozen := Freeze(an)
+	thawed := an.Thaw()
+
+	addPath := []PatchOperation{{Op: "add", Path: "/paths/~1added", Value: map[string]interface{}{
+		"get": map[string]interface{}{"responses": map[string]interface{}{"200": map[string]interface{}{"description": "ok"}}},
+	}}}
+	require.NoError(t, an.ApplyPatch(addPath))
+
+	_, found := an.OperationFor("GET", "/added")
+	assert.True(t, found)
+	assert.Len(t, an.AllPaths(), paths+1)
+
+	for _, view := range []*Spec{frozen, thawed} {
+		_, found = view.OperationFor("GET", "/added")
+		assert.False(t, found)
+		assert.Len(t, view.AllPaths(), paths)
+		assert.NotContains(t, view.spec.Paths.Paths, "/added")
+	}
+
+	// the thawed copy is modified independently of the original
+	require.NoError(t, thawed.ApplyMergePatch(map[string]interface{}{"basePath": "/thawed"}))
+	assert.NotEqual(t, "/thawed", an.spec.BasePath)
+	assert.NotEqual(t, "/thawed", frozen.spec.BasePath)
+}
@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/url"
+	slashpath "path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// LoadFS loads a swagger specification document (JSON or YAML) from a file system,
+// such as an embed.FS or a fstest.MapFS.
+//
+// Use FlattenOpts.FS with the same file system to resolve the relative $ref's found in this document.
+func LoadFS(fsys fs.FS, name string) (*spec.Swagger, error) {
+	doc, err := loadFromFS(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var sp spec.Swagger
+	if err := json.Unmarshal(doc, &sp); err != nil {
+		return nil, err
+	}
+
+	return &sp, nil
+}
+
+// fsPathLoader builds a spec.ExpandOptions.PathLoader resolving local files from a file system.
+//
+// Remote documents are still fetched with the default spec.PathLoader.
+func fsPathLoader(fsys fs.FS) func(string) (json.RawMessage, error) {
+	return func(pth string) (json.RawMessage, error) {
+		u, err := url.Parse(pth)
+		if err != nil {
+			return nil, err
+		}
+
+		if u.Scheme != "" && u.Scheme != "file" {
+			return spec.PathLoader(pth)
+		}
+
+		return loadFromFS(fsys, fsName(u.Path))
+	}
+}
+
+// fsName maps the absolute path of a local file, as resolved by the spec package, to a name in a file system.
+//
+// Relative base paths are anchored to the current working directory by the spec package:
+// the working directory is mapped to the root of the file system. Other absolute paths are rooted
+// at the root of the file system.
+func fsName(pth string) string {
+	if cwd, err := filepath.Abs(""); err == nil {
+		if rel, err := filepath.Rel(cwd, filepath.FromSlash(pth)); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	return strings.TrimPrefix(slashpath.Clean("/"+pth), "/")
+}
+
+// loadFromFS reads a JSON or YAML document from a file system, and yields it as JSON
+func loadFromFS(fsys fs.FS, name string) (json.RawMessage, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(slashpath.Ext(name)) {
+	case ".yaml", ".yml":
+		doc, err := swag.BytesToYAMLDoc(data)
+		if err != nil {
+			return nil, err
+		}
+
+		return swag.YAMLToJSON(doc)
+	default:
+		return json.RawMessage(data), nil
+	}
+}
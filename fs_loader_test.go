@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFS_Flatten(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"embedded/specs/root.yaml": {Data: []byte(`
+swagger: "2.0"
+info:
+  version: "0.1.0"
+  title: embedded spec
+paths:
+  /pets:
+    get:
+      responses:
+        200:
+          description: ok
+          schema:
+            $ref: 'models/pets.json#/definitions/pets'
+`)},
+		"embedded/specs/models/pets.json": {Data: []byte(`{
+  "definitions": {
+    "pets": {"type": "array", "items": {"$ref": "pet.yml"}}
+  }
+}`)},
+		"embedded/specs/models/pet.yml": {Data: []byte(`
+type: object
+properties:
+  name:
+    type: string
+`)},
+	}
+
+	for _, basePath := range []string{"embedded/specs/root.yaml", "/embedded/specs/root.yaml"} {
+		bp := basePath
+		t.Run(bp, func(t *testing.T) {
+			t.Parallel()
+
+			sp, err := LoadFS(fsys, "embedded/specs/root.yaml")
+			require.NoError(t, err)
+
+			require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp, FS: fsys, Minimal: true}))
+
+			require.Contains(t, sp.Definitions, "pets")
+			require.Contains(t, sp.Definitions, "pet")
+			assert.Equal(t, "#/definitions/pets", sp.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200].Schema.Ref.String())
+			assert.Equal(t, "#/definitions/pet", sp.Definitions["pets"].Items.Schema.Ref.String())
+			assert.Contains(t, sp.Definitions["pet"].Properties, "name")
+		})
+	}
+
+	t.Run("with a missing document", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := LoadFS(fsys, "embedded/specs/none.yaml")
+		require.Error(t, err)
+	})
+}
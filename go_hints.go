@@ -0,0 +1,228 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+)
+
+// names of the extensions giving hints to go code generators
+const (
+	extGoName        = "x-go-name"
+	extGoType        = "x-go-type"
+	extGoCustomTag   = "x-go-custom-tag"
+	extGoJSONString  = "x-go-json-string"
+	extGoGenLocation = "x-go-gen-location"
+	extNullable      = "x-nullable"
+	extIsNullable    = "x-isnullable"
+	extOmitEmpty     = "x-omitempty"
+	extOrder         = "x-order"
+)
+
+// GoHints are the hints given to go code generators by the x-go-* family of vendor extensions
+// (and a few related ones), as used across the go-openapi ecosystem
+type GoHints struct {
+	// Name is the name of the go type, field or parameter (x-go-name)
+	Name string
+
+	// Type is an existing go type to use instead of generating one (x-go-type)
+	Type *GoTypeExtension
+
+	// CustomTag holds extra struct tags for a field (x-go-custom-tag), e.g. `db:"name"`
+	CustomTag string
+
+	// Nullable tells if the value is a pointer (x-nullable, or x-isnullable). It is nil when unspecified.
+	Nullable *bool
+
+	// OmitEmpty tells if the field is omitted from JSON when empty (x-omitempty). It is nil when unspecified.
+	OmitEmpty *bool
+
+	// JSONString encodes a numeric field as a JSON string (x-go-json-string)
+	JSONString bool
+
+	// Order is the position of a field in the generated struct (x-order). It is nil when unspecified.
+	Order *int
+
+	// GenLocation is the location of an inline schema moved to definitions when flattening (x-go-gen-location)
+	GenLocation string
+}
+
+// GoTypeExtension is the value of the x-go-type extension
+type GoTypeExtension struct {
+	Type     string
+	Embedded bool
+
+	Import struct {
+		Package string
+		Alias   string
+	}
+
+	Hints struct {
+		Kind         string
+		Nullable     *bool
+		NoValidation bool
+	}
+}
+
+// GoHintsFor returns the go code generation hints given by the vendor extensions of an element of the spec,
+// such as a schema, a parameter or an operation (e.g. "#/definitions/pet/properties/name").
+//
+// The element is not resolved when it is a $ref. Extensions are matched case-insensitively.
+// This fails with a *PointerError when the pointer does not locate any element, or with an *ExtensionError
+// when an extension has an unexpected type.
+func (s *Spec) GoHintsFor(pointer string) (GoHints, error) {
+	var hints GoHints
+
+	ptr, err := jsonpointer.New(strings.TrimPrefix(pointer, "#"))
+	if err != nil {
+		return hints, &PointerError{Pointer: pointer, Cause: err}
+	}
+
+	element, _, err := ptr.Get(s.spec)
+	if err != nil {
+		return hints, &PointerError{Pointer: pointer, Cause: err}
+	}
+
+	doc, err := genericJSON(element)
+	if err != nil {
+		return hints, &PointerError{Pointer: pointer, Cause: err}
+	}
+
+	object, isObject := doc.(map[string]interface{})
+	if !isObject {
+		return hints, &PointerError{Pointer: pointer, Cause: fmt.Errorf("not an object: %T", doc)}
+	}
+
+	x := &extensionReader{pointer: pointer, values: object, err: new(error)}
+	hints.Name = x.string(extGoName)
+	hints.CustomTag = x.string(extGoCustomTag)
+	hints.GenLocation = x.string(extGoGenLocation)
+	hints.JSONString = x.bool(extGoJSONString)
+	hints.Nullable = x.optionalBool(extNullable)
+	if hints.Nullable == nil {
+		hints.Nullable = x.optionalBool(extIsNullable)
+	}
+	hints.OmitEmpty = x.optionalBool(extOmitEmpty)
+	hints.Order = x.optionalInt(extOrder)
+
+	if goType := x.object(extGoType); goType != nil {
+		hints.Type = &GoTypeExtension{Type: goType.string("type"), Embedded: goType.bool("embedded")}
+		if imported := goType.object("import"); imported != nil {
+			hints.Type.Import.Package = imported.string("package")
+			hints.Type.Import.Alias = imported.string("alias")
+		}
+		if hinted := goType.object("hints"); hinted != nil {
+			hints.Type.Hints.Kind = hinted.string("kind")
+			hints.Type.Hints.Nullable = hinted.optionalBool("nullable")
+			hints.Type.Hints.NoValidation = hinted.bool("noValidation")
+		}
+	}
+
+	return hints, x.failure()
+}
+
+// extensionReader reads the values of extensions (or of their nested keys), retaining the first error met
+type extensionReader struct {
+	pointer string
+	prefix  string // the path to the nested object being read, e.g. "x-go-type/import/"
+	values  map[string]interface{}
+	err     *error // shared with nested readers
+}
+
+func (x *extensionReader) failure() error {
+	return *x.err
+}
+
+func (x *extensionReader) lookup(name, expected string, accept func(interface{}) bool) (interface{}, bool) {
+	for key, value := range x.values {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+
+		if !accept(value) {
+			if *x.err == nil {
+				*x.err = &ExtensionError{
+					Pointer:   x.pointer,
+					Extension: x.prefix + name,
+					Cause:     fmt.Errorf("expected %s, got %T", expected, value),
+				}
+			}
+
+			return nil, false
+		}
+
+		return value, true
+	}
+
+	return nil, false
+}
+
+func (x *extensionReader) string(name string) string {
+	value, _ := x.lookup(name, "a string", isJSONString)
+	str, _ := value.(string)
+
+	return str
+}
+
+func (x *extensionReader) bool(name string) bool {
+	b := x.optionalBool(name)
+
+	return b != nil && *b
+}
+
+func (x *extensionReader) optionalBool(name string) *bool {
+	value, found := x.lookup(name, "a boolean", isJSONBool)
+	if !found {
+		return nil
+	}
+
+	b := value.(bool)
+
+	return &b
+}
+
+func (x *extensionReader) optionalInt(name string) *int {
+	value, found := x.lookup(name, "an integer", isJSONInteger)
+	if !found {
+		return nil
+	}
+
+	n := int(value.(float64))
+
+	return &n
+}
+
+// object yields a reader of the keys of a nested object, or nil when absent
+func (x *extensionReader) object(name string) *extensionReader {
+	value, found := x.lookup(name, "an object", isJSONObject)
+	if !found {
+		return nil
+	}
+
+	return &extensionReader{pointer: x.pointer, prefix: x.prefix + name + "/", values: value.(map[string]interface{}), err: x.err}
+}
+
+func isJSONString(value interface{}) bool {
+	_, ok := value.(string)
+
+	return ok
+}
+
+func isJSONBool(value interface{}) bool {
+	_, ok := value.(bool)
+
+	return ok
+}
+
+func isJSONInteger(value interface{}) bool {
+	number, ok := value.(float64)
+
+	return ok && number == float64(int(number))
+}
+
+func isJSONObject(value interface{}) bool {
+	_, ok := value.(map[string]interface{})
+
+	return ok
+}
@@ -0,0 +1,81 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_GoHintsFor(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "go_hints.yml")))
+
+	t.Run("should read the hints of schemas", func(t *testing.T) {
+		hints, err := an.GoHintsFor("#/definitions/pet")
+		require.NoError(t, err)
+		assert.Equal(t, GoHints{Name: "Animal"}, hints)
+
+		hints, err = an.GoHintsFor("#/definitions/pet/properties/name")
+		require.NoError(t, err)
+		assert.Equal(t, GoHints{CustomTag: `db:"name"`, OmitEmpty: swag.Bool(false), Order: swag.Int(1)}, hints)
+
+		hints, err = an.GoHintsFor("#/definitions/pet/properties/id")
+		require.NoError(t, err)
+		assert.Equal(t, GoHints{JSONString: true, Nullable: swag.Bool(false)}, hints)
+
+		hints, err = an.GoHintsFor("#/definitions/pet/properties/birth")
+		require.NoError(t, err)
+		require.NotNil(t, hints.Type)
+		assert.Equal(t, "Date", hints.Type.Type)
+		assert.False(t, hints.Type.Embedded)
+		assert.Equal(t, "github.com/example/dates", hints.Type.Import.Package)
+		assert.Equal(t, "dates", hints.Type.Import.Alias)
+		assert.Equal(t, "primitive", hints.Type.Hints.Kind)
+		assert.Equal(t, swag.Bool(true), hints.Type.Hints.Nullable)
+		assert.True(t, hints.Type.Hints.NoValidation)
+	})
+
+	t.Run("should read the hints of operations and parameters", func(t *testing.T) {
+		hints, err := an.GoHintsFor("#/paths/~1pets/get")
+		require.NoError(t, err)
+		assert.Equal(t, "ListPets", hints.Name)
+
+		hints, err = an.GoHintsFor("#/paths/~1pets/get/parameters/0")
+		require.NoError(t, err)
+		assert.Equal(t, GoHints{Name: "MaxItems", Nullable: swag.Bool(true)}, hints)
+
+		hints, err = an.GoHintsFor("#/paths/~1pets/get/responses/200/schema/items")
+		require.NoError(t, err)
+		assert.Equal(t, GoHints{}, hints)
+	})
+
+	t.Run("should fail on invalid extensions", func(t *testing.T) {
+		_, err := an.GoHintsFor("#/definitions/invalid")
+		require.Error(t, err)
+
+		var extErr *ExtensionError
+		require.ErrorAs(t, err, &extErr)
+		assert.Equal(t, "#/definitions/invalid", extErr.Pointer)
+		assert.Equal(t, "x-go-type/import", extErr.Extension)
+
+		_, err = an.GoHintsFor("#/definitions/badOrder")
+		require.ErrorAs(t, err, &extErr)
+		assert.Equal(t, "x-order", extErr.Extension)
+	})
+
+	t.Run("should fail on invalid pointers", func(t *testing.T) {
+		_, err := an.GoHintsFor("#/definitions/missing")
+		require.Error(t, err)
+
+		var ptrErr *PointerError
+		require.ErrorAs(t, err, &ptrErr)
+
+		_, err = an.GoHintsFor("#/info/title")
+		require.ErrorAs(t, err, &ptrErr)
+	})
+}
@@ -0,0 +1,512 @@
+package analysis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// custom scalars of the GraphQL schema sketched by ExportGraphQL
+const (
+	graphQLJSON     = "JSON"
+	graphQLDateTime = "DateTime"
+	graphQLLong     = "Long"
+	graphQLUpload   = "Upload"
+)
+
+// go types which fit in a GraphQL Int, a signed 32-bit integer
+var graphQLIntTypes = map[string]bool{
+	"int8": true, "int16": true, "int32": true, "uint8": true, "uint16": true,
+}
+
+// ExportGraphQL sketches a GraphQL schema, in the schema definition language (SDL), from the definitions
+// and the operations of a spec:
+//   - object definitions become types (inputs, for those used in request bodies), and string enums become enums;
+//   - definitions with a discriminator become interfaces, implemented by the definitions which extend them with allOf;
+//   - GET operations become queries, and POST, PUT, PATCH and DELETE operations become mutations,
+//     with their parameters as arguments (header parameters excepted) and their success response as result.
+//
+// Maps, tuples and untyped schemas become a JSON custom scalar. Other custom scalars represent date-times (DateTime),
+// integers which do not fit in 32 bits (Long) and files (Upload).
+//
+// Findings report what cannot be mapped faithfully, sorted by pointer: e.g. maps, tuples, polymorphic inputs,
+// names which are not valid GraphQL names and are renamed, or parameters which cannot be resolved (such as
+// remote parameter $ref's), which are omitted.
+func ExportGraphQL(sp *spec.Swagger) (string, []Finding, error) {
+	e := &graphQLExporter{
+		sp:       sp,
+		scalars:  make(map[string]bool),
+		blocks:   make(map[string]string),
+		pending:  make(map[string]bool),
+		inlining: make(map[string]bool),
+	}
+
+	queries, mutations, err := e.operations()
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, name := range sortedKeys(sp.Definitions) {
+		def := sp.Definitions[name]
+		if e.isNamed(&def) {
+			if _, err := e.namedType(name, false); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+
+	var sdl strings.Builder
+	for _, scalar := range sortedKeys(e.scalars) {
+		fmt.Fprintf(&sdl, "scalar %s\n\n", scalar)
+	}
+
+	for _, name := range sortedKeys(e.blocks) {
+		sdl.WriteString(e.blocks[name])
+		sdl.WriteString("\n")
+	}
+
+	for _, root := range []struct {
+		name   string
+		fields []string
+	}{{"Query", queries}, {"Mutation", mutations}} {
+		if len(root.fields) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&sdl, "type %s {\n%s}\n\n", root.name, strings.Join(root.fields, ""))
+	}
+
+	sortFindings(e.findings)
+
+	return strings.TrimSuffix(sdl.String(), "\n"), e.findings, nil
+}
+
+type graphQLExporter struct {
+	sp       *spec.Swagger
+	findings []Finding
+
+	scalars map[string]bool
+	// SDL of the named types, by name
+	blocks map[string]string
+	// named types being generated, to break cycles
+	pending map[string]bool
+	// definitions being inlined, to break cycles of aliases
+	inlining map[string]bool
+}
+
+func (e *graphQLExporter) report(pointer, code, format string, args ...interface{}) {
+	e.findings = appendFindingOnce(e.findings, Finding{Pointer: pointer, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *graphQLExporter) scalar(name string) string {
+	e.scalars[name] = true
+
+	return name
+}
+
+// fieldName yields the GraphQL name of a field or an argument, reporting renamed fields
+func (e *graphQLExporter) fieldName(pointer, name string) string {
+	if rexIdentifier.MatchString(name) {
+		return name
+	}
+
+	renamed := swag.ToVarName(name)
+	if !rexIdentifier.MatchString(renamed) {
+		renamed = "_" + renamed
+	}
+	e.report(pointer, CodeUnmappableName, "%q is not a valid GraphQL name: renamed %s", name, renamed)
+
+	return renamed
+}
+
+// graphQLDescription renders a description as a block string
+func graphQLDescription(indent, description string) string {
+	if description == "" {
+		return ""
+	}
+
+	return indent + `"""` + strings.ReplaceAll(description, `"""`, `\"""`) + `"""` + "\n"
+}
+
+// isNamed tells if a definition maps to a named GraphQL type: an object or an enum.
+// Other definitions are inlined where they are referred to.
+func (e *graphQLExporter) isNamed(schema *spec.Schema) bool {
+	if schema.Ref.String() != "" {
+		return false
+	}
+
+	a, err := Schema(SchemaOpts{Schema: schema, Root: e.sp})
+	if err != nil {
+		return false
+	}
+
+	return isStringEnum(a) || (a.GoTypeHint.Kind == GoTypeStruct && !a.IsMap)
+}
+
+func isStringEnum(a *AnalyzedSchema) bool {
+	return a.IsEnum && a.GoTypeHint.Kind == GoTypeBuiltin && a.GoTypeHint.Type == "string"
+}
+
+// namedType yields the name of the GraphQL type for a definition, generating this type when needed
+func (e *graphQLExporter) namedType(name string, input bool) (string, error) {
+	def := e.sp.Definitions[name]
+
+	return e.typeOf(definitionsPrefix+jsonpointer.Escape(name), &def, exportedTypeName(name), input)
+}
+
+// typeRef yields the GraphQL type of a schema. Inline objects and enums are named after the given name.
+func (e *graphQLExporter) typeRef(pointer string, schema *spec.Schema, name string, input bool) (string, error) {
+	ref := schema.Ref.String()
+	if ref == "" {
+		return e.typeOf(pointer, schema, name, input)
+	}
+
+	def, isDefinition := definitionOfPointer(ref)
+	target, found := e.sp.Definitions[def]
+	if !isDefinition || !found || ref != definitionsPrefix+jsonpointer.Escape(def) {
+		e.report(pointer, CodeUnmappableRef, "only $ref's to local definitions are mapped: %s is mapped to %s", ref, graphQLJSON)
+
+		return e.scalar(graphQLJSON), nil
+	}
+
+	if e.isNamed(&target) {
+		return e.namedType(def, input)
+	}
+
+	if e.inlining[def] {
+		return e.scalar(graphQLJSON), nil
+	}
+
+	e.inlining[def] = true
+	defer delete(e.inlining, def)
+
+	return e.typeRef(definitionsPrefix+jsonpointer.Escape(def), &target, exportedTypeName(def), input)
+}
+
+func (e *graphQLExporter) typeOf(pointer string, schema *spec.Schema, name string, input bool) (string, error) {
+	a, err := Schema(SchemaOpts{Schema: schema, Root: e.sp})
+	if err != nil {
+		return "", err
+	}
+
+	hint := a.GoTypeHint
+	switch {
+	case isStringEnum(a):
+		return e.enum(pointer, name, schema), nil
+	case a.IsTuple || a.IsTupleWithExtra:
+		e.report(pointer, CodeUnmappableTuple, "GraphQL has no tuples: mapped to %s", graphQLJSON)
+
+		return e.scalar(graphQLJSON), nil
+	case a.IsMap || hint.Kind == GoTypeMap:
+		e.report(pointer, CodeUnmappableMap, "GraphQL has no maps: mapped to %s", graphQLJSON)
+
+		return e.scalar(graphQLJSON), nil
+	case hint.Kind == GoTypeSlice && schema.Items != nil && schema.Items.Schema != nil:
+		elem, err := e.typeRef(pointer+"/items", schema.Items.Schema, name+"Item", input)
+		if err != nil {
+			return "", err
+		}
+
+		return "[" + elem + "]", nil
+	case hint.Kind == GoTypeStruct:
+		return e.object(pointer, schema, a, name, input)
+	case hint.Kind == GoTypeTime:
+		return e.scalar(graphQLDateTime), nil
+	case hint.Kind == GoTypeBuiltin:
+		return e.builtin(hint), nil
+	default:
+		return e.scalar(graphQLJSON), nil
+	}
+}
+
+func (e *graphQLExporter) builtin(hint GoTypeHint) string {
+	switch hint.Type {
+	case "string":
+		return "String"
+	case "bool":
+		return "Boolean"
+	case "float32", "float64":
+		return "Float"
+	case "[]byte", "io.ReadCloser":
+		if hint.Format == "byte" {
+			return "String" // base64 encoded
+		}
+
+		return e.scalar(graphQLUpload)
+	}
+
+	if graphQLIntTypes[hint.Type] {
+		return "Int"
+	}
+
+	if strings.Contains(hint.Type, "int") {
+		return e.scalar(graphQLLong)
+	}
+
+	return e.scalar(graphQLJSON)
+}
+
+// enum generates an enum type, and yields its name
+func (e *graphQLExporter) enum(pointer, name string, schema *spec.Schema) string {
+	if _, exists := e.blocks[name]; exists {
+		return name
+	}
+
+	var sdl strings.Builder
+	sdl.WriteString(graphQLDescription("", schema.Description))
+	fmt.Fprintf(&sdl, "enum %s {\n", name)
+
+	seen := make(map[string]bool, len(schema.Enum))
+	for _, value := range schema.Enum {
+		str, isString := value.(string)
+		if !isString {
+			continue
+		}
+
+		symbol := str
+		if !rexIdentifier.MatchString(symbol) {
+			symbol = exportedTypeName(str)
+			e.report(pointer+"/enum", CodeUnmappableName, "%q is not a valid GraphQL enum value: renamed %s", str, symbol)
+		}
+
+		if !seen[symbol] {
+			seen[symbol] = true
+			fmt.Fprintf(&sdl, "  %s\n", symbol)
+		}
+	}
+	sdl.WriteString("}\n")
+
+	e.blocks[name] = sdl.String()
+
+	return name
+}
+
+// object generates an object type (or an interface, or an input), and yields its name.
+// Objects without any property are mapped to the JSON scalar.
+func (e *graphQLExporter) object(pointer string, schema *spec.Schema, a *AnalyzedSchema, name string, input bool) (string, error) {
+	owner := name
+	if input {
+		name += "Input"
+	}
+
+	if _, exists := e.blocks[name]; exists || e.pending[name] {
+		return name, nil
+	}
+
+	var fields []objectField
+	var bases, interfaces []string
+	collectObjectFields(e.sp, pointer, owner, schema, &fields, &bases, make(map[string]bool))
+	for _, base := range bases {
+		interfaces = append(interfaces, exportedTypeName(base))
+	}
+
+	if len(fields) == 0 {
+		return e.scalar(graphQLJSON), nil
+	}
+
+	e.pending[name] = true
+	defer delete(e.pending, name)
+
+	keyword := "type"
+	switch {
+	case input:
+		keyword = "input"
+		if a.IsBaseType || len(interfaces) > 0 {
+			e.report(pointer, CodeUnmappablePolymorphism, "GraphQL inputs have no polymorphism: %s only holds the fields of its own type", name)
+		}
+		interfaces = nil
+	case a.IsBaseType:
+		keyword = "interface"
+	}
+
+	var sdl strings.Builder
+	sdl.WriteString(graphQLDescription("", schema.Description))
+	fmt.Fprintf(&sdl, "%s %s", keyword, name)
+	if len(interfaces) > 0 {
+		fmt.Fprintf(&sdl, " implements %s", strings.Join(interfaces, " & "))
+	}
+	sdl.WriteString(" {\n")
+
+	for _, field := range fields {
+		fieldName := e.fieldName(field.pointer, field.name)
+		tpe, err := e.typeRef(field.pointer, field.schema, field.owner+swag.ToGoName(field.name), input)
+		if err != nil {
+			return "", err
+		}
+
+		if field.required {
+			tpe += "!"
+		}
+
+		sdl.WriteString(graphQLDescription("  ", field.schema.Description))
+		fmt.Fprintf(&sdl, "  %s: %s\n", fieldName, tpe)
+	}
+	sdl.WriteString("}\n")
+
+	e.blocks[name] = sdl.String()
+
+	return name, nil
+}
+
+// operations yields the fields of the Query and the Mutation types
+func (e *graphQLExporter) operations() ([]string, []string, error) {
+	if e.sp.Paths == nil {
+		return nil, nil, nil
+	}
+
+	an := newSpec(e.sp)
+	var queries, mutations []string
+	names := make(map[string]bool)
+
+	for _, pth := range sortedKeys(e.sp.Paths.Paths) {
+		pathItem := e.sp.Paths.Paths[pth]
+		for _, method := range sortedOperationMethods(&pathItem) {
+			var root *[]string
+			switch method {
+			case "GET":
+				root = &queries
+			case "POST", "PUT", "PATCH", "DELETE":
+				root = &mutations
+			default:
+				continue
+			}
+
+			op := operationOf(&pathItem, method)
+			pointer := "#/paths/" + jsonpointer.Escape(pth) + "/" + strings.ToLower(method)
+
+			name := op.ID
+			if name == "" {
+				name = strings.ToLower(method) + " " + pth
+			}
+			name = swag.ToVarName(name)
+			if !rexIdentifier.MatchString(name) {
+				name = "_" + name
+			}
+			for unique, i := name, 2; ; i++ {
+				if !names[unique] {
+					name = unique
+
+					break
+				}
+				unique = name + strconv.Itoa(i)
+			}
+			names[name] = true
+
+			field, err := e.operation(pointer, an, method, pth, op, name)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			*root = append(*root, field)
+		}
+	}
+
+	return queries, mutations, nil
+}
+
+func (e *graphQLExporter) operation(pointer string, an *Spec, method, pth string, op *spec.Operation, name string) (string, error) {
+	typeName := swag.ToGoName(name)
+	params := an.SafeParamsFor(method, pth, func(param spec.Parameter, _ error) bool {
+		e.report(pointer+"/parameters", CodeUnmappableRef, "parameter %s cannot be resolved: it is not mapped to an argument", param.Ref.String())
+
+		return true
+	})
+
+	var args []string
+	for _, key := range sortedKeys(params) {
+		param := params[key]
+		argPointer := pointer + "/parameters/" + jsonpointer.Escape(param.Name)
+
+		var (
+			tpe string
+			err error
+		)
+		switch param.In {
+		case "header":
+			continue
+		case "body":
+			if param.Schema == nil {
+				continue
+			}
+			tpe, err = e.typeRef(argPointer, param.Schema, typeName+"Body", true)
+		default:
+			tpe, err = e.typeRef(argPointer, simpleSchemaOf(param.SimpleSchema, param.CommonValidations), typeName+swag.ToGoName(param.Name), true)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if param.Required {
+			tpe += "!"
+		}
+
+		args = append(args, e.fieldName(argPointer, param.Name)+": "+tpe)
+	}
+
+	result := "Boolean"
+	if schemaPointer, schema := e.resultSchema(pointer, op); schema != nil {
+		var err error
+		if result, err = e.typeRef(schemaPointer, schema, typeName+"Result", false); err != nil {
+			return "", err
+		}
+	}
+
+	description := op.Summary
+	if description == "" {
+		description = op.Description
+	}
+
+	var field strings.Builder
+	field.WriteString(graphQLDescription("  ", description))
+	field.WriteString("  " + name)
+	if len(args) > 0 {
+		field.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+	field.WriteString(": " + result + "\n")
+
+	return field.String(), nil
+}
+
+// resultSchema yields the schema of the first success response of an operation, or else of its default response
+func (e *graphQLExporter) resultSchema(pointer string, op *spec.Operation) (string, *spec.Schema) {
+	if op.Responses == nil {
+		return "", nil
+	}
+
+	resolve := func(resp *spec.Response) *spec.Response {
+		ref := resp.Ref.String()
+		if !strings.HasPrefix(ref, "#/responses/") {
+			return resp
+		}
+
+		shared, found := e.sp.Responses[jsonpointer.Unescape(strings.TrimPrefix(ref, "#/responses/"))]
+		if !found {
+			return resp
+		}
+
+		return &shared
+	}
+
+	for _, code := range sortedStatusCodes(op.Responses.StatusCodeResponses) {
+		if code < 200 || code >= 300 {
+			continue
+		}
+
+		resp := op.Responses.StatusCodeResponses[code]
+		if resolved := resolve(&resp); resolved.Schema != nil {
+			return pointer + "/responses/" + strconv.Itoa(code) + "/schema", resolved.Schema
+		}
+	}
+
+	if op.Responses.Default != nil {
+		if resolved := resolve(op.Responses.Default); resolved.Schema != nil {
+			return pointer + "/responses/default/schema", resolved.Schema
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,167 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportGraphQL(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "graphql.yml"))
+
+	sdl, findings, err := ExportGraphQL(sp)
+	require.NoError(t, err)
+
+	assert.Equal(t, `scalar DateTime
+
+scalar JSON
+
+scalar Long
+
+type Dog implements Pet {
+  birth: DateTime
+  kind: String!
+  labels: JSON
+  name: String!
+  owner: PetOwner
+  position: JSON
+  status: Status
+  tags: [String]
+  barkVolume: Float
+}
+
+"""a pet"""
+interface Pet {
+  birth: DateTime
+  kind: String!
+  labels: JSON
+  name: String!
+  owner: PetOwner
+  position: JSON
+  status: Status
+  tags: [String]
+}
+
+"""a pet"""
+input PetInput {
+  birth: DateTime
+  kind: String!
+  labels: JSON
+  name: String!
+  owner: PetOwnerInput
+  position: JSON
+  status: Status
+  tags: [String]
+}
+
+type PetOwner {
+  id: Long
+  pets: [Pet]
+}
+
+input PetOwnerInput {
+  id: Long
+  pets: [PetInput]
+}
+
+enum Status {
+  available
+  SoldOut
+}
+
+type Query {
+  """lists pets"""
+  listPets(limit: Int): [Pet]
+}
+
+type Mutation {
+  createPet(pet: PetInput!): Pet
+  deletePetsPetID(petID: String!): Boolean
+}
+`, sdl)
+
+	codes := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		codes = append(codes, finding.Pointer+" "+finding.Code)
+	}
+
+	assert.Equal(t, []string{
+		"#/definitions/dog/allOf/1/properties/bark-volume " + CodeUnmappableName,
+		"#/definitions/pet " + CodeUnmappablePolymorphism,
+		"#/definitions/pet/properties/labels " + CodeUnmappableMap,
+		"#/definitions/pet/properties/position " + CodeUnmappableTuple,
+		"#/definitions/status/enum " + CodeUnmappableName,
+		"#/paths/~1pets~1{pet-id}/delete/parameters/pet-id " + CodeUnmappableName,
+	}, codes)
+}
+
+func TestExportGraphQL_Edge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should map an empty spec to an empty schema", func(t *testing.T) {
+		t.Parallel()
+
+		sdl, findings, err := ExportGraphQL(&spec.Swagger{})
+		require.NoError(t, err)
+		assert.Empty(t, sdl)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("should map remote $ref's to the JSON scalar", func(t *testing.T) {
+		t.Parallel()
+
+		sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Definitions: spec.Definitions{
+				"pet": {SchemaProps: spec.SchemaProps{
+					Type:       spec.StringOrArray{"object"},
+					Properties: spec.SchemaProperties{"owner": *spec.RefSchema("owner.json#/definitions/owner")},
+				}},
+			},
+		}}
+
+		sdl, findings, err := ExportGraphQL(sp)
+		require.NoError(t, err)
+		assert.Contains(t, sdl, "type Pet {\n  owner: JSON\n}\n")
+		require.Len(t, findings, 1)
+		assert.Equal(t, CodeUnmappableRef, findings[0].Code)
+	})
+
+	t.Run("should omit parameters which cannot be resolved", func(t *testing.T) {
+		t.Parallel()
+
+		sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+				"/pets": {PathItemProps: spec.PathItemProps{Get: &spec.Operation{OperationProps: spec.OperationProps{
+					ID: "listPets",
+					Parameters: []spec.Parameter{
+						*spec.QueryParam("limit").Typed("integer", "int32"),
+						*spec.ParamRef("./params.json#/parameters/offset"),
+					},
+					Responses: &spec.Responses{ResponsesProps: spec.ResponsesProps{StatusCodeResponses: map[int]spec.Response{
+						200: *spec.NewResponse().WithDescription("ok").WithSchema(spec.StringProperty()),
+					}}},
+				}}}},
+			}},
+		}}
+
+		var (
+			sdl      string
+			findings []Finding
+			err      error
+		)
+		require.NotPanics(t, func() {
+			sdl, findings, err = ExportGraphQL(sp)
+		})
+		require.NoError(t, err)
+		assert.Contains(t, sdl, "listPets(limit: Int): String")
+		require.Len(t, findings, 1)
+		assert.Equal(t, CodeUnmappableRef, findings[0].Code)
+		assert.Equal(t, "#/paths/~1pets/get/parameters", findings[0].Pointer)
+	})
+}
@@ -0,0 +1,194 @@
+package analysis
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// CodeIgnoredHeaderParameter is the code of the findings reported by HeaderConflicts
+const CodeIgnoredHeaderParameter = "ignored-header-parameter"
+
+// wellKnownHeaders are the header parameters ignored by swagger 2.0, with the construct to use instead
+var wellKnownHeaders = map[string]string{
+	"Accept":        "produces",
+	"Authorization": "securityDefinitions and security",
+	"Content-Type":  "consumes",
+}
+
+// HeaderUse is the use of a header by an operation
+type HeaderUse struct {
+	// Operation is the operation, as "METHOD /path" (e.g. "GET /pets")
+	Operation string
+
+	// Pointer is the location of the operation for a request header, or of the response for a response header
+	// (e.g. "#/paths/~1pets/get/responses/200")
+	Pointer string
+
+	// Response is the status code of the response declaring a response header (e.g. "200" or "default").
+	// It is empty for a request header.
+	Response string
+}
+
+// Header is a header used by the operations of a spec, as a request header parameter or as a response header
+type Header struct {
+	// Name is the canonical name of the header (e.g. "X-Rate-Limit")
+	Name string
+
+	// Response is true for a response header, false for a request header parameter
+	Response bool
+
+	// Type and Format of the header, with $ref's to shared parameters and responses resolved.
+	// ItemsType is the type of the items of an array.
+	Type      string
+	Format    string
+	ItemsType string
+
+	// Uses of the header, in the order of paths and methods
+	Uses []HeaderUse
+}
+
+// AllHeaders indexes all the headers of the spec: request header parameters and response headers,
+// with the operations using them.
+//
+// Header names are matched case-insensitively. A header used with different types is reported once per type.
+// Headers are sorted by name, with request headers first, then by type.
+func (s *Spec) AllHeaders() []Header {
+	index := make(map[string]int)
+	var result []Header
+
+	add := func(name string, response bool, simple spec.SimpleSchema, use HeaderUse) {
+		header := Header{Name: http.CanonicalHeaderKey(name), Response: response, Type: simple.Type, Format: simple.Format}
+		if simple.Items != nil {
+			header.ItemsType = simple.Items.Type
+		}
+
+		key := fmt.Sprintf("%s|%t|%s|%s|%s", header.Name, header.Response, header.Type, header.Format, header.ItemsType)
+		i, known := index[key]
+		if !known {
+			i = len(result)
+			index[key] = i
+			result = append(result, header)
+		}
+
+		result[i].Uses = append(result[i].Uses, use)
+	}
+
+	skip := func(spec.Parameter, error) bool { return true }
+	walkOperations(s.spec, func(pointer string, op *spec.Operation) {
+		parent, method := splitPointer(pointer)
+		_, path := splitPointer(parent)
+		operation := strings.ToUpper(method) + " " + path
+
+		params := s.SafeParamsFor(method, path, skip)
+		for _, key := range sortedKeys(params) {
+			if param := params[key]; param.In == "header" {
+				add(param.Name, false, param.SimpleSchema, HeaderUse{Operation: operation, Pointer: "#" + pointer})
+			}
+		}
+
+		s.forEachOperationResponse(op, func(status string, resp spec.Response) {
+			for _, name := range sortedKeys(resp.Headers) {
+				use := HeaderUse{Operation: operation, Pointer: "#" + pointer + "/responses/" + status, Response: status}
+				add(name, true, resp.Headers[name].SimpleSchema, use)
+			}
+		})
+	})
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+
+		if result[i].Response != result[j].Response {
+			return !result[i].Response
+		}
+
+		return result[i].Type < result[j].Type
+	})
+
+	return result
+}
+
+// HeadersFor gets the headers of the response of an operation with some status code, indexed by name.
+// The headers of the default response apply when the operation declares no response with this status code.
+//
+// $ref's to shared responses are resolved.
+func (s *Spec) HeadersFor(operation *spec.Operation, status int) map[string]spec.Header {
+	if operation == nil || operation.Responses == nil {
+		return nil
+	}
+
+	resp, found := operation.Responses.StatusCodeResponses[status]
+	if !found {
+		if operation.Responses.Default == nil {
+			return nil
+		}
+
+		resp = *operation.Responses.Default
+	}
+
+	return s.resolveResponse(resp).Headers
+}
+
+// forEachOperationResponse visits the responses of an operation, with $ref's to shared responses resolved,
+// starting with the default response then by status code
+func (s *Spec) forEachOperationResponse(operation *spec.Operation, visit func(status string, resp spec.Response)) {
+	if operation.Responses == nil {
+		return
+	}
+
+	if operation.Responses.Default != nil {
+		visit("default", s.resolveResponse(*operation.Responses.Default))
+	}
+
+	for _, code := range sortedStatusCodes(operation.Responses.StatusCodeResponses) {
+		visit(strconv.Itoa(code), s.resolveResponse(operation.Responses.StatusCodeResponses[code]))
+	}
+}
+
+// resolveResponse follows a $ref to a shared response
+func (s *Spec) resolveResponse(resp spec.Response) spec.Response {
+	if ref := resp.Ref.String(); strings.HasPrefix(ref, "#/responses/") {
+		if shared, ok := s.spec.Responses[jsonpointer.Unescape(strings.TrimPrefix(ref, "#/responses/"))]; ok {
+			return shared
+		}
+	}
+
+	return resp
+}
+
+// HeaderConflicts reports header parameters which conflict with the semantics of the protocol:
+// swagger 2.0 ignores header parameters named "Accept", "Content-Type" or "Authorization", which are
+// described by produces, consumes and security requirements respectively.
+//
+// Findings are sorted by pointer.
+func (s *Spec) HeaderConflicts() []Finding {
+	var findings []Finding
+	walkParameters(s.spec, func(pointer string, param *spec.Parameter) {
+		if param.In != "header" {
+			return
+		}
+
+		name := http.CanonicalHeaderKey(param.Name)
+		instead, isWellKnown := wellKnownHeaders[name]
+		if !isWellKnown {
+			return
+		}
+
+		findings = append(findings, Finding{
+			Pointer: pointer,
+			Code:    CodeIgnoredHeaderParameter,
+			Message: fmt.Sprintf("header parameter %q is ignored: use %s instead", param.Name, instead),
+		})
+	})
+
+	sortFindings(findings)
+
+	return findings
+}
@@ -0,0 +1,109 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllHeaders(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "headers.yml"))
+	headers := New(sp).AllHeaders()
+
+	assert.Equal(t, []Header{
+		{
+			Name: "Accept", Type: "string",
+			Uses: []HeaderUse{{Operation: "GET /pets", Pointer: "#/paths/~1pets/get"}},
+		},
+		{
+			Name: "Link", Response: true, Type: "array", ItemsType: "string",
+			Uses: []HeaderUse{{Operation: "POST /pets", Pointer: "#/paths/~1pets/post/responses/201", Response: "201"}},
+		},
+		{
+			Name: "X-Next-Page-Token", Response: true, Type: "string",
+			Uses: []HeaderUse{{Operation: "GET /pets", Pointer: "#/paths/~1pets/get/responses/200", Response: "200"}},
+		},
+		{
+			Name: "X-Page-Token", Type: "string",
+			Uses: []HeaderUse{{Operation: "GET /pets", Pointer: "#/paths/~1pets/get"}},
+		},
+		{
+			Name: "X-Rate-Limit", Response: true, Type: "integer", Format: "int32",
+			Uses: []HeaderUse{
+				{Operation: "GET /pets", Pointer: "#/paths/~1pets/get/responses/200", Response: "200"},
+				{Operation: "POST /pets", Pointer: "#/paths/~1pets/post/responses/201", Response: "201"},
+			},
+		},
+		{
+			// the operation overrides the parameter declared on the path item
+			Name: "X-Request-Id", Type: "integer",
+			Uses: []HeaderUse{{Operation: "POST /pets", Pointer: "#/paths/~1pets/post"}},
+		},
+		{
+			Name: "X-Request-Id", Type: "string", Format: "uuid",
+			Uses: []HeaderUse{{Operation: "GET /pets", Pointer: "#/paths/~1pets/get"}},
+		},
+		{
+			Name: "X-Request-Id", Response: true, Type: "string", Format: "uuid",
+			Uses: []HeaderUse{{Operation: "GET /pets", Pointer: "#/paths/~1pets/get/responses/default", Response: "default"}},
+		},
+	}, headers)
+}
+
+func TestHeadersFor(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "headers.yml"))
+	an := New(sp)
+
+	op, ok := an.OperationFor("GET", "/pets")
+	require.True(t, ok)
+
+	headers := an.HeadersFor(op, 200)
+	assert.Equal(t, []string{"X-Next-Page-Token", "X-Rate-Limit"}, sortedKeys(headers))
+	assert.Equal(t, "int32", headers["X-Rate-Limit"].Format)
+
+	// the default response applies, from a shared response
+	headers = an.HeadersFor(op, 500)
+	assert.Equal(t, []string{"X-Request-ID"}, sortedKeys(headers))
+
+	op, ok = an.OperationFor("POST", "/pets")
+	require.True(t, ok)
+	assert.Empty(t, an.HeadersFor(op, 500))
+	assert.Nil(t, an.HeadersFor(nil, 200))
+}
+
+func TestHeaderConflicts(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "headers.yml"))
+	findings := New(sp).HeaderConflicts()
+
+	assert.Equal(t, []Finding{
+		{
+			Pointer: "#/parameters/auth",
+			Code:    CodeIgnoredHeaderParameter,
+			Message: `header parameter "Authorization" is ignored: use securityDefinitions and security instead`,
+		},
+		{
+			Pointer: "#/paths/~1pets/get/parameters/1",
+			Code:    CodeIgnoredHeaderParameter,
+			Message: `header parameter "accept" is ignored: use produces instead`,
+		},
+	}, findings)
+
+	lintFindings := Lint(New(sp), RegisteredLintRules()...)
+	var ignored int
+	for _, finding := range lintFindings {
+		if finding.Rule == CodeIgnoredHeaderParameter {
+			ignored++
+			assert.Equal(t, SeverityWarning, finding.Severity)
+		}
+	}
+	assert.Equal(t, 2, ignored)
+}
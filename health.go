@@ -0,0 +1,231 @@
+package analysis
+
+import (
+	"math"
+
+	"github.com/go-openapi/spec"
+)
+
+// HealthCategory is a category of the health score of a spec
+type HealthCategory string
+
+// Categories of the health score
+const (
+	// HealthFindings measures the number of issues reported by checks, relative to the size of the spec
+	HealthFindings HealthCategory = "findings"
+	// HealthDescriptions measures the proportion of operations, parameters and definitions with a description
+	HealthDescriptions HealthCategory = "descriptions"
+	// HealthExamples measures the proportion of operations and definitions with examples
+	HealthExamples HealthCategory = "examples"
+	// HealthSecurity measures the proportion of operations requiring some security scheme
+	HealthSecurity HealthCategory = "security"
+)
+
+// HealthCheck is a check contributing findings to the health score
+type HealthCheck func(*Spec) []Finding
+
+// DefaultHealthChecks returns the checks used by Health when none are configured
+func DefaultHealthChecks() []HealthCheck {
+	return []HealthCheck{
+		(*Spec).UnsatisfiableSchemas,
+		(*Spec).NoOpConstraints,
+		(*Spec).OperationIDIssues,
+		(*Spec).EmptyResponseDescriptions,
+		(*Spec).InconsistentDefaults,
+		func(s *Spec) []Finding { return s.CompiledPatterns().Findings },
+		func(s *Spec) []Finding { return s.TimeFormats().Findings },
+		func(s *Spec) []Finding { return s.NamingConventions(NamingOpts{}).Findings },
+		func(s *Spec) []Finding { return s.Units(UnitOpts{}).Findings },
+	}
+}
+
+// HealthOpts configures the computation of the health score
+type HealthOpts struct {
+	// Weights of each category in the overall score. Categories with no weight do not count.
+	//
+	// Defaults to equal weights for all categories.
+	Weights map[HealthCategory]float64
+
+	// Checks contributing findings. Defaults to DefaultHealthChecks().
+	Checks []HealthCheck
+
+	_ struct{}
+}
+
+// CategoryScore is the score of a spec for a category, from 0 to 100
+type CategoryScore struct {
+	Score  float64
+	Weight float64
+
+	// Covered and Total count the elements which qualify for this category, out of all inspected elements.
+	//
+	// For the findings category, Covered is the number of findings.
+	Covered int
+	Total   int
+}
+
+// HealthScore is the weighted health score of a spec, from 0 to 100, with the breakdown per category
+type HealthScore struct {
+	Score      float64
+	Categories map[HealthCategory]CategoryScore
+	Findings   []Finding // all the findings reported by checks, sorted by pointer
+}
+
+// Health aggregates findings, description, example and security coverage into a single weighted score.
+//
+// Each category is scored from 0 to 100:
+//   - findings: 100, minus the ratio of findings per operation or definition (floored at 0)
+//   - descriptions: the proportion of operations, parameters and definitions with a description (or a summary)
+//   - examples: the proportion of operations (with examples in some response) and definitions with an example
+//   - security: the proportion of operations which require authentication. Operations with an optional
+//     (empty) security requirement are not considered as secured.
+//
+// Categories without any element to inspect score 100.
+func (s *Spec) Health(opts HealthOpts) HealthScore {
+	weights := opts.Weights
+	if weights == nil {
+		weights = map[HealthCategory]float64{
+			HealthFindings: 1, HealthDescriptions: 1, HealthExamples: 1, HealthSecurity: 1,
+		}
+	}
+
+	checks := opts.Checks
+	if checks == nil {
+		checks = DefaultHealthChecks()
+	}
+
+	var findings []Finding
+	for _, check := range checks {
+		findings = append(findings, check(s)...)
+	}
+	sortFindings(findings)
+
+	var operations, definitions int
+	var described, exemplified, secured, params, describedParams int
+
+	walkOperations(s.spec, func(_ string, op *spec.Operation) {
+		operations++
+		if op.Description != "" || op.Summary != "" {
+			described++
+		}
+
+		if operationHasExamples(op) {
+			exemplified++
+		}
+
+		if s.isSecured(op) {
+			secured++
+		}
+	})
+
+	walkParameters(s.spec, func(_ string, param *spec.Parameter) {
+		if param.Ref.String() != "" {
+			return
+		}
+
+		params++
+		if param.Description != "" {
+			describedParams++
+		}
+	})
+
+	if s.spec != nil {
+		for _, def := range s.spec.Definitions {
+			definitions++
+			if def.Description != "" {
+				described++
+			}
+
+			if def.Example != nil {
+				exemplified++
+			}
+		}
+	}
+
+	findingsTotal := operations + definitions
+	categories := map[HealthCategory]CategoryScore{
+		HealthFindings:     {Covered: len(findings), Total: findingsTotal, Score: findingsScore(len(findings), findingsTotal)},
+		HealthDescriptions: coverageScore(described+describedParams, operations+definitions+params),
+		HealthExamples:     coverageScore(exemplified, operations+definitions),
+		HealthSecurity:     coverageScore(secured, operations),
+	}
+
+	var total, sumWeights float64
+	for category, score := range categories {
+		score.Weight = weights[category]
+		categories[category] = score
+		total += score.Score * score.Weight
+		sumWeights += score.Weight
+	}
+
+	result := HealthScore{Score: 100, Categories: categories, Findings: findings}
+	if sumWeights > 0 {
+		result.Score = roundScore(total / sumWeights)
+	}
+
+	return result
+}
+
+func (s *Spec) isSecured(op *spec.Operation) bool {
+	requirements := s.SecurityRequirementsFor(op)
+	if len(requirements) == 0 {
+		return false
+	}
+
+	for _, alternative := range requirements {
+		if len(alternative) == 0 || (len(alternative) == 1 && alternative[0].Name == "") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func operationHasExamples(op *spec.Operation) bool {
+	if op.Responses == nil {
+		return false
+	}
+
+	responses := make([]spec.Response, 0, len(op.Responses.StatusCodeResponses)+1)
+	if op.Responses.Default != nil {
+		responses = append(responses, *op.Responses.Default)
+	}
+
+	for _, resp := range op.Responses.StatusCodeResponses {
+		responses = append(responses, resp)
+	}
+
+	for _, resp := range responses {
+		if len(resp.Examples) > 0 || (resp.Schema != nil && resp.Schema.Example != nil) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func coverageScore(covered, total int) CategoryScore {
+	score := CategoryScore{Covered: covered, Total: total, Score: 100}
+	if total > 0 {
+		score.Score = roundScore(100 * float64(covered) / float64(total))
+	}
+
+	return score
+}
+
+func findingsScore(findings, total int) float64 {
+	if total == 0 {
+		if findings > 0 {
+			return 0
+		}
+
+		return 100
+	}
+
+	return roundScore(math.Max(0, 100*(1-float64(findings)/float64(total))))
+}
+
+// roundScore rounds a score to 2 decimals
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
@@ -0,0 +1,54 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "health.yml")))
+
+	t.Run("with default options", func(t *testing.T) {
+		t.Parallel()
+
+		health := an.Health(HealthOpts{})
+
+		assert.Equal(t, map[HealthCategory]CategoryScore{
+			HealthFindings:     {Score: 75, Weight: 1, Covered: 1, Total: 4},
+			HealthDescriptions: {Score: 50, Weight: 1, Covered: 3, Total: 6},
+			HealthExamples:     {Score: 50, Weight: 1, Covered: 2, Total: 4},
+			HealthSecurity:     {Score: 50, Weight: 1, Covered: 1, Total: 2},
+		}, health.Categories)
+		assert.InDelta(t, 56.25, health.Score, 1e-9)
+
+		require.Len(t, health.Findings, 1)
+		assert.Equal(t, CodeMissingOperationID, health.Findings[0].Code)
+	})
+
+	t.Run("with weights and checks", func(t *testing.T) {
+		t.Parallel()
+
+		health := an.Health(HealthOpts{
+			Weights: map[HealthCategory]float64{HealthFindings: 3, HealthSecurity: 1},
+			Checks:  []HealthCheck{},
+		})
+
+		assert.Empty(t, health.Findings)
+		assert.InDelta(t, 87.5, health.Score, 1e-9)
+		assert.Zero(t, health.Categories[HealthExamples].Weight)
+	})
+
+	t.Run("with an empty spec", func(t *testing.T) {
+		t.Parallel()
+
+		health := New(&spec.Swagger{}).Health(HealthOpts{})
+		assert.InDelta(t, 100, health.Score, 1e-9)
+	})
+}
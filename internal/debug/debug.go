@@ -28,11 +28,17 @@ var (
 
 // GetLogger provides a prefix debug logger
 func GetLogger(prefix string, debug bool) func(string, ...interface{}) {
+	return GetLoggerWithDepth(prefix, debug, 1)
+}
+
+// GetLoggerWithDepth provides a prefix debug logger, which reports the location of the caller
+// found depth frames up the stack (1 being the caller of the logger)
+func GetLoggerWithDepth(prefix string, debug bool, depth int) func(string, ...interface{}) {
 	if debug {
 		logger := log.New(output, fmt.Sprintf("%s:", prefix), log.LstdFlags)
 
 		return func(msg string, args ...interface{}) {
-			_, file1, pos1, _ := runtime.Caller(1)
+			_, file1, pos1, _ := runtime.Caller(depth)
 			logger.Printf("%s:%d: %s", filepath.Base(file1), pos1, fmt.Sprintf(msg, args...))
 		}
 	}
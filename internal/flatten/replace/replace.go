@@ -185,6 +185,29 @@ func getParentFromKey(sp interface{}, key string) (string, string, interface{},
 
 // UpdateRef replaces a ref by another one
 func UpdateRef(sp interface{}, key string, ref spec.Ref) error {
+	return updateRef(sp, key, ref, false)
+}
+
+// UpdateRefKeepingKeywords replaces a ref by another one, like UpdateRef.
+//
+// The vendor extensions and the unknown keywords (e.g. JSON schema keywords not supported by swagger 2.0)
+// found alongside the replaced ref are retained.
+func UpdateRefKeepingKeywords(sp interface{}, key string, ref spec.Ref) error {
+	return updateRef(sp, key, ref, true)
+}
+
+// refSchema builds a schema with a ref, to replace a schema held by a container
+func refSchema(replaced spec.Schema, ref spec.Ref, keepKeywords bool) spec.Schema {
+	sch := spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref}}
+	if keepKeywords {
+		sch.VendorExtensible = replaced.VendorExtensible
+		sch.ExtraProps = replaced.ExtraProps
+	}
+
+	return sch
+}
+
+func updateRef(sp interface{}, key string, ref spec.Ref, keepKeywords bool) error {
 	switch sp.(type) {
 	case *spec.Schema:
 	case *spec.Swagger:
@@ -216,17 +239,17 @@ func UpdateRef(sp interface{}, key string, ref spec.Ref) error {
 		}
 		switch container := pvalue.(type) {
 		case spec.Definitions:
-			container[entry] = spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref}}
+			container[entry] = refSchema(refable, ref, keepKeywords)
 
 		case map[string]spec.Schema:
-			container[entry] = spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref}}
+			container[entry] = refSchema(refable, ref, keepKeywords)
 
 		case []spec.Schema:
 			idx, err := strconv.Atoi(entry)
 			if err != nil {
 				return fmt.Errorf("%s not a number: %w", pth, err)
 			}
-			container[idx] = spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref}}
+			container[idx] = refSchema(refable, ref, keepKeywords)
 
 		case *spec.SchemaOrArray:
 			// NOTE: this is necessarily an array - otherwise, the parent would be *Schema
@@ -234,10 +257,10 @@ func UpdateRef(sp interface{}, key string, ref spec.Ref) error {
 			if err != nil {
 				return fmt.Errorf("%s not a number: %w", pth, err)
 			}
-			container.Schemas[idx] = spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref}}
+			container.Schemas[idx] = refSchema(refable, ref, keepKeywords)
 
 		case spec.SchemaProperties:
-			container[entry] = spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref}}
+			container[entry] = refSchema(refable, ref, keepKeywords)
 
 		// NOTE: can't have case *spec.SchemaOrBool = parent in this case is *Schema
 
@@ -62,6 +62,35 @@ func TestUpdateRef(t *testing.T) {
 	}
 }
 
+func TestUpdateRefKeepingKeywords(t *testing.T) {
+	t.Parallel()
+
+	newSpec := func() *spec.Swagger {
+		return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Definitions: spec.Definitions{
+				"named": spec.Schema{
+					SchemaProps:      spec.SchemaProps{Ref: spec.MustCreateRef("other.yml#/definitions/named")},
+					VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-order": 1}},
+					ExtraProps:       map[string]interface{}{"$comment": "kept"},
+				},
+			},
+		}}
+	}
+	sp, other := newSpec(), newSpec()
+
+	require.NoError(t, UpdateRefKeepingKeywords(sp, "#/definitions/named", spec.MustCreateRef("#/definitions/record")))
+	named := sp.Definitions["named"]
+	assert.Equal(t, "#/definitions/record", named.Ref.String())
+	assert.Equal(t, spec.Extensions{"x-order": 1}, named.Extensions)
+	assert.Equal(t, map[string]interface{}{"$comment": "kept"}, named.ExtraProps)
+
+	require.NoError(t, UpdateRef(other, "#/definitions/named", spec.MustCreateRef("#/definitions/record")))
+	named = other.Definitions["named"]
+	assert.Equal(t, "#/definitions/record", named.Ref.String())
+	assert.Empty(t, named.Extensions)
+	assert.Empty(t, named.ExtraProps)
+}
+
 func TestRewriteSchemaRef(t *testing.T) {
 	t.Parallel()
 
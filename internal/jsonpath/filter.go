@@ -0,0 +1,137 @@
+package jsonpath
+
+import "reflect"
+
+// expression is a filter expression, tested against each child of the filtered node
+type expression interface {
+	test(current interface{}) bool
+}
+
+type orExpr []expression
+
+func (e orExpr) test(current interface{}) bool {
+	for _, term := range e {
+		if term.test(current) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type andExpr []expression
+
+func (e andExpr) test(current interface{}) bool {
+	for _, term := range e {
+		if !term.test(current) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type notExpr struct {
+	inner expression
+}
+
+func (e notExpr) test(current interface{}) bool {
+	return !e.inner.test(current)
+}
+
+type existsExpr struct {
+	path []selector
+}
+
+func (e existsExpr) test(current interface{}) bool {
+	_, ok := resolve(e.path, current)
+
+	return ok
+}
+
+type operand struct {
+	isPath  bool
+	path    []selector
+	literal interface{}
+}
+
+func (o operand) value(current interface{}) (interface{}, bool) {
+	if !o.isPath {
+		return o.literal, true
+	}
+
+	return resolve(o.path, current)
+}
+
+type compareExpr struct {
+	op          string
+	left, right operand
+}
+
+func (e compareExpr) test(current interface{}) bool {
+	left, hasLeft := e.left.value(current)
+	right, hasRight := e.right.value(current)
+
+	switch e.op {
+	case "==":
+		return equal(left, hasLeft, right, hasRight)
+	case "!=":
+		return !equal(left, hasLeft, right, hasRight)
+	}
+
+	if !hasLeft || !hasRight {
+		return false
+	}
+
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+
+		return ok && compare(e.op, l, r)
+	case string:
+		r, ok := right.(string)
+
+		return ok && compare(e.op, l, r)
+	default:
+		return false
+	}
+}
+
+// equal compares two values, where a missing value only equals another missing value
+func equal(left interface{}, hasLeft bool, right interface{}, hasRight bool) bool {
+	if !hasLeft || !hasRight {
+		return hasLeft == hasRight
+	}
+
+	return reflect.DeepEqual(left, right)
+}
+
+func compare[T float64 | string](op string, l, r T) bool {
+	switch op {
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	default:
+		return false
+	}
+}
+
+// resolve evaluates a singular relative path
+func resolve(path []selector, current interface{}) (interface{}, bool) {
+	node := Node{Value: current}
+	for _, sel := range path {
+		nodes := sel.apply(node)
+		if len(nodes) == 0 {
+			return nil, false
+		}
+
+		node = nodes[0]
+	}
+
+	return node.Value, true
+}
@@ -0,0 +1,235 @@
+// Package jsonpath evaluates JSONPath expressions against generic JSON documents,
+// i.e. documents made of map[string]interface{}, []interface{} and scalar values,
+// as produced by encoding/json.
+//
+// The supported syntax is a subset of RFC 9535:
+//
+//   - the root identifier: $
+//   - child members: .name, ['name'], ["name"]
+//   - array elements: [0], [-1]
+//   - wildcards: .* and [*]
+//   - unions: ['a','b'], [0,1]
+//   - descendants: ..name, ..*, ..['name'], ..[0]
+//   - filters: [?(@.type == 'string')], [?@.deprecated], [?(!@.x && @.n >= 2)]
+//
+// Filters support relative paths made of child members and array elements, comparisons
+// (==, !=, <, <=, >, >=) with string, number, boolean and null literals, existence tests,
+// negation, conjunction, disjunction and parentheses.
+package jsonpath
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+)
+
+// Node is a value selected by a path
+type Node struct {
+	// Location holds the keys (strings) and indices (ints) leading from the root of the document to the value
+	Location []interface{}
+	Value    interface{}
+}
+
+// Pointer returns the location of the node as a JSON pointer, e.g. "/paths/~1pets/get"
+func (n Node) Pointer() string {
+	var b strings.Builder
+	for _, token := range n.Location {
+		b.WriteByte('/')
+		switch t := token.(type) {
+		case string:
+			b.WriteString(jsonpointer.Escape(t))
+		case int:
+			b.WriteString(strconv.Itoa(t))
+		}
+	}
+
+	return b.String()
+}
+
+// Path is a compiled JSONPath expression
+type Path struct {
+	expr     string
+	segments []segment
+}
+
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+type selectorKind uint8
+
+const (
+	nameSelector selectorKind = iota
+	indexSelector
+	wildcardSelector
+	filterSelector
+)
+
+type selector struct {
+	kind   selectorKind
+	name   string
+	index  int
+	filter expression
+}
+
+// Compile parses a JSONPath expression
+func Compile(expr string) (*Path, error) {
+	p := &parser{input: expr}
+	segments, err := p.parsePath()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+	}
+
+	return &Path{expr: expr, segments: segments}, nil
+}
+
+// MustCompile is like Compile but panics if the expression is invalid
+func MustCompile(expr string) *Path {
+	p, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// String returns the source expression
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Find returns all the nodes selected by the path in a document.
+//
+// Nodes are returned in document order. Members of objects are visited in lexicographic order of their keys,
+// so results are deterministic.
+func (p *Path) Find(doc interface{}) []Node {
+	nodes := []Node{{Value: doc}}
+	for _, seg := range p.segments {
+		var next []Node
+		for _, node := range nodes {
+			if !seg.descendant {
+				next = append(next, seg.apply(node)...)
+
+				continue
+			}
+
+			for _, descendant := range descendants(node) {
+				next = append(next, seg.apply(descendant)...)
+			}
+		}
+
+		nodes = next
+	}
+
+	return nodes
+}
+
+func (s segment) apply(node Node) []Node {
+	var result []Node
+	for _, sel := range s.selectors {
+		result = append(result, sel.apply(node)...)
+	}
+
+	return result
+}
+
+func (s selector) apply(node Node) []Node {
+	switch s.kind {
+	case nameSelector:
+		obj, ok := node.Value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		value, ok := obj[s.name]
+		if !ok {
+			return nil
+		}
+
+		return []Node{node.child(s.name, value)}
+
+	case indexSelector:
+		arr, ok := node.Value.([]interface{})
+		if !ok {
+			return nil
+		}
+
+		index := s.index
+		if index < 0 {
+			index += len(arr)
+		}
+
+		if index < 0 || index >= len(arr) {
+			return nil
+		}
+
+		return []Node{node.child(index, arr[index])}
+
+	case wildcardSelector:
+		return children(node)
+
+	case filterSelector:
+		var result []Node
+		for _, child := range children(node) {
+			if s.filter.test(child.Value) {
+				result = append(result, child)
+			}
+		}
+
+		return result
+
+	default:
+		return nil
+	}
+}
+
+func (n Node) child(token, value interface{}) Node {
+	location := make([]interface{}, len(n.Location), len(n.Location)+1)
+	copy(location, n.Location)
+
+	return Node{Location: append(location, token), Value: value}
+}
+
+// children yields the members of an object, or the elements of an array
+func children(node Node) []Node {
+	switch v := node.Value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		result := make([]Node, 0, len(keys))
+		for _, k := range keys {
+			result = append(result, node.child(k, v[k]))
+		}
+
+		return result
+
+	case []interface{}:
+		result := make([]Node, 0, len(v))
+		for i, elem := range v {
+			result = append(result, node.child(i, elem))
+		}
+
+		return result
+
+	default:
+		return nil
+	}
+}
+
+// descendants yields a node and all its descendants, parents first
+func descendants(node Node) []Node {
+	result := []Node{node}
+	for _, child := range children(node) {
+		result = append(result, descendants(child)...)
+	}
+
+	return result
+}
@@ -0,0 +1,97 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `{
+  "paths": {
+    "/pets": {
+      "get": {"operationId": "listPets", "tags": ["pets"], "parameters": [{"name": "limit", "in": "query", "type": "integer", "maximum": 100}]},
+      "post": {"operationId": "createPet", "tags": ["pets", "admin"], "deprecated": true}
+    },
+    "/stores": {
+      "get": {"operationId": "listStores", "parameters": [{"name": "it's", "in": "header", "type": "string"}]}
+    }
+  },
+  "x-internal": {"it's": "quoted"}
+}`
+
+func TestPath_Find(t *testing.T) {
+	t.Parallel()
+
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(testDocument), &doc))
+
+	for expr, expected := range map[string][]string{
+		"$":                                         {""},
+		"$.paths['/pets'].get":                      {"/paths/~1pets/get"},
+		`$.paths["/pets"].get.operationId`:          {"/paths/~1pets/get/operationId"},
+		"$.paths.*.get":                             {"/paths/~1pets/get", "/paths/~1stores/get"},
+		"$.paths['/pets'][*]":                       {"/paths/~1pets/get", "/paths/~1pets/post"},
+		"$.paths['/pets'].post.tags[-1]":            {"/paths/~1pets/post/tags/1"},
+		"$.paths['/pets'].post.tags[0,5]":           {"/paths/~1pets/post/tags/0"},
+		"$.paths['/pets']['get','post'].deprecated": {"/paths/~1pets/post/deprecated"},
+		"$..operationId": {
+			"/paths/~1pets/get/operationId", "/paths/~1pets/post/operationId", "/paths/~1stores/get/operationId",
+		},
+		"$..parameters[0].name":                                      {"/paths/~1pets/get/parameters/0/name", "/paths/~1stores/get/parameters/0/name"},
+		"$.paths.*[?(@.deprecated == true)]":                         {"/paths/~1pets/post"},
+		"$.paths.*[?@.deprecated]":                                   {"/paths/~1pets/post"},
+		"$.paths.*[?(!@.deprecated && @.parameters)]":                {"/paths/~1pets/get", "/paths/~1stores/get"},
+		"$..parameters[?(@.in == 'query' || @.maximum >= 100)].name": {"/paths/~1pets/get/parameters/0/name"},
+		"$..parameters[?(@.name == 'it\\'s')]":                       {"/paths/~1stores/get/parameters/0"},
+		"$.paths.*.*[?(@.tags[1] == 'admin')]":                       nil,
+		"$.paths.*[?(@.tags[1] == 'admin')]":                         {"/paths/~1pets/post"},
+		"$['x-internal']['it\\'s']":                                  {"/x-internal/it's"},
+		"$.nowhere.*":                                                nil,
+	} {
+		path, err := Compile(expr)
+		require.NoErrorf(t, err, "unexpected error compiling %q", expr)
+
+		var pointers []string
+		for _, node := range path.Find(doc) {
+			pointers = append(pointers, node.Pointer())
+		}
+
+		assert.Equalf(t, expected, pointers, "unexpected nodes for %q", expr)
+	}
+}
+
+func TestPath_FindValues(t *testing.T) {
+	t.Parallel()
+
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(testDocument), &doc))
+
+	nodes := MustCompile("$.paths['/pets'].post.tags").Find(doc)
+	require.Len(t, nodes, 1)
+	assert.Equal(t, []interface{}{"paths", "/pets", "post", "tags"}, nodes[0].Location)
+	assert.Equal(t, []interface{}{"pets", "admin"}, nodes[0].Value)
+}
+
+func TestCompile_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{
+		"",
+		"paths",
+		"$.",
+		"$paths",
+		"$[",
+		"$['unterminated]",
+		"$[1.5]",
+		"$[?(@.a == 'x']",
+		"$[?('x')]",
+		"$[?(@.a ==)]",
+	} {
+		_, err := Compile(expr)
+		assert.Errorf(t, err, "expected %q to be rejected", expr)
+	}
+
+	assert.Panics(t, func() { MustCompile("$.") })
+}
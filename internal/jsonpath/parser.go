@@ -0,0 +1,441 @@
+package jsonpath
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.input[p.pos]
+}
+
+func (p *parser) consume(token string) bool {
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) skipSpaces() {
+	for !p.eof() && strings.IndexByte(" \t\n\r", p.peek()) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parsePath() ([]segment, error) {
+	if !p.consume("$") {
+		return nil, errors.New("a path must start with $")
+	}
+
+	var segments []segment
+	for !p.eof() {
+		var seg segment
+		switch {
+		case p.consume(".."):
+			seg.descendant = true
+			if p.peek() == '[' {
+				break
+			}
+
+			sel, err := p.parseDotSelector()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = []selector{sel}
+
+		case p.consume("."):
+			sel, err := p.parseDotSelector()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = []selector{sel}
+
+		case p.peek() == '[':
+		default:
+			return nil, p.errorf("unexpected character %q", p.peek())
+		}
+
+		if seg.selectors == nil {
+			selectors, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = selectors
+		}
+
+		segments = append(segments, seg)
+	}
+
+	return segments, nil
+}
+
+// parseDotSelector parses the selector following a dot: a wildcard or a member name
+func (p *parser) parseDotSelector() (selector, error) {
+	if p.consume("*") {
+		return selector{kind: wildcardSelector}, nil
+	}
+
+	name, err := p.parseName(".[")
+	if err != nil {
+		return selector{}, err
+	}
+
+	return selector{kind: nameSelector, name: name}, nil
+}
+
+// parseName parses an unquoted member name, up to one of the stop characters
+func (p *parser) parseName(stop string) (string, error) {
+	start := p.pos
+	for !p.eof() && strings.IndexByte(stop, p.peek()) < 0 && strings.IndexByte(" \t\n\r", p.peek()) < 0 {
+		p.pos++
+	}
+
+	if p.pos == start {
+		return "", p.errorf("expected a member name")
+	}
+
+	return p.input[start:p.pos], nil
+}
+
+// parseBracket parses a comma-separated list of selectors enclosed in brackets
+func (p *parser) parseBracket() ([]selector, error) {
+	if !p.consume("[") {
+		return nil, p.errorf("expected [")
+	}
+
+	var selectors []selector
+	for {
+		p.skipSpaces()
+		sel, err := p.parseBracketSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+
+		p.skipSpaces()
+		switch {
+		case p.consume(","):
+			continue
+		case p.consume("]"):
+			return selectors, nil
+		default:
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+
+func (p *parser) parseBracketSelector() (selector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return selector{}, err
+		}
+
+		return selector{kind: nameSelector, name: name}, nil
+
+	case c == '*':
+		p.pos++
+
+		return selector{kind: wildcardSelector}, nil
+
+	case c == '?':
+		p.pos++
+		filter, err := p.parseOr()
+		if err != nil {
+			return selector{}, err
+		}
+
+		return selector{kind: filterSelector, filter: filter}, nil
+
+	case c == '-' || (c >= '0' && c <= '9'):
+		index, err := p.parseInt()
+		if err != nil {
+			return selector{}, err
+		}
+
+		return selector{kind: indexSelector, index: index}, nil
+
+	default:
+		return selector{}, p.errorf("unexpected character %q in brackets", c)
+	}
+}
+
+func (p *parser) parseInt() (int, error) {
+	start := p.pos
+	p.consume("-")
+	for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+
+	index, err := strconv.Atoi(p.input[start:p.pos])
+	if err != nil {
+		return 0, p.errorf("invalid index %q", p.input[start:p.pos])
+	}
+
+	return index, nil
+}
+
+// parseString parses a single- or double-quoted string literal
+func (p *parser) parseString() (string, error) {
+	quote := p.peek()
+	p.pos++
+
+	var b strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		p.pos++
+
+		switch c {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated escape sequence")
+			}
+
+			escaped := p.peek()
+			p.pos++
+			switch escaped {
+			case '\\', '/', '\'', '"':
+				b.WriteByte(escaped)
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				return "", p.errorf("unsupported escape sequence \\%c", escaped)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := orExpr{left}
+	for {
+		p.skipSpaces()
+		if !p.consume("||") {
+			break
+		}
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+
+	if len(terms) == 1 {
+		return left, nil
+	}
+
+	return terms, nil
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := andExpr{left}
+	for {
+		p.skipSpaces()
+		if !p.consume("&&") {
+			break
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+
+	if len(terms) == 1 {
+		return left, nil
+	}
+
+	return terms, nil
+}
+
+func (p *parser) parseUnary() (expression, error) {
+	p.skipSpaces()
+
+	switch {
+	case p.consume("!"):
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notExpr{inner}, nil
+
+	case p.consume("("):
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+		if !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+
+		return inner, nil
+
+	default:
+		return p.parseComparison()
+	}
+}
+
+var comparisonOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (p *parser) parseComparison() (expression, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	for _, op := range comparisonOperators {
+		if !p.consume(op) {
+			continue
+		}
+
+		p.skipSpaces()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		return compareExpr{op: op, left: left, right: right}, nil
+	}
+
+	if !left.isPath {
+		return nil, p.errorf("a literal is not a valid test expression")
+	}
+
+	return existsExpr{path: left.path}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	switch c := p.peek(); {
+	case c == '@':
+		p.pos++
+		path, err := p.parseRelativePath()
+		if err != nil {
+			return operand{}, err
+		}
+
+		return operand{isPath: true, path: path}, nil
+
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return operand{}, err
+		}
+
+		return operand{literal: s}, nil
+
+	case p.consume("true"):
+		return operand{literal: true}, nil
+
+	case p.consume("false"):
+		return operand{literal: false}, nil
+
+	case p.consume("null"):
+		return operand{literal: nil}, nil
+
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for !p.eof() && strings.IndexByte("+-.0123456789eE", p.peek()) >= 0 {
+			p.pos++
+		}
+
+		f, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return operand{}, p.errorf("invalid number %q", p.input[start:p.pos])
+		}
+
+		return operand{literal: f}, nil
+
+	default:
+		return operand{}, p.errorf("expected @ or a literal")
+	}
+}
+
+// parseRelativePath parses the singular path following @ in a filter
+func (p *parser) parseRelativePath() ([]selector, error) {
+	var path []selector
+	for {
+		switch {
+		case p.consume("."):
+			name, err := p.parseName(".[]()=!<>&|,")
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, selector{kind: nameSelector, name: name})
+
+		case p.consume("["):
+			p.skipSpaces()
+			var sel selector
+			switch c := p.peek(); {
+			case c == '\'' || c == '"':
+				name, err := p.parseString()
+				if err != nil {
+					return nil, err
+				}
+				sel = selector{kind: nameSelector, name: name}
+			default:
+				index, err := p.parseInt()
+				if err != nil {
+					return nil, err
+				}
+				sel = selector{kind: indexSelector, index: index}
+			}
+
+			p.skipSpaces()
+			if !p.consume("]") {
+				return nil, p.errorf("expected ]")
+			}
+			path = append(path, sel)
+
+		default:
+			return path, nil
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// OperationKey identifies an operation by its (upper case) method and path
+type OperationKey struct {
+	Method string
+	Path   string
+}
+
+func (k OperationKey) String() string {
+	return k.Method + " " + k.Path
+}
+
+// pointer yields the JSON pointer to the operation (e.g. "#/paths/~1pets/get")
+func (k OperationKey) pointer() string {
+	return "#/paths/" + jsonpointer.Escape(k.Path) + "/" + strings.ToLower(k.Method)
+}
+
+// EachOperation calls fn for every operation found in the spec, with its (upper case) method and path.
+//
+// Iteration stops as soon as fn returns false. Operations are visited in no particular order.
+//
+// Unlike Operations(), this does not expose the internal index to the caller, and unlike
+// OperationIDs() or OperationMethodPaths(), it does not allocate a new slice.
+func (s *Spec) EachOperation(fn func(method, path string, op *spec.Operation) bool) {
+	for method, byPath := range s.operations {
+		for path, op := range byPath {
+			if !fn(method, path, op) {
+				return
+			}
+		}
+	}
+}
+
+// EachReference calls fn for every $ref found in the document, with the JSON pointer
+// to the location of the $ref (e.g. "#/definitions/pet/properties/category").
+//
+// Iteration stops as soon as fn returns false. References are visited in no particular order.
+//
+// This walks the same index as AllReferences(), without copying it into a new slice.
+func (s *Spec) EachReference(fn func(key string, ref spec.Ref) bool) {
+	for key, ref := range s.references.allRefs {
+		if !fn(key, ref) {
+			return
+		}
+	}
+}
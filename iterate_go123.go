@@ -0,0 +1,28 @@
+//go:build go1.23
+
+package analysis
+
+import (
+	"iter"
+
+	"github.com/go-openapi/spec"
+)
+
+// OperationsSeq returns an iterator over all the operations in the spec.
+//
+// This is the range-over-func equivalent of EachOperation.
+func (s *Spec) OperationsSeq() iter.Seq2[OperationKey, *spec.Operation] {
+	return func(yield func(OperationKey, *spec.Operation) bool) {
+		s.EachOperation(func(method, path string, op *spec.Operation) bool {
+			return yield(OperationKey{Method: method, Path: path}, op)
+		})
+	}
+}
+
+// ReferencesSeq returns an iterator over all the $ref found in the document,
+// keyed by the JSON pointer to their location.
+//
+// This is the range-over-func equivalent of EachReference.
+func (s *Spec) ReferencesSeq() iter.Seq2[string, spec.Ref] {
+	return s.EachReference
+}
@@ -0,0 +1,38 @@
+//go:build go1.23
+
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterate_Seq(t *testing.T) {
+	t.Parallel()
+
+	doc := antest.LoadOrFail(t, filepath.Join("fixtures", "references.yml"))
+	an := New(doc)
+
+	operations := 0
+	for key, op := range an.OperationsSeq() {
+		assert.NotEmpty(t, key.Method)
+		assert.NotEmpty(t, key.Path)
+		assert.NotNil(t, op)
+		operations++
+	}
+	assert.Len(t, an.OperationMethodPaths(), operations)
+
+	references := 0
+	for key := range an.ReferencesSeq() {
+		assert.NotEmpty(t, key)
+		references++
+
+		if references == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 2, references)
+}
@@ -0,0 +1,68 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterate_EachOperation(t *testing.T) {
+	t.Parallel()
+
+	doc := antest.LoadOrFail(t, filepath.Join("fixtures", "operations", "fixture-operations.yaml"))
+	an := New(doc)
+
+	var visited []string
+	an.EachOperation(func(method, path string, _ *spec.Operation) bool {
+		visited = append(visited, method+" "+path)
+
+		return true
+	})
+
+	expected := an.OperationMethodPaths()
+	sort.Strings(expected)
+	sort.Strings(visited)
+	assert.Equal(t, expected, visited)
+
+	t.Run("should stop early", func(t *testing.T) {
+		count := 0
+		an.EachOperation(func(_, _ string, _ *spec.Operation) bool {
+			count++
+
+			return false
+		})
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestIterate_EachReference(t *testing.T) {
+	t.Parallel()
+
+	doc := antest.LoadOrFail(t, filepath.Join("fixtures", "references.yml"))
+	an := New(doc)
+
+	var visited []string
+	an.EachReference(func(key string, ref spec.Ref) bool {
+		assert.NotEmpty(t, key)
+		visited = append(visited, ref.String())
+
+		return true
+	})
+
+	expected := an.AllReferences()
+	sort.Strings(expected)
+	sort.Strings(visited)
+	assert.Equal(t, expected, visited)
+
+	count := 0
+	an.EachReference(func(_ string, _ spec.Ref) bool {
+		count++
+
+		return count < 2
+	})
+	assert.Equal(t, 2, count)
+}
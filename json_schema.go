@@ -0,0 +1,269 @@
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// JSONSchemaDraft is a version of the JSON Schema specification
+type JSONSchemaDraft string
+
+// JSON Schema drafts supported by ExportJSONSchema
+const (
+	JSONSchemaDraft04     JSONSchemaDraft = "draft-04"
+	JSONSchemaDraft202012 JSONSchemaDraft = "2020-12"
+)
+
+var jsonSchemaDialects = map[JSONSchemaDraft]string{
+	JSONSchemaDraft04:     "http://json-schema.org/draft-04/schema#",
+	JSONSchemaDraft202012: "https://json-schema.org/draft/2020-12/schema",
+}
+
+// bundle sections for each draft
+var jsonSchemaDefinitions = map[JSONSchemaDraft]string{
+	JSONSchemaDraft04:     "definitions",
+	JSONSchemaDraft202012: "$defs",
+}
+
+// ExportJSONSchema extracts a definition of a spec as a standalone JSON Schema document of some draft.
+//
+// The definitions it refers to, directly or transitively, are bundled in the document under "$defs"
+// (or "definitions" with draft-04), and $ref's are rewritten accordingly. Only $ref's to local definitions
+// are supported: remote $ref's should be resolved first, e.g. with Flatten.
+//
+// Keywords specific to swagger 2.0 are translated:
+//   - "x-nullable: true" adds "null" to the type of the schema;
+//   - "type: file" becomes a binary string;
+//   - with 2020-12, "example" becomes "examples", boolean "exclusiveMinimum"/"exclusiveMaximum" become numeric,
+//     and arrays of "items" become "prefixItems" (with "additionalItems" as "items");
+//   - "discriminator" is dropped, as JSON Schema has no equivalent.
+func ExportJSONSchema(sp *spec.Swagger, name string, draft JSONSchemaDraft) (map[string]interface{}, error) {
+	dialect, supported := jsonSchemaDialects[draft]
+	if !supported {
+		return nil, fmt.Errorf("unsupported JSON schema draft %q", draft)
+	}
+
+	if _, found := sp.Definitions[name]; !found {
+		return nil, fmt.Errorf("no definition %q to export", name)
+	}
+
+	e := &jsonSchemaExporter{sp: sp, root: name, draft: draft, defs: make(map[string]interface{})}
+
+	doc, err := e.export(name)
+	if err != nil {
+		return nil, err
+	}
+
+	root, isMap := doc.(map[string]interface{})
+	if !isMap {
+		root = map[string]interface{}{}
+	}
+
+	root["$schema"] = dialect
+	if len(e.defs) > 0 {
+		root[jsonSchemaDefinitions[draft]] = e.defs
+	}
+
+	return root, nil
+}
+
+type jsonSchemaExporter struct {
+	sp    *spec.Swagger
+	root  string // the exported definition
+	draft JSONSchemaDraft
+	defs  map[string]interface{} // bundled definitions
+}
+
+// export translates a definition, and bundles the definitions it refers to
+func (e *jsonSchemaExporter) export(name string) (interface{}, error) {
+	schema := e.sp.Definitions[name]
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	if err := e.translate(doc, definitionsPrefix+jsonpointer.Escape(name), &pending); err != nil {
+		return nil, err
+	}
+
+	for _, dependency := range pending {
+		if _, bundled := e.defs[dependency]; bundled || dependency == e.root {
+			continue
+		}
+
+		e.defs[dependency] = true // placeholder, against cycles
+		translated, err := e.export(dependency)
+		if err != nil {
+			return nil, err
+		}
+		e.defs[dependency] = translated
+	}
+
+	return doc, nil
+}
+
+// translate translates the keywords of a schema and its subschemas, collecting the definitions it refers to
+func (e *jsonSchemaExporter) translate(node interface{}, pointer string, pending *[]string) error {
+	var nullables []map[string]interface{}
+	err := walkJSONSchema(node, pointer, func(schema map[string]interface{}, pointer string) error {
+		if ref, hasRef := schema["$ref"].(string); hasRef {
+			rewritten, dependency, err := e.rewriteRef(pointer, ref)
+			if err != nil {
+				return err
+			}
+
+			schema["$ref"] = rewritten
+			if dependency != "" {
+				*pending = append(*pending, dependency)
+			}
+		}
+
+		if e.translateKeywords(schema) {
+			nullables = append(nullables, schema)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// nullable schemas without a type (e.g. a $ref) are wrapped once all subschemas are translated,
+	// so they are not visited twice
+	for _, schema := range nullables {
+		wrapped := make(map[string]interface{}, len(schema))
+		for keyword, value := range schema {
+			wrapped[keyword] = value
+			delete(schema, keyword)
+		}
+		schema["anyOf"] = []interface{}{wrapped, map[string]interface{}{"type": "null"}}
+	}
+
+	return nil
+}
+
+// walkJSONSchema visits a schema decoded as generic JSON, then its subschemas. Subschemas are found
+// after the visit of their parent, so the visitor may rename keywords (e.g. "items" to "prefixItems").
+func walkJSONSchema(node interface{}, pointer string, visit func(schema map[string]interface{}, pointer string) error) error {
+	schema, isMap := node.(map[string]interface{})
+	if !isMap {
+		return nil // e.g. additionalProperties: true
+	}
+
+	if err := visit(schema, pointer); err != nil {
+		return err
+	}
+
+	for _, keyword := range []string{"properties", "patternProperties", "definitions", "$defs"} {
+		children, _ := schema[keyword].(map[string]interface{})
+		for _, key := range sortedKeys(children) {
+			if err := walkJSONSchema(children[key], pointer+"/"+keyword+"/"+jsonpointer.Escape(key), visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf", "prefixItems", "items"} {
+		children, _ := schema[keyword].([]interface{})
+		for i, child := range children {
+			if err := walkJSONSchema(child, fmt.Sprintf("%s/%s/%d", pointer, keyword, i), visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, keyword := range []string{"not", "items", "additionalItems", "additionalProperties"} {
+		if err := walkJSONSchema(schema[keyword], pointer+"/"+keyword, visit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rewriteRef rewrites a $ref to a definition, to refer to the bundled definition. It yields the name of this definition.
+func (e *jsonSchemaExporter) rewriteRef(pointer, ref string) (string, string, error) {
+	name, isDefinition := definitionOfPointer(ref)
+	if !isDefinition {
+		return "", "", &RefError{Pointer: pointer, Ref: ref, Cause: errors.New("only $ref's to local definitions may be exported")}
+	}
+
+	if _, found := e.sp.Definitions[name]; !found {
+		return "", "", &RefError{Pointer: pointer, Ref: ref, Cause: fmt.Errorf("no definition %q", name)}
+	}
+
+	rest := strings.TrimPrefix(strings.TrimPrefix(ref, definitionsPrefix), jsonpointer.Escape(name))
+	if name == e.root {
+		return "#" + rest, "", nil
+	}
+
+	return "#/" + jsonSchemaDefinitions[e.draft] + "/" + jsonpointer.Escape(name) + rest, name, nil
+}
+
+// translateKeywords translates the swagger 2.0 keywords of a schema. It reports nullable schemas without a type,
+// which must be wrapped to allow null.
+func (e *jsonSchemaExporter) translateKeywords(schema map[string]interface{}) bool {
+	delete(schema, "discriminator")
+
+	if tpe, isFile := schema["type"].(string); isFile && tpe == "file" {
+		schema["type"] = "string"
+		schema["format"] = "binary"
+	}
+
+	var untyped bool
+	if nullable, _ := schema["x-nullable"].(bool); nullable {
+		delete(schema, "x-nullable")
+		switch tpe := schema["type"].(type) {
+		case string:
+			schema["type"] = []interface{}{tpe, "null"}
+		case []interface{}:
+			schema["type"] = append(tpe, "null")
+		default:
+			untyped = true
+		}
+	}
+
+	if e.draft != JSONSchemaDraft202012 {
+		return untyped
+	}
+
+	if example, hasExample := schema["example"]; hasExample {
+		delete(schema, "example")
+		schema["examples"] = []interface{}{example}
+	}
+
+	for exclusive, bound := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		isExclusive, isBool := schema[exclusive].(bool)
+		if !isBool {
+			continue
+		}
+
+		delete(schema, exclusive)
+		if value, hasBound := schema[bound]; hasBound && isExclusive {
+			delete(schema, bound)
+			schema[exclusive] = value
+		}
+	}
+
+	if tuple, isTuple := schema["items"].([]interface{}); isTuple {
+		schema["prefixItems"] = tuple
+		delete(schema, "items")
+		if additional, hasAdditional := schema["additionalItems"]; hasAdditional {
+			delete(schema, "additionalItems")
+			schema["items"] = additional
+		}
+	}
+
+	return untyped
+}
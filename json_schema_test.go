@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "json_schema.yml"))
+
+	t.Run("with draft 2020-12", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := ExportJSONSchema(sp, "pet", JSONSchemaDraft202012)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["kind", "name"],
+  "properties": {
+    "kind": {"type": "string"},
+    "name": {"type": "string", "examples": ["Rex"]},
+    "age": {"type": ["integer", "null"], "exclusiveMinimum": 0},
+    "photo": {"type": "string", "format": "binary"},
+    "owner": {"$ref": "#/$defs/owner"},
+    "coordinates": {"type": "array", "prefixItems": [{"type": "number"}, {"type": "number"}], "items": false}
+  },
+  "$defs": {
+    "owner": {
+      "type": "object",
+      "properties": {
+        "pets": {"type": "array", "items": {"$ref": "#"}},
+        "address": {"$ref": "#/$defs/address"}
+      }
+    },
+    "address": {"type": "string"}
+  }
+}`, antest.AsJSON(t, doc))
+	})
+
+	t.Run("with draft-04", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := ExportJSONSchema(sp, "owner", JSONSchemaDraft04)
+		require.NoError(t, err)
+
+		assert.Equal(t, "http://json-schema.org/draft-04/schema#", doc["$schema"])
+		definitions, ok := doc["definitions"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, []string{"address", "pet"}, sortedKeys(definitions))
+
+		pet, ok := definitions["pet"].(map[string]interface{})
+		require.True(t, ok)
+		assert.JSONEq(t, `{
+  "type": "object",
+  "required": ["kind", "name"],
+  "properties": {
+    "kind": {"type": "string"},
+    "name": {"type": "string", "example": "Rex"},
+    "age": {"type": ["integer", "null"], "minimum": 0, "exclusiveMinimum": true},
+    "photo": {"type": "string", "format": "binary"},
+    "owner": {"$ref": "#"},
+    "coordinates": {"type": "array", "items": [{"type": "number"}, {"type": "number"}], "additionalItems": false}
+  }
+}`, antest.AsJSON(t, pet))
+	})
+
+	t.Run("with nullable $ref", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := ExportJSONSchema(sp, "adoption", JSONSchemaDraft202012)
+		require.NoError(t, err)
+
+		properties, ok := doc["properties"].(map[string]interface{})
+		require.True(t, ok)
+		assert.JSONEq(t, `{"anyOf": [{"$ref": "#/$defs/owner"}, {"type": "null"}]}`, antest.AsJSON(t, properties["adopter"]))
+
+		defs, ok := doc["$defs"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, []string{"address", "owner", "pet"}, sortedKeys(defs))
+	})
+
+	t.Run("with errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ExportJSONSchema(sp, "pet", "draft-07")
+		require.Error(t, err)
+
+		_, err = ExportJSONSchema(sp, "missing", JSONSchemaDraft04)
+		require.Error(t, err)
+
+		_, err = ExportJSONSchema(sp, "remote", JSONSchemaDraft04)
+		var refErr *RefError
+		require.ErrorAs(t, err, &refErr)
+		assert.Equal(t, "#/definitions/remote", refErr.Pointer)
+	})
+}
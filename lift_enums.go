@@ -0,0 +1,107 @@
+package analysis
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// NamingFunc names the definition created from the schema at some location (e.g. "#/definitions/pet/properties/kind")
+type NamingFunc func(pointer string, schema *spec.Schema) string
+
+// LiftedEnum describes an inline enum which has been lifted into a definition
+type LiftedEnum struct {
+	Pointer    string // the location of the inline enum, now a $ref (e.g. "#/definitions/pet/properties/kind")
+	Definition string // the JSON pointer to the definition (e.g. "#/definitions/petKind")
+}
+
+// keywords of the JSON pointers to schemas, which do not contribute to the names of lifted enums
+var enumNameSkippedTokens = map[string]bool{
+	"definitions": true, "properties": true, "items": true, "schema": true,
+	"paths": true, "parameters": true, "responses": true,
+}
+
+// DefaultEnumName names an enum lifted by LiftEnums after its location, e.g. "petKind" for
+// "#/definitions/pet/properties/kind", or "petTagsItems" for "#/definitions/pet/properties/tags/items"
+func DefaultEnumName(pointer string, _ *spec.Schema) string {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "#/"), "/")
+
+	parts := make([]string, 0, len(tokens))
+	for i, token := range tokens {
+		if enumNameSkippedTokens[token] && (token != "items" || i < len(tokens)-1) {
+			continue
+		}
+		parts = append(parts, jsonpointer.Unescape(token))
+	}
+
+	return swag.ToJSONName(strings.Join(parts, " "))
+}
+
+// LiftEnums extracts the enums declared inline by properties (or by the items of array properties)
+// into definitions, and replaces them with $ref's to these definitions. Code generators produce named
+// types from definitions, which they do not for inline enums.
+//
+// Definitions are named by the naming function, or by DefaultEnumName when it is nil. Identical enums
+// with the same name share a definition; a numeric suffix disambiguates the names of different enums.
+// Only the enums are lifted: other inline schemas are left unchanged (see Flatten to lift them all).
+//
+// The spec is modified in place. Lifted enums are sorted by pointer.
+func LiftEnums(sp *spec.Swagger, naming NamingFunc) []LiftedEnum {
+	if sp == nil {
+		return nil
+	}
+
+	if naming == nil {
+		naming = DefaultEnumName
+	}
+
+	var lifted []LiftedEnum
+	walkSchemas(sp, func(pointer string, schema *spec.Schema) {
+		if len(schema.Enum) == 0 || schema.Ref.String() != "" || !isPropertyLevel(pointer) {
+			return
+		}
+
+		name := liftedEnumName(sp, naming(pointer, schema), schema)
+		if sp.Definitions == nil {
+			sp.Definitions = make(spec.Definitions)
+		}
+		sp.Definitions[name] = *schema
+
+		definition := definitionsPrefix + jsonpointer.Escape(name)
+		*schema = *spec.RefSchema(definition)
+		lifted = append(lifted, LiftedEnum{Pointer: pointer, Definition: definition})
+	})
+
+	sort.Slice(lifted, func(i, j int) bool { return lifted[i].Pointer < lifted[j].Pointer })
+
+	return lifted
+}
+
+// isPropertyLevel tells if a schema is a property, or the items of a property
+func isPropertyLevel(pointer string) bool {
+	tokens := strings.Split(pointer, "/")
+	if n := len(tokens); n > 2 && tokens[n-1] == "items" {
+		tokens = tokens[:n-1]
+	}
+
+	return len(tokens) > 2 && tokens[len(tokens)-2] == "properties"
+}
+
+// liftedEnumName yields a name for the definition of an enum: the proposed name when it is free, or already names
+// an identical enum, and the proposed name with a numeric suffix otherwise
+func liftedEnumName(sp *spec.Swagger, proposed string, schema *spec.Schema) string {
+	name := proposed
+	for i := 2; ; i++ {
+		existing, exists := sp.Definitions[name]
+		if !exists || reflect.DeepEqual(schemaAsJSON(&existing), schemaAsJSON(schema)) {
+			return name
+		}
+
+		name = proposed + strconv.Itoa(i)
+	}
+}
@@ -0,0 +1,80 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiftEnums(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "lift_enums.yml")
+
+	t.Run("should lift inline enums with default names", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		lifted := LiftEnums(sp, nil)
+
+		assert.Equal(t, []LiftedEnum{
+			{Pointer: "#/definitions/owner/properties/address/properties/country", Definition: "#/definitions/ownerAddressCountry"},
+			{Pointer: "#/definitions/owner/properties/level", Definition: "#/definitions/ownerLevel"},
+			{Pointer: "#/definitions/pet/properties/kind", Definition: "#/definitions/petKind2"},
+			{Pointer: "#/definitions/pet/properties/tags/items", Definition: "#/definitions/petTagsItems"},
+			{Pointer: "#/paths/~1pets/get/responses/200/schema/properties/status", Definition: "#/definitions/petsGet200Status"},
+		}, lifted)
+
+		pet := sp.Definitions["pet"]
+		kind := pet.Properties["kind"]
+		assert.Equal(t, "#/definitions/petKind2", kind.Ref.String())
+		assert.Equal(t, []interface{}{"cat", "dog"}, sp.Definitions["petKind2"].Enum)
+		assert.Equal(t, spec.StringOrArray{"integer"}, sp.Definitions["petKind"].Type) // left unchanged
+		assert.Equal(t, "#/definitions/petTagsItems", pet.Properties["tags"].Items.Schema.Ref.String())
+		assert.Equal(t, "int32", sp.Definitions["ownerLevel"].Format)
+
+		// top-level enums are already named
+		assert.Equal(t, []interface{}{"reptile"}, sp.Definitions["kind"].Enum)
+
+		for _, enum := range lifted {
+			assert.Contains(t, New(sp).AllDefinitionReferences(), enum.Definition)
+		}
+	})
+
+	t.Run("should share identical enums", func(t *testing.T) {
+		t.Parallel()
+
+		sp := antest.LoadOrFail(t, bp)
+		lifted := LiftEnums(sp, func(_ string, schema *spec.Schema) string {
+			if schema.Type.Contains("string") {
+				return "stringEnum"
+			}
+
+			return "otherEnum"
+		})
+		require.Len(t, lifted, 5)
+
+		// all string enums differ: they get distinct names
+		var names []string
+		for _, enum := range lifted {
+			names = append(names, enum.Definition)
+		}
+		assert.ElementsMatch(t, []string{
+			"#/definitions/stringEnum", "#/definitions/stringEnum2", "#/definitions/stringEnum3", "#/definitions/stringEnum4",
+			"#/definitions/otherEnum",
+		}, names)
+
+		// lifting an identical enum again reuses its definition
+		sp.Definitions["copy"] = spec.Schema{SchemaProps: spec.SchemaProps{
+			Type:       spec.StringOrArray{"object"},
+			Properties: spec.SchemaProperties{"level": sp.Definitions["otherEnum"]},
+		}}
+		assert.Equal(t, []LiftedEnum{
+			{Pointer: "#/definitions/copy/properties/level", Definition: "#/definitions/otherEnum"},
+		}, LiftEnums(sp, func(string, *spec.Schema) string { return "otherEnum" }))
+	})
+}
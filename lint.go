@@ -0,0 +1,253 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	slashpath "path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// Codes for findings reported by the built-in lint rules
+const (
+	CodeUnusedDefinition  = "unused-definition"
+	CodeDeepInlineSchema  = "deep-inline-schema"
+	CodeMissingTags       = "missing-tags"
+	CodeBodyWithoutSchema = "body-without-schema"
+)
+
+// DefaultMaxInlineDepth is the nesting level beyond which inline schemas are reported by the built-in rules
+const DefaultMaxInlineDepth = 2
+
+// Severity is the severity of a lint finding
+type Severity string
+
+// Severities of lint findings
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// LintRule is a heuristic check of a spec, run by Lint
+type LintRule struct {
+	Name     string
+	Severity Severity // the severity of the findings reported by this rule
+	Check    func(*Spec) []Finding
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// LintFinding is a finding reported by a lint rule
+type LintFinding struct {
+	Finding
+
+	Rule     string
+	Severity Severity
+}
+
+// registered lint rules
+var lintRules = struct {
+	sync.RWMutex
+	byName map[string]LintRule
+}{byName: make(map[string]LintRule)}
+
+func init() {
+	for _, rule := range BuiltinLintRules() {
+		lintRules.byName[rule.Name] = rule
+	}
+}
+
+// BuiltinLintRules returns the lint rules provided by this package:
+//   - unused-definition: definitions which cannot be reached from the paths of the spec
+//   - duplicate-operation-id: operations sharing their operationId with some other operation
+//   - deep-inline-schema: inline objects and compositions nested deeper than DefaultMaxInlineDepth
+//   - missing-tags: operations without tags
+//   - body-without-schema: body parameters without a schema
+//   - ignored-header-parameter: header parameters ignored by swagger 2.0 (e.g. "Content-Type")
+func BuiltinLintRules() []LintRule {
+	return []LintRule{
+		{Name: CodeUnusedDefinition, Severity: SeverityWarning, Check: (*Spec).unusedDefinitions},
+		{Name: CodeDuplicateOperationID, Severity: SeverityError, Check: (*Spec).duplicateOperationIDs},
+		DeepInlineSchemasRule(DefaultMaxInlineDepth),
+		{Name: CodeMissingTags, Severity: SeverityInfo, Check: (*Spec).missingTags},
+		{Name: CodeBodyWithoutSchema, Severity: SeverityError, Check: (*Spec).bodiesWithoutSchema},
+		{Name: CodeIgnoredHeaderParameter, Severity: SeverityWarning, Check: (*Spec).HeaderConflicts},
+	}
+}
+
+// DeepInlineSchemasRule builds a rule reporting inline objects and compositions of schemas (i.e. which are not
+// $ref's to definitions) nested more than maxDepth levels below the root of a definition, parameter or response.
+func DeepInlineSchemasRule(maxDepth int) LintRule {
+	return LintRule{
+		Name:     CodeDeepInlineSchema,
+		Severity: SeverityWarning,
+		Check: func(s *Spec) []Finding {
+			return s.deepInlineSchemas(maxDepth)
+		},
+	}
+}
+
+// RegisterLintRule registers a custom rule, run by Lint when no rules are specified.
+//
+// Registering a rule with an empty name, without a check, or with the name of another rule is an error.
+func RegisterLintRule(rule LintRule) error {
+	if rule.Name == "" || rule.Check == nil {
+		return errors.New("a lint rule requires a name and a check")
+	}
+
+	lintRules.Lock()
+	defer lintRules.Unlock()
+
+	if _, exists := lintRules.byName[rule.Name]; exists {
+		return fmt.Errorf("lint rule %q is already registered", rule.Name)
+	}
+
+	lintRules.byName[rule.Name] = rule
+
+	return nil
+}
+
+// UnregisterLintRule removes a registered rule, including built-in rules.
+// This is a no-op if no such rule is registered.
+func UnregisterLintRule(name string) {
+	lintRules.Lock()
+	defer lintRules.Unlock()
+
+	delete(lintRules.byName, name)
+}
+
+// RegisteredLintRules returns all registered rules, sorted by name
+func RegisteredLintRules() []LintRule {
+	lintRules.RLock()
+	defer lintRules.RUnlock()
+
+	rules := make([]LintRule, 0, len(lintRules.byName))
+	for _, name := range sortedKeys(lintRules.byName) {
+		rules = append(rules, lintRules.byName[name])
+	}
+
+	return rules
+}
+
+// Lint runs rules against a spec, and returns their findings sorted by pointer.
+//
+// All registered rules (see RegisterLintRule) are run when no rules are specified.
+func Lint(s *Spec, rules ...LintRule) []LintFinding {
+	if len(rules) == 0 {
+		rules = RegisteredLintRules()
+	}
+
+	var findings []LintFinding
+	for _, rule := range rules {
+		ruleFindings := rule.Check(s)
+		sortFindings(ruleFindings)
+
+		for _, finding := range ruleFindings {
+			findings = append(findings, LintFinding{Finding: finding, Rule: rule.Name, Severity: rule.Severity})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Pointer < findings[j].Pointer
+	})
+
+	return findings
+}
+
+func (s *Spec) unusedDefinitions() []Finding {
+	reachable := reachableComponents(s.spec)
+
+	var findings []Finding
+	for _, name := range sortedKeys(s.spec.Definitions) {
+		pointer := definitionsPrefix + jsonpointer.Escape(name)
+		if reachable[pointer] {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Pointer: pointer,
+			Code:    CodeUnusedDefinition,
+			Message: fmt.Sprintf("definition %q is not used by any operation", name),
+			Fix:     []PatchOperation{{Op: "remove", Path: patchPath(pointer)}},
+		})
+	}
+
+	return findings
+}
+
+func (s *Spec) duplicateOperationIDs() []Finding {
+	var findings []Finding
+	for _, finding := range s.OperationIDIssues() {
+		if finding.Code == CodeDuplicateOperationID {
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+func (s *Spec) deepInlineSchemas(maxDepth int) []Finding {
+	var findings []Finding
+	var visit func(pointer string, schema *spec.Schema, depth int)
+	visit = func(pointer string, schema *spec.Schema, depth int) {
+		if depth > maxDepth && isInlineComplexSchema(schema) {
+			findings = append(findings, Finding{
+				Pointer: pointer,
+				Code:    CodeDeepInlineSchema,
+				Message: fmt.Sprintf("inline schema nested %d levels deep: consider declaring it as a definition", depth),
+			})
+		}
+
+		forEachSubSchema(schema, func(suffix string, child *spec.Schema) {
+			visit(slashpath.Join(pointer, suffix), child, depth+1)
+		})
+	}
+
+	root := ""
+	walkSchemas(s.spec, func(pointer string, schema *spec.Schema) {
+		if root != "" && strings.HasPrefix(pointer, root+"/") { // children are visited with their root
+			return
+		}
+		root = pointer
+
+		visit(pointer, schema, 0)
+	})
+
+	return findings
+}
+
+func (s *Spec) missingTags() []Finding {
+	var findings []Finding
+	walkOperations(s.spec, func(pointer string, op *spec.Operation) {
+		if len(op.Tags) == 0 {
+			findings = append(findings, Finding{
+				Pointer: "#" + pointer,
+				Code:    CodeMissingTags,
+				Message: "operation has no tags",
+			})
+		}
+	})
+
+	return findings
+}
+
+func (s *Spec) bodiesWithoutSchema() []Finding {
+	var findings []Finding
+	walkParameters(s.spec, func(pointer string, param *spec.Parameter) {
+		if param.In == "body" && param.Schema == nil {
+			findings = append(findings, Finding{
+				Pointer: pointer,
+				Code:    CodeBodyWithoutSchema,
+				Message: fmt.Sprintf("body parameter %q has no schema", param.Name),
+			})
+		}
+	})
+
+	return findings
+}
@@ -0,0 +1,96 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "lint", "spec.yml")))
+
+	type result struct {
+		Pointer  string
+		Rule     string
+		Severity Severity
+	}
+
+	t.Run("with built-in rules", func(t *testing.T) {
+		t.Parallel()
+
+		findings := Lint(an, BuiltinLintRules()...)
+
+		actual := make([]result, 0, len(findings))
+		for _, finding := range findings {
+			assert.Equal(t, finding.Rule, finding.Code)
+			assert.NotEmpty(t, finding.Message)
+			actual = append(actual, result{Pointer: finding.Pointer, Rule: finding.Rule, Severity: finding.Severity})
+		}
+
+		assert.Equal(t, []result{
+			{Pointer: "#/definitions/unused", Rule: CodeUnusedDefinition, Severity: SeverityWarning},
+			{Pointer: "#/paths/~1pets/get", Rule: CodeDuplicateOperationID, Severity: SeverityError},
+			{Pointer: "#/paths/~1pets/post", Rule: CodeDuplicateOperationID, Severity: SeverityError},
+			{Pointer: "#/paths/~1pets/post", Rule: CodeMissingTags, Severity: SeverityInfo},
+			{Pointer: "#/paths/~1pets/post/parameters/0", Rule: CodeBodyWithoutSchema, Severity: SeverityError},
+			{Pointer: "#/paths/~1pets/post/responses/201/schema/properties/links/properties/self/properties/meta", Rule: CodeDeepInlineSchema, Severity: SeverityWarning},
+		}, actual)
+
+		assert.Equal(t, []PatchOperation{{Op: "remove", Path: "/definitions/unused"}}, findings[0].Fix)
+	})
+
+	t.Run("with configured rules", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, Lint(an, DeepInlineSchemasRule(3)))
+		assert.Len(t, Lint(an, DeepInlineSchemasRule(1)), 2)
+	})
+}
+
+func TestLint_Registry(t *testing.T) {
+	// not parallel: alters the registry of rules
+
+	rule := LintRule{
+		Name:     "no-post",
+		Severity: SeverityInfo,
+		Check: func(s *Spec) []Finding {
+			if _, ok := s.OperationFor("POST", "/pets"); ok {
+				return []Finding{{Pointer: "#/paths/~1pets/post", Code: "no-post", Message: "POST is not allowed"}}
+			}
+
+			return nil
+		},
+	}
+
+	require.NoError(t, RegisterLintRule(rule))
+	defer UnregisterLintRule(rule.Name)
+
+	require.Error(t, RegisterLintRule(rule))
+	require.Error(t, RegisterLintRule(LintRule{Name: "no check"}))
+
+	names := make([]string, 0)
+	for _, registered := range RegisteredLintRules() {
+		names = append(names, registered.Name)
+	}
+	assert.Equal(t, []string{
+		CodeBodyWithoutSchema, CodeDeepInlineSchema, CodeDuplicateOperationID, CodeIgnoredHeaderParameter, CodeMissingTags, "no-post", CodeUnusedDefinition,
+	}, names)
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "lint", "spec.yml")))
+	findings := Lint(an)
+	assert.Len(t, findings, 7)
+
+	var custom []LintFinding
+	for _, finding := range findings {
+		if finding.Rule == "no-post" {
+			custom = append(custom, finding)
+		}
+	}
+	require.Len(t, custom, 1)
+	assert.Equal(t, SeverityInfo, custom[0].Severity)
+}
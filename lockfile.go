@@ -0,0 +1,138 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Lockfile pins the content of the remote documents referenced by a spec, as a hash for each document URL.
+//
+// Like for snapshots, remote documents are those fetched from a URL other than a local file.
+//
+// In verification mode, resolving a remote document which is absent from the lockfile, or whose content
+// no longer matches the locked hash, fails. Use this to detect upstream changes to the documents a spec depends on.
+type Lockfile struct {
+	// Documents maps the URL of each resolved remote document to the hash of its content
+	Documents map[string]string `json:"documents"`
+
+	// Verify checks resolved remote documents against the lockfile, instead of recording them
+	Verify bool `json:"-"`
+
+	mx         sync.Mutex
+	mismatches []*LockMismatchError
+}
+
+// LockMismatchError reports a remote document which does not match a lockfile
+type LockMismatchError struct {
+	URL    string
+	Locked string // the hash recorded in the lockfile, empty when the document is not locked
+	Actual string // the hash of the resolved document
+}
+
+func (e *LockMismatchError) Error() string {
+	if e.Locked == "" {
+		return fmt.Sprintf("document %s is not in the lockfile", e.URL)
+	}
+
+	return fmt.Sprintf("document %s does not match the lockfile: expected %s, got %s", e.URL, e.Locked, e.Actual)
+}
+
+// NewLockfile builds a lockfile recording remote documents
+func NewLockfile() *Lockfile {
+	return &Lockfile{Documents: make(map[string]string)}
+}
+
+// ReadLockfile reads a lockfile, as written by Write, and prepares it for verification
+func ReadLockfile(r io.Reader) (*Lockfile, error) {
+	l := &Lockfile{Verify: true}
+	if err := json.NewDecoder(r).Decode(l); err != nil {
+		return nil, err
+	}
+
+	if l.Documents == nil {
+		l.Documents = make(map[string]string)
+	}
+
+	return l, nil
+}
+
+// Lockfile builds a lockfile pinning the documents recorded in a snapshot
+func (s *Snapshot) Lockfile() *Lockfile {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	l := NewLockfile()
+	for u, hash := range s.Documents {
+		l.Documents[u] = hash
+	}
+
+	return l
+}
+
+// Write writes the lockfile as JSON
+func (l *Lockfile) Write(w io.Writer) error {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(l)
+}
+
+// Mismatches lists the remote documents which failed the verification so far, sorted by URL
+func (l *Lockfile) Mismatches() []*LockMismatchError {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	mismatches := append([]*LockMismatchError(nil), l.mismatches...)
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].URL < mismatches[j].URL })
+
+	return mismatches
+}
+
+// verified returns the first mismatch found, if any
+func (l *Lockfile) verified() error {
+	if mismatches := l.Mismatches(); len(mismatches) > 0 {
+		return mismatches[0]
+	}
+
+	return nil
+}
+
+// pathLoader wraps a loader of documents to record or verify remote documents
+func (l *Lockfile) pathLoader(next func(string) (json.RawMessage, error)) func(string) (json.RawMessage, error) {
+	return func(pth string) (json.RawMessage, error) {
+		data, err := next(pth)
+		if err != nil || !isRemoteDocument(pth) {
+			return data, err
+		}
+
+		hash := contentHash(data)
+
+		l.mx.Lock()
+		defer l.mx.Unlock()
+
+		if l.Documents == nil {
+			l.Documents = make(map[string]string)
+		}
+
+		if !l.Verify {
+			l.Documents[pth] = hash
+
+			return data, nil
+		}
+
+		if locked := l.Documents[pth]; locked != hash {
+			mismatch := &LockMismatchError{URL: pth, Locked: locked, Actual: hash}
+			l.mismatches = append(l.mismatches, mismatch)
+
+			return nil, mismatch
+		}
+
+		return data, nil
+	}
+}
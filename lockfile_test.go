@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockfile_RecordAndVerify(t *testing.T) {
+	t.Parallel()
+
+	var changed atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/pet.json" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		if changed.Load() {
+			_, _ = w.Write([]byte(`{"definitions": {"pet": {"type": "object", "properties": {"id": {"type": "integer"}}}}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"definitions": {"pet": {"type": "object", "properties": {"name": {"type": "string"}}}}}`))
+	}))
+	defer server.Close()
+
+	docURL := server.URL + "/models/pet.json"
+	newSpec := func(ref string) *spec.Swagger {
+		return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			Definitions: spec.Definitions{
+				"pets": *spec.ArrayProperty(spec.RefSchema(ref)),
+			},
+		}}
+	}
+
+	lock := NewLockfile()
+	require.NoError(t, Flatten(FlattenOpts{Spec: New(newSpec(docURL + "#/definitions/pet")), Minimal: true, Lockfile: lock}))
+	require.Len(t, lock.Documents, 1)
+	require.Contains(t, lock.Documents, docURL)
+
+	var buf bytes.Buffer
+	require.NoError(t, lock.Write(&buf))
+
+	t.Run("should verify unchanged documents", func(t *testing.T) {
+		verify, err := ReadLockfile(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		assert.True(t, verify.Verify)
+
+		require.NoError(t, Flatten(FlattenOpts{Spec: New(newSpec(docURL + "#/definitions/pet")), Minimal: true, Lockfile: verify}))
+		assert.Empty(t, verify.Mismatches())
+	})
+
+	t.Run("should reject documents missing from the lockfile", func(t *testing.T) {
+		verify, err := ReadLockfile(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+
+		err = Flatten(FlattenOpts{Spec: New(newSpec(server.URL + "/other.json#/definitions/pet")), Minimal: true, Lockfile: verify})
+		require.Error(t, err)
+	})
+
+	t.Run("should reject changed documents", func(t *testing.T) {
+		changed.Store(true)
+		t.Cleanup(func() { changed.Store(false) })
+
+		verify, err := ReadLockfile(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+
+		err = Flatten(FlattenOpts{Spec: New(newSpec(docURL + "#/definitions/pet")), Minimal: true, Lockfile: verify, ContinueOnError: true})
+		require.Error(t, err)
+
+		var mismatch *LockMismatchError
+		require.True(t, errors.As(err, &mismatch))
+		assert.Equal(t, docURL, mismatch.URL)
+		assert.Equal(t, lock.Documents[docURL], mismatch.Locked)
+		assert.NotEqual(t, mismatch.Locked, mismatch.Actual)
+		assert.Len(t, verify.Mismatches(), 1)
+	})
+}
+
+func TestLockfile_FromSnapshot(t *testing.T) {
+	t.Parallel()
+
+	snapshot := NewSnapshot(&MemorySnapshotStore{})
+	snapshot.Documents["https://example.com/pet.json"] = "sha256:abc"
+
+	lock := snapshot.Lockfile()
+	assert.Equal(t, map[string]string{"https://example.com/pet.json": "sha256:abc"}, lock.Documents)
+	assert.False(t, lock.Verify)
+
+	_, err := ReadLockfile(bytes.NewReader([]byte("not json")))
+	require.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package analysis
+
+import (
+	"os"
+	"sync"
+
+	"github.com/go-openapi/analysis/internal/debug"
+)
+
+// Logger traces the decisions made while analyzing a spec, such as $ref's being resolved,
+// definitions being renamed or entries being skipped.
+//
+// It is satisfied by *log.Logger.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// defaultLogger holds the Logger used when none is specified by the options of a call
+var defaultLogger struct {
+	sync.RWMutex
+	logger Logger
+}
+
+// traceDebugLog is the debug logger used by tracing helpers, reporting the location of their caller
+var traceDebugLog = debug.GetLoggerWithDepth("analysis", os.Getenv("SWAGGER_DEBUG") != "", 3)
+
+// SetLogger sets the Logger used by Flatten, Schema and Mixin, when none is specified in their options.
+//
+// Tracing is disabled with a nil Logger (the default).
+func SetLogger(logger Logger) {
+	defaultLogger.Lock()
+	defer defaultLogger.Unlock()
+
+	defaultLogger.logger = logger
+}
+
+// resolveLogger yields the Logger to use, or the default one when none is specified
+func resolveLogger(logger Logger) Logger {
+	if logger != nil {
+		return logger
+	}
+
+	defaultLogger.RLock()
+	defer defaultLogger.RUnlock()
+
+	return defaultLogger.logger
+}
+
+// tracef traces a message with some Logger, and with the debug logger (enabled with SWAGGER_DEBUG)
+func tracef(logger Logger, format string, args ...interface{}) {
+	traceDebugLog(format, args...)
+
+	if logger = resolveLogger(logger); logger != nil {
+		logger.Printf(format, args...)
+	}
+}
@@ -0,0 +1,90 @@
+package analysis
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	mx       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, args ...interface{}) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) contains(substr string) bool {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	for _, msg := range l.messages {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestLogger_Flatten(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "oaigen", "fixture-oaigen.yaml")
+	sp := antest.LoadOrFail(t, bp)
+	logger := &recordingLogger{}
+
+	require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), BasePath: bp, Logger: logger}))
+
+	assert.True(t, logger.contains("resolving schema from remote $ref"))
+	assert.True(t, logger.contains("with name conflict:true"))
+	assert.True(t, logger.contains("naming inlined schema at"))
+}
+
+func TestLogger_Schema(t *testing.T) {
+	t.Parallel()
+
+	sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{
+		"name": *spec.StringProperty().WithMaxLength(10),
+	}}}
+	logger := &recordingLogger{}
+
+	_, err := Schema(SchemaOpts{Schema: spec.ArrayProperty(spec.RefSchema("#/definitions/name")), Root: sp, Logger: logger})
+	require.NoError(t, err)
+
+	assert.True(t, logger.contains("resolving schema $ref #/definitions/name"))
+}
+
+func TestLogger_SetLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+
+	primary := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Definitions: spec.Definitions{"pet": *spec.StringProperty()},
+	}}
+	mixin := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Definitions: spec.Definitions{"pet": *spec.Int64Property()},
+	}}
+
+	require.Len(t, Mixin(primary, mixin), 1)
+	assert.True(t, logger.contains("mixin 0: skipped definitions entry at #/definitions/pet"))
+
+	// options take precedence over the default Logger
+	other := &recordingLogger{}
+	_, err := Schema(SchemaOpts{Schema: spec.RefSchema("#/definitions/pet"), Root: primary, Logger: other})
+	require.NoError(t, err)
+	assert.True(t, other.contains("resolving schema $ref #/definitions/pet"))
+	assert.False(t, logger.contains("resolving schema $ref"))
+}
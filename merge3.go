@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// MergeConflict describes a location where both sides of a three-way merge changed the base differently
+type MergeConflict struct {
+	Pointer string // the JSON pointer to the conflicting location, e.g. "#/paths/~1pets/get/summary"
+
+	// Values at this location in each document, as generic JSON values.
+	// Values are nil when absent from a document: use the Has* flags to tell an absent value from a null one.
+	Base   interface{}
+	Ours   interface{}
+	Theirs interface{}
+
+	HasBase   bool
+	HasOurs   bool
+	HasTheirs bool
+}
+
+// Merge3 performs a structural three-way merge of specs: changes from base to ours and from base to theirs
+// are combined into a new spec. None of the inputs is modified.
+//
+// Objects are merged key by key, recursively. Arrays and scalar values are merged as a whole.
+// When both sides changed the same location differently, the change from ours is retained and a conflict is reported.
+//
+// Conflicts are sorted by pointer.
+func Merge3(base, ours, theirs *spec.Swagger) (*spec.Swagger, []MergeConflict, error) {
+	docs := make([]interface{}, 0, 3)
+	for _, sp := range []*spec.Swagger{base, ours, theirs} {
+		doc, err := toGenericJSON(sp)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		docs = append(docs, doc)
+	}
+
+	var conflicts []MergeConflict
+	merged, _ := merge3Value("#", docs[0], true, docs[1], true, docs[2], true, &conflicts)
+
+	buf, err := json.Marshal(merged)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result spec.Swagger
+	if err := json.Unmarshal(buf, &result); err != nil {
+		return nil, nil, err
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Pointer < conflicts[j].Pointer })
+
+	return &result, conflicts, nil
+}
+
+// merge3Value merges a value present or absent from each of the three documents
+func merge3Value(pointer string, base interface{}, hasBase bool, ours interface{}, hasOurs bool, theirs interface{}, hasTheirs bool, conflicts *[]MergeConflict) (interface{}, bool) {
+	sameAs := func(a interface{}, hasA bool, b interface{}, hasB bool) bool {
+		return hasA == hasB && (!hasA || reflect.DeepEqual(a, b))
+	}
+
+	switch {
+	case sameAs(ours, hasOurs, theirs, hasTheirs):
+		return ours, hasOurs
+	case sameAs(base, hasBase, ours, hasOurs):
+		return theirs, hasTheirs
+	case sameAs(base, hasBase, theirs, hasTheirs):
+		return ours, hasOurs
+	}
+
+	ourMap, ourIsMap := ours.(map[string]interface{})
+	theirMap, theirIsMap := theirs.(map[string]interface{})
+	if hasOurs && hasTheirs && ourIsMap && theirIsMap {
+		baseMap, _ := base.(map[string]interface{})
+
+		keys := make(map[string]struct{}, len(ourMap)+len(theirMap))
+		for _, m := range []map[string]interface{}{baseMap, ourMap, theirMap} {
+			for k := range m {
+				keys[k] = struct{}{}
+			}
+		}
+
+		result := make(map[string]interface{}, len(keys))
+		for _, k := range sortedKeys(keys) {
+			b, hasB := baseMap[k]
+			o, hasO := ourMap[k]
+			t, hasT := theirMap[k]
+
+			if v, present := merge3Value(pointer+"/"+jsonpointer.Escape(k), b, hasB, o, hasO, t, hasT, conflicts); present {
+				result[k] = v
+			}
+		}
+
+		return result, true
+	}
+
+	*conflicts = append(*conflicts, MergeConflict{
+		Pointer:   pointer,
+		Base:      base,
+		Ours:      ours,
+		Theirs:    theirs,
+		HasBase:   hasBase,
+		HasOurs:   hasOurs,
+		HasTheirs: hasTheirs,
+	})
+
+	return ours, hasOurs
+}
+
+// toGenericJSON converts a spec into generic JSON values (e.g. map[string]interface{})
+func toGenericJSON(sp *spec.Swagger) (interface{}, error) {
+	if sp == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	buf, err := json.Marshal(sp)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(buf, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
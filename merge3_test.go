@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge3(t *testing.T) {
+	t.Parallel()
+
+	base := antest.LoadOrFail(t, fooFile)
+	ours := antest.LoadOrFail(t, fooFile)
+	theirs := antest.LoadOrFail(t, fooFile)
+
+	// changes on both sides
+	ours.Info.Title = "our title"
+	theirs.Info.Title = "their title"
+	ours.BasePath = "/v2"
+	theirs.BasePath = "/v2"
+	delete(ours.Definitions, "common")
+	common := theirs.Definitions["common"]
+	common.Description = "changed by them"
+	theirs.Definitions["common"] = common
+
+	// changes on one side
+	ours.Definitions["ours"] = *spec.StringProperty()
+	theirs.Paths.Paths["/theirs"] = spec.PathItem{}
+	delete(theirs.Definitions, "fooId")
+	theirs.Info.Description = "their description"
+
+	pristine := antest.AsJSON(t, base)
+
+	merged, conflicts, err := Merge3(base, ours, theirs)
+	require.NoError(t, err)
+
+	require.JSONEq(t, pristine, antest.AsJSON(t, base))
+
+	assert.Equal(t, "/v2", merged.BasePath)
+	assert.Equal(t, "our title", merged.Info.Title)
+	assert.Equal(t, "their description", merged.Info.Description)
+	assert.Contains(t, merged.Definitions, "ours")
+	assert.NotContains(t, merged.Definitions, "fooId")
+	assert.NotContains(t, merged.Definitions, "common")
+	assert.Contains(t, merged.Definitions, "foo")
+	assert.Contains(t, merged.Paths.Paths, "/theirs")
+	assert.Contains(t, merged.Paths.Paths, "/foos")
+
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, "#/definitions/common", conflicts[0].Pointer)
+	assert.True(t, conflicts[0].HasBase)
+	assert.False(t, conflicts[0].HasOurs)
+	assert.True(t, conflicts[0].HasTheirs)
+	assert.Equal(t, MergeConflict{
+		Pointer:   "#/info/title",
+		Base:      base.Info.Title,
+		Ours:      "our title",
+		Theirs:    "their title",
+		HasBase:   true,
+		HasOurs:   true,
+		HasTheirs: true,
+	}, conflicts[1])
+}
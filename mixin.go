@@ -16,8 +16,10 @@ package analysis
 
 import (
 	"fmt"
+	slashpath "path"
 	"reflect"
 
+	"github.com/go-openapi/jsonpointer"
 	"github.com/go-openapi/spec"
 )
 
@@ -43,7 +45,8 @@ import (
 // The count of skipped entries (from collisions) is returned so any
 // deviation from the number expected can flag a warning in your build
 // scripts. Carefully review the collisions before accepting them;
-// consider renaming things if possible.
+// consider renaming things if possible. Use MixinReport to get these
+// collisions as structured data.
 //
 // No key normalization takes place (paths, type defs,
 // etc). Ensure they are canonical if your downstream tools do
@@ -52,36 +55,95 @@ import (
 // Merging schemes (http, https), and consumers/producers do not account for
 // collisions.
 func Mixin(primary *spec.Swagger, mixins ...*spec.Swagger) []string {
-	skipped := make([]string, 0, len(mixins))
-	opIds := getOpIds(primary)
-	initPrimary(primary)
+	conflicts := MixinReport(primary, mixins...)
+	skipped := make([]string, 0, len(conflicts))
 
-	for i, m := range mixins {
-		skipped = append(skipped, mergeSwaggerProps(primary, m)...)
-
-		skipped = append(skipped, mergeConsumes(primary, m)...)
+	for _, conflict := range conflicts {
+		if conflict.Action != MixinSkipped {
+			continue
+		}
 
-		skipped = append(skipped, mergeProduces(primary, m)...)
+		skipped = append(skipped, conflict.Message)
+	}
 
-		skipped = append(skipped, mergeTags(primary, m)...)
+	return skipped
+}
 
-		skipped = append(skipped, mergeSchemes(primary, m)...)
+// Mixed mixes specs like Mixin, into a copy of the primary spec, and returns this copy with the skipped entries.
+//
+// The primary and mixin specs are left unchanged, and the returned spec does not share anything with them.
+func Mixed(primary *spec.Swagger, mixins ...*spec.Swagger) (*spec.Swagger, []string) {
+	mixed := CloneSpec(primary)
+	clones := make([]*spec.Swagger, 0, len(mixins))
+	for _, mixin := range mixins {
+		clones = append(clones, CloneSpec(mixin))
+	}
 
-		skipped = append(skipped, mergeSecurityDefinitions(primary, m)...)
+	return mixed, Mixin(mixed, clones...)
+}
 
-		skipped = append(skipped, mergeSecurityRequirements(primary, m)...)
+// MixinAction is the action taken by Mixin upon a conflict
+type MixinAction string
+
+// Actions taken upon mixin conflicts
+const (
+	// MixinSkipped means the entry from the mixin was not merged
+	MixinSkipped MixinAction = "skipped"
+	// MixinRenamed means the entry from the mixin was merged, with a new name (e.g. operationIds)
+	MixinRenamed MixinAction = "renamed"
+	// MixinMerged means the entry from the mixin was merged with the existing entry (e.g. OAuth2 scopes)
+	MixinMerged MixinAction = "merged"
+)
 
-		skipped = append(skipped, mergeDefinitions(primary, m)...)
+// MixinConflict describes a collision found when merging a mixin into a primary spec
+type MixinConflict struct {
+	Section string      // the section of the spec where the collision occurred, e.g. "paths", "definitions", "tags"
+	Key     string      // the colliding key, e.g. a path, a definition name or a tag name
+	Pointer string      // the JSON pointer to the colliding entry in the primary spec
+	Action  MixinAction // the action taken by Mixin
+	Mixin   int         // the index of the mixin causing the collision
+	Message string      // a human readable message, as returned by Mixin
+}
 
-		// merging paths requires a map of operationIDs to work with
-		skipped = append(skipped, mergePaths(primary, m, opIds, i)...)
+// Err yields the conflict as a *NameConflictError
+func (c MixinConflict) Err() error {
+	return &NameConflictError{Section: c.Section, Name: c.Key, Pointer: c.Pointer}
+}
 
-		skipped = append(skipped, mergeParameters(primary, m)...)
+// Sections of a spec reported in mixin conflicts
+const (
+	MixinSectionExtensions          = "extensions"
+	MixinSectionSecurityDefinitions = "securityDefinitions"
+	MixinSectionSecurity            = "security"
+	MixinSectionDefinitions         = "definitions"
+	MixinSectionPaths               = "paths"
+	MixinSectionOperationIDs        = "operationIds"
+	MixinSectionParameters          = "parameters"
+	MixinSectionResponses           = "responses"
+	MixinSectionTags                = "tags"
+)
 
-		skipped = append(skipped, mergeResponses(primary, m)...)
+// MixinReport works like Mixin, but returns a structured report of the collisions, so callers may react
+// to specific kinds of conflicts (e.g. fail on path collisions, but tolerate tag collisions).
+//
+// Besides skipped entries, the report lists the operationIds renamed to avoid a collision.
+func MixinReport(primary *spec.Swagger, mixins ...*spec.Swagger) []MixinConflict {
+	specs := make([]MixinSpec, 0, len(mixins))
+	for _, m := range mixins {
+		specs = append(specs, MixinSpec{Spec: m})
 	}
 
-	return skipped
+	return MixinSpecs(primary, specs...)
+}
+
+func skippedEntry(section, key, pointer, message string) MixinConflict {
+	return MixinConflict{
+		Section: section,
+		Key:     key,
+		Pointer: pointer,
+		Action:  MixinSkipped,
+		Message: message,
+	}
 }
 
 // getOpIds extracts all the paths.<path>.operationIds from the given
@@ -123,12 +185,29 @@ func appendOp(ops []*spec.Operation, op *spec.Operation) []*spec.Operation {
 	return append(ops, op)
 }
 
-func mergeSecurityDefinitions(primary *spec.Swagger, m *spec.Swagger) (skipped []string) {
+func mergeSecurityDefinitions(primary *spec.Swagger, m *spec.Swagger, unionScopes bool) (skipped []MixinConflict) {
 	for k, v := range m.SecurityDefinitions {
-		if _, exists := primary.SecurityDefinitions[k]; exists {
+		if existing, exists := primary.SecurityDefinitions[k]; exists {
+			if unionScopes && canUnionScopes(existing, v) {
+				merged, added := unionOAuth2Scopes(existing, v)
+				primary.SecurityDefinitions[k] = merged
+				if len(added) > 0 {
+					skipped = append(skipped, MixinConflict{
+						Section: MixinSectionSecurityDefinitions,
+						Key:     k,
+						Pointer: "#/securityDefinitions/" + jsonpointer.Escape(k),
+						Action:  MixinMerged,
+						Message: fmt.Sprintf("SecurityDefinitions entry '%v' merged with scopes %v", k, added),
+					})
+				}
+
+				continue
+			}
+
 			warn := fmt.Sprintf(
 				"SecurityDefinitions entry '%v' already exists in primary or higher priority mixin, skipping\n", k)
-			skipped = append(skipped, warn)
+			skipped = append(skipped, skippedEntry(MixinSectionSecurityDefinitions, k,
+				"#/securityDefinitions/"+jsonpointer.Escape(k), warn))
 
 			continue
 		}
@@ -139,7 +218,41 @@ func mergeSecurityDefinitions(primary *spec.Swagger, m *spec.Swagger) (skipped [
 	return
 }
 
-func mergeSecurityRequirements(primary *spec.Swagger, m *spec.Swagger) (skipped []string) {
+// canUnionScopes tells if two security schemes are the same OAuth2 flow, against the same endpoints
+func canUnionScopes(primary, m *spec.SecurityScheme) bool {
+	if primary == nil || m == nil {
+		return false
+	}
+
+	return primary.Type == "oauth2" && m.Type == "oauth2" &&
+		primary.Flow == m.Flow &&
+		primary.AuthorizationURL == m.AuthorizationURL &&
+		primary.TokenURL == m.TokenURL
+}
+
+// unionOAuth2Scopes yields a copy of the primary security scheme, with the scopes of m added.
+// It returns the names of added scopes, sorted.
+func unionOAuth2Scopes(primary, m *spec.SecurityScheme) (*spec.SecurityScheme, []string) {
+	merged := *primary
+	merged.Scopes = make(map[string]string, len(primary.Scopes)+len(m.Scopes))
+	for scope, description := range primary.Scopes {
+		merged.Scopes[scope] = description
+	}
+
+	var added []string
+	for _, scope := range sortedKeys(m.Scopes) {
+		if _, exists := merged.Scopes[scope]; exists {
+			continue
+		}
+
+		merged.Scopes[scope] = m.Scopes[scope]
+		added = append(added, scope)
+	}
+
+	return &merged, added
+}
+
+func mergeSecurityRequirements(primary *spec.Swagger, m *spec.Swagger) (skipped []MixinConflict) {
 	for _, v := range m.Security {
 		found := false
 		for _, vv := range primary.Security {
@@ -153,7 +266,7 @@ func mergeSecurityRequirements(primary *spec.Swagger, m *spec.Swagger) (skipped
 		if found {
 			warn := fmt.Sprintf(
 				"Security requirement: '%v' already exists in primary or higher priority mixin, skipping\n", v)
-			skipped = append(skipped, warn)
+			skipped = append(skipped, skippedEntry(MixinSectionSecurity, fmt.Sprintf("%v", v), "#/security", warn))
 
 			continue
 		}
@@ -163,13 +276,14 @@ func mergeSecurityRequirements(primary *spec.Swagger, m *spec.Swagger) (skipped
 	return
 }
 
-func mergeDefinitions(primary *spec.Swagger, m *spec.Swagger) (skipped []string) {
+func mergeDefinitions(primary *spec.Swagger, m *spec.Swagger) (skipped []MixinConflict) {
 	for k, v := range m.Definitions {
 		// assume name collisions represent IDENTICAL type. careful.
 		if _, exists := primary.Definitions[k]; exists {
 			warn := fmt.Sprintf(
 				"definitions entry '%v' already exists in primary or higher priority mixin, skipping\n", k)
-			skipped = append(skipped, warn)
+			skipped = append(skipped, skippedEntry(MixinSectionDefinitions, k,
+				slashpath.Join(definitionsPath, jsonpointer.Escape(k)), warn))
 
 			continue
 		}
@@ -179,13 +293,13 @@ func mergeDefinitions(primary *spec.Swagger, m *spec.Swagger) (skipped []string)
 	return
 }
 
-func mergePaths(primary *spec.Swagger, m *spec.Swagger, opIds map[string]bool, mixIndex int) (skipped []string) {
+func mergePaths(primary *spec.Swagger, m *spec.Swagger, opIds map[string]bool, mixIndex int) (skipped []MixinConflict) {
 	if m.Paths != nil {
 		for k, v := range m.Paths.Paths {
 			if _, exists := primary.Paths.Paths[k]; exists {
 				warn := fmt.Sprintf(
 					"paths entry '%v' already exists in primary or higher priority mixin, skipping\n", k)
-				skipped = append(skipped, warn)
+				skipped = append(skipped, skippedEntry(MixinSectionPaths, k, "#/paths/"+jsonpointer.Escape(k), warn))
 
 				continue
 			}
@@ -199,7 +313,15 @@ func mergePaths(primary *spec.Swagger, m *spec.Swagger, opIds map[string]bool, m
 			piops := pathItemOps(v)
 			for _, piop := range piops {
 				if opIds[piop.ID] {
-					piop.ID = fmt.Sprintf("%v%v%v", piop.ID, "Mixin", mixIndex)
+					renamed := fmt.Sprintf("%v%v%v", piop.ID, "Mixin", mixIndex)
+					skipped = append(skipped, MixinConflict{
+						Section: MixinSectionOperationIDs,
+						Key:     piop.ID,
+						Pointer: "#/paths/" + jsonpointer.Escape(k),
+						Action:  MixinRenamed,
+						Message: fmt.Sprintf("operationId '%v' in paths entry '%v' already exists, renamed as '%v'", piop.ID, k, renamed),
+					})
+					piop.ID = renamed
 				}
 				opIds[piop.ID] = true
 			}
@@ -210,7 +332,7 @@ func mergePaths(primary *spec.Swagger, m *spec.Swagger, opIds map[string]bool, m
 	return
 }
 
-func mergeParameters(primary *spec.Swagger, m *spec.Swagger) (skipped []string) {
+func mergeParameters(primary *spec.Swagger, m *spec.Swagger) (skipped []MixinConflict) {
 	for k, v := range m.Parameters {
 		// could try to rename on conflict but would
 		// have to fix $refs in the mixin. Complain
@@ -218,7 +340,7 @@ func mergeParameters(primary *spec.Swagger, m *spec.Swagger) (skipped []string)
 		if _, exists := primary.Parameters[k]; exists {
 			warn := fmt.Sprintf(
 				"top level parameters entry '%v' already exists in primary or higher priority mixin, skipping\n", k)
-			skipped = append(skipped, warn)
+			skipped = append(skipped, skippedEntry(MixinSectionParameters, k, "#/parameters/"+jsonpointer.Escape(k), warn))
 
 			continue
 		}
@@ -228,7 +350,7 @@ func mergeParameters(primary *spec.Swagger, m *spec.Swagger) (skipped []string)
 	return
 }
 
-func mergeResponses(primary *spec.Swagger, m *spec.Swagger) (skipped []string) {
+func mergeResponses(primary *spec.Swagger, m *spec.Swagger) (skipped []MixinConflict) {
 	for k, v := range m.Responses {
 		// could try to rename on conflict but would
 		// have to fix $refs in the mixin. Complain
@@ -236,7 +358,7 @@ func mergeResponses(primary *spec.Swagger, m *spec.Swagger) (skipped []string) {
 		if _, exists := primary.Responses[k]; exists {
 			warn := fmt.Sprintf(
 				"top level responses entry '%v' already exists in primary or higher priority mixin, skipping\n", k)
-			skipped = append(skipped, warn)
+			skipped = append(skipped, skippedEntry(MixinSectionResponses, k, "#/responses/"+jsonpointer.Escape(k), warn))
 
 			continue
 		}
@@ -246,7 +368,7 @@ func mergeResponses(primary *spec.Swagger, m *spec.Swagger) (skipped []string) {
 	return skipped
 }
 
-func mergeConsumes(primary *spec.Swagger, m *spec.Swagger) []string {
+func mergeConsumes(primary *spec.Swagger, m *spec.Swagger) []MixinConflict {
 	for _, v := range m.Consumes {
 		found := false
 		for _, vv := range primary.Consumes {
@@ -264,10 +386,10 @@ func mergeConsumes(primary *spec.Swagger, m *spec.Swagger) []string {
 		primary.Consumes = append(primary.Consumes, v)
 	}
 
-	return []string{}
+	return []MixinConflict{}
 }
 
-func mergeProduces(primary *spec.Swagger, m *spec.Swagger) []string {
+func mergeProduces(primary *spec.Swagger, m *spec.Swagger) []MixinConflict {
 	for _, v := range m.Produces {
 		found := false
 		for _, vv := range primary.Produces {
@@ -285,10 +407,10 @@ func mergeProduces(primary *spec.Swagger, m *spec.Swagger) []string {
 		primary.Produces = append(primary.Produces, v)
 	}
 
-	return []string{}
+	return []MixinConflict{}
 }
 
-func mergeTags(primary *spec.Swagger, m *spec.Swagger) (skipped []string) {
+func mergeTags(primary *spec.Swagger, m *spec.Swagger) (skipped []MixinConflict) {
 	for _, v := range m.Tags {
 		found := false
 		for _, vv := range primary.Tags {
@@ -304,7 +426,7 @@ func mergeTags(primary *spec.Swagger, m *spec.Swagger) (skipped []string) {
 				"top level tags entry with name '%v' already exists in primary or higher priority mixin, skipping\n",
 				v.Name,
 			)
-			skipped = append(skipped, warn)
+			skipped = append(skipped, skippedEntry(MixinSectionTags, v.Name, "#/tags", warn))
 
 			continue
 		}
@@ -315,7 +437,7 @@ func mergeTags(primary *spec.Swagger, m *spec.Swagger) (skipped []string) {
 	return
 }
 
-func mergeSchemes(primary *spec.Swagger, m *spec.Swagger) []string {
+func mergeSchemes(primary *spec.Swagger, m *spec.Swagger) []MixinConflict {
 	for _, v := range m.Schemes {
 		found := false
 		for _, vv := range primary.Schemes {
@@ -333,13 +455,13 @@ func mergeSchemes(primary *spec.Swagger, m *spec.Swagger) []string {
 		primary.Schemes = append(primary.Schemes, v)
 	}
 
-	return []string{}
+	return []MixinConflict{}
 }
 
-func mergeSwaggerProps(primary *spec.Swagger, m *spec.Swagger) []string {
-	var skipped, skippedInfo, skippedDocs []string
+func mergeSwaggerProps(primary *spec.Swagger, m *spec.Swagger) []MixinConflict {
+	var skipped, skippedInfo, skippedDocs []MixinConflict
 
-	primary.Extensions, skipped = mergeExtensions(primary.Extensions, m.Extensions)
+	primary.Extensions, skipped = mergeExtensions(primary.Extensions, m.Extensions, "#")
 
 	// merging details in swagger top properties
 	if primary.Host == "" {
@@ -368,7 +490,7 @@ func mergeSwaggerProps(primary *spec.Swagger, m *spec.Swagger) []string {
 }
 
 //nolint:unparam
-func mergeExternalDocs(primary *spec.ExternalDocumentation, m *spec.ExternalDocumentation) []string {
+func mergeExternalDocs(primary *spec.ExternalDocumentation, m *spec.ExternalDocumentation) []MixinConflict {
 	if primary.Description == "" {
 		primary.Description = m.Description
 	}
@@ -380,10 +502,10 @@ func mergeExternalDocs(primary *spec.ExternalDocumentation, m *spec.ExternalDocu
 	return nil
 }
 
-func mergeInfo(primary *spec.Info, m *spec.Info) []string {
-	var sk, skipped []string
+func mergeInfo(primary *spec.Info, m *spec.Info) []MixinConflict {
+	var sk, skipped []MixinConflict
 
-	primary.Extensions, sk = mergeExtensions(primary.Extensions, m.Extensions)
+	primary.Extensions, sk = mergeExtensions(primary.Extensions, m.Extensions, "#/info")
 	skipped = append(skipped, sk...)
 
 	if primary.Description == "" {
@@ -405,8 +527,8 @@ func mergeInfo(primary *spec.Info, m *spec.Info) []string {
 	if primary.Contact == nil {
 		primary.Contact = m.Contact
 	} else if m.Contact != nil {
-		var csk []string
-		primary.Contact.Extensions, csk = mergeExtensions(primary.Contact.Extensions, m.Contact.Extensions)
+		var csk []MixinConflict
+		primary.Contact.Extensions, csk = mergeExtensions(primary.Contact.Extensions, m.Contact.Extensions, "#/info/contact")
 		skipped = append(skipped, csk...)
 
 		if primary.Contact.Name == "" {
@@ -425,8 +547,8 @@ func mergeInfo(primary *spec.Info, m *spec.Info) []string {
 	if primary.License == nil {
 		primary.License = m.License
 	} else if m.License != nil {
-		var lsk []string
-		primary.License.Extensions, lsk = mergeExtensions(primary.License.Extensions, m.License.Extensions)
+		var lsk []MixinConflict
+		primary.License.Extensions, lsk = mergeExtensions(primary.License.Extensions, m.License.Extensions, "#/info/license")
 		skipped = append(skipped, lsk...)
 
 		if primary.License.Name == "" {
@@ -441,7 +563,7 @@ func mergeInfo(primary *spec.Info, m *spec.Info) []string {
 	return skipped
 }
 
-func mergeExtensions(primary spec.Extensions, m spec.Extensions) (result spec.Extensions, skipped []string) {
+func mergeExtensions(primary spec.Extensions, m spec.Extensions, pointer string) (result spec.Extensions, skipped []MixinConflict) {
 	if primary == nil {
 		result = m
 
@@ -457,7 +579,7 @@ func mergeExtensions(primary spec.Extensions, m spec.Extensions) (result spec.Ex
 	result = primary
 	for k, v := range m {
 		if _, found := primary[k]; found {
-			skipped = append(skipped, k)
+			skipped = append(skipped, skippedEntry(MixinSectionExtensions, k, pointer+"/"+jsonpointer.Escape(k), k))
 
 			continue
 		}
@@ -0,0 +1,127 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// MixinSpec is a spec to mix into a primary spec with MixinSpecs, with namespacing options
+type MixinSpec struct {
+	Spec *spec.Swagger
+
+	// PathPrefix is prepended to all the paths of the mixin (e.g. "/billing")
+	PathPrefix string
+
+	// DefinitionPrefix is prepended to all the definition names of the mixin (e.g. "Billing").
+	//
+	// $ref's to these definitions found in the mixin are rewritten accordingly.
+	DefinitionPrefix string
+
+	// UnionScopes merges OAuth2 security definitions colliding with the primary spec, by taking the union of their scopes.
+	//
+	// This applies only when both definitions use the same flow, authorization and token URLs.
+	// Other colliding definitions are skipped.
+	UnionScopes bool
+}
+
+// MixinSpecs works like MixinReport, with namespacing options for each mixin.
+//
+// This allows for composing several specs into one (e.g. microservice specs into a gateway spec),
+// with no collisions on paths or definitions.
+//
+// Mixins with a prefix are not modified: a copy of them is merged into the primary spec.
+func MixinSpecs(primary *spec.Swagger, mixins ...MixinSpec) []MixinConflict {
+	var conflicts []MixinConflict
+	opIds := getOpIds(primary)
+	initPrimary(primary)
+
+	for i, mixin := range mixins {
+		m := mixin.Spec
+		if mixin.PathPrefix != "" || mixin.DefinitionPrefix != "" {
+			mixinTracef("mixin %d: prefixing paths with %q and definitions with %q", i, mixin.PathPrefix, mixin.DefinitionPrefix)
+			m = prefixedSpec(m, mixin.PathPrefix, mixin.DefinitionPrefix)
+		}
+
+		report := func(merged []MixinConflict) {
+			for j := range merged {
+				merged[j].Mixin = i
+				mixinTracef("mixin %d: %s %s entry at %s: %s", i, merged[j].Action, merged[j].Section, merged[j].Pointer, merged[j].Message)
+			}
+
+			conflicts = append(conflicts, merged...)
+		}
+
+		report(mergeSwaggerProps(primary, m))
+
+		report(mergeConsumes(primary, m))
+
+		report(mergeProduces(primary, m))
+
+		report(mergeTags(primary, m))
+
+		report(mergeSchemes(primary, m))
+
+		report(mergeSecurityDefinitions(primary, m, mixin.UnionScopes))
+
+		report(mergeSecurityRequirements(primary, m))
+
+		report(mergeDefinitions(primary, m))
+
+		// merging paths requires a map of operationIDs to work with
+		report(mergePaths(primary, m, opIds, i))
+
+		report(mergeParameters(primary, m))
+
+		report(mergeResponses(primary, m))
+	}
+
+	return conflicts
+}
+
+// mixinTracef traces a decision made while merging mixins, with the Logger set with SetLogger
+func mixinTracef(format string, args ...interface{}) {
+	tracef(nil, format, args...)
+}
+
+// prefixedSpec yields a copy of a spec with prefixed paths and definitions
+func prefixedSpec(sp *spec.Swagger, pathPrefix, definitionPrefix string) *spec.Swagger {
+	m := CloneSpec(sp)
+
+	if pathPrefix != "" && m.Paths != nil {
+		prefix := strings.TrimRight(pathPrefix, "/")
+		if !strings.HasPrefix(prefix, "/") {
+			prefix = "/" + prefix
+		}
+
+		paths := make(map[string]spec.PathItem, len(m.Paths.Paths))
+		for pth, pathItem := range m.Paths.Paths {
+			paths[prefix+pth] = pathItem
+		}
+		m.Paths.Paths = paths
+	}
+
+	if definitionPrefix == "" {
+		return m
+	}
+
+	definitions := make(spec.Definitions, len(m.Definitions))
+	for name, schema := range m.Definitions {
+		definitions[definitionPrefix+name] = schema
+	}
+	m.Definitions = definitions
+
+	walkSchemas(m, func(_ string, schema *spec.Schema) {
+		ref := schema.Ref.String()
+		name, isDefinition := definitionOfPointer(ref)
+		if !isDefinition {
+			return
+		}
+
+		rest := strings.TrimPrefix(strings.TrimPrefix(ref, definitionsPrefix), jsonpointer.Escape(name))
+		schema.Ref = spec.MustCreateRef(definitionsPrefix + jsonpointer.Escape(definitionPrefix+name) + rest)
+	})
+
+	return m
+}
@@ -15,9 +15,14 @@
 package analysis
 
 import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -58,6 +63,173 @@ func TestMixin_All(t *testing.T) {
 	require.Lenf(t, primary.Produces, 2, "TestMixin: Expected 2 top level Producers merged, got %v\n", len(primary.Security))
 }
 
+func TestMixed(t *testing.T) {
+	t.Parallel()
+
+	primary := antest.LoadOrFail(t, widgetFile)
+	mixin := antest.LoadOrFail(t, fooFile)
+	originalPrimary, originalMixin := CloneSpec(primary), CloneSpec(mixin)
+
+	mixed, skipped := Mixed(primary, mixin)
+
+	expected := CloneSpec(primary)
+	assert.ElementsMatch(t, Mixin(expected, CloneSpec(mixin)), skipped)
+	assert.Equal(t, expected, mixed)
+
+	assert.Equal(t, originalPrimary, primary)
+	assert.Equal(t, originalMixin, mixin)
+
+	// the mixed spec does not share anything with its inputs
+	for name := range mixed.Definitions {
+		delete(mixed.Definitions, name)
+	}
+	for _, item := range mixed.Paths.Paths {
+		if item.Get != nil {
+			item.Get.Summary = "changed"
+		}
+	}
+	assert.Equal(t, originalPrimary, primary)
+	assert.Equal(t, originalMixin, mixin)
+}
+
+func TestMixin_Report(t *testing.T) {
+	t.Parallel()
+
+	primary := antest.LoadOrFail(t, widgetFile)
+	mixin1 := antest.LoadOrFail(t, fooFile)
+	mixin2 := antest.LoadOrFail(t, barFile)
+	mixin3 := antest.LoadOrFail(t, noPathsFile)
+	mixin4 := antest.LoadOrFail(t, securityFile)
+	mixin5 := antest.LoadOrFail(t, otherMixin)
+
+	conflicts := MixinReport(primary, mixin1, mixin2, mixin3, mixin4, mixin5)
+
+	bySection := make(map[string]int)
+	var skipped, renamed int
+	for _, conflict := range conflicts {
+		bySection[conflict.Section]++
+		switch conflict.Action {
+		case MixinSkipped:
+			skipped++
+		case MixinRenamed:
+			renamed++
+		}
+	}
+
+	require.Equal(t, 19, skipped)
+	require.Equal(t, 8, renamed)
+	require.Equal(t, map[string]int{
+		MixinSectionDefinitions:         5,
+		MixinSectionOperationIDs:        8,
+		MixinSectionParameters:          3,
+		MixinSectionPaths:               2,
+		MixinSectionResponses:           6,
+		MixinSectionSecurityDefinitions: 1,
+		MixinSectionSecurity:            1,
+		MixinSectionTags:                1,
+	}, bySection)
+
+	require.Contains(t, conflicts, MixinConflict{
+		Section: MixinSectionPaths,
+		Key:     "/common",
+		Pointer: "#/paths/~1common",
+		Action:  MixinSkipped,
+		Mixin:   1,
+		Message: "paths entry '/common' already exists in primary or higher priority mixin, skipping\n",
+	})
+	require.Contains(t, conflicts, MixinConflict{
+		Section: MixinSectionOperationIDs,
+		Key:     "create",
+		Pointer: "#/paths/~1foos",
+		Action:  MixinRenamed,
+		Mixin:   0,
+		Message: "operationId 'create' in paths entry '/foos' already exists, renamed as 'createMixin0'",
+	})
+}
+
+func TestMixin_Prefixes(t *testing.T) {
+	t.Parallel()
+
+	primary := antest.LoadOrFail(t, widgetFile)
+	mixin := antest.LoadOrFail(t, fooFile)
+	pristine := antest.AsJSON(t, mixin)
+
+	conflicts := MixinSpecs(primary,
+		MixinSpec{Spec: mixin, PathPrefix: "/foo-service/", DefinitionPrefix: "Foo"},
+		MixinSpec{Spec: mixin, PathPrefix: "bar-service", DefinitionPrefix: "Bar"},
+	)
+
+	for _, conflict := range conflicts {
+		require.NotContains(t, []string{MixinSectionPaths, MixinSectionDefinitions}, conflict.Section, conflict.Message)
+	}
+	require.JSONEq(t, pristine, antest.AsJSON(t, mixin), "expected the mixin to remain unchanged")
+
+	require.Contains(t, primary.Paths.Paths, "/common")
+	require.Contains(t, primary.Paths.Paths, "/foo-service/common")
+	require.Contains(t, primary.Paths.Paths, "/bar-service/foos/{fooid}")
+	require.Contains(t, primary.Definitions, "common")
+	require.Contains(t, primary.Definitions, "Foocommon")
+	require.Contains(t, primary.Definitions, "BarfooId")
+
+	// $ref's in mixins are rewritten, and all resolve in the merged spec
+	an := New(primary)
+	for _, ref := range an.AllDefinitionReferences() {
+		name, isDefinition := definitionOfPointer(ref)
+		require.Truef(t, isDefinition, "unexpected $ref %s", ref)
+		require.Containsf(t, primary.Definitions, name, "unresolved $ref %s", ref)
+	}
+	require.Contains(t, an.AllDefinitionReferences(), "#/definitions/BarfooId")
+}
+
+func TestMixin_UnionScopes(t *testing.T) {
+	t.Parallel()
+
+	oauth2 := func(tokenURL string, scopes ...string) *spec.SecurityScheme {
+		scheme := spec.OAuth2AccessToken("https://auth.example.com/authorize", tokenURL)
+		for _, scope := range scopes {
+			scheme.AddScope(scope, "scope "+scope)
+		}
+
+		return scheme
+	}
+	withAuth := func(scheme *spec.SecurityScheme) *spec.Swagger {
+		return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			SecurityDefinitions: spec.SecurityDefinitions{"auth": scheme},
+		}}
+	}
+
+	primary := withAuth(oauth2("https://auth.example.com/token", "pets:read"))
+	billing := withAuth(oauth2("https://auth.example.com/token", "pets:read", "invoices:read", "invoices:write"))
+	other := withAuth(oauth2("https://other.example.com/token", "other:read"))
+
+	conflicts := MixinSpecs(primary,
+		MixinSpec{Spec: billing, UnionScopes: true},
+		MixinSpec{Spec: billing, UnionScopes: true},
+		MixinSpec{Spec: other, UnionScopes: true},
+	)
+
+	require.Len(t, conflicts, 2)
+	require.Equal(t, MixinConflict{
+		Section: MixinSectionSecurityDefinitions,
+		Key:     "auth",
+		Pointer: "#/securityDefinitions/auth",
+		Action:  MixinMerged,
+		Message: "SecurityDefinitions entry 'auth' merged with scopes [invoices:read invoices:write]",
+	}, conflicts[0])
+	require.Equal(t, MixinSkipped, conflicts[1].Action)
+	require.Equal(t, 2, conflicts[1].Mixin)
+
+	require.Len(t, primary.SecurityDefinitions["auth"].Scopes, 3)
+	require.Len(t, billing.SecurityDefinitions["auth"].Scopes, 3)
+
+	t.Run("should skip colliding definitions by default", func(t *testing.T) {
+		primary := withAuth(oauth2("https://auth.example.com/token", "pets:read"))
+
+		require.Len(t, Mixin(primary, billing), 1)
+		require.Len(t, primary.SecurityDefinitions["auth"].Scopes, 1)
+	})
+}
+
 func TestMixin_EmptyPath(t *testing.T) {
 	t.Parallel()
 
@@ -92,3 +264,32 @@ func TestMixin_SwaggerProps(t *testing.T) {
 
 	require.Lenf(t, collisions, 1, "TestMixin: Expected 1 collisions, got %v\n%v", len(collisions), collisions)
 }
+
+func TestMixin_UnknownKeywords(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "keywords", "spec.yml")
+	primary := antest.LoadOrFail(t, widgetFile)
+	mixin := antest.LoadOrFail(t, bp)
+	original := CloneSpec(mixin)
+
+	MixinSpecs(primary, MixinSpec{Spec: mixin, DefinitionPrefix: "Keywords"})
+
+	// unknown keywords and vendor extensions are carried through, byte for byte
+	for name, schema := range original.Definitions {
+		expected, err := json.Marshal(schema)
+		require.NoError(t, err)
+		if name == "owner" {
+			// the local $ref is prefixed
+			expected = bytes.ReplaceAll(expected, []byte("#/definitions/tags"), []byte("#/definitions/Keywordstags"))
+		}
+
+		actual, err := json.Marshal(primary.Definitions["Keywords"+name])
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(actual))
+	}
+
+	items := primary.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200].Schema.Items.Schema
+	assert.Equal(t, spec.Extensions{"x-nullable": true}, items.Extensions)
+	assert.Equal(t, map[string]interface{}{"$comment": "pets may be null"}, items.ExtraProps)
+}
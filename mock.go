@@ -0,0 +1,366 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+// DefaultMockMaxDepth is the default depth of nested objects and arrays beyond which mock values
+// only hold what their schema requires
+const DefaultMockMaxDepth = 5
+
+// MockOpts specifies how Spec.MockResponseFor produces mock values
+type MockOpts struct {
+	// Seed seeds the randomness of mock values: the same seed yields the same value for the same schema
+	Seed int64
+
+	// MediaType selects the example declared for the response. Defaults to the first media type with an example,
+	// in lexical order.
+	MediaType string
+
+	// IgnoreExamples produces values from the schemas, ignoring the examples declared for the response and its schemas
+	IgnoreExamples bool
+
+	// RequiredOnly omits the optional properties of objects. Otherwise, each of them is present at random.
+	RequiredOnly bool
+
+	// MaxDepth is the depth of nested objects and arrays beyond which values only hold what their schema requires:
+	// required properties, and the minimum number of items. It stops recursive schemas. Defaults to DefaultMockMaxDepth.
+	MaxDepth int
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// MockResponseFor produces a mock value of the body of a response of an operation, as generic JSON.
+//
+// The response for the status code is used, or the default response when the operation declares none
+// for this status code. The example declared for the response is used when present. Otherwise, a random value
+// matching the schema of the response is built, honoring examples, enums, formats, bounds, lengths, patterns
+// (when some simple candidate matches), required properties and the bounds of arrays. $ref's are resolved,
+// and the members of allOf are merged (see EffectiveSchema).
+//
+// A response without a schema yields nil.
+func (s *Spec) MockResponseFor(operation *spec.Operation, status int, opts MockOpts) (interface{}, error) {
+	if operation == nil || operation.Responses == nil {
+		return nil, fmt.Errorf("%w: %d", ErrNoResponse, status)
+	}
+
+	response, declared := operation.Responses.StatusCodeResponses[status]
+	if !declared {
+		if operation.Responses.Default == nil {
+			return nil, fmt.Errorf("%w: %d", ErrNoResponse, status)
+		}
+		response = *operation.Responses.Default
+	}
+
+	if response.Ref.String() != "" {
+		resolved, err := spec.ResolveResponse(s.spec, response.Ref)
+		if err != nil {
+			return nil, &RefError{Ref: response.Ref.String(), Cause: err}
+		}
+		response = *resolved
+	}
+
+	if !opts.IgnoreExamples && len(response.Examples) > 0 {
+		mediaType := opts.MediaType
+		if _, found := response.Examples[mediaType]; !found {
+			mediaType = sortedKeys(response.Examples)[0]
+		}
+
+		return deepCopyJSON(response.Examples[mediaType]), nil
+	}
+
+	if response.Schema == nil {
+		return nil, nil
+	}
+
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultMockMaxDepth
+	}
+
+	m := &mocker{root: s.spec, opts: opts, random: rand.New(rand.NewSource(opts.Seed))} //nolint:gosec // mock values need no secure randomness
+
+	return m.value(response.Schema, 0)
+}
+
+type mocker struct {
+	root   *spec.Swagger
+	opts   MockOpts
+	random *rand.Rand
+}
+
+// value produces a random value for a schema, at some depth of nesting
+func (m *mocker) value(schema *spec.Schema, depth int) (interface{}, error) {
+	effective, _, err := EffectiveSchema(schema, m.root, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// circular $ref's are retained by EffectiveSchema: they are resolved one level at a time
+	if effective.Ref.String() != "" {
+		if depth >= m.opts.MaxDepth {
+			return nil, nil
+		}
+
+		resolved, err := spec.ResolveRef(m.root, &effective.Ref)
+		if err != nil {
+			return nil, &RefError{Ref: effective.Ref.String(), Cause: err}
+		}
+
+		return m.value(resolved, depth+1)
+	}
+
+	return m.effectiveValue(effective, depth)
+}
+
+func (m *mocker) effectiveValue(schema *spec.Schema, depth int) (interface{}, error) {
+	switch {
+	case !m.opts.IgnoreExamples && schema.Example != nil:
+		return deepCopyJSON(schema.Example), nil
+	case len(schema.Enum) > 0:
+		return deepCopyJSON(schema.Enum[m.random.Intn(len(schema.Enum))]), nil
+	case len(schema.AnyOf) > 0:
+		return m.value(&schema.AnyOf[m.random.Intn(len(schema.AnyOf))], depth)
+	case len(schema.OneOf) > 0:
+		return m.value(&schema.OneOf[m.random.Intn(len(schema.OneOf))], depth)
+	}
+
+	tpe := ""
+	switch {
+	case len(schema.Type) > 0:
+		tpe = schema.Type[m.random.Intn(len(schema.Type))]
+	case len(schema.Properties) > 0 || len(schema.Required) > 0:
+		tpe = "object"
+	case schema.Items != nil:
+		tpe = "array"
+	}
+
+	switch tpe {
+	case "object":
+		return m.object(schema, depth)
+	case "array":
+		return m.array(schema, depth)
+	case "string":
+		return m.string(schema), nil
+	case "integer":
+		return m.number(schema, 1), nil
+	case "number":
+		return m.number(schema, 0), nil
+	case "boolean":
+		return m.random.Intn(2) == 1, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (m *mocker) object(schema *spec.Schema, depth int) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(schema.Properties))
+	for _, name := range sortedKeys(schema.Properties) {
+		required := containsString(schema.Required, name)
+		if !required && (m.opts.RequiredOnly || depth >= m.opts.MaxDepth || m.random.Intn(2) == 0) {
+			continue
+		}
+
+		property := schema.Properties[name]
+		value, err := m.value(&property, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = value
+	}
+
+	for _, name := range schema.Required {
+		if _, isDeclared := result[name]; isDeclared {
+			continue
+		}
+
+		var value interface{}
+		if additional := schema.AdditionalProperties; additional != nil && additional.Schema != nil {
+			var err error
+			if value, err = m.value(additional.Schema, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		result[name] = value
+	}
+
+	return result, nil
+}
+
+func (m *mocker) array(schema *spec.Schema, depth int) ([]interface{}, error) {
+	if schema.Items != nil && len(schema.Items.Schemas) > 0 {
+		result := make([]interface{}, 0, len(schema.Items.Schemas))
+		for i := range schema.Items.Schemas {
+			value, err := m.value(&schema.Items.Schemas[i], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+
+		return result, nil
+	}
+
+	minimum, maximum := int64(0), int64(3)
+	if schema.MinItems != nil {
+		minimum = *schema.MinItems
+		if maximum < minimum {
+			maximum = minimum + 3
+		}
+	}
+	if schema.MaxItems != nil {
+		maximum = *schema.MaxItems
+	}
+
+	count := minimum
+	if depth < m.opts.MaxDepth && maximum > minimum {
+		count += m.random.Int63n(maximum - minimum + 1)
+	}
+
+	result := make([]interface{}, 0, count)
+	for attempts := int64(0); int64(len(result)) < count && attempts < count*10; attempts++ {
+		var (
+			value interface{}
+			err   error
+		)
+		if schema.Items != nil && schema.Items.Schema != nil {
+			if value, err = m.value(schema.Items.Schema, depth+1); err != nil {
+				return nil, err
+			}
+		}
+
+		if schema.UniqueItems && containsValue(result, value) {
+			continue
+		}
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+// mockLetters are the characters of random strings
+const mockLetters = "abcdefghijklmnopqrstuvwxyz"
+
+func (m *mocker) string(schema *spec.Schema) string {
+	if value, isKnown := m.format(schema.Format); isKnown {
+		return value
+	}
+
+	minimum, maximum := int64(1), int64(12)
+	if schema.MinLength != nil {
+		minimum = *schema.MinLength
+		if maximum < minimum {
+			maximum = minimum + 12
+		}
+	}
+	if schema.MaxLength != nil {
+		maximum = *schema.MaxLength
+	}
+
+	length := minimum
+	if maximum > minimum {
+		length += m.random.Int63n(maximum - minimum + 1)
+	}
+
+	var value strings.Builder
+	for i := int64(0); i < length; i++ {
+		value.WriteByte(mockLetters[m.random.Intn(len(mockLetters))])
+	}
+
+	if schema.Pattern != "" {
+		if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(value.String()) {
+			return synthesizeString(schema)
+		}
+	}
+
+	return value.String()
+}
+
+// format produces a random string for some common formats
+func (m *mocker) format(format string) (string, bool) {
+	// random instants are picked in 2020-2029
+	instant := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(m.random.Int63n(int64(10 * 365 * 24 * time.Hour))))
+
+	switch format {
+	case "date":
+		return instant.Format("2006-01-02"), true
+	case "date-time":
+		return instant.Truncate(time.Second).Format(time.RFC3339), true
+	case "uuid":
+		var b [16]byte
+		m.random.Read(b[:])
+		b[6] = b[6]&0x0f | 0x40 // version 4
+		b[8] = b[8]&0x3f | 0x80 // variant 10
+
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), true
+	case "email":
+		return fmt.Sprintf("user%d@example.com", m.random.Intn(1000)), true
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", 1+m.random.Intn(254)), true
+	}
+
+	example, isKnown := formatExamples[format]
+
+	return example, isKnown
+}
+
+// number produces a random number within the bounds of a schema, which default to [0, 100].
+//
+// The step is the granularity of the values: 1 for integers, 0 for numbers.
+func (m *mocker) number(schema *spec.Schema, step float64) float64 {
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		step = *schema.MultipleOf
+	}
+
+	minimum, maximum := 0.0, 100.0
+	if schema.Minimum != nil {
+		minimum = *schema.Minimum
+		if maximum < minimum {
+			maximum = minimum + 100
+		}
+	}
+	if schema.Maximum != nil {
+		maximum = *schema.Maximum
+		if schema.Minimum == nil && minimum > maximum {
+			minimum = maximum - 100
+		}
+	}
+
+	if step == 0 {
+		value := math.Round((minimum+m.random.Float64()*(maximum-minimum))*100) / 100
+		if m.withinBounds(schema, value) {
+			return value
+		}
+
+		return synthesizeNumber(schema, step)
+	}
+
+	// the multiples of the step within the bounds
+	first, last := math.Ceil(minimum/step), math.Floor(maximum/step)
+	if schema.ExclusiveMinimum && first*step == minimum {
+		first++
+	}
+	if schema.ExclusiveMaximum && last*step == maximum {
+		last--
+	}
+	if last < first {
+		return synthesizeNumber(schema, step)
+	}
+
+	return (first + float64(m.random.Int63n(int64(last-first)+1))) * step
+}
+
+func (m *mocker) withinBounds(schema *spec.Schema, value float64) bool {
+	if schema.Minimum != nil && (value < *schema.Minimum || schema.ExclusiveMinimum && value == *schema.Minimum) {
+		return false
+	}
+
+	return schema.Maximum == nil || value < *schema.Maximum || !schema.ExclusiveMaximum && value == *schema.Maximum
+}
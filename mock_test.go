@@ -0,0 +1,143 @@
+package analysis
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockResponseFor(t *testing.T) {
+	t.Parallel()
+
+	s := New(antest.LoadOrFail(t, filepath.Join("fixtures", "mock.yml")))
+	op, ok := s.OperationFor("GET", "/pets")
+	require.True(t, ok)
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	t.Run("should honor the schema of the response", func(t *testing.T) {
+		t.Parallel()
+
+		for seed := int64(0); seed < 20; seed++ {
+			value, err := s.MockResponseFor(op, 200, MockOpts{Seed: seed})
+			require.NoError(t, err)
+
+			pets, isArray := value.([]interface{})
+			require.Truef(t, isArray, "expected an array, got %T", value)
+			assert.GreaterOrEqual(t, len(pets), 2)
+			assert.LessOrEqual(t, len(pets), 4)
+
+			for _, item := range pets {
+				pet, isObject := item.(map[string]interface{})
+				require.True(t, isObject)
+
+				assert.Regexp(t, uuid, pet["id"])
+				assert.Contains(t, []interface{}{"cat", "dog"}, pet["kind"])
+
+				name, isString := pet["name"].(string)
+				require.True(t, isString)
+				assert.GreaterOrEqual(t, len(name), 3)
+				assert.LessOrEqual(t, len(name), 8)
+
+				born, isString := pet["born"].(string)
+				require.True(t, isString)
+				_, err := time.Parse(time.RFC3339, born)
+				require.NoError(t, err)
+
+				if age, isPresent := pet["age"]; isPresent {
+					assert.GreaterOrEqual(t, age, 1.0)
+					assert.LessOrEqual(t, age, 30.0)
+					assert.Equal(t, float64(int(age.(float64))), age)
+				}
+
+				if weight, isPresent := pet["weight"]; isPresent {
+					assert.Greater(t, weight, 0.0)
+					assert.LessOrEqual(t, weight, 50.0)
+				}
+
+				if owner, isPresent := pet["owner"]; isPresent {
+					require.IsType(t, map[string]interface{}{}, owner)
+					assert.Contains(t, owner, "email")
+					assert.Contains(t, owner, "pets") // the members of allOf are merged
+				}
+			}
+		}
+	})
+
+	t.Run("should be reproducible", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := s.MockResponseFor(op, 200, MockOpts{Seed: 42})
+		require.NoError(t, err)
+		again, err := s.MockResponseFor(op, 200, MockOpts{Seed: 42})
+		require.NoError(t, err)
+		other, err := s.MockResponseFor(op, 200, MockOpts{Seed: 43})
+		require.NoError(t, err)
+
+		assert.Equal(t, first, again)
+		assert.NotEqual(t, first, other)
+	})
+
+	t.Run("should only produce required properties", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := s.MockResponseFor(op, 200, MockOpts{RequiredOnly: true})
+		require.NoError(t, err)
+
+		for _, item := range value.([]interface{}) {
+			assert.Len(t, item, 4)
+		}
+	})
+
+	t.Run("should honor examples", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := s.MockResponseFor(op, 404, MockOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"message": "pet not found"}, value)
+
+		value, err = s.MockResponseFor(op, 404, MockOpts{IgnoreExamples: true})
+		require.NoError(t, err)
+		assert.NotEqual(t, map[string]interface{}{"message": "pet not found"}, value)
+		assert.Contains(t, value, "message")
+
+		value, err = s.MockResponseFor(op, 500, MockOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"message": "unexpected"}, value)
+
+		value, err = s.MockResponseFor(op, 500, MockOpts{MediaType: "text/plain"})
+		require.NoError(t, err)
+		assert.Equal(t, "unexpected", value)
+	})
+
+	t.Run("should stop recursive schemas", func(t *testing.T) {
+		t.Parallel()
+
+		for seed := int64(0); seed < 5; seed++ {
+			_, err := s.MockResponseFor(op, 200, MockOpts{Seed: seed, MaxDepth: 2})
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("should produce nothing without schema", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := s.MockResponseFor(op, 204, MockOpts{})
+		require.NoError(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("should fail without response", func(t *testing.T) {
+		t.Parallel()
+
+		op := *op
+		op.Responses = nil
+		_, err := s.MockResponseFor(&op, 200, MockOpts{})
+		require.ErrorIs(t, err, ErrNoResponse)
+	})
+}
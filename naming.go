@@ -0,0 +1,265 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// Codes for findings about naming conventions
+const (
+	CodeOperationIDNaming   = "operation-id-naming"
+	CodePathCasing          = "inconsistent-path-casing"
+	CodeParameterNameCasing = "inconsistent-parameter-casing"
+)
+
+// NamingCase is the casing convention of a name
+type NamingCase string
+
+// Known naming cases.
+//
+// LowerCase names (single lower case words such as "pets") are compatible with all lower case conventions
+// and never count as outliers.
+const (
+	LowerCase      NamingCase = "lowercase"
+	CamelCase      NamingCase = "camelCase"
+	PascalCase     NamingCase = "PascalCase"
+	SnakeCase      NamingCase = "snake_case"
+	KebabCase      NamingCase = "kebab-case"
+	UpperSnakeCase NamingCase = "UPPER_SNAKE_CASE"
+	MixedCase      NamingCase = "mixed"
+)
+
+var defaultOperationIDVerbs = []string{
+	"add", "check", "create", "delete", "download", "find", "get", "list", "patch",
+	"remove", "replace", "search", "set", "update", "upload", "upsert",
+}
+
+// NamingOpts configures the naming convention checks
+type NamingOpts struct {
+	// OperationIDPattern is the regexp operationIds must match.
+	//
+	// When not set, operationIds must follow the verbNoun pattern (e.g. "getPetById"),
+	// starting with one of OperationIDVerbs.
+	OperationIDPattern *regexp.Regexp
+
+	// OperationIDVerbs lists the verbs accepted by the default verbNoun pattern.
+	// Defaults to common verbs such as "get", "list", "create", "update", "delete".
+	OperationIDVerbs []string
+
+	_ struct{}
+}
+
+func (o NamingOpts) operationIDPattern() *regexp.Regexp {
+	if o.OperationIDPattern != nil {
+		return o.OperationIDPattern
+	}
+
+	verbs := o.OperationIDVerbs
+	if len(verbs) == 0 {
+		verbs = defaultOperationIDVerbs
+	}
+
+	quoted := make([]string, 0, len(verbs))
+	for _, verb := range verbs {
+		quoted = append(quoted, regexp.QuoteMeta(verb))
+	}
+
+	return regexp.MustCompile(`^(` + strings.Join(quoted, "|") + `)([A-Z][a-zA-Z0-9]*)+$`)
+}
+
+// NamingConvention summarizes the casing used for a category of names across a document
+type NamingConvention struct {
+	Dominant NamingCase         // the most frequent naming case, LowerCase if none stands out
+	Counts   map[NamingCase]int // the number of names using each case
+	Outliers []string           // the JSON pointers of names which do not follow the dominant case
+}
+
+// NamingReport is the result of the naming convention checks
+type NamingReport struct {
+	Paths      NamingConvention // casing of path segments (e.g. "/pet-stores" is kebab-case)
+	Parameters NamingConvention // casing of path, query and formData parameter names
+	Findings   []Finding
+}
+
+// NamingConventions checks operationIds against a naming pattern, and reports path segments and parameter names
+// which do not follow the dominant casing convention in the document.
+//
+// Path parameters in path templates are not considered as path segments. Header and body parameters are not checked:
+// header names follow their own HTTP conventions, and body parameter names are not exposed to clients.
+//
+// Findings are sorted by pointer.
+func (s *Spec) NamingConventions(opts NamingOpts) NamingReport {
+	var (
+		report  NamingReport
+		pattern = opts.operationIDPattern()
+	)
+
+	walkOperations(s.spec, func(pointer string, op *spec.Operation) {
+		if op.ID == "" || pattern.MatchString(op.ID) {
+			return
+		}
+
+		report.Findings = append(report.Findings, Finding{
+			Pointer: "#" + pointer,
+			Code:    CodeOperationIDNaming,
+			Message: fmt.Sprintf("operationId %q does not match %s", op.ID, pattern.String()),
+		})
+	})
+
+	pathCases := make(map[string]NamingCase)
+	for _, path := range s.sortedPaths() {
+		pathCases["#/paths/"+jsonpointer.Escape(path)] = pathCase(path)
+	}
+	report.Paths = namingConvention(pathCases)
+
+	paramCases := make(map[string]NamingCase)
+	names := make(map[string]string)
+	walkParameters(s.spec, func(pointer string, param *spec.Parameter) {
+		if param.Ref.String() != "" || param.In == "body" || param.In == "header" || param.Name == "" {
+			return
+		}
+
+		paramCases[pointer] = caseOf(param.Name)
+		names[pointer] = param.Name
+	})
+	report.Parameters = namingConvention(paramCases)
+
+	for _, pointer := range report.Paths.Outliers {
+		report.Findings = append(report.Findings, Finding{
+			Pointer: pointer,
+			Code:    CodePathCasing,
+			Message: fmt.Sprintf("path uses %s, but most paths use %s", pathCases[pointer], report.Paths.Dominant),
+		})
+	}
+
+	for _, pointer := range report.Parameters.Outliers {
+		report.Findings = append(report.Findings, Finding{
+			Pointer: pointer,
+			Code:    CodeParameterNameCasing,
+			Message: fmt.Sprintf("parameter %q uses %s, but most parameters use %s",
+				names[pointer], paramCases[pointer], report.Parameters.Dominant),
+		})
+	}
+
+	sortFindings(report.Findings)
+
+	return report
+}
+
+func (s *Spec) sortedPaths() []string {
+	if s.spec == nil || s.spec.Paths == nil {
+		return nil
+	}
+
+	return sortedKeys(s.spec.Paths.Paths)
+}
+
+// namingConvention determines the dominant case among names, and the outliers
+func namingConvention(cases map[string]NamingCase) NamingConvention {
+	convention := NamingConvention{
+		Dominant: LowerCase,
+		Counts:   make(map[NamingCase]int),
+	}
+
+	for _, nc := range cases {
+		convention.Counts[nc]++
+	}
+
+	best := 0
+	for _, nc := range []NamingCase{CamelCase, KebabCase, PascalCase, SnakeCase, UpperSnakeCase} {
+		if count := convention.Counts[nc]; count > best {
+			best = count
+			convention.Dominant = nc
+		}
+	}
+
+	for pointer, nc := range cases {
+		if nc != LowerCase && nc != convention.Dominant {
+			convention.Outliers = append(convention.Outliers, pointer)
+		}
+	}
+	sort.Strings(convention.Outliers)
+
+	return convention
+}
+
+// pathCase determines the case of the segments of a path, ignoring path parameters.
+//
+// Segments using different cases yield MixedCase.
+func pathCase(path string) NamingCase {
+	result := LowerCase
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || isPathParam(segment) {
+			continue
+		}
+
+		nc := caseOf(segment)
+		switch {
+		case nc == LowerCase || nc == result:
+		case result == LowerCase:
+			result = nc
+		default:
+			return MixedCase
+		}
+	}
+
+	return result
+}
+
+// caseOf determines the naming case of a name
+func caseOf(name string) NamingCase {
+	var hasLower, hasUpper, hasUnderscore, hasHyphen bool
+	for _, r := range name {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case r == '_':
+			hasUnderscore = true
+		case r == '-':
+			hasHyphen = true
+		case unicode.IsDigit(r), r == '.':
+		default:
+			return MixedCase
+		}
+	}
+
+	first := []rune(name)[0]
+
+	switch {
+	case hasUnderscore && hasHyphen:
+		return MixedCase
+	case hasHyphen:
+		if hasUpper {
+			return MixedCase
+		}
+
+		return KebabCase
+	case hasUnderscore:
+		if hasLower && hasUpper {
+			return MixedCase
+		}
+
+		if hasUpper {
+			return UpperSnakeCase
+		}
+
+		return SnakeCase
+	case !hasUpper:
+		return LowerCase
+	case !hasLower:
+		return UpperSnakeCase
+	case unicode.IsUpper(first):
+		return PascalCase
+	default:
+		return CamelCase
+	}
+}
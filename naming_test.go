@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamingConventions(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "operations", "fixture-naming.yaml")))
+
+	t.Run("with verbNoun operationIds", func(t *testing.T) {
+		t.Parallel()
+
+		report := an.NamingConventions(NamingOpts{})
+
+		assert.Equal(t, KebabCase, report.Paths.Dominant)
+		assert.Equal(t, map[NamingCase]int{KebabCase: 2, CamelCase: 1, LowerCase: 1}, report.Paths.Counts)
+		assert.Equal(t, []string{"#/paths/~1petOwners"}, report.Paths.Outliers)
+
+		assert.Equal(t, SnakeCase, report.Parameters.Dominant)
+		assert.Equal(t, map[NamingCase]int{SnakeCase: 2, CamelCase: 1, LowerCase: 1}, report.Parameters.Counts)
+		assert.Equal(t, []string{"#/paths/~1pet-stores~1{store_id}~1pets/get/parameters/0"}, report.Parameters.Outliers)
+
+		require.Len(t, report.Findings, 4)
+		assert.Equal(t, Finding{
+			Pointer: "#/paths/~1health/get",
+			Code:    CodeOperationIDNaming,
+			Message: `operationId "Health" does not match ` + NamingOpts{}.operationIDPattern().String(),
+		}, report.Findings[0])
+		assert.Equal(t, Finding{
+			Pointer: "#/paths/~1pet-stores~1{store_id}~1pets/get",
+			Code:    CodeOperationIDNaming,
+			Message: `operationId "fetch_pets" does not match ` + NamingOpts{}.operationIDPattern().String(),
+		}, report.Findings[1])
+		assert.Equal(t, Finding{
+			Pointer: "#/paths/~1pet-stores~1{store_id}~1pets/get/parameters/0",
+			Code:    CodeParameterNameCasing,
+			Message: `parameter "sortOrder" uses camelCase, but most parameters use snake_case`,
+		}, report.Findings[2])
+		assert.Equal(t, Finding{
+			Pointer: "#/paths/~1petOwners",
+			Code:    CodePathCasing,
+			Message: "path uses camelCase, but most paths use kebab-case",
+		}, report.Findings[3])
+	})
+
+	t.Run("with an operationId pattern", func(t *testing.T) {
+		t.Parallel()
+
+		report := an.NamingConventions(NamingOpts{OperationIDPattern: regexp.MustCompile(`^[a-zA-Z_]+$`)})
+
+		for _, finding := range report.Findings {
+			assert.NotEqual(t, CodeOperationIDNaming, finding.Code)
+		}
+	})
+}
+
+func TestNamingConventions_CaseOf(t *testing.T) {
+	t.Parallel()
+
+	for name, expected := range map[string]NamingCase{
+		"pets":        LowerCase,
+		"v2":          LowerCase,
+		"petId":       CamelCase,
+		"PetId":       PascalCase,
+		"pet_id":      SnakeCase,
+		"pet-id":      KebabCase,
+		"PET_ID":      UpperSnakeCase,
+		"ID":          UpperSnakeCase,
+		"Pet_id":      MixedCase,
+		"Pet-Id":      MixedCase,
+		"pet_id-name": MixedCase,
+		"pet id":      MixedCase,
+	} {
+		assert.Equalf(t, expected, caseOf(name), "unexpected case for %q", name)
+	}
+}
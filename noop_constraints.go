@@ -0,0 +1,237 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// CodeNoOpConstraint is the code for findings reporting constraints which have no effect
+const CodeNoOpConstraint = "noop-constraint"
+
+// keywords which only apply to some JSON types
+var typedKeywords = map[string][]string{
+	"minimum":          {"number", "integer"},
+	"maximum":          {"number", "integer"},
+	"exclusiveMinimum": {"number", "integer"},
+	"exclusiveMaximum": {"number", "integer"},
+	"multipleOf":       {"number", "integer"},
+	"minLength":        {"string"},
+	"maxLength":        {"string"},
+	"pattern":          {"string"},
+	"minItems":         {"array"},
+	"maxItems":         {"array"},
+	"uniqueItems":      {"array"},
+	"minProperties":    {"object"},
+	"maxProperties":    {"object"},
+	"required":         {"object"},
+}
+
+// NoOpConstraints reports validation keywords which have no effect given the type of the schema
+// or parameter they are attached to, such as uniqueItems on a string or pattern on an integer.
+//
+// Such constraints usually indicate copy-paste errors. Schemas without a declared type, as well as
+// $ref schemas, are not reported.
+//
+// Findings are sorted by pointer, and come with a fix removing the keyword.
+// Use FixNoOpConstraints to strip them all.
+func (s *Spec) NoOpConstraints() []Finding {
+	var findings []Finding
+
+	for _, key := range sortedKeys(s.allSchemas) {
+		sch := s.allSchemas[key].Schema
+		if sch == nil || sch.Ref.String() != "" {
+			continue
+		}
+
+		for _, keyword := range noOpSchemaKeywords(sch) {
+			findings = append(findings, noOpFinding(key, keyword, sch.Type))
+		}
+	}
+
+	// parameters are not indexed by the analyzer: walk the document (this does not mutate anything)
+	walkParameters(s.spec, func(pointer string, param *spec.Parameter) {
+		findings = append(findings, noOpSimpleSchemaFindings(pointer, param.In, &param.SimpleSchema, &param.CommonValidations)...)
+	})
+
+	sortFindings(findings)
+
+	return findings
+}
+
+func noOpFinding(pointer, keyword string, types spec.StringOrArray) Finding {
+	return Finding{
+		Pointer: pointer,
+		Code:    CodeNoOpConstraint,
+		Message: fmt.Sprintf("%s has no effect on type %s", keyword, strings.Join(types, ", ")),
+		Fix:     []PatchOperation{{Op: "remove", Path: patchPath(pointer, keyword)}},
+	}
+}
+
+func noOpSimpleSchemaFindings(pointer, in string, simple *spec.SimpleSchema, validations *spec.CommonValidations) []Finding {
+	if in == "body" || simple.Type == "" {
+		return nil
+	}
+
+	var findings []Finding
+	types := spec.StringOrArray{simple.Type}
+	for _, keyword := range noOpKeywords(types, commonValidationKeywords(validations)) {
+		findings = append(findings, noOpFinding(pointer, keyword, types))
+	}
+
+	if simple.Items != nil {
+		items := simple.Items
+		findings = append(findings,
+			noOpSimpleSchemaFindings(pointer+"/items", "", &items.SimpleSchema, &items.CommonValidations)...)
+	}
+
+	return findings
+}
+
+// noOpKeywords filters the keywords which do not apply to any of the given types
+func noOpKeywords(types spec.StringOrArray, keywords []string) []string {
+	if len(types) == 0 || types.Contains("") {
+		return nil
+	}
+
+	var result []string
+	for _, keyword := range keywords {
+		applies := false
+		for _, tpe := range typedKeywords[keyword] {
+			if types.Contains(tpe) {
+				applies = true
+
+				break
+			}
+		}
+
+		if !applies {
+			result = append(result, keyword)
+		}
+	}
+
+	return result
+}
+
+func noOpSchemaKeywords(sch *spec.Schema) []string {
+	return noOpKeywords(sch.Type, schemaKeywords(sch))
+}
+
+// schemaKeywords lists the type-specific keywords set on a schema
+func schemaKeywords(sch *spec.Schema) []string {
+	var keywords []string
+	add := func(set bool, keyword string) {
+		if set {
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	add(sch.Minimum != nil, "minimum")
+	add(sch.Maximum != nil, "maximum")
+	add(sch.ExclusiveMinimum, "exclusiveMinimum")
+	add(sch.ExclusiveMaximum, "exclusiveMaximum")
+	add(sch.MultipleOf != nil, "multipleOf")
+	add(sch.MinLength != nil, "minLength")
+	add(sch.MaxLength != nil, "maxLength")
+	add(sch.Pattern != "", "pattern")
+	add(sch.MinItems != nil, "minItems")
+	add(sch.MaxItems != nil, "maxItems")
+	add(sch.UniqueItems, "uniqueItems")
+	add(sch.MinProperties != nil, "minProperties")
+	add(sch.MaxProperties != nil, "maxProperties")
+	add(len(sch.Required) > 0, "required")
+
+	return keywords
+}
+
+// commonValidationKeywords lists the type-specific keywords set on a simple schema
+func commonValidationKeywords(v *spec.CommonValidations) []string {
+	return schemaKeywords(&spec.Schema{SchemaProps: spec.SchemaProps{
+		Minimum:          v.Minimum,
+		Maximum:          v.Maximum,
+		ExclusiveMinimum: v.ExclusiveMinimum,
+		ExclusiveMaximum: v.ExclusiveMaximum,
+		MultipleOf:       v.MultipleOf,
+		MinLength:        v.MinLength,
+		MaxLength:        v.MaxLength,
+		Pattern:          v.Pattern,
+		MinItems:         v.MinItems,
+		MaxItems:         v.MaxItems,
+		UniqueItems:      v.UniqueItems,
+	}})
+}
+
+func clearSchemaKeyword(sch *spec.Schema, keyword string) {
+	switch keyword {
+	case "minimum":
+		sch.Minimum = nil
+	case "maximum":
+		sch.Maximum = nil
+	case "exclusiveMinimum":
+		sch.ExclusiveMinimum = false
+	case "exclusiveMaximum":
+		sch.ExclusiveMaximum = false
+	case "multipleOf":
+		sch.MultipleOf = nil
+	case "minLength":
+		sch.MinLength = nil
+	case "maxLength":
+		sch.MaxLength = nil
+	case "pattern":
+		sch.Pattern = ""
+	case "minItems":
+		sch.MinItems = nil
+	case "maxItems":
+		sch.MaxItems = nil
+	case "uniqueItems":
+		sch.UniqueItems = false
+	case "minProperties":
+		sch.MinProperties = nil
+	case "maxProperties":
+		sch.MaxProperties = nil
+	case "required":
+		sch.Required = nil
+	}
+}
+
+func clearCommonValidationKeyword(v *spec.CommonValidations, keyword string) {
+	switch keyword {
+	case "minimum":
+		v.Minimum = nil
+	case "maximum":
+		v.Maximum = nil
+	case "exclusiveMinimum":
+		v.ExclusiveMinimum = false
+	case "exclusiveMaximum":
+		v.ExclusiveMaximum = false
+	case "multipleOf":
+		v.MultipleOf = nil
+	case "minLength":
+		v.MinLength = nil
+	case "maxLength":
+		v.MaxLength = nil
+	case "pattern":
+		v.Pattern = ""
+	case "minItems":
+		v.MinItems = nil
+	case "maxItems":
+		v.MaxItems = nil
+	case "uniqueItems":
+		v.UniqueItems = false
+	}
+}
+
+func fixNoOpSimpleSchema(in string, simple *spec.SimpleSchema, validations *spec.CommonValidations) {
+	if in == "body" || simple.Type == "" {
+		return
+	}
+
+	for _, keyword := range noOpKeywords(spec.StringOrArray{simple.Type}, commonValidationKeywords(validations)) {
+		clearCommonValidationKeyword(validations, keyword)
+	}
+
+	if simple.Items != nil {
+		fixNoOpSimpleSchema("", &simple.Items.SimpleSchema, &simple.Items.CommonValidations)
+	}
+}
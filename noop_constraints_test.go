@@ -0,0 +1,51 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoOpConstraints(t *testing.T) {
+	t.Parallel()
+
+	doc := antest.LoadOrFail(t, filepath.Join("fixtures", "constraints", "noop.yml"))
+	an := New(doc)
+
+	findings := an.NoOpConstraints()
+	pointers := make([]string, 0, len(findings))
+	for _, f := range findings {
+		assert.Equal(t, CodeNoOpConstraint, f.Code)
+		pointers = append(pointers, f.Pointer)
+	}
+
+	assert.Equal(t, []string{
+		"#/definitions/pet/properties/age",
+		"#/parameters/limit",
+		"#/paths/~1pets/get/parameters/0/items",
+		"#/paths/~1pets/get/responses/200/schema",
+	}, pointers)
+	assert.Contains(t, findings[0].Message, "maxLength")
+
+	t.Run("should strip no-op constraints", func(t *testing.T) {
+		FixNoOpConstraints(doc)
+
+		require.Empty(t, New(doc).NoOpConstraints())
+
+		age := doc.Definitions["pet"].Properties["age"]
+		assert.Nil(t, age.MaxLength)
+		require.NotNil(t, age.Minimum)
+
+		name := doc.Definitions["pet"].Properties["name"]
+		require.NotNil(t, name.MaxLength)
+		assert.Len(t, doc.Definitions["pet"].Required, 1)
+
+		assert.Empty(t, doc.Parameters["limit"].Pattern)
+		assert.True(t, doc.Paths.Paths["/pets"].Get.Parameters[0].UniqueItems)
+		assert.Nil(t, doc.Paths.Paths["/pets"].Get.Parameters[0].Items.Maximum)
+		assert.False(t, doc.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200].Schema.UniqueItems)
+	})
+}
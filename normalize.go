@@ -0,0 +1,187 @@
+package analysis
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// NormalizeOpts configures the normalization of a spec
+type NormalizeOpts struct {
+	KeepRefChains bool // When true, $ref's to definitions which are mere aliases of other definitions are retained
+	SortTags      bool // When true, sort tags by name. Tags are otherwise rendered in order by documentation tools
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// Normalize rewrites a spec in place into a canonical form, so that equivalent specs marshal identically.
+// This is useful before comparing or caching specs.
+//
+// Normalizing a spec:
+//   - lower cases media types (e.g. "Application/JSON; Charset=UTF-8" becomes "application/json; charset=UTF-8")
+//   - sorts and deduplicates collections which have the semantics of a set (consumes, produces, schemes,
+//     security scopes, required properties)
+//   - resolves trivial $ref indirections: a $ref to a definition which is nothing but a $ref to another definition
+//     is rewritten to point to the final definition. The intermediate definitions are retained
+//   - removes empty objects (e.g. empty externalDocs, contact, license, xml) and empty lists (e.g. "required": [])
+//
+// Maps are always marshaled with sorted keys, and need no normalization.
+func Normalize(sp *spec.Swagger, opts NormalizeOpts) {
+	if sp == nil {
+		return
+	}
+
+	sp.Consumes = normalizeSet(sp.Consumes, normalizeMediaType)
+	sp.Produces = normalizeSet(sp.Produces, normalizeMediaType)
+	sp.Schemes = normalizeSet(sp.Schemes, strings.ToLower)
+	sp.Security = normalizeSecurity(sp.Security)
+	sp.ExternalDocs = emptyToNil(sp.ExternalDocs)
+
+	if sp.Info != nil {
+		sp.Info.Contact = emptyToNil(sp.Info.Contact)
+		sp.Info.License = emptyToNil(sp.Info.License)
+	}
+
+	for i := range sp.Tags {
+		sp.Tags[i].ExternalDocs = emptyToNil(sp.Tags[i].ExternalDocs)
+	}
+
+	if opts.SortTags {
+		sort.SliceStable(sp.Tags, func(i, j int) bool { return sp.Tags[i].Name < sp.Tags[j].Name })
+	}
+
+	aliases := make(map[string][]string)
+	if !opts.KeepRefChains {
+		aliases = refChains(sp, func(schema spec.Schema) bool { return isTransparentRef(schema, false) })
+	}
+
+	walkSchemas(sp, func(_ string, schema *spec.Schema) {
+		normalizeSchema(schema, aliases)
+	})
+
+	for name, resp := range sp.Responses {
+		normalizeResponse(&resp)
+		sp.Responses[name] = resp
+	}
+
+	walkOperations(sp, func(_ string, op *spec.Operation) {
+		op.Consumes = normalizeSet(op.Consumes, normalizeMediaType)
+		op.Produces = normalizeSet(op.Produces, normalizeMediaType)
+		op.Schemes = normalizeSet(op.Schemes, strings.ToLower)
+		op.Security = normalizeSecurity(op.Security)
+		op.ExternalDocs = emptyToNil(op.ExternalDocs)
+
+		if op.Responses == nil {
+			return
+		}
+
+		if op.Responses.Default != nil {
+			normalizeResponse(op.Responses.Default)
+		}
+
+		for code, resp := range op.Responses.StatusCodeResponses {
+			normalizeResponse(&resp)
+			op.Responses.StatusCodeResponses[code] = resp
+		}
+	})
+}
+
+func normalizeSchema(schema *spec.Schema, aliases map[string][]string) {
+	schema.Required = normalizeSet(schema.Required, nil)
+	schema.ExternalDocs = emptyToNil(schema.ExternalDocs)
+	schema.XML = emptyToNil(schema.XML)
+
+	if len(schema.Type) == 0 {
+		schema.Type = nil
+	}
+
+	if len(schema.Enum) == 0 {
+		schema.Enum = nil
+	}
+
+	if chain, isAlias := aliases[schema.Ref.String()]; isAlias {
+		schema.Ref = spec.MustCreateRef(chain[len(chain)-1])
+	}
+}
+
+func normalizeResponse(resp *spec.Response) {
+	if len(resp.Examples) == 0 {
+		return
+	}
+
+	examples := make(map[string]interface{}, len(resp.Examples))
+	for mediaType, example := range resp.Examples {
+		examples[normalizeMediaType(mediaType)] = example
+	}
+	resp.Examples = examples
+}
+
+// normalizeSecurity sorts the scopes of security requirements
+func normalizeSecurity(requirements []map[string][]string) []map[string][]string {
+	for _, requirement := range requirements {
+		for name, scopes := range requirement {
+			requirement[name] = normalizeSet(scopes, nil)
+			if requirement[name] == nil {
+				requirement[name] = []string{}
+			}
+		}
+	}
+
+	return requirements
+}
+
+// normalizeSet normalizes, sorts and deduplicates a list of strings. An empty list yields nil.
+func normalizeSet(values []string, normalize func(string) string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if normalize != nil {
+			value = normalize(value)
+		}
+
+		if seen[value] {
+			continue
+		}
+
+		seen[value] = true
+		result = append(result, value)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// normalizeMediaType lower cases the type, subtype and parameter names of a media type
+func normalizeMediaType(mediaType string) string {
+	parts := strings.Split(mediaType, ";")
+	for i, part := range parts {
+		name, value, hasValue := strings.Cut(strings.TrimSpace(part), "=")
+		parts[i] = strings.ToLower(strings.TrimSpace(name))
+		if hasValue {
+			parts[i] += "=" + strings.TrimSpace(value)
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// emptyToNil returns nil when an object marshals to an empty JSON object
+func emptyToNil[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+
+	buf, err := json.Marshal(value)
+	if err != nil || string(buf) == "{}" {
+		return nil
+	}
+
+	return value
+}
@@ -0,0 +1,80 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "normalize.yml"))
+	Normalize(sp, NormalizeOpts{SortTags: true})
+
+	assert.Equal(t, []string{"http", "https"}, sp.Schemes)
+	assert.Equal(t, []string{"application/json; charset=UTF-8"}, sp.Consumes)
+	assert.Equal(t, []string{"application/xml", "text/plain"}, sp.Produces)
+	assert.Equal(t, []string{"read", "write"}, sp.Security[0]["oauth"])
+	assert.Nil(t, sp.Info.Contact)
+	assert.Equal(t, "pets", sp.Tags[0].Name)
+	assert.Nil(t, sp.Tags[0].ExternalDocs)
+
+	op := sp.Paths.Paths["/pets"].Get
+	assert.Equal(t, []string{"application/json"}, op.Produces)
+	assert.Nil(t, op.ExternalDocs)
+	assert.Contains(t, op.Responses.StatusCodeResponses[200].Examples, "application/json")
+	assert.Equal(t, "#/definitions/pet", op.Responses.StatusCodeResponses[200].Schema.Items.Schema.Ref.String())
+
+	pet := sp.Definitions["pet"]
+	assert.Equal(t, []string{"id", "name"}, pet.Required)
+	assert.Nil(t, pet.Properties["name"].XML)
+
+	owner := sp.Definitions["owner"]
+	assert.Nil(t, owner.Required)
+	assert.Equal(t, "#/definitions/documentedAlias", schemaRef(owner.Properties["pet"]))
+	assert.Equal(t, "#/definitions/pet", schemaRef(owner.Properties["other"]))
+
+	// intermediate definitions are retained, circular aliases are left alone
+	assert.Equal(t, "#/definitions/pet", schemaRef(sp.Definitions["petAlias"]))
+	assert.Contains(t, sp.Definitions, "petAliasAlias")
+	assert.Equal(t, "#/definitions/loopB", schemaRef(sp.Definitions["loopA"]))
+
+	// normalizing is idempotent
+	normalized := antest.AsJSON(t, sp)
+	Normalize(sp, NormalizeOpts{SortTags: true})
+	assert.JSONEq(t, normalized, antest.AsJSON(t, sp))
+}
+
+func TestNormalize_Options(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "normalize.yml"))
+	Normalize(sp, NormalizeOpts{KeepRefChains: true})
+
+	assert.Equal(t, "stores", sp.Tags[0].Name)
+	assert.Equal(t, "#/definitions/petAliasAlias", schemaRef(sp.Definitions["petAlias"]))
+
+	require.NotPanics(t, func() { Normalize(nil, NormalizeOpts{}) })
+}
+
+func TestNormalize_MediaType(t *testing.T) {
+	t.Parallel()
+
+	for in, expected := range map[string]string{
+		"application/json":                        "application/json",
+		"Application/JSON":                        "application/json",
+		"Text/Plain ;Charset=UTF-8":               "text/plain; charset=UTF-8",
+		"multipart/Form-Data; Boundary=SomeThing": "multipart/form-data; boundary=SomeThing",
+	} {
+		assert.Equal(t, expected, normalizeMediaType(in))
+	}
+}
+
+func schemaRef(schema spec.Schema) string {
+	return schema.Ref.String()
+}
@@ -0,0 +1,208 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// Codes for findings about operation ids
+const (
+	CodeMissingOperationID   = "missing-operation-id"
+	CodeDuplicateOperationID = "duplicate-operation-id"
+)
+
+// OperationIDStyle is the naming convention used to suggest operation ids
+type OperationIDStyle uint8
+
+// Supported styles for operation ids
+const (
+	// CamelCaseOperationIDs yields ids such as getPetById
+	CamelCaseOperationIDs OperationIDStyle = iota
+	// PascalCaseOperationIDs yields ids such as GetPetById
+	PascalCaseOperationIDs
+	// SnakeCaseOperationIDs yields ids such as get_pet_by_id
+	SnakeCaseOperationIDs
+)
+
+// OperationIDIssues reports operations without an operationId, and operations which share
+// their operationId with some other operation.
+//
+// Each finding points to the offending operation (e.g. "#/paths/~1pets/get").
+// Missing operationIds come with a fix adding the camel-cased id suggested by SuggestOperationIDs.
+// Findings are sorted by pointer.
+func (s *Spec) OperationIDIssues() []Finding {
+	var findings []Finding
+	seen := make(map[string][]string)
+	suggestions := s.SuggestOperationIDs(CamelCaseOperationIDs)
+
+	for _, key := range s.sortedOperationKeys() {
+		pointer := key.pointer()
+		op, _ := s.OperationFor(key.Method, key.Path)
+		if op.ID == "" {
+			findings = append(findings, Finding{
+				Pointer: pointer,
+				Code:    CodeMissingOperationID,
+				Message: "operation has no operationId",
+				Fix:     []PatchOperation{{Op: "add", Path: patchPath(pointer, "operationId"), Value: suggestions[key.String()]}},
+			})
+
+			continue
+		}
+
+		seen[op.ID] = append(seen[op.ID], pointer)
+	}
+
+	for id, pointers := range seen {
+		if len(pointers) < 2 {
+			continue
+		}
+
+		for _, pointer := range pointers {
+			findings = append(findings, Finding{
+				Pointer: pointer,
+				Code:    CodeDuplicateOperationID,
+				Message: fmt.Sprintf("operationId %q is used by %d operations", id, len(pointers)),
+			})
+		}
+	}
+
+	sortFindings(findings)
+
+	return findings
+}
+
+// SuggestOperationIDs produces operation ids for all operations which don't have one.
+//
+// The result is keyed by method and path, as in "GET /pets/{id}" (see OperationMethodPaths()).
+//
+// Suggestions are deterministic: they are derived from the method and path of the operation,
+// e.g. "GET /pets/{id}" yields "getPetById" with the CamelCaseOperationIDs style.
+// Suggested ids never collide with existing operation ids, nor with each other: a numeric suffix is added
+// whenever needed.
+func (s *Spec) SuggestOperationIDs(style OperationIDStyle) map[string]string {
+	taken := make(map[string]bool)
+	var missing []string
+
+	s.EachOperation(func(method, path string, op *spec.Operation) bool {
+		if op.ID != "" {
+			taken[op.ID] = true
+		} else {
+			missing = append(missing, method+" "+path)
+		}
+
+		return true
+	})
+	sort.Strings(missing)
+
+	result := make(map[string]string, len(missing))
+	for _, key := range missing {
+		method, path, _ := strings.Cut(key, " ")
+		base := operationIDFromMethodPath(method, path, style)
+		id := base
+		for i := 2; taken[id]; i++ {
+			id = fmt.Sprintf("%s%d", base, i)
+			if style == SnakeCaseOperationIDs {
+				id = fmt.Sprintf("%s_%d", base, i)
+			}
+		}
+
+		taken[id] = true
+		result[key] = id
+	}
+
+	return result
+}
+
+// operationIDFromMethodPath builds an operation id from a method and a path template.
+//
+// Literal path segments followed by a parameter are singularized, and path parameters are
+// appended as "By{param}And{param}...".
+func operationIDFromMethodPath(method, path string, style OperationIDStyle) string {
+	words := []string{strings.ToLower(method)}
+	var params []string
+
+	segments := strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+	for i, segment := range segments {
+		if isPathParam(segment) {
+			params = append(params, strings.Trim(segment, "{}"))
+
+			continue
+		}
+
+		if i+1 < len(segments) && isPathParam(segments[i+1]) {
+			segment = singularize(segment)
+		}
+
+		words = append(words, splitWords(segment)...)
+	}
+
+	if len(segments) == 0 {
+		words = append(words, "root")
+	}
+
+	for i, param := range params {
+		if i == 0 {
+			words = append(words, "by")
+		} else {
+			words = append(words, "and")
+		}
+
+		words = append(words, splitWords(param)...)
+	}
+
+	return joinWords(words, style)
+}
+
+func isPathParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// splitWords splits an identifier (e.g. "petId", "pet-store", "pet_store") into lower case words
+func splitWords(in string) []string {
+	return strings.FieldsFunc(swag.ToFileName(in), func(r rune) bool { return r == '_' })
+}
+
+func joinWords(words []string, style OperationIDStyle) string {
+	if style == SnakeCaseOperationIDs {
+		return strings.Join(words, "_")
+	}
+
+	var b strings.Builder
+	for i, word := range words {
+		if word == "" {
+			continue
+		}
+
+		if i == 0 && style == CamelCaseOperationIDs {
+			b.WriteString(word)
+
+			continue
+		}
+
+		b.WriteString(strings.ToUpper(word[:1]))
+		b.WriteString(word[1:])
+	}
+
+	return b.String()
+}
+
+// singularize is a naive english singularizer for resource names in paths
+func singularize(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"):
+		return word
+	case strings.HasSuffix(lower, "s") && len(word) > 1:
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
@@ -0,0 +1,78 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationIDs_Issues(t *testing.T) {
+	t.Parallel()
+
+	doc := antest.LoadOrFail(t, filepath.Join("fixtures", "operations", "fixture-operation-ids.yaml"))
+	an := New(doc)
+
+	findings := an.OperationIDIssues()
+	require.Len(t, findings, 6)
+
+	issues := make(map[string]string, len(findings))
+	for _, f := range findings {
+		issues[f.Pointer] = f.Code
+	}
+
+	assert.Equal(t, map[string]string{
+		"#/paths/~1/head": CodeMissingOperationID,
+		"#/paths/~1categories~1{id}~1pets~1{petId}/get": CodeMissingOperationID,
+		"#/paths/~1pets/get":                            CodeMissingOperationID,
+		"#/paths/~1pets/post":                           CodeDuplicateOperationID,
+		"#/paths/~1pets~1{petId}/delete":                CodeDuplicateOperationID,
+		"#/paths/~1pets~1{petId}/get":                   CodeMissingOperationID,
+	}, issues)
+}
+
+func TestOperationIDs_Suggest(t *testing.T) {
+	t.Parallel()
+
+	doc := antest.LoadOrFail(t, filepath.Join("fixtures", "operations", "fixture-operation-ids.yaml"))
+	an := New(doc)
+
+	assert.Equal(t, map[string]string{
+		"GET /categories/{id}/pets/{petId}": "getCategoryPetByIdAndPetId",
+		"GET /pets":                         "getPets2",
+		"GET /pets/{petId}":                 "getPetByPetId",
+		"HEAD /":                            "headRoot",
+	}, an.SuggestOperationIDs(CamelCaseOperationIDs))
+
+	assert.Equal(t, map[string]string{
+		"GET /categories/{id}/pets/{petId}": "GetCategoryPetByIdAndPetId",
+		"GET /pets":                         "GetPets",
+		"GET /pets/{petId}":                 "GetPetByPetId",
+		"HEAD /":                            "HeadRoot",
+	}, an.SuggestOperationIDs(PascalCaseOperationIDs))
+
+	assert.Equal(t, map[string]string{
+		"GET /categories/{id}/pets/{petId}": "get_category_pet_by_id_and_pet_id",
+		"GET /pets":                         "get_pets",
+		"GET /pets/{petId}":                 "get_pet_by_pet_id",
+		"HEAD /":                            "head_root",
+	}, an.SuggestOperationIDs(SnakeCaseOperationIDs))
+}
+
+func TestOperationIDs_Singularize(t *testing.T) {
+	t.Parallel()
+
+	for input, expected := range map[string]string{
+		"pets":       "pet",
+		"categories": "category",
+		"addresses":  "address",
+		"boxes":      "box",
+		"status":     "status",
+		"class":      "class",
+		"data":       "data",
+	} {
+		assert.Equal(t, expected, singularize(input))
+	}
+}
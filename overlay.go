@@ -0,0 +1,300 @@
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/analysis/internal/jsonpath"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// Overlay is an OpenAPI Overlay document (https://spec.openapis.org/overlay/v1.0.0.html).
+//
+// An overlay is an ordered list of actions, each targeting nodes of a document with a JSONPath expression.
+type Overlay struct {
+	Overlay string          `json:"overlay"`
+	Info    OverlayInfo     `json:"info"`
+	Extends string          `json:"extends,omitempty"`
+	Actions []OverlayAction `json:"actions"`
+}
+
+// OverlayInfo describes an overlay document
+type OverlayInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OverlayAction updates or removes the nodes selected by a JSONPath expression
+type OverlayAction struct {
+	Target      string      `json:"target"`
+	Description string      `json:"description,omitempty"`
+	Update      interface{} `json:"update,omitempty"`
+	Remove      bool        `json:"remove,omitempty"`
+}
+
+// OverlayActionResult reports the outcome of an overlay action
+type OverlayActionResult struct {
+	Index       int      // the position of the action in the overlay
+	Target      string   // the JSONPath target of the action
+	Description string   // the description of the action, if any
+	Pointers    []string // JSON pointers to the nodes selected by the target, e.g. "#/paths/~1pets/get"
+	Err         error    // the reason why the action failed, nil if it was applied
+}
+
+// OverlayResult lists the overlay actions which have been applied, and those which failed
+type OverlayResult struct {
+	Applied []OverlayActionResult
+	Failed  []OverlayActionResult
+}
+
+// ParseOverlay reads an overlay document, in JSON or YAML format
+func ParseOverlay(data []byte) (*Overlay, error) {
+	doc, err := swag.BytesToYAMLDoc(data)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := swag.YAMLToJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlay Overlay
+	if err := json.Unmarshal(buf, &overlay); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(overlay.Overlay, "1.") {
+		return nil, fmt.Errorf("unsupported overlay version %q", overlay.Overlay)
+	}
+
+	return &overlay, nil
+}
+
+// ApplyOverlay applies the actions of an overlay to a spec, in order.
+// Each action applies to the document resulting from the previous actions.
+//
+// An update action deep merges its value into each selected object: members of objects are merged recursively,
+// other values are replaced. When the selected node is an array, the value is appended to it.
+// A remove action deletes each selected node from its parent object or array.
+//
+// An action fails when its target is not a valid JSONPath expression, when it does not select any node,
+// or when it updates a scalar value. Failed actions leave the document unchanged and do not prevent
+// subsequent actions from being applied.
+//
+// The spec is modified in place, unless an error is returned: this happens when the updated
+// document is no longer a valid swagger document.
+func ApplyOverlay(sp *spec.Swagger, overlay *Overlay) (OverlayResult, error) {
+	var result OverlayResult
+	if overlay == nil {
+		return result, nil
+	}
+
+	doc, err := toGenericJSON(sp)
+	if err != nil {
+		return result, err
+	}
+
+	for i, action := range overlay.Actions {
+		outcome := OverlayActionResult{Index: i, Target: action.Target, Description: action.Description}
+		doc, outcome.Pointers, outcome.Err = applyOverlayAction(doc, action)
+
+		if outcome.Err != nil {
+			result.Failed = append(result.Failed, outcome)
+
+			continue
+		}
+
+		result.Applied = append(result.Applied, outcome)
+	}
+
+	buf, err := json.Marshal(doc)
+	if err != nil {
+		return result, err
+	}
+
+	var updated spec.Swagger
+	if err := json.Unmarshal(buf, &updated); err != nil {
+		return result, err
+	}
+
+	*sp = updated
+
+	return result, nil
+}
+
+// applyOverlayAction applies an action to a generic JSON document, and returns the updated document
+// with the pointers to the selected nodes
+func applyOverlayAction(doc interface{}, action OverlayAction) (interface{}, []string, error) {
+	path, err := jsonpath.Compile(action.Target)
+	if err != nil {
+		return doc, nil, err
+	}
+
+	nodes := path.Find(doc)
+	if len(nodes) == 0 {
+		return doc, nil, errors.New("target does not select any node")
+	}
+
+	pointers := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		pointers = append(pointers, "#"+node.Pointer())
+	}
+
+	switch {
+	case action.Remove:
+		for _, node := range nodes {
+			if len(node.Location) == 0 {
+				return doc, pointers, errors.New("the root of the document cannot be removed")
+			}
+		}
+
+		// remove deeper and later nodes first, so the locations of the other nodes remain valid
+		sort.SliceStable(nodes, func(i, j int) bool { return compareLocations(nodes[i].Location, nodes[j].Location) > 0 })
+		for i, node := range nodes {
+			if i > 0 && compareLocations(node.Location, nodes[i-1].Location) == 0 {
+				continue
+			}
+
+			doc = removeAt(doc, node.Location)
+		}
+
+		return doc, pointers, nil
+
+	case action.Update != nil:
+		for _, node := range nodes {
+			switch node.Value.(type) {
+			case map[string]interface{}, []interface{}:
+			default:
+				return doc, pointers, fmt.Errorf("cannot update the scalar value at %s", "#"+node.Pointer())
+			}
+		}
+
+		for _, node := range nodes {
+			if target, isArray := node.Value.([]interface{}); isArray {
+				doc = setAt(doc, node.Location, append(target, deepCopyJSON(action.Update)))
+
+				continue
+			}
+
+			doc = setAt(doc, node.Location, mergeJSON(node.Value, action.Update))
+		}
+
+		return doc, pointers, nil
+
+	default:
+		return doc, pointers, errors.New("action neither updates nor removes its target")
+	}
+}
+
+// mergeJSON deep merges a value into a generic JSON value
+func mergeJSON(target, update interface{}) interface{} {
+	targetMap, isMap := target.(map[string]interface{})
+	updateMap, isUpdateMap := update.(map[string]interface{})
+	if !isMap || !isUpdateMap {
+		return deepCopyJSON(update)
+	}
+
+	for k, v := range updateMap {
+		if existing, ok := targetMap[k]; ok {
+			targetMap[k] = mergeJSON(existing, v)
+
+			continue
+		}
+
+		targetMap[k] = deepCopyJSON(v)
+	}
+
+	return targetMap
+}
+
+func deepCopyJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			clone[k] = deepCopyJSON(elem)
+		}
+
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, elem := range v {
+			clone[i] = deepCopyJSON(elem)
+		}
+
+		return clone
+	default:
+		return v
+	}
+}
+
+// setAt replaces the value at some location in a generic JSON document, and returns the updated document
+func setAt(doc interface{}, location []interface{}, value interface{}) interface{} {
+	if len(location) == 0 {
+		return value
+	}
+
+	switch parent := doc.(type) {
+	case map[string]interface{}:
+		key := location[0].(string)
+		parent[key] = setAt(parent[key], location[1:], value)
+	case []interface{}:
+		index := location[0].(int)
+		parent[index] = setAt(parent[index], location[1:], value)
+	}
+
+	return doc
+}
+
+// removeAt removes the value at some (non-root) location in a generic JSON document, and returns the updated document
+func removeAt(doc interface{}, location []interface{}) interface{} {
+	if len(location) > 1 {
+		switch parent := doc.(type) {
+		case map[string]interface{}:
+			key := location[0].(string)
+			parent[key] = removeAt(parent[key], location[1:])
+		case []interface{}:
+			index := location[0].(int)
+			parent[index] = removeAt(parent[index], location[1:])
+		}
+
+		return doc
+	}
+
+	switch parent := doc.(type) {
+	case map[string]interface{}:
+		delete(parent, location[0].(string))
+	case []interface{}:
+		index := location[0].(int)
+		return append(parent[:index:index], parent[index+1:]...)
+	}
+
+	return doc
+}
+
+// compareLocations orders locations in document order, parents first
+func compareLocations(a, b []interface{}) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch x := a[i].(type) {
+		case int:
+			if y, ok := b[i].(int); ok && x != y {
+				if x < y {
+					return -1
+				}
+
+				return 1
+			}
+		case string:
+			if y, ok := b[i].(string); ok && x != y {
+				return strings.Compare(x, y)
+			}
+		}
+	}
+
+	return len(a) - len(b)
+}
@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyOverlay(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "overlay", "petstore.yaml"))
+	data, err := os.ReadFile(filepath.Join("fixtures", "overlay", "overlay.yaml"))
+	require.NoError(t, err)
+
+	overlay, err := ParseOverlay(data)
+	require.NoError(t, err)
+	assert.Equal(t, "public petstore", overlay.Info.Title)
+	require.Len(t, overlay.Actions, 8)
+
+	result, err := ApplyOverlay(sp, overlay)
+	require.NoError(t, err)
+
+	require.Len(t, result.Applied, 5)
+	assert.Equal(t, OverlayActionResult{
+		Index:       1,
+		Target:      "$.paths.*[?(@['x-internal'] == true)]",
+		Description: "hide internal operations",
+		Pointers:    []string{"#/paths/~1pets/post"},
+	}, result.Applied[1])
+	assert.Equal(t, []string{"#/paths/~1pets/get/parameters/1"}, result.Applied[2].Pointers)
+
+	require.Len(t, result.Failed, 3)
+	assert.Equal(t, 5, result.Failed[0].Index)
+	assert.EqualError(t, result.Failed[0].Err, "target does not select any node")
+	assert.Equal(t, 6, result.Failed[1].Index)
+	assert.Equal(t, []string{"#/info/title"}, result.Failed[1].Pointers)
+	assert.Error(t, result.Failed[2].Err)
+
+	assert.Equal(t, "public petstore", sp.Info.Title)
+	assert.Equal(t, "public", sp.Info.Extensions["x-audience"])
+	assert.Equal(t, []string{"pets", "stores"}, []string{sp.Tags[0].Name, sp.Tags[1].Name})
+
+	pathItem := sp.Paths.Paths["/pets"]
+	assert.Nil(t, pathItem.Post)
+	require.NotNil(t, pathItem.Get)
+	require.Len(t, pathItem.Get.Parameters, 1)
+	assert.Equal(t, "limit", pathItem.Get.Parameters[0].Name)
+
+	assert.Contains(t, sp.Definitions["pet"].Properties, "name")
+	assert.Contains(t, sp.Definitions["pet"].Properties, "tag")
+}
+
+func TestApplyOverlay_Edge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid overlay documents", func(t *testing.T) {
+		_, err := ParseOverlay([]byte(`{"overlay": "2.0.0", "actions": []}`))
+		require.Error(t, err)
+
+		_, err = ParseOverlay([]byte(`overlay: [`))
+		require.Error(t, err)
+	})
+
+	t.Run("removing the root fails", func(t *testing.T) {
+		sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Swagger: "2.0"}}
+		result, err := ApplyOverlay(sp, &Overlay{Actions: []OverlayAction{{Target: "$", Remove: true}}})
+		require.NoError(t, err)
+		require.Len(t, result.Failed, 1)
+		assert.Equal(t, "2.0", sp.Swagger)
+	})
+
+	t.Run("removing array elements", func(t *testing.T) {
+		sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Schemes: []string{"http", "https", "ws", "wss"}}}
+		result, err := ApplyOverlay(sp, &Overlay{Actions: []OverlayAction{
+			{Target: "$.schemes[0,2,0]", Remove: true},
+		}})
+		require.NoError(t, err)
+		require.Len(t, result.Applied, 1)
+		assert.Equal(t, []string{"https", "wss"}, sp.Schemes)
+	})
+
+	t.Run("invalid result", func(t *testing.T) {
+		sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Swagger: "2.0"}}
+		_, err := ApplyOverlay(sp, &Overlay{Actions: []OverlayAction{
+			{Target: "$", Update: map[string]interface{}{"paths": []interface{}{"not", "an", "object"}}},
+		}})
+		require.Error(t, err)
+		assert.Equal(t, "2.0", sp.Swagger)
+	})
+
+	t.Run("nil overlay", func(t *testing.T) {
+		result, err := ApplyOverlay(&spec.Swagger{}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, result.Applied)
+	})
+}
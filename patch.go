@@ -0,0 +1,433 @@
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// top-level sections of a swagger document indexed by the analyzer, keyed by pointer prefix
+var indexedSections = []string{"paths", "parameters", "responses", "definitions"}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) to the analyzed document, then refreshes the analysis.
+//
+// Patch paths are JSON pointers relative to the root of the document, e.g. "/paths/~1pets/get/summary".
+// The fixes carried by findings are valid patches.
+//
+// The patch is atomic: if any operation fails, the document is left unchanged and an error is returned.
+//
+// The underlying document is modified in place. Only the indexes of the top-level sections touched
+// by the patch are rebuilt: members of other sections keep their identity.
+func (s *Spec) ApplyPatch(ops []PatchOperation) error {
+	if err := s.mutable(); err != nil {
+		return err
+	}
+
+	doc, err := toGenericJSON(s.spec)
+	if err != nil {
+		return err
+	}
+
+	touched := make(map[string]bool)
+	for i, op := range ops {
+		doc, err = applyPatchOperation(doc, op)
+		if err != nil {
+			return fmt.Errorf("patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+
+		touched[patchSection(op.Path)] = true
+		if op.Op == "move" {
+			touched[patchSection(op.From)] = true
+		}
+	}
+
+	return s.update(doc, touched)
+}
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7386) to the analyzed document, then refreshes the analysis.
+//
+// The patch may be any value which marshals to JSON, such as a json.RawMessage or a map[string]interface{}.
+// Null members of the patch remove the corresponding members from the document.
+//
+// The underlying document is modified in place. Only the indexes of the top-level sections touched
+// by the patch are rebuilt: members of other sections keep their identity.
+func (s *Spec) ApplyMergePatch(patch interface{}) error {
+	if err := s.mutable(); err != nil {
+		return err
+	}
+
+	doc, err := toGenericJSON(s.spec)
+	if err != nil {
+		return err
+	}
+
+	generic, err := asGenericJSON(patch)
+	if err != nil {
+		return err
+	}
+
+	touched := make(map[string]bool)
+	if members, isObject := generic.(map[string]interface{}); isObject {
+		for k := range members {
+			touched[k] = true
+		}
+	} else {
+		touched[""] = true
+	}
+
+	return s.update(mergePatch(doc, generic), touched)
+}
+
+// update replaces the analyzed document by a patched generic document, then refreshes the indexes
+// of the touched top-level sections and runs the eager analysis plugins again.
+//
+// The empty section stands for the root of the document.
+func (s *Spec) update(doc interface{}, touched map[string]bool) error {
+	buf, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	var updated spec.Swagger
+	if err := json.Unmarshal(buf, &updated); err != nil {
+		return err
+	}
+
+	if touched[""] {
+		*s.spec = updated
+		s.reload()
+		s.runEagerPlugins()
+
+		return nil
+	}
+
+	// retain the untouched sections as they are
+	if !touched["paths"] {
+		updated.Paths = s.spec.Paths
+	}
+	if !touched["parameters"] {
+		updated.Parameters = s.spec.Parameters
+	}
+	if !touched["responses"] {
+		updated.Responses = s.spec.Responses
+	}
+	if !touched["definitions"] {
+		updated.Definitions = s.spec.Definitions
+	}
+	*s.spec = updated
+
+	s.refresh(touched)
+	s.runEagerPlugins()
+
+	return nil
+}
+
+// refresh rebuilds the indexes of some top-level sections of the document
+func (s *Spec) refresh(touched map[string]bool) {
+	for _, section := range indexedSections {
+		if touched[section] {
+			s.forget("#/" + section + "/")
+		}
+	}
+
+	if touched["paths"] {
+		s.operations = make(map[string]map[string]*spec.Operation, 150)
+	}
+
+	if touched["paths"] || touched["consumes"] || touched["produces"] || touched["security"] {
+		// media types and security requirements are aggregated from the top level and from operations
+		s.consumes = make(map[string]struct{}, 150)
+		s.produces = make(map[string]struct{}, 150)
+		s.authSchemes = make(map[string]struct{}, 150)
+		s.initializeGlobals()
+
+		if !touched["paths"] {
+			for _, operations := range s.operations {
+				for _, op := range operations {
+					s.aggregateOperation(op)
+				}
+			}
+		}
+	}
+
+	if touched["paths"] {
+		s.initializePaths()
+	}
+	if touched["parameters"] {
+		s.initializeParameters()
+	}
+	if touched["responses"] {
+		s.initializeResponses()
+	}
+	if touched["definitions"] {
+		s.initializeDefinitions()
+	}
+
+	// plugin results may depend on any part of the spec
+	s.plugins = newPluginCache()
+}
+
+// forget removes all the indexed entries located under some pointer prefix
+func (s *Spec) forget(prefix string) {
+	forgetPrefix(s.allSchemas, prefix)
+	forgetPrefix(s.allOfs, prefix)
+
+	for _, index := range []map[string]spec.Ref{
+		s.references.schemas, s.references.responses, s.references.parameters, s.references.items,
+		s.references.headerItems, s.references.parameterItems, s.references.allRefs, s.references.pathItems,
+	} {
+		forgetPrefix(index, prefix)
+	}
+
+	for _, index := range []map[string]string{
+		s.patterns.parameters, s.patterns.headers, s.patterns.items, s.patterns.schemas, s.patterns.allPatterns,
+	} {
+		forgetPrefix(index, prefix)
+	}
+
+	for _, index := range []map[string][]interface{}{
+		s.enums.parameters, s.enums.headers, s.enums.items, s.enums.schemas, s.enums.allEnums,
+	} {
+		forgetPrefix(index, prefix)
+	}
+
+	forgetPrefix(s.defaults.allDefaults, prefix)
+}
+
+func forgetPrefix[T any](index map[string]T, prefix string) {
+	for key := range index {
+		if strings.HasPrefix(key, prefix) {
+			delete(index, key)
+		}
+	}
+}
+
+// patchSection yields the top-level section of the document targeted by a patch path,
+// or the empty string for the root of the document
+func patchSection(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	section, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+	return jsonpointer.Unescape(section)
+}
+
+// applyPatchOperation applies a single JSON Patch operation to a generic JSON document
+func applyPatchOperation(doc interface{}, op PatchOperation) (interface{}, error) {
+	tokens, err := pointerTokens(op.Path)
+	if err != nil {
+		return doc, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		value, err := asGenericJSON(op.Value)
+		if err != nil {
+			return doc, err
+		}
+
+		if op.Op == "add" {
+			return patchAdd(doc, tokens, value)
+		}
+
+		location, current, err := locate(doc, tokens)
+		if err != nil {
+			return doc, err
+		}
+
+		if op.Op == "test" {
+			if !reflect.DeepEqual(current, value) {
+				return doc, errors.New("test failed")
+			}
+
+			return doc, nil
+		}
+
+		return setAt(doc, location, value), nil
+
+	case "remove":
+		if len(tokens) == 0 {
+			return doc, errors.New("the root of the document cannot be removed")
+		}
+
+		location, _, err := locate(doc, tokens)
+		if err != nil {
+			return doc, err
+		}
+
+		return removeAt(doc, location), nil
+
+	case "move", "copy":
+		from, err := pointerTokens(op.From)
+		if err != nil {
+			return doc, err
+		}
+
+		location, value, err := locate(doc, from)
+		if err != nil {
+			return doc, err
+		}
+
+		if op.Op == "copy" {
+			return patchAdd(doc, tokens, deepCopyJSON(value))
+		}
+
+		if op.Path == op.From {
+			return doc, nil
+		}
+
+		if len(from) == 0 || strings.HasPrefix(op.Path, op.From+"/") {
+			return doc, errors.New("a value cannot be moved into one of its children")
+		}
+
+		return patchAdd(removeAt(doc, location), tokens, value)
+
+	default:
+		return doc, fmt.Errorf("unsupported operation %q", op.Op)
+	}
+}
+
+// patchAdd adds a value to an object, inserts it into an array, or replaces the whole document
+func patchAdd(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	location, parent, err := locate(doc, tokens[:len(tokens)-1])
+	if err != nil {
+		return doc, err
+	}
+
+	last := tokens[len(tokens)-1]
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		container[last] = value
+
+		return doc, nil
+
+	case []interface{}:
+		index := len(container)
+		if last != "-" {
+			index, err = strconv.Atoi(last)
+			if err != nil || index < 0 || index > len(container) {
+				return doc, fmt.Errorf("invalid array index %q", last)
+			}
+		}
+
+		inserted := make([]interface{}, 0, len(container)+1)
+		inserted = append(inserted, container[:index]...)
+		inserted = append(inserted, value)
+		inserted = append(inserted, container[index:]...)
+
+		return setAt(doc, location, inserted), nil
+
+	default:
+		return doc, errors.New("the parent of the target location is not a container")
+	}
+}
+
+// locate finds the value designated by JSON pointer tokens in a generic JSON document.
+//
+// It returns the location of this value, made of keys and array indices.
+func locate(doc interface{}, tokens []string) ([]interface{}, interface{}, error) {
+	location := make([]interface{}, 0, len(tokens))
+	current := doc
+
+	for _, token := range tokens {
+		switch container := current.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, nil, fmt.Errorf("no member %q at /%s", token, joinTokens(location))
+			}
+
+			location = append(location, token)
+			current = value
+
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil, nil, fmt.Errorf("invalid array index %q at /%s", token, joinTokens(location))
+			}
+
+			location = append(location, index)
+			current = container[index]
+
+		default:
+			return nil, nil, fmt.Errorf("no container at /%s", joinTokens(location))
+		}
+	}
+
+	return location, current, nil
+}
+
+func joinTokens(location []interface{}) string {
+	parts := make([]string, 0, len(location))
+	for _, token := range location {
+		switch t := token.(type) {
+		case string:
+			parts = append(parts, jsonpointer.Escape(t))
+		case int:
+			parts = append(parts, strconv.Itoa(t))
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+func pointerTokens(path string) ([]string, error) {
+	ptr, err := jsonpointer.New(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ptr.DecodedTokens(), nil
+}
+
+// mergePatch applies a JSON Merge Patch to a generic JSON value
+func mergePatch(target, patch interface{}) interface{} {
+	members, isObject := patch.(map[string]interface{})
+	if !isObject {
+		return patch
+	}
+
+	targetMembers, ok := target.(map[string]interface{})
+	if !ok {
+		targetMembers = make(map[string]interface{}, len(members))
+	}
+
+	for k, v := range members {
+		if v == nil {
+			delete(targetMembers, k)
+
+			continue
+		}
+
+		targetMembers[k] = mergePatch(targetMembers[k], v)
+	}
+
+	return targetMembers
+}
+
+// asGenericJSON converts any value which marshals to JSON to a generic JSON value
+func asGenericJSON(value interface{}) (interface{}, error) {
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(buf, &generic); err != nil {
+		return nil, err
+	}
+
+	return generic, nil
+}
@@ -0,0 +1,111 @@
+package analysis
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpec_ApplyPatch(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "patch.yml"))
+	an := New(sp)
+	listPets, ok := an.OperationFor("GET", "/pets")
+	require.True(t, ok)
+
+	require.NoError(t, an.ApplyPatch([]PatchOperation{
+		{Op: "test", Path: "/definitions/pet/type", Value: "object"},
+		{Op: "add", Path: "/definitions/tag", Value: map[string]interface{}{
+			"type": "string", "enum": []string{"cat", "dog"},
+		}},
+		{Op: "copy", From: "/definitions/pet/properties/name", Path: "/definitions/pet/properties/nickname"},
+		{Op: "move", From: "/definitions/pet/properties/age", Path: "/definitions/pet/properties/years"},
+		{Op: "add", Path: "/definitions/pet/required", Value: []string{"name"}},
+		{Op: "add", Path: "/definitions/pet/required/0", Value: "nickname"},
+	}))
+
+	assert.Contains(t, sp.Definitions, "tag")
+	assert.Equal(t, []string{"nickname", "name"}, sp.Definitions["pet"].Required)
+	assert.Len(t, an.AllDefinitions(), 8)
+	assert.Equal(t, []interface{}{"cat", "dog"}, an.SchemaEnums()["#/definitions/tag"])
+	assert.Equal(t, map[string]string{
+		"#/definitions/pet/properties/name":     "^[a-z]+$",
+		"#/definitions/pet/properties/nickname": "^[a-z]+$",
+	}, an.SchemaPatterns())
+
+	// untouched sections retain their identity
+	op, ok := an.OperationFor("GET", "/pets")
+	require.True(t, ok)
+	assert.Same(t, listPets, op)
+	assert.Len(t, an.ParameterEnums(), 1)
+
+	// removing an operation refreshes the aggregated media types
+	assert.ElementsMatch(t, []string{"application/json", "application/x-yaml"}, an.RequiredConsumes())
+	require.NoError(t, an.ApplyPatch([]PatchOperation{{Op: "remove", Path: "/paths/~1pets/post"}}))
+	_, ok = an.OperationFor("POST", "/pets")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"application/json"}, an.RequiredConsumes())
+	assert.Len(t, an.ParameterEnums(), 1)
+
+	// findings come with patches
+	require.Len(t, an.NoOpConstraints(), 1)
+	require.NoError(t, an.ApplyPatch(an.NoOpConstraints()[0].Fix))
+	assert.Empty(t, an.NoOpConstraints())
+}
+
+func TestSpec_ApplyPatchErrors(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "patch.yml"))
+	an := New(sp)
+	before := antest.AsJSON(t, sp)
+
+	for _, ops := range [][]PatchOperation{
+		{{Op: "add", Path: "/definitions/tag", Value: map[string]interface{}{"type": "string"}}, {Op: "remove", Path: "/definitions/nowhere"}},
+		{{Op: "test", Path: "/info/title", Value: "other"}},
+		{{Op: "replace", Path: "/paths/~1pets/get/parameters/1", Value: "x"}},
+		{{Op: "add", Path: "/paths/~1pets/get/parameters/5", Value: map[string]interface{}{}}},
+		{{Op: "move", From: "/definitions", Path: "/definitions/pet/definitions"}},
+		{{Op: "remove", Path: ""}},
+		{{Op: "add", Path: "/info/title/x", Value: "x"}},
+		{{Op: "frobnicate", Path: "/info"}},
+		{{Op: "replace", Path: "/paths", Value: []string{"not", "an", "object"}}},
+	} {
+		require.Errorf(t, an.ApplyPatch(ops), "expected patch %v to fail", ops)
+		assert.JSONEq(t, before, antest.AsJSON(t, sp))
+	}
+
+	assert.Len(t, an.AllDefinitions(), 6)
+}
+
+func TestSpec_ApplyMergePatch(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "patch.yml"))
+	an := New(sp)
+
+	require.NoError(t, an.ApplyMergePatch(json.RawMessage(`{
+  "produces": ["application/xml"],
+  "definitions": {
+    "pet": {"properties": {"age": null, "name": {"pattern": null}}}
+  }
+}`)))
+
+	assert.Equal(t, []string{"application/xml"}, an.RequiredProduces())
+	assert.Empty(t, an.SchemaPatterns())
+	assert.NotContains(t, sp.Definitions["pet"].Properties, "age")
+	assert.Equal(t, "string", sp.Definitions["pet"].Properties["name"].Type[0])
+	assert.Len(t, an.AllDefinitions(), 5)
+
+	// a merge patch which is not an object replaces the whole document
+	require.Error(t, an.ApplyMergePatch("not a spec"))
+	require.NoError(t, an.ApplyMergePatch(map[string]interface{}{"paths": nil, "definitions": nil}))
+	assert.Empty(t, an.Operations())
+	assert.Empty(t, an.AllDefinitions())
+	assert.Equal(t, "patches", sp.Info.Title)
+}
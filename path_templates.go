@@ -0,0 +1,160 @@
+package analysis
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PathConflictKind qualifies a conflict between two path templates
+type PathConflictKind uint8
+
+const (
+	// EquivalentPaths are path templates which only differ by the names of their parameters,
+	// e.g. "/pets/{id}" and "/pets/{petId}"
+	EquivalentPaths PathConflictKind = iota + 1
+
+	// AmbiguousPaths are path templates which may both match the same request path,
+	// e.g. "/pets/mine" and "/pets/{id}"
+	AmbiguousPaths
+)
+
+func (k PathConflictKind) String() string {
+	switch k {
+	case EquivalentPaths:
+		return "equivalent"
+	case AmbiguousPaths:
+		return "ambiguous"
+	default:
+		return ""
+	}
+}
+
+// PathConflict reports two path templates in conflict.
+//
+// Paths are sorted in lexicographic order.
+type PathConflict struct {
+	Paths [2]string
+	Kind  PathConflictKind
+}
+
+var pathParamRex = regexp.MustCompile(`{([^{}/]*)}`)
+
+// PathParamsOf returns the names of the parameters in a path template, in order of appearance.
+//
+// Parameters may span a whole path segment (e.g. "/pets/{id}") or only a part of it (e.g. "/files/{name}.{ext}").
+func (s *Spec) PathParamsOf(path string) []string {
+	matches := pathParamRex.FindAllStringSubmatch(path, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	params := make([]string, 0, len(matches))
+	for _, match := range matches {
+		params = append(params, match[1])
+	}
+
+	return params
+}
+
+// PathConflicts detects path templates which conflict with each other: either they are equivalent
+// up to the names of their parameters, or they overlap (i.e. some request path would match both).
+//
+// Routers built from the spec usually reject equivalent paths, and resolve ambiguous paths
+// with some precedence rule (e.g. literal segments first), which is worth checking.
+//
+// Conflicts are sorted by path.
+func (s *Spec) PathConflicts() []PathConflict {
+	paths := sortedKeys(s.AllPaths())
+	templates := make([][]string, len(paths))
+	for i, pth := range paths {
+		templates[i] = strings.Split(strings.Trim(pth, "/"), "/")
+	}
+
+	var conflicts []PathConflict
+	for i := range paths {
+		for j := i + 1; j < len(paths); j++ {
+			kind := comparePathTemplates(templates[i], templates[j])
+			if kind == 0 {
+				continue
+			}
+
+			conflicts = append(conflicts, PathConflict{Paths: [2]string{paths[i], paths[j]}, Kind: kind})
+		}
+	}
+
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		if conflicts[i].Paths[0] == conflicts[j].Paths[0] {
+			return conflicts[i].Paths[1] < conflicts[j].Paths[1]
+		}
+
+		return conflicts[i].Paths[0] < conflicts[j].Paths[0]
+	})
+
+	return conflicts
+}
+
+func comparePathTemplates(left, right []string) PathConflictKind {
+	if len(left) != len(right) {
+		return 0
+	}
+
+	equivalent := true
+	for i := range left {
+		if anonymizeSegment(left[i]) != anonymizeSegment(right[i]) {
+			equivalent = false
+		}
+
+		if !segmentsOverlap(left[i], right[i]) {
+			return 0
+		}
+	}
+
+	if equivalent {
+		return EquivalentPaths
+	}
+
+	return AmbiguousPaths
+}
+
+// anonymizeSegment removes parameter names from a path segment
+func anonymizeSegment(segment string) string {
+	return pathParamRex.ReplaceAllString(segment, "{}")
+}
+
+// segmentsOverlap tells if some value may match both path segments.
+//
+// This is a conservative check: two segments with parameters are always considered to overlap.
+func segmentsOverlap(left, right string) bool {
+	leftHasParams := pathParamRex.MatchString(left)
+	rightHasParams := pathParamRex.MatchString(right)
+
+	switch {
+	case leftHasParams && rightHasParams:
+		return true
+	case leftHasParams:
+		return segmentMatcher(left).MatchString(right)
+	case rightHasParams:
+		return segmentMatcher(right).MatchString(left)
+	default:
+		return left == right
+	}
+}
+
+// segmentMatcher builds a regexp matching the values of a path segment with parameters
+func segmentMatcher(segment string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+
+	last := 0
+	for _, loc := range pathParamRex.FindAllStringIndex(segment, -1) {
+		b.WriteString(regexp.QuoteMeta(segment[last:loc[0]]))
+		b.WriteString(".+")
+		last = loc[1]
+	}
+
+	b.WriteString(regexp.QuoteMeta(segment[last:]))
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,35 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathTemplates_PathParamsOf(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "operations", "fixture-path-templates.yaml")))
+
+	assert.Equal(t, []string{"id"}, an.PathParamsOf("/pets/{id}"))
+	assert.Equal(t, []string{"owner", "name", "ext"}, an.PathParamsOf("/{owner}/files/{name}.{ext}"))
+	assert.Empty(t, an.PathParamsOf("/pets/mine"))
+}
+
+func TestPathTemplates_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "operations", "fixture-path-templates.yaml")))
+
+	assert.Equal(t, []PathConflict{
+		{Paths: [2]string{"/files/config.json", "/files/{name}.json"}, Kind: AmbiguousPaths},
+		{Paths: [2]string{"/pets/mine", "/pets/{id}"}, Kind: AmbiguousPaths},
+		{Paths: [2]string{"/pets/mine", "/pets/{petId}"}, Kind: AmbiguousPaths},
+		{Paths: [2]string{"/pets/{id}", "/pets/{petId}"}, Kind: EquivalentPaths},
+	}, an.PathConflicts())
+
+	assert.Equal(t, "equivalent", EquivalentPaths.String())
+	assert.Equal(t, "ambiguous", AmbiguousPaths.String())
+}
@@ -0,0 +1,80 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Codes for findings about patterns
+const (
+	CodeUnsupportedPattern = "unsupported-pattern"
+	CodeInvalidPattern     = "invalid-pattern"
+)
+
+// unsupportedPatternConstructs detect the ECMA 262 constructs which Go regular expressions (RE2) do not support
+var unsupportedPatternConstructs = []struct {
+	Name   string
+	Detect *regexp.Regexp
+}{
+	{Name: "lookahead", Detect: regexp.MustCompile(`\(\?[=!]`)},
+	{Name: "lookbehind", Detect: regexp.MustCompile(`\(\?<[=!]`)},
+	{Name: "atomic group", Detect: regexp.MustCompile(`\(\?>`)},
+	{Name: "backreference", Detect: regexp.MustCompile(`\\[1-9]|\\k<`)},
+	{Name: "possessive quantifier", Detect: regexp.MustCompile(`[*+?}]\+`)},
+}
+
+// PatternAudit reports on the compilation of the patterns found in a spec as Go regular expressions
+type PatternAudit struct {
+	// Compiled maps the JSON pointer of every valid pattern to its compiled regular expression
+	Compiled map[string]*regexp.Regexp
+
+	// Findings reports the patterns which cannot be compiled, either because they use constructs
+	// Go does not support (e.g. lookahead, backreferences) or because they are invalid
+	Findings []Finding
+}
+
+// CompiledPatterns compiles all the patterns found in the spec (see AllPatterns) as Go (RE2) regular expressions.
+//
+// Patterns are keyed by the JSON pointer of the schema, parameter, header or items declaring them.
+func (s *Spec) CompiledPatterns() PatternAudit {
+	audit := PatternAudit{Compiled: make(map[string]*regexp.Regexp, len(s.patterns.allPatterns))}
+
+	for pointer, pattern := range s.patterns.allPatterns {
+		re, err := regexp.Compile(pattern)
+		if err == nil {
+			audit.Compiled[pointer] = re
+
+			continue
+		}
+
+		var unsupported []string
+		for _, construct := range unsupportedPatternConstructs {
+			if construct.Detect.MatchString(pattern) {
+				unsupported = append(unsupported, construct.Name)
+			}
+		}
+
+		if len(unsupported) == 0 {
+			audit.Findings = append(audit.Findings, Finding{
+				Pointer: pointer,
+				Code:    CodeInvalidPattern,
+				Message: fmt.Sprintf("pattern %q is not a valid regular expression: %v", pattern, err),
+			})
+
+			continue
+		}
+
+		sort.Strings(unsupported)
+		audit.Findings = append(audit.Findings, Finding{
+			Pointer: pointer,
+			Code:    CodeUnsupportedPattern,
+			Message: fmt.Sprintf("pattern %q uses constructs not supported by Go regular expressions: %s",
+				pattern, strings.Join(unsupported, ", ")),
+		})
+	}
+	sortFindings(audit.Findings)
+
+	return audit
+}
@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpec_CompiledPatterns(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with invalid pattern", func(t *testing.T) {
+		t.Parallel()
+
+		an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "patterns.yml")))
+		audit := an.CompiledPatterns()
+
+		require.Len(t, audit.Findings, 1)
+		assert.Equal(t, "#/parameters/idParam", audit.Findings[0].Pointer)
+		assert.Equal(t, CodeInvalidPattern, audit.Findings[0].Code)
+
+		assert.Len(t, audit.Compiled, len(an.AllPatterns())-1)
+		re, ok := audit.Compiled["#/paths/~1some~1where~1{id}/get/parameters/0"]
+		require.True(t, ok)
+		assert.True(t, re.MatchString("a1"))
+	})
+
+	t.Run("with unsupported constructs", func(t *testing.T) {
+		t.Parallel()
+
+		definitions := spec.Definitions{}
+		for name, pattern := range map[string]string{
+			"lookahead":     `^(?=.*[0-9]).{8,}$`,
+			"lookbehind":    `(?<!x)y`,
+			"backreference": `^(a+)\1$`,
+			"mixed":         `(?!a)(b)\1`,
+			"supported":     `^[a-z]+$`,
+		} {
+			definitions[name] = *spec.StringProperty().WithPattern(pattern)
+		}
+
+		an := New(&spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: definitions}})
+		audit := an.CompiledPatterns()
+
+		assert.Contains(t, audit.Compiled, "#/definitions/supported")
+
+		messages := make(map[string]string, len(audit.Findings))
+		for _, finding := range audit.Findings {
+			assert.Equal(t, CodeUnsupportedPattern, finding.Code)
+			messages[finding.Pointer] = finding.Message
+		}
+
+		require.Len(t, messages, 4)
+		assert.Contains(t, messages["#/definitions/lookahead"], "lookahead")
+		assert.Contains(t, messages["#/definitions/lookbehind"], "lookbehind")
+		assert.Contains(t, messages["#/definitions/backreference"], "backreference")
+		assert.Contains(t, messages["#/definitions/mixed"], "backreference, lookahead")
+	})
+}
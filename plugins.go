@@ -0,0 +1,176 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// AnalyzerFunc is an analysis computed from an analyzed spec, e.g. by an external package
+type AnalyzerFunc func(*Spec) (interface{}, error)
+
+// AnalyzerPlugin is a named analysis registered with RegisterAnalyzer
+type AnalyzerPlugin struct {
+	Name    string
+	Analyze AnalyzerFunc
+
+	// Eager runs the analysis whenever a spec is analyzed (with New, or after a patch), rather than on demand
+	Eager bool
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// PluginResult is the outcome of an analysis plugin
+type PluginResult struct {
+	Value interface{}
+	Err   error
+}
+
+// pluginCache retains the results of the analysis plugins run on a spec. Each plugin is run once,
+// so that concurrent readers of a spec may share its results.
+type pluginCache struct {
+	mu   sync.Mutex
+	runs map[string]*pluginRun
+}
+
+type pluginRun struct {
+	once   sync.Once
+	result PluginResult
+}
+
+func newPluginCache() *pluginCache {
+	return &pluginCache{runs: make(map[string]*pluginRun)}
+}
+
+// run yields the result of a plugin, running it on the spec unless it has already been run
+func (c *pluginCache) run(s *Spec, plugin AnalyzerPlugin) PluginResult {
+	c.mu.Lock()
+	run, exists := c.runs[plugin.Name]
+	if !exists {
+		run = new(pluginRun)
+		c.runs[plugin.Name] = run
+	}
+	c.mu.Unlock()
+
+	run.once.Do(func() {
+		value, err := plugin.Analyze(s)
+		if err != nil {
+			err = fmt.Errorf("analyzer plugin %q: %w", plugin.Name, err)
+		}
+		run.result = PluginResult{Value: value, Err: err}
+	})
+
+	return run.result
+}
+
+// copy yields a cache sharing the results of this one
+func (c *pluginCache) copy() *pluginCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	runs := make(map[string]*pluginRun, len(c.runs))
+	for name, run := range c.runs {
+		runs[name] = run
+	}
+
+	return &pluginCache{runs: runs}
+}
+
+// registered analysis plugins
+var analyzerPlugins = struct {
+	sync.RWMutex
+	byName map[string]AnalyzerPlugin
+}{byName: make(map[string]AnalyzerPlugin)}
+
+// RegisterAnalyzer registers a named analysis, so that tools may extend the analysis of specs without forking
+// this package. Results are retrieved by name with Spec.PluginResult.
+//
+// Plugins are typically registered from the init function of the package contributing them.
+// Registering a plugin with an empty name, without an analysis, or with the name of another plugin is an error.
+func RegisterAnalyzer(plugin AnalyzerPlugin) error {
+	if plugin.Name == "" || plugin.Analyze == nil {
+		return errors.New("an analyzer plugin requires a name and an analysis")
+	}
+
+	analyzerPlugins.Lock()
+	defer analyzerPlugins.Unlock()
+
+	if _, exists := analyzerPlugins.byName[plugin.Name]; exists {
+		return fmt.Errorf("analyzer plugin %q is already registered", plugin.Name)
+	}
+
+	analyzerPlugins.byName[plugin.Name] = plugin
+
+	return nil
+}
+
+// UnregisterAnalyzer removes a named analysis. This is a no-op if no such plugin is registered.
+func UnregisterAnalyzer(name string) {
+	analyzerPlugins.Lock()
+	defer analyzerPlugins.Unlock()
+
+	delete(analyzerPlugins.byName, name)
+}
+
+// RegisteredAnalyzers returns the names of all registered analysis plugins, sorted
+func RegisteredAnalyzers() []string {
+	analyzerPlugins.RLock()
+	defer analyzerPlugins.RUnlock()
+
+	return sortedKeys(analyzerPlugins.byName)
+}
+
+func registeredAnalyzer(name string) (AnalyzerPlugin, bool) {
+	analyzerPlugins.RLock()
+	defer analyzerPlugins.RUnlock()
+
+	plugin, ok := analyzerPlugins.byName[name]
+
+	return plugin, ok
+}
+
+// PluginResult returns the result of a registered analysis plugin.
+//
+// Eager plugins have already been run when the spec was analyzed. Other plugins are run on the first call.
+// Results are retained until the spec is patched. PluginResult may be called concurrently.
+func (s *Spec) PluginResult(name string) (interface{}, error) {
+	plugin, ok := registeredAnalyzer(name)
+	if !ok {
+		return nil, fmt.Errorf("no analyzer plugin registered as %q", name)
+	}
+
+	result := s.plugins.run(s, plugin)
+
+	return result.Value, result.Err
+}
+
+// PluginResults runs all registered analysis plugins, and returns their results by name
+func (s *Spec) PluginResults() map[string]PluginResult {
+	results := make(map[string]PluginResult)
+	for _, name := range RegisteredAnalyzers() {
+		value, err := s.PluginResult(name)
+		results[name] = PluginResult{Value: value, Err: err}
+	}
+
+	return results
+}
+
+// runEagerPlugins runs the plugins which analyze specs as soon as they are loaded
+func (s *Spec) runEagerPlugins() {
+	analyzerPlugins.RLock()
+	eager := make([]AnalyzerPlugin, 0, len(analyzerPlugins.byName))
+	for _, plugin := range analyzerPlugins.byName {
+		if plugin.Eager {
+			eager = append(eager, plugin)
+		}
+	}
+	analyzerPlugins.RUnlock()
+
+	sort.Slice(eager, func(i, j int) bool { return eager[i].Name < eager[j].Name })
+
+	for _, plugin := range eager {
+		s.plugins.run(s, plugin)
+	}
+}
@@ -0,0 +1,137 @@
+package analysis
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzerPlugins(t *testing.T) {
+	// not parallel: eager plugins run whenever any other test analyzes a spec
+
+	var eagerRuns, lazyRuns int
+	require.NoError(t, RegisterAnalyzer(AnalyzerPlugin{
+		Name:  "test-eager-paths",
+		Eager: true,
+		Analyze: func(s *Spec) (interface{}, error) {
+			eagerRuns++
+
+			return len(s.AllPaths()), nil
+		},
+	}))
+	require.NoError(t, RegisterAnalyzer(AnalyzerPlugin{
+		Name: "test-lazy-definitions",
+		Analyze: func(s *Spec) (interface{}, error) {
+			lazyRuns++
+
+			return len(s.AllDefinitions()), nil
+		},
+	}))
+	require.NoError(t, RegisterAnalyzer(AnalyzerPlugin{
+		Name:    "test-failing",
+		Analyze: func(*Spec) (interface{}, error) { return nil, errors.New("boom") },
+	}))
+	t.Cleanup(func() {
+		UnregisterAnalyzer("test-eager-paths")
+		UnregisterAnalyzer("test-lazy-definitions")
+		UnregisterAnalyzer("test-failing")
+	})
+
+	assert.Subset(t, RegisteredAnalyzers(), []string{"test-eager-paths", "test-failing", "test-lazy-definitions"})
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "patch.yml")))
+	assert.Equal(t, 1, eagerRuns)
+	assert.Zero(t, lazyRuns)
+
+	paths, err := an.PluginResult("test-eager-paths")
+	require.NoError(t, err)
+	assert.Equal(t, len(an.AllPaths()), paths)
+
+	definitions, err := an.PluginResult("test-lazy-definitions")
+	require.NoError(t, err)
+	assert.Equal(t, len(an.AllDefinitions()), definitions)
+
+	_, err = an.PluginResult("test-lazy-definitions")
+	require.NoError(t, err)
+	assert.Equal(t, 1, lazyRuns, "results are retained")
+
+	_, err = an.PluginResult("test-failing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "test-failing")
+
+	_, err = an.PluginResult("test-unknown")
+	require.Error(t, err)
+
+	results := an.PluginResults()
+	assert.Equal(t, paths, results["test-eager-paths"].Value)
+	require.Error(t, results["test-failing"].Err)
+
+	// internal analyses do not run eager plugins
+	_, err = Flattened(an.spec, FlattenOpts{BasePath: filepath.Join("fixtures", "patch.yml"), Minimal: true})
+	require.NoError(t, err)
+	Prune(CloneSpec(an.spec))
+	assert.Equal(t, 1, eagerRuns)
+
+	// results are refreshed when the spec is patched
+	require.NoError(t, an.ApplyMergePatch(map[string]interface{}{"paths": map[string]interface{}{"/extra": map[string]interface{}{}}}))
+	assert.Equal(t, 2, eagerRuns)
+
+	paths, err = an.PluginResult("test-eager-paths")
+	require.NoError(t, err)
+	assert.Equal(t, len(an.AllPaths()), paths)
+
+	_, _ = an.PluginResult("test-lazy-definitions")
+	assert.Equal(t, 2, lazyRuns)
+}
+
+func TestAnalyzerPlugins_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var runs int32
+	require.NoError(t, RegisterAnalyzer(AnalyzerPlugin{
+		Name: "test-concurrent",
+		Analyze: func(s *Spec) (interface{}, error) {
+			atomic.AddInt32(&runs, 1)
+
+			return len(s.AllDefinitions()), nil
+		},
+	}))
+	t.Cleanup(func() { UnregisterAnalyzer("test-concurrent") })
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "patch.yml")))
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = an.PluginResult("test-concurrent")
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+	for _, result := range results {
+		assert.Equal(t, len(an.AllDefinitions()), result)
+	}
+}
+
+func TestRegisterAnalyzer_Errors(t *testing.T) {
+	t.Parallel()
+
+	require.Error(t, RegisterAnalyzer(AnalyzerPlugin{Name: "test-no-analysis"}))
+	require.Error(t, RegisterAnalyzer(AnalyzerPlugin{Analyze: func(*Spec) (interface{}, error) { return nil, nil }}))
+
+	plugin := AnalyzerPlugin{Name: "test-duplicate", Analyze: func(*Spec) (interface{}, error) { return nil, nil }}
+	require.NoError(t, RegisterAnalyzer(plugin))
+	t.Cleanup(func() { UnregisterAnalyzer("test-duplicate") })
+
+	require.Error(t, RegisterAnalyzer(plugin))
+}
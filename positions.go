@@ -0,0 +1,126 @@
+package analysis
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// Position locates an element of a spec in the source of a document
+type Position struct {
+	Document string // the location of the document, as a key of the sources given to NewWithSource
+	Line     int    // 1-based
+	Column   int    // 1-based
+}
+
+// NewWithSource analyzes a spec like New, and retains the sources of its documents (JSON or YAML), so that
+// the elements of the spec may be located by line and column with PositionOf.
+//
+// Sources are indexed by the location of their document, as recorded by the provenance of the spec
+// (e.g. "models.yml" for the definitions imported by Flatten from this document). The root document is the only
+// source, or the source of the document recorded as the provenance of "#" (e.g. from FlattenOpts.BasePath).
+// Sources which cannot be parsed are ignored.
+func NewWithSource(doc *spec.Swagger, sources map[string][]byte) *Spec {
+	s := New(doc)
+	s.sources = make(map[string]*yaml.Node, len(sources))
+	for location, data := range sources {
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil || len(node.Content) == 0 {
+			continue
+		}
+
+		s.sources[documentLocation(location)] = node.Content[0]
+	}
+
+	return s
+}
+
+// PositionOf locates an element of the spec (e.g. "#/definitions/pet/properties/name") in the source of the document
+// it comes from (see ProvenanceOf). The position of a member of an object is the position of its key.
+//
+// This returns false when the spec has no source for this document (see NewWithSource), or when the element
+// cannot be found in this source.
+func (s *Spec) PositionOf(pointer string) (Position, bool) {
+	origin := s.ProvenanceOf(pointer)
+
+	return s.positionIn(origin.Document, origin.Pointer)
+}
+
+// positionIn locates an element in the source of a document, or of the root document when empty
+func (s *Spec) positionIn(document, pointer string) (Position, bool) {
+	if len(s.sources) == 0 {
+		return Position{}, false
+	}
+
+	if document == "" {
+		document = s.ProvenanceOf("#").Document
+	}
+
+	location := documentLocation(document)
+	node, found := s.sources[location]
+	if !found && document == "" && len(s.sources) == 1 {
+		for only := range s.sources {
+			location = only
+		}
+		node, found = s.sources[location], true
+	}
+
+	if !found {
+		return Position{}, false
+	}
+
+	line, column, found := positionInNode(node, pointer)
+	if !found {
+		return Position{}, false
+	}
+
+	return Position{Document: location, Line: line, Column: column}, true
+}
+
+// positionInNode locates the element at some JSON pointer in a YAML node
+func positionInNode(node *yaml.Node, pointer string) (int, int, bool) {
+	line, column := node.Line, node.Column
+
+	fragment := strings.TrimPrefix(pointer, "#")
+	if fragment == "" {
+		return line, column, true
+	}
+
+	for _, token := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+		token = jsonpointer.Unescape(token)
+		for node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			var child *yaml.Node
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if key := node.Content[i]; key.Value == token {
+					line, column = key.Line, key.Column
+					child = node.Content[i+1]
+				}
+			}
+
+			if child == nil {
+				return 0, 0, false
+			}
+			node = child
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return 0, 0, false
+			}
+
+			node = node.Content[index]
+			line, column = node.Line, node.Column
+		default:
+			return 0, 0, false
+		}
+	}
+
+	return line, column, true
+}
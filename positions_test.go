@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositionOf(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "expand", "spec.yml")
+	models := filepath.Join("fixtures", "expand", "models.yml")
+	root, err := os.ReadFile(bp)
+	require.NoError(t, err)
+	remote, err := os.ReadFile(models)
+	require.NoError(t, err)
+
+	an := NewWithSource(antest.LoadOrFail(t, bp), map[string][]byte{bp: root, models: remote})
+	require.NoError(t, Flatten(FlattenOpts{Spec: an, BasePath: bp, Minimal: true}))
+
+	for _, toPin := range []struct {
+		Pointer  string
+		Document string
+		Line     int
+		Column   int
+	}{
+		{Pointer: "#", Document: bp, Line: 1, Column: 1},
+		{Pointer: "#/paths/~1pets", Document: bp, Line: 6, Column: 3},
+		{Pointer: "#/paths/~1pets/get/parameters/0", Document: bp, Line: 9, Column: 11},
+		{Pointer: "#/paths/~1pets/get/responses/200/schema/items", Document: bp, Line: 15, Column: 13},
+		{Pointer: "#/definitions/receipt/properties/tag", Document: models, Line: 7, Column: 7},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.Pointer, func(t *testing.T) {
+			t.Parallel()
+
+			position, found := an.PositionOf(fixture.Pointer)
+			require.True(t, found)
+			assert.Equal(t, Position{Document: absoluteBasePath(fixture.Document), Line: fixture.Line, Column: fixture.Column}, position)
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, found := an.PositionOf("#/paths/~1pets/get/parameters/9")
+		assert.False(t, found)
+		_, found = an.PositionOf("#/definitions/unknown")
+		assert.False(t, found)
+	})
+}
+
+func TestPositionOf_SingleSource(t *testing.T) {
+	t.Parallel()
+
+	source := []byte("{\n  \"swagger\": \"2.0\",\n  \"info\": {\"title\": \"json\", \"version\": \"1.0\"},\n  \"paths\": {}\n}\n")
+	an := NewWithSource(antest.LoadOrFail(t, filepath.Join("fixtures", "empty-paths.json")), map[string][]byte{"swagger.json": source})
+
+	position, found := an.PositionOf("#/info/version")
+	require.True(t, found)
+	assert.Equal(t, 3, position.Line)
+	assert.Equal(t, 29, position.Column)
+
+	_, found = New(an.spec).PositionOf("#/info")
+	assert.False(t, found, "no source is retained by New")
+
+	var buf bytes.Buffer
+	require.NoError(t, an.WriteSARIF(&buf, FindingsSARIF([]Finding{{Pointer: "#/paths", Code: "test", Message: "test"}}, SeverityInfo), SARIFOpts{Document: "swagger.json"}))
+	assert.Contains(t, buf.String(), `"startLine": 4`)
+}
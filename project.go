@@ -0,0 +1,264 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// ProjectOpts configures the loading of a multi-document project with LoadProject
+type ProjectOpts struct {
+	// FS is the file system to read local documents from. Defaults to the OS file system
+	FS fs.FS
+
+	// Notifier tells Project.Watch when documents may have changed. Defaults to polling the documents
+	// every PollInterval.
+	Notifier ProjectNotifier
+
+	// PollInterval is the interval between two polls of the documents by Project.Watch, when no Notifier is set.
+	// Defaults to DefaultProjectPollInterval.
+	PollInterval time.Duration
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// Project is a root spec analyzed together with all the documents it refers to, directly or transitively
+type Project struct {
+	// Root is the location of the root document, as an absolute file path or a URL
+	Root string
+
+	// Spec is the analyzer of the root document
+	Spec *Spec
+
+	// Documents are all the documents of the project, including the root document, by location
+	Documents map[string]*ProjectDocument
+
+	// Refs are all the $ref's found in the documents of the project, sorted by location
+	Refs []ProjectRef
+
+	opts   ProjectOpts
+	hashes map[string]string // the hash of the content of each document, empty when it could not be loaded
+}
+
+// ProjectDocument is a document of a Project
+type ProjectDocument struct {
+	// Location is the absolute file path or URL of the document
+	Location string
+
+	// Content is the document, as generic JSON. It is nil when the document could not be loaded.
+	Content interface{}
+
+	// Err tells why the document could not be loaded
+	Err error
+}
+
+// ProjectRef is a $ref found in a document of a Project
+type ProjectRef struct {
+	// From locates the $ref, as the location of its document and a JSON pointer (e.g. "/specs/api.yml#/paths/~1pets/get/responses/200/schema")
+	From string
+
+	// Ref is the $ref, as written in the document
+	Ref string
+
+	// Target is the location the $ref resolves to, as the location of its document and a JSON pointer
+	// (e.g. "/specs/models.yml#/definitions/pet")
+	Target string
+}
+
+// LoadProject loads a root spec, and all the documents it refers to with $ref's, directly or transitively.
+//
+// Documents which cannot be loaded are part of the project, with an error: only failing to load
+// the root document is an error.
+func LoadProject(root string, opts ProjectOpts) (*Project, error) {
+	loader := opts.pathLoader()
+
+	p := &Project{
+		Root:      absoluteBasePath(root),
+		Documents: make(map[string]*ProjectDocument),
+		opts:      opts,
+		hashes:    make(map[string]string),
+	}
+
+	raw, err := loader(p.Root)
+	if err != nil {
+		return nil, fmt.Errorf("could not load %s: %w", root, err)
+	}
+
+	var sp spec.Swagger
+	if err := json.Unmarshal(raw, &sp); err != nil {
+		return nil, fmt.Errorf("could not load %s: %w", root, err)
+	}
+	p.Spec = New(&sp)
+
+	pending := []string{p.Root}
+	p.Documents[p.Root] = &ProjectDocument{Location: p.Root}
+	for len(pending) > 0 {
+		document := p.Documents[pending[0]]
+		pending = pending[1:]
+
+		if raw, document.Err = loader(document.Location); document.Err != nil {
+			continue
+		}
+		p.hashes[document.Location] = contentHash(raw)
+
+		if document.Err = json.Unmarshal(raw, &document.Content); document.Err != nil {
+			continue
+		}
+
+		walkJSONRefs(document.Content, "", func(pointer, ref string) {
+			target, fragment := refDocument(document.Location, ref)
+			if target == "" {
+				target = document.Location
+			}
+
+			p.Refs = append(p.Refs, ProjectRef{From: document.Location + "#" + pointer, Ref: ref, Target: target + "#" + fragment})
+
+			if _, known := p.Documents[target]; !known {
+				p.Documents[target] = &ProjectDocument{Location: target}
+				pending = append(pending, target)
+			}
+		})
+	}
+
+	sort.Slice(p.Refs, func(i, j int) bool { return p.Refs[i].From < p.Refs[j].From })
+
+	return p, nil
+}
+
+func (o ProjectOpts) pathLoader() func(string) (json.RawMessage, error) {
+	if o.FS != nil {
+		return fsPathLoader(o.FS)
+	}
+
+	return spec.PathLoader
+}
+
+// Components lists the shared definitions, parameters and responses defined by each document of the project,
+// as JSON pointers (e.g. "#/definitions/pet"), sorted
+func (p *Project) Components() map[string][]string {
+	components := make(map[string][]string, len(p.Documents))
+	for location, document := range p.Documents {
+		components[location] = documentComponents(document.Content)
+	}
+
+	return components
+}
+
+// DefinedIn lists the locations of the documents defining a shared definition, parameter or response,
+// given its JSON pointer (e.g. "#/definitions/pet"), sorted
+func (p *Project) DefinedIn(pointer string) []string {
+	var documents []string
+	for _, location := range sortedKeys(p.Documents) {
+		for _, component := range documentComponents(p.Documents[location].Content) {
+			if component == pointer {
+				documents = append(documents, location)
+
+				break
+			}
+		}
+	}
+
+	return documents
+}
+
+// DanglingRefs lists the $ref's which cannot be resolved: their document could not be loaded,
+// or nothing is found at their JSON pointer in this document. Refs are sorted by location.
+func (p *Project) DanglingRefs() []ProjectRef {
+	var dangling []ProjectRef
+	for _, ref := range p.Refs {
+		location, fragment, _ := strings.Cut(ref.Target, "#")
+		document := p.Documents[location]
+		if document.Err != nil {
+			dangling = append(dangling, ref)
+
+			continue
+		}
+
+		pointer, err := jsonpointer.New(fragment)
+		if err != nil {
+			dangling = append(dangling, ref)
+
+			continue
+		}
+
+		if _, _, err := pointer.Get(document.Content); err != nil {
+			dangling = append(dangling, ref)
+		}
+	}
+
+	return dangling
+}
+
+// Unused lists, for each document of the project, the shared definitions, parameters and responses which cannot
+// be reached from the paths of the root document, following $ref's across documents (e.g. "#/definitions/pet").
+// Documents without unused components are omitted. Components are sorted.
+func (p *Project) Unused() map[string][]string {
+	// an edge goes from the component holding a $ref (or the document, outside components) to its target
+	edges := make(map[string][]string)
+	for _, ref := range p.Refs {
+		from := projectNode(ref.From)
+		edges[from] = append(edges[from], projectNode(ref.Target))
+	}
+
+	reachable := make(map[string]bool)
+	pending := []string{p.Root + "#"}
+	for len(pending) > 0 {
+		node := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if reachable[node] {
+			continue
+		}
+
+		reachable[node] = true
+		pending = append(pending, edges[node]...)
+
+		// the parts of a document outside its components hold the components they refer to
+		if location, fragment, _ := strings.Cut(node, "#"); fragment != "" {
+			pending = append(pending, location+"#")
+		}
+	}
+
+	unused := make(map[string][]string)
+	for location, components := range p.Components() {
+		for _, component := range components {
+			if !reachable[location+component] {
+				unused[location] = append(unused[location], component)
+			}
+		}
+	}
+
+	return unused
+}
+
+// projectNode yields the node of the graph of a project holding a location: a component of a document
+// (e.g. "/specs/models.yml#/definitions/pet"), or a document outside its components (e.g. "/specs/models.yml#")
+func projectNode(location string) string {
+	document, fragment, _ := strings.Cut(location, "#")
+	if component, isComponent := componentOfPointer("#" + fragment); isComponent {
+		return document + component
+	}
+
+	return document + "#"
+}
+
+// documentComponents lists the components of a generic JSON document, sorted
+func documentComponents(content interface{}) []string {
+	object := asObject(content)
+
+	var components []string
+	for _, section := range componentSections {
+		for _, name := range sortedKeys(asObject(object[section])) {
+			components = append(components, "#/"+section+"/"+jsonpointer.Escape(name))
+		}
+	}
+	sort.Strings(components)
+
+	return components
+}
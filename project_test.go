@@ -0,0 +1,129 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/analysis/internal/antest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProject(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"api.yml": {Data: []byte(`swagger: '2.0'
+info: {title: project, version: '1'}
+paths:
+  /pets:
+    get:
+      parameters:
+        - $ref: 'models.yml#/parameters/limit'
+      responses:
+        200:
+          description: pets
+          schema: {type: array, items: {$ref: 'models.yml#/definitions/pet'}}
+        404:
+          description: missing
+          schema: {$ref: 'missing.yml#/definitions/error'}
+definitions:
+  unused: {type: string}
+`)},
+		"models.yml": {Data: []byte(`parameters:
+  limit: {name: limit, in: query, type: integer}
+  offset: {name: offset, in: query, type: integer}
+definitions:
+  pet:
+    type: object
+    properties:
+      owner: {$ref: '#/definitions/owner'}
+      tag: {$ref: '#/definitions/tag'}
+  owner: {type: object}
+  orphan:
+    type: object
+    properties:
+      pet: {$ref: '#/definitions/pet'}
+`)},
+	}
+
+	p, err := LoadProject("api.yml", ProjectOpts{FS: fsys})
+	require.NoError(t, err)
+	require.NotNil(t, p.Spec)
+
+	api := absoluteBasePath("api.yml")
+	models := absoluteBasePath("models.yml")
+	missing := absoluteBasePath("missing.yml")
+
+	assert.Equal(t, api, p.Root)
+	require.Len(t, p.Documents, 3)
+	require.NoError(t, p.Documents[api].Err)
+	require.NoError(t, p.Documents[models].Err)
+	require.Error(t, p.Documents[missing].Err)
+
+	t.Run("should list what each document defines", func(t *testing.T) {
+		t.Parallel()
+
+		components := p.Components()
+		assert.Equal(t, []string{"#/definitions/unused"}, components[api])
+		assert.Equal(t, []string{
+			"#/definitions/orphan", "#/definitions/owner", "#/definitions/pet",
+			"#/parameters/limit", "#/parameters/offset",
+		}, components[models])
+		assert.Empty(t, components[missing])
+
+		assert.Equal(t, []string{models}, p.DefinedIn("#/definitions/pet"))
+		assert.Empty(t, p.DefinedIn("#/definitions/nowhere"))
+	})
+
+	t.Run("should find dangling refs", func(t *testing.T) {
+		t.Parallel()
+
+		dangling := p.DanglingRefs()
+		require.Len(t, dangling, 2)
+
+		assert.Equal(t, ProjectRef{
+			From:   api + "#/paths/~1pets/get/responses/404/schema",
+			Ref:    "missing.yml#/definitions/error",
+			Target: missing + "#/definitions/error",
+		}, dangling[0])
+
+		assert.Equal(t, models+"#/definitions/pet/properties/tag", dangling[1].From)
+		assert.Equal(t, models+"#/definitions/tag", dangling[1].Target)
+	})
+
+	t.Run("should find unused items per document", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, map[string][]string{
+			api:    {"#/definitions/unused"},
+			models: {"#/definitions/orphan", "#/parameters/offset"},
+		}, p.Unused())
+	})
+}
+
+func TestLoadProject_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := LoadProject(filepath.Join("fixtures", "nowhere.yml"), ProjectOpts{})
+	require.Error(t, err)
+}
+
+func TestLoadProject_ExternalFixtures(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "external_definitions_valid.yml")
+	sp := antest.LoadOrFail(t, bp)
+
+	p, err := LoadProject(bp, ProjectOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, sp.Info.Title, p.Spec.spec.Info.Title)
+
+	assert.Empty(t, p.DanglingRefs())
+	assert.Contains(t, p.Documents, absoluteBasePath(filepath.Join("fixtures", "external", "definitions.yml")))
+	for location, document := range p.Documents {
+		assert.NoErrorf(t, document.Err, "unexpected error loading %s", location)
+	}
+}
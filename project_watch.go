@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	gocontext "context"
+	"time"
+)
+
+// DefaultProjectPollInterval is the interval between two polls of the documents of a project by Project.Watch,
+// when no ProjectNotifier is set
+const DefaultProjectPollInterval = time.Second
+
+// ProjectNotifier tells Project.Watch when the documents of a project may have changed, e.g. from file system events.
+//
+// Notifications may be spurious: Project.Watch checks the content of the documents before analyzing them again.
+type ProjectNotifier interface {
+	// WaitForChange blocks until one of the documents at these locations (absolute file paths or URLs) may have changed,
+	// or the context is done. It yields an error to stop watching.
+	WaitForChange(ctx gocontext.Context, locations []string) error
+}
+
+// Watch analyzes the project again whenever its root document, or any document it refers to, changes, until the
+// context is done or the notifier fails.
+//
+// onChange is called with the analyzer of the new root document after each change, or with the error which prevented
+// loading it. The documents watched follow the $ref's of the latest loaded version of the project. The project itself
+// is left unchanged.
+func (p *Project) Watch(ctx gocontext.Context, onChange func(*Spec, error)) error {
+	locations, hashes := sortedKeys(p.Documents), p.hashes
+
+	for {
+		if err := p.waitForChange(ctx, locations); err != nil {
+			return err
+		}
+
+		latest := p.contentHashes(locations)
+		if sameHashes(latest, hashes) {
+			continue
+		}
+
+		next, err := LoadProject(p.Root, p.opts)
+		if err != nil {
+			hashes = latest
+			onChange(nil, err)
+
+			continue
+		}
+
+		locations, hashes = sortedKeys(next.Documents), next.hashes
+		onChange(next.Spec, nil)
+	}
+}
+
+func (p *Project) waitForChange(ctx gocontext.Context, locations []string) error {
+	if p.opts.Notifier != nil {
+		return p.opts.Notifier.WaitForChange(ctx, locations)
+	}
+
+	interval := p.opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultProjectPollInterval
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// contentHashes yields the hash of the content of the documents at these locations, empty when they cannot be loaded
+func (p *Project) contentHashes(locations []string) map[string]string {
+	loader := p.opts.pathLoader()
+	hashes := make(map[string]string, len(locations))
+	for _, location := range locations {
+		if raw, err := loader(location); err == nil {
+			hashes[location] = contentHash(raw)
+		}
+	}
+
+	return hashes
+}
+
+func sameHashes(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for location, hash := range a {
+		if b[location] != hash {
+			return false
+		}
+	}
+
+	return true
+}
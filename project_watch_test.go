@@ -0,0 +1,129 @@
+package analysis
+
+import (
+	gocontext "context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// channelNotifier notifies Project.Watch of a change whenever it receives on its channel
+type channelNotifier struct {
+	changes   chan struct{}
+	locations chan []string
+}
+
+func (n *channelNotifier) WaitForChange(ctx gocontext.Context, locations []string) error {
+	n.locations <- locations
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case _, open := <-n.changes:
+		if !open {
+			return errors.New("closed")
+		}
+
+		return nil
+	}
+}
+
+type watchResult struct {
+	spec *Spec
+	err  error
+}
+
+func TestProject_Watch(t *testing.T) {
+	t.Parallel()
+
+	const root = "swagger: '2.0'\ninfo: {title: watch, version: '1'}\npaths: {}\ndefinitions:\n  pet: {$ref: 'pet.yml'}\n"
+	fsys := fstest.MapFS{
+		"api.yml": {Data: []byte(root)},
+		"pet.yml": {Data: []byte("type: object\n")},
+	}
+
+	notifier := &channelNotifier{changes: make(chan struct{}), locations: make(chan []string, 1)}
+	p, err := LoadProject("api.yml", ProjectOpts{FS: fsys, Notifier: notifier})
+	require.NoError(t, err)
+
+	results := make(chan watchResult, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Watch(gocontext.Background(), func(s *Spec, err error) { results <- watchResult{s, err} })
+	}()
+
+	api, pet, owner := absoluteBasePath("api.yml"), absoluteBasePath("pet.yml"), absoluteBasePath("owner.yml")
+	assert.Equal(t, []string{api, pet}, <-notifier.locations)
+
+	// a spurious notification is ignored
+	notifier.changes <- struct{}{}
+	assert.Equal(t, []string{api, pet}, <-notifier.locations)
+
+	// a referenced document changes, and refers to another document, which is watched from now on
+	fsys["pet.yml"] = &fstest.MapFile{Data: []byte("type: object\nproperties:\n  owner: {$ref: 'owner.yml'}\n")}
+	fsys["owner.yml"] = &fstest.MapFile{Data: []byte("type: string\n")}
+	notifier.changes <- struct{}{}
+	result := <-results
+	require.NoError(t, result.err)
+	require.NotNil(t, result.spec)
+	assert.Equal(t, []string{api, owner, pet}, <-notifier.locations)
+
+	// the root document is broken
+	fsys["api.yml"] = &fstest.MapFile{Data: []byte("swagger: [")}
+	notifier.changes <- struct{}{}
+	result = <-results
+	require.Error(t, result.err)
+	assert.Nil(t, result.spec)
+	assert.Equal(t, []string{api, owner, pet}, <-notifier.locations)
+
+	// the root document is fixed
+	fsys["api.yml"] = &fstest.MapFile{Data: []byte(root + "  owner: {type: object}\n")}
+	notifier.changes <- struct{}{}
+	result = <-results
+	require.NoError(t, result.err)
+	assert.Contains(t, result.spec.spec.Definitions, "owner")
+	<-notifier.locations
+
+	close(notifier.changes)
+	require.Error(t, <-done)
+}
+
+func TestProject_WatchPolling(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	root := filepath.Join(dir, "api.json")
+	require.NoError(t, os.WriteFile(root, []byte(`{"swagger": "2.0", "info": {"title": "watch", "version": "1"}, "paths": {}}`), 0o600))
+
+	p, err := LoadProject(root, ProjectOpts{PollInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	results := make(chan watchResult, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Watch(ctx, func(s *Spec, err error) { results <- watchResult{s, err} })
+	}()
+
+	// replace the document at once, so it is never polled half written
+	updated := filepath.Join(dir, "updated.json")
+	require.NoError(t, os.WriteFile(updated, []byte(`{"swagger": "2.0", "info": {"title": "watch", "version": "2"}, "paths": {}}`), 0o600))
+	require.NoError(t, os.Rename(updated, root))
+
+	select {
+	case result := <-results:
+		require.NoError(t, result.err)
+		assert.Equal(t, "2", result.spec.spec.Info.Version)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the change to be detected")
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, gocontext.Canceled)
+}
@@ -0,0 +1,554 @@
+package analysis
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// ProtoNumbering is a strategy to number the fields of the protobuf messages exported by ExportProto
+type ProtoNumbering string
+
+// Strategies to number the fields of protobuf messages
+const (
+	// ProtoNumberingSequential numbers the fields of a message in order: inherited properties first,
+	// then the properties of the message by name
+	ProtoNumberingSequential ProtoNumbering = "sequential"
+
+	// ProtoNumberingHash derives the number of a field from a hash of its name, so that adding or removing
+	// a property does not renumber the others. Collisions are resolved with the next free number.
+	ProtoNumberingHash ProtoNumbering = "hash"
+
+	// ProtoNumberingExtension takes the number of a field from the x-proto-field extension of its property.
+	// Properties without this extension are numbered sequentially, after the highest number in the message.
+	ProtoNumberingExtension ProtoNumbering = "extension"
+)
+
+// CodeInvalidFieldNumber reports a number declared with the x-proto-field extension, which cannot number a field
+const CodeInvalidFieldNumber = "invalid-field-number"
+
+// CodeUnmappableNesting reports an array of arrays, which protobuf cannot represent
+const CodeUnmappableNesting = "unmappable-nesting"
+
+const (
+	protoFieldExtension = "x-proto-field"
+
+	protoMaxFieldNumber     = 1<<29 - 1
+	protoReservedRangeStart = 19000
+	protoReservedRangeEnd   = 19999
+	protoStructImport       = "google/protobuf/struct.proto"
+	protoTimestampImport    = "google/protobuf/timestamp.proto"
+	protoValue              = "google.protobuf.Value"
+	protoListValue          = "google.protobuf.ListValue"
+	protoStruct             = "google.protobuf.Struct"
+	protoTimestamp          = "google.protobuf.Timestamp"
+	protoRepeatedPrefix     = "repeated "
+	protoMapPrefix          = "map<"
+)
+
+// protobuf scalar types, for builtin go types
+var protoScalars = map[string]string{
+	"string":        "string",
+	"bool":          "bool",
+	"float32":       "float",
+	"float64":       "double",
+	"int8":          "int32",
+	"int16":         "int32",
+	"int32":         "int32",
+	"int64":         "int64",
+	"uint8":         "uint32",
+	"uint16":        "uint32",
+	"uint32":        "uint32",
+	"uint64":        "uint64",
+	"[]byte":        "bytes",
+	"io.ReadCloser": "bytes",
+}
+
+// ProtoOpts specifies how definitions are exported as protobuf messages
+type ProtoOpts struct {
+	// Package is the protobuf package of the messages. No package is declared when empty.
+	Package string
+
+	// Numbering is the strategy to number fields. Defaults to ProtoNumberingSequential.
+	Numbering ProtoNumbering
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// ExportProto sketches protobuf (proto3) message definitions from the definitions of a spec:
+//   - object definitions become messages, with the properties inherited with allOf;
+//   - string enums become enums, with a zero value named after the enum (e.g. STATUS_UNSPECIFIED);
+//   - arrays become repeated fields, and maps become map fields;
+//   - inline objects and enums become top-level messages and enums, named after their field (e.g. PetOwner);
+//   - date-times become google.protobuf.Timestamp, and untyped schemas google.protobuf.Value.
+//
+// Field names are converted to snake case, with a json_name option when the JSON name of the field
+// differs from the name of its property.
+//
+// Findings report what cannot be mapped faithfully, sorted by pointer: e.g. tuples, arrays of arrays,
+// maps of arrays or maps, maps with pattern-constrained keys, or polymorphic definitions.
+func ExportProto(sp *spec.Swagger, opts ProtoOpts) (string, []Finding, error) {
+	if opts.Numbering == "" {
+		opts.Numbering = ProtoNumberingSequential
+	}
+
+	switch opts.Numbering {
+	case ProtoNumberingSequential, ProtoNumberingHash, ProtoNumberingExtension:
+	default:
+		return "", nil, fmt.Errorf("unknown field numbering strategy %q", opts.Numbering)
+	}
+
+	e := &protoExporter{
+		sp:       sp,
+		opts:     opts,
+		imports:  make(map[string]bool),
+		blocks:   make(map[string]string),
+		pending:  make(map[string]bool),
+		inlining: make(map[string]bool),
+	}
+
+	for _, name := range sortedKeys(sp.Definitions) {
+		def := sp.Definitions[name]
+		if def.Ref.String() != "" {
+			continue
+		}
+
+		a, err := Schema(SchemaOpts{Schema: &def, Root: sp})
+		if err != nil {
+			return "", nil, err
+		}
+
+		if isStringEnum(a) || (a.GoTypeHint.Kind == GoTypeStruct && !a.IsMap) {
+			if _, err := e.singular(definitionsPrefix+jsonpointer.Escape(name), &def, exportedTypeName(name)); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+
+	var proto strings.Builder
+	proto.WriteString("syntax = \"proto3\";\n\n")
+	if opts.Package != "" {
+		fmt.Fprintf(&proto, "package %s;\n\n", opts.Package)
+	}
+
+	for _, imported := range sortedKeys(e.imports) {
+		fmt.Fprintf(&proto, "import %q;\n", imported)
+	}
+	if len(e.imports) > 0 {
+		proto.WriteString("\n")
+	}
+
+	for _, name := range sortedKeys(e.blocks) {
+		proto.WriteString(e.blocks[name])
+		proto.WriteString("\n")
+	}
+
+	sortFindings(e.findings)
+
+	return strings.TrimSuffix(proto.String(), "\n"), e.findings, nil
+}
+
+type protoExporter struct {
+	sp       *spec.Swagger
+	opts     ProtoOpts
+	findings []Finding
+
+	imports map[string]bool
+	// definitions of messages and enums, by name
+	blocks map[string]string
+	// messages being generated, to break cycles
+	pending map[string]bool
+	// definitions being inlined, to break cycles of aliases
+	inlining map[string]bool
+}
+
+func (e *protoExporter) report(pointer, code, format string, args ...interface{}) {
+	e.findings = appendFindingOnce(e.findings, Finding{Pointer: pointer, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+// wellKnown yields a well known protobuf type, importing its definition
+func (e *protoExporter) wellKnown(tpe string) string {
+	if tpe == protoTimestamp {
+		e.imports[protoTimestampImport] = true
+	} else {
+		e.imports[protoStructImport] = true
+	}
+
+	return tpe
+}
+
+// fieldType yields the type of a field, which may be repeated or a map. Inline messages and enums are named
+// after the given name.
+func (e *protoExporter) fieldType(pointer string, schema *spec.Schema, name string) (string, error) {
+	if ref := schema.Ref.String(); ref != "" {
+		def, target, ok := e.resolve(pointer, ref)
+		if !ok {
+			return e.wellKnown(protoValue), nil
+		}
+
+		if e.isNamed(target) || e.inlining[def] {
+			return e.singular(pointer, schema, name)
+		}
+
+		e.inlining[def] = true
+		defer delete(e.inlining, def)
+
+		return e.fieldType(definitionsPrefix+jsonpointer.Escape(def), target, exportedTypeName(def))
+	}
+
+	a, err := Schema(SchemaOpts{Schema: schema, Root: e.sp})
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case a.IsTuple || a.IsTupleWithExtra || isStringEnum(a):
+		return e.singular(pointer, schema, name)
+	case a.IsMap || a.GoTypeHint.Kind == GoTypeMap:
+		if len(schema.PatternProperties) > 0 {
+			e.report(pointer, CodeUnmappableMap, "the patterns which constrain the keys of a map are not represented")
+		}
+
+		value := &spec.Schema{}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			value = schema.AdditionalProperties.Schema
+		}
+
+		valueType, err := e.fieldType(pointer+"/additionalProperties", value, name+"Value")
+		if err != nil {
+			return "", err
+		}
+
+		if strings.HasPrefix(valueType, protoRepeatedPrefix) || strings.HasPrefix(valueType, protoMapPrefix) {
+			e.report(pointer, CodeUnmappableMap, "the values of a map may not be repeated fields or maps: mapped to %s", protoStruct)
+
+			return e.wellKnown(protoStruct), nil
+		}
+
+		return protoMapPrefix + "string, " + valueType + ">", nil
+	case a.GoTypeHint.Kind == GoTypeSlice && schema.Items != nil && schema.Items.Schema != nil:
+		elemType, err := e.fieldType(pointer+"/items", schema.Items.Schema, name+"Item")
+		if err != nil {
+			return "", err
+		}
+
+		if strings.HasPrefix(elemType, protoRepeatedPrefix) || strings.HasPrefix(elemType, protoMapPrefix) {
+			e.report(pointer, CodeUnmappableNesting, "the elements of a repeated field may not be repeated fields or maps: mapped to %s", protoListValue)
+
+			return protoRepeatedPrefix + e.wellKnown(protoListValue), nil
+		}
+
+		return protoRepeatedPrefix + elemType, nil
+	default:
+		return e.singular(pointer, schema, name)
+	}
+}
+
+// singular yields the type of a field which is neither repeated nor a map: a scalar, an enum, or a message
+func (e *protoExporter) singular(pointer string, schema *spec.Schema, name string) (string, error) {
+	if ref := schema.Ref.String(); ref != "" {
+		def, target, ok := e.resolve(pointer, ref)
+		if !ok {
+			return e.wellKnown(protoValue), nil
+		}
+
+		if !e.isNamed(target) {
+			// cycle of aliases
+			return e.wellKnown(protoValue), nil
+		}
+
+		return e.singular(definitionsPrefix+jsonpointer.Escape(def), target, exportedTypeName(def))
+	}
+
+	a, err := Schema(SchemaOpts{Schema: schema, Root: e.sp})
+	if err != nil {
+		return "", err
+	}
+
+	hint := a.GoTypeHint
+	switch {
+	case isStringEnum(a):
+		return e.enum(name, schema), nil
+	case a.IsTuple || a.IsTupleWithExtra:
+		e.report(pointer, CodeUnmappableTuple, "protobuf has no tuples: mapped to %s", protoListValue)
+
+		return e.wellKnown(protoListValue), nil
+	case hint.Kind == GoTypeStruct:
+		return e.message(pointer, schema, a, name)
+	case hint.Kind == GoTypeTime:
+		return e.wellKnown(protoTimestamp), nil
+	case hint.Kind == GoTypeBuiltin:
+		if scalar, ok := protoScalars[hint.Type]; ok {
+			return scalar, nil
+		}
+	}
+
+	return e.wellKnown(protoValue), nil
+}
+
+// resolve resolves a $ref to a local definition, reporting other $ref's
+func (e *protoExporter) resolve(pointer, ref string) (string, *spec.Schema, bool) {
+	def, isDefinition := definitionOfPointer(ref)
+	target, found := e.sp.Definitions[def]
+	if !isDefinition || !found || ref != definitionsPrefix+jsonpointer.Escape(def) {
+		e.report(pointer, CodeUnmappableRef, "only $ref's to local definitions are mapped: %s is mapped to %s", ref, protoValue)
+
+		return "", nil, false
+	}
+
+	return def, &target, true
+}
+
+// isNamed tells if a definition maps to a message or an enum. Other definitions are inlined where they are referred to.
+func (e *protoExporter) isNamed(schema *spec.Schema) bool {
+	if schema.Ref.String() != "" {
+		return false
+	}
+
+	a, err := Schema(SchemaOpts{Schema: schema, Root: e.sp})
+	if err != nil {
+		return false
+	}
+
+	return isStringEnum(a) || (a.GoTypeHint.Kind == GoTypeStruct && !a.IsMap)
+}
+
+// protoComment renders a description as comment lines
+func protoComment(indent, description string) string {
+	if description == "" {
+		return ""
+	}
+
+	var comment strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+		comment.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+	}
+
+	return comment.String()
+}
+
+// enum generates an enum, and yields its name
+func (e *protoExporter) enum(name string, schema *spec.Schema) string {
+	if _, exists := e.blocks[name]; exists {
+		return name
+	}
+
+	prefix := strings.ToUpper(swag.ToFileName(name)) + "_"
+
+	var proto strings.Builder
+	proto.WriteString(protoComment("", schema.Description))
+	fmt.Fprintf(&proto, "enum %s {\n", name)
+	fmt.Fprintf(&proto, "  %sUNSPECIFIED = 0;\n", prefix)
+
+	seen := map[string]bool{prefix + "UNSPECIFIED": true}
+	number := 1
+	for _, value := range schema.Enum {
+		str, isString := value.(string)
+		if !isString {
+			continue
+		}
+
+		symbol := prefix + strings.ToUpper(swag.ToFileName(str))
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+
+		fmt.Fprintf(&proto, "  %s = %d;\n", symbol, number)
+		number++
+	}
+	proto.WriteString("}\n")
+
+	e.blocks[name] = proto.String()
+
+	return name
+}
+
+// message generates a message, and yields its name. Objects without any property are mapped to google.protobuf.Struct.
+func (e *protoExporter) message(pointer string, schema *spec.Schema, a *AnalyzedSchema, name string) (string, error) {
+	if _, exists := e.blocks[name]; exists || e.pending[name] {
+		return name, nil
+	}
+
+	var fields []objectField
+	var bases []string
+	collectObjectFields(e.sp, pointer, name, schema, &fields, &bases, make(map[string]bool))
+
+	if len(fields) == 0 {
+		return e.wellKnown(protoStruct), nil
+	}
+
+	e.pending[name] = true
+	defer delete(e.pending, name)
+
+	if a.IsBaseType {
+		e.report(pointer, CodeUnmappablePolymorphism,
+			"protobuf messages have no inheritance: the messages of subtypes hold the fields of %s, and %s holds none of theirs", name, name)
+	}
+
+	numbers := e.fieldNumbers(fields)
+
+	var proto strings.Builder
+	proto.WriteString(protoComment("", schema.Description))
+	fmt.Fprintf(&proto, "message %s {\n", name)
+
+	for i, field := range fields {
+		tpe, err := e.fieldType(field.pointer, field.schema, field.owner+swag.ToGoName(field.name))
+		if err != nil {
+			return "", err
+		}
+
+		fieldName := e.fieldName(field.pointer, field.name)
+		var options string
+		if jsonName := protoJSONName(fieldName); jsonName != field.name {
+			options = fmt.Sprintf(" [json_name = %q]", field.name)
+		}
+
+		proto.WriteString(protoComment("  ", field.schema.Description))
+		fmt.Fprintf(&proto, "  %s %s = %d%s;\n", tpe, fieldName, numbers[i], options)
+	}
+	proto.WriteString("}\n")
+
+	e.blocks[name] = proto.String()
+
+	return name, nil
+}
+
+// fieldName yields the snake case name of a field
+func (e *protoExporter) fieldName(pointer, name string) string {
+	fieldName := swag.ToFileName(name)
+	if !rexIdentifier.MatchString(fieldName) || strings.HasPrefix(fieldName, "_") {
+		renamed := "field_" + strings.TrimLeft(fieldName, "_")
+		if !rexIdentifier.MatchString(renamed) {
+			renamed = "field_" + strconv.Itoa(int(fnv32(name)))
+		}
+		e.report(pointer, CodeUnmappableName, "%q is not a valid protobuf field name: renamed %s", name, renamed)
+		fieldName = renamed
+	}
+
+	return fieldName
+}
+
+// protoJSONName yields the default JSON name protoc gives to a field, i.e. its name in lower camel case
+func protoJSONName(fieldName string) string {
+	var jsonName strings.Builder
+	upper := false
+	for _, r := range fieldName {
+		if r == '_' {
+			upper = true
+
+			continue
+		}
+
+		if upper {
+			jsonName.WriteString(strings.ToUpper(string(r)))
+			upper = false
+
+			continue
+		}
+
+		jsonName.WriteRune(r)
+	}
+
+	return jsonName.String()
+}
+
+func fnv32(name string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+
+	return h.Sum32()
+}
+
+func isValidFieldNumber(number int) bool {
+	return number >= 1 && number <= protoMaxFieldNumber &&
+		(number < protoReservedRangeStart || number > protoReservedRangeEnd)
+}
+
+// fieldNumbers numbers the fields of a message, according to the numbering strategy
+func (e *protoExporter) fieldNumbers(fields []objectField) []int {
+	numbers := make([]int, len(fields))
+	used := make(map[int]bool, len(fields))
+
+	// next yields the first free number, from a given number
+	next := func(number int) int {
+		for ; !isValidFieldNumber(number) || used[number]; number++ {
+			if number > protoMaxFieldNumber {
+				number = 0
+			}
+		}
+		used[number] = true
+
+		return number
+	}
+
+	switch e.opts.Numbering {
+	case ProtoNumberingHash:
+		for i, field := range fields {
+			numbers[i] = next(int(fnv32(field.name)%(protoMaxFieldNumber-1)) + 1)
+		}
+	case ProtoNumberingExtension:
+		highest := 0
+		for i, field := range fields {
+			value, declared := lookupExtension(field.schema.Extensions, protoFieldExtension)
+			if !declared {
+				continue
+			}
+
+			number, isNumber := asFieldNumber(value)
+			switch {
+			case !isNumber || !isValidFieldNumber(number):
+				e.report(field.pointer, CodeInvalidFieldNumber, "%v is not a valid field number", value)
+			case used[number]:
+				e.report(field.pointer, CodeInvalidFieldNumber, "field number %d is already used in this message", number)
+			default:
+				numbers[i] = number
+				used[number] = true
+				if number > highest {
+					highest = number
+				}
+			}
+		}
+
+		for i := range fields {
+			if numbers[i] == 0 {
+				numbers[i] = next(highest + 1)
+			}
+		}
+	default:
+		for i := range fields {
+			numbers[i] = next(1)
+		}
+	}
+
+	return numbers
+}
+
+// asFieldNumber converts the value of an extension to an integer
+func asFieldNumber(value interface{}) (int, bool) {
+	switch number := value.(type) {
+	case float64:
+		if number != math.Trunc(number) || number > protoMaxFieldNumber {
+			return 0, false
+		}
+
+		return int(number), true
+	case int:
+		return number, true
+	case int64:
+		return int(number), number <= protoMaxFieldNumber
+	case string:
+		n, err := strconv.Atoi(number)
+
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,132 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportProto(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "proto.yml"))
+
+	t.Run("should number fields sequentially", func(t *testing.T) {
+		t.Parallel()
+
+		proto, findings, err := ExportProto(sp, ProtoOpts{Package: "pets"})
+		require.NoError(t, err)
+
+		assert.Equal(t, `syntax = "proto3";
+
+package pets;
+
+import "google/protobuf/struct.proto";
+import "google/protobuf/timestamp.proto";
+
+message Dog {
+  int32 age = 1;
+  google.protobuf.Timestamp birth_date = 2;
+  string kind = 3;
+  map<string, string> labels = 4;
+  // the name of the pet
+  string name = 5;
+  PetOwner owner = 6;
+  google.protobuf.ListValue position = 7;
+  Status status = 8;
+  repeated string tags = 9;
+  double weight = 10;
+  float bark_volume = 11 [json_name = "bark-volume"];
+  google.protobuf.Struct groups = 12;
+  repeated google.protobuf.ListValue matrix = 13;
+}
+
+message Numbered {
+  string a = 1;
+  string b = 2;
+  string c = 3;
+}
+
+// a pet
+message Pet {
+  int32 age = 1;
+  google.protobuf.Timestamp birth_date = 2;
+  string kind = 3;
+  map<string, string> labels = 4;
+  // the name of the pet
+  string name = 5;
+  PetOwner owner = 6;
+  google.protobuf.ListValue position = 7;
+  Status status = 8;
+  repeated string tags = 9;
+  double weight = 10;
+}
+
+message PetOwner {
+  int64 id = 1;
+  repeated Pet pets = 2;
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_AVAILABLE = 1;
+  STATUS_SOLD_OUT = 2;
+}
+`, proto)
+
+		codes := make([]string, 0, len(findings))
+		for _, finding := range findings {
+			codes = append(codes, finding.Pointer+" "+finding.Code)
+		}
+
+		assert.Equal(t, []string{
+			"#/definitions/dog/allOf/1/properties/groups " + CodeUnmappableMap,
+			"#/definitions/dog/allOf/1/properties/matrix " + CodeUnmappableNesting,
+			"#/definitions/pet " + CodeUnmappablePolymorphism,
+			"#/definitions/pet/properties/position " + CodeUnmappableTuple,
+		}, codes)
+	})
+
+	t.Run("should number fields from a hash of their name", func(t *testing.T) {
+		t.Parallel()
+
+		proto, _, err := ExportProto(sp, ProtoOpts{Numbering: ProtoNumberingHash})
+		require.NoError(t, err)
+
+		assert.Contains(t, proto, "string kind = 420733520;")
+		assert.NotContains(t, proto, "package")
+
+		// numbers do not depend on the other properties
+		subset := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{
+			"pet": *new(spec.Schema).Typed("object", "").SetProperty("kind", *spec.StringProperty()),
+		}}}
+		other, _, err := ExportProto(subset, ProtoOpts{Numbering: ProtoNumberingHash})
+		require.NoError(t, err)
+		assert.Contains(t, other, "string kind = 420733520;")
+	})
+
+	t.Run("should number fields from extensions", func(t *testing.T) {
+		t.Parallel()
+
+		proto, findings, err := ExportProto(sp, ProtoOpts{Numbering: ProtoNumberingExtension})
+		require.NoError(t, err)
+
+		assert.Contains(t, proto, "message Numbered {\n  string a = 3;\n  string b = 4;\n  string c = 5;\n}\n")
+		assert.Contains(t, findings, Finding{
+			Pointer: "#/definitions/numbered/properties/c",
+			Code:    CodeInvalidFieldNumber,
+			Message: "19500 is not a valid field number",
+		})
+	})
+
+	t.Run("should reject an unknown numbering strategy", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := ExportProto(sp, ProtoOpts{Numbering: "random"})
+		require.Error(t, err)
+	})
+}
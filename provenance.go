@@ -0,0 +1,96 @@
+package analysis
+
+import (
+	"strings"
+)
+
+// Provenance locates the origin of an element of an analyzed spec, when this spec has been assembled
+// from several documents (e.g. remote definitions imported by Flatten)
+type Provenance struct {
+	// Document is the file path or URL of the source document. It is empty when unknown.
+	Document string
+
+	// Pointer locates the element in the source document (e.g. "#/definitions/pet/properties/name")
+	Pointer string
+}
+
+// ProvenanceOf locates the origin of an element of the spec, given its pointer (e.g. "#/definitions/pet").
+//
+// Flatten records the origin of the definitions it imports from remote documents, and of the inline schemas
+// it moves to definitions, as well as the location of the root document (from FlattenOpts.BasePath).
+// Elements without any recorded origin come from the same location in the root document.
+//
+// Use SetProvenance to record the origin of elements when assembling a spec by other means (e.g. with Mixin).
+func (s *Spec) ProvenanceOf(pointer string) Provenance {
+	// origins within the spec itself are followed, e.g. a definition lifted from an inline schema which
+	// was itself imported, at most once per recorded origin
+	for hops := 0; hops <= len(s.provenance); hops++ {
+		origin, found := s.recordedProvenance(pointer)
+		if !found {
+			return Provenance{Pointer: pointer}
+		}
+
+		if origin.Document != "" {
+			return origin
+		}
+
+		pointer = origin.Pointer
+	}
+
+	return Provenance{Pointer: pointer}
+}
+
+// SetProvenance records the origin of an element of the spec, and of all the elements it contains.
+//
+// An empty Document stands for another location in the spec itself. Use the pointer "#" to record
+// the location of the root document.
+//
+// SetProvenance fails with ErrFrozenSpec when the spec is frozen.
+func (s *Spec) SetProvenance(pointer string, origin Provenance) error {
+	if err := s.mutable(); err != nil {
+		return err
+	}
+
+	s.setProvenance(pointer, origin)
+
+	return nil
+}
+
+// setProvenance records the origin of an element of a spec known to be mutable
+func (s *Spec) setProvenance(pointer string, origin Provenance) {
+	if s.provenance == nil {
+		s.provenance = make(map[string]Provenance)
+	}
+
+	s.provenance[pointer] = origin
+}
+
+// recordedProvenance yields the origin of the closest container of an element with a recorded origin
+func (s *Spec) recordedProvenance(pointer string) (Provenance, bool) {
+	for container := pointer; ; {
+		if origin, found := s.provenance[container]; found {
+			return Provenance{Document: origin.Document, Pointer: origin.Pointer + strings.TrimPrefix(pointer, container)}, true
+		}
+
+		i := strings.LastIndexByte(container, '/')
+		if i < 0 {
+			return Provenance{}, false
+		}
+		container = container[:i]
+	}
+}
+
+// recordProvenance records the origin of the definitions created while flattening
+func (s *Spec) recordProvenance(event Event) {
+	switch event.Kind {
+	case EventSchemaLifted, EventDefinitionRenamed:
+		s.setProvenance(event.Ref, Provenance{Pointer: event.Pointer})
+	case EventDefinitionImported:
+		document, fragment, _ := strings.Cut(event.Ref, "#")
+		origin := Provenance{Pointer: "#" + fragment}
+		if document != "" {
+			origin.Document = documentLocation(document)
+		}
+		s.setProvenance(event.Pointer, origin)
+	}
+}
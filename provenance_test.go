@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_ProvenanceOf(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should locate imported definitions in remote documents", func(t *testing.T) {
+		t.Parallel()
+
+		bp := filepath.Join("fixtures", "external_definitions_valid.yml")
+		an := New(antest.LoadOrFail(t, bp))
+		require.NoError(t, Flatten(FlattenOpts{Spec: an, BasePath: bp, Minimal: true}))
+
+		definitions, err := filepath.Abs(filepath.Join("fixtures", "external", "definitions.yml"))
+		require.NoError(t, err)
+		root, err := filepath.Abs(bp)
+		require.NoError(t, err)
+
+		assert.Equal(t, Provenance{Document: definitions, Pointer: "#/definitions/record"}, an.ProvenanceOf("#/definitions/record"))
+		assert.Equal(t,
+			Provenance{Document: definitions, Pointer: "#/definitions/record/properties/createdAt"},
+			an.ProvenanceOf("#/definitions/record/properties/createdAt"),
+		)
+		assert.Equal(t, Provenance{Document: root, Pointer: "#/paths/~1some~1where~1{id}"}, an.ProvenanceOf("#/paths/~1some~1where~1{id}"))
+	})
+
+	t.Run("should locate the inline schemas moved to definitions", func(t *testing.T) {
+		t.Parallel()
+
+		bp := filepath.Join("fixtures", "flatten_verify.yml")
+		an := New(antest.LoadOrFail(t, bp))
+		require.NoError(t, Flatten(FlattenOpts{Spec: an, BasePath: bp}))
+
+		root, err := filepath.Abs(bp)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			Provenance{Document: root, Pointer: "#/paths/~1pets/get/responses/200/schema/items/properties/name"},
+			an.ProvenanceOf("#/definitions/getPetsOKBodyItems/properties/name"),
+		)
+	})
+
+	t.Run("should locate elements with a recorded provenance", func(t *testing.T) {
+		t.Parallel()
+
+		an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "reachability.yml")))
+		assert.Equal(t, Provenance{Pointer: "#/definitions/pet"}, an.ProvenanceOf("#/definitions/pet"))
+
+		require.NoError(t, an.SetProvenance("#/definitions/pet", Provenance{Document: "https://example.com/pets.json", Pointer: "#/pet"}))
+		require.NoError(t, an.SetProvenance("#/definitions/owner", Provenance{Pointer: "#/definitions/pet/properties/owner"}))
+
+		assert.Equal(t, Provenance{Document: "https://example.com/pets.json", Pointer: "#/pet/properties/owner"}, an.ProvenanceOf("#/definitions/owner"))
+		assert.Equal(t, Provenance{Pointer: "#/definitions/error"}, an.ProvenanceOf("#/definitions/error"))
+	})
+}
@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// sections of a swagger document holding shared components, which may be referred to with a $ref
+var componentSections = []string{"definitions", "parameters", "responses"}
+
+// Prune removes the shared definitions, parameters and responses which are no longer reachable
+// from the paths of a spec, e.g. after operations have been deleted or filtered out.
+//
+// Reachability is transitive: a definition only used by an unused shared parameter, or by another unused
+// definition, is removed as well. Only local $ref's are followed.
+//
+// This is the RemoveUnused option of Flatten, without flattening the spec.
+//
+// The spec is modified in place. Prune returns the JSON pointers to all removed components
+// (e.g. "#/definitions/pet"), sorted.
+func Prune(sp *spec.Swagger) []string {
+	if sp == nil {
+		return nil
+	}
+
+	reachable := reachableComponents(sp)
+
+	var removed []string
+	prune := func(section string, names []string, remove func(string)) {
+		for _, name := range names {
+			pointer := "#/" + section + "/" + jsonpointer.Escape(name)
+			if reachable[pointer] {
+				continue
+			}
+
+			remove(name)
+			removed = append(removed, pointer)
+		}
+	}
+
+	prune("definitions", sortedKeys(sp.Definitions), func(name string) { delete(sp.Definitions, name) })
+	prune("parameters", sortedKeys(sp.Parameters), func(name string) { delete(sp.Parameters, name) })
+	prune("responses", sortedKeys(sp.Responses), func(name string) { delete(sp.Responses, name) })
+
+	sort.Strings(removed)
+
+	return removed
+}
+
+// reachableComponents determines the shared components (e.g. "#/definitions/pet") which may be reached
+// by following $ref's from outside shared components (e.g. from paths), or from some extra roots.
+func reachableComponents(sp *spec.Swagger, roots ...string) map[string]bool {
+	an := newSpec(sp)
+	refsFrom := make(map[string][]string) // component -> referred components; "" for references from elsewhere
+	for key, ref := range an.references.allRefs {
+		target, isComponent := componentOfPointer(ref.String())
+		if !isComponent {
+			continue
+		}
+
+		from, _ := componentOfPointer(key)
+		refsFrom[from] = append(refsFrom[from], target)
+	}
+
+	reachable := make(map[string]bool)
+	pending := append(append([]string{}, roots...), refsFrom[""]...)
+	for len(pending) > 0 {
+		pointer := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if reachable[pointer] {
+			continue
+		}
+
+		reachable[pointer] = true
+		pending = append(pending, refsFrom[pointer]...)
+	}
+
+	return reachable
+}
+
+// componentOfPointer yields the shared component a local JSON pointer belongs to,
+// e.g. "#/definitions/pet" for "#/definitions/pet/properties/name"
+func componentOfPointer(pointer string) (string, bool) {
+	for _, section := range componentSections {
+		prefix := "#/" + section + "/"
+		if !strings.HasPrefix(pointer, prefix) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(strings.TrimPrefix(pointer, prefix), "/")
+
+		return prefix + name, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,43 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "prune.yml"))
+
+	assert.Equal(t, []string{
+		"#/definitions/orphanDetail",
+		"#/definitions/orphanPayload",
+		"#/definitions/selfish",
+		"#/parameters/orphanBody",
+		"#/responses/gone",
+	}, Prune(sp))
+
+	assert.Equal(t, []string{"error", "owner", "pet"}, sortedKeys(sp.Definitions))
+	assert.Equal(t, []string{"petId"}, sortedKeys(sp.Parameters))
+	assert.Equal(t, []string{"notFound"}, sortedKeys(sp.Responses))
+
+	// pruning is idempotent
+	assert.Empty(t, Prune(sp))
+	assert.Empty(t, Prune(nil))
+}
+
+func TestPrune_AfterEdits(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "prune.yml"))
+	delete(sp.Paths.Paths, "/pets/{petId}")
+
+	assert.Len(t, Prune(sp), 10)
+	assert.Empty(t, sp.Definitions)
+	assert.Empty(t, sp.Parameters)
+	assert.Empty(t, sp.Responses)
+}
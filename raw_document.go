@@ -0,0 +1,203 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// DataLoss is some data found in a raw document which the typed model (spec.Swagger) does not represent,
+// e.g. an unknown field which is not a vendor extension
+type DataLoss struct {
+	// Pointer is the location of the data in the raw document (e.g. "#/paths/~1pets/get/x-unknown")
+	Pointer string
+
+	// Value is the original value at this location
+	Value interface{}
+}
+
+// RawDocument is a swagger specification decoded as generic JSON (e.g. a map[string]interface{} or a
+// json.RawMessage), analyzed through the typed model.
+//
+// The data which does not survive the conversion to the typed model is retained, and restored by Map:
+// unknown fields are preserved through transformations such as Flatten.
+type RawDocument struct {
+	// Swagger is the typed model of the document. Transformations apply to this spec.
+	Swagger *spec.Swagger
+
+	// Spec is the analyzer for this specification
+	Spec *Spec
+
+	// Losses reports the data of the raw document which the typed model does not represent, sorted by pointer
+	Losses []DataLoss
+}
+
+// NewRawDocument builds the analyzer for a raw document: a map[string]interface{}, a json.RawMessage or
+// a []byte with JSON content.
+func NewRawDocument(doc interface{}) (*RawDocument, error) {
+	var raw []byte
+	switch value := doc.(type) {
+	case json.RawMessage:
+		raw = value
+	case []byte:
+		raw = value
+	case map[string]interface{}:
+		var err error
+		if raw, err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedDocument, doc)
+	}
+
+	var original interface{}
+	if err := json.Unmarshal(raw, &original); err != nil {
+		return nil, err
+	}
+
+	var sp spec.Swagger
+	if err := json.Unmarshal(raw, &sp); err != nil {
+		return nil, err
+	}
+
+	converted, err := genericJSON(&sp)
+	if err != nil {
+		return nil, err
+	}
+
+	losses := jsonDataLoss(original, converted, "#", nil)
+	sort.SliceStable(losses, func(i, j int) bool { return losses[i].Pointer < losses[j].Pointer })
+
+	return &RawDocument{Swagger: &sp, Spec: New(&sp), Losses: losses}, nil
+}
+
+// Flatten flattens the document, like the Flatten function. The Spec option is set to the analyzer of the document.
+func (d *RawDocument) Flatten(opts FlattenOpts) error {
+	opts.Spec = d.Spec
+
+	return Flatten(opts)
+}
+
+// Map converts the document back to generic JSON, restoring the data lost by the typed model.
+//
+// Some lost data may no longer be restored when its parent has been removed by some transformation
+// (e.g. a definition removed by flattening): Map returns the data which could not be restored.
+func (d *RawDocument) Map() (map[string]interface{}, []DataLoss, error) {
+	converted, err := genericJSON(d.Swagger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, isMap := converted.(map[string]interface{})
+	if !isMap {
+		doc = make(map[string]interface{})
+	}
+
+	var unrestored []DataLoss
+	for _, loss := range d.Losses {
+		if !restoreJSON(doc, loss) {
+			unrestored = append(unrestored, loss)
+		}
+	}
+
+	return doc, unrestored, nil
+}
+
+// genericJSON converts a value to generic JSON
+func genericJSON(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+
+	return doc, json.Unmarshal(raw, &doc)
+}
+
+// jsonDataLoss reports the data found in an original generic JSON document, which is missing or altered
+// in a converted one
+func jsonDataLoss(original, converted interface{}, pointer string, losses []DataLoss) []DataLoss {
+	switch value := original.(type) {
+	case map[string]interface{}:
+		convertedMap, isMap := converted.(map[string]interface{})
+		if !isMap {
+			return append(losses, DataLoss{Pointer: pointer, Value: original})
+		}
+
+		for _, key := range sortedKeys(value) {
+			child := pointer + "/" + jsonpointer.Escape(key)
+			convertedValue, found := convertedMap[key]
+			if !found {
+				losses = append(losses, DataLoss{Pointer: child, Value: value[key]})
+
+				continue
+			}
+
+			losses = jsonDataLoss(value[key], convertedValue, child, losses)
+		}
+	case []interface{}:
+		convertedSlice, isSlice := converted.([]interface{})
+		if !isSlice || len(convertedSlice) != len(value) {
+			return append(losses, DataLoss{Pointer: pointer, Value: original})
+		}
+
+		for i, item := range value {
+			losses = jsonDataLoss(item, convertedSlice[i], pointer+"/"+strconv.Itoa(i), losses)
+		}
+	default:
+		if !reflect.DeepEqual(original, converted) {
+			losses = append(losses, DataLoss{Pointer: pointer, Value: original})
+		}
+	}
+
+	return losses
+}
+
+// restoreJSON restores some lost data into a generic JSON document. Only missing fields of existing objects
+// are restored: altered values are left untouched.
+func restoreJSON(doc map[string]interface{}, loss DataLoss) bool {
+	if loss.Pointer == "#" {
+		return false
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(loss.Pointer, "#/"), "/")
+	var parent interface{} = doc
+	for _, token := range tokens[:len(tokens)-1] {
+		token = jsonpointer.Unescape(token)
+
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			parent = container[token]
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(container) {
+				return false
+			}
+
+			parent = container[i]
+		default:
+			return false
+		}
+	}
+
+	container, isMap := parent.(map[string]interface{})
+	if !isMap {
+		return false
+	}
+
+	key := jsonpointer.Unescape(tokens[len(tokens)-1])
+	if _, exists := container[key]; exists {
+		return false
+	}
+
+	container[key] = loss.Value
+
+	return true
+}
@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rawDocumentFixture = `{
+  "swagger": "2.0",
+  "info": {"title": "raw", "version": "1.0", "x-logo": "logo.png"},
+  "tags-metadata": {"pets": "the pets"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "rate-limit": 10,
+        "responses": {
+          "200": {
+            "description": "pets",
+            "schema": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": "string"}}}}
+          }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "unused": {"type": "object", "x-note": "kept"},
+    "legacy": {"type": "string", "x-note": "legacy"}
+  },
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer", "deprecated-since": "1.2"}
+  }
+}`
+
+func TestRawDocument(t *testing.T) {
+	t.Parallel()
+
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawDocumentFixture), &raw))
+
+	doc, err := NewRawDocument(raw)
+	require.NoError(t, err)
+	require.NotNil(t, doc.Spec)
+	assert.Equal(t, []DataLoss{
+		{Pointer: "#/parameters/limit/deprecated-since", Value: "1.2"},
+		{Pointer: "#/paths/~1pets/get/rate-limit", Value: float64(10)},
+		{Pointer: "#/tags-metadata", Value: map[string]interface{}{"pets": "the pets"}},
+	}, doc.Losses)
+
+	require.NoError(t, doc.Flatten(FlattenOpts{}))
+
+	result, unrestored, err := doc.Map()
+	require.NoError(t, err)
+	assert.Empty(t, unrestored)
+
+	assert.Equal(t, map[string]interface{}{"pets": "the pets"}, result["tags-metadata"])
+	get := result["paths"].(map[string]interface{})["/pets"].(map[string]interface{})["get"].(map[string]interface{})
+	assert.Equal(t, float64(10), get["rate-limit"])
+	limit := result["parameters"].(map[string]interface{})["limit"].(map[string]interface{})
+	assert.Equal(t, "1.2", limit["deprecated-since"])
+	assert.Equal(t, "logo.png", result["info"].(map[string]interface{})["x-logo"])
+
+	// the inline schema has been flattened
+	definitions := result["definitions"].(map[string]interface{})
+	assert.Contains(t, definitions, "listPetsOKBodyItems")
+	assert.Equal(t, "kept", definitions["unused"].(map[string]interface{})["x-note"])
+}
+
+func TestRawDocument_Unrestored(t *testing.T) {
+	t.Parallel()
+
+	doc, err := NewRawDocument(json.RawMessage(`{
+  "swagger": "2.0",
+  "info": {"title": "raw", "version": "1.0"},
+  "paths": {},
+  "parameters": {"limit": {"name": "limit", "in": "query", "type": "integer", "deprecated-since": "1.2"}}
+}`))
+	require.NoError(t, err)
+	require.Len(t, doc.Losses, 1)
+
+	delete(doc.Swagger.Parameters, "limit")
+
+	_, unrestored, err := doc.Map()
+	require.NoError(t, err)
+	assert.Equal(t, doc.Losses, unrestored)
+}
+
+func TestRawDocument_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewRawDocument("swagger: '2.0'")
+	require.ErrorIs(t, err, ErrUnsupportedDocument)
+
+	_, err = NewRawDocument([]byte(`{"swagger":`))
+	require.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// SchemasReachableFrom returns the names of the definitions an operation depends on, directly or transitively:
+// the definitions referred to by its parameters (including those of its path item) and responses, and
+// the definitions these refer to in turn. Names are sorted.
+//
+// This returns nil when the operation does not belong to the spec.
+func (s *Spec) SchemasReachableFrom(op *spec.Operation) []string {
+	key, found := s.operationKeyOf(op)
+	if !found {
+		return nil
+	}
+
+	definitions := s.definitionsReachableFrom(s.refGraph(), key)
+	names := make([]string, 0, len(definitions))
+	for pointer := range definitions {
+		name, _ := definitionOfPointer(pointer)
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SchemaConsumers returns the operations which depend on a definition, directly or transitively
+// (see SchemasReachableFrom), i.e. the operations affected by a change to this definition.
+// Operations are sorted by path, then by method.
+func (s *Spec) SchemaConsumers(definitionName string) []OperationKey {
+	if _, exists := s.spec.Definitions[definitionName]; !exists {
+		return nil
+	}
+
+	g := s.refGraph()
+	pointer := definitionsPrefix + jsonpointer.Escape(definitionName)
+
+	var consumers []OperationKey
+	for _, key := range s.sortedOperationKeys() {
+		if s.definitionsReachableFrom(g, key)[pointer] {
+			consumers = append(consumers, key)
+		}
+	}
+
+	return consumers
+}
+
+// definitionsReachableFrom yields the pointers to the definitions reachable from an operation and its path item
+func (s *Spec) definitionsReachableFrom(g *refGraph, key OperationKey) map[string]bool {
+	definitions := make(map[string]bool)
+	for _, from := range []string{key.pointer(), "#/paths/" + jsonpointer.Escape(key.Path)} {
+		for node := range g.reachable(from) {
+			if g.nodes[node].kind == refNodeDefinition {
+				definitions[node] = true
+			}
+		}
+	}
+
+	return definitions
+}
+
+// operationKeyOf locates an operation of the spec
+func (s *Spec) operationKeyOf(op *spec.Operation) (OperationKey, bool) {
+	if op == nil {
+		return OperationKey{}, false
+	}
+
+	for method, byPath := range s.operations {
+		for path, candidate := range byPath {
+			if candidate == op {
+				return OperationKey{Method: method, Path: path}, true
+			}
+		}
+	}
+
+	return OperationKey{}, false
+}
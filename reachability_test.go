@@ -0,0 +1,54 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_SchemasReachableFrom(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "reachability.yml")))
+
+	for _, toPin := range []struct {
+		method   string
+		path     string
+		expected []string
+	}{
+		{method: "GET", path: "/pets", expected: []string{"error", "owner", "pet"}},
+		{method: "POST", path: "/pets", expected: []string{}},
+		{method: "PUT", path: "/pets/{id}", expected: []string{"owner", "pet"}},
+	} {
+		tc := toPin
+
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			op, found := an.OperationFor(tc.method, tc.path)
+			require.True(t, found)
+			assert.Equal(t, tc.expected, an.SchemasReachableFrom(op))
+		})
+	}
+
+	assert.Nil(t, an.SchemasReachableFrom(&spec.Operation{}))
+	assert.Nil(t, an.SchemasReachableFrom(nil))
+}
+
+func TestAnalyzer_SchemaConsumers(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "reachability.yml")))
+
+	assert.Equal(t, []OperationKey{
+		{Method: "GET", Path: "/pets"},
+		{Method: "PUT", Path: "/pets/{id}"},
+	}, an.SchemaConsumers("owner"))
+	assert.Equal(t, []OperationKey{{Method: "GET", Path: "/pets"}}, an.SchemaConsumers("error"))
+	assert.Empty(t, an.SchemaConsumers("unused"))
+	assert.Nil(t, an.SchemaConsumers("missing"))
+}
@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"net/url"
+	slashpath "path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// RebaseRefs rewrites the relative remote $ref's of a spec moved from one location to another, e.g. to another
+// directory or to another URL root, so they still refer to the same documents.
+//
+// Locations are file paths or URLs. Local $ref's (e.g. "#/definitions/pet") and absolute $ref's are retained.
+// When the new location does not allow for a relative $ref (e.g. the spec moves from a local file to a remote
+// server, or to another host), the $ref is made absolute.
+//
+// The spec is modified in place.
+func RebaseRefs(sp *spec.Swagger, oldBase, newBase string) error {
+	if sp == nil {
+		return nil
+	}
+
+	oldBase = absoluteBasePath(oldBase)
+	newBase = absoluteBasePath(newBase)
+
+	return rewriteSpecRefs(sp, func(ref string) string {
+		if strings.HasPrefix(ref, "#") || isAbsoluteLocation(ref) {
+			return ref
+		}
+
+		target, fragment := refDocument(oldBase, ref)
+		rebased, ok := relativeLocation(newBase, target)
+		if !ok {
+			rebased = target
+		}
+
+		if fragment == "" {
+			return rebased
+		}
+
+		return rebased + "#" + fragment
+	})
+}
+
+// isAbsoluteLocation tells if a $ref locates a document regardless of the location of the document it is found in
+func isAbsoluteLocation(ref string) bool {
+	if u, err := url.Parse(ref); err == nil && len(u.Scheme) > 1 {
+		return true
+	}
+
+	return filepath.IsAbs(ref) || strings.HasPrefix(ref, "/")
+}
+
+// relativeLocation yields the location of a target document, relative to the document found at base.
+// This is only possible when both documents are local files, or are served from the same host.
+func relativeLocation(base, target string) (string, bool) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", false
+	}
+
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return "", false
+	}
+
+	if isLocal(baseURL) != isLocal(targetURL) {
+		return "", false
+	}
+
+	if isLocal(baseURL) {
+		rel, err := filepath.Rel(filepath.Dir(strings.TrimPrefix(base, "file://")), strings.TrimPrefix(target, "file://"))
+		if err != nil {
+			return "", false
+		}
+
+		return filepath.ToSlash(rel), true
+	}
+
+	if baseURL.Scheme != targetURL.Scheme || baseURL.Host != targetURL.Host {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(filepath.FromSlash(slashpath.Dir(baseURL.Path)), filepath.FromSlash(targetURL.Path))
+	if err != nil {
+		return "", false
+	}
+
+	return filepath.ToSlash(rel), true
+}
+
+// isLocal tells if a location is a local file
+func isLocal(u *url.URL) bool {
+	// single letter schemes are Windows drive letters
+	return u.Scheme == "" || u.Scheme == "file" || len(u.Scheme) == 1
+}
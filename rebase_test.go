@@ -0,0 +1,81 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebaseRefs(t *testing.T) {
+	t.Parallel()
+
+	newSpec := func() *spec.Swagger {
+		return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Definitions: spec.Definitions{
+				"local":    *spec.RefSchema("#/definitions/pet"),
+				"sibling":  *spec.RefSchema("models.yml#/definitions/pet"),
+				"nested":   *spec.RefSchema("common/models.yml#/definitions/pet"),
+				"whole":    *spec.RefSchema("../shared/pet.yml"),
+				"absolute": *spec.RefSchema("https://example.com/schemas/pet.json"),
+			},
+		}}
+	}
+
+	refs := func(sp *spec.Swagger) map[string]string {
+		result := make(map[string]string, len(sp.Definitions))
+		for name, schema := range sp.Definitions {
+			result[name] = schema.Ref.String()
+		}
+
+		return result
+	}
+
+	t.Run("to another directory", func(t *testing.T) {
+		t.Parallel()
+
+		sp := newSpec()
+		require.NoError(t, RebaseRefs(sp, filepath.Join("specs", "api", "spec.yml"), filepath.Join("out", "v1", "spec.yml")))
+		assert.Equal(t, map[string]string{
+			"local":    "#/definitions/pet",
+			"sibling":  "../../specs/api/models.yml#/definitions/pet",
+			"nested":   "../../specs/api/common/models.yml#/definitions/pet",
+			"whole":    "../../specs/shared/pet.yml",
+			"absolute": "https://example.com/schemas/pet.json",
+		}, refs(sp))
+
+		// moving back restores the original $ref's
+		require.NoError(t, RebaseRefs(sp, filepath.Join("out", "v1", "spec.yml"), filepath.Join("specs", "api", "spec.yml")))
+		assert.Equal(t, refs(newSpec()), refs(sp))
+	})
+
+	t.Run("to another URL root", func(t *testing.T) {
+		t.Parallel()
+
+		sp := newSpec()
+		require.NoError(t, RebaseRefs(sp, "https://example.com/api/v1/spec.yml", "https://example.com/spec.yml"))
+		assert.Equal(t, map[string]string{
+			"local":    "#/definitions/pet",
+			"sibling":  "api/v1/models.yml#/definitions/pet",
+			"nested":   "api/v1/common/models.yml#/definitions/pet",
+			"whole":    "api/shared/pet.yml",
+			"absolute": "https://example.com/schemas/pet.json",
+		}, refs(sp))
+	})
+
+	t.Run("to another host", func(t *testing.T) {
+		t.Parallel()
+
+		sp := newSpec()
+		require.NoError(t, RebaseRefs(sp, "https://example.com/api/spec.yml", "https://mirror.example.com/spec.yml"))
+		assert.Equal(t, map[string]string{
+			"local":    "#/definitions/pet",
+			"sibling":  "https://example.com/api/models.yml#/definitions/pet",
+			"nested":   "https://example.com/api/common/models.yml#/definitions/pet",
+			"whole":    "https://example.com/shared/pet.yml",
+			"absolute": "https://example.com/schemas/pet.json",
+		}, refs(sp))
+	})
+}
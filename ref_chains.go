@@ -0,0 +1,162 @@
+package analysis
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// CollapseRefChainsOpts configures the collapsing of $ref chains
+type CollapseRefChainsOpts struct {
+	// RemoveIntermediates deletes the intermediate definitions of the collapsed chains which are no longer
+	// referred to after collapsing. Other unused definitions are retained.
+	RemoveIntermediates bool
+
+	// IgnoreAnnotations sees through intermediate definitions which add only annotations (title, description,
+	// example, externalDocs) to their $ref. Such siblings of a $ref are ignored by JSON schema anyway.
+	IgnoreAnnotations bool
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// RefChain describes a chain of $ref's which has been collapsed
+type RefChain struct {
+	Pointer string   // the location of the rewritten $ref, e.g. "#/definitions/owner/properties/pet"
+	Chain   []string // the successive $ref's, from the original one to the final one
+}
+
+// RefChainsReport describes the changes made when collapsing $ref chains
+type RefChainsReport struct {
+	Collapsed []RefChain // sorted by pointer
+	Removed   []string   // JSON pointers to the removed intermediate definitions, sorted
+}
+
+// CollapseRefChains rewrites the $ref's to definitions which merely refer to other definitions
+// (e.g. A → B → C, where B is nothing but a $ref to C), so that they point directly to the final definition.
+// Specs produced by converters often contain such chains.
+//
+// Only local $ref's to definitions are considered. Circular chains are left unchanged.
+//
+// The spec is modified in place.
+func CollapseRefChains(sp *spec.Swagger, opts CollapseRefChainsOpts) RefChainsReport {
+	var report RefChainsReport
+	if sp == nil {
+		return report
+	}
+
+	chains := refChains(sp, func(schema spec.Schema) bool {
+		return isTransparentRef(schema, opts.IgnoreAnnotations)
+	})
+
+	intermediates := make(map[string]bool)
+	walkSchemas(sp, func(pointer string, schema *spec.Schema) {
+		ref := schema.Ref.String()
+		chain, isChain := chains[ref]
+		if !isChain {
+			return
+		}
+
+		intermediates[ref] = true
+		for _, intermediate := range chain[:len(chain)-1] {
+			intermediates[intermediate] = true
+		}
+
+		schema.Ref = spec.MustCreateRef(chain[len(chain)-1])
+		report.Collapsed = append(report.Collapsed, RefChain{
+			Pointer: pointer,
+			Chain:   append([]string{ref}, chain...),
+		})
+	})
+
+	sort.Slice(report.Collapsed, func(i, j int) bool { return report.Collapsed[i].Pointer < report.Collapsed[j].Pointer })
+
+	if !opts.RemoveIntermediates {
+		return report
+	}
+
+	referred := make(map[string]bool)
+	for _, ref := range newSpec(sp).references.allRefs {
+		referred[ref.String()] = true
+	}
+
+	for intermediate := range intermediates {
+		if referred[intermediate] {
+			continue
+		}
+
+		name, _ := definitionOfPointer(intermediate)
+		delete(sp.Definitions, name)
+		report.Removed = append(report.Removed, intermediate)
+	}
+
+	sort.Strings(report.Removed)
+
+	return report
+}
+
+// refChains maps $ref's to transparent definitions (e.g. "#/definitions/a"), i.e. definitions which are merely
+// a $ref to another definition, to the successive definitions they lead to. The last one is not transparent.
+//
+// Circular chains are skipped.
+func refChains(sp *spec.Swagger, transparent func(spec.Schema) bool) map[string][]string {
+	direct := make(map[string]string)
+	for name, schema := range sp.Definitions {
+		if !strings.HasPrefix(schema.Ref.String(), definitionsPrefix) || !transparent(schema) {
+			continue
+		}
+
+		direct[definitionsPrefix+jsonpointer.Escape(name)] = schema.Ref.String()
+	}
+
+	chains := make(map[string][]string, len(direct))
+	for key, ref := range direct {
+		visited := map[string]bool{key: true}
+		chain := []string{ref}
+		for {
+			next, isTransparent := direct[ref]
+			if !isTransparent || visited[ref] {
+				break
+			}
+
+			visited[ref] = true
+			ref = next
+			chain = append(chain, ref)
+		}
+
+		if !visited[ref] { // circular chains are left unchanged
+			chains[key] = chain
+		}
+	}
+
+	return chains
+}
+
+// isTransparentRef tells if a schema is a $ref without any other keyword, save annotations if they are ignored
+func isTransparentRef(schema spec.Schema, ignoreAnnotations bool) bool {
+	if schema.Ref.String() == "" {
+		return false
+	}
+
+	buf, err := json.Marshal(schema)
+	if err != nil {
+		return false
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &keys); err != nil {
+		return false
+	}
+
+	delete(keys, "$ref")
+	if ignoreAnnotations {
+		for _, annotation := range []string{"title", "description", "example", "externalDocs"} {
+			delete(keys, annotation)
+		}
+	}
+
+	return len(keys) == 0
+}
@@ -0,0 +1,53 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollapseRefChains(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with bare $ref's only", func(t *testing.T) {
+		sp := antest.LoadOrFail(t, filepath.Join("fixtures", "ref-chains.yml"))
+		report := CollapseRefChains(sp, CollapseRefChainsOpts{})
+
+		assert.Equal(t, []RefChain{
+			{
+				Pointer: "#/definitions/Owner/properties/pets/items",
+				Chain:   []string{"#/definitions/PetModel", "#/definitions/Pet"},
+			},
+			{
+				Pointer: "#/definitions/PetDTO",
+				Chain:   []string{"#/definitions/PetModel", "#/definitions/Pet"},
+			},
+		}, report.Collapsed)
+		assert.Empty(t, report.Removed)
+		assert.Contains(t, sp.Definitions, "PetModel")
+	})
+
+	t.Run("ignoring annotations, removing intermediates", func(t *testing.T) {
+		sp := antest.LoadOrFail(t, filepath.Join("fixtures", "ref-chains.yml"))
+		report := CollapseRefChains(sp, CollapseRefChainsOpts{IgnoreAnnotations: true, RemoveIntermediates: true})
+
+		require.Len(t, report.Collapsed, 3)
+		assert.Equal(t, RefChain{
+			Pointer: "#/paths/~1pets/get/responses/200/schema/items",
+			Chain:   []string{"#/definitions/PetDTO", "#/definitions/PetModel", "#/definitions/Pet"},
+		}, report.Collapsed[2])
+		assert.Equal(t, []string{"#/definitions/PetDTO", "#/definitions/PetModel"}, report.Removed)
+
+		// definitions adding something to their $ref, circular chains and unused aliases which were not
+		// part of a collapsed chain are retained
+		assert.Equal(t, []string{"Alias", "LoopA", "LoopB", "Named", "Owner", "Pet"}, sortedKeys(sp.Definitions))
+		assert.Equal(t, "#/definitions/Pet", schemaRef(*sp.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200].Schema.Items.Schema))
+	})
+
+	t.Run("with a nil spec", func(t *testing.T) {
+		assert.Empty(t, CollapseRefChains(nil, CollapseRefChainsOpts{}).Collapsed)
+	})
+}
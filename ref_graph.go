@@ -0,0 +1,395 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+)
+
+// RefGraphCluster is a strategy to group the nodes of the graph rendered by Spec.RefGraphDOT
+type RefGraphCluster string
+
+// Strategies to group the nodes of a $ref graph
+const (
+	// RefGraphNoCluster does not group nodes
+	RefGraphNoCluster RefGraphCluster = ""
+
+	// RefGraphClusterByFile groups nodes by the document which holds them: the spec, or remote documents
+	RefGraphClusterByFile RefGraphCluster = "file"
+
+	// RefGraphClusterByTag groups operations by their first tag, and the other nodes by the first tag of the operations
+	// which use them, directly or transitively. Nodes used by operations with different tags are not grouped.
+	RefGraphClusterByTag RefGraphCluster = "tag"
+)
+
+// RefGraphDOTOpts specifies how the $ref graph of a spec is rendered by Spec.RefGraphDOT
+type RefGraphDOTOpts struct {
+	// Cluster groups the nodes of the graph in clusters
+	Cluster RefGraphCluster
+
+	// HighlightPolymorphic draws definitions with a discriminator with a double border
+	HighlightPolymorphic bool
+
+	// HighlightCycles draws the nodes and edges which belong to a cycle of $ref's in red
+	HighlightCycles bool
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// kinds of nodes in a $ref graph
+const (
+	refNodeDefinition = "definition"
+	refNodeParameter  = "parameter"
+	refNodeResponse   = "response"
+	refNodeOperation  = "operation"
+	refNodePathItem   = "path"
+	refNodeRemote     = "remote"
+)
+
+// label of the cluster of nodes which belong to the spec, when clustering by file
+const refGraphSpecCluster = "(spec)"
+
+// refNode is a node of a $ref graph: a definition, a shared parameter or response, an operation,
+// a path item, or a remote document (or a location in a remote document)
+type refNode struct {
+	id       string // the JSON pointer to the node, or the $ref to a remote node
+	label    string
+	kind     string
+	document string // the remote document holding the node, empty for nodes of the spec
+	tags     map[string]bool
+}
+
+// refGraph is the graph of the $ref's in a spec: an edge goes from the node holding a $ref to the node it refers to
+type refGraph struct {
+	nodes map[string]*refNode
+	edges map[string]map[string]bool
+}
+
+// RefGraphDOT renders the graph of the $ref's of the spec in the Graphviz DOT language.
+//
+// The nodes of the graph are the definitions, the shared parameters and responses, the operations and path items
+// holding $ref's, and the remote documents referred to. An edge goes from a node to each node it refers to
+// with a $ref.
+func (s *Spec) RefGraphDOT(opts RefGraphDOTOpts) string {
+	g := s.refGraph()
+
+	if opts.Cluster == RefGraphClusterByTag {
+		g.propagateTags()
+	}
+
+	// the strongly connected component of each node in a cycle
+	var cycles map[string]int
+	if opts.HighlightCycles {
+		cycles = g.cycles()
+	}
+
+	clusters := make(map[string][]string)
+	var unclustered []string
+	for _, id := range sortedKeys(g.nodes) {
+		cluster := g.clusterOf(g.nodes[id], opts.Cluster)
+		if cluster == "" {
+			unclustered = append(unclustered, id)
+
+			continue
+		}
+		clusters[cluster] = append(clusters[cluster], id)
+	}
+
+	var dot strings.Builder
+	dot.WriteString("digraph refs {\n")
+	dot.WriteString("  rankdir=LR;\n")
+	dot.WriteString("  node [shape=box];\n")
+
+	for i, cluster := range sortedKeys(clusters) {
+		fmt.Fprintf(&dot, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&dot, "    label=%s;\n", dotQuote(cluster))
+		for _, id := range clusters[cluster] {
+			dot.WriteString("    " + s.dotNode(g.nodes[id], opts, cycles) + ";\n")
+		}
+		dot.WriteString("  }\n")
+	}
+
+	for _, id := range unclustered {
+		dot.WriteString("  " + s.dotNode(g.nodes[id], opts, cycles) + ";\n")
+	}
+
+	for _, from := range sortedKeys(g.edges) {
+		for _, to := range sortedKeys(g.edges[from]) {
+			var attributes string
+			if component, isCyclic := cycles[from]; isCyclic && g.inComponent(to, component, cycles) {
+				attributes = " [color=red]"
+			}
+			fmt.Fprintf(&dot, "  %s -> %s%s;\n", dotQuote(from), dotQuote(to), attributes)
+		}
+	}
+
+	dot.WriteString("}\n")
+
+	return dot.String()
+}
+
+func (s *Spec) dotNode(node *refNode, opts RefGraphDOTOpts, cycles map[string]int) string {
+	attributes := []string{"label=" + dotQuote(node.label)}
+
+	switch node.kind {
+	case refNodeOperation, refNodePathItem:
+		attributes = append(attributes, "shape=ellipse")
+	case refNodeParameter, refNodeResponse:
+		attributes = append(attributes, "shape=note")
+	case refNodeRemote:
+		attributes = append(attributes, "style=dashed")
+	}
+
+	if opts.HighlightPolymorphic && node.kind == refNodeDefinition {
+		name := jsonpointer.Unescape(strings.TrimPrefix(node.id, definitionsPrefix))
+		if def, ok := s.spec.Definitions[name]; ok && def.Discriminator != "" {
+			attributes = append(attributes, "peripheries=2")
+		}
+	}
+
+	if _, isCyclic := cycles[node.id]; isCyclic {
+		attributes = append(attributes, "color=red")
+	}
+
+	return dotQuote(node.id) + " [" + strings.Join(attributes, ", ") + "]"
+}
+
+// dotQuote quotes an identifier or a label in the DOT language
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+// refGraph builds the graph of the $ref's of the spec
+func (s *Spec) refGraph() *refGraph {
+	g := &refGraph{
+		nodes: make(map[string]*refNode),
+		edges: make(map[string]map[string]bool),
+	}
+
+	for name := range s.spec.Definitions {
+		g.addNode(definitionsPrefix+jsonpointer.Escape(name), name, refNodeDefinition, "")
+	}
+
+	for name := range s.spec.Parameters {
+		g.addNode("#/parameters/"+jsonpointer.Escape(name), "parameter "+name, refNodeParameter, "")
+	}
+
+	for name := range s.spec.Responses {
+		g.addNode("#/responses/"+jsonpointer.Escape(name), "response "+name, refNodeResponse, "")
+	}
+
+	for pointer, ref := range s.references.allRefs {
+		from := s.refGraphNode(g, pointer)
+		to := s.refGraphNode(g, ref.String())
+		if from == "" || to == "" {
+			continue
+		}
+
+		if g.edges[from] == nil {
+			g.edges[from] = make(map[string]bool)
+		}
+		g.edges[from][to] = true
+	}
+
+	return g
+}
+
+func (g *refGraph) inComponent(node string, component int, cycles map[string]int) bool {
+	c, isCyclic := cycles[node]
+
+	return isCyclic && c == component
+}
+
+// propagateTags passes the tags of operations on to the nodes they use
+func (g *refGraph) propagateTags() {
+	for id, node := range g.nodes {
+		if node.kind != refNodeOperation {
+			continue
+		}
+
+		for target := range g.reachable(id) {
+			for tag := range node.tags {
+				g.nodes[target].tags[tag] = true
+			}
+		}
+	}
+}
+
+func (g *refGraph) addNode(id, label, kind, document string) string {
+	if _, exists := g.nodes[id]; !exists {
+		g.nodes[id] = &refNode{id: id, label: label, kind: kind, document: document, tags: make(map[string]bool)}
+	}
+
+	return id
+}
+
+// refGraphNode yields the node holding a location of the spec, or a location a $ref refers to,
+// adding it to the graph when needed
+func (s *Spec) refGraphNode(g *refGraph, location string) string {
+	if !strings.HasPrefix(location, "#/") {
+		if location == "" || strings.HasPrefix(location, "#") {
+			return ""
+		}
+
+		document := location
+		if i := strings.IndexByte(location, '#'); i >= 0 {
+			document = location[:i]
+		}
+
+		return g.addNode(location, location, refNodeRemote, document)
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(location, "#/"), "/")
+	if len(tokens) < 2 {
+		return ""
+	}
+
+	id := "#/" + tokens[0] + "/" + tokens[1]
+	name := jsonpointer.Unescape(tokens[1])
+
+	switch tokens[0] {
+	case "definitions":
+		return g.addNode(id, name, refNodeDefinition, "")
+	case "parameters":
+		return g.addNode(id, "parameter "+name, refNodeParameter, "")
+	case "responses":
+		return g.addNode(id, "response "+name, refNodeResponse, "")
+	case "paths":
+		if len(tokens) < 3 {
+			return g.addNode(id, name, refNodePathItem, "")
+		}
+
+		if s.spec.Paths == nil {
+			return ""
+		}
+
+		method := strings.ToUpper(tokens[2])
+		pathItem, found := s.spec.Paths.Paths[name]
+		if !found {
+			return ""
+		}
+
+		op := operationOf(&pathItem, method)
+		if op == nil {
+			return g.addNode(id, name, refNodePathItem, "")
+		}
+
+		id += "/" + tokens[2]
+		if _, exists := g.nodes[id]; !exists {
+			g.addNode(id, method+" "+name, refNodeOperation, "")
+			if len(op.Tags) > 0 {
+				g.nodes[id].tags[op.Tags[0]] = true
+			}
+		}
+
+		return id
+	default:
+		return ""
+	}
+}
+
+// clusterOf yields the cluster of a node, or an empty string when the node is not grouped
+func (g *refGraph) clusterOf(node *refNode, cluster RefGraphCluster) string {
+	switch cluster {
+	case RefGraphClusterByFile:
+		if node.document == "" {
+			return refGraphSpecCluster
+		}
+
+		return node.document
+	case RefGraphClusterByTag:
+		if len(node.tags) != 1 {
+			return ""
+		}
+
+		for tag := range node.tags {
+			return tag
+		}
+	}
+
+	return ""
+}
+
+// reachable yields the nodes which can be reached from a node, following one edge or more
+func (g *refGraph) reachable(from string) map[string]bool {
+	reached := make(map[string]bool)
+	stack := []string{from}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for to := range g.edges[node] {
+			if !reached[to] {
+				reached[to] = true
+				stack = append(stack, to)
+			}
+		}
+	}
+
+	return reached
+}
+
+// cycles finds the strongly connected components of the graph (Tarjan's algorithm), and yields the component
+// of each node which belongs to a cycle
+func (g *refGraph) cycles() map[string]int {
+	var (
+		index    = make(map[string]int, len(g.nodes))
+		lowlink  = make(map[string]int, len(g.nodes))
+		onStack  = make(map[string]bool, len(g.nodes))
+		stack    []string
+		counter  int
+		cyclic   = make(map[string]int)
+		connect  func(string)
+		sequence int
+	)
+
+	connect = func(node string) {
+		index[node], lowlink[node] = counter, counter
+		counter++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, to := range sortedKeys(g.edges[node]) {
+			if _, visited := index[to]; !visited {
+				connect(to)
+				if lowlink[to] < lowlink[node] {
+					lowlink[node] = lowlink[to]
+				}
+			} else if onStack[to] && index[to] < lowlink[node] {
+				lowlink[node] = index[to]
+			}
+		}
+
+		if lowlink[node] != index[node] {
+			return
+		}
+
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == node {
+				break
+			}
+		}
+
+		if len(component) > 1 || g.edges[node][node] {
+			for _, member := range component {
+				cyclic[member] = sequence
+			}
+			sequence++
+		}
+	}
+
+	for _, node := range sortedKeys(g.nodes) {
+		if _, visited := index[node]; !visited {
+			connect(node)
+		}
+	}
+
+	return cyclic
+}
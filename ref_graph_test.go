@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefGraphDOT(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "ref_graph.yml")))
+
+	edges := `  "#/definitions/owner" -> "#/definitions/pet";
+  "#/definitions/pet" -> "#/definitions/owner";
+  "#/paths/~1pets/get" -> "#/definitions/pet";
+  "#/paths/~1pets/get" -> "#/parameters/limit";
+  "#/paths/~1pets/get" -> "#/responses/notFound";
+  "#/paths/~1stores/get" -> "#/responses/notFound";
+  "#/paths/~1stores/get" -> "stores.yml#/definitions/store";
+  "#/responses/notFound" -> "#/definitions/error";
+}
+`
+
+	t.Run("should render the graph of $ref's", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, `digraph refs {
+  rankdir=LR;
+  node [shape=box];
+  "#/definitions/error" [label="error"];
+  "#/definitions/owner" [label="owner"];
+  "#/definitions/pet" [label="pet"];
+  "#/parameters/limit" [label="parameter limit", shape=note];
+  "#/paths/~1pets/get" [label="GET /pets", shape=ellipse];
+  "#/paths/~1stores/get" [label="GET /stores", shape=ellipse];
+  "#/responses/notFound" [label="response notFound", shape=note];
+  "stores.yml#/definitions/store" [label="stores.yml#/definitions/store", style=dashed];
+`+edges, an.RefGraphDOT(RefGraphDOTOpts{}))
+	})
+
+	t.Run("should cluster nodes by file", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, `digraph refs {
+  rankdir=LR;
+  node [shape=box];
+  subgraph cluster_0 {
+    label="(spec)";
+    "#/definitions/error" [label="error"];
+    "#/definitions/owner" [label="owner"];
+    "#/definitions/pet" [label="pet"];
+    "#/parameters/limit" [label="parameter limit", shape=note];
+    "#/paths/~1pets/get" [label="GET /pets", shape=ellipse];
+    "#/paths/~1stores/get" [label="GET /stores", shape=ellipse];
+    "#/responses/notFound" [label="response notFound", shape=note];
+  }
+  subgraph cluster_1 {
+    label="stores.yml";
+    "stores.yml#/definitions/store" [label="stores.yml#/definitions/store", style=dashed];
+  }
+`+edges, an.RefGraphDOT(RefGraphDOTOpts{Cluster: RefGraphClusterByFile}))
+	})
+
+	t.Run("should cluster nodes by tag, and highlight polymorphic and cyclic nodes", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, `digraph refs {
+  rankdir=LR;
+  node [shape=box];
+  subgraph cluster_0 {
+    label="pets";
+    "#/definitions/owner" [label="owner", color=red];
+    "#/definitions/pet" [label="pet", peripheries=2, color=red];
+    "#/parameters/limit" [label="parameter limit", shape=note];
+    "#/paths/~1pets/get" [label="GET /pets", shape=ellipse];
+  }
+  subgraph cluster_1 {
+    label="stores";
+    "#/paths/~1stores/get" [label="GET /stores", shape=ellipse];
+    "stores.yml#/definitions/store" [label="stores.yml#/definitions/store", style=dashed];
+  }
+  "#/definitions/error" [label="error"];
+  "#/responses/notFound" [label="response notFound", shape=note];
+  "#/definitions/owner" -> "#/definitions/pet" [color=red];
+  "#/definitions/pet" -> "#/definitions/owner" [color=red];
+  "#/paths/~1pets/get" -> "#/definitions/pet";
+  "#/paths/~1pets/get" -> "#/parameters/limit";
+  "#/paths/~1pets/get" -> "#/responses/notFound";
+  "#/paths/~1stores/get" -> "#/responses/notFound";
+  "#/paths/~1stores/get" -> "stores.yml#/definitions/store";
+  "#/responses/notFound" -> "#/definitions/error";
+}
+`, an.RefGraphDOT(RefGraphDOTOpts{Cluster: RefGraphClusterByTag, HighlightPolymorphic: true, HighlightCycles: true}))
+	})
+
+	t.Run("should quote identifiers", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, `"a \"b\" \\c"`, dotQuote(`a "b" \c`))
+	})
+}
@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-openapi/analysis/internal/flatten/normalize"
+)
+
+// RefHostError reports a remote document which is not fetched, because its host is not allowed
+// by FlattenOpts.AllowedRefHosts or FlattenOpts.DeniedRefHosts
+type RefHostError struct {
+	URL  string
+	Host string
+}
+
+func (e *RefHostError) Error() string {
+	return fmt.Sprintf("document %s may not be fetched: host %s is not allowed", e.URL, e.Host)
+}
+
+// hasRefHostsPolicy tells if the AllowedRefHosts or DeniedRefHosts options restrict the hosts of remote documents
+func (f *FlattenOpts) hasRefHostsPolicy() bool {
+	return len(f.AllowedRefHosts) > 0 || len(f.DeniedRefHosts) > 0
+}
+
+// checkRefHost verifies that a document may be fetched. Local files are always allowed.
+func (f *FlattenOpts) checkRefHost(pth string) error {
+	if !isRemoteDocument(pth) {
+		return nil
+	}
+
+	u, err := url.Parse(pth)
+	if err != nil {
+		return err
+	}
+
+	if matchesHost(u, f.DeniedRefHosts) || len(f.AllowedRefHosts) > 0 && !matchesHost(u, f.AllowedRefHosts) {
+		f.tracef("rejecting remote document %s from host %s", pth, u.Host)
+
+		return &RefHostError{URL: pth, Host: u.Host}
+	}
+
+	return nil
+}
+
+// checkRefHosts verifies the remote documents referred to by the spec, before any of them is fetched
+func (f *FlattenOpts) checkRefHosts() error {
+	for _, key := range sortedKeys(f.Spec.references.allRefs) {
+		ref := f.Spec.references.allRefs[key]
+		if ref.HasFragmentOnly {
+			continue
+		}
+
+		document, _, _ := strings.Cut(normalize.RebaseRef(f.BasePath, ref.String()), "#")
+		if err := f.checkRefHost(document); err != nil {
+			return &RefError{Pointer: key, Ref: ref.String(), Cause: err}
+		}
+	}
+
+	return nil
+}
+
+// refHostsPathLoader wraps a loader of documents, so documents are fetched from allowed hosts only
+func (f *FlattenOpts) refHostsPathLoader(loader func(string) (json.RawMessage, error)) func(string) (json.RawMessage, error) {
+	return func(pth string) (json.RawMessage, error) {
+		if err := f.checkRefHost(pth); err != nil {
+			return nil, err
+		}
+
+		return loader(pth)
+	}
+}
+
+// matchesHost tells if the host of a URL is one of the hosts listed.
+//
+// Hosts are matched case-insensitively. A host listed with a port only matches this port, and a host starting
+// with "*." matches all its subdomains (e.g. "*.example.com" matches "schemas.example.com").
+func matchesHost(u *url.URL, hosts []string) bool {
+	for _, host := range hosts {
+		host = strings.ToLower(host)
+
+		name := strings.ToLower(u.Hostname())
+		if strings.Contains(host, ":") {
+			name = strings.ToLower(u.Host)
+		}
+
+		if name == host || strings.HasPrefix(host, "*.") && strings.HasSuffix(name, host[1:]) {
+			return true
+		}
+	}
+
+	return false
+}
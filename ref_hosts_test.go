@@ -0,0 +1,86 @@
+package analysis
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten_RefHosts(t *testing.T) {
+	t.Parallel()
+
+	var fetched atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched.Add(1)
+		_, _ = w.Write([]byte(`{"definitions": {"pet": {"type": "object", "properties": {"name": {"type": "string"}}}}}`))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	newSpec := func(document string) *spec.Swagger {
+		return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			Definitions: spec.Definitions{
+				"pets": *spec.ArrayProperty(spec.RefSchema(server.URL + document + "#/definitions/pet")),
+			},
+		}}
+	}
+
+	t.Run("should fetch documents from allowed hosts", func(t *testing.T) {
+		sp := newSpec("/allowed.json")
+		require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), Minimal: true, AllowedRefHosts: []string{serverURL.Host}}))
+		assert.Contains(t, sp.Definitions, "pet")
+	})
+
+	for _, toPin := range []struct {
+		name    string
+		allowed []string
+		denied  []string
+	}{
+		{name: "not allowed", allowed: []string{"schemas.example.com", "*.example.com"}},
+		{name: "denied", denied: []string{serverURL.Hostname()}},
+		{name: "denied even though allowed", allowed: []string{serverURL.Host}, denied: []string{serverURL.Host}},
+	} {
+		tc := toPin
+
+		t.Run("should not fetch documents from hosts "+tc.name, func(t *testing.T) {
+			before := fetched.Load()
+
+			err := Flatten(FlattenOpts{
+				Spec:            New(newSpec("/denied.json")),
+				Minimal:         true,
+				AllowedRefHosts: tc.allowed,
+				DeniedRefHosts:  tc.denied,
+			})
+			require.Error(t, err)
+
+			var hostErr *RefHostError
+			require.True(t, errors.As(err, &hostErr))
+			assert.Equal(t, serverURL.Host, hostErr.Host)
+			assert.Equal(t, before, fetched.Load())
+		})
+	}
+}
+
+func TestMatchesHost(t *testing.T) {
+	t.Parallel()
+
+	u, err := url.Parse("https://Schemas.Example.com:8443/pet.json")
+	require.NoError(t, err)
+
+	assert.True(t, matchesHost(u, []string{"schemas.example.com"}))
+	assert.True(t, matchesHost(u, []string{"*.example.com"}))
+	assert.True(t, matchesHost(u, []string{"schemas.example.com:8443"}))
+	assert.False(t, matchesHost(u, []string{"schemas.example.com:443"}))
+	assert.False(t, matchesHost(u, []string{"example.com", "*.schemas.example.com"}))
+	assert.False(t, matchesHost(u, nil))
+}
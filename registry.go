@@ -0,0 +1,153 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// RegistryEntry identifies a spec published in a registry
+type RegistryEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// SpecRegistry is a central repository of specs, from which inputs may be pulled
+// (see FetchSpecs, MixinFromRegistry and CompatibilityFromRegistry)
+type SpecRegistry interface {
+	// Get retrieves a version of a spec. An empty version stands for the latest version.
+	Get(name, version string) (*spec.Swagger, error)
+
+	// List enumerates the specs available in the registry
+	List() ([]RegistryEntry, error)
+}
+
+// HTTPRegistry is a reference implementation of a SpecRegistry served over HTTP.
+//
+// It expects the following endpoints, relative to its BaseURL:
+//   - GET /specs: a JSON array of entries, such as [{"name": "billing", "version": "1.2.0"}]
+//   - GET /specs/{name}/{version}: the spec document, as JSON or YAML. The version "latest" is requested when
+//     no version is specified.
+type HTTPRegistry struct {
+	BaseURL string
+	Client  *http.Client // defaults to http.DefaultClient
+}
+
+// NewHTTPRegistry builds a registry client against some base URL
+func NewHTTPRegistry(baseURL string) *HTTPRegistry {
+	return &HTTPRegistry{BaseURL: baseURL}
+}
+
+// Get retrieves a version of a spec from the registry
+func (r *HTTPRegistry) Get(name, version string) (*spec.Swagger, error) {
+	if version == "" {
+		version = "latest"
+	}
+
+	data, err := r.fetch("specs", name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if !json.Valid(data) {
+		doc, erd := swag.BytesToYAMLDoc(data)
+		if erd != nil {
+			return nil, fmt.Errorf("invalid spec %s@%s: %w", name, version, erd)
+		}
+
+		if data, err = swag.YAMLToJSON(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	var sp spec.Swagger
+	if err := json.Unmarshal(data, &sp); err != nil {
+		return nil, fmt.Errorf("invalid spec %s@%s: %w", name, version, err)
+	}
+
+	return &sp, nil
+}
+
+// List enumerates the specs available in the registry
+func (r *HTTPRegistry) List() ([]RegistryEntry, error) {
+	data, err := r.fetch("specs")
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []RegistryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid registry listing: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *HTTPRegistry) fetch(segments ...string) ([]byte, error) {
+	escaped := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+	target := strings.TrimRight(r.BaseURL, "/") + "/" + strings.Join(escaped, "/")
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(target)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry request to %s failed: %s", target, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// FetchSpecs retrieves several specs from a registry
+func FetchSpecs(registry SpecRegistry, entries ...RegistryEntry) ([]*spec.Swagger, error) {
+	specs := make([]*spec.Swagger, 0, len(entries))
+	for _, entry := range entries {
+		sp, err := registry.Get(entry.Name, entry.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		specs = append(specs, sp)
+	}
+
+	return specs, nil
+}
+
+// MixinFromRegistry pulls specs from a registry, and mixes them into the primary spec, like MixinReport.
+//
+// Use FetchSpecs then MixinSpecs to mix specs with namespacing options.
+func MixinFromRegistry(primary *spec.Swagger, registry SpecRegistry, entries ...RegistryEntry) ([]MixinConflict, error) {
+	mixins, err := FetchSpecs(registry, entries...)
+	if err != nil {
+		return nil, err
+	}
+
+	return MixinReport(primary, mixins...), nil
+}
+
+// CompatibilityFromRegistry pulls two versions of specs from a registry, and checks the compatibility
+// of the second one with the first one, like CheckCompatibility
+func CompatibilityFromRegistry(registry SpecRegistry, before, after RegistryEntry, opts CompatOpts) ([]CompatFinding, error) {
+	specs, err := FetchSpecs(registry, before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	return CheckCompatibility(specs[0], specs[1], opts), nil
+}
@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPRegistry(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/registry/specs", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"name":"billing","version":"1.0.0"},{"name":"pets","version":"2.1.0"}]`))
+	})
+	mux.HandleFunc("/registry/specs/billing/latest", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"swagger":"2.0","info":{"title":"billing","version":"1.0.0"},"paths":{"/invoices":{}}}`))
+	})
+	mux.HandleFunc("/registry/specs/pets/2.1.0", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("swagger: '2.0'\ninfo:\n  title: pets\n  version: 2.1.0\npaths:\n  /pets: {}\n"))
+	})
+	mux.HandleFunc("/registry/specs/billing/0.9.0", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"swagger":"2.0","info":{"title":"billing","version":"0.9.0"},"paths":{"/invoices":{"get":{"responses":{"200":{"description":"ok"}}}}}}`))
+	})
+	mux.HandleFunc("/registry/specs/broken/latest", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("- not a spec"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	registry := NewHTTPRegistry(server.URL + "/registry/")
+
+	t.Run("should list specs", func(t *testing.T) {
+		t.Parallel()
+
+		entries, err := registry.List()
+		require.NoError(t, err)
+		assert.Equal(t, []RegistryEntry{{Name: "billing", Version: "1.0.0"}, {Name: "pets", Version: "2.1.0"}}, entries)
+	})
+
+	t.Run("should get JSON and YAML specs", func(t *testing.T) {
+		t.Parallel()
+
+		billing, err := registry.Get("billing", "")
+		require.NoError(t, err)
+		assert.Equal(t, "billing", billing.Info.Title)
+
+		pets, err := registry.Get("pets", "2.1.0")
+		require.NoError(t, err)
+		assert.Equal(t, "pets", pets.Info.Title)
+	})
+
+	t.Run("should report errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := registry.Get("pets", "9.9.9")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+
+		_, err = registry.Get("broken", "")
+		require.Error(t, err)
+	})
+
+	t.Run("should mix specs from the registry", func(t *testing.T) {
+		t.Parallel()
+
+		primary := &spec.Swagger{}
+		conflicts, err := MixinFromRegistry(primary, registry,
+			RegistryEntry{Name: "billing"}, RegistryEntry{Name: "pets", Version: "2.1.0"})
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+		assert.Contains(t, primary.Paths.Paths, "/invoices")
+		assert.Contains(t, primary.Paths.Paths, "/pets")
+
+		_, err = MixinFromRegistry(primary, registry, RegistryEntry{Name: "unknown"})
+		require.Error(t, err)
+	})
+
+	t.Run("should compare specs from the registry", func(t *testing.T) {
+		t.Parallel()
+
+		findings, err := CompatibilityFromRegistry(registry,
+			RegistryEntry{Name: "billing", Version: "0.9.0"}, RegistryEntry{Name: "billing"}, CompatOpts{})
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		assert.Equal(t, ChangeOperationRemoved, findings[0].Kind)
+
+		_, err = CompatibilityFromRegistry(registry, RegistryEntry{Name: "billing"}, RegistryEntry{Name: "unknown"}, CompatOpts{})
+		require.Error(t, err)
+	})
+}
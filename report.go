@@ -0,0 +1,170 @@
+package analysis
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// ReportSchema identifies the structure of the reports produced by Spec.Report.
+// It changes whenever this structure changes in an incompatible way.
+const ReportSchema = "urn:go-openapi:analysis:report:v1"
+
+// Report is a machine-readable summary of the analysis of a whole spec, to be serialized as JSON
+type Report struct {
+	// Schema identifies the structure of the report: see ReportSchema
+	Schema string `json:"$schema"`
+
+	// Operations lists the operations, sorted by path then by method
+	Operations []ReportOperation `json:"operations"`
+
+	// References maps the location of every $ref (e.g. "#/definitions/pet/properties/owner") to the $ref
+	References map[string]string `json:"references"`
+
+	// Unused lists the pointers to the shared components which cannot be reached from the paths of the spec, sorted
+	Unused []string `json:"unused"`
+
+	// Cycles lists the cycles of $ref's: each cycle lists the pointers to the components referring to each other, sorted
+	Cycles [][]string `json:"cycles"`
+
+	Stats Stats `json:"stats"`
+
+	// Findings are the findings of all the registered lint rules
+	Findings []ReportFinding `json:"findings"`
+}
+
+// ReportOperation describes an operation in a Report
+type ReportOperation struct {
+	Method      string   `json:"method"` // upper case
+	Path        string   `json:"path"`
+	ID          string   `json:"operationId,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Consumes    []string `json:"consumes,omitempty"` // sorted
+	Produces    []string `json:"produces,omitempty"` // sorted
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	Parameters  int      `json:"parameters"`          // parameters with an unresolved $ref are not counted
+	Responses   []string `json:"responses,omitempty"` // status codes, and "default"
+	HasSecurity bool     `json:"hasSecurity,omitempty"`
+}
+
+// ReportFinding is a lint finding in a Report
+type ReportFinding struct {
+	Pointer  string           `json:"pointer"`
+	Code     string           `json:"code"`
+	Message  string           `json:"message"`
+	Rule     string           `json:"rule"`
+	Severity Severity         `json:"severity"`
+	Fix      []PatchOperation `json:"fix,omitempty"`
+}
+
+// Report produces a summary of the analysis of the spec, combining its operations, $ref's, unused components,
+// cycles of $ref's, stats and the findings of all the registered lint rules (see RegisterLintRule).
+//
+// All lists are sorted and never nil, so reports of the same spec serialize identically.
+func (s *Spec) Report() Report {
+	report := Report{
+		Schema:     ReportSchema,
+		Operations: s.reportOperations(),
+		References: make(map[string]string, len(s.references.allRefs)),
+		Unused:     []string{},
+		Cycles:     s.refGraph().cycleLists(),
+		Stats:      s.Stats(),
+		Findings:   []ReportFinding{},
+	}
+
+	for pointer, ref := range s.references.allRefs {
+		report.References[pointer] = ref.String()
+	}
+
+	reachable := reachableComponents(s.spec)
+	for _, section := range []struct {
+		name  string
+		names []string
+	}{
+		{"definitions", sortedKeys(s.spec.Definitions)},
+		{"parameters", sortedKeys(s.spec.Parameters)},
+		{"responses", sortedKeys(s.spec.Responses)},
+	} {
+		for _, name := range section.names {
+			if pointer := "#/" + section.name + "/" + jsonpointer.Escape(name); !reachable[pointer] {
+				report.Unused = append(report.Unused, pointer)
+			}
+		}
+	}
+	sort.Strings(report.Unused)
+
+	for _, finding := range Lint(s) {
+		report.Findings = append(report.Findings, ReportFinding{
+			Pointer:  finding.Pointer,
+			Code:     finding.Code,
+			Message:  finding.Message,
+			Rule:     finding.Rule,
+			Severity: finding.Severity,
+			Fix:      finding.Fix,
+		})
+	}
+
+	return report
+}
+
+func (s *Spec) reportOperations() []ReportOperation {
+	operations := []ReportOperation{}
+	if s.spec.Paths == nil {
+		return operations
+	}
+
+	for _, pth := range sortedKeys(s.spec.Paths.Paths) {
+		pathItem := s.spec.Paths.Paths[pth]
+		for _, method := range sortedOperationMethods(&pathItem) {
+			op := operationOf(&pathItem, method)
+			operation := ReportOperation{
+				Method:      method,
+				Path:        pth,
+				ID:          op.ID,
+				Tags:        op.Tags,
+				Consumes:    s.ConsumesFor(op),
+				Produces:    s.ProducesFor(op),
+				Deprecated:  op.Deprecated,
+				Parameters:  len(s.SafeParamsFor(method, pth, func(spec.Parameter, error) bool { return true })),
+				HasSecurity: len(s.SecurityRequirementsFor(op)) > 0,
+			}
+			sort.Strings(operation.Consumes)
+			sort.Strings(operation.Produces)
+
+			if op.Responses != nil {
+				for _, code := range sortedStatusCodes(op.Responses.StatusCodeResponses) {
+					operation.Responses = append(operation.Responses, strconv.Itoa(code))
+				}
+
+				if op.Responses.Default != nil {
+					operation.Responses = append(operation.Responses, "default")
+				}
+			}
+
+			operations = append(operations, operation)
+		}
+	}
+
+	return operations
+}
+
+// cycleLists lists the cycles of the graph, each as the sorted list of its nodes. Cycles are sorted
+// by their first node.
+func (g *refGraph) cycleLists() [][]string {
+	components := make(map[int][]string)
+	for node, component := range g.cycles() {
+		components[component] = append(components[component], node)
+	}
+
+	cycles := make([][]string, 0, len(components))
+	for _, nodes := range components {
+		sort.Strings(nodes)
+		cycles = append(cycles, nodes)
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+
+	return cycles
+}
@@ -0,0 +1,86 @@
+package analysis
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "ref_graph.yml"))
+	sp.Definitions["orphan"] = *spec.StringProperty()
+	sp.Paths.Paths["/pets"].Get.Tags = nil
+
+	report := New(sp).Report()
+
+	buf, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf, &doc))
+
+	assert.Equal(t, ReportSchema, doc["$schema"])
+
+	assert.JSONEq(t, `[
+  {"method": "GET", "path": "/pets", "parameters": 1, "responses": ["200", "404"]},
+  {"method": "GET", "path": "/stores", "tags": ["stores"], "parameters": 0, "responses": ["200", "404"]}
+]`, antest.AsJSON(t, doc["operations"]))
+
+	assert.Equal(t, "#/definitions/owner", report.References["#/definitions/pet/properties/owner"])
+	assert.Equal(t, "stores.yml#/definitions/store", report.References["#/paths/~1stores/get/responses/200/schema"])
+	assert.Len(t, report.References, 8)
+
+	assert.Equal(t, []string{"#/definitions/orphan"}, report.Unused)
+	assert.Equal(t, [][]string{{"#/definitions/owner", "#/definitions/pet"}}, report.Cycles)
+	assert.Equal(t, 2, report.Stats.TotalOperations())
+	assert.JSONEq(t, `{"GET": 2}`, antest.AsJSON(t, doc["stats"].(map[string]interface{})["operations"]))
+
+	rules := make(map[string]string, len(report.Findings))
+	for _, finding := range report.Findings {
+		rules[finding.Pointer] = finding.Rule
+	}
+	assert.Equal(t, map[string]string{
+		"#/definitions/orphan": CodeUnusedDefinition,
+		"#/paths/~1pets/get":   CodeMissingTags,
+	}, rules)
+
+	t.Run("should report an empty spec with empty lists", func(t *testing.T) {
+		t.Parallel()
+
+		buf, err := json.Marshal(New(&spec.Swagger{}).Report())
+		require.NoError(t, err)
+
+		var empty map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf, &empty))
+		for _, key := range []string{"operations", "unused", "cycles", "findings"} {
+			assert.Equal(t, []interface{}{}, empty[key], key)
+		}
+	})
+}
+
+func TestReport_UnresolvedParameter(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "body_schema.yml"))
+
+	var report Report
+	require.NotPanics(t, func() {
+		report = New(sp).Report()
+	})
+
+	for _, operation := range report.Operations {
+		if operation.Path == "/broken" {
+			assert.Equal(t, 0, operation.Parameters)
+
+			return
+		}
+	}
+	t.Fatal("expected the operation with an unresolved parameter to be reported")
+}
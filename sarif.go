@@ -0,0 +1,237 @@
+package analysis
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// SARIF log format produced by WriteSARIF
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// DefaultSARIFToolName is the name of the tool reported in SARIF logs, unless specified by SARIFOpts.ToolName
+const DefaultSARIFToolName = "go-openapi/analysis"
+
+// SARIFFinding is a finding to export as a SARIF result
+type SARIFFinding struct {
+	Finding
+
+	Rule     string   // the identifier of the rule reporting the finding. Defaults to the Code of the finding.
+	Severity Severity // defaults to SeverityWarning
+}
+
+// SARIFOpts configures WriteSARIF
+type SARIFOpts struct {
+	// ToolName and ToolVersion describe the tool reporting the findings. ToolName defaults to DefaultSARIFToolName.
+	ToolName    string
+	ToolVersion string
+
+	// Document is the location of the root document of the spec, when it is not recorded by its provenance
+	// (see Spec.ProvenanceOf), e.g. "api/swagger.yml"
+	Document string
+
+	// BaseDir makes the paths of local documents relative to this directory (e.g. the root of a repository,
+	// as expected by code scanning tools). Paths are kept as they are when empty.
+	BaseDir string
+
+	// Position locates an element of a document (e.g. "#/definitions/pet") in its source, with 1-based line
+	// and column numbers. Defaults to the sources of the spec, if any (see NewWithSource and Spec.PositionOf).
+	// Results are located by document only when the element is not found.
+	Position func(document, pointer string) (line, column int, found bool)
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// LintSARIF prepares lint findings for WriteSARIF
+func LintSARIF(findings []LintFinding) []SARIFFinding {
+	result := make([]SARIFFinding, 0, len(findings))
+	for _, finding := range findings {
+		result = append(result, SARIFFinding{Finding: finding.Finding, Rule: finding.Rule, Severity: finding.Severity})
+	}
+
+	return result
+}
+
+// CompatSARIF prepares compatibility findings for WriteSARIF, located in the new version of the spec
+func CompatSARIF(findings []CompatFinding) []SARIFFinding {
+	result := make([]SARIFFinding, 0, len(findings))
+	for _, finding := range findings {
+		result = append(result, SARIFFinding{Finding: finding.Finding, Severity: finding.Severity})
+	}
+
+	return result
+}
+
+// FindingsSARIF prepares findings without severity (e.g. from StructuralIssues or UnsatisfiableSchemas)
+// for WriteSARIF, with the same severity
+func FindingsSARIF(findings []Finding, severity Severity) []SARIFFinding {
+	result := make([]SARIFFinding, 0, len(findings))
+	for _, finding := range findings {
+		result = append(result, SARIFFinding{Finding: finding, Severity: severity})
+	}
+
+	return result
+}
+
+// WriteSARIF writes findings about the spec as a SARIF log (Static Analysis Results Interchange Format),
+// so that code scanning tools may annotate the documents of the spec.
+//
+// Findings are located in the documents they come from, according to the provenance of the spec
+// (e.g. a definition imported by Flatten from a remote document), and by JSON pointer as logical locations.
+func (s *Spec) WriteSARIF(w io.Writer, findings []SARIFFinding, opts SARIFOpts) error {
+	toolName := opts.ToolName
+	if toolName == "" {
+		toolName = DefaultSARIFToolName
+	}
+
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: toolName, Version: opts.ToolVersion, Rules: []sarifRule{}}},
+		Results: make([]sarifResult, 0, len(findings)),
+	}
+
+	rules := make(map[string]bool)
+	for _, finding := range findings {
+		rule := finding.Rule
+		if rule == "" {
+			rule = finding.Code
+		}
+		rules[rule] = true
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    rule,
+			Level:     sarifLevel(finding.Severity),
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{s.sarifLocation(finding.Pointer, opts)},
+		})
+	}
+
+	for _, rule := range sortedKeys(rules) {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: rule})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(sarifLog{Schema: SARIFSchema, Version: SARIFVersion, Runs: []sarifRun{run}})
+}
+
+// sarifLocation locates an element of the spec in the document it comes from
+func (s *Spec) sarifLocation(pointer string, opts SARIFOpts) sarifLocation {
+	location := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: pointer, Kind: "element"}},
+	}
+
+	origin := s.ProvenanceOf(pointer)
+	document := origin.Document
+	if document == "" {
+		document = opts.Document
+	}
+
+	if document == "" {
+		return location
+	}
+
+	physical := &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: sarifURI(document, opts.BaseDir)}}
+	if opts.Position != nil {
+		if line, column, found := opts.Position(document, origin.Pointer); found {
+			physical.Region = &sarifRegion{StartLine: line, StartColumn: column}
+		}
+	} else if position, found := s.positionIn(origin.Document, origin.Pointer); found {
+		physical.Region = &sarifRegion{StartLine: position.Line, StartColumn: position.Column}
+	}
+	location.PhysicalLocation = physical
+
+	return location
+}
+
+// sarifURI yields the URI of a document, relative to a base directory for local files
+func sarifURI(document, baseDir string) string {
+	if isRemoteDocument(document) {
+		return document
+	}
+
+	if baseDir != "" {
+		if rel, err := filepath.Rel(absoluteBasePath(baseDir), absoluteBasePath(document)); err == nil && !strings.HasPrefix(rel, "..") {
+			document = rel
+		}
+	}
+
+	return filepath.ToSlash(document)
+}
+
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityInfo:
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+// sarifLog is a SARIF 2.1.0 log, restricted to the properties produced by WriteSARIF
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
@@ -0,0 +1,126 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "expand", "spec.yml")
+	an := New(antest.LoadOrFail(t, bp))
+	require.NoError(t, Flatten(FlattenOpts{Spec: an, BasePath: bp, Minimal: true}))
+
+	findings := append(
+		LintSARIF([]LintFinding{{
+			Finding:  Finding{Pointer: "#/definitions/receipt", Code: CodeUnusedDefinition, Message: "unused"},
+			Rule:     CodeUnusedDefinition,
+			Severity: SeverityError,
+		}}),
+		FindingsSARIF([]Finding{{Pointer: "#/paths/~1pets", Code: "custom", Message: "custom finding"}}, SeverityInfo)...,
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, an.WriteSARIF(&buf, findings, SARIFOpts{
+		ToolVersion: "v1.0.0",
+		BaseDir:     "fixtures",
+		Position: func(document, pointer string) (int, int, bool) {
+			if pointer == "#/paths/~1pets" {
+				return 5, 3, true
+			}
+
+			return 0, 0, false
+		},
+	}))
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name    string `json:"name"`
+					Version string `json:"version"`
+					Rules   []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation *struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+					LogicalLocations []struct {
+						FullyQualifiedName string `json:"fullyQualifiedName"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+
+	assert.Equal(t, SARIFVersion, log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, DefaultSARIFToolName, run.Tool.Driver.Name)
+	assert.Equal(t, "v1.0.0", run.Tool.Driver.Version)
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "custom", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, CodeUnusedDefinition, run.Tool.Driver.Rules[1].ID)
+
+	require.Len(t, run.Results, 2)
+
+	// the imported definition is located in the remote document
+	imported := run.Results[0]
+	assert.Equal(t, CodeUnusedDefinition, imported.RuleID)
+	assert.Equal(t, "error", imported.Level)
+	require.NotNil(t, imported.Locations[0].PhysicalLocation)
+	assert.Equal(t, "expand/models.yml", imported.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Nil(t, imported.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "#/definitions/receipt", imported.Locations[0].LogicalLocations[0].FullyQualifiedName)
+
+	root := run.Results[1]
+	assert.Equal(t, "note", root.Level)
+	require.NotNil(t, root.Locations[0].PhysicalLocation)
+	assert.Equal(t, "expand/spec.yml", root.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.NotNil(t, root.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, 5, root.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, 3, root.Locations[0].PhysicalLocation.Region.StartColumn)
+}
+
+func TestWriteSARIF_NoDocument(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "compat", "v1.yml")))
+
+	var buf bytes.Buffer
+	findings := CompatSARIF([]CompatFinding{{
+		Finding:  Finding{Pointer: "#/paths/~1pets/get", Code: string(ChangeOperationRemoved), Message: "removed"},
+		Kind:     ChangeOperationRemoved,
+		Severity: SeverityWarning,
+	}})
+	require.NoError(t, an.WriteSARIF(&buf, findings, SARIFOpts{ToolName: "compat"}))
+
+	assert.Contains(t, buf.String(), `"name": "compat"`)
+	assert.Contains(t, buf.String(), `"level": "warning"`)
+	assert.NotContains(t, buf.String(), "physicalLocation")
+
+	buf.Reset()
+	require.NoError(t, an.WriteSARIF(&buf, findings, SARIFOpts{Document: "api/swagger.yml"}))
+	assert.Contains(t, buf.String(), `"uri": "api/swagger.yml"`)
+}
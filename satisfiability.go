@@ -0,0 +1,199 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/go-openapi/spec"
+)
+
+// Codes for findings reporting unsatisfiable schemas
+const (
+	CodeUnsatisfiableRange      = "unsatisfiable-range"
+	CodeUnsatisfiableLength     = "unsatisfiable-length"
+	CodeUnsatisfiableItems      = "unsatisfiable-items"
+	CodeUnsatisfiableProperties = "unsatisfiable-properties"
+	CodeUnsatisfiableRequired   = "unsatisfiable-required"
+	CodeUnsatisfiableEnum       = "unsatisfiable-enum"
+)
+
+// UnsatisfiableSchemas reports schemas which no value can ever validate against.
+//
+// The following impossible contracts are detected:
+//   - minimum > maximum (or minimum == maximum with any bound exclusive)
+//   - minLength > maxLength, minItems > maxItems, minProperties > maxProperties
+//   - required properties which are neither declared as properties nor allowed by additionalProperties
+//   - more required properties than maxProperties
+//   - enum values which do not match the declared type
+//
+// Findings are sorted by pointer.
+func (s *Spec) UnsatisfiableSchemas() []Finding {
+	var findings []Finding
+
+	for _, key := range sortedKeys(s.allSchemas) {
+		findings = append(findings, unsatisfiableConstraints(key, s.allSchemas[key].Schema)...)
+	}
+
+	sortFindings(findings)
+
+	return findings
+}
+
+func unsatisfiableConstraints(key string, schema *spec.Schema) []Finding {
+	if schema == nil {
+		return nil
+	}
+
+	var findings []Finding
+	report := func(code, format string, args ...interface{}) {
+		findings = append(findings, Finding{Pointer: key, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if schema.Minimum != nil && schema.Maximum != nil {
+		lo, hi := *schema.Minimum, *schema.Maximum
+		if lo > hi || (lo == hi && (schema.ExclusiveMinimum || schema.ExclusiveMaximum)) {
+			report(CodeUnsatisfiableRange, "minimum %v is not compatible with maximum %v", lo, hi)
+		}
+	}
+
+	if schema.MinLength != nil && schema.MaxLength != nil && *schema.MinLength > *schema.MaxLength {
+		report(CodeUnsatisfiableLength, "minLength %d is greater than maxLength %d", *schema.MinLength, *schema.MaxLength)
+	}
+
+	if schema.MinItems != nil && schema.MaxItems != nil && *schema.MinItems > *schema.MaxItems {
+		report(CodeUnsatisfiableItems, "minItems %d is greater than maxItems %d", *schema.MinItems, *schema.MaxItems)
+	}
+
+	if schema.MinProperties != nil && schema.MaxProperties != nil && *schema.MinProperties > *schema.MaxProperties {
+		report(CodeUnsatisfiableProperties, "minProperties %d is greater than maxProperties %d",
+			*schema.MinProperties, *schema.MaxProperties)
+	}
+
+	if schema.MaxProperties != nil && int64(len(schema.Required)) > *schema.MaxProperties {
+		report(CodeUnsatisfiableRequired, "%d properties are required, but maxProperties is %d",
+			len(schema.Required), *schema.MaxProperties)
+	}
+
+	if forbidsAdditionalProperties(schema) && len(schema.PatternProperties) == 0 {
+		for _, name := range schema.Required {
+			if _, declared := schema.Properties[name]; declared {
+				continue
+			}
+
+			report(CodeUnsatisfiableRequired,
+				"required property %q is not declared and additionalProperties are not allowed", name)
+		}
+	}
+
+	for i, value := range schema.Enum {
+		if !valueMatchesType(value, schema.Type, schema.Nullable || isXNullable(schema.Extensions)) {
+			report(CodeUnsatisfiableEnum, "enum value at index %d (%v) does not match type %v", i, value, []string(schema.Type))
+		}
+	}
+
+	return findings
+}
+
+func forbidsAdditionalProperties(schema *spec.Schema) bool {
+	return schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema == nil &&
+		!schema.AdditionalProperties.Allows
+}
+
+func isXNullable(ext spec.Extensions) bool {
+	nullable, _ := ext.GetBool("x-nullable")
+
+	return nullable
+}
+
+// valueMatchesType tells if a JSON value is valid against any of the types declared by a schema.
+//
+// An empty type accepts any value.
+func valueMatchesType(value interface{}, types spec.StringOrArray, nullable bool) bool {
+	if len(types) == 0 {
+		return true
+	}
+
+	if value == nil && nullable {
+		return true
+	}
+
+	for _, tpe := range types {
+		if jsonValueHasType(value, tpe) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsonValueHasType(value interface{}, tpe string) bool {
+	switch tpe {
+	case "":
+		return true
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+
+		return ok
+	case "string", "file":
+		_, ok := value.(string)
+
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+
+		return ok
+	case "number":
+		_, ok := asFloat(value)
+
+		return ok
+	case "integer":
+		f, ok := asFloat(value)
+
+		return ok && f == math.Trunc(f)
+	default:
+		return false
+	}
+}
+
+// asFloat converts any numerical JSON value to a float64
+func asFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
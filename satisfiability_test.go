@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsatisfiable_Schemas(t *testing.T) {
+	t.Parallel()
+
+	doc := antest.LoadOrFail(t, filepath.Join("fixtures", "constraints", "unsatisfiable.yml"))
+	an := New(doc)
+
+	findings := an.UnsatisfiableSchemas()
+
+	type expected struct {
+		Pointer string
+		Code    string
+	}
+	actual := make([]expected, 0, len(findings))
+	for _, f := range findings {
+		actual = append(actual, expected{Pointer: f.Pointer, Code: f.Code})
+		assert.NotEmpty(t, f.Message)
+	}
+
+	assert.Equal(t, []expected{
+		{Pointer: "#/definitions/pet", Code: CodeUnsatisfiableRequired},
+		{Pointer: "#/definitions/pet/properties/age", Code: CodeUnsatisfiableRange},
+		{Pointer: "#/definitions/pet/properties/kind", Code: CodeUnsatisfiableEnum},
+		{Pointer: "#/definitions/pet/properties/legs", Code: CodeUnsatisfiableEnum},
+		{Pointer: "#/definitions/pet/properties/name", Code: CodeUnsatisfiableLength},
+		{Pointer: "#/definitions/pet/properties/weight", Code: CodeUnsatisfiableRange},
+		{Pointer: "#/definitions/tag", Code: CodeUnsatisfiableProperties},
+		{Pointer: "#/definitions/tag", Code: CodeUnsatisfiableRequired},
+		{Pointer: "#/paths/~1pets/get/responses/200/schema", Code: CodeUnsatisfiableItems},
+	}, actual)
+}
+
+func TestUnsatisfiable_ValueMatchesType(t *testing.T) {
+	t.Parallel()
+
+	for _, toPin := range []struct {
+		Value    interface{}
+		Types    spec.StringOrArray
+		Nullable bool
+		Expected bool
+	}{
+		{Value: "a", Types: nil, Expected: true},
+		{Value: "a", Types: spec.StringOrArray{"string"}, Expected: true},
+		{Value: 1.0, Types: spec.StringOrArray{"integer"}, Expected: true},
+		{Value: 1.5, Types: spec.StringOrArray{"integer"}, Expected: false},
+		{Value: int64(3), Types: spec.StringOrArray{"number"}, Expected: true},
+		{Value: nil, Types: spec.StringOrArray{"string"}, Expected: false},
+		{Value: nil, Types: spec.StringOrArray{"string"}, Nullable: true, Expected: true},
+		{Value: nil, Types: spec.StringOrArray{"string", "null"}, Expected: true},
+		{Value: true, Types: spec.StringOrArray{"boolean"}, Expected: true},
+		{Value: []interface{}{}, Types: spec.StringOrArray{"array"}, Expected: true},
+		{Value: map[string]interface{}{}, Types: spec.StringOrArray{"object"}, Expected: true},
+		{Value: map[string]interface{}{}, Types: spec.StringOrArray{"unknown"}, Expected: false},
+	} {
+		fixture := toPin
+		require.Equalf(t, fixture.Expected, valueMatchesType(fixture.Value, fixture.Types, fixture.Nullable),
+			"unexpected result for %v against %v", fixture.Value, fixture.Types)
+	}
+}
@@ -1,10 +1,11 @@
 package analysis
 
 import (
-	"fmt"
+	"errors"
+	"strings"
 
+	"github.com/go-openapi/analysis/internal/flatten/normalize"
 	"github.com/go-openapi/spec"
-	"github.com/go-openapi/strfmt"
 )
 
 // SchemaOpts configures the schema analyzer
@@ -12,26 +13,52 @@ type SchemaOpts struct {
 	Schema   *spec.Schema
 	Root     interface{}
 	BasePath string
-	_        struct{}
+
+	// ExtraFormats are custom formats (e.g. "decimal", "ulid") which classify schemas as known types,
+	// in addition to the formats known by strfmt.Default and those declared with RegisterFormat
+	ExtraFormats []string
+
+	// Logger traces the $ref's resolved by the analysis. Defaults to the Logger set with SetLogger.
+	Logger Logger
+
+	// OnResolve is called before the analysis resolves a $ref, including the $ref's of the schemas it refers to,
+	// with the $ref and the document it is resolved from (BasePath, the remote document holding the $ref, or empty
+	// for the root document). It may log the $ref, veto its resolution by returning an error, or rewrite it
+	// by returning a *RefRedirect (e.g. to map a public location to an internal mirror).
+	//
+	// Errors other than *RefRedirect are returned by Schema as a *RefError wrapping them.
+	OnResolve func(ref, from string) error
+
+	// resolving holds the (rebased) $ref's being resolved by the enclosing analyses
+	resolving []string
+	// remote is set when BasePath is a remote document the schema comes from, rather than the location of Root
+	remote bool
+	_      struct{}
 }
 
 // Schema analysis, will classify the schema according to known
 // patterns.
 func Schema(opts SchemaOpts) (*AnalyzedSchema, error) {
 	if opts.Schema == nil {
-		return nil, fmt.Errorf("no schema to analyze")
+		return nil, ErrNoSchema
 	}
 
 	a := &AnalyzedSchema{
-		schema:   opts.Schema,
-		root:     opts.Root,
-		basePath: opts.BasePath,
+		schema:       opts.Schema,
+		root:         opts.Root,
+		basePath:     opts.BasePath,
+		extraFormats: opts.ExtraFormats,
+		logger:       opts.Logger,
+		onResolve:    opts.OnResolve,
+		resolving:    opts.resolving,
+		remote:       opts.remote,
 	}
 
 	a.initializeFlags()
 	a.inferKnownType()
 	a.inferEnum()
 	a.inferBaseType()
+	a.inferIntegerType()
 
 	if err := a.inferMap(); err != nil {
 		return nil, err
@@ -41,11 +68,16 @@ func Schema(opts SchemaOpts) (*AnalyzedSchema, error) {
 	}
 
 	a.inferTuple()
+	a.inferGoTypeHint()
 
 	if err := a.inferFromRef(); err != nil {
 		return nil, err
 	}
 
+	if err := a.inferConstraints(); err != nil {
+		return nil, err
+	}
+
 	a.inferSimpleSchema()
 
 	return a, nil
@@ -53,9 +85,15 @@ func Schema(opts SchemaOpts) (*AnalyzedSchema, error) {
 
 // AnalyzedSchema indicates what the schema represents
 type AnalyzedSchema struct {
-	schema   *spec.Schema
-	root     interface{}
-	basePath string
+	schema       *spec.Schema
+	root         interface{}
+	basePath     string
+	extraFormats []string
+	logger       Logger
+	onResolve    func(ref, from string) error
+	resolutions  map[string]refResolution
+	resolving    []string
+	remote       bool
 
 	hasProps           bool
 	hasAllOf           bool
@@ -75,6 +113,20 @@ type AnalyzedSchema struct {
 	IsTupleWithExtra bool
 	IsBaseType       bool
 	IsEnum           bool
+
+	// IntegerType is the narrowest go integer type able to hold all the values allowed
+	// by an integer schema (e.g. "uint8", "int32"). This is empty for non-integer schemas.
+	IntegerType string
+
+	// IsIntegerOverflow indicates an integer schema which allows values beyond what int64 or uint64 can hold
+	IsIntegerOverflow bool
+
+	// Constraints summarizes the numeric, string and array validations of the schema,
+	// including those inherited through allOf and $ref
+	Constraints Constraints
+
+	// GoTypeHint is the canonical go type the schema maps to, including the hint for the elements of arrays and maps
+	GoTypeHint GoTypeHint
 }
 
 // Inherits copies value fields from other onto this schema
@@ -100,34 +152,124 @@ func (a *AnalyzedSchema) inherits(other *AnalyzedSchema) {
 	a.IsTupleWithExtra = other.IsTupleWithExtra
 	a.IsBaseType = other.IsBaseType
 	a.IsEnum = other.IsEnum
+	a.IntegerType = other.IntegerType
+	a.IsIntegerOverflow = other.IsIntegerOverflow
+	a.GoTypeHint = other.GoTypeHint
 }
 
 func (a *AnalyzedSchema) inferFromRef() error {
 	if a.hasRef {
-		sch := new(spec.Schema)
-		sch.Ref = a.schema.Ref
-		err := spec.ExpandSchema(sch, a.root, nil)
+		ref, err := a.resolvedRef(a.schema.Ref)
 		if err != nil {
 			return err
 		}
+
+		key := normalize.RebaseRef(a.basePath, ref.String())
+		if containsString(a.resolving, key) { // stop on circular $ref's
+			a.tracef("not resolving circular schema $ref %s", ref.String())
+
+			return nil
+		}
+
+		a.tracef("resolving schema $ref %s", ref.String())
+		sch, err := a.resolveRef(&ref, a.basePath, a.remote)
+		if err != nil {
+			return &RefError{Ref: a.schema.Ref.String(), Cause: err}
+		}
+
+		// $ref's in a remote document are relative to this document
+		basePath, remote := a.basePath, a.remote
+		if !ref.HasFragmentOnly {
+			basePath, _, _ = strings.Cut(key, "#")
+			remote = true
+		}
+
 		rsch, err := Schema(SchemaOpts{
-			Schema:   sch,
-			Root:     a.root,
-			BasePath: a.basePath,
+			Schema:       sch,
+			Root:         a.root,
+			BasePath:     basePath,
+			ExtraFormats: a.extraFormats,
+			Logger:       a.logger,
+			OnResolve:    a.onResolve,
+			resolving:    append(a.resolving[:len(a.resolving):len(a.resolving)], key),
+			remote:       remote,
 		})
 		if err != nil {
-			// NOTE(fredbi): currently the only cause for errors is
-			// unresolved ref. Since spec.ExpandSchema() expands the
-			// schema recursively, there is no chance to get there,
-			// until we add more causes for error in this schema analysis.
 			return err
 		}
 		a.inherits(rsch)
+
+		if name, isDefinition := definitionOfPointer(a.schema.Ref.String()); isDefinition {
+			a.GoTypeHint.Definition = name
+			if a.GoTypeHint.Kind == GoTypeStruct {
+				a.GoTypeHint.Type = name
+			}
+		}
 	}
 
 	return nil
 }
 
+// resolveRef resolves a $ref found in some document: the root document, or a remote document loaded
+// from its base path
+func (a *AnalyzedSchema) resolveRef(ref *spec.Ref, basePath string, remote bool) (*spec.Schema, error) {
+	root := a.root
+	if remote {
+		root = nil
+	}
+
+	return spec.ResolveRefWithBase(root, ref, &spec.ExpandOptions{RelativeBase: basePath})
+}
+
+// refResolution is the outcome of the OnResolve hook for a $ref
+type refResolution struct {
+	ref spec.Ref
+	err error
+}
+
+// resolvedRef submits a $ref to the OnResolve hook, and yields the $ref to resolve instead.
+//
+// The outcome is remembered, so the hook is called only once per $ref for a given schema.
+func (a *AnalyzedSchema) resolvedRef(ref spec.Ref) (spec.Ref, error) {
+	if a.onResolve == nil {
+		return ref, nil
+	}
+
+	key := ref.String()
+	if resolution, done := a.resolutions[key]; done {
+		return resolution.ref, resolution.err
+	}
+
+	resolution := refResolution{ref: ref}
+	var redirect *RefRedirect
+	switch err := a.onResolve(key, a.basePath); {
+	case err == nil:
+	case errors.As(err, &redirect):
+		a.tracef("redirecting $ref %s to %s", key, redirect.Ref)
+		rewritten, erc := spec.NewRef(redirect.Ref)
+		if erc != nil {
+			resolution.err = &RefError{Ref: key, Cause: erc}
+
+			break
+		}
+		resolution.ref = rewritten
+	default:
+		resolution.err = &RefError{Ref: key, Cause: err}
+	}
+
+	if a.resolutions == nil {
+		a.resolutions = make(map[string]refResolution)
+	}
+	a.resolutions[key] = resolution
+
+	return resolution.ref, resolution.err
+}
+
+// tracef traces a decision made while analyzing the schema
+func (a *AnalyzedSchema) tracef(format string, args ...interface{}) {
+	tracef(a.logger, format, args...)
+}
+
 func (a *AnalyzedSchema) inferSimpleSchema() {
 	a.IsSimpleSchema = a.IsKnownType || a.IsSimpleArray || a.IsSimpleMap
 }
@@ -139,10 +281,20 @@ func (a *AnalyzedSchema) inferKnownType() {
 		tpe.Contains("integer") ||
 		tpe.Contains("number") ||
 		tpe.Contains("string") ||
-		(format != "" && strfmt.Default.ContainsName(format)) ||
+		(format != "" && a.isKnownFormat(format)) ||
 		(a.isObjectType() && !a.hasProps && !a.hasAllOf && !a.hasAdditionalProps && !a.hasAdditionalItems)
 }
 
+func (a *AnalyzedSchema) isKnownFormat(format string) bool {
+	for _, extra := range a.extraFormats {
+		if extra == format {
+			return true
+		}
+	}
+
+	return IsKnownFormat(format)
+}
+
 func (a *AnalyzedSchema) inferMap() error {
 	if !a.isObjectType() {
 		return nil
@@ -159,14 +311,20 @@ func (a *AnalyzedSchema) inferMap() error {
 	// maps
 	if a.schema.AdditionalProperties.Schema != nil {
 		msch, err := Schema(SchemaOpts{
-			Schema:   a.schema.AdditionalProperties.Schema,
-			Root:     a.root,
-			BasePath: a.basePath,
+			Schema:       a.schema.AdditionalProperties.Schema,
+			Root:         a.root,
+			BasePath:     a.basePath,
+			ExtraFormats: a.extraFormats,
+			Logger:       a.logger,
+			OnResolve:    a.onResolve,
+			resolving:    a.resolving,
+			remote:       a.remote,
 		})
 		if err != nil {
 			return err
 		}
 		a.IsSimpleMap = msch.IsSimpleSchema
+		a.GoTypeHint.Elem = &msch.GoTypeHint
 	} else if a.schema.AdditionalProperties.Allows {
 		a.IsSimpleMap = true
 	}
@@ -186,15 +344,21 @@ func (a *AnalyzedSchema) inferArray() error {
 	if a.IsArray && a.hasItems {
 		if a.schema.Items.Schema != nil {
 			itsch, err := Schema(SchemaOpts{
-				Schema:   a.schema.Items.Schema,
-				Root:     a.root,
-				BasePath: a.basePath,
+				Schema:       a.schema.Items.Schema,
+				Root:         a.root,
+				BasePath:     a.basePath,
+				ExtraFormats: a.extraFormats,
+				Logger:       a.logger,
+				OnResolve:    a.onResolve,
+				resolving:    a.resolving,
+				remote:       a.remote,
 			})
 			if err != nil {
 				return err
 			}
 
 			a.IsSimpleArray = itsch.IsSimpleSchema
+			a.GoTypeHint.Elem = &itsch.GoTypeHint
 		}
 	}
 
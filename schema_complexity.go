@@ -0,0 +1,57 @@
+package analysis
+
+import "github.com/go-openapi/spec"
+
+// Score measures the complexity of a schema, not following $ref's
+type Score struct {
+	// Depth is the deepest nesting of schemas (properties, items, allOf, ...). A schema without any child has depth 1.
+	Depth int
+
+	// Schemas counts the schema and all its children
+	Schemas int
+
+	// Properties counts the properties declared by the schema and its children
+	Properties int
+
+	// Branches counts the members of allOf, anyOf and oneOf compositions
+	Branches int
+
+	// Refs counts the $ref's found in the schema and its children
+	Refs int
+
+	// RefFanOut counts the distinct targets of these $ref's
+	RefFanOut int
+
+	// Total sums all the other metrics, counting one point for every nesting level below the schema:
+	// a primitive schema scores 1, an object with two primitive properties scores 6.
+	Total int
+}
+
+// SchemaComplexity scores the complexity of a schema.
+//
+// This is a shared measure for rules such as flattening thresholds (see FlattenOpts.InlineThreshold),
+// or governance policies (e.g. "no schema above complexity 50").
+func SchemaComplexity(schema *spec.Schema) Score {
+	var score Score
+	if schema == nil {
+		return score
+	}
+
+	targets := make(map[string]bool)
+	walkSchema("", schema, func(_ string, sch *spec.Schema) {
+		score.Schemas++
+		score.Properties += len(sch.Properties)
+		score.Branches += len(sch.AllOf) + len(sch.AnyOf) + len(sch.OneOf)
+
+		if ref := sch.Ref.String(); ref != "" {
+			score.Refs++
+			targets[ref] = true
+		}
+	})
+
+	score.Depth = schemaDepth(schema)
+	score.RefFanOut = len(targets)
+	score.Total = score.Schemas + score.Properties + score.Branches + score.Refs + score.RefFanOut + score.Depth - 1
+
+	return score
+}
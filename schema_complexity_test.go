@@ -0,0 +1,51 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaComplexity(t *testing.T) {
+	t.Parallel()
+
+	for _, toPin := range []struct {
+		Title    string
+		Schema   string
+		Expected Score
+	}{
+		{
+			Title:    "primitive",
+			Schema:   `{"type": "string", "format": "date"}`,
+			Expected: Score{Depth: 1, Schemas: 1, Total: 1},
+		},
+		{
+			Title:    "object",
+			Schema:   `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}}`,
+			Expected: Score{Depth: 2, Schemas: 3, Properties: 2, Total: 6},
+		},
+		{
+			Title: "compositions and $ref's",
+			Schema: `{
+				"allOf": [
+					{"$ref": "#/definitions/base"},
+					{"type": "object", "properties": {
+						"tags": {"type": "array", "items": {"$ref": "#/definitions/tag"}},
+						"parent": {"$ref": "#/definitions/base"}
+					}}
+				]
+			}`,
+			Expected: Score{Depth: 4, Schemas: 6, Properties: 2, Branches: 2, Refs: 3, RefFanOut: 2, Total: 18},
+		},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.Title, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, fixture.Expected, SchemaComplexity(schemaFromJSON(t, fixture.Schema)))
+		})
+	}
+
+	assert.Equal(t, Score{}, SchemaComplexity(nil))
+}
@@ -0,0 +1,144 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/go-openapi/analysis/internal/flatten/normalize"
+	"github.com/go-openapi/spec"
+)
+
+// Constraints summarizes the validations enforced on the values of a schema, including the validations
+// inherited through allOf and $ref.
+//
+// Missing bounds are nil. When several schemas constrain the same bound, the tightest one is retained.
+type Constraints struct {
+	Minimum          *float64
+	ExclusiveMinimum bool
+	Maximum          *float64
+	ExclusiveMaximum bool
+
+	// MultipleOf holds all the factors values must be a multiple of. Factors implied by others
+	// (e.g. 2 when 4 is required) are omitted.
+	MultipleOf []float64
+
+	MinLength *int64
+	MaxLength *int64
+
+	// Patterns holds all the patterns strings must match
+	Patterns []string
+
+	MinItems    *int64
+	MaxItems    *int64
+	UniqueItems bool
+}
+
+// inferConstraints collects the constraints of a schema, its allOf members and the schemas it refers to
+func (a *AnalyzedSchema) inferConstraints() error {
+	visited := make(map[string]bool)
+
+	// the base is the location of the document the schema comes from: $ref's in a remote document
+	// are relative to this document
+	var collect func(*spec.Schema, string, bool) error
+	collect = func(schema *spec.Schema, base string, remote bool) error {
+		a.Constraints.merge(schema)
+
+		if ref := schema.Ref.String(); ref != "" {
+			target, err := a.resolvedRef(schema.Ref)
+			if err != nil {
+				return err
+			}
+
+			key := normalize.RebaseRef(base, target.String())
+			if !visited[key] {
+				visited[key] = true
+				a.tracef("resolving $ref %s to infer constraints", ref)
+
+				resolved, err := a.resolveRef(&target, base, remote)
+				if err != nil {
+					return &RefError{Ref: ref, Cause: err}
+				}
+
+				document, isRemote := base, remote
+				if !target.HasFragmentOnly {
+					document, _, _ = strings.Cut(key, "#")
+					isRemote = true
+				}
+
+				if err := collect(resolved, document, isRemote); err != nil {
+					return err
+				}
+			}
+		}
+
+		for i := range schema.AllOf {
+			if err := collect(&schema.AllOf[i], base, remote); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return collect(a.schema, a.basePath, a.remote)
+}
+
+// merge narrows constraints with the validations of a schema
+func (c *Constraints) merge(schema *spec.Schema) {
+	if lo := schema.Minimum; lo != nil {
+		if c.Minimum == nil || *lo > *c.Minimum || *lo == *c.Minimum && schema.ExclusiveMinimum {
+			c.Minimum, c.ExclusiveMinimum = lo, schema.ExclusiveMinimum
+		}
+	}
+
+	if hi := schema.Maximum; hi != nil {
+		if c.Maximum == nil || *hi < *c.Maximum || *hi == *c.Maximum && schema.ExclusiveMaximum {
+			c.Maximum, c.ExclusiveMaximum = hi, schema.ExclusiveMaximum
+		}
+	}
+
+	if schema.MultipleOf != nil {
+		c.addMultipleOf(*schema.MultipleOf)
+	}
+
+	c.MinLength = maxLimit(c.MinLength, schema.MinLength)
+	c.MaxLength = minLimit(c.MaxLength, schema.MaxLength)
+	c.MinItems = maxLimit(c.MinItems, schema.MinItems)
+	c.MaxItems = minLimit(c.MaxItems, schema.MaxItems)
+
+	if schema.Pattern != "" && !containsString(c.Patterns, schema.Pattern) {
+		c.Patterns = append(c.Patterns, schema.Pattern)
+	}
+
+	c.UniqueItems = c.UniqueItems || schema.UniqueItems
+}
+
+func (c *Constraints) addMultipleOf(factor float64) {
+	retained := c.MultipleOf[:0]
+	for _, other := range c.MultipleOf {
+		if isMultiple(other, factor) {
+			return // already implied
+		}
+
+		if !isMultiple(factor, other) {
+			retained = append(retained, other)
+		}
+	}
+
+	c.MultipleOf = append(retained, factor)
+}
+
+func maxLimit(limit, other *int64) *int64 {
+	if other != nil && (limit == nil || *other > *limit) {
+		return other
+	}
+
+	return limit
+}
+
+func minLimit(limit, other *int64) *int64 {
+	if other != nil && (limit == nil || *other < *limit) {
+		return other
+	}
+
+	return limit
+}
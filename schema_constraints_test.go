@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaAnalysis_Constraints(t *testing.T) {
+	t.Parallel()
+
+	root := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{
+		"positive": *schemaFromJSON(t, `{"type": "integer", "minimum": 0, "exclusiveMinimum": true, "multipleOf": 2}`),
+		"code":     *schemaFromJSON(t, `{"allOf": [{"$ref": "#/definitions/code"}], "type": "string", "pattern": "^[A-Z]+$", "maxLength": 8}`),
+		"tags":     *schemaFromJSON(t, `{"type": "array", "minItems": 1, "maxItems": 10}`),
+	}}}
+
+	t.Run("with own constraints", func(t *testing.T) {
+		t.Parallel()
+
+		sch, err := Schema(SchemaOpts{Root: root, Schema: schemaFromJSON(t, `{"type": "number", "minimum": 1, "maximum": 10, "exclusiveMaximum": true}`)})
+		require.NoError(t, err)
+
+		assert.Equal(t, Constraints{Minimum: swag.Float64(1), Maximum: swag.Float64(10), ExclusiveMaximum: true}, sch.Constraints)
+	})
+
+	t.Run("with constraints inherited through $ref and allOf", func(t *testing.T) {
+		t.Parallel()
+
+		sch, err := Schema(SchemaOpts{Root: root, Schema: schemaFromJSON(t, `{
+			"allOf": [
+				{"$ref": "#/definitions/positive"},
+				{"minimum": 0, "maximum": 100, "multipleOf": 4},
+				{"maximum": 50, "multipleOf": 3}
+			]
+		}`)})
+		require.NoError(t, err)
+
+		c := sch.Constraints
+		require.NotNil(t, c.Minimum)
+		assert.InDelta(t, 0, *c.Minimum, 1e-9)
+		assert.True(t, c.ExclusiveMinimum)
+		require.NotNil(t, c.Maximum)
+		assert.InDelta(t, 50, *c.Maximum, 1e-9)
+		assert.False(t, c.ExclusiveMaximum)
+		assert.Equal(t, []float64{4, 3}, c.MultipleOf)
+	})
+
+	t.Run("with circular $ref", func(t *testing.T) {
+		t.Parallel()
+
+		sch, err := Schema(SchemaOpts{Root: root, Schema: schemaFromJSON(t, `{"$ref": "#/definitions/code", "pattern": "^A", "minLength": 2}`)})
+		require.NoError(t, err)
+
+		c := sch.Constraints
+		assert.Equal(t, []string{"^A", "^[A-Z]+$"}, c.Patterns)
+		assert.Equal(t, swag.Int64(2), c.MinLength)
+		assert.Equal(t, swag.Int64(8), c.MaxLength)
+	})
+
+	t.Run("with array constraints", func(t *testing.T) {
+		t.Parallel()
+
+		sch, err := Schema(SchemaOpts{Root: root, Schema: schemaFromJSON(t, `{
+			"allOf": [{"$ref": "#/definitions/tags"}, {"maxItems": 20, "uniqueItems": true}],
+			"minItems": 2
+		}`)})
+		require.NoError(t, err)
+
+		c := sch.Constraints
+		assert.Equal(t, swag.Int64(2), c.MinItems)
+		assert.Equal(t, swag.Int64(10), c.MaxItems)
+		assert.True(t, c.UniqueItems)
+	})
+
+	t.Run("with unresolved $ref", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Schema(SchemaOpts{Root: root, Schema: spec.RefSchema("#/definitions/nowhere")})
+		require.Error(t, err)
+	})
+}
+
+func TestSchemaAnalysis_ConstraintsRemote(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "constraints", "remote.yml")
+	sp := antest.LoadOrFail(t, bp)
+
+	// the local $ref in the remote document is relative to this document
+	sch, err := Schema(SchemaOpts{Root: sp, BasePath: bp, Schema: sp.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200].Schema})
+	require.NoError(t, err)
+
+	c := sch.Constraints
+	assert.Equal(t, []string{"^[A-Z]+$"}, c.Patterns)
+	assert.Equal(t, swag.Int64(2), c.MinLength)
+	assert.Equal(t, swag.Int64(8), c.MaxLength)
+}
@@ -0,0 +1,386 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// SchemaChangeKind is the class of a change between two versions of a schema
+type SchemaChangeKind string
+
+// Classes of changes between two versions of a schema
+const (
+	ChangePropertyAdded   SchemaChangeKind = "property-added"
+	ChangePropertyRemoved SchemaChangeKind = "property-removed"
+
+	// ChangeTypeChanged is reported at the location of a property when this property is retyped
+	ChangeTypeChanged   SchemaChangeKind = "type-changed"
+	ChangeFormatChanged SchemaChangeKind = "format-changed"
+	ChangeRefChanged    SchemaChangeKind = "ref-changed"
+
+	ChangeRequiredAdded   SchemaChangeKind = "required-added"
+	ChangeRequiredRemoved SchemaChangeKind = "required-removed"
+
+	ChangeEnumValueAdded   SchemaChangeKind = "enum-value-added"
+	ChangeEnumValueRemoved SchemaChangeKind = "enum-value-removed"
+
+	// ChangeConstraintTightened is reported for a constraint which rejects more values than before
+	// (e.g. a lower maxLength, or a new pattern), ChangeConstraintRelaxed for one which accepts more values
+	ChangeConstraintTightened SchemaChangeKind = "constraint-tightened"
+	ChangeConstraintRelaxed   SchemaChangeKind = "constraint-relaxed"
+
+	ChangeCompositionMemberAdded   SchemaChangeKind = "composition-member-added"
+	ChangeCompositionMemberRemoved SchemaChangeKind = "composition-member-removed"
+
+	// ChangeAnnotationChanged is only reported with SchemaDiffOpts.IncludeAnnotations
+	ChangeAnnotationChanged SchemaChangeKind = "annotation-changed"
+)
+
+// SchemaChange is a change between two versions of a schema
+type SchemaChange struct {
+	Pointer string // location in both schemas, e.g. "#/properties/name"
+	Kind    SchemaChangeKind
+	Keyword string // the keyword which changed, e.g. "maxLength", "required" or "allOf"
+
+	// Before and After are the values which changed, nil when absent: the values of the keyword,
+	// or the property, required property, enum value or composition member added or removed
+	Before interface{}
+	After  interface{}
+
+	Message string
+}
+
+// SchemaDiffOpts configures DiffSchemas
+type SchemaDiffOpts struct {
+	// IncludeAnnotations reports the changes of title, description, default and example.
+	// Only changes to validations are reported by default.
+	IncludeAnnotations bool
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// constraintDirections tells whether a greater value of a constraint rejects more values (+1) or fewer values (-1).
+// Other constraints are tightened by any change.
+var constraintDirections = map[string]int{
+	"maximum": -1, "maxLength": -1, "maxItems": -1, "maxProperties": -1,
+	"minimum": 1, "minLength": 1, "minItems": 1, "minProperties": 1,
+}
+
+// booleanConstraints are the constraints which reject values when true ("x-nullable" accepts null when true)
+var booleanConstraints = map[string]bool{
+	"exclusiveMaximum": true, "exclusiveMinimum": true, "uniqueItems": true, "readOnly": true, "x-nullable": false,
+}
+
+var (
+	diffedConstraints = []string{
+		"maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum", "multipleOf",
+		"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems",
+		"maxProperties", "minProperties", "readOnly", "x-nullable",
+	}
+	diffedAnnotations  = []string{"title", "description", "default", "example"}
+	diffedCompositions = []string{"allOf", "anyOf", "oneOf"}
+)
+
+// DiffSchemas compares two versions of a schema, property by property: properties added or removed, types,
+// formats and $ref's changed, required properties, enum values, constraints and composition members (allOf, anyOf,
+// oneOf) added or removed.
+//
+// Changes are located in both schemas, and sorted by pointer. Composition members are matched by content, then by
+// position. The schemas are not resolved: $ref's are compared as they are.
+func DiffSchemas(before, after *spec.Schema, opts SchemaDiffOpts) []SchemaChange {
+	d := &schemaDiff{opts: opts}
+	d.diff("#", schemaAsJSON(before), schemaAsJSON(after))
+
+	sort.SliceStable(d.changes, func(i, j int) bool { return d.changes[i].Pointer < d.changes[j].Pointer })
+
+	return d.changes
+}
+
+type schemaDiff struct {
+	opts    SchemaDiffOpts
+	changes []SchemaChange
+}
+
+func (d *schemaDiff) report(pointer string, kind SchemaChangeKind, keyword string, before, after interface{}, format string, args ...interface{}) {
+	d.changes = append(d.changes, SchemaChange{
+		Pointer: pointer,
+		Kind:    kind,
+		Keyword: keyword,
+		Before:  before,
+		After:   after,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *schemaDiff) diff(pointer string, a, b map[string]interface{}) {
+	if reflect.DeepEqual(a, b) {
+		return
+	}
+
+	if !reflect.DeepEqual(a["$ref"], b["$ref"]) {
+		d.report(pointer, ChangeRefChanged, "$ref", a["$ref"], b["$ref"], "$ref changed from %v to %v", a["$ref"], b["$ref"])
+	}
+
+	if typesA, typesB := schemaTypes(a), schemaTypes(b); !reflect.DeepEqual(typesA, typesB) {
+		d.report(pointer, ChangeTypeChanged, "type", a["type"], b["type"], "type changed from %v to %v", typesA, typesB)
+	}
+
+	if !reflect.DeepEqual(a["format"], b["format"]) {
+		d.report(pointer, ChangeFormatChanged, "format", a["format"], b["format"], "format changed from %v to %v", a["format"], b["format"])
+	}
+
+	d.diffEnum(pointer, a, b)
+
+	for _, keyword := range diffedConstraints {
+		d.diffConstraint(pointer, keyword, a[keyword], b[keyword])
+	}
+
+	d.diffRequired(pointer, a, b)
+	d.diffProperties(pointer, a, b)
+	d.diffSubSchema(pointer, "additionalProperties", a, b)
+	d.diffSubSchema(pointer, "not", a, b)
+	d.diffItems(pointer, a, b)
+
+	for _, keyword := range diffedCompositions {
+		d.diffComposition(pointer, keyword, a, b)
+	}
+
+	if d.opts.IncludeAnnotations {
+		for _, keyword := range diffedAnnotations {
+			if !reflect.DeepEqual(a[keyword], b[keyword]) {
+				d.report(pointer, ChangeAnnotationChanged, keyword, a[keyword], b[keyword], "%s changed", keyword)
+			}
+		}
+	}
+}
+
+func (d *schemaDiff) diffEnum(pointer string, a, b map[string]interface{}) {
+	enumA, isEnumA := a["enum"].([]interface{})
+	enumB, isEnumB := b["enum"].([]interface{})
+
+	switch {
+	case !isEnumA && !isEnumB:
+	case !isEnumA:
+		d.report(pointer, ChangeConstraintTightened, "enum", nil, enumB, "enum added")
+	case !isEnumB:
+		d.report(pointer, ChangeConstraintRelaxed, "enum", enumA, nil, "enum removed")
+	default:
+		for _, value := range enumA {
+			if !containsValue(enumB, value) {
+				d.report(pointer, ChangeEnumValueRemoved, "enum", value, nil, "enum value %v removed", value)
+			}
+		}
+
+		for _, value := range enumB {
+			if !containsValue(enumA, value) {
+				d.report(pointer, ChangeEnumValueAdded, "enum", nil, value, "enum value %v added", value)
+			}
+		}
+	}
+}
+
+func (d *schemaDiff) diffConstraint(pointer, keyword string, before, after interface{}) {
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+
+	var tightened bool
+	if rejectsWhenTrue, isBoolean := booleanConstraints[keyword]; isBoolean {
+		// absent booleans are false
+		valueBefore, _ := before.(bool)
+		valueAfter, _ := after.(bool)
+		if valueBefore == valueAfter {
+			return
+		}
+
+		tightened = valueAfter == rejectsWhenTrue
+	} else {
+		tightened = after != nil && (before == nil || constraintTightened(keyword, before, after))
+	}
+
+	kind := ChangeConstraintRelaxed
+	if tightened {
+		kind = ChangeConstraintTightened
+	}
+
+	switch {
+	case before == nil:
+		d.report(pointer, kind, keyword, nil, after, "%s %v added", keyword, after)
+	case after == nil:
+		d.report(pointer, kind, keyword, before, nil, "%s %v removed", keyword, before)
+	default:
+		d.report(pointer, kind, keyword, before, after, "%s changed from %v to %v", keyword, before, after)
+	}
+}
+
+// constraintTightened tells if a new value of a constraint rejects more values than the former value
+func constraintTightened(keyword string, before, after interface{}) bool {
+	a, isNumberA := asFloat(before)
+	b, isNumberB := asFloat(after)
+	if !isNumberA || !isNumberB {
+		return true // e.g. a new pattern
+	}
+
+	if keyword == "multipleOf" {
+		// a divisor of the former multiple accepts more values
+		ratio := a / b
+		return ratio != math.Trunc(ratio)
+	}
+
+	direction, isOrdered := constraintDirections[keyword]
+	if !isOrdered {
+		return true
+	}
+
+	return (b-a)*float64(direction) > 0
+}
+
+func (d *schemaDiff) diffRequired(pointer string, a, b map[string]interface{}) {
+	requiredA := asStrings(a["required"])
+	requiredB := asStrings(b["required"])
+
+	for _, name := range requiredA {
+		if !containsString(requiredB, name) {
+			d.report(pointer, ChangeRequiredRemoved, "required", name, nil, "property %q no longer required", name)
+		}
+	}
+
+	for _, name := range requiredB {
+		if !containsString(requiredA, name) {
+			d.report(pointer, ChangeRequiredAdded, "required", nil, name, "property %q now required", name)
+		}
+	}
+}
+
+func (d *schemaDiff) diffProperties(pointer string, a, b map[string]interface{}) {
+	propertiesA := asObject(a["properties"])
+	propertiesB := asObject(b["properties"])
+
+	for _, name := range sortedKeys(propertiesA) {
+		property := pointer + "/properties/" + jsonpointer.Escape(name)
+		if _, exists := propertiesB[name]; !exists {
+			d.report(property, ChangePropertyRemoved, "properties", propertiesA[name], nil, "property %q removed", name)
+
+			continue
+		}
+
+		d.diff(property, asObject(propertiesA[name]), asObject(propertiesB[name]))
+	}
+
+	for _, name := range sortedKeys(propertiesB) {
+		if _, exists := propertiesA[name]; !exists {
+			property := pointer + "/properties/" + jsonpointer.Escape(name)
+			d.report(property, ChangePropertyAdded, "properties", nil, propertiesB[name], "property %q added", name)
+		}
+	}
+}
+
+// diffSubSchema compares a keyword which holds a schema, or a boolean for additionalProperties
+func (d *schemaDiff) diffSubSchema(pointer, keyword string, a, b map[string]interface{}) {
+	va, vb := a[keyword], b[keyword]
+	if reflect.DeepEqual(va, vb) {
+		return
+	}
+
+	schemaA, isSchemaA := va.(map[string]interface{})
+	schemaB, isSchemaB := vb.(map[string]interface{})
+	if isSchemaA && isSchemaB {
+		d.diff(pointer+"/"+keyword, schemaA, schemaB)
+
+		return
+	}
+
+	// additional properties are allowed when absent, and forbidden when false
+	tightened := vb != nil
+	if keyword == "additionalProperties" {
+		tightened = vb == false || (va == nil || va == true) && isSchemaB
+	}
+
+	kind := ChangeConstraintRelaxed
+	if tightened {
+		kind = ChangeConstraintTightened
+	}
+	d.report(pointer, kind, keyword, va, vb, "%s changed", keyword)
+}
+
+func (d *schemaDiff) diffItems(pointer string, a, b map[string]interface{}) {
+	itemsA, itemsB := a["items"], b["items"]
+	tupleA, isTupleA := itemsA.([]interface{})
+	tupleB, isTupleB := itemsB.([]interface{})
+
+	switch {
+	case itemsA == nil && itemsB == nil:
+	case isTupleA && isTupleB:
+		for i := 0; i < len(tupleA) || i < len(tupleB); i++ {
+			item := pointer + "/items/" + strconv.Itoa(i)
+			switch {
+			case i >= len(tupleB):
+				d.report(item, ChangeConstraintRelaxed, "items", tupleA[i], nil, "tuple item %d removed", i)
+			case i >= len(tupleA):
+				d.report(item, ChangeConstraintTightened, "items", nil, tupleB[i], "tuple item %d added", i)
+			default:
+				d.diff(item, asObject(tupleA[i]), asObject(tupleB[i]))
+			}
+		}
+	case isTupleA || isTupleB || itemsA == nil || itemsB == nil:
+		kind := ChangeConstraintTightened
+		if itemsB == nil {
+			kind = ChangeConstraintRelaxed
+		}
+		d.report(pointer, kind, "items", itemsA, itemsB, "items changed")
+	default:
+		d.diff(pointer+"/items", asObject(itemsA), asObject(itemsB))
+	}
+}
+
+// diffComposition compares the members of allOf, anyOf or oneOf: identical members are matched first,
+// then the remaining members are compared by position
+func (d *schemaDiff) diffComposition(pointer, keyword string, a, b map[string]interface{}) {
+	membersA, _ := a[keyword].([]interface{})
+	membersB, _ := b[keyword].([]interface{})
+
+	matched := make(map[int]bool, len(membersB))
+	var unmatchedA []interface{}
+	for _, member := range membersA {
+		found := false
+		for j, candidate := range membersB {
+			if !matched[j] && reflect.DeepEqual(member, candidate) {
+				matched[j] = true
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			unmatchedA = append(unmatchedA, member)
+		}
+	}
+
+	for j, member := range membersB {
+		if matched[j] {
+			continue
+		}
+
+		location := pointer + "/" + keyword + "/" + strconv.Itoa(j)
+		if len(unmatchedA) > 0 {
+			d.diff(location, asObject(unmatchedA[0]), asObject(member))
+			unmatchedA = unmatchedA[1:]
+
+			continue
+		}
+
+		d.report(location, ChangeCompositionMemberAdded, keyword, nil, member, "%s member %d added", keyword, j)
+	}
+
+	for _, member := range unmatchedA {
+		d.report(pointer+"/"+keyword, ChangeCompositionMemberRemoved, keyword, member, nil, "%s member removed", keyword)
+	}
+}
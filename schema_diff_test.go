@@ -0,0 +1,178 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	t.Parallel()
+
+	type change struct {
+		Pointer string
+		Kind    SchemaChangeKind
+		Keyword string
+	}
+
+	for _, toPin := range []struct {
+		Title    string
+		A, B     string
+		Opts     SchemaDiffOpts
+		Expected []change
+	}{
+		{
+			Title: "same schemas",
+			A:     `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+			B:     `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+		},
+		{
+			Title: "annotations are ignored",
+			A:     `{"type": "string", "description": "a name"}`,
+			B:     `{"type": "string", "description": "the name"}`,
+		},
+		{
+			Title: "annotations are reported",
+			A:     `{"type": "string", "description": "a name"}`,
+			B:     `{"type": "string", "description": "the name"}`,
+			Opts:  SchemaDiffOpts{IncludeAnnotations: true},
+			Expected: []change{
+				{Pointer: "#", Kind: ChangeAnnotationChanged, Keyword: "description"},
+			},
+		},
+		{
+			Title: "properties added, removed and retyped",
+			A:     `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}}`,
+			B:     `{"type": "object", "properties": {"id": {"type": "string", "format": "uuid"}, "tag": {"type": "string"}}}`,
+			Expected: []change{
+				{Pointer: "#/properties/id", Kind: ChangeTypeChanged, Keyword: "type"},
+				{Pointer: "#/properties/id", Kind: ChangeFormatChanged, Keyword: "format"},
+				{Pointer: "#/properties/name", Kind: ChangePropertyRemoved, Keyword: "properties"},
+				{Pointer: "#/properties/tag", Kind: ChangePropertyAdded, Keyword: "properties"},
+			},
+		},
+		{
+			Title: "required properties",
+			A:     `{"type": "object", "required": ["id", "name"]}`,
+			B:     `{"type": "object", "required": ["id", "tag"]}`,
+			Expected: []change{
+				{Pointer: "#", Kind: ChangeRequiredRemoved, Keyword: "required"},
+				{Pointer: "#", Kind: ChangeRequiredAdded, Keyword: "required"},
+			},
+		},
+		{
+			Title: "constraints",
+			A:     `{"type": "string", "maxLength": 10, "minLength": 2, "pattern": "^a"}`,
+			B:     `{"type": "string", "maxLength": 5, "minLength": 1, "x-nullable": true}`,
+			Expected: []change{
+				{Pointer: "#", Kind: ChangeConstraintTightened, Keyword: "maxLength"},
+				{Pointer: "#", Kind: ChangeConstraintRelaxed, Keyword: "minLength"},
+				{Pointer: "#", Kind: ChangeConstraintRelaxed, Keyword: "pattern"},
+				{Pointer: "#", Kind: ChangeConstraintRelaxed, Keyword: "x-nullable"},
+			},
+		},
+		{
+			Title: "numeric constraints",
+			A:     `{"type": "number", "maximum": 10, "multipleOf": 2}`,
+			B:     `{"type": "number", "maximum": 10, "exclusiveMaximum": true, "multipleOf": 1, "minimum": 0}`,
+			Expected: []change{
+				{Pointer: "#", Kind: ChangeConstraintTightened, Keyword: "exclusiveMaximum"},
+				{Pointer: "#", Kind: ChangeConstraintTightened, Keyword: "minimum"},
+				{Pointer: "#", Kind: ChangeConstraintRelaxed, Keyword: "multipleOf"},
+			},
+		},
+		{
+			Title: "enum values",
+			A:     `{"type": "string", "enum": ["a", "b"]}`,
+			B:     `{"type": "string", "enum": ["b", "c"]}`,
+			Expected: []change{
+				{Pointer: "#", Kind: ChangeEnumValueRemoved, Keyword: "enum"},
+				{Pointer: "#", Kind: ChangeEnumValueAdded, Keyword: "enum"},
+			},
+		},
+		{
+			Title: "nested items",
+			A:     `{"type": "array", "items": {"type": "object", "properties": {"id": {"type": "integer", "enum": [1]}}}}`,
+			B:     `{"type": "array", "items": {"type": "object", "properties": {"id": {"type": "integer"}}}}`,
+			Expected: []change{
+				{Pointer: "#/items/properties/id", Kind: ChangeConstraintRelaxed, Keyword: "enum"},
+			},
+		},
+		{
+			Title: "additional properties",
+			A:     `{"type": "object", "additionalProperties": {"type": "string"}}`,
+			B:     `{"type": "object", "additionalProperties": false}`,
+			Expected: []change{
+				{Pointer: "#", Kind: ChangeConstraintTightened, Keyword: "additionalProperties"},
+			},
+		},
+		{
+			Title: "composition",
+			A:     `{"allOf": [{"$ref": "#/definitions/base"}, {"type": "object", "required": ["id"]}]}`,
+			B:     `{"allOf": [{"$ref": "#/definitions/base"}, {"type": "object"}, {"$ref": "#/definitions/audit"}]}`,
+			Expected: []change{
+				{Pointer: "#/allOf/1", Kind: ChangeRequiredRemoved, Keyword: "required"},
+				{Pointer: "#/allOf/2", Kind: ChangeCompositionMemberAdded, Keyword: "allOf"},
+			},
+		},
+		{
+			Title: "composition member removed",
+			A:     `{"oneOf": [{"type": "string"}, {"type": "integer"}]}`,
+			B:     `{"oneOf": [{"type": "string"}]}`,
+			Expected: []change{
+				{Pointer: "#/oneOf", Kind: ChangeCompositionMemberRemoved, Keyword: "oneOf"},
+			},
+		},
+		{
+			Title: "$ref",
+			A:     `{"$ref": "#/definitions/pet"}`,
+			B:     `{"$ref": "#/definitions/animal"}`,
+			Expected: []change{
+				{Pointer: "#", Kind: ChangeRefChanged, Keyword: "$ref"},
+			},
+		},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.Title, func(t *testing.T) {
+			t.Parallel()
+
+			changes := DiffSchemas(schemaFromJSON(t, fixture.A), schemaFromJSON(t, fixture.B), fixture.Opts)
+
+			actual := make([]change, 0, len(changes))
+			for _, c := range changes {
+				assert.NotEmpty(t, c.Message)
+				actual = append(actual, change{Pointer: c.Pointer, Kind: c.Kind, Keyword: c.Keyword})
+			}
+
+			if len(fixture.Expected) == 0 {
+				assert.Empty(t, actual)
+			} else {
+				assert.Equal(t, fixture.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestDiffSchemas_Values(t *testing.T) {
+	t.Parallel()
+
+	changes := DiffSchemas(
+		schemaFromJSON(t, `{"type": "object", "required": ["id"], "properties": {"id": {"type": "string", "maxLength": 10}}}`),
+		schemaFromJSON(t, `{"type": "object", "properties": {"id": {"type": "string", "maxLength": 5}}}`),
+		SchemaDiffOpts{},
+	)
+
+	require.Len(t, changes, 2)
+	assert.Equal(t, SchemaChange{
+		Pointer: "#", Kind: ChangeRequiredRemoved, Keyword: "required", Before: "id",
+		Message: `property "id" no longer required`,
+	}, changes[0])
+	assert.Equal(t, SchemaChange{
+		Pointer: "#/properties/id", Kind: ChangeConstraintTightened, Keyword: "maxLength", Before: 10.0, After: 5.0,
+		Message: "maxLength changed from 10 to 5",
+	}, changes[1])
+
+	assert.Len(t, DiffSchemas(nil, schemaFromJSON(t, `{"type": "string"}`), SchemaDiffOpts{}), 1)
+}
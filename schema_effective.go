@@ -0,0 +1,156 @@
+package analysis
+
+import (
+	slashpath "path"
+	"strings"
+
+	"github.com/go-openapi/analysis/internal/flatten/normalize"
+	"github.com/go-openapi/spec"
+)
+
+// EffectiveSchema computes the schema a validator would actually enforce: $ref's are inlined and the members
+// of allOf are merged into their parent, recursively.
+//
+// The root is the document $ref's are resolved against (e.g. a *spec.Swagger), and the basePath is its location,
+// used to resolve relative remote $ref's. Circular $ref's are retained. Retained $ref's found in remote documents
+// are rebased, so they remain valid from the root document.
+//
+// A $ref is inlined once per call: once found circular, a $ref is retained wherever it occurs.
+//
+// allOf members are merged like with IntersectSchemas: keywords which cannot be merged in place (e.g. two
+// different patterns) remain in a residual allOf. Conflicting keywords, which no value may satisfy, are reported
+// with the findings of IntersectSchemas. Findings point into the effective schema (e.g. "#/properties/name").
+//
+// The input schema is not modified.
+func EffectiveSchema(schema *spec.Schema, root interface{}, basePath string) (*spec.Schema, []Finding, error) {
+	if schema == nil {
+		return nil, nil, nil
+	}
+
+	e := &effectiveSchemas{
+		root:      root,
+		base:      absoluteBasePath(basePath),
+		circulars: make(map[string]bool),
+		refs:      make(map[string]effectiveRef),
+	}
+	effective, err := e.effective("#", jsonAsSchema(schemaAsJSON(schema)), e.base, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	findings := make([]Finding, 0, len(e.findings))
+	for _, finding := range e.findings {
+		if !containsFinding(findings, finding) { // merging several allOf members may report the same conflict again
+			findings = append(findings, finding)
+		}
+	}
+	sortFindings(findings)
+
+	return effective, findings, nil
+}
+
+type effectiveSchemas struct {
+	root      interface{}
+	base      string          // the absolute location of the root document
+	circulars map[string]bool // the rebased $ref's found circular, which are retained
+	refs      map[string]effectiveRef
+	findings  []Finding
+}
+
+// effectiveRef is the effective schema of an inlined $ref, memoized by rebased $ref.
+type effectiveRef struct {
+	schema   *spec.Schema
+	findings []Finding // pointers are relative to the location of the $ref
+}
+
+// effective computes the effective schema of a copy of a schema, which may be altered.
+//
+// The base is the location of the document the schema comes from, and the stack holds the $ref's being inlined.
+func (e *effectiveSchemas) effective(pointer string, schema *spec.Schema, base string, stack []string) (*spec.Schema, error) {
+	if ref := schema.Ref.String(); ref != "" {
+		key := normalize.RebaseRef(base, ref)
+		absolute := schema.Ref
+		if base != e.base {
+			absolute = spec.MustCreateRef(key)
+		}
+
+		if e.circulars[key] || containsString(stack, key) {
+			e.circulars[key] = true
+
+			return &spec.Schema{SchemaProps: spec.SchemaProps{Ref: absolute}}, nil
+		}
+
+		if cached, ok := e.refs[key]; ok {
+			for _, finding := range cached.findings {
+				finding.Pointer = pointer + finding.Pointer
+				e.findings = append(e.findings, finding)
+			}
+
+			return jsonAsSchema(schemaAsJSON(cached.schema)), nil
+		}
+
+		resolved, err := spec.ResolveRefWithBase(e.root, &absolute, &spec.ExpandOptions{RelativeBase: e.base})
+		if err != nil {
+			return nil, &RefError{Pointer: pointer, Ref: ref, Cause: err}
+		}
+
+		document := base
+		if !absolute.HasFragmentOnly {
+			document, _, _ = strings.Cut(key, "#")
+		}
+
+		first := len(e.findings)
+		effective, err := e.effective(pointer, jsonAsSchema(schemaAsJSON(resolved)), document, append(stack, key))
+		if err != nil {
+			return nil, err
+		}
+
+		cached := effectiveRef{schema: jsonAsSchema(schemaAsJSON(effective))}
+		for _, finding := range e.findings[first:] {
+			finding.Pointer = strings.TrimPrefix(finding.Pointer, pointer)
+			cached.findings = append(cached.findings, finding)
+		}
+		e.refs[key] = cached
+
+		return effective, nil
+	}
+
+	var err error
+	forEachSubSchema(schema, func(suffix string, child *spec.Schema) {
+		if err != nil {
+			return
+		}
+
+		var effective *spec.Schema
+		effective, err = e.effective(slashpath.Join(pointer, suffix), child, base, stack)
+		if err == nil {
+			*child = *effective
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(schema.AllOf) == 0 {
+		return schema, nil
+	}
+
+	members := schema.AllOf
+	schema.AllOf = nil
+	merged := schemaAsJSON(schema)
+	for i := range members {
+		merged, _ = intersectJSON(pointer, merged, schemaAsJSON(&members[i]), &e.findings)
+	}
+
+	return jsonAsSchema(merged), nil
+}
+
+func containsFinding(findings []Finding, finding Finding) bool {
+	for _, other := range findings {
+		if other.Pointer == finding.Pointer && other.Code == finding.Code && other.Message == finding.Message {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,177 @@
+package analysis
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveSchema(t *testing.T) {
+	t.Parallel()
+
+	root := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{
+		"named": *schemaFromJSON(t, `{
+			"type": "object",
+			"required": ["name"],
+			"properties": {"name": {"type": "string", "maxLength": 20}}
+		}`),
+		"identified": *schemaFromJSON(t, `{
+			"allOf": [{"$ref": "#/definitions/named"}],
+			"required": ["id"],
+			"properties": {"id": {"type": "integer", "minimum": 1}}
+		}`),
+		"short": *schemaFromJSON(t, `{"allOf": [{"type": "string", "minLength": 5}, {"maxLength": 3}]}`),
+		"node":  *schemaFromJSON(t, `{"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/definitions/node"}}}}`),
+	}}}
+
+	for _, toPin := range []struct {
+		Title    string
+		Schema   string
+		Expected string
+		Codes    []string
+	}{
+		{
+			Title:    "with plain schema",
+			Schema:   `{"type": "string", "maxLength": 5}`,
+			Expected: `{"type": "string", "maxLength": 5}`,
+		},
+		{
+			Title:  "with nested allOf and $ref",
+			Schema: `{"allOf": [{"$ref": "#/definitions/identified"}, {"properties": {"name": {"minLength": 1}}}], "description": "a pet"}`,
+			Expected: `{
+				"type": "object",
+				"description": "a pet",
+				"required": ["id", "name"],
+				"properties": {
+					"id": {"type": "integer", "minimum": 1},
+					"name": {"type": "string", "minLength": 1, "maxLength": 20}
+				}
+			}`,
+		},
+		{
+			Title:    "with $ref in properties",
+			Schema:   `{"type": "array", "items": {"$ref": "#/definitions/named"}}`,
+			Expected: `{"type": "array", "items": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string", "maxLength": 20}}}}`,
+		},
+		{
+			Title:  "with circular $ref",
+			Schema: `{"$ref": "#/definitions/node"}`,
+			Expected: `{
+				"type": "object",
+				"properties": {"children": {"type": "array", "items": {"$ref": "#/definitions/node"}}}
+			}`,
+		},
+		{
+			Title:    "with conflicting keywords",
+			Schema:   `{"allOf": [{"type": "string", "minLength": 5}, {"maxLength": 3}, {"type": "string"}]}`,
+			Expected: `{"type": "string", "minLength": 5, "maxLength": 3}`,
+			Codes:    []string{CodeUnsatisfiableLength},
+		},
+		{
+			Title:  "with conflicting keywords in a $ref used twice",
+			Schema: `{"properties": {"a": {"$ref": "#/definitions/short"}, "b": {"$ref": "#/definitions/short"}}}`,
+			Expected: `{"properties": {
+				"a": {"type": "string", "minLength": 5, "maxLength": 3},
+				"b": {"type": "string", "minLength": 5, "maxLength": 3}
+			}}`,
+			Codes: []string{CodeUnsatisfiableLength, CodeUnsatisfiableLength},
+		},
+		{
+			Title:    "with disjoint types",
+			Schema:   `{"allOf": [{"$ref": "#/definitions/named"}, {"type": "string"}]}`,
+			Expected: `{"not": {}}`,
+			Codes:    []string{CodeEmptyIntersection},
+		},
+		{
+			Title:    "with residual allOf",
+			Schema:   `{"allOf": [{"type": "string", "pattern": "^a"}, {"pattern": "b$"}]}`,
+			Expected: `{"type": "string", "pattern": "^a", "allOf": [{"pattern": "b$"}]}`,
+		},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.Title, func(t *testing.T) {
+			t.Parallel()
+
+			schema := schemaFromJSON(t, fixture.Schema)
+			effective, findings, err := EffectiveSchema(schema, root, "")
+			require.NoError(t, err)
+
+			assert.JSONEq(t, fixture.Expected, schemaAsJSONString(t, effective))
+			assert.JSONEq(t, fixture.Schema, schemaAsJSONString(t, schema))
+
+			codes := findingCodes(findings)
+			if len(fixture.Codes) == 0 {
+				assert.Empty(t, codes)
+			} else {
+				assert.Equal(t, fixture.Codes, codes)
+			}
+		})
+	}
+}
+
+func TestEffectiveSchema_Remote(t *testing.T) {
+	t.Parallel()
+
+	bp := filepath.Join("fixtures", "expand", "spec.yml")
+	sp := antest.LoadOrFail(t, bp)
+
+	t.Run("with remote $ref", func(t *testing.T) {
+		t.Parallel()
+
+		effective, findings, err := EffectiveSchema(sp.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses[201].Schema, sp, bp)
+		require.NoError(t, err)
+		assert.Empty(t, findings)
+
+		assert.JSONEq(t, `{
+			"type": "object",
+			"properties": {"id": {"type": "integer"}, "tag": {"type": "string"}}
+		}`, schemaAsJSONString(t, effective))
+	})
+
+	t.Run("with circular $ref", func(t *testing.T) {
+		t.Parallel()
+
+		pet := sp.Definitions["pet"]
+		effective, _, err := EffectiveSchema(&pet, sp, bp)
+		require.NoError(t, err)
+
+		// the definition itself is not a $ref: the cycle is detected when reaching the owner again
+		nested := effective.Properties["owner"].Properties["pets"].Items.Schema
+		assert.Empty(t, nested.Ref.String())
+		assert.Equal(t, "#/definitions/owner", schemaRef(nested.Properties["owner"]))
+	})
+
+	t.Run("with unresolved $ref", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := EffectiveSchema(schemaFromJSON(t, `{"properties": {"a": {"$ref": "#/definitions/nowhere"}}}`), sp, bp)
+		require.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), "#/properties/a"), err.Error())
+	})
+
+	t.Run("with many remote $ref", func(t *testing.T) {
+		t.Parallel()
+
+		abp := filepath.Join("fixtures", "azure", "networkInterface.json")
+		asp := antest.LoadOrFail(t, abp)
+		format := asp.Definitions["NetworkInterfacePropertiesFormat"]
+
+		effective, _, err := EffectiveSchema(&format, asp, abp)
+		require.NoError(t, err)
+
+		// circular $ref's are retained wherever they occur, so shared definitions are not inlined over and over
+		// (inlining them again after each cycle yields more than 1MB)
+		assert.Less(t, len(schemaAsJSONString(t, effective)), 128*1024)
+	})
+
+	effective, findings, err := EffectiveSchema(nil, sp, bp)
+	require.NoError(t, err)
+	assert.Nil(t, effective)
+	assert.Empty(t, findings)
+}
@@ -0,0 +1,117 @@
+package analysis
+
+// GoTypeKind classifies the go type a schema maps to
+type GoTypeKind string
+
+// Kinds of go types
+const (
+	GoTypeBuiltin   GoTypeKind = "builtin"   // a builtin primitive, e.g. string, int64, bool, []byte
+	GoTypeTime      GoTypeKind = "time"      // time.Time
+	GoTypeMap       GoTypeKind = "map"       // map[string]T
+	GoTypeSlice     GoTypeKind = "slice"     // []T
+	GoTypeStruct    GoTypeKind = "struct"    // a struct with fields
+	GoTypeInterface GoTypeKind = "interface" // interface{}
+)
+
+// GoTypeHint is the canonical go type a schema maps to
+type GoTypeHint struct {
+	Kind GoTypeKind
+
+	// Type is the go type (e.g. "int32", "time.Time", "[]string", "map[string]interface{}").
+	//
+	// A struct is named after the definition it refers to. It is empty for an inline struct,
+	// and for slices and maps of inline structs.
+	Type string
+
+	// Format is the format of the schema (e.g. "uuid"), which a generator may map to a more specific type
+	Format string
+
+	// Definition is the name of the definition a schema refers to with a $ref (e.g. "pet")
+	Definition string
+
+	// Elem is the hint for the elements of a slice, or the values of a map
+	Elem *GoTypeHint
+}
+
+// builtin go types for some formats
+var (
+	goStringFormats = map[string]string{
+		"byte":   "[]byte",
+		"binary": "[]byte",
+	}
+
+	goNumberFormats = map[string]string{
+		"float":  "float32",
+		"double": "float64",
+	}
+)
+
+// inferGoTypeHint classifies the schema as a go type. The hints for the elements of slices and maps
+// are set when analyzing items and additional properties.
+func (a *AnalyzedSchema) inferGoTypeHint() {
+	hint := &a.GoTypeHint
+	hint.Format = a.schema.Format
+	tpe := a.primaryType()
+
+	switch {
+	case a.hasRef:
+		// inherited from the resolved schema
+	case tpe == "string" && a.schema.Format == "date-time":
+		hint.Kind, hint.Type = GoTypeTime, "time.Time"
+	case tpe == "string":
+		hint.Kind, hint.Type = GoTypeBuiltin, "string"
+		if goType, ok := goStringFormats[a.schema.Format]; ok {
+			hint.Type = goType
+		}
+	case tpe == "integer":
+		hint.Kind, hint.Type = GoTypeBuiltin, "int64"
+		if _, ok := integerFormatRanges[a.schema.Format]; ok {
+			hint.Type = a.schema.Format
+		}
+	case tpe == "number":
+		hint.Kind, hint.Type = GoTypeBuiltin, "float64"
+		if goType, ok := goNumberFormats[a.schema.Format]; ok {
+			hint.Type = goType
+		}
+	case tpe == "boolean":
+		hint.Kind, hint.Type = GoTypeBuiltin, "bool"
+	case tpe == "file":
+		hint.Kind, hint.Type = GoTypeBuiltin, "io.ReadCloser"
+	case a.IsArray || a.IsTuple || a.IsTupleWithExtra:
+		hint.Kind = GoTypeSlice
+		if hint.Elem == nil {
+			hint.Elem = &GoTypeHint{Kind: GoTypeInterface, Type: "interface{}"}
+		}
+		hint.Type = elemGoType("[]", hint.Elem)
+	case a.IsMap:
+		hint.Kind = GoTypeMap
+		if hint.Elem == nil {
+			hint.Elem = &GoTypeHint{Kind: GoTypeInterface, Type: "interface{}"}
+		}
+		hint.Type = elemGoType("map[string]", hint.Elem)
+	case a.hasProps || a.hasAllOf || a.IsExtendedObject:
+		hint.Kind = GoTypeStruct
+	default:
+		hint.Kind, hint.Type = GoTypeInterface, "interface{}"
+	}
+}
+
+// primaryType yields the type of the schema, ignoring "null"
+func (a *AnalyzedSchema) primaryType() string {
+	for _, tpe := range a.schema.Type {
+		if tpe != "null" {
+			return tpe
+		}
+	}
+
+	return ""
+}
+
+// elemGoType composes the go type of a slice or a map. It is empty when the go type of the elements is unknown.
+func elemGoType(prefix string, elem *GoTypeHint) string {
+	if elem.Type == "" {
+		return ""
+	}
+
+	return prefix + elem.Type
+}
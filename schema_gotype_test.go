@@ -0,0 +1,111 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaGoTypeHint(t *testing.T) {
+	t.Parallel()
+
+	root := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{
+		"pet":  *spec.MapProperty(nil).WithProperties(map[string]spec.Schema{"name": *spec.StringProperty()}),
+		"code": *spec.StringProperty().WithEnum("a", "b"),
+	}}}
+	interfaceHint := &GoTypeHint{Kind: GoTypeInterface, Type: "interface{}"}
+
+	for _, toPin := range []struct {
+		Name     string
+		Schema   string
+		Expected GoTypeHint
+	}{
+		{Name: "string", Schema: `{"type": "string"}`, Expected: GoTypeHint{Kind: GoTypeBuiltin, Type: "string"}},
+		{Name: "uuid", Schema: `{"type": "string", "format": "uuid"}`, Expected: GoTypeHint{Kind: GoTypeBuiltin, Type: "string", Format: "uuid"}},
+		{Name: "bytes", Schema: `{"type": "string", "format": "byte"}`, Expected: GoTypeHint{Kind: GoTypeBuiltin, Type: "[]byte", Format: "byte"}},
+		{Name: "date-time", Schema: `{"type": "string", "format": "date-time"}`, Expected: GoTypeHint{Kind: GoTypeTime, Type: "time.Time", Format: "date-time"}},
+		{Name: "integer", Schema: `{"type": "integer"}`, Expected: GoTypeHint{Kind: GoTypeBuiltin, Type: "int64"}},
+		{Name: "int32", Schema: `{"type": "integer", "format": "int32"}`, Expected: GoTypeHint{Kind: GoTypeBuiltin, Type: "int32", Format: "int32"}},
+		{Name: "float", Schema: `{"type": "number", "format": "float"}`, Expected: GoTypeHint{Kind: GoTypeBuiltin, Type: "float32", Format: "float"}},
+		{Name: "number", Schema: `{"type": "number"}`, Expected: GoTypeHint{Kind: GoTypeBuiltin, Type: "float64"}},
+		{Name: "boolean", Schema: `{"type": "boolean"}`, Expected: GoTypeHint{Kind: GoTypeBuiltin, Type: "bool"}},
+		{Name: "nullable", Schema: `{"type": ["null", "boolean"]}`, Expected: GoTypeHint{Kind: GoTypeBuiltin, Type: "bool"}},
+		{Name: "file", Schema: `{"type": "file"}`, Expected: GoTypeHint{Kind: GoTypeBuiltin, Type: "io.ReadCloser"}},
+		{Name: "any", Schema: `{}`, Expected: *interfaceHint},
+		{Name: "empty object", Schema: `{"type": "object"}`, Expected: *interfaceHint},
+		{
+			Name:     "struct",
+			Schema:   `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+			Expected: GoTypeHint{Kind: GoTypeStruct},
+		},
+		{
+			Name:     "ref to struct",
+			Schema:   `{"$ref": "#/definitions/pet"}`,
+			Expected: GoTypeHint{Kind: GoTypeStruct, Type: "pet", Definition: "pet"},
+		},
+		{
+			Name:     "ref to enum",
+			Schema:   `{"$ref": "#/definitions/code"}`,
+			Expected: GoTypeHint{Kind: GoTypeBuiltin, Type: "string", Definition: "code"},
+		},
+		{
+			Name:   "slice",
+			Schema: `{"type": "array", "items": {"type": "string", "format": "date-time"}}`,
+			Expected: GoTypeHint{
+				Kind: GoTypeSlice, Type: "[]time.Time",
+				Elem: &GoTypeHint{Kind: GoTypeTime, Type: "time.Time", Format: "date-time"},
+			},
+		},
+		{
+			Name:   "slice of refs",
+			Schema: `{"type": "array", "items": {"$ref": "#/definitions/pet"}}`,
+			Expected: GoTypeHint{
+				Kind: GoTypeSlice, Type: "[]pet",
+				Elem: &GoTypeHint{Kind: GoTypeStruct, Type: "pet", Definition: "pet"},
+			},
+		},
+		{
+			Name:     "slice of inline structs",
+			Schema:   `{"type": "array", "items": {"type": "object", "properties": {"id": {"type": "integer"}}}}`,
+			Expected: GoTypeHint{Kind: GoTypeSlice, Elem: &GoTypeHint{Kind: GoTypeStruct}},
+		},
+		{
+			Name:     "tuple",
+			Schema:   `{"type": "array", "items": [{"type": "string"}, {"type": "integer"}]}`,
+			Expected: GoTypeHint{Kind: GoTypeSlice, Type: "[]interface{}", Elem: interfaceHint},
+		},
+		{
+			Name:   "map of slices",
+			Schema: `{"type": "object", "additionalProperties": {"type": "array", "items": {"type": "integer", "format": "int32"}}}`,
+			Expected: GoTypeHint{
+				Kind: GoTypeMap, Type: "map[string][]int32",
+				Elem: &GoTypeHint{
+					Kind: GoTypeSlice, Type: "[]int32",
+					Elem: &GoTypeHint{Kind: GoTypeBuiltin, Type: "int32", Format: "int32"},
+				},
+			},
+		},
+		{
+			Name:     "free form map",
+			Schema:   `{"type": "object", "additionalProperties": true}`,
+			Expected: GoTypeHint{Kind: GoTypeMap, Type: "map[string]interface{}", Elem: interfaceHint},
+		},
+		{
+			Name:     "extended object",
+			Schema:   `{"type": "object", "properties": {"name": {"type": "string"}}, "additionalProperties": {"type": "string"}}`,
+			Expected: GoTypeHint{Kind: GoTypeStruct},
+		},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.Name, func(t *testing.T) {
+			t.Parallel()
+
+			analyzed, err := Schema(SchemaOpts{Schema: schemaFromJSON(t, fixture.Schema), Root: root})
+			require.NoError(t, err)
+			assert.Equal(t, fixture.Expected, analyzed.GoTypeHint)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package analysis
+
+import "math"
+
+// integerRange describes a range of integer values. Missing bounds are unbounded.
+type integerRange struct {
+	hasMin, hasMax bool
+	min, max       float64
+}
+
+// bounds for integer formats
+var integerFormatRanges = map[string]integerRange{
+	"int8":   {hasMin: true, hasMax: true, min: math.MinInt8, max: math.MaxInt8},
+	"int16":  {hasMin: true, hasMax: true, min: math.MinInt16, max: math.MaxInt16},
+	"int32":  {hasMin: true, hasMax: true, min: math.MinInt32, max: math.MaxInt32},
+	"int64":  {hasMin: true, hasMax: true, min: math.MinInt64, max: math.MaxInt64},
+	"uint8":  {hasMin: true, hasMax: true, min: 0, max: math.MaxUint8},
+	"uint16": {hasMin: true, hasMax: true, min: 0, max: math.MaxUint16},
+	"uint32": {hasMin: true, hasMax: true, min: 0, max: math.MaxUint32},
+	"uint64": {hasMin: true, hasMax: true, min: 0, max: math.MaxUint64},
+}
+
+// candidate go types, from the narrowest to the widest
+var (
+	signedIntegerTypes = []struct {
+		name     string
+		min, max float64
+	}{
+		{name: "int8", min: math.MinInt8, max: math.MaxInt8},
+		{name: "int16", min: math.MinInt16, max: math.MaxInt16},
+		{name: "int32", min: math.MinInt32, max: math.MaxInt32},
+		{name: "int64", min: math.MinInt64, max: math.MaxInt64},
+	}
+
+	unsignedIntegerTypes = []struct {
+		name string
+		max  float64
+	}{
+		{name: "uint8", max: math.MaxUint8},
+		{name: "uint16", max: math.MaxUint16},
+		{name: "uint32", max: math.MaxUint32},
+		{name: "uint64", max: math.MaxUint64},
+	}
+)
+
+// inferIntegerType determines the narrowest go integer type which holds all values
+// allowed by the format, minimum and maximum of an integer schema.
+//
+// Unsigned types are only recommended when the schema explicitly excludes negative values
+// (with a minimum or an unsigned format). Unbounded integers default to int64.
+func (a *AnalyzedSchema) inferIntegerType() {
+	if a.hasRef || !a.schema.Type.Contains("integer") {
+		return
+	}
+
+	rng := integerFormatRanges[a.schema.Format]
+
+	if a.schema.Minimum != nil {
+		lo := math.Ceil(*a.schema.Minimum)
+		if a.schema.ExclusiveMinimum && lo == *a.schema.Minimum {
+			lo++
+		}
+
+		if !rng.hasMin || lo > rng.min {
+			rng.min = lo
+		}
+		rng.hasMin = true
+	}
+
+	if a.schema.Maximum != nil {
+		hi := math.Floor(*a.schema.Maximum)
+		if a.schema.ExclusiveMaximum && hi == *a.schema.Maximum {
+			hi--
+		}
+
+		if !rng.hasMax || hi < rng.max {
+			rng.max = hi
+		}
+		rng.hasMax = true
+	}
+
+	a.IntegerType, a.IsIntegerOverflow = rng.narrowestType()
+}
+
+// narrowestType yields the narrowest go type for this range, and tells if the range overflows int64 or uint64.
+//
+// NOTE: bounds are float64 values, so the largest int64 and uint64 values cannot be distinguished
+// from the next power of two.
+func (r integerRange) narrowestType() (string, bool) {
+	const (
+		twoPow63 = 1 << 63
+		twoPow64 = 1 << 64
+	)
+
+	if r.hasMin && r.min >= 0 {
+		if r.hasMax && r.max > twoPow64 {
+			return "uint64", true
+		}
+
+		if !r.hasMax {
+			return "uint64", false
+		}
+
+		for _, candidate := range unsignedIntegerTypes {
+			if r.max <= candidate.max {
+				return candidate.name, false
+			}
+		}
+	}
+
+	if (r.hasMin && r.min < -twoPow63) || (r.hasMax && r.max > twoPow63) {
+		return "int64", true
+	}
+
+	if !r.hasMin || !r.hasMax {
+		return "int64", false
+	}
+
+	for _, candidate := range signedIntegerTypes {
+		if r.min >= candidate.min && r.max <= candidate.max {
+			return candidate.name, false
+		}
+	}
+
+	return "int64", false
+}
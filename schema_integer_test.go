@@ -0,0 +1,73 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaAnalysis_IntegerType(t *testing.T) {
+	t.Parallel()
+
+	bounded := func(format string, lo, hi *float64) *spec.Schema {
+		sch := new(spec.Schema).Typed("integer", format)
+		sch.Minimum = lo
+		sch.Maximum = hi
+
+		return sch
+	}
+	exclusive := func(sch *spec.Schema) *spec.Schema {
+		sch.ExclusiveMinimum = true
+		sch.ExclusiveMaximum = true
+
+		return sch
+	}
+
+	for i, toPin := range []struct {
+		Schema   *spec.Schema
+		Expected string
+		Overflow bool
+	}{
+		{Schema: spec.Int64Property(), Expected: "int64"},
+		{Schema: spec.Int32Property(), Expected: "int32"},
+		{Schema: new(spec.Schema).Typed("integer", ""), Expected: "int64"},
+		{Schema: new(spec.Schema).Typed("integer", "uint32"), Expected: "uint32"},
+		{Schema: new(spec.Schema).Typed("integer", "uint64"), Expected: "uint64"},
+		{Schema: bounded("", swag.Float64(0), swag.Float64(200)), Expected: "uint8"},
+		{Schema: bounded("", swag.Float64(0), swag.Float64(256)), Expected: "uint16"},
+		{Schema: bounded("", swag.Float64(0), nil), Expected: "uint64"},
+		{Schema: bounded("", nil, swag.Float64(10)), Expected: "int64"},
+		{Schema: bounded("", swag.Float64(-1), swag.Float64(127)), Expected: "int8"},
+		{Schema: bounded("", swag.Float64(-129), swag.Float64(127)), Expected: "int16"},
+		{Schema: exclusive(bounded("", swag.Float64(-129), swag.Float64(128))), Expected: "int8"},
+		{Schema: bounded("int64", swag.Float64(-100000), swag.Float64(100000)), Expected: "int32"},
+		{Schema: bounded("int8", swag.Float64(-100000), swag.Float64(100000)), Expected: "int8"},
+		{Schema: bounded("", swag.Float64(0), swag.Float64(1e20)), Expected: "uint64", Overflow: true},
+		{Schema: bounded("", swag.Float64(-1), swag.Float64(1e19)), Expected: "int64", Overflow: true},
+		{Schema: bounded("", swag.Float64(-1e19), nil), Expected: "int64", Overflow: true},
+		{Schema: spec.StringProperty(), Expected: ""},
+		{Schema: spec.Float64Property(), Expected: ""},
+	} {
+		fixture := toPin
+		sch, err := Schema(SchemaOpts{Schema: fixture.Schema})
+		require.NoError(t, err)
+
+		assert.Equalf(t, fixture.Expected, sch.IntegerType, "unexpected integer type for fixture %d", i)
+		assert.Equalf(t, fixture.Overflow, sch.IsIntegerOverflow, "unexpected overflow for fixture %d", i)
+	}
+
+	t.Run("should inherit from $ref", func(t *testing.T) {
+		root := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Definitions: spec.Definitions{
+				"small": *bounded("", swag.Float64(0), swag.Float64(10)),
+			},
+		}}
+
+		sch, err := Schema(SchemaOpts{Schema: spec.RefSchema("#/definitions/small"), Root: root})
+		require.NoError(t, err)
+		assert.Equal(t, "uint8", sch.IntegerType)
+	})
+}
@@ -0,0 +1,1128 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// Codes for findings about unions and intersections of schemas
+const (
+	CodeInexactUnion      = "inexact-union"
+	CodeEmptyIntersection = "empty-intersection"
+)
+
+// keywords which only apply to some JSON types.
+//
+// Bounds stand for both the bound and its exclusive flag (e.g. maximum and exclusiveMaximum).
+var keywordFamilies = []struct {
+	types    []string
+	keywords []string
+}{
+	{types: []string{"number", "integer"}, keywords: []string{"maximum", "minimum", "multipleOf"}},
+	{types: []string{"string"}, keywords: []string{"maxLength", "minLength", "pattern"}},
+	{types: []string{"array"}, keywords: []string{"items", "additionalItems", "maxItems", "minItems", "uniqueItems"}},
+	{types: []string{"object"}, keywords: []string{
+		"properties", "additionalProperties", "patternProperties", "required", "maxProperties", "minProperties",
+	}},
+}
+
+// keywords which apply to values of any type
+var genericKeywords = []string{"format", "enum", "allOf", "anyOf", "oneOf", "not"}
+
+// validationKeywords are all keywords which are not mere annotations
+var validationKeywords = func() map[string]bool {
+	keywords := map[string]bool{
+		"$ref": true, "type": true, "x-nullable": true, "exclusiveMaximum": true, "exclusiveMinimum": true,
+	}
+	for _, family := range keywordFamilies {
+		for _, keyword := range family.keywords {
+			keywords[keyword] = true
+		}
+	}
+	for _, keyword := range genericKeywords {
+		keywords[keyword] = true
+	}
+
+	return keywords
+}()
+
+// UnionSchemas computes a schema accepting all values valid under either of two schemas.
+//
+// The union is computed structurally, keyword by keyword, and widens constraints whenever both schemas
+// constrain values of the same type differently (e.g. maxLength 5 and maxLength 10 yield maxLength 10, two patterns
+// yield an alternation). The result always accepts all values valid under either schema, but it may accept more:
+// each location where the union is approximated is reported with a CodeInexactUnion finding.
+// The union is exact when the schemas differ in at most one keyword, or when they constrain disjoint types.
+//
+// When a schema is a $ref, the result is an anyOf of both schemas: $ref's are not resolved. Since Swagger 2.0
+// does not support anyOf, such unions are only valid as JSON Schema: resolve $ref's beforehand (e.g. with
+// EffectiveSchema) to obtain a schema usable in a spec.
+// Annotations (title, description, ...) are retained from the first schema, then from the second one.
+//
+// Findings point into the resulting schema (e.g. "#/properties/name").
+func UnionSchemas(a, b *spec.Schema) (*spec.Schema, []Finding) {
+	return combineSchemas(a, b, unionJSON)
+}
+
+// IntersectSchemas computes a schema accepting only the values valid under both schemas.
+//
+// The intersection is computed structurally, keyword by keyword, narrowing constraints (e.g. maxLength 5 and
+// maxLength 10 yield maxLength 5, required properties are combined). Constraints which cannot be combined in place
+// (e.g. two different patterns, or $ref's) are retained as a member of allOf, so the result is exact.
+//
+// The intersection of schemas which share no value is reported with findings:
+// CodeEmptyIntersection findings for disjoint types or enums, and the findings of UnsatisfiableSchemas for
+// incompatible bounds. Schemas with disjoint types or enums intersect as a schema forbidding all values: {"not": {}}.
+//
+// Findings point into the resulting schema (e.g. "#/properties/name").
+func IntersectSchemas(a, b *spec.Schema) (*spec.Schema, []Finding) {
+	return combineSchemas(a, b, intersectJSON)
+}
+
+func combineSchemas(a, b *spec.Schema, combine func(string, map[string]interface{}, map[string]interface{}, *[]Finding) (map[string]interface{}, bool)) (*spec.Schema, []Finding) {
+	var findings []Finding
+	result, _ := combine("#", schemaAsJSON(a), schemaAsJSON(b), &findings)
+
+	sortFindings(findings)
+
+	return jsonAsSchema(result), findings
+}
+
+// unionJSON computes the union of two schemas as generic JSON, and tells if it is exact
+func unionJSON(pointer string, a, b map[string]interface{}, findings *[]Finding) (map[string]interface{}, bool) {
+	if reflect.DeepEqual(a, b) {
+		return deepCopyJSON(a).(map[string]interface{}), true
+	}
+
+	if !hasValidations(a) || !hasValidations(b) {
+		return mergeAnnotations(a, b), true
+	}
+
+	if _, isRef := a["$ref"]; isRef {
+		return map[string]interface{}{"anyOf": []interface{}{deepCopyJSON(a), deepCopyJSON(b)}}, true
+	}
+	if _, isRef := b["$ref"]; isRef {
+		return map[string]interface{}{"anyOf": []interface{}{deepCopyJSON(a), deepCopyJSON(b)}}, true
+	}
+
+	result := mergeAnnotations(a, b)
+	var differing, approximated []string
+	looser := make(map[wideningSide]bool)
+
+	typesA, typesB := schemaTypes(a), schemaTypes(b)
+	if !sameStrings(typesA, typesB) {
+		differing = append(differing, "type")
+		looser[looserTypes(typesA, typesB)] = true
+	}
+	if typesA != nil && typesB != nil {
+		setSchemaTypes(result, unionStrings(typesA, typesB))
+	}
+
+	if isNullableJSON(a) != isNullableJSON(b) {
+		differing = append(differing, "x-nullable")
+		looser[looserIf(isNullableJSON(a))] = true
+	}
+	if isNullableJSON(a) || isNullableJSON(b) {
+		result["x-nullable"] = true
+	}
+
+	combine := func(keyword string) {
+		exact := true
+		if keyword == "maximum" || keyword == "minimum" {
+			unionBound(result, keyword, a, b)
+		} else {
+			var (
+				merged  interface{}
+				present bool
+			)
+			merged, present, exact = unionKeyword(pointer, keyword, a, b, findings)
+			if present {
+				result[keyword] = merged
+			}
+		}
+
+		if !exact {
+			approximated = append(approximated, keyword)
+		}
+
+		if sameKeyword(keyword, a, b) {
+			return
+		}
+
+		if side := looserKeyword(keyword, a, b); side != equivalent {
+			differing = append(differing, keyword)
+			looser[side] = true
+		}
+	}
+
+	for _, family := range keywordFamilies {
+		appliesToA, appliesToB := typesOverlap(typesA, family.types), typesOverlap(typesB, family.types)
+		for _, keyword := range family.keywords {
+			switch {
+			case !appliesToB: // this keyword constrains only values of the first schema
+				copyKeyword(result, a, keyword)
+			case !appliesToA:
+				copyKeyword(result, b, keyword)
+			default:
+				combine(keyword)
+			}
+		}
+	}
+
+	for _, keyword := range genericKeywords {
+		combine(keyword)
+	}
+
+	// the union of schemas differing on several keywords is exact only when one schema is looser on all of them
+	if len(approximated) > 0 || len(differing) > 1 && (len(looser) > 1 || looser[eitherSide]) {
+		keywords := append(approximated, differing...)
+		sort.Strings(keywords)
+		*findings = append(*findings, Finding{
+			Pointer: pointer,
+			Code:    CodeInexactUnion,
+			Message: fmt.Sprintf("the union is approximated and accepts more values than either schema (%s)",
+				strings.Join(uniqueStrings(keywords), ", ")),
+		})
+
+		return result, false
+	}
+
+	return result, true
+}
+
+// unionKeyword computes the union of the values of a keyword in two schemas.
+//
+// It tells if the keyword is present in the result, and if the union of the values is exact
+// assuming that the schemas differ on this keyword only.
+func unionKeyword(pointer, keyword string, a, b map[string]interface{}, findings *[]Finding) (interface{}, bool, bool) {
+	va, hasA := a[keyword]
+	vb, hasB := b[keyword]
+
+	if !hasA && !hasB {
+		return nil, false, true
+	}
+
+	if hasA && hasB && reflect.DeepEqual(va, vb) {
+		return deepCopyJSON(va), true, true
+	}
+
+	// one schema does not constrain this keyword at all.
+	// Properties of one schema are still constrained by the additionalProperties of the other one.
+	if (!hasA || !hasB) && keyword != "properties" {
+		return nil, false, true
+	}
+
+	switch keyword {
+	case "maxLength", "maxItems", "maxProperties":
+		return math.Max(asNumber(va), asNumber(vb)), true, true
+
+	case "minLength", "minItems", "minProperties":
+		return math.Min(asNumber(va), asNumber(vb)), true, true
+
+	case "multipleOf":
+		x, y := asNumber(va), asNumber(vb)
+		if isMultiple(x, y) {
+			return y, true, true
+		}
+		if isMultiple(y, x) {
+			return x, true, true
+		}
+
+		return nil, false, false
+
+	case "pattern":
+		return fmt.Sprintf("(?:%v)|(?:%v)", va, vb), true, true
+
+	case "enum":
+		values, _ := va.([]interface{})
+		others, _ := vb.([]interface{})
+
+		return unionValues(values, others), true, true
+
+	case "required":
+		x, y := asStrings(va), asStrings(vb)
+		common := intersectStrings(x, y)
+		exact := len(common) == len(x) || len(common) == len(y)
+		if len(common) == 0 {
+			return nil, false, exact
+		}
+
+		return toJSONList(common), true, exact
+
+	case "items":
+		itemsA, isSchemaA := va.(map[string]interface{})
+		itemsB, isSchemaB := vb.(map[string]interface{})
+		if !isSchemaA || !isSchemaB {
+			return nil, false, false // tuples
+		}
+
+		merged, exact := unionJSON(pointer+"/items", itemsA, itemsB, findings)
+
+		return merged, true, exact
+
+	case "additionalProperties":
+		return unionAdditionalProperties(pointer, va, vb, findings)
+
+	case "properties":
+		return unionProperties(pointer, a, b, findings)
+
+	default:
+		// no structural union for this keyword (e.g. format, patternProperties, allOf, ...)
+		return nil, false, false
+	}
+}
+
+// unionBound sets the loosest of two bounds (maximum or minimum), with its exclusive flag
+func unionBound(result map[string]interface{}, keyword string, a, b map[string]interface{}) {
+	exclusiveKeyword := exclusiveKeywordOf(keyword)
+	va, hasA := a[keyword]
+	vb, hasB := b[keyword]
+	if !hasA || !hasB {
+		return
+	}
+
+	x, y := asNumber(va), asNumber(vb)
+	exclusiveA, _ := a[exclusiveKeyword].(bool)
+	exclusiveB, _ := b[exclusiveKeyword].(bool)
+
+	looser, exclusive := x, exclusiveA
+	switch {
+	case x == y:
+		exclusive = exclusiveA && exclusiveB
+	case (keyword == "maximum") == (y > x):
+		looser, exclusive = y, exclusiveB
+	}
+
+	result[keyword] = looser
+	if exclusive {
+		result[exclusiveKeyword] = true
+	}
+}
+
+func unionAdditionalProperties(pointer string, va, vb interface{}, findings *[]Finding) (interface{}, bool, bool) {
+	schemaA, isSchemaA := va.(map[string]interface{})
+	schemaB, isSchemaB := vb.(map[string]interface{})
+
+	switch {
+	case va == true || vb == true:
+		return nil, false, true
+	case va == false:
+		return deepCopyJSON(vb), true, true
+	case vb == false:
+		return deepCopyJSON(va), true, true
+	case isSchemaA && isSchemaB:
+		merged, exact := unionJSON(pointer+"/additionalProperties", schemaA, schemaB, findings)
+
+		return merged, true, exact
+	default:
+		return nil, false, false
+	}
+}
+
+// unionProperties computes the union of the properties of two object schemas.
+//
+// The union is exact when at most one property differs, or when all differing properties are looser in the same schema.
+func unionProperties(pointer string, a, b map[string]interface{}, findings *[]Finding) (interface{}, bool, bool) {
+	propsA, _ := a["properties"].(map[string]interface{})
+	propsB, _ := b["properties"].(map[string]interface{})
+
+	result := make(map[string]interface{})
+	exact := true
+	differing := 0
+	looser := make(map[wideningSide]bool)
+
+	names := make(map[string]bool)
+	for name := range propsA {
+		names[name] = true
+	}
+	for name := range propsB {
+		names[name] = true
+	}
+
+	for _, name := range sortedKeys(names) {
+		propA, constrainedA := propertySchema(a, propsA, name)
+		propB, constrainedB := propertySchema(b, propsB, name)
+		if !constrainedA || !constrainedB {
+			differing++
+			looser[looserIf(!constrainedA)] = true
+
+			continue
+		}
+
+		if !reflect.DeepEqual(propA, propB) {
+			differing++
+			looser[eitherSide] = true
+		}
+
+		merged, mergedExactly := unionJSON(pointer+"/properties/"+jsonpointer.Escape(name), propA, propB, findings)
+		exact = exact && mergedExactly
+		result[name] = merged
+	}
+
+	exact = exact && (differing <= 1 || len(looser) == 1 && !looser[eitherSide])
+	if len(result) == 0 {
+		return nil, false, exact
+	}
+
+	return result, true, exact
+}
+
+// wideningSide tells which schema is the loosest for some keyword
+type wideningSide int
+
+const (
+	eitherSide wideningSide = iota // unknown, or none is looser than the other
+	sideA
+	sideB
+	equivalent // both schemas accept the same values
+)
+
+func looserIf(isA bool) wideningSide {
+	if isA {
+		return sideA
+	}
+
+	return sideB
+}
+
+func looserTypes(typesA, typesB []string) wideningSide {
+	switch {
+	case typesA == nil:
+		return sideA
+	case typesB == nil:
+		return sideB
+	}
+
+	common := intersectTypes(typesA, typesB)
+	switch {
+	case sameStrings(common, typesB):
+		return sideA
+	case sameStrings(common, typesA):
+		return sideB
+	default:
+		return eitherSide
+	}
+}
+
+// looserKeyword tells which schema is the loosest for a keyword on which both schemas differ
+func looserKeyword(keyword string, a, b map[string]interface{}) wideningSide {
+	va, hasA := a[keyword]
+	vb, hasB := b[keyword]
+
+	switch {
+	case !hasA && keyword != "properties":
+		return sideA
+	case !hasB && keyword != "properties":
+		return sideB
+	}
+
+	switch keyword {
+	case "maximum", "minimum":
+		x, y := asNumber(va), asNumber(vb)
+		if x == y {
+			return looserIf(b[exclusiveKeywordOf(keyword)] == true)
+		}
+
+		return looserIf((keyword == "maximum") == (x > y))
+	case "maxLength", "maxItems", "maxProperties":
+		return looserIf(asNumber(va) > asNumber(vb))
+	case "minLength", "minItems", "minProperties":
+		return looserIf(asNumber(va) < asNumber(vb))
+	case "multipleOf":
+		switch x, y := asNumber(va), asNumber(vb); {
+		case isMultiple(y, x):
+			return sideA
+		case isMultiple(x, y):
+			return sideB
+		}
+	case "required":
+		x, y := asStrings(va), asStrings(vb)
+		switch common := intersectStrings(x, y); {
+		case len(common) == len(x):
+			return sideA
+		case len(common) == len(y):
+			return sideB
+		}
+	case "enum":
+		values, _ := va.([]interface{})
+		others, _ := vb.([]interface{})
+		switch merged := unionValues(values, others); {
+		case len(merged) == len(values):
+			return sideA
+		case len(merged) == len(others):
+			return sideB
+		}
+	case "properties":
+		return looserProperties(a, b)
+	case "additionalProperties":
+		switch {
+		case va == true:
+			return sideA
+		case vb == true:
+			return sideB
+		case va == false:
+			return sideB
+		case vb == false:
+			return sideA
+		}
+	}
+
+	return eitherSide
+}
+
+// looserProperties tells which schema is the loosest for all properties on which both schemas differ
+func looserProperties(a, b map[string]interface{}) wideningSide {
+	propsA, _ := a["properties"].(map[string]interface{})
+	propsB, _ := b["properties"].(map[string]interface{})
+	looser := make(map[wideningSide]bool)
+
+	for _, props := range []map[string]interface{}{propsA, propsB} {
+		for name := range props {
+			propA, constrainedA := propertySchema(a, propsA, name)
+			propB, constrainedB := propertySchema(b, propsB, name)
+			switch {
+			case !constrainedA && !constrainedB:
+			case !constrainedA || !constrainedB:
+				looser[looserIf(!constrainedA)] = true
+			default:
+				looser[looserSchema(propA, propB)] = true
+			}
+		}
+	}
+
+	delete(looser, equivalent)
+	if len(looser) == 0 {
+		return equivalent
+	}
+
+	if len(looser) > 1 {
+		return eitherSide
+	}
+
+	for side := range looser {
+		return side
+	}
+
+	return eitherSide
+}
+
+// looserSchema tells which of two schemas accepts all the values of the other one, as far as unions can tell
+func looserSchema(a, b map[string]interface{}) wideningSide {
+	validationsA, validationsB := validationsOf(a), validationsOf(b)
+	if reflect.DeepEqual(validationsA, validationsB) {
+		return equivalent
+	}
+
+	var discarded []Finding
+	union, exact := unionJSON("", a, b, &discarded)
+	if !exact {
+		return eitherSide
+	}
+
+	switch validations := validationsOf(union); {
+	case reflect.DeepEqual(validations, validationsA):
+		return sideA
+	case reflect.DeepEqual(validations, validationsB):
+		return sideB
+	default:
+		return eitherSide
+	}
+}
+
+// propertySchema yields the schema constraining a property of an object schema,
+// and tells if the property is constrained at all
+func propertySchema(schema, properties map[string]interface{}, name string) (map[string]interface{}, bool) {
+	if prop, isDeclared := properties[name].(map[string]interface{}); isDeclared {
+		return prop, true
+	}
+
+	switch additional := schema["additionalProperties"].(type) {
+	case map[string]interface{}:
+		return additional, true
+	case bool:
+		if !additional {
+			return forbiddenJSON(), true
+		}
+	}
+
+	return nil, false
+}
+
+// intersectJSON computes the intersection of two schemas as generic JSON.
+//
+// Intersections are always exact.
+func intersectJSON(pointer string, a, b map[string]interface{}, findings *[]Finding) (map[string]interface{}, bool) {
+	if reflect.DeepEqual(a, b) {
+		return deepCopyJSON(a).(map[string]interface{}), true
+	}
+
+	if !hasValidations(a) || !hasValidations(b) {
+		result := mergeAnnotations(a, b)
+		for _, schema := range []map[string]interface{}{a, b} {
+			for keyword, value := range schema {
+				if validationKeywords[keyword] {
+					result[keyword] = deepCopyJSON(value)
+				}
+			}
+		}
+
+		return result, true
+	}
+
+	_, isRefA := a["$ref"]
+	_, isRefB := b["$ref"]
+	if isRefA || isRefB {
+		return map[string]interface{}{"allOf": []interface{}{deepCopyJSON(a), deepCopyJSON(b)}}, true
+	}
+
+	result := mergeAnnotations(a, b)
+	deferred := make(map[string]interface{}) // constraints of b which cannot be merged in place
+
+	typesA, typesB := schemaTypes(a), schemaTypes(b)
+	switch {
+	case typesA == nil && typesB != nil:
+		setSchemaTypes(result, typesB)
+	case typesB == nil && typesA != nil:
+		setSchemaTypes(result, typesA)
+	case typesA != nil:
+		common := intersectTypes(typesA, typesB)
+		if len(common) == 0 {
+			*findings = append(*findings, Finding{
+				Pointer: pointer,
+				Code:    CodeEmptyIntersection,
+				Message: fmt.Sprintf("types %s and %s have no common value",
+					strings.Join(typesA, ", "), strings.Join(typesB, ", ")),
+			})
+
+			return forbiddenJSON(), true
+		}
+		setSchemaTypes(result, common)
+	}
+
+	if isNullableJSON(a) && isNullableJSON(b) {
+		result["x-nullable"] = true
+	}
+
+	for _, keyword := range []string{"maximum", "minimum"} {
+		intersectBound(result, keyword, a, b)
+	}
+
+	for keyword, pick := range map[string]func(float64, float64) float64{
+		"maxLength": math.Min, "maxItems": math.Min, "maxProperties": math.Min,
+		"minLength": math.Max, "minItems": math.Max, "minProperties": math.Max,
+	} {
+		va, hasA := a[keyword]
+		vb, hasB := b[keyword]
+		switch {
+		case hasA && hasB:
+			result[keyword] = pick(asNumber(va), asNumber(vb))
+		case hasA:
+			result[keyword] = va
+		case hasB:
+			result[keyword] = vb
+		}
+	}
+
+	if va, vb := a["multipleOf"], b["multipleOf"]; va != nil && vb != nil {
+		x, y := asNumber(va), asNumber(vb)
+		switch {
+		case isMultiple(x, y):
+			result["multipleOf"] = x
+		case isMultiple(y, x):
+			result["multipleOf"] = y
+		default:
+			result["multipleOf"] = x
+			deferred["multipleOf"] = y
+		}
+	} else {
+		copyKeyword(result, a, "multipleOf")
+		copyKeyword(result, b, "multipleOf")
+	}
+
+	if a["uniqueItems"] == true || b["uniqueItems"] == true {
+		result["uniqueItems"] = true
+	}
+
+	// keywords retained from the first schema, or deferred to allOf if they differ
+	for _, keyword := range []string{"pattern", "format", "additionalItems", "anyOf", "oneOf", "not"} {
+		va, hasA := a[keyword]
+		vb, hasB := b[keyword]
+		switch {
+		case hasA:
+			result[keyword] = deepCopyJSON(va)
+			if hasB && !reflect.DeepEqual(va, vb) {
+				deferred[keyword] = deepCopyJSON(vb)
+			}
+		case hasB:
+			result[keyword] = deepCopyJSON(vb)
+		}
+	}
+
+	intersectItems(pointer, result, deferred, a, b, findings)
+	intersectObjects(pointer, result, deferred, a, b, findings)
+	if !intersectEnums(pointer, result, a, b, findings) {
+		return forbiddenJSON(), true
+	}
+
+	allOf, _ := deepCopyJSON(a["allOf"]).([]interface{})
+	if others, ok := b["allOf"].([]interface{}); ok {
+		allOf = append(allOf, deepCopyJSON(others).([]interface{})...)
+	}
+	if len(deferred) > 0 {
+		allOf = append(allOf, deferred)
+	}
+	if len(allOf) > 0 {
+		result["allOf"] = allOf
+	}
+
+	*findings = append(*findings, unsatisfiableConstraints(pointer, jsonAsSchema(result))...)
+
+	return result, true
+}
+
+func intersectBound(result map[string]interface{}, keyword string, a, b map[string]interface{}) {
+	exclusiveKeyword := exclusiveKeywordOf(keyword)
+	va, hasA := a[keyword]
+	vb, hasB := b[keyword]
+	exclusiveA, _ := a[exclusiveKeyword].(bool)
+	exclusiveB, _ := b[exclusiveKeyword].(bool)
+
+	var (
+		tighter   float64
+		exclusive bool
+	)
+
+	switch {
+	case !hasA && !hasB:
+		return
+	case !hasB:
+		tighter, exclusive = asNumber(va), exclusiveA
+	case !hasA:
+		tighter, exclusive = asNumber(vb), exclusiveB
+	default:
+		x, y := asNumber(va), asNumber(vb)
+		tighter, exclusive = x, exclusiveA
+		switch {
+		case x == y:
+			exclusive = exclusiveA || exclusiveB
+		case (keyword == "maximum") == (y < x):
+			tighter, exclusive = y, exclusiveB
+		}
+	}
+
+	result[keyword] = tighter
+	if exclusive {
+		result[exclusiveKeyword] = true
+	}
+}
+
+func intersectItems(pointer string, result, deferred, a, b map[string]interface{}, findings *[]Finding) {
+	va, hasA := a["items"]
+	vb, hasB := b["items"]
+
+	switch {
+	case hasA && hasB:
+		itemsA, isSchemaA := va.(map[string]interface{})
+		itemsB, isSchemaB := vb.(map[string]interface{})
+		if isSchemaA && isSchemaB {
+			result["items"], _ = intersectJSON(pointer+"/items", itemsA, itemsB, findings)
+
+			return
+		}
+
+		result["items"] = deepCopyJSON(va)
+		if !reflect.DeepEqual(va, vb) {
+			deferred["items"] = deepCopyJSON(vb)
+		}
+	case hasA:
+		result["items"] = deepCopyJSON(va)
+	case hasB:
+		result["items"] = deepCopyJSON(vb)
+	}
+}
+
+// intersectObjects combines the object keywords of two schemas.
+//
+// Properties are intersected by name. When patternProperties are involved, the object keywords of the second schema
+// are deferred to allOf.
+func intersectObjects(pointer string, result, deferred, a, b map[string]interface{}, findings *[]Finding) {
+	required := unionStrings(asStrings(a["required"]), asStrings(b["required"]))
+	if len(required) > 0 {
+		result["required"] = toJSONList(required)
+	}
+
+	_, patternsA := a["patternProperties"]
+	_, patternsB := b["patternProperties"]
+	if patternsA || patternsB {
+		for _, keyword := range []string{"properties", "additionalProperties", "patternProperties"} {
+			copyKeyword(result, a, keyword)
+			if _, hasB := b[keyword]; hasB && !reflect.DeepEqual(a[keyword], b[keyword]) {
+				deferred[keyword] = deepCopyJSON(b[keyword])
+			} else if _, hasA := a[keyword]; !hasA {
+				copyKeyword(result, b, keyword)
+			}
+		}
+
+		return
+	}
+
+	propsA, _ := a["properties"].(map[string]interface{})
+	propsB, _ := b["properties"].(map[string]interface{})
+	properties := make(map[string]interface{})
+
+	names := make(map[string]bool)
+	for name := range propsA {
+		names[name] = true
+	}
+	for name := range propsB {
+		names[name] = true
+	}
+
+	for _, name := range sortedKeys(names) {
+		propA, constrainedA := propertySchema(a, propsA, name)
+		propB, constrainedB := propertySchema(b, propsB, name)
+
+		switch {
+		case isForbidden(propA) || isForbidden(propB):
+			// the property is not allowed by one of the schemas: required properties are reported as unsatisfiable
+		case !constrainedB:
+			properties[name] = deepCopyJSON(propA)
+		case !constrainedA:
+			properties[name] = deepCopyJSON(propB)
+		default:
+			properties[name], _ = intersectJSON(pointer+"/properties/"+jsonpointer.Escape(name), propA, propB, findings)
+		}
+	}
+
+	if len(properties) > 0 {
+		result["properties"] = properties
+	}
+
+	additionalA, hasA := a["additionalProperties"]
+	additionalB, hasB := b["additionalProperties"]
+	schemaA, isSchemaA := additionalA.(map[string]interface{})
+	schemaB, isSchemaB := additionalB.(map[string]interface{})
+
+	switch {
+	case additionalA == false || additionalB == false:
+		result["additionalProperties"] = false
+	case isSchemaA && isSchemaB:
+		result["additionalProperties"], _ = intersectJSON(pointer+"/additionalProperties", schemaA, schemaB, findings)
+	case isSchemaA:
+		result["additionalProperties"] = deepCopyJSON(schemaA)
+	case isSchemaB:
+		result["additionalProperties"] = deepCopyJSON(schemaB)
+	case hasA || hasB:
+		result["additionalProperties"] = true
+	}
+}
+
+// intersectEnums retains the common values of enums. It tells false when enums have no common value.
+func intersectEnums(pointer string, result, a, b map[string]interface{}, findings *[]Finding) bool {
+	va, hasA := a["enum"].([]interface{})
+	vb, hasB := b["enum"].([]interface{})
+
+	switch {
+	case hasA && hasB:
+		var common []interface{}
+		for _, value := range va {
+			if containsValue(vb, value) {
+				common = append(common, deepCopyJSON(value))
+			}
+		}
+
+		if len(common) == 0 {
+			*findings = append(*findings, Finding{
+				Pointer: pointer,
+				Code:    CodeEmptyIntersection,
+				Message: "enums have no common value",
+			})
+
+			return false
+		}
+
+		result["enum"] = common
+	case hasA:
+		result["enum"] = deepCopyJSON(va)
+	case hasB:
+		result["enum"] = deepCopyJSON(vb)
+	}
+
+	return true
+}
+
+// forbiddenJSON yields a schema which no value is valid under
+func forbiddenJSON() map[string]interface{} {
+	return map[string]interface{}{"not": map[string]interface{}{}}
+}
+
+func isForbidden(schema map[string]interface{}) bool {
+	not, isNot := schema["not"].(map[string]interface{})
+
+	return isNot && len(not) == 0 && len(schema) == 1
+}
+
+// mergeAnnotations retains the keywords which are not validations, from the first schema then from the second one
+func mergeAnnotations(a, b map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, schema := range []map[string]interface{}{b, a} {
+		for keyword, value := range schema {
+			if !validationKeywords[keyword] {
+				result[keyword] = deepCopyJSON(value)
+			}
+		}
+	}
+
+	return result
+}
+
+// validationsOf retains the validation keywords of a schema
+func validationsOf(schema map[string]interface{}) map[string]interface{} {
+	validations := make(map[string]interface{}, len(schema))
+	for keyword, value := range schema {
+		if validationKeywords[keyword] {
+			validations[keyword] = value
+		}
+	}
+
+	return validations
+}
+
+func hasValidations(schema map[string]interface{}) bool {
+	for keyword := range schema {
+		if validationKeywords[keyword] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sameKeyword(keyword string, a, b map[string]interface{}) bool {
+	if keyword == "maximum" || keyword == "minimum" {
+		exclusiveKeyword := exclusiveKeywordOf(keyword)
+		if !reflect.DeepEqual(a[exclusiveKeyword], b[exclusiveKeyword]) {
+			return false
+		}
+	}
+
+	return reflect.DeepEqual(a[keyword], b[keyword])
+}
+
+func copyKeyword(result, schema map[string]interface{}, keyword string) {
+	value, ok := schema[keyword]
+	if !ok {
+		return
+	}
+
+	result[keyword] = deepCopyJSON(value)
+	if keyword == "maximum" || keyword == "minimum" {
+		exclusiveKeyword := exclusiveKeywordOf(keyword)
+		if exclusive, ok := schema[exclusiveKeyword]; ok {
+			result[exclusiveKeyword] = exclusive
+		}
+	}
+}
+
+// exclusiveKeywordOf yields the keyword flagging a bound as exclusive, e.g. exclusiveMaximum for maximum
+func exclusiveKeywordOf(keyword string) string {
+	return "exclusive" + strings.ToUpper(keyword[:1]) + keyword[1:]
+}
+
+// schemaTypes returns the sorted types of a schema, or nil when any type is allowed
+func schemaTypes(schema map[string]interface{}) []string {
+	switch tpe := schema["type"].(type) {
+	case string:
+		return []string{tpe}
+	case []interface{}:
+		if len(tpe) == 0 {
+			return nil
+		}
+
+		types := asStrings(tpe)
+		sort.Strings(types)
+
+		return types
+	default:
+		return nil
+	}
+}
+
+func setSchemaTypes(schema map[string]interface{}, types []string) {
+	if len(types) == 1 {
+		schema["type"] = types[0]
+
+		return
+	}
+
+	schema["type"] = toJSONList(types)
+}
+
+// typesOverlap tells if some values may have one of the types of a schema and one of the other types.
+// Nil types stand for any type.
+func typesOverlap(types, others []string) bool {
+	return types == nil || len(intersectTypes(types, others)) > 0
+}
+
+// intersectTypes computes the types common to two lists of types, knowing that integers are numbers
+func intersectTypes(types, others []string) []string {
+	var common []string
+	for _, tpe := range types {
+		for _, other := range others {
+			switch {
+			case tpe == other:
+				common = append(common, tpe)
+			case tpe == "integer" && other == "number", tpe == "number" && other == "integer":
+				common = append(common, "integer")
+			}
+		}
+	}
+
+	return uniqueStrings(common)
+}
+
+func isNullableJSON(schema map[string]interface{}) bool {
+	nullable, _ := schema["x-nullable"].(bool)
+
+	return nullable
+}
+
+// isMultiple tells if x is a multiple of y
+func isMultiple(x, y float64) bool {
+	if y == 0 {
+		return false
+	}
+
+	ratio := x / y
+
+	return ratio == math.Trunc(ratio)
+}
+
+func asNumber(value interface{}) float64 {
+	f, _ := asFloat(value)
+
+	return f
+}
+
+func asStrings(value interface{}) []string {
+	list, _ := value.([]interface{})
+	result := make([]string, 0, len(list))
+	for _, elem := range list {
+		if s, ok := elem.(string); ok {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+func toJSONList(values []string) []interface{} {
+	list := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		list = append(list, value)
+	}
+
+	return list
+}
+
+func sameStrings(a, b []string) bool {
+	return len(a) == len(b) && len(intersectStrings(a, b)) == len(a)
+}
+
+func unionStrings(a, b []string) []string {
+	return uniqueStrings(append(append([]string{}, a...), b...))
+}
+
+func intersectStrings(a, b []string) []string {
+	var common []string
+	for _, s := range a {
+		if containsString(b, s) {
+			common = append(common, s)
+		}
+	}
+
+	return uniqueStrings(common)
+}
+
+// uniqueStrings sorts and deduplicates strings
+func uniqueStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	sort.Strings(values)
+	result := values[:1]
+	for _, value := range values[1:] {
+		if value != result[len(result)-1] {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func unionValues(values, others []interface{}) []interface{} {
+	result := deepCopyJSON(values).([]interface{})
+	for _, value := range others {
+		if !containsValue(result, value) {
+			result = append(result, deepCopyJSON(value))
+		}
+	}
+
+	return result
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func schemaAsJSON(schema *spec.Schema) map[string]interface{} {
+	result := make(map[string]interface{})
+	if schema == nil {
+		return result
+	}
+
+	buf, err := json.Marshal(schema)
+	if err != nil {
+		return result
+	}
+
+	_ = json.Unmarshal(buf, &result)
+
+	return result
+}
+
+func jsonAsSchema(schema map[string]interface{}) *spec.Schema {
+	var result spec.Schema
+	buf, err := json.Marshal(schema)
+	if err != nil {
+		return &result
+	}
+
+	_ = json.Unmarshal(buf, &result)
+
+	return &result
+}
@@ -0,0 +1,330 @@
+package analysis
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnionSchemas(t *testing.T) {
+	t.Parallel()
+
+	for _, toPin := range []struct {
+		Title    string
+		A, B     string
+		Expected string
+		Inexact  []string // pointers to inexact locations
+	}{
+		{
+			Title:    "identical schemas",
+			A:        `{"type": "string", "maxLength": 5}`,
+			B:        `{"type": "string", "maxLength": 5}`,
+			Expected: `{"type": "string", "maxLength": 5}`,
+		},
+		{
+			Title:    "looser bound",
+			A:        `{"type": "integer", "minimum": 1, "maximum": 10, "exclusiveMaximum": true}`,
+			B:        `{"type": "integer", "minimum": 1, "maximum": 20}`,
+			Expected: `{"type": "integer", "minimum": 1, "maximum": 20}`,
+		},
+		{
+			Title:    "one schema is looser on all keywords",
+			A:        `{"type": "string", "minLength": 1, "maxLength": 5}`,
+			B:        `{"type": "string", "maxLength": 10}`,
+			Expected: `{"type": "string", "maxLength": 10}`,
+		},
+		{
+			Title:    "several keywords loosened on either side",
+			A:        `{"type": "string", "minLength": 1, "maxLength": 5}`,
+			B:        `{"type": "string", "minLength": 3, "maxLength": 10}`,
+			Expected: `{"type": "string", "minLength": 1, "maxLength": 10}`,
+			Inexact:  []string{"#"},
+		},
+		{
+			Title:    "patterns and enums",
+			A:        `{"type": "string", "pattern": "^a", "title": "first"}`,
+			B:        `{"type": "string", "pattern": "^b", "description": "second"}`,
+			Expected: `{"type": "string", "pattern": "(?:^a)|(?:^b)", "title": "first", "description": "second"}`,
+		},
+		{
+			Title:    "disjoint types",
+			A:        `{"type": "string", "maxLength": 5}`,
+			B:        `{"type": "integer", "maximum": 3, "x-nullable": true}`,
+			Expected: `{"type": ["integer", "string"], "maxLength": 5, "maximum": 3, "x-nullable": true}`,
+			Inexact:  []string{"#"},
+		},
+		{
+			Title:    "enum union",
+			A:        `{"type": "string", "enum": ["a", "b"]}`,
+			B:        `{"type": "string", "enum": ["b", "c"]}`,
+			Expected: `{"type": "string", "enum": ["a", "b", "c"]}`,
+		},
+		{
+			Title:    "formats cannot be combined",
+			A:        `{"type": "string", "format": "date"}`,
+			B:        `{"type": "string", "format": "date-time"}`,
+			Expected: `{"type": "string"}`,
+			Inexact:  []string{"#"},
+		},
+		{
+			Title:    "$ref",
+			A:        `{"$ref": "#/definitions/pet"}`,
+			B:        `{"type": "string"}`,
+			Expected: `{"anyOf": [{"$ref": "#/definitions/pet"}, {"type": "string"}]}`,
+		},
+		{
+			Title:    "unconstrained schema",
+			A:        `{"type": "string"}`,
+			B:        `{"description": "anything"}`,
+			Expected: `{"description": "anything"}`,
+		},
+		{
+			Title: "objects",
+			A: `{
+				"type": "object",
+				"required": ["id", "name"],
+				"properties": {"id": {"type": "integer"}, "name": {"type": "string", "maxLength": 5}}
+			}`,
+			B: `{
+				"type": "object",
+				"required": ["id"],
+				"properties": {"id": {"type": "integer"}, "name": {"type": "string", "maxLength": 10}}
+			}`,
+			Expected: `{
+				"type": "object",
+				"required": ["id"],
+				"properties": {"id": {"type": "integer"}, "name": {"type": "string", "maxLength": 10}}
+			}`,
+		},
+		{
+			Title: "nested approximation",
+			A: `{
+				"type": "object",
+				"properties": {"name": {"type": "string", "minLength": 1, "maxLength": 5}, "age": {"type": "integer"}}
+			}`,
+			B: `{
+				"type": "object",
+				"properties": {"name": {"type": "string", "minLength": 3, "maxLength": 10}, "age": {"type": "integer"}}
+			}`,
+			Expected: `{
+				"type": "object",
+				"properties": {"name": {"type": "string", "minLength": 1, "maxLength": 10}, "age": {"type": "integer"}}
+			}`,
+			Inexact: []string{"#", "#/properties/name"},
+		},
+		{
+			Title:    "properties differing on either side",
+			A:        `{"type": "object", "properties": {"a": {"type": "string"}, "b": {"type": "string"}}}`,
+			B:        `{"type": "object", "properties": {"a": {"type": "integer"}, "b": {"type": "integer"}}}`,
+			Expected: `{"type": "object", "properties": {"a": {"type": ["integer", "string"]}, "b": {"type": ["integer", "string"]}}}`,
+			Inexact:  []string{"#"},
+		},
+		{
+			Title:    "closed objects",
+			A:        `{"type": "object", "properties": {"a": {"type": "string"}}, "additionalProperties": false}`,
+			B:        `{"type": "object", "additionalProperties": {"type": "string"}}`,
+			Expected: `{"type": "object", "properties": {"a": {"type": "string"}}, "additionalProperties": {"type": "string"}}`,
+		},
+		{
+			Title:    "arrays",
+			A:        `{"type": "array", "items": {"type": "string"}, "maxItems": 3}`,
+			B:        `{"type": "array", "items": {"type": "string"}, "maxItems": 5}`,
+			Expected: `{"type": "array", "items": {"type": "string"}, "maxItems": 5}`,
+		},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.Title, func(t *testing.T) {
+			t.Parallel()
+
+			union, findings := UnionSchemas(schemaFromJSON(t, fixture.A), schemaFromJSON(t, fixture.B))
+			assert.JSONEq(t, fixture.Expected, schemaAsJSONString(t, union))
+
+			pointers := make([]string, 0, len(findings))
+			for _, finding := range findings {
+				assert.Equal(t, CodeInexactUnion, finding.Code)
+				assert.NotEmpty(t, finding.Message)
+				pointers = append(pointers, finding.Pointer)
+			}
+
+			if len(fixture.Inexact) == 0 {
+				assert.Empty(t, pointers)
+			} else {
+				assert.Equal(t, fixture.Inexact, pointers)
+			}
+		})
+	}
+}
+
+func TestIntersectSchemas(t *testing.T) {
+	t.Parallel()
+
+	for _, toPin := range []struct {
+		Title    string
+		A, B     string
+		Expected string
+		Codes    []string
+	}{
+		{
+			Title:    "tighter bounds",
+			A:        `{"type": "number", "minimum": 1, "maximum": 10}`,
+			B:        `{"type": "integer", "minimum": 1, "exclusiveMinimum": true, "maximum": 20, "multipleOf": 2}`,
+			Expected: `{"type": "integer", "minimum": 1, "exclusiveMinimum": true, "maximum": 10, "multipleOf": 2}`,
+		},
+		{
+			Title:    "patterns are deferred to allOf",
+			A:        `{"type": "string", "pattern": "^a", "maxLength": 10}`,
+			B:        `{"type": "string", "pattern": "b$", "minLength": 2}`,
+			Expected: `{"type": "string", "pattern": "^a", "maxLength": 10, "minLength": 2, "allOf": [{"pattern": "b$"}]}`,
+		},
+		{
+			Title:    "$ref",
+			A:        `{"$ref": "#/definitions/pet"}`,
+			B:        `{"required": ["name"]}`,
+			Expected: `{"allOf": [{"$ref": "#/definitions/pet"}, {"required": ["name"]}]}`,
+		},
+		{
+			Title:    "unconstrained schema",
+			A:        `{"title": "first"}`,
+			B:        `{"type": "string", "title": "second"}`,
+			Expected: `{"type": "string", "title": "first"}`,
+		},
+		{
+			Title: "objects",
+			A: `{
+				"type": "object",
+				"required": ["id"],
+				"properties": {"id": {"type": "integer"}, "name": {"type": "string", "maxLength": 10}}
+			}`,
+			B: `{
+				"type": "object",
+				"required": ["name"],
+				"properties": {"name": {"type": "string", "minLength": 1}, "tag": {"type": "string"}},
+				"additionalProperties": {"type": ["integer", "string"]}
+			}`,
+			Expected: `{
+				"type": "object",
+				"required": ["id", "name"],
+				"properties": {
+					"id": {"type": "integer"},
+					"name": {"type": "string", "minLength": 1, "maxLength": 10},
+					"tag": {"type": "string"}
+				},
+				"additionalProperties": {"type": ["integer", "string"]}
+			}`,
+		},
+		{
+			Title:    "closed object",
+			A:        `{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}, "extra": {}}}`,
+			B:        `{"type": "object", "properties": {"id": {"type": "integer"}}, "additionalProperties": false}`,
+			Expected: `{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}, "additionalProperties": false}`,
+		},
+		{
+			Title:    "required property not allowed",
+			A:        `{"type": "object", "required": ["extra"]}`,
+			B:        `{"type": "object", "properties": {"id": {"type": "integer"}}, "additionalProperties": false}`,
+			Expected: `{"type": "object", "required": ["extra"], "properties": {"id": {"type": "integer"}}, "additionalProperties": false}`,
+			Codes:    []string{CodeUnsatisfiableRequired},
+		},
+		{
+			Title:    "disjoint types",
+			A:        `{"type": "string"}`,
+			B:        `{"type": ["integer", "boolean"]}`,
+			Expected: `{"not": {}}`,
+			Codes:    []string{CodeEmptyIntersection},
+		},
+		{
+			Title:    "disjoint nullable types",
+			A:        `{"type": ["string", "null"], "maxLength": 5}`,
+			B:        `{"type": "integer", "maximum": 3}`,
+			Expected: `{"not": {}}`,
+			Codes:    []string{CodeEmptyIntersection},
+		},
+		{
+			Title:    "disjoint property types",
+			A:        `{"type": "object", "properties": {"id": {"type": "string"}}}`,
+			B:        `{"type": "object", "properties": {"id": {"type": "integer"}}}`,
+			Expected: `{"type": "object", "properties": {"id": {"not": {}}}}`,
+			Codes:    []string{CodeEmptyIntersection},
+		},
+		{
+			Title:    "disjoint enums",
+			A:        `{"type": "string", "enum": ["a", "b"]}`,
+			B:        `{"enum": ["c"]}`,
+			Expected: `{"not": {}}`,
+			Codes:    []string{CodeEmptyIntersection},
+		},
+		{
+			Title:    "common enum values",
+			A:        `{"type": "string", "enum": ["a", "b"]}`,
+			B:        `{"enum": ["c", "b"]}`,
+			Expected: `{"type": "string", "enum": ["b"]}`,
+		},
+		{
+			Title:    "incompatible bounds",
+			A:        `{"type": "string", "minLength": 5}`,
+			B:        `{"type": "string", "maxLength": 3}`,
+			Expected: `{"type": "string", "minLength": 5, "maxLength": 3}`,
+			Codes:    []string{CodeUnsatisfiableLength},
+		},
+		{
+			Title:    "arrays",
+			A:        `{"type": "array", "items": {"type": "string", "maxLength": 5}}`,
+			B:        `{"type": "array", "items": {"type": "string", "minLength": 6}, "uniqueItems": true}`,
+			Expected: `{"type": "array", "items": {"type": "string", "minLength": 6, "maxLength": 5}, "uniqueItems": true}`,
+			Codes:    []string{CodeUnsatisfiableLength},
+		},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.Title, func(t *testing.T) {
+			t.Parallel()
+
+			intersection, findings := IntersectSchemas(schemaFromJSON(t, fixture.A), schemaFromJSON(t, fixture.B))
+			assert.JSONEq(t, fixture.Expected, schemaAsJSONString(t, intersection))
+
+			codes := make([]string, 0, len(findings))
+			for _, finding := range findings {
+				assert.NotEmpty(t, finding.Message)
+				codes = append(codes, finding.Code)
+			}
+
+			if len(fixture.Codes) == 0 {
+				assert.Empty(t, codes)
+			} else {
+				assert.Equal(t, fixture.Codes, codes)
+			}
+		})
+	}
+}
+
+func TestSchemaSetOps_Nil(t *testing.T) {
+	t.Parallel()
+
+	schema := schemaFromJSON(t, `{"type": "string"}`)
+
+	union, findings := UnionSchemas(nil, schema)
+	assert.Empty(t, findings)
+	assert.JSONEq(t, `{}`, schemaAsJSONString(t, union))
+
+	intersection, findings := IntersectSchemas(schema, nil)
+	assert.Empty(t, findings)
+	assert.JSONEq(t, `{"type": "string"}`, schemaAsJSONString(t, intersection))
+}
+
+func schemaFromJSON(t testing.TB, data string) *spec.Schema {
+	var schema spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(data), &schema))
+
+	return &schema
+}
+
+func schemaAsJSONString(t testing.TB, schema *spec.Schema) string {
+	buf, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	return string(buf)
+}
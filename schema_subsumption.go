@@ -0,0 +1,848 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+)
+
+// Codes for hints explaining why a schema may not accept all the values of another one
+const (
+	CodeNotSubsumed        = "not-subsumed"
+	CodeSubsumptionUnknown = "subsumption-unknown"
+)
+
+// SubsumptionHint explains why a schema may reject some value accepted by another schema.
+//
+// Hints with CodeNotSubsumed tell about a constraint which rejects some values for sure, whereas
+// hints with CodeSubsumptionUnknown tell about constructs the check cannot reason about.
+type SubsumptionHint struct {
+	Pointer string // location in both schemas, e.g. "#/properties/name"
+	Keyword string // the keyword of the accepting schema which may reject values, e.g. "maxLength"
+	Code    string
+	Message string
+
+	// Example is a counterexample at Pointer, i.e. a value accepted by the narrower schema and rejected
+	// by the broader one. It is only a hint: it may be rejected by some other constraint of the narrower schema.
+	// It is nil when no counterexample is known (or when null is the counterexample).
+	Example interface{}
+}
+
+// Subsumption tells if a schema accepts all the values valid under another schema
+type Subsumption struct {
+	Subsumes bool
+	Hints    []SubsumptionHint // sorted by pointer, empty when Subsumes is true
+}
+
+// Subsumes checks whether schema a accepts all the values accepted by schema b (i.e. b ⊆ a),
+// which answers questions such as "can I substitute model a with model b?" (e.g. in responses).
+//
+// The check is sound but incomplete: when Subsumes is true, all values valid under b are valid under a.
+// When Subsumes is false, the hints tell which constraints of a reject some values of b (CodeNotSubsumed),
+// possibly with a counterexample, or which constructs could not be decided (CodeSubsumptionUnknown):
+// different patterns, oneOf, not, patternProperties, tuples...
+// Enums of b are decided by validating each value against a.
+//
+// The schemas are expected to be resolved (e.g. with spec.ExpandSchema): $ref's are not followed,
+// and a $ref is only known to accept the same $ref.
+func Subsumes(a, b *spec.Schema) Subsumption {
+	var hints []SubsumptionHint
+	subsumes := checkSubsumption("#", schemaAsJSON(a), schemaAsJSON(b), &hints)
+
+	sort.SliceStable(hints, func(i, j int) bool { return hints[i].Pointer < hints[j].Pointer })
+
+	if subsumes {
+		return Subsumption{Subsumes: true}
+	}
+
+	return Subsumption{Hints: hints}
+}
+
+// subsumptionCheck checks the constraints of a broader schema against those of a narrower one
+type subsumptionCheck struct {
+	pointer string
+	a, b    map[string]interface{}
+	typesB  []string
+	hints   *[]SubsumptionHint
+	ok      bool
+}
+
+func (c *subsumptionCheck) reject(keyword, code, message string) {
+	*c.hints = append(*c.hints, SubsumptionHint{Pointer: c.pointer, Keyword: keyword, Code: code, Message: message})
+	c.ok = false
+}
+
+func (c *subsumptionCheck) counterexample(keyword string, example interface{}, format string, args ...interface{}) {
+	*c.hints = append(*c.hints, SubsumptionHint{
+		Pointer: c.pointer,
+		Keyword: keyword,
+		Code:    CodeNotSubsumed,
+		Message: fmt.Sprintf(format, args...),
+		Example: example,
+	})
+	c.ok = false
+}
+
+func (c *subsumptionCheck) notSubsumed(keyword, format string, args ...interface{}) {
+	c.reject(keyword, CodeNotSubsumed, fmt.Sprintf(format, args...))
+}
+
+func (c *subsumptionCheck) unknown(keyword, format string, args ...interface{}) {
+	c.reject(keyword, CodeSubsumptionUnknown, fmt.Sprintf(format, args...))
+}
+
+// mayBe tells if values of the narrower schema may have one of some types
+func (c *subsumptionCheck) mayBe(types ...string) bool {
+	return typesOverlap(c.typesB, types)
+}
+
+// checkSubsumption tells if schema a certainly accepts all values of schema b
+func checkSubsumption(pointer string, a, b map[string]interface{}, hints *[]SubsumptionHint) bool {
+	if !hasValidations(a) || reflect.DeepEqual(validationsOf(a), validationsOf(b)) {
+		return true
+	}
+
+	_, isRefA := a["$ref"]
+	_, isRefB := b["$ref"]
+	if isRefA || isRefB {
+		*hints = append(*hints, SubsumptionHint{
+			Pointer: pointer,
+			Keyword: "$ref",
+			Code:    CodeSubsumptionUnknown,
+			Message: "$ref's are not resolved",
+		})
+
+		return false
+	}
+
+	for _, finding := range unsatisfiableConstraints(pointer, jsonAsSchema(b)) {
+		if finding.Code != CodeUnsatisfiableEnum { // some enum values may still be valid
+			return true // no value is valid under b
+		}
+	}
+
+	if _, hasAllOf := b["allOf"]; hasAllOf {
+		return checkSubsumption(pointer, a, foldAllOf(b), hints)
+	}
+
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		members, hasMembers := b[keyword].([]interface{})
+		if !hasMembers {
+			continue
+		}
+
+		// a must accept the values of every alternative
+		rest := deepCopyJSON(b).(map[string]interface{})
+		delete(rest, keyword)
+
+		subsumes := true
+		for _, member := range members {
+			schema, _ := member.(map[string]interface{})
+			alternative, _ := intersectJSON(pointer, rest, schema, new([]Finding))
+			subsumes = checkSubsumption(pointer, a, alternative, hints) && subsumes
+		}
+
+		return subsumes
+	}
+
+	c := &subsumptionCheck{pointer: pointer, a: a, b: b, typesB: schemaTypes(b), hints: hints, ok: true}
+
+	if enum, isEnum := b["enum"].([]interface{}); isEnum {
+		c.checkEnumValues(enum)
+
+		return c.ok
+	}
+
+	c.checkCombinators()
+	c.checkTypes()
+	c.checkEnum()
+	c.checkFormat()
+
+	if c.mayBe("number", "integer") {
+		c.checkNumbers()
+	}
+
+	if c.mayBe("string") {
+		c.checkStrings()
+	}
+
+	if c.mayBe("array") {
+		c.checkArrays()
+	}
+
+	if c.mayBe("object") {
+		c.checkObjects()
+	}
+
+	return c.ok
+}
+
+// foldAllOf merges the allOf members of a schema into the schema itself.
+//
+// Constraints which cannot be merged are dropped: this widens the schema, which keeps the subsumption check sound.
+func foldAllOf(schema map[string]interface{}) map[string]interface{} {
+	members, _ := schema["allOf"].([]interface{})
+	folded := deepCopyJSON(schema).(map[string]interface{})
+	delete(folded, "allOf")
+
+	for _, member := range members {
+		memberSchema, _ := member.(map[string]interface{})
+		if _, isRef := memberSchema["$ref"]; isRef {
+			continue
+		}
+
+		folded, _ = intersectJSON("", folded, memberSchema, new([]Finding))
+		delete(folded, "allOf")
+	}
+
+	return folded
+}
+
+func (c *subsumptionCheck) checkCombinators() {
+	if members, ok := c.a["allOf"].([]interface{}); ok {
+		for _, member := range members {
+			schema, _ := member.(map[string]interface{})
+			if !checkSubsumption(c.pointer, schema, c.b, c.hints) {
+				c.ok = false
+			}
+		}
+	}
+
+	if members, ok := c.a["anyOf"].([]interface{}); ok {
+		accepted := false
+		for _, member := range members {
+			schema, _ := member.(map[string]interface{})
+			if checkSubsumption(c.pointer, schema, c.b, new([]SubsumptionHint)) {
+				accepted = true
+
+				break
+			}
+		}
+
+		if !accepted {
+			c.unknown("anyOf", "no alternative is known to accept all values")
+		}
+	}
+
+	for _, keyword := range []string{"oneOf", "not"} {
+		if value, ok := c.a[keyword]; ok && !reflect.DeepEqual(value, c.b[keyword]) {
+			c.unknown(keyword, "%s is not supported", keyword)
+		}
+	}
+}
+
+// checkEnumValues checks each value of an enum of the narrower schema against the broader one
+func (c *subsumptionCheck) checkEnumValues(values []interface{}) {
+	for _, value := range values {
+		if keyword, decided := rejectingKeyword(c.b, value); !decided || keyword != "" {
+			continue // not a valid value of the narrower schema anyway
+		}
+
+		keyword, decided := rejectingKeyword(c.a, value)
+		switch {
+		case !decided:
+			c.unknown(keyword, "cannot tell if enum value %v is accepted", value)
+		case keyword != "":
+			c.counterexample(keyword, value, "enum value %v is rejected by %s", value, keyword)
+		}
+	}
+}
+
+func (c *subsumptionCheck) checkTypes() {
+	if isNullableJSON(c.b) && !isNullableJSON(c.a) && schemaTypes(c.a) != nil {
+		c.counterexample("x-nullable", nil, "null is rejected")
+	}
+
+	typesA := schemaTypes(c.a)
+	if typesA == nil {
+		return
+	}
+
+	typesB := c.typesB
+	if typesB == nil {
+		typesB = []string{"array", "boolean", "integer", "number", "object", "string"}
+	}
+
+	for _, tpe := range typesB {
+		common := intersectTypes([]string{tpe}, typesA)
+		if len(common) == 1 && common[0] == tpe {
+			continue
+		}
+
+		c.counterexample("type", exampleOfType(tpe), "values of type %s are rejected", tpe)
+	}
+}
+
+func (c *subsumptionCheck) checkEnum() {
+	enum, isEnum := c.a["enum"].([]interface{})
+	if !isEnum {
+		return
+	}
+
+	for _, tpe := range []string{"boolean", "integer", "number", "string"} {
+		if !c.mayBe(tpe) {
+			continue
+		}
+
+		for _, candidate := range exampleCandidates(tpe) {
+			if !containsValue(enum, candidate) {
+				c.counterexample("enum", candidate, "values not in enum are rejected")
+
+				return
+			}
+		}
+	}
+
+	c.notSubsumed("enum", "values not in enum are rejected")
+}
+
+// widths of numerical formats, in bits
+var formatWidths = map[string]int{"int32": 32, "int64": 64, "float": 32, "double": 64}
+
+func (c *subsumptionCheck) checkFormat() {
+	formatA, hasFormat := c.a["format"].(string)
+	if !hasFormat {
+		return
+	}
+
+	formatB, _ := c.b["format"].(string)
+	if formatA == formatB {
+		return
+	}
+
+	if widthA, isNumerical := formatWidths[formatA]; isNumerical {
+		if widthB, ok := formatWidths[formatB]; ok && widthB <= widthA {
+			return
+		}
+
+		if c.mayBe("number", "integer") {
+			c.counterexample("format", math.Pow(2, float64(widthA)), "numbers wider than %s are rejected", formatA)
+		}
+
+		return
+	}
+
+	if !c.mayBe("string") {
+		return
+	}
+
+	if strfmt.Default.ContainsName(formatA) && !strfmt.Default.Validates(formatA, "") {
+		c.counterexample("format", "", "strings which are not a valid %s are rejected", formatA)
+
+		return
+	}
+
+	c.unknown("format", "cannot tell if format %s accepts all values of format %q", formatA, formatB)
+}
+
+func (c *subsumptionCheck) checkNumbers() {
+	for _, keyword := range []string{"maximum", "minimum"} {
+		c.checkBound(keyword)
+	}
+
+	multipleA, hasMultiple := c.a["multipleOf"]
+	if !hasMultiple {
+		return
+	}
+
+	m := asNumber(multipleA)
+	multipleB, hasMultipleB := c.b["multipleOf"]
+	switch {
+	case hasMultipleB && isMultiple(asNumber(multipleB), m):
+	case sameStrings(c.typesB, []string{"integer"}) && isMultiple(1, m):
+	case hasMultipleB:
+		c.counterexample("multipleOf", asNumber(multipleB), "numbers which are not a multiple of %v are rejected", m)
+	case sameStrings(c.typesB, []string{"integer"}):
+		c.counterexample("multipleOf", 1.0, "numbers which are not a multiple of %v are rejected", m)
+	default:
+		c.counterexample("multipleOf", m/2, "numbers which are not a multiple of %v are rejected", m)
+	}
+}
+
+func (c *subsumptionCheck) checkBound(keyword string) {
+	boundA, hasBound := c.a[keyword]
+	if !hasBound {
+		return
+	}
+
+	exclusiveKeyword := exclusiveKeywordOf(keyword)
+	x := asNumber(boundA)
+	exclusiveA := c.a[exclusiveKeyword] == true
+
+	sign := 1.0 // the direction beyond the bound
+	if keyword == "minimum" {
+		sign = -1.0
+	}
+
+	boundB, hasBoundB := c.b[keyword]
+	if !hasBoundB {
+		c.counterexample(keyword, x+sign, "numbers beyond %s %v are rejected", keyword, x)
+
+		return
+	}
+
+	y := asNumber(boundB)
+	exclusiveB := c.b[exclusiveKeyword] == true
+
+	switch {
+	case sign*y < sign*x:
+	case y == x && (!exclusiveA || exclusiveB):
+	case !exclusiveB:
+		c.counterexample(keyword, y, "numbers beyond %s %v are rejected", keyword, x)
+	default:
+		c.counterexample(keyword, (x+y)/2, "numbers beyond %s %v are rejected", keyword, x)
+	}
+}
+
+func (c *subsumptionCheck) checkStrings() {
+	if maxLength, ok := c.a["maxLength"]; ok {
+		limit := int(asNumber(maxLength))
+		if value, ok := c.b["maxLength"]; !ok || int(asNumber(value)) > limit {
+			c.counterexample("maxLength", strings.Repeat("a", limit+1), "strings longer than %d are rejected", limit)
+		}
+	}
+
+	if minLength, ok := c.a["minLength"]; ok {
+		limit := int(asNumber(minLength))
+		if value := int(asNumber(c.b["minLength"])); value < limit {
+			c.counterexample("minLength", strings.Repeat("a", value), "strings shorter than %d are rejected", limit)
+		}
+	}
+
+	if pattern, ok := c.a["pattern"].(string); ok && pattern != c.b["pattern"] {
+		c.checkPattern(pattern)
+	}
+}
+
+// checkPattern looks for a simple counterexample of a pattern, or reports that the check cannot tell
+func (c *subsumptionCheck) checkPattern(pattern string) {
+	if _, hasPattern := c.b["pattern"]; !hasPattern {
+		for _, candidate := range []string{"", "a", "A", "0", " ", "-", "a0", "0a", "aaaaaaaaaa", "0000000000"} {
+			if keyword, decided := rejectingKeyword(c.b, candidate); !decided || keyword != "" {
+				continue
+			}
+
+			if keyword, decided := rejectingKeyword(c.a, candidate); decided && keyword == "pattern" {
+				c.counterexample("pattern", candidate, "strings not matching %q are rejected", pattern)
+
+				return
+			}
+		}
+	}
+
+	c.unknown("pattern", "cannot tell if pattern %q accepts all strings", pattern)
+}
+
+func (c *subsumptionCheck) checkArrays() {
+	c.checkCount("maxItems", "minItems", "arrays")
+
+	if c.a["uniqueItems"] == true && c.b["uniqueItems"] != true {
+		c.notSubsumed("uniqueItems", "arrays with duplicate items are rejected")
+	}
+
+	itemsA, hasItems := c.a["items"]
+	if !hasItems {
+		return
+	}
+
+	schemaA, isSchemaA := itemsA.(map[string]interface{})
+	itemsB, hasItemsB := c.b["items"]
+	schemaB, isSchemaB := itemsB.(map[string]interface{})
+
+	switch {
+	case isSchemaA && (isSchemaB || !hasItemsB):
+		if !checkSubsumption(c.pointer+"/items", schemaA, schemaB, c.hints) {
+			c.ok = false
+		}
+	case !reflect.DeepEqual(itemsA, itemsB) || !reflect.DeepEqual(c.a["additionalItems"], c.b["additionalItems"]):
+		c.unknown("items", "tuples are not supported")
+	}
+}
+
+func (c *subsumptionCheck) checkObjects() {
+	c.checkCount("maxProperties", "minProperties", "objects")
+
+	requiredB := asStrings(c.b["required"])
+	for _, name := range asStrings(c.a["required"]) {
+		if !containsString(requiredB, name) {
+			c.notSubsumed("required", "objects without property %q are rejected", name)
+		}
+	}
+
+	if patterns, ok := c.a["patternProperties"]; ok && !reflect.DeepEqual(patterns, c.b["patternProperties"]) {
+		c.unknown("patternProperties", "patternProperties are not supported")
+
+		return
+	}
+
+	propsA, _ := c.a["properties"].(map[string]interface{})
+	propsB, _ := c.b["properties"].(map[string]interface{})
+
+	for _, name := range sortedKeys(propsA) {
+		propA, _ := propsA[name].(map[string]interface{})
+		propB, _ := propertySchema(c.b, propsB, name)
+		if !checkSubsumption(c.pointer+"/properties/"+jsonpointer.Escape(name), propA, propB, c.hints) {
+			c.ok = false
+		}
+	}
+
+	additionalA, hasAdditional := c.a["additionalProperties"]
+	if !hasAdditional || additionalA == true {
+		return
+	}
+
+	schemaA, _ := additionalA.(map[string]interface{})
+	if additionalA == false {
+		schemaA = map[string]interface{}{"not": map[string]interface{}{}}
+	}
+
+	for _, name := range sortedKeys(propsB) {
+		if _, isDeclared := propsA[name]; isDeclared {
+			continue
+		}
+
+		propB, _ := propsB[name].(map[string]interface{})
+		if isForbidden(propB) {
+			continue
+		}
+
+		if additionalA == false {
+			c.notSubsumed("additionalProperties", "objects with property %q are rejected", name)
+
+			continue
+		}
+
+		if !checkSubsumption(c.pointer+"/properties/"+jsonpointer.Escape(name), schemaA, propB, c.hints) {
+			c.ok = false
+		}
+	}
+
+	additionalB, hasAdditionalB := c.b["additionalProperties"]
+	schemaB, isSchemaB := additionalB.(map[string]interface{})
+	_, hasPatternsB := c.b["patternProperties"]
+
+	switch {
+	case additionalB == false:
+	case hasPatternsB && !hasAdditionalB:
+		c.unknown("additionalProperties", "patternProperties are not supported")
+	case additionalA == false:
+		c.notSubsumed("additionalProperties", "objects with additional properties are rejected")
+	case isSchemaB:
+		if !checkSubsumption(c.pointer+"/additionalProperties", schemaA, schemaB, c.hints) {
+			c.ok = false
+		}
+	default:
+		if !checkSubsumption(c.pointer+"/additionalProperties", schemaA, map[string]interface{}{}, c.hints) {
+			c.ok = false
+		}
+	}
+}
+
+// checkCount checks the bounds on the number of items or properties
+func (c *subsumptionCheck) checkCount(maxKeyword, minKeyword, what string) {
+	if maxCount, ok := c.a[maxKeyword]; ok {
+		limit := asNumber(maxCount)
+		if value, ok := c.b[maxKeyword]; !ok || asNumber(value) > limit {
+			c.notSubsumed(maxKeyword, "%s with more than %v elements are rejected", what, limit)
+		}
+	}
+
+	if minCount, ok := c.a[minKeyword]; ok {
+		limit := asNumber(minCount)
+		if asNumber(c.b[minKeyword]) < limit {
+			c.notSubsumed(minKeyword, "%s with less than %v elements are rejected", what, limit)
+		}
+	}
+}
+
+// exampleOfType yields some value of a JSON type
+func exampleOfType(tpe string) interface{} {
+	return exampleCandidates(tpe)[0]
+}
+
+func exampleCandidates(tpe string) []interface{} {
+	switch tpe {
+	case "boolean":
+		return []interface{}{true, false}
+	case "integer":
+		return []interface{}{0.0, 1.0, -1.0, 42.0}
+	case "number":
+		return []interface{}{0.5, 0.0, 1.0, -1.0}
+	case "string":
+		return []interface{}{"", "a", "example"}
+	case "array":
+		return []interface{}{[]interface{}{}}
+	case "object":
+		return []interface{}{map[string]interface{}{}}
+	default:
+		return []interface{}{nil}
+	}
+}
+
+// rejectingKeyword validates a JSON value against a schema, returning the keyword which rejects the value,
+// or "" when the value is valid.
+//
+// It tells if the validation could be decided: $ref's are not resolved, and unknown formats, tuples or
+// invalid patterns are not supported.
+func rejectingKeyword(schema map[string]interface{}, value interface{}) (string, bool) {
+	if _, isRef := schema["$ref"]; isRef {
+		return "$ref", false
+	}
+
+	if value == nil && isNullableJSON(schema) {
+		return "", true
+	}
+
+	if types := schemaTypes(schema); types != nil {
+		matches := false
+		for _, tpe := range types {
+			matches = matches || jsonValueHasType(value, tpe)
+		}
+
+		if !matches {
+			return "type", true
+		}
+	}
+
+	if enum, isEnum := schema["enum"].([]interface{}); isEnum && !containsValue(enum, value) {
+		return "enum", true
+	}
+
+	var checks []func(map[string]interface{}, interface{}) (string, bool)
+	switch value.(type) {
+	case string:
+		checks = append(checks, rejectingStringKeyword)
+	case []interface{}:
+		checks = append(checks, rejectingArrayKeyword)
+	case map[string]interface{}:
+		checks = append(checks, rejectingObjectKeyword)
+	default:
+		if _, isNumber := asFloat(value); isNumber {
+			checks = append(checks, rejectingNumberKeyword)
+		}
+	}
+
+	checks = append(checks, rejectingCombinatorKeyword)
+
+	for _, check := range checks {
+		if keyword, decided := check(schema, value); !decided || keyword != "" {
+			return keyword, decided
+		}
+	}
+
+	return "", true
+}
+
+func rejectingNumberKeyword(schema map[string]interface{}, value interface{}) (string, bool) {
+	number, _ := asFloat(value)
+
+	if bound, ok := schema["maximum"]; ok {
+		limit := asNumber(bound)
+		if number > limit || number == limit && schema["exclusiveMaximum"] == true {
+			return "maximum", true
+		}
+	}
+
+	if bound, ok := schema["minimum"]; ok {
+		limit := asNumber(bound)
+		if number < limit || number == limit && schema["exclusiveMinimum"] == true {
+			return "minimum", true
+		}
+	}
+
+	if multiple, ok := schema["multipleOf"]; ok && !isMultiple(number, asNumber(multiple)) {
+		return "multipleOf", true
+	}
+
+	if format, ok := schema["format"].(string); ok {
+		switch format {
+		case "int32":
+			if number < math.MinInt32 || number > math.MaxInt32 {
+				return "format", true
+			}
+		case "float":
+			if math.Abs(number) > math.MaxFloat32 {
+				return "format", true
+			}
+		}
+	}
+
+	return "", true
+}
+
+func rejectingStringKeyword(schema map[string]interface{}, value interface{}) (string, bool) {
+	str, _ := value.(string)
+	length := float64(utf8.RuneCountInString(str))
+
+	if maxLength, ok := schema["maxLength"]; ok && length > asNumber(maxLength) {
+		return "maxLength", true
+	}
+
+	if minLength, ok := schema["minLength"]; ok && length < asNumber(minLength) {
+		return "minLength", true
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		rex, err := regexp.Compile(pattern)
+		if err != nil {
+			return "pattern", false
+		}
+
+		if !rex.MatchString(str) {
+			return "pattern", true
+		}
+	}
+
+	if format, ok := schema["format"].(string); ok {
+		if !strfmt.Default.ContainsName(format) {
+			return "format", false
+		}
+
+		if !strfmt.Default.Validates(format, str) {
+			return "format", true
+		}
+	}
+
+	return "", true
+}
+
+func rejectingArrayKeyword(schema map[string]interface{}, value interface{}) (string, bool) {
+	items, _ := value.([]interface{})
+	count := float64(len(items))
+
+	if maxItems, ok := schema["maxItems"]; ok && count > asNumber(maxItems) {
+		return "maxItems", true
+	}
+
+	if minItems, ok := schema["minItems"]; ok && count < asNumber(minItems) {
+		return "minItems", true
+	}
+
+	if schema["uniqueItems"] == true {
+		for i := range items {
+			if containsValue(items[:i], items[i]) {
+				return "uniqueItems", true
+			}
+		}
+	}
+
+	switch itemsSchema := schema["items"].(type) {
+	case nil:
+	case map[string]interface{}:
+		for _, item := range items {
+			if keyword, decided := rejectingKeyword(itemsSchema, item); !decided || keyword != "" {
+				return "items", decided
+			}
+		}
+	default:
+		return "items", false
+	}
+
+	return "", true
+}
+
+func rejectingObjectKeyword(schema map[string]interface{}, value interface{}) (string, bool) {
+	object, _ := value.(map[string]interface{})
+	count := float64(len(object))
+
+	if maxProperties, ok := schema["maxProperties"]; ok && count > asNumber(maxProperties) {
+		return "maxProperties", true
+	}
+
+	if minProperties, ok := schema["minProperties"]; ok && count < asNumber(minProperties) {
+		return "minProperties", true
+	}
+
+	for _, name := range asStrings(schema["required"]) {
+		if _, ok := object[name]; !ok {
+			return "required", true
+		}
+	}
+
+	if _, hasPatterns := schema["patternProperties"]; hasPatterns {
+		return "patternProperties", false
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, name := range sortedKeys(object) {
+		keyword := "properties"
+		propertySchema, isDeclared := properties[name].(map[string]interface{})
+		if !isDeclared {
+			keyword = "additionalProperties"
+			switch additional := schema["additionalProperties"].(type) {
+			case map[string]interface{}:
+				propertySchema = additional
+			case bool:
+				if !additional {
+					return keyword, true
+				}
+			}
+		}
+
+		if propertySchema == nil {
+			continue
+		}
+
+		if rejecting, decided := rejectingKeyword(propertySchema, object[name]); !decided || rejecting != "" {
+			return keyword, decided
+		}
+	}
+
+	return "", true
+}
+
+func rejectingCombinatorKeyword(schema map[string]interface{}, value interface{}) (string, bool) {
+	validates := func(member interface{}) (bool, bool) {
+		memberSchema, _ := member.(map[string]interface{})
+		keyword, decided := rejectingKeyword(memberSchema, value)
+
+		return keyword == "", decided
+	}
+
+	allOf, _ := schema["allOf"].([]interface{})
+	for _, member := range allOf {
+		if valid, decided := validates(member); !decided || !valid {
+			return "allOf", decided
+		}
+	}
+
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		members, ok := schema[keyword].([]interface{})
+		if !ok {
+			continue
+		}
+
+		matches := 0
+		for _, member := range members {
+			valid, decided := validates(member)
+			if !decided {
+				return keyword, false
+			}
+
+			if valid {
+				matches++
+			}
+		}
+
+		if matches == 0 || keyword == "oneOf" && matches > 1 {
+			return keyword, true
+		}
+	}
+
+	if not, ok := schema["not"]; ok {
+		valid, decided := validates(not)
+		if !decided || valid {
+			return "not", decided
+		}
+	}
+
+	return "", true
+}
@@ -0,0 +1,206 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubsumes(t *testing.T) {
+	t.Parallel()
+
+	type hint struct {
+		Pointer string
+		Keyword string
+		Code    string
+		Example interface{}
+	}
+
+	for _, toPin := range []struct {
+		Title    string
+		A, B     string
+		Expected []hint // no hint when a subsumes b
+	}{
+		{
+			Title: "unconstrained schema",
+			A:     `{"description": "anything"}`,
+			B:     `{"type": "string"}`,
+		},
+		{
+			Title: "narrower bounds",
+			A:     `{"type": "number", "minimum": 0, "maximum": 100}`,
+			B:     `{"type": "integer", "minimum": 1, "maximum": 100, "exclusiveMaximum": true}`,
+		},
+		{
+			Title: "wider bounds",
+			A:     `{"type": "integer", "minimum": 0, "maximum": 10, "exclusiveMaximum": true}`,
+			B:     `{"type": "integer", "maximum": 10}`,
+			Expected: []hint{
+				{Pointer: "#", Keyword: "maximum", Code: CodeNotSubsumed, Example: 10.0},
+				{Pointer: "#", Keyword: "minimum", Code: CodeNotSubsumed, Example: -1.0},
+			},
+		},
+		{
+			Title: "wider type",
+			A:     `{"type": "integer"}`,
+			B:     `{"type": ["integer", "number"], "x-nullable": true}`,
+			Expected: []hint{
+				{Pointer: "#", Keyword: "x-nullable", Code: CodeNotSubsumed},
+				{Pointer: "#", Keyword: "type", Code: CodeNotSubsumed, Example: 0.5},
+			},
+		},
+		{
+			Title: "enum values",
+			A:     `{"type": "string", "maxLength": 3}`,
+			B:     `{"type": "string", "enum": ["a", "abc", 12, "abcd"]}`,
+			Expected: []hint{
+				{Pointer: "#", Keyword: "maxLength", Code: CodeNotSubsumed, Example: "abcd"},
+			},
+		},
+		{
+			Title: "enum subset",
+			A:     `{"type": "string", "enum": ["a", "b", "c"]}`,
+			B:     `{"type": "string", "enum": ["c", "a"]}`,
+		},
+		{
+			Title: "enum",
+			A:     `{"type": "string", "enum": ["", "a"]}`,
+			B:     `{"type": "string"}`,
+			Expected: []hint{
+				{Pointer: "#", Keyword: "enum", Code: CodeNotSubsumed, Example: "example"},
+			},
+		},
+		{
+			Title: "multipleOf",
+			A:     `{"type": "integer", "multipleOf": 2}`,
+			B:     `{"type": "integer", "multipleOf": 4}`,
+		},
+		{
+			Title: "strings",
+			A:     `{"type": "string", "minLength": 2, "maxLength": 4, "pattern": "^[a-z]+$"}`,
+			B:     `{"type": "string", "minLength": 1, "maxLength": 3}`,
+			Expected: []hint{
+				{Pointer: "#", Keyword: "minLength", Code: CodeNotSubsumed, Example: "a"},
+				{Pointer: "#", Keyword: "pattern", Code: CodeNotSubsumed, Example: "a0"},
+			},
+		},
+		{
+			Title: "different patterns",
+			A:     `{"type": "string", "pattern": "^[a-z]+$"}`,
+			B:     `{"type": "string", "pattern": "^a+$"}`,
+			Expected: []hint{
+				{Pointer: "#", Keyword: "pattern", Code: CodeSubsumptionUnknown},
+			},
+		},
+		{
+			Title: "formats",
+			A:     `{"type": "integer", "format": "int64"}`,
+			B:     `{"type": "integer", "format": "int32"}`,
+		},
+		{
+			Title: "narrower formats",
+			A:     `{"type": "string", "format": "date"}`,
+			B:     `{"type": "string"}`,
+			Expected: []hint{
+				{Pointer: "#", Keyword: "format", Code: CodeNotSubsumed, Example: ""},
+			},
+		},
+		{
+			Title: "objects",
+			A: `{
+				"type": "object",
+				"required": ["id"],
+				"properties": {"id": {"type": "integer"}, "name": {"type": "string"}}
+			}`,
+			B: `{
+				"type": "object",
+				"required": ["id", "name"],
+				"properties": {"id": {"type": "integer", "minimum": 1}, "name": {"type": "string", "maxLength": 10}},
+				"additionalProperties": false
+			}`,
+		},
+		{
+			Title: "missing required property",
+			A: `{
+				"type": "object",
+				"required": ["id"],
+				"properties": {"id": {"type": "integer"}, "tags": {"type": "array", "items": {"type": "string"}}}
+			}`,
+			B: `{
+				"type": "object",
+				"properties": {"id": {"type": "integer"}, "tags": {"type": "array", "items": {"type": "integer"}}}
+			}`,
+			Expected: []hint{
+				{Pointer: "#", Keyword: "required", Code: CodeNotSubsumed},
+				{Pointer: "#/properties/tags/items", Keyword: "type", Code: CodeNotSubsumed, Example: 0.0},
+			},
+		},
+		{
+			Title: "closed objects",
+			A:     `{"type": "object", "properties": {"id": {"type": "integer"}}, "additionalProperties": false}`,
+			B:     `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}}`,
+			Expected: []hint{
+				{Pointer: "#", Keyword: "additionalProperties", Code: CodeNotSubsumed},
+				{Pointer: "#", Keyword: "additionalProperties", Code: CodeNotSubsumed},
+			},
+		},
+		{
+			Title: "allOf",
+			A:     `{"type": "object", "required": ["id", "name"]}`,
+			B:     `{"allOf": [{"type": "object", "required": ["id"]}, {"required": ["name"]}]}`,
+		},
+		{
+			Title: "anyOf",
+			A:     `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`,
+			B:     `{"anyOf": [{"type": "string", "maxLength": 3}, {"type": "integer", "minimum": 0}]}`,
+		},
+		{
+			Title: "unsatisfiable schema",
+			A:     `{"type": "string", "maxLength": 3}`,
+			B:     `{"type": "string", "minLength": 5, "maxLength": 4}`,
+		},
+		{
+			Title: "$ref",
+			A:     `{"$ref": "#/definitions/pet"}`,
+			B:     `{"type": "object"}`,
+			Expected: []hint{
+				{Pointer: "#", Keyword: "$ref", Code: CodeSubsumptionUnknown},
+			},
+		},
+		{
+			Title: "same $ref",
+			A:     `{"$ref": "#/definitions/pet"}`,
+			B:     `{"$ref": "#/definitions/pet", "description": "a pet"}`,
+		},
+	} {
+		fixture := toPin
+
+		t.Run(fixture.Title, func(t *testing.T) {
+			t.Parallel()
+
+			result := Subsumes(schemaFromJSON(t, fixture.A), schemaFromJSON(t, fixture.B))
+			assert.Equal(t, len(fixture.Expected) == 0, result.Subsumes)
+
+			actual := make([]hint, 0, len(result.Hints))
+			for _, h := range result.Hints {
+				assert.NotEmpty(t, h.Message)
+				actual = append(actual, hint{Pointer: h.Pointer, Keyword: h.Keyword, Code: h.Code, Example: h.Example})
+			}
+
+			if len(fixture.Expected) == 0 {
+				assert.Empty(t, actual)
+			} else {
+				assert.Equal(t, fixture.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestSubsumes_Nil(t *testing.T) {
+	t.Parallel()
+
+	schema := schemaFromJSON(t, `{"type": "string"}`)
+
+	assert.True(t, Subsumes(nil, schema).Subsumes)
+	assert.False(t, Subsumes(schema, nil).Subsumes)
+}
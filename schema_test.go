@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -252,6 +253,93 @@ func TestSchemaAnalysis_EdgeCases(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestSchemaAnalysis_OnResolve(t *testing.T) {
+	t.Parallel()
+
+	errBlocked := errors.New("blocked")
+	root := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Definitions: spec.Definitions{
+			"public": *spec.StringProperty(),
+			"mirror": *spec.Int64Property(),
+			"names":  *spec.ArrayProperty(spec.RefSchema("#/definitions/public")),
+			"tree":   *spec.ArrayProperty(spec.RefSchema("#/definitions/tree")),
+		},
+	}}
+
+	t.Run("should observe the resolved $ref's", func(t *testing.T) {
+		t.Parallel()
+
+		var observed []string
+		a, err := Schema(SchemaOpts{
+			Schema: spec.ArrayProperty(spec.RefSchema("#/definitions/names")),
+			Root:   root,
+			OnResolve: func(ref, from string) error {
+				observed = append(observed, ref+" from "+from)
+
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		assert.True(t, a.IsSimpleArray)
+		assert.Equal(t, []string{"#/definitions/names from ", "#/definitions/public from "}, observed)
+	})
+
+	t.Run("should stop on circular $ref's", func(t *testing.T) {
+		t.Parallel()
+
+		var observed int
+		a, err := Schema(SchemaOpts{
+			Schema: spec.RefSchema("#/definitions/tree"),
+			Root:   root,
+			OnResolve: func(string, string) error {
+				observed++
+
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		assert.True(t, a.IsArray)
+		assert.Equal(t, 2, observed)
+	})
+
+	t.Run("should redirect a $ref", func(t *testing.T) {
+		t.Parallel()
+
+		a, err := Schema(SchemaOpts{
+			Schema: spec.RefSchema("#/definitions/public"),
+			Root:   root,
+			OnResolve: func(ref, _ string) error {
+				if ref == "#/definitions/public" {
+					return &RefRedirect{Ref: "#/definitions/mirror"}
+				}
+
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "int64", a.GoTypeHint.Type)
+		assert.Equal(t, "public", a.GoTypeHint.Definition)
+	})
+
+	t.Run("should veto a $ref", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Schema(SchemaOpts{
+			Schema: spec.MapProperty(spec.RefSchema("#/definitions/public")),
+			Root:   root,
+			OnResolve: func(string, string) error {
+				return errBlocked
+			},
+		})
+		require.Error(t, err)
+		require.ErrorIs(t, err, errBlocked)
+
+		var refErr *RefError
+		require.ErrorAs(t, err, &refErr)
+		assert.Equal(t, "#/definitions/public", refErr.Ref)
+	})
+}
+
 /* helpers for the Schema test suite */
 
 func newCObj() *spec.Schema {
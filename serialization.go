@@ -0,0 +1,159 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/go-openapi/spec"
+)
+
+// CodeInvalidCollectionFormat is the code for findings about collection formats which cannot be honored,
+// e.g. "multi" for a header parameter, or an unknown format
+const CodeInvalidCollectionFormat = "invalid-collection-format"
+
+// SerializationKind classifies how the value of a parameter is serialized in a request
+type SerializationKind string
+
+// Kinds of serialization of parameters
+const (
+	// SerializationScalar is a single value
+	SerializationScalar SerializationKind = "scalar"
+
+	// SerializationDelimited is an array serialized as a single value, with its items joined by a separator
+	SerializationDelimited SerializationKind = "delimited"
+
+	// SerializationMulti is an array serialized as one parameter per item (e.g. "?tag=a&tag=b")
+	SerializationMulti SerializationKind = "multi"
+
+	// SerializationBody is a body parameter, serialized according to the media type of the request
+	SerializationBody SerializationKind = "body"
+
+	// SerializationFile is a file uploaded as a form parameter
+	SerializationFile SerializationKind = "file"
+)
+
+// separators of the collection formats of arrays
+var collectionSeparators = map[string]string{
+	"csv":   ",",
+	"ssv":   " ",
+	"tsv":   "\t",
+	"pipes": "|",
+}
+
+// Serialization describes how the value of a parameter is serialized in a request
+type Serialization struct {
+	Kind SerializationKind
+
+	// CollectionFormat is the effective collection format of an array ("csv" when not specified),
+	// and empty for other kinds
+	CollectionFormat string
+
+	// Separator joins the items of a delimited array
+	Separator string
+
+	// ItemType and ItemFormat are the type and format of the values serialized: those of the innermost items
+	// for arrays, or of the parameter itself
+	ItemType   string
+	ItemFormat string
+
+	// Items describes the serialization of the items of an array, when these are arrays too (e.g. "1,2|3,4")
+	Items *Serialization
+}
+
+// SerializationFor analyzes how the value of a (resolved) parameter is serialized in a request,
+// according to its location, type and collection format.
+//
+// Use InvalidSerializations to detect the collection formats which cannot be honored.
+func SerializationFor(param *spec.Parameter) Serialization {
+	switch {
+	case param.In == "body":
+		return Serialization{Kind: SerializationBody}
+	case param.Type == "file":
+		return Serialization{Kind: SerializationFile, ItemType: param.Type}
+	case param.Type != "array":
+		return Serialization{Kind: SerializationScalar, ItemType: param.Type, ItemFormat: param.Format}
+	}
+
+	serialization := arraySerialization(param.CollectionFormat, param.Items)
+	if param.CollectionFormat == "multi" {
+		serialization.Kind = SerializationMulti
+	}
+
+	return serialization
+}
+
+// arraySerialization describes a delimited array, and recursively its nested arrays
+func arraySerialization(format string, items *spec.Items) Serialization {
+	if format == "" {
+		format = "csv"
+	}
+
+	serialization := Serialization{
+		Kind:             SerializationDelimited,
+		CollectionFormat: format,
+		Separator:        collectionSeparators[format],
+	}
+
+	if items == nil {
+		return serialization
+	}
+
+	if items.Type != "array" {
+		serialization.ItemType, serialization.ItemFormat = items.Type, items.Format
+
+		return serialization
+	}
+
+	nested := arraySerialization(items.CollectionFormat, items.Items)
+	serialization.Items = &nested
+	serialization.ItemType, serialization.ItemFormat = nested.ItemType, nested.ItemFormat
+
+	return serialization
+}
+
+// InvalidSerializations reports the parameters whose collection format cannot be honored:
+//   - "multi" outside query and form parameters, or for nested items
+//   - unknown collection formats
+//   - collection formats of parameters which are not arrays
+//
+// Findings are sorted by pointer.
+func (s *Spec) InvalidSerializations() []Finding {
+	var findings []Finding
+	report := func(pointer, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			Pointer: pointer,
+			Code:    CodeInvalidCollectionFormat,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	walkParameters(s.spec, func(pointer string, param *spec.Parameter) {
+		if param.Ref.String() != "" || param.In == "body" {
+			return
+		}
+
+		if param.CollectionFormat != "" && param.Type != "array" {
+			report(pointer, "collection format %q is ignored by a parameter of type %q", param.CollectionFormat, param.Type)
+
+			return
+		}
+
+		switch format := param.CollectionFormat; {
+		case format == "multi" && param.In != "query" && param.In != "formData":
+			report(pointer, `collection format "multi" is only valid for query or formData parameters, not %s`, param.In)
+		case format != "" && format != "multi" && collectionSeparators[format] == "":
+			report(pointer, "unknown collection format %q", format)
+		}
+
+		for items, itemsPointer := param.Items, pointer+"/items"; items != nil; items, itemsPointer = items.Items, itemsPointer+"/items" {
+			switch format := items.CollectionFormat; {
+			case format == "multi":
+				report(itemsPointer, `collection format "multi" is not valid for nested items`)
+			case format != "" && collectionSeparators[format] == "":
+				report(itemsPointer, "unknown collection format %q", format)
+			}
+		}
+	})
+	sortFindings(findings)
+
+	return findings
+}
@@ -0,0 +1,89 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerializationFor(t *testing.T) {
+	t.Parallel()
+
+	for _, toPin := range []struct {
+		name     string
+		param    *spec.Parameter
+		expected Serialization
+	}{
+		{
+			name:     "body",
+			param:    spec.BodyParam("pet", spec.RefSchema("#/definitions/pet")),
+			expected: Serialization{Kind: SerializationBody},
+		},
+		{
+			name:     "file",
+			param:    spec.FileParam("photo"),
+			expected: Serialization{Kind: SerializationFile, ItemType: "file"},
+		},
+		{
+			name:     "scalar",
+			param:    spec.QueryParam("limit").Typed("integer", "int32"),
+			expected: Serialization{Kind: SerializationScalar, ItemType: "integer", ItemFormat: "int32"},
+		},
+		{
+			name:  "default csv",
+			param: spec.QueryParam("ids").CollectionOf(spec.NewItems().Typed("integer", "int64"), ""),
+			expected: Serialization{
+				Kind: SerializationDelimited, CollectionFormat: "csv", Separator: ",", ItemType: "integer", ItemFormat: "int64",
+			},
+		},
+		{
+			name:     "multi",
+			param:    spec.QueryParam("tags").CollectionOf(spec.NewItems().Typed("string", ""), "multi"),
+			expected: Serialization{Kind: SerializationMulti, CollectionFormat: "multi", ItemType: "string"},
+		},
+		{
+			name: "nested arrays",
+			param: spec.QueryParam("grid").CollectionOf(
+				spec.NewItems().CollectionOf(spec.NewItems().Typed("number", "float"), "csv"), "pipes",
+			),
+			expected: Serialization{
+				Kind: SerializationDelimited, CollectionFormat: "pipes", Separator: "|", ItemType: "number", ItemFormat: "float",
+				Items: &Serialization{
+					Kind: SerializationDelimited, CollectionFormat: "csv", Separator: ",", ItemType: "number", ItemFormat: "float",
+				},
+			},
+		},
+	} {
+		tc := toPin
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, SerializationFor(tc.param))
+		})
+	}
+}
+
+func TestAnalyzer_InvalidSerializations(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "serialization.yml")))
+
+	findings := an.InvalidSerializations()
+	pointers := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		assert.Equal(t, CodeInvalidCollectionFormat, finding.Code)
+		assert.NotEmpty(t, finding.Message)
+		pointers = append(pointers, finding.Pointer)
+	}
+
+	assert.Equal(t, []string{
+		"#/parameters/tags",
+		"#/paths/~1pets/get/parameters/2/items",
+		"#/paths/~1pets/get/parameters/3",
+		"#/paths/~1pets/get/parameters/4",
+	}, pointers)
+}
@@ -0,0 +1,185 @@
+package analysis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const snapshotHashPrefix = "sha256:"
+
+// SnapshotStore persists documents, addressed by the hash of their content
+type SnapshotStore interface {
+	// Put stores a document and returns its hash, e.g. "sha256:2c26b46b68ffc68ff99b453c1d304134..."
+	Put(data []byte) (string, error)
+
+	// Get retrieves a document from its hash
+	Get(hash string) ([]byte, error)
+}
+
+// DirSnapshotStore is a SnapshotStore keeping documents as files in a directory
+type DirSnapshotStore struct {
+	Dir string
+}
+
+// Put stores a document as a file named after its hash
+func (s DirSnapshotStore) Put(data []byte) (string, error) {
+	hash := contentHash(data)
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return "", err
+	}
+
+	return hash, os.WriteFile(s.file(hash), data, 0o600)
+}
+
+// Get retrieves a document from its hash
+func (s DirSnapshotStore) Get(hash string) ([]byte, error) {
+	return os.ReadFile(s.file(hash))
+}
+
+func (s DirSnapshotStore) file(hash string) string {
+	return filepath.Join(s.Dir, strings.TrimPrefix(hash, snapshotHashPrefix))
+}
+
+// MemorySnapshotStore is a SnapshotStore keeping documents in memory
+type MemorySnapshotStore struct {
+	mx   sync.Mutex
+	docs map[string][]byte
+}
+
+// Put stores a document in memory
+func (s *MemorySnapshotStore) Put(data []byte) (string, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.docs == nil {
+		s.docs = make(map[string][]byte)
+	}
+
+	hash := contentHash(data)
+	s.docs[hash] = append([]byte(nil), data...)
+
+	return hash, nil
+}
+
+// Get retrieves a document from memory
+func (s *MemorySnapshotStore) Get(hash string) ([]byte, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	data, ok := s.docs[hash]
+	if !ok {
+		return nil, fmt.Errorf("document %s not found in snapshot store", hash)
+	}
+
+	return data, nil
+}
+
+// Snapshot records the remote documents resolved while flattening a spec into a content-addressed store,
+// so that the analysis may be reproduced later from this snapshot only.
+//
+// Remote documents are those fetched from a URL other than a local file.
+//
+// In replay mode, remote documents are never fetched, but served from the store: resolving a document
+// absent from the snapshot fails.
+type Snapshot struct {
+	Store SnapshotStore `json:"-"`
+
+	// Documents maps the URL of each resolved remote document to the hash of its content
+	Documents map[string]string `json:"documents"`
+
+	// Replay serves remote documents from the snapshot only
+	Replay bool `json:"-"`
+
+	mx sync.Mutex
+}
+
+// NewSnapshot builds a snapshot recording documents into a store
+func NewSnapshot(store SnapshotStore) *Snapshot {
+	return &Snapshot{Store: store, Documents: make(map[string]string)}
+}
+
+// ReadSnapshot reads the index of a snapshot, as written by WriteIndex, and prepares the snapshot for replay
+// from a store
+func ReadSnapshot(r io.Reader, store SnapshotStore) (*Snapshot, error) {
+	s := &Snapshot{Store: store, Replay: true}
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, err
+	}
+
+	if s.Documents == nil {
+		s.Documents = make(map[string]string)
+	}
+
+	return s, nil
+}
+
+// WriteIndex writes the index of the snapshot (URL to hash), as JSON
+func (s *Snapshot) WriteIndex(w io.Writer) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(s)
+}
+
+// pathLoader wraps a loader of documents to record or replay remote documents
+func (s *Snapshot) pathLoader(next func(string) (json.RawMessage, error)) func(string) (json.RawMessage, error) {
+	return func(pth string) (json.RawMessage, error) {
+		if !isRemoteDocument(pth) {
+			return next(pth)
+		}
+
+		s.mx.Lock()
+		hash, known := s.Documents[pth]
+		s.mx.Unlock()
+
+		if s.Replay {
+			if !known {
+				return nil, fmt.Errorf("document %s is not in the snapshot", pth)
+			}
+
+			return s.Store.Get(hash)
+		}
+
+		data, err := next(pth)
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err = s.Store.Put(data)
+		if err != nil {
+			return nil, err
+		}
+
+		s.mx.Lock()
+		if s.Documents == nil {
+			s.Documents = make(map[string]string)
+		}
+		s.Documents[pth] = hash
+		s.mx.Unlock()
+
+		return data, nil
+	}
+}
+
+func isRemoteDocument(pth string) bool {
+	u, err := url.Parse(pth)
+
+	return err == nil && u.Scheme != "" && u.Scheme != "file" && len(u.Scheme) > 1 // skip windows drive letters
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return snapshotHashPrefix + hex.EncodeToString(sum[:])
+}
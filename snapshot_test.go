@@ -0,0 +1,86 @@
+package analysis
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_RecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	var served int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/pet.json" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		served++
+		_, _ = w.Write([]byte(`{"definitions": {"pet": {"type": "object", "properties": {"name": {"type": "string"}}}}}`))
+	}))
+	petRef := server.URL + "/models/pet.json#/definitions/pet"
+
+	newSpec := func() *spec.Swagger {
+		return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			Definitions: spec.Definitions{
+				"pets": *spec.ArrayProperty(spec.RefSchema(petRef)),
+			},
+		}}
+	}
+
+	store := DirSnapshotStore{Dir: t.TempDir()}
+	snapshot := NewSnapshot(store)
+
+	sp := newSpec()
+	require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), Minimal: true, Snapshot: snapshot}))
+	require.Contains(t, sp.Definitions, "pet")
+	require.Len(t, snapshot.Documents, 1)
+	require.Contains(t, snapshot.Documents, server.URL+"/models/pet.json")
+	require.Positive(t, served)
+
+	var index bytes.Buffer
+	require.NoError(t, snapshot.WriteIndex(&index))
+
+	server.Close()
+
+	t.Run("should replay from the snapshot", func(t *testing.T) {
+		replay, err := ReadSnapshot(bytes.NewReader(index.Bytes()), store)
+		require.NoError(t, err)
+		assert.True(t, replay.Replay)
+
+		sp := newSpec()
+		require.NoError(t, Flatten(FlattenOpts{Spec: New(sp), Minimal: true, Snapshot: replay}))
+		assert.Contains(t, sp.Definitions["pet"].Properties, "name")
+	})
+
+	t.Run("should fail to replay unknown documents", func(t *testing.T) {
+		replay := NewSnapshot(&MemorySnapshotStore{})
+		replay.Replay = true
+
+		require.Error(t, Flatten(FlattenOpts{Spec: New(newSpec()), Minimal: true, Snapshot: replay}))
+	})
+}
+
+func TestSnapshot_MemoryStore(t *testing.T) {
+	t.Parallel()
+
+	store := &MemorySnapshotStore{}
+	hash, err := store.Put([]byte("foo"))
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", hash)
+
+	data, err := store.Get(hash)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("foo"), data)
+
+	_, err = store.Get("sha256:none")
+	require.Error(t, err)
+}
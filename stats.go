@@ -0,0 +1,119 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// Stats are counts and size metrics about a spec
+type Stats struct {
+	Paths int `json:"paths"`
+
+	// Operations counts the operations for each (upper case) method
+	Operations map[string]int `json:"operations"`
+
+	Definitions int `json:"definitions"`
+
+	// MaxSchemaDepth is the deepest nesting of schemas (properties, items, allOf, ...) in a single schema,
+	// not following $ref's. A schema without any child has depth 1.
+	MaxSchemaDepth int `json:"maxSchemaDepth"`
+
+	// InlineSchemas counts the schemas declared in place which are objects or compositions of schemas
+	// (i.e. the schemas Flatten would lift as new definitions)
+	InlineSchemas int `json:"inlineSchemas"`
+
+	// Refs counts all $ref's
+	Refs int `json:"refs"`
+
+	// RefFanOut counts the $ref's pointing to each target (e.g. "#/definitions/pet")
+	RefFanOut map[string]int `json:"refFanOut"`
+
+	// MaxRefFanOut is the largest number of $ref's pointing to the same target
+	MaxRefFanOut int `json:"maxRefFanOut"`
+
+	// ExternalFiles counts the distinct remote documents which are referred to
+	ExternalFiles int `json:"externalFiles"`
+}
+
+// TotalOperations counts all the operations of a spec
+func (st Stats) TotalOperations() int {
+	total := 0
+	for _, count := range st.Operations {
+		total += count
+	}
+
+	return total
+}
+
+// Stats computes counts and size metrics about a spec
+func (s *Spec) Stats() Stats {
+	stats := Stats{
+		Operations: make(map[string]int, len(s.operations)),
+		RefFanOut:  make(map[string]int),
+	}
+
+	if s.spec.Paths != nil {
+		stats.Paths = len(s.spec.Paths.Paths)
+	}
+	stats.Definitions = len(s.spec.Definitions)
+
+	for method, operations := range s.operations {
+		stats.Operations[method] = len(operations)
+	}
+
+	files := make(map[string]bool)
+	for _, ref := range s.references.allRefs {
+		stats.Refs++
+
+		target := ref.String()
+		stats.RefFanOut[target]++
+		if stats.RefFanOut[target] > stats.MaxRefFanOut {
+			stats.MaxRefFanOut = stats.RefFanOut[target]
+		}
+
+		if !ref.HasFragmentOnly {
+			document, _, _ := strings.Cut(target, "#")
+			files[document] = true
+		}
+	}
+	stats.ExternalFiles = len(files)
+
+	root := "" // schemas are visited parents first: the depth is measured from the root of each schema
+	walkSchemas(s.spec, func(pointer string, schema *spec.Schema) {
+		if parent, _ := splitPointer(pointer); parent != definitionsPath && isInlineComplexSchema(schema) {
+			stats.InlineSchemas++
+		}
+
+		if root != "" && strings.HasPrefix(pointer, root+"/") {
+			return
+		}
+		root = pointer
+
+		if depth := schemaDepth(schema); depth > stats.MaxSchemaDepth {
+			stats.MaxSchemaDepth = depth
+		}
+	})
+
+	return stats
+}
+
+func isInlineComplexSchema(schema *spec.Schema) bool {
+	if schema.Ref.String() != "" {
+		return false
+	}
+
+	return len(schema.Properties) > 0 || len(schema.AllOf) > 0 || len(schema.AnyOf) > 0 || len(schema.OneOf) > 0 ||
+		(schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil)
+}
+
+func schemaDepth(schema *spec.Schema) int {
+	depth := 0
+	forEachSubSchema(schema, func(_ string, child *spec.Schema) {
+		if childDepth := schemaDepth(child); childDepth > depth {
+			depth = childDepth
+		}
+	})
+
+	return depth + 1
+}
@@ -0,0 +1,33 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpec_Stats(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "stats", "spec.yml")))
+	stats := an.Stats()
+
+	assert.Equal(t, 2, stats.Paths)
+	assert.Equal(t, map[string]int{"GET": 2, "POST": 1}, stats.Operations)
+	assert.Equal(t, 3, stats.TotalOperations())
+	assert.Equal(t, 2, stats.Definitions)
+	assert.Equal(t, 4, stats.MaxSchemaDepth)
+	assert.Equal(t, 2, stats.InlineSchemas)
+	assert.Equal(t, 7, stats.Refs)
+	assert.Equal(t, 4, stats.RefFanOut["#/definitions/pet"])
+	assert.Equal(t, 4, stats.MaxRefFanOut)
+	assert.Equal(t, 2, stats.ExternalFiles)
+
+	empty := New(&spec.Swagger{}).Stats()
+	assert.Zero(t, empty.Paths)
+	assert.Zero(t, empty.TotalOperations())
+	assert.Zero(t, empty.MaxSchemaDepth)
+}
@@ -0,0 +1,144 @@
+package analysis
+
+import (
+	"fmt"
+	slashpath "path"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// Codes of the findings of StructuralIssues
+const (
+	// CodeUndeclaredPathParam is the code for findings about parameters of a path template not declared
+	// by an operation
+	CodeUndeclaredPathParam = "undeclared-path-param"
+
+	// CodeUnusedPathParam is the code for findings about path parameters absent from the path template
+	CodeUnusedPathParam = "unused-path-param"
+
+	// CodeBodyAndFormData is the code for findings about operations with both body and formData parameters
+	CodeBodyAndFormData = "body-and-form-data"
+
+	// CodeMultipleBodyParams is the code for findings about operations with several body parameters
+	CodeMultipleBodyParams = "multiple-body-params"
+
+	// CodeNoSuccessResponse is the code for findings about operations without any 2xx or default response
+	CodeNoSuccessResponse = "no-success-response"
+)
+
+// StructuralIssues performs cheap consistency checks on the operations of the spec:
+//   - parameters of the path template which are not declared as path parameters, and vice versa
+//   - operations with both a body and formData parameters, or with several body parameters
+//   - operations without any successful (2xx) or default response
+//
+// Parameters declared on path items apply to all their operations. This is not a full validation
+// of the spec (see github.com/go-openapi/validate). Findings are sorted by pointer.
+func (s *Spec) StructuralIssues() []Finding {
+	var findings []Finding
+	report := func(pointer, code, format string, args ...interface{}) {
+		findings = append(findings, Finding{Pointer: pointer, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.spec.Paths == nil {
+		return nil
+	}
+
+	for _, pth := range sortedKeys(s.spec.Paths.Paths) {
+		pathItem := s.spec.Paths.Paths[pth]
+		prefix := "#" + slashpath.Join("/paths", jsonpointer.Escape(pth))
+
+		templated := make(map[string]bool)
+		for _, name := range s.PathParamsOf(pth) {
+			templated[name] = true
+		}
+
+		unused := func(pointer string, params []spec.Parameter) {
+			for i, param := range params {
+				resolved, ok := s.resolveParam(param)
+				if ok && resolved.In == "path" && !templated[resolved.Name] {
+					report(slashpath.Join(pointer, "parameters", strconv.Itoa(i)), CodeUnusedPathParam,
+						"path parameter %q does not appear in the path template %s", resolved.Name, pth)
+				}
+			}
+		}
+		unused(prefix, pathItem.Parameters)
+
+		for _, method := range sortedOperationMethods(&pathItem) {
+			op := operationOf(&pathItem, method)
+			pointer := slashpath.Join(prefix, strings.ToLower(method))
+			unused(pointer, op.Parameters)
+
+			params := s.SafeParamsFor(method, pth, func(spec.Parameter, error) bool { return true })
+			declared := make(map[string]bool)
+			var bodies, forms int
+			for _, param := range params {
+				switch param.In {
+				case "path":
+					declared[param.Name] = true
+				case "body":
+					bodies++
+				case "formData":
+					forms++
+				}
+			}
+
+			for _, name := range s.PathParamsOf(pth) {
+				if !declared[name] {
+					report(pointer, CodeUndeclaredPathParam, "parameter {%s} of the path template is not declared", name)
+				}
+			}
+
+			if bodies > 0 && forms > 0 {
+				report(pointer, CodeBodyAndFormData, "operation has both body and formData parameters")
+			}
+
+			if bodies > 1 {
+				report(pointer, CodeMultipleBodyParams, "operation has %d body parameters", bodies)
+			}
+
+			if !hasSuccessResponse(op) {
+				report(pointer, CodeNoSuccessResponse, "operation has no successful (2xx) or default response")
+			}
+		}
+	}
+	sortFindings(findings)
+
+	return findings
+}
+
+// resolveParam resolves a parameter which may be a $ref to a shared parameter
+func (s *Spec) resolveParam(param spec.Parameter) (spec.Parameter, bool) {
+	if param.Ref.String() == "" {
+		return param, true
+	}
+
+	obj, _, err := param.Ref.GetPointer().Get(s.spec)
+	if err != nil {
+		return param, false
+	}
+
+	resolved, ok := obj.(spec.Parameter)
+
+	return resolved, ok
+}
+
+func hasSuccessResponse(op *spec.Operation) bool {
+	if op.Responses == nil {
+		return false
+	}
+
+	if op.Responses.Default != nil {
+		return true
+	}
+
+	for code := range op.Responses.StatusCodeResponses {
+		if code >= 200 && code < 300 {
+			return true
+		}
+	}
+
+	return false
+}
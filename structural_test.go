@@ -0,0 +1,39 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzer_StructuralIssues(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "structural.yml")))
+
+	type result struct {
+		Pointer string
+		Code    string
+	}
+
+	findings := an.StructuralIssues()
+	actual := make([]result, 0, len(findings))
+	for _, finding := range findings {
+		assert.NotEmpty(t, finding.Message)
+		actual = append(actual, result{Pointer: finding.Pointer, Code: finding.Code})
+	}
+
+	assert.Equal(t, []result{
+		{Pointer: "#/paths/~1pets/post", Code: CodeBodyAndFormData},
+		{Pointer: "#/paths/~1pets/post", Code: CodeMultipleBodyParams},
+		{Pointer: "#/paths/~1pets/post", Code: CodeNoSuccessResponse},
+		{Pointer: "#/paths/~1pets~1{id}/delete", Code: CodeUndeclaredPathParam},
+		{Pointer: "#/paths/~1pets~1{id}/parameters/0", Code: CodeUnusedPathParam},
+		{Pointer: "#/paths/~1stores~1{storeId}~1{section}/get", Code: CodeUndeclaredPathParam},
+	}, actual)
+
+	assert.Empty(t, New(&spec.Swagger{}).StructuralIssues())
+}
@@ -0,0 +1,158 @@
+package analysis
+
+import (
+	"fmt"
+	slashpath "path"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// Codes for findings about date and time fields
+const (
+	CodeMissingTimeFormat    = "missing-time-format"
+	CodeMixedTimeConventions = "mixed-time-conventions"
+)
+
+const (
+	timeConventionFormatted = "formatted"
+	timeConventionUnixEpoch = "unix-epoch"
+	timeFormatDate          = "date"
+	timeFormatDateTime      = "date-time"
+	timeFormatDuration      = "duration"
+)
+
+// TimeFormatAudit is a consistency report about date, time and duration fields in a spec
+type TimeFormatAudit struct {
+	// Formatted maps the JSON pointer of every string schema or parameter with a date, date-time
+	// or duration format to this format
+	Formatted map[string]string
+
+	// Epochs lists the JSON pointers of numeric fields which look like timestamps, judging by their name
+	// (e.g. "createdAt" as an integer), and are thus assumed to be unix epochs
+	Epochs []string
+
+	// Findings reports strings which look like timestamps but lack a format, and the mix of
+	// formatted timestamps and unix epochs in the same spec
+	Findings []Finding
+}
+
+// Conventions counts the fields following each convention for timestamps: "formatted" strings
+// (date, date-time) or "unix-epoch" numbers
+func (a TimeFormatAudit) Conventions() map[string]int {
+	formatted := 0
+	for _, format := range a.Formatted {
+		if format == timeFormatDate || format == timeFormatDateTime {
+			formatted++
+		}
+	}
+
+	return map[string]int{
+		timeConventionFormatted: formatted,
+		timeConventionUnixEpoch: len(a.Epochs),
+	}
+}
+
+// TimeFormats indexes all date, date-time and duration fields (schemas and parameters),
+// and reports inconsistent conventions for timestamps.
+func (s *Spec) TimeFormats() TimeFormatAudit {
+	audit := TimeFormatAudit{
+		Formatted: make(map[string]string),
+	}
+
+	for _, key := range sortedKeys(s.allSchemas) {
+		sch := s.allSchemas[key]
+		if sch.Schema == nil || sch.Schema.Ref.String() != "" {
+			continue
+		}
+
+		audit.auditField(key, fieldNameFromPointer(key), sch.Schema.Type, sch.Schema.Format)
+	}
+
+	walkParameters(s.spec, func(pointer string, param *spec.Parameter) {
+		if param.In == "body" || param.Ref.String() != "" {
+			return
+		}
+
+		audit.auditField(pointer, param.Name, spec.StringOrArray{param.Type}, param.Format)
+	})
+
+	conventions := audit.Conventions()
+	if conventions[timeConventionFormatted] > 0 && conventions[timeConventionUnixEpoch] > 0 {
+		audit.Findings = append(audit.Findings, Finding{
+			Pointer: "#",
+			Code:    CodeMixedTimeConventions,
+			Message: fmt.Sprintf("timestamps are represented both as formatted strings (%d) and as unix epochs (%d)",
+				conventions[timeConventionFormatted], conventions[timeConventionUnixEpoch]),
+		})
+	}
+
+	sortFindings(audit.Findings)
+
+	return audit
+}
+
+func (a *TimeFormatAudit) auditField(pointer, name string, types spec.StringOrArray, format string) {
+	switch {
+	case types.Contains("string") && isTimeFormat(format):
+		a.Formatted[pointer] = format
+	case types.Contains("string") && format == "" && looksLikeTimestamp(name):
+		a.Findings = append(a.Findings, Finding{
+			Pointer: pointer,
+			Code:    CodeMissingTimeFormat,
+			Message: fmt.Sprintf("%q looks like a timestamp, but has no date or date-time format", name),
+		})
+	case (types.Contains("integer") || types.Contains("number")) && looksLikeTimestamp(name):
+		a.Epochs = append(a.Epochs, pointer)
+	}
+}
+
+func isTimeFormat(format string) bool {
+	switch format {
+	case timeFormatDate, timeFormatDateTime, timeFormatDuration:
+		return true
+	default:
+		return false
+	}
+}
+
+// fieldNameFromPointer yields the name of a property from its JSON pointer, or an empty string
+// if the pointer does not designate a property.
+func fieldNameFromPointer(pointer string) string {
+	parent := slashpath.Base(slashpath.Dir(pointer))
+	if parent != "properties" {
+		return ""
+	}
+
+	return jsonpointer.Unescape(slashpath.Base(pointer))
+}
+
+// looksLikeTimestamp guesses from its name whether a field holds a timestamp, e.g. "createdAt",
+// "expiry_date", "lastUpdated".
+func looksLikeTimestamp(name string) bool {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return false
+	}
+
+	last := words[len(words)-1]
+	if (last == "at" || last == "on") && len(words) > 1 {
+		return true
+	}
+
+	for _, word := range words {
+		switch word {
+		case "zone", "timezone", "timeout", "format":
+			return false
+		}
+	}
+
+	for _, word := range words {
+		switch word {
+		case "date", "time", "timestamp", "datetime", "created", "updated", "modified", "expires", "expiry":
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeFormats_Audit(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "time-formats.yml")))
+	audit := an.TimeFormats()
+
+	assert.Equal(t, map[string]string{
+		"#/definitions/event/properties/birthDate": "date",
+		"#/definitions/event/properties/createdAt": "date-time",
+		"#/definitions/event/properties/ttl":       "duration",
+		"#/paths/~1events/get/parameters/0":        "date-time",
+	}, audit.Formatted)
+
+	assert.Equal(t, []string{"#/definitions/event/properties/expires_at"}, audit.Epochs)
+	assert.Equal(t, map[string]int{"formatted": 3, "unix-epoch": 1}, audit.Conventions())
+
+	require.Len(t, audit.Findings, 3)
+	assert.Equal(t, Finding{
+		Pointer: "#",
+		Code:    CodeMixedTimeConventions,
+		Message: "timestamps are represented both as formatted strings (3) and as unix epochs (1)",
+	}, audit.Findings[0])
+	assert.Equal(t, "#/definitions/event/properties/updatedAt", audit.Findings[1].Pointer)
+	assert.Equal(t, CodeMissingTimeFormat, audit.Findings[1].Code)
+	assert.Equal(t, "#/paths/~1events/get/parameters/1", audit.Findings[2].Pointer)
+}
+
+func TestTimeFormats_LooksLikeTimestamp(t *testing.T) {
+	t.Parallel()
+
+	for name, expected := range map[string]bool{
+		"createdAt":    true,
+		"updated_at":   true,
+		"publishedOn":  true,
+		"expiryDate":   true,
+		"timestamp":    true,
+		"lastModified": true,
+		"at":           false,
+		"timeZone":     false,
+		"timeout":      false,
+		"dateFormat":   false,
+		"name":         false,
+	} {
+		assert.Equalf(t, expected, looksLikeTimestamp(name), "unexpected guess for %q", name)
+	}
+}
@@ -0,0 +1,165 @@
+package analysis
+
+import (
+	"fmt"
+	slashpath "path"
+
+	"github.com/go-openapi/spec"
+)
+
+// CodeMissingCurrency is the code for findings about monetary amounts without currency metadata
+const CodeMissingCurrency = "missing-currency"
+
+// default settings for UnitOpts
+const (
+	defaultUnitExtension     = "x-unit"
+	defaultCurrencyExtension = "x-currency"
+)
+
+var defaultMoneyNames = []string{
+	"amount", "balance", "charge", "cost", "fee", "fees", "price", "revenue", "salary", "tax", "total",
+}
+
+// UnitOpts configures the analysis of units and currencies
+type UnitOpts struct {
+	UnitExtension     string   // the extension declaring the unit of a field. Defaults to "x-unit"
+	CurrencyExtension string   // the extension declaring the currency of a field. Defaults to "x-currency"
+	MoneyNames        []string // words denoting a monetary amount in a field name. Defaults to "price", "amount", "cost", ...
+
+	_ struct{}
+}
+
+func (o UnitOpts) withDefaults() UnitOpts {
+	if o.UnitExtension == "" {
+		o.UnitExtension = defaultUnitExtension
+	}
+
+	if o.CurrencyExtension == "" {
+		o.CurrencyExtension = defaultCurrencyExtension
+	}
+
+	if len(o.MoneyNames) == 0 {
+		o.MoneyNames = defaultMoneyNames
+	}
+
+	return o
+}
+
+// UnitReport maps fields to their declared units and currencies
+type UnitReport struct {
+	Units      map[string]string // JSON pointer to unit, for every schema or parameter declaring a unit
+	Currencies map[string]string // JSON pointer to currency, for every schema or parameter declaring a currency
+
+	// Findings reports numeric fields with a money-like name (e.g. "price", "totalAmount") lacking currency metadata.
+	//
+	// A field is considered to be covered when it declares a currency, or when it has a sibling property named "currency".
+	Findings []Finding
+}
+
+// Units aggregates the units and currencies declared with vendor extensions on schemas and parameters,
+// and flags monetary amounts which do not declare any currency.
+func (s *Spec) Units(opts UnitOpts) UnitReport {
+	opts = opts.withDefaults()
+	report := UnitReport{
+		Units:      make(map[string]string),
+		Currencies: make(map[string]string),
+	}
+
+	for _, key := range sortedKeys(s.allSchemas) {
+		sch := s.allSchemas[key].Schema
+		if sch == nil || sch.Ref.String() != "" {
+			continue
+		}
+
+		name := fieldNameFromPointer(key)
+		covered := report.collect(key, sch.Extensions, opts) || s.hasSiblingCurrency(key)
+		report.checkMoney(key, name, sch.Type, covered, opts)
+	}
+
+	walkParameters(s.spec, func(pointer string, param *spec.Parameter) {
+		if param.In == "body" || param.Ref.String() != "" {
+			return
+		}
+
+		covered := report.collect(pointer, param.Extensions, opts)
+		report.checkMoney(pointer, param.Name, spec.StringOrArray{param.Type}, covered, opts)
+	})
+
+	sortFindings(report.Findings)
+
+	return report
+}
+
+// collect records unit and currency extensions, and tells if a currency is declared
+func (r *UnitReport) collect(pointer string, extensions spec.Extensions, opts UnitOpts) bool {
+	if unit, ok := extensionAsString(extensions, opts.UnitExtension); ok {
+		r.Units[pointer] = unit
+	}
+
+	currency, ok := extensionAsString(extensions, opts.CurrencyExtension)
+	if ok {
+		r.Currencies[pointer] = currency
+	}
+
+	return ok
+}
+
+func (r *UnitReport) checkMoney(pointer, name string, types spec.StringOrArray, covered bool, opts UnitOpts) {
+	if covered || name == "" || !(types.Contains("number") || types.Contains("integer")) {
+		return
+	}
+
+	for _, word := range splitWords(name) {
+		for _, money := range opts.MoneyNames {
+			if word != money {
+				continue
+			}
+
+			r.Findings = append(r.Findings, Finding{
+				Pointer: pointer,
+				Code:    CodeMissingCurrency,
+				Message: fmt.Sprintf("%q looks like a monetary amount, but declares no %s", name, opts.CurrencyExtension),
+			})
+
+			return
+		}
+	}
+}
+
+// hasSiblingCurrency tells if a property has a sibling property named "currency"
+func (s *Spec) hasSiblingCurrency(pointer string) bool {
+	if fieldNameFromPointer(pointer) == "" {
+		return false
+	}
+
+	parent, ok := s.allSchemas[slashpath.Dir(slashpath.Dir(pointer))]
+	if !ok || parent.Schema == nil {
+		return false
+	}
+
+	for name := range parent.Schema.Properties {
+		for _, word := range splitWords(name) {
+			if word == "currency" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// extensionAsString retrieves the value of an extension as a string.
+//
+// Extension names are matched case-insensitively.
+func extensionAsString(extensions spec.Extensions, name string) (string, bool) {
+	raw, found := lookupExtension(extensions, name)
+	if !found || raw == nil {
+		return "", false
+	}
+
+	if value, isString := raw.(string); isString {
+		return value, true
+	}
+
+	return fmt.Sprintf("%v", raw), true
+}
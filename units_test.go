@@ -0,0 +1,56 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnits(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "units.yml")))
+	report := an.Units(UnitOpts{})
+
+	assert.Equal(t, map[string]string{
+		"#/definitions/money/properties/amount": "cents",
+		"#/definitions/order/properties/weight": "kg",
+		"#/paths/~1orders/get/parameters/1":     "kg",
+	}, report.Units)
+	assert.Equal(t, map[string]string{
+		"#/definitions/order/properties/shippingFee": "EUR",
+	}, report.Currencies)
+
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, Finding{
+		Pointer: "#/definitions/order/properties/totalAmount",
+		Code:    CodeMissingCurrency,
+		Message: `"totalAmount" looks like a monetary amount, but declares no x-currency`,
+	}, report.Findings[0])
+	assert.Equal(t, "#/paths/~1orders/get/parameters/0", report.Findings[1].Pointer)
+}
+
+func TestUnits_Options(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "units.yml")))
+	report := an.Units(UnitOpts{CurrencyExtension: "x-unit", MoneyNames: []string{"weight"}})
+
+	assert.Len(t, report.Currencies, 3)
+	assert.Empty(t, report.Findings)
+}
+
+func TestUnits_CaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	an := New(antest.LoadOrFail(t, filepath.Join("fixtures", "units.yml")))
+	assert.Equal(t, an.Units(UnitOpts{}).Units, an.Units(UnitOpts{UnitExtension: "X-Unit"}).Units)
+
+	currency, ok := extensionAsString(spec.Extensions{"X-Currency": "EUR"}, "x-currency")
+	require.True(t, ok)
+	assert.Equal(t, "EUR", currency)
+}
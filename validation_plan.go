@@ -0,0 +1,153 @@
+package analysis
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/go-openapi/spec"
+)
+
+// ValidationPlan lists what should be checked to validate the requests of an operation, so request validators
+// need not walk the spec on every call
+type ValidationPlan struct {
+	Operation OperationKey
+
+	// Parameters are the checks of the parameters which are not the payload, sorted by location then by name
+	Parameters []ParameterCheck
+
+	// Body is the payload accepted by the operation, nil when it accepts none (see Spec.BodySchemaFor).
+	// Its schema is the effective schema of the payload (see EffectiveSchema).
+	Body *BodySchema
+
+	// Required lists the keys (e.g. "query#limit") of the required parameters, including the payload, sorted
+	Required []string
+
+	// Patterns maps every pattern found in the parameters and the payload to its compiled regular expression
+	Patterns map[string]*regexp.Regexp
+
+	// Consumes lists the media types accepted for the payload
+	Consumes []string
+}
+
+// ParameterCheck describes how a parameter of a request is checked
+type ParameterCheck struct {
+	Name string
+	In   string
+
+	Required        bool
+	AllowEmptyValue bool
+
+	// Coerce tells if the value of the parameter is not a plain string, and must be converted before it is validated:
+	// see Serialization for the type of the values
+	Coerce bool
+
+	Serialization Serialization
+
+	// Schema holds the type and the validations of the parameter, and of its items
+	Schema *spec.Schema
+}
+
+// ValidationPlanFor prepares the validation of the requests of an operation, including the parameters
+// of its path item: the parameters which need coercion, the patterns to compile, the schema of the payload
+// and the required parameters.
+//
+// This returns nil when the operation does not belong to the spec. Patterns which cannot be compiled as
+// Go regular expressions are errors (see Spec.CompiledPatterns).
+func (s *Spec) ValidationPlanFor(op *spec.Operation) (*ValidationPlan, error) {
+	key, found := s.operationKeyOf(op)
+	if !found {
+		return nil, nil
+	}
+
+	params, err := s.operationParams(op)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ValidationPlan{
+		Operation: key,
+		Patterns:  make(map[string]*regexp.Regexp),
+		Consumes:  s.ConsumesFor(op),
+	}
+
+	for i := range params {
+		param := &params[i]
+		if param.In == "body" || param.In == "formData" {
+			continue
+		}
+
+		serialization := SerializationFor(param)
+		check := ParameterCheck{
+			Name:            param.Name,
+			In:              param.In,
+			Required:        param.Required,
+			AllowEmptyValue: param.AllowEmptyValue,
+			Coerce:          serialization.Kind != SerializationScalar || param.Type != "string",
+			Serialization:   serialization,
+			Schema:          simpleSchemaOf(param.SimpleSchema, param.CommonValidations),
+		}
+		plan.Parameters = append(plan.Parameters, check)
+
+		if param.Required {
+			plan.Required = append(plan.Required, param.In+"#"+param.Name)
+		}
+
+		if err := plan.compilePatterns(key.pointer(), check.Schema); err != nil {
+			return nil, err
+		}
+	}
+
+	if plan.Body, err = s.BodySchemaFor(op); err != nil {
+		return nil, err
+	}
+
+	if plan.Body == nil {
+		return plan, nil
+	}
+
+	effective, _, err := EffectiveSchema(plan.Body.Schema, s.spec, "")
+	if err != nil {
+		return nil, err
+	}
+	plan.Body.Schema = effective
+
+	switch plan.Body.In {
+	case "body":
+		if plan.Body.Required {
+			plan.Required = append(plan.Required, "body#"+plan.Body.Name)
+		}
+	case "formData":
+		for _, name := range effective.Required {
+			plan.Required = append(plan.Required, "formData#"+name)
+		}
+	}
+	sort.Strings(plan.Required)
+
+	return plan, plan.compilePatterns(key.pointer(), effective)
+}
+
+// compilePatterns compiles the patterns of a schema and its subschemas, which belong to an operation
+func (p *ValidationPlan) compilePatterns(pointer string, schema *spec.Schema) error {
+	if schema == nil {
+		return nil
+	}
+
+	if pattern := schema.Pattern; pattern != "" {
+		if _, compiled := p.Patterns[pattern]; !compiled {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return &SchemaError{Pointer: pointer, Cause: err}
+			}
+			p.Patterns[pattern] = re
+		}
+	}
+
+	var err error
+	forEachSubSchema(schema, func(_ string, child *spec.Schema) {
+		if err == nil {
+			err = p.compilePatterns(pointer, child)
+		}
+	})
+
+	return err
+}
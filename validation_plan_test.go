@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationPlanFor(t *testing.T) {
+	t.Parallel()
+
+	s := New(antest.LoadOrFail(t, filepath.Join("fixtures", "validation_plan.yml")))
+
+	t.Run("should plan the validation of parameters and body", func(t *testing.T) {
+		t.Parallel()
+
+		op, ok := s.OperationFor("PUT", "/pets/{id}")
+		require.True(t, ok)
+
+		plan, err := s.ValidationPlanFor(op)
+		require.NoError(t, err)
+		require.NotNil(t, plan)
+
+		assert.Equal(t, OperationKey{Method: "PUT", Path: "/pets/{id}"}, plan.Operation)
+		assert.Equal(t, []string{"application/json"}, plan.Consumes)
+		assert.Equal(t, []string{"body#pet", "header#X-Tenant", "path#id"}, plan.Required)
+
+		require.Len(t, plan.Parameters, 4)
+		tenant, id, dryRun, tags := plan.Parameters[0], plan.Parameters[1], plan.Parameters[2], plan.Parameters[3]
+
+		assert.Equal(t, "X-Tenant", tenant.Name)
+		assert.True(t, tenant.Required)
+		assert.False(t, tenant.Coerce)
+		assert.Equal(t, "^[a-z]+$", tenant.Schema.Pattern)
+
+		assert.Equal(t, "id", id.Name)
+		assert.True(t, id.Coerce)
+		assert.Equal(t, Serialization{Kind: SerializationScalar, ItemType: "integer", ItemFormat: "int64"}, id.Serialization)
+
+		assert.Equal(t, "dryRun", dryRun.Name)
+		assert.True(t, dryRun.AllowEmptyValue)
+		assert.True(t, dryRun.Coerce)
+
+		assert.Equal(t, "tags", tags.Name)
+		assert.True(t, tags.Coerce)
+		assert.Equal(t, "|", tags.Serialization.Separator)
+		require.NotNil(t, tags.Schema.Items)
+		assert.Equal(t, "^[a-z]+$", tags.Schema.Items.Schema.Pattern)
+
+		require.NotNil(t, plan.Body)
+		assert.Equal(t, "pet", plan.Body.Name)
+		assert.Contains(t, plan.Body.Schema.Properties["owner"].Properties, "email") // $ref's are inlined
+
+		assert.Len(t, plan.Patterns, 3)
+		for _, pattern := range []string{"^[a-z]+$", "^[A-Z]", "@"} {
+			require.Contains(t, plan.Patterns, pattern)
+			assert.Equal(t, pattern, plan.Patterns[pattern].String())
+		}
+	})
+
+	t.Run("should plan the validation of forms", func(t *testing.T) {
+		t.Parallel()
+
+		op, ok := s.OperationFor("POST", "/pets/{id}/photo")
+		require.True(t, ok)
+
+		plan, err := s.ValidationPlanFor(op)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"multipart/form-data"}, plan.Consumes)
+		assert.Equal(t, []string{"formData#photo", "path#id"}, plan.Required)
+		require.Len(t, plan.Parameters, 1)
+		require.NotNil(t, plan.Body)
+		assert.Equal(t, "formData", plan.Body.In)
+		assert.Contains(t, plan.Patterns, `^\w+$`)
+	})
+
+	t.Run("should fail on invalid patterns", func(t *testing.T) {
+		t.Parallel()
+
+		op, ok := s.OperationFor("GET", "/invalid")
+		require.True(t, ok)
+
+		_, err := s.ValidationPlanFor(op)
+		var schemaErr *SchemaError
+		require.ErrorAs(t, err, &schemaErr)
+		assert.Equal(t, "#/paths/~1invalid/get", schemaErr.Pointer)
+	})
+
+	t.Run("should ignore unknown operations", func(t *testing.T) {
+		t.Parallel()
+
+		plan, err := s.ValidationPlanFor(&spec.Operation{})
+		require.NoError(t, err)
+		assert.Nil(t, plan)
+	})
+}
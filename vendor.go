@@ -0,0 +1,173 @@
+package analysis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"net/url"
+	"os"
+	slashpath "path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const vendorExternalDir = "external"
+
+// VendorOpts configures the vendoring of the external documents referenced by a spec with VendorRefs
+type VendorOpts struct {
+	// BasePath is the location of the spec, used to resolve relative remote $ref's
+	BasePath string
+
+	// Dir is the directory where external documents are copied. A relative directory is resolved
+	// from the directory of the spec.
+	Dir string
+
+	// FS is the file system to read local documents from. Defaults to the OS file system
+	FS fs.FS
+
+	/* Extra keys */
+	_ struct{} // require keys
+}
+
+// VendorRefs copies all the external documents referenced by a spec, directly or transitively, into a local
+// directory, and rewrites the remote $ref's to relative paths to these copies. Unlike flattening, no
+// schema is inlined: the multi-file structure of the spec is retained.
+//
+// Documents are laid out in the vendor directory as follows:
+//   - local files located under the directory of the spec keep their relative path;
+//   - remote documents are stored under their host and path (e.g. "example.com/schemas/pet.json");
+//   - other local files are stored under "external", with a prefix telling apart files with the same name.
+//
+// Documents already located in the vendor directory are left in place, so vendoring is idempotent.
+// Documents are written as JSON, which is valid YAML: the original file names are kept.
+//
+// The spec is modified in place. VendorRefs returns the vendored files, indexed by the location of the original documents.
+func VendorRefs(sp *spec.Swagger, opts VendorOpts) (map[string]string, error) {
+	if sp == nil {
+		return nil, nil
+	}
+
+	base := absoluteBasePath(opts.BasePath)
+	baseDir := filepath.Dir(base)
+	dir := opts.Dir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(baseDir, dir)
+	}
+
+	loader := spec.PathLoader
+	if opts.FS != nil {
+		loader = fsPathLoader(opts.FS)
+	}
+
+	dependencies := newSpec(sp).ExternalDependencies(DependencyOpts{BasePath: opts.BasePath, FS: opts.FS})
+	vendored := make(map[string]string, len(dependencies))
+	for _, dependency := range dependencies {
+		if !dependency.Reachable {
+			return nil, &RefError{Pointer: dependency.ReferencedFrom[0], Ref: dependency.Document, Cause: dependency.Err}
+		}
+
+		vendored[dependency.Document] = filepath.Join(dir, filepath.FromSlash(vendoredName(baseDir, dir, dependency.Document)))
+	}
+
+	for _, dependency := range dependencies {
+		raw, err := loader(dependency.Document)
+		if err != nil {
+			return nil, &RefError{Ref: dependency.Document, Cause: err}
+		}
+
+		var doc interface{}
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+
+		file := vendored[dependency.Document]
+		rewriteJSONRefs(doc, vendoredRef(dependency.Document, filepath.Dir(file), vendored))
+
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			return nil, err
+		}
+	}
+
+	return vendored, rewriteSpecRefs(sp, vendoredRef(base, baseDir, vendored))
+}
+
+// vendoredName tells where an external document is copied, relative to the vendor directory
+func vendoredName(baseDir, dir, document string) string {
+	if u, err := url.Parse(document); err == nil && u.Scheme != "" && u.Scheme != "file" {
+		return slashpath.Join(u.Host, slashpath.Clean("/"+u.Path))
+	}
+
+	document = strings.TrimPrefix(document, "file://")
+	for _, root := range []string{dir, baseDir} {
+		if rel, err := filepath.Rel(root, document); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	sum := sha256.Sum256([]byte(document))
+
+	return slashpath.Join(vendorExternalDir, hex.EncodeToString(sum[:4])+"-"+filepath.Base(document))
+}
+
+// vendoredRef rewrites the remote $ref's found in some document to refer to the vendored copies,
+// relative to the directory where this document is located
+func vendoredRef(document, dir string, vendored map[string]string) func(string) string {
+	return func(ref string) string {
+		target, fragment := refDocument(document, ref)
+		file, isVendored := vendored[target]
+		if !isVendored || target == document {
+			return ref
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return ref
+		}
+
+		if fragment == "" {
+			return filepath.ToSlash(rel)
+		}
+
+		return filepath.ToSlash(rel) + "#" + fragment
+	}
+}
+
+// rewriteSpecRefs replaces all the $ref's found in a spec
+func rewriteSpecRefs(sp *spec.Swagger, rewrite func(string) string) error {
+	raw, err := json.Marshal(sp)
+	if err != nil {
+		return err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+
+	rewriteJSONRefs(doc, rewrite)
+
+	if raw, err = json.Marshal(doc); err != nil {
+		return err
+	}
+
+	var rewritten spec.Swagger
+	if err := json.Unmarshal(raw, &rewritten); err != nil {
+		return err
+	}
+
+	*sp = rewritten
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package analysis
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVendorRefs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api")
+	common := filepath.Join(dir, "common")
+	require.NoError(t, os.MkdirAll(api, 0o755))
+	require.NoError(t, os.MkdirAll(common, 0o755))
+
+	data, err := os.ReadFile(filepath.Join("fixtures", "expand", "spec.yml"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(api, "spec.yml"), data, 0o600))
+
+	// models are shared with other specs, outside of the directory of the spec
+	data, err = os.ReadFile(filepath.Join("fixtures", "expand", "models.yml"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(common, "models.yml"), data, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(api, "paths.yml"), []byte(`
+owners:
+  get:
+    responses:
+      200:
+        description: owners
+        schema:
+          type: array
+          items:
+            $ref: '../common/models.yml#/definitions/tag'
+`), 0o600))
+
+	bp := filepath.Join(api, "spec.yml")
+	sp, err := antest.LoadSpec(bp)
+	require.NoError(t, err)
+	sp.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses[201].Schema.Ref = spec.MustCreateRef("../common/models.yml#/definitions/receipt")
+
+	vendored, err := VendorRefs(sp, VendorOpts{BasePath: bp, Dir: "vendor"})
+	require.NoError(t, err)
+
+	vendoredModels := vendored[filepath.Join(common, "models.yml")]
+	vendoredPaths := vendored[filepath.Join(api, "paths.yml")]
+	require.Len(t, vendored, 2)
+	assert.Equal(t, filepath.Join(api, "vendor", "paths.yml"), vendoredPaths)
+	assert.Equal(t, filepath.Join(api, "vendor", "external"), filepath.Dir(vendoredModels))
+	assert.Equal(t, "models.yml", filepath.Base(vendoredModels)[9:])
+
+	modelsRef := "external/" + filepath.Base(vendoredModels)
+	post := sp.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses[201].Schema.Ref
+	assert.Equal(t, "vendor/"+modelsRef+"#/definitions/receipt", post.String())
+	owners := sp.Paths.Paths["/owners"].Ref
+	assert.Equal(t, "vendor/paths.yml#/owners", owners.String())
+
+	var doc interface{}
+	data, err = os.ReadFile(vendoredPaths)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &doc))
+	var refs []string
+	walkJSONRefs(doc, "", func(_, ref string) { refs = append(refs, ref) })
+	assert.Equal(t, []string{modelsRef + "#/definitions/tag"}, refs)
+
+	// local $ref's are retained
+	data, err = os.ReadFile(vendoredModels)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &doc))
+	refs = nil
+	walkJSONRefs(doc, "", func(_, ref string) { refs = append(refs, ref) })
+	assert.Equal(t, []string{"#/definitions/tag"}, refs)
+
+	// the vendored spec is self-contained
+	require.NoError(t, os.RemoveAll(common))
+	expanded := CloneSpec(sp)
+	require.NoError(t, spec.ExpandSpec(expanded, &spec.ExpandOptions{RelativeBase: bp}))
+	assert.Equal(t, "string", expanded.Paths.Paths["/owners"].Get.Responses.StatusCodeResponses[200].Schema.Items.Schema.Type[0])
+
+	// vendoring is idempotent
+	again := CloneSpec(sp)
+	revendored, err := VendorRefs(again, VendorOpts{BasePath: bp, Dir: "vendor"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{vendoredModels: vendoredModels, vendoredPaths: vendoredPaths}, revendored)
+	assert.Equal(t, sp, again)
+}
+
+func TestVendorRefs_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	sp := antest.LoadOrFail(t, filepath.Join("fixtures", "expand", "spec.yml"))
+	sp.Definitions["missing"] = *spec.RefSchema("missing.yml#/definitions/nowhere")
+
+	_, err := VendorRefs(sp, VendorOpts{BasePath: filepath.Join("fixtures", "expand", "spec.yml"), Dir: t.TempDir()})
+	var refErr *RefError
+	require.ErrorAs(t, err, &refErr)
+	assert.Equal(t, "#/definitions/missing", refErr.Pointer)
+}
@@ -0,0 +1,329 @@
+package analysis
+
+import (
+	slashpath "path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/go-openapi/spec"
+)
+
+// schemaVisitor is called on every schema found when walking a document.
+//
+// The schema may be altered in place: schemas held in maps are written back after the visit.
+// The pointer follows the same conventions as the keys of the analyzer indexes.
+type schemaVisitor func(pointer string, schema *spec.Schema)
+
+// parameterVisitor is called on every parameter found when walking a document.
+type parameterVisitor func(pointer string, param *spec.Parameter)
+
+// walkSchemas visits all schemas in a swagger document, parents first.
+//
+// Keys of maps are visited in lexicographic order, so walking a document is deterministic.
+func walkSchemas(sp *spec.Swagger, visit schemaVisitor) {
+	if sp == nil {
+		return
+	}
+
+	for _, name := range sortedKeys(sp.Definitions) {
+		sch := sp.Definitions[name]
+		walkSchema(slashpath.Join("/definitions", jsonpointer.Escape(name)), &sch, visit)
+		sp.Definitions[name] = sch
+	}
+
+	walkParameters(sp, func(pointer string, param *spec.Parameter) {
+		if param.Schema != nil {
+			walkSchema(slashpath.Join(strings.TrimPrefix(pointer, "#"), "schema"), param.Schema, visit)
+		}
+	})
+
+	for _, name := range sortedKeys(sp.Responses) {
+		resp := sp.Responses[name]
+		if resp.Schema != nil {
+			walkSchema(slashpath.Join("/responses", jsonpointer.Escape(name), "schema"), resp.Schema, visit)
+		}
+		sp.Responses[name] = resp
+	}
+
+	walkOperations(sp, func(pointer string, op *spec.Operation) {
+		if op.Responses == nil {
+			return
+		}
+
+		if op.Responses.Default != nil && op.Responses.Default.Schema != nil {
+			walkSchema(slashpath.Join(pointer, "responses", "default", "schema"), op.Responses.Default.Schema, visit)
+		}
+
+		for _, code := range sortedStatusCodes(op.Responses.StatusCodeResponses) {
+			resp := op.Responses.StatusCodeResponses[code]
+			if resp.Schema != nil {
+				walkSchema(slashpath.Join(pointer, "responses", strconv.Itoa(code), "schema"), resp.Schema, visit)
+			}
+			op.Responses.StatusCodeResponses[code] = resp
+		}
+	})
+}
+
+// walkSchema visits a schema and all its children, parents first
+func walkSchema(pointer string, schema *spec.Schema, visit schemaVisitor) {
+	if schema == nil {
+		return
+	}
+
+	visit("#"+pointer, schema)
+
+	forEachSubSchema(schema, func(suffix string, child *spec.Schema) {
+		walkSchema(slashpath.Join(pointer, suffix), child, visit)
+	})
+}
+
+// forEachSubSchema calls a function on each direct child of a schema, with the relative JSON pointer
+// to the child (e.g. "properties/name").
+//
+// The children may be altered in place: schemas held in maps are written back after the call.
+func forEachSubSchema(schema *spec.Schema, fn func(suffix string, child *spec.Schema)) {
+	forEachSchemaInMap("definitions", schema.Definitions, fn)
+	forEachSchemaInMap("properties", schema.Properties, fn)
+	forEachSchemaInMap("patternProperties", schema.PatternProperties, fn)
+
+	for i := range schema.AllOf {
+		fn(slashpath.Join("allOf", strconv.Itoa(i)), &schema.AllOf[i])
+	}
+
+	for i := range schema.AnyOf {
+		fn(slashpath.Join("anyOf", strconv.Itoa(i)), &schema.AnyOf[i])
+	}
+
+	for i := range schema.OneOf {
+		fn(slashpath.Join("oneOf", strconv.Itoa(i)), &schema.OneOf[i])
+	}
+
+	if schema.Not != nil {
+		fn("not", schema.Not)
+	}
+
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		fn("additionalProperties", schema.AdditionalProperties.Schema)
+	}
+
+	if schema.AdditionalItems != nil && schema.AdditionalItems.Schema != nil {
+		fn("additionalItems", schema.AdditionalItems.Schema)
+	}
+
+	if schema.Items != nil {
+		if schema.Items.Schema != nil {
+			fn("items", schema.Items.Schema)
+		}
+
+		for i := range schema.Items.Schemas {
+			fn(slashpath.Join("items", strconv.Itoa(i)), &schema.Items.Schemas[i])
+		}
+	}
+}
+
+func forEachSchemaInMap(prefix string, schemas map[string]spec.Schema, fn func(string, *spec.Schema)) {
+	for _, name := range sortedKeys(schemas) {
+		sch := schemas[name]
+		fn(slashpath.Join(prefix, jsonpointer.Escape(name)), &sch)
+		schemas[name] = sch
+	}
+}
+
+// walkParameters visits all parameters in a swagger document: shared parameters, then
+// parameters declared on path items and operations.
+func walkParameters(sp *spec.Swagger, visit parameterVisitor) {
+	if sp == nil {
+		return
+	}
+
+	for _, name := range sortedKeys(sp.Parameters) {
+		param := sp.Parameters[name]
+		visit("#"+slashpath.Join("/parameters", jsonpointer.Escape(name)), &param)
+		sp.Parameters[name] = param
+	}
+
+	if sp.Paths == nil {
+		return
+	}
+
+	for _, pth := range sortedKeys(sp.Paths.Paths) {
+		pathItem := sp.Paths.Paths[pth]
+		prefix := slashpath.Join("/paths", jsonpointer.Escape(pth))
+
+		for i := range pathItem.Parameters {
+			visit("#"+slashpath.Join(prefix, "parameters", strconv.Itoa(i)), &pathItem.Parameters[i])
+		}
+
+		for _, method := range sortedOperationMethods(&pathItem) {
+			op := operationOf(&pathItem, method)
+			opPrefix := slashpath.Join(prefix, strings.ToLower(method))
+			for i := range op.Parameters {
+				visit("#"+slashpath.Join(opPrefix, "parameters", strconv.Itoa(i)), &op.Parameters[i])
+			}
+		}
+
+		sp.Paths.Paths[pth] = pathItem
+	}
+}
+
+// walkOperations visits all operations in a swagger document.
+//
+// The pointer passed to the visitor is the location of the operation (e.g. "/paths/~1pets/get"),
+// without a leading "#".
+func walkOperations(sp *spec.Swagger, visit func(pointer string, op *spec.Operation)) {
+	if sp == nil || sp.Paths == nil {
+		return
+	}
+
+	for _, pth := range sortedKeys(sp.Paths.Paths) {
+		pathItem := sp.Paths.Paths[pth]
+		for _, method := range sortedOperationMethods(&pathItem) {
+			visit(slashpath.Join("/paths", jsonpointer.Escape(pth), strings.ToLower(method)), operationOf(&pathItem, method))
+		}
+	}
+}
+
+// sortedOperationMethods yields the (upper case) methods of the operations defined on a path item,
+// in lexicographic order
+func sortedOperationMethods(pathItem *spec.PathItem) []string {
+	methods := make([]string, 0, 7)
+	for _, method := range []string{"DELETE", "GET", "HEAD", "OPTIONS", "PATCH", "POST", "PUT"} {
+		if operationOf(pathItem, method) != nil {
+			methods = append(methods, method)
+		}
+	}
+
+	return methods
+}
+
+// operationOf returns the operation for a given (upper case) method on a path item
+func operationOf(pathItem *spec.PathItem, method string) *spec.Operation {
+	switch method {
+	case "GET":
+		return pathItem.Get
+	case "PUT":
+		return pathItem.Put
+	case "POST":
+		return pathItem.Post
+	case "PATCH":
+		return pathItem.Patch
+	case "DELETE":
+		return pathItem.Delete
+	case "HEAD":
+		return pathItem.Head
+	case "OPTIONS":
+		return pathItem.Options
+	default:
+		return nil
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedStatusCodes(m map[int]spec.Response) []int {
+	codes := make([]int, 0, len(m))
+	for k := range m {
+		codes = append(codes, k)
+	}
+	sort.Ints(codes)
+
+	return codes
+}
+
+// walkExtensions visits all the vendor extensions maps found in a swagger document.
+//
+// The visitor may delete or alter extensions in place.
+func walkExtensions(sp *spec.Swagger, visit func(spec.Extensions)) {
+	if sp == nil {
+		return
+	}
+
+	visit(sp.Extensions)
+
+	if sp.Info != nil {
+		visit(sp.Info.Extensions)
+		if sp.Info.Contact != nil {
+			visit(sp.Info.Contact.Extensions)
+		}
+		if sp.Info.License != nil {
+			visit(sp.Info.License.Extensions)
+		}
+	}
+
+	for i := range sp.Tags {
+		visit(sp.Tags[i].Extensions)
+	}
+
+	for _, name := range sortedKeys(sp.SecurityDefinitions) {
+		if scheme := sp.SecurityDefinitions[name]; scheme != nil {
+			visit(scheme.Extensions)
+		}
+	}
+
+	walkSchemas(sp, func(_ string, schema *spec.Schema) {
+		visit(schema.Extensions)
+	})
+
+	walkParameters(sp, func(_ string, param *spec.Parameter) {
+		visit(param.Extensions)
+		walkItemsExtensions(param.Items, visit)
+	})
+
+	for _, name := range sortedKeys(sp.Responses) {
+		resp := sp.Responses[name]
+		walkResponseExtensions(&resp, visit)
+	}
+
+	if sp.Paths == nil {
+		return
+	}
+
+	visit(sp.Paths.Extensions)
+	for _, pth := range sortedKeys(sp.Paths.Paths) {
+		pathItem := sp.Paths.Paths[pth]
+		visit(pathItem.Extensions)
+	}
+
+	walkOperations(sp, func(_ string, op *spec.Operation) {
+		visit(op.Extensions)
+		if op.Responses == nil {
+			return
+		}
+
+		visit(op.Responses.Extensions)
+		if op.Responses.Default != nil {
+			walkResponseExtensions(op.Responses.Default, visit)
+		}
+
+		for _, code := range sortedStatusCodes(op.Responses.StatusCodeResponses) {
+			resp := op.Responses.StatusCodeResponses[code]
+			walkResponseExtensions(&resp, visit)
+		}
+	})
+}
+
+func walkResponseExtensions(resp *spec.Response, visit func(spec.Extensions)) {
+	visit(resp.Extensions)
+
+	for _, name := range sortedKeys(resp.Headers) {
+		header := resp.Headers[name]
+		visit(header.Extensions)
+		walkItemsExtensions(header.Items, visit)
+	}
+}
+
+func walkItemsExtensions(items *spec.Items, visit func(spec.Extensions)) {
+	for ; items != nil; items = items.Items {
+		visit(items.Extensions)
+	}
+}
@@ -0,0 +1,34 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/go-openapi/analysis/internal/antest"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk_SchemasMatchAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	for _, fixture := range []string{"definitions.yml", "references.yml", "enums.yml"} {
+		doc := antest.LoadOrFail(t, filepath.Join("fixtures", fixture))
+		an := New(doc)
+
+		var walked []string
+		walkSchemas(doc, func(pointer string, _ *spec.Schema) {
+			walked = append(walked, pointer)
+		})
+
+		expected := make([]string, 0, len(an.allSchemas))
+		for k := range an.allSchemas {
+			expected = append(expected, k)
+		}
+
+		sort.Strings(walked)
+		sort.Strings(expected)
+		assert.Equalf(t, expected, walked, "walked schemas differ from analyzed schemas in %s", fixture)
+	}
+}